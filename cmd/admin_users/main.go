@@ -0,0 +1,219 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+const usage = `Usage: admin-users [-config-dir <dir>] [-env <env>] <command> [flags]
+
+Commands:
+  list-office-users       [-office <transportation office ID>]
+  invite-office-user      -email <email> -first-name <name> -last-name <name> -telephone <phone> -office <transportation office ID> [-supervisor] [-admin]
+  deactivate-office-user  -email <email>
+  reactivate-office-user  -email <email>
+  reassign-office-user    -email <email> -office <transportation office ID>
+  list-tsp-users          -scac <SCAC>
+  invite-tsp-user         -email <email> -first-name <name> -last-name <name> -telephone <phone> -scac <SCAC>
+  deactivate-tsp-user     -email <email>
+  reactivate-tsp-user     -email <email>
+`
+
+func mustParseUUID(name string, value string) uuid.UUID {
+	id, err := uuid.FromString(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return id
+}
+
+func mustSucceed(verrs *validate.Errors, err error) {
+	if verrs.HasAny() {
+		log.Fatalf("Validation errors: %v", verrs)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func mustFetchOfficeUser(db *pop.Connection, email string) *models.OfficeUser {
+	if email == "" {
+		log.Fatal("-email is required")
+	}
+	officeUser, err := models.FetchOfficeUserByEmail(db, email)
+	if err != nil {
+		log.Fatalf("Failed to find office user %s: %v", email, err)
+	}
+	// Load the transportation office too
+	officeUser, err = models.FetchOfficeUserByID(db, officeUser.ID)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return officeUser
+}
+
+func mustFetchTspUser(db *pop.Connection, email string) *models.TspUser {
+	if email == "" {
+		log.Fatal("-email is required")
+	}
+	tspUser, err := models.FetchTspUserByEmail(db, email)
+	if err != nil {
+		log.Fatalf("Failed to find TSP user %s: %v", email, err)
+	}
+	return tspUser
+}
+
+func mustFetchTsp(db *pop.Connection, scac string) models.TransportationServiceProvider {
+	if scac == "" {
+		log.Fatal("-scac is required")
+	}
+	var tsps []models.TransportationServiceProvider
+	err := db.Where("standard_carrier_alpha_code = $1", scac).All(&tsps)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(tsps) == 0 {
+		log.Fatalf("No TSP with SCAC %s", scac)
+	}
+	return tsps[0]
+}
+
+func printOfficeUser(officeUser models.OfficeUser) {
+	status := "active"
+	if officeUser.Deactivated {
+		status = "deactivated"
+	}
+	fmt.Printf("%s\t%s, %s\t%s\t%s\t%s\n", officeUser.ID, officeUser.LastName, officeUser.FirstName,
+		officeUser.Email, officeUser.TransportationOffice.Name, status)
+}
+
+func printTspUser(tspUser models.TspUser) {
+	status := "active"
+	if tspUser.Deactivated {
+		status = "deactivated"
+	}
+	fmt.Printf("%s\t%s, %s\t%s\t%s\n", tspUser.ID, tspUser.LastName, tspUser.FirstName, tspUser.Email, status)
+}
+
+// Manages office and TSP users from the command line. Changes are recorded in the audit log as
+// made by the system.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	command := flag.Arg(0)
+
+	commandFlags := flag.NewFlagSet(command, flag.ExitOnError)
+	email := commandFlags.String("email", "", "Email of the user")
+	firstName := commandFlags.String("first-name", "", "First name of the user to invite")
+	lastName := commandFlags.String("last-name", "", "Last name of the user to invite")
+	telephone := commandFlags.String("telephone", "", "Telephone of the user to invite")
+	office := commandFlags.String("office", "", "Transportation office ID")
+	supervisor := commandFlags.Bool("supervisor", false, "Invite the office user as a supervisor")
+	admin := commandFlags.Bool("admin", false, "Invite the office user as an admin")
+	scac := commandFlags.String("scac", "", "Standard carrier alpha code of the TSP")
+	commandFlags.Parse(flag.Args()[1:])
+
+	//DB connection
+	err := pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	switch command {
+	case "list-office-users":
+		var officeID *uuid.UUID
+		if *office != "" {
+			id := mustParseUUID("office", *office)
+			officeID = &id
+		}
+		officeUsers, err := models.FetchOfficeUsers(db, officeID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, officeUser := range officeUsers {
+			printOfficeUser(officeUser)
+		}
+	case "invite-office-user":
+		officeID := mustParseUUID("office", *office)
+		officeUser := models.OfficeUser{
+			FirstName:              *firstName,
+			LastName:               *lastName,
+			Email:                  *email,
+			Telephone:              *telephone,
+			TransportationOfficeID: officeID,
+			IsSupervisor:           *supervisor,
+			IsAdmin:                *admin,
+		}
+		mustSucceed(models.InviteOfficeUser(db, nil, &officeUser))
+		log.Printf("Invited office user %s", officeUser.ID)
+	case "deactivate-office-user":
+		officeUser := mustFetchOfficeUser(db, *email)
+		mustSucceed(models.SetOfficeUserDeactivated(db, nil, officeUser, true))
+		log.Printf("Deactivated office user %s", officeUser.ID)
+	case "reactivate-office-user":
+		officeUser := mustFetchOfficeUser(db, *email)
+		mustSucceed(models.SetOfficeUserDeactivated(db, nil, officeUser, false))
+		log.Printf("Reactivated office user %s", officeUser.ID)
+	case "reassign-office-user":
+		officeUser := mustFetchOfficeUser(db, *email)
+		transportationOffice, err := models.FetchTransportationOffice(db, mustParseUUID("office", *office))
+		if err != nil {
+			log.Fatalf("Failed to find transportation office: %v", err)
+		}
+		before := *officeUser
+		officeUser.TransportationOfficeID = transportationOffice.ID
+		officeUser.TransportationOffice = transportationOffice
+		mustSucceed(models.SaveWithAudit(db, nil, &before, officeUser, "update", ""))
+		log.Printf("Reassigned office user %s to %s", officeUser.ID, transportationOffice.Name)
+	case "list-tsp-users":
+		tsp := mustFetchTsp(db, *scac)
+		tspUsers, err := models.FetchTspUsersForTSP(db, tsp.ID)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, tspUser := range tspUsers {
+			printTspUser(tspUser)
+		}
+	case "invite-tsp-user":
+		tsp := mustFetchTsp(db, *scac)
+		tspUser := models.TspUser{
+			FirstName:                       *firstName,
+			LastName:                        *lastName,
+			Email:                           *email,
+			Telephone:                       *telephone,
+			TransportationServiceProviderID: tsp.ID,
+		}
+		mustSucceed(models.InviteTspUser(db, nil, &tspUser))
+		log.Printf("Invited TSP user %s", tspUser.ID)
+	case "deactivate-tsp-user":
+		tspUser := mustFetchTspUser(db, *email)
+		mustSucceed(models.SetTspUserDeactivated(db, nil, tspUser, true))
+		log.Printf("Deactivated TSP user %s", tspUser.ID)
+	case "reactivate-tsp-user":
+		tspUser := mustFetchTspUser(db, *email)
+		mustSucceed(models.SetTspUserDeactivated(db, nil, tspUser, false))
+		log.Printf("Reactivated TSP user %s", tspUser.ID)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/namsral/flag"
 	"github.com/transcom/mymove/pkg/iws"
@@ -19,11 +20,12 @@ func main() {
 	lastName := flag.String("last", "", "Last Name to look up (op=pids)")
 	firstName := flag.String("first", "", "First Name to look up (op=pids) [optional]")
 	workEmail := flag.String("email", "", "Work e-mail address to look up (op=wkEma)")
+	timeout := flag.Duration("timeout", 30*time.Second, "How long to wait for IWS to respond")
 
 	flag.Parse()
 
 	// Load client cert
-	rbs, err := iws.NewRealTimeBrokerService(*host, *dodCaCertPackage, *moveMilDODTLSCert, *moveMilDODTLSKey)
+	rbs, err := iws.NewRealTimeBrokerService(*host, *dodCaCertPackage, *moveMilDODTLSCert, *moveMilDODTLSKey, *timeout)
 	if err != nil {
 		log.Fatal(err)
 		os.Exit(1)
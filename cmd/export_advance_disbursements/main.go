@@ -0,0 +1,93 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Exports approved PPM advances as CSV for finance to disburse
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	output := flag.String("output", "", "File to write the CSV to, defaults to stdout")
+	dryRun := flag.Bool("dry-run", false, "Don't mark the exported advances, so they are exported again next time")
+	flag.Parse()
+
+	//DB connection
+	err := pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	disbursements, err := models.FetchAdvanceDisbursements(db)
+	if err != nil {
+		log.Fatalf("Failed to fetch advance disbursements: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	w := csv.NewWriter(out)
+	err = w.Write([]string{"reimbursement_id", "move_id", "locator", "edipi", "last_name", "first_name", "method_of_receipt", "amount", "approved_at"})
+	if err != nil {
+		log.Fatalf("Failed to write CSV header: %v", err)
+	}
+	for _, disbursement := range disbursements {
+		err = w.Write([]string{
+			disbursement.ReimbursementID.String(),
+			disbursement.MoveID.String(),
+			disbursement.Locator,
+			stringOrEmpty(disbursement.Edipi),
+			stringOrEmpty(disbursement.LastName),
+			stringOrEmpty(disbursement.FirstName),
+			string(disbursement.MethodOfReceipt),
+			fmt.Sprintf("%.2f", disbursement.ApprovedAmount.ToDollarFloat()),
+			disbursement.ApprovedAt.UTC().Format(time.RFC3339),
+		})
+		if err != nil {
+			log.Fatalf("Failed to write advance disbursement %s: %v", disbursement.ReimbursementID, err)
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatalf("Failed to write CSV: %v", err)
+	}
+	// Advances are only marked as exported once the whole file has been written
+	if out != os.Stdout {
+		if err := out.Close(); err != nil {
+			log.Fatalf("Failed to write CSV: %v", err)
+		}
+	}
+
+	if !*dryRun {
+		err = models.MarkDisbursementsRequested(db, disbursements, time.Now())
+		if err != nil {
+			log.Fatalf("Failed to mark advances as exported: %v", err)
+		}
+	}
+	log.Printf("Exported %d advance disbursements", len(disbursements))
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func floatOrEmpty(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 0, 64)
+}
+
+func parseDate(name string, value string) time.Time {
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Fatalf("Invalid %s %s: %v", name, value, err)
+	}
+	return date
+}
+
+// Exports how much each API client used the public API as CSV, for auditing TSP integrations. By
+// default the last 7 days are exported.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	startDate := flag.String("start-date", "", "The first day (YYYY-MM-DD) to export")
+	endDate := flag.String("end-date", "", "The last day (YYYY-MM-DD) to export")
+	output := flag.String("output", "", "File to write the CSV to, defaults to stdout")
+	flag.Parse()
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	end := today
+	if *endDate != "" {
+		end = parseDate("end-date", *endDate).AddDate(0, 0, 1)
+	}
+	start := end.AddDate(0, 0, -7)
+	if *startDate != "" {
+		start = parseDate("start-date", *startDate)
+	}
+
+	//DB connection
+	err := pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	usage, err := models.FetchAPIClientUsage(db, start, end)
+	if err != nil {
+		log.Fatalf("Failed to fetch API client usage: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"start_date", "end_date", "api_client_id", "name", "transportation_service_provider_id", "scac", "requests", "errors", "throttled", "average_duration_ms"})
+	for _, client := range usage {
+		w.Write([]string{
+			start.Format("2006-01-02"),
+			end.AddDate(0, 0, -1).Format("2006-01-02"),
+			client.APIClientID.String(),
+			client.Name,
+			client.TransportationServiceProviderID.String(),
+			client.StandardCarrierAlphaCode,
+			strconv.Itoa(client.Requests),
+			strconv.Itoa(client.Errors),
+			strconv.Itoa(client.Throttled),
+			floatOrEmpty(client.AverageDurationMs),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Exported usage of %d API clients", len(usage))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func mustParseUUID(name string, value string) *uuid.UUID {
+	if value == "" {
+		return nil
+	}
+	id, err := uuid.FromString(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return &id
+}
+
+func mustParseDate(name string, value string) *time.Time {
+	if value == "" {
+		return nil
+	}
+	date, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		log.Fatalf("Invalid %s, expected YYYY-MM-DD: %v", name, err)
+	}
+	return &date
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func uuidOrEmpty(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// Exports audit records as CSV for investigations
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	moveID := flag.String("move", "", "Only export records for this move ID")
+	recordType := flag.String("record-type", "", "Only export records of this type, e.g. shipment")
+	recordID := flag.String("record", "", "Only export records for this record ID")
+	actorUserID := flag.String("actor", "", "Only export changes made by this user ID")
+	since := flag.String("since", "", "Only export records created on or after this date (YYYY-MM-DD)")
+	until := flag.String("until", "", "Only export records created before this date (YYYY-MM-DD)")
+	output := flag.String("output", "", "File to write the CSV to, defaults to stdout")
+	flag.Parse()
+
+	filter := models.AuditRecordFilter{
+		MoveID:      mustParseUUID("move", *moveID),
+		RecordType:  *recordType,
+		RecordID:    mustParseUUID("record", *recordID),
+		ActorUserID: mustParseUUID("actor", *actorUserID),
+		Since:       mustParseDate("since", *since),
+		Until:       mustParseDate("until", *until),
+	}
+
+	//DB connection
+	err := pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	records, err := models.FetchAuditRecords(db, filter)
+	if err != nil {
+		log.Fatalf("Failed to fetch audit records: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"id", "created_at", "move_id", "record_type", "record_id", "event", "from_status", "to_status", "reason", "actor_user_id", "actor_name", "session_type", "changes"})
+	for _, record := range records {
+		w.Write([]string{
+			record.ID.String(),
+			record.CreatedAt.UTC().Format(time.RFC3339),
+			uuidOrEmpty(record.MoveID),
+			record.RecordType,
+			record.RecordID.String(),
+			record.Event,
+			stringOrEmpty(record.FromStatus),
+			stringOrEmpty(record.ToStatus),
+			stringOrEmpty(record.Reason),
+			uuidOrEmpty(record.ActorUserID),
+			record.ActorName,
+			string(record.SessionType),
+			record.Changes,
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Exported %d audit records", len(records))
+}
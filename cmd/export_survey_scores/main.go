@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/csv"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func floatOrEmpty(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', 2, 64)
+}
+
+// Exports the satisfaction survey scores of each TSP and TDL for a performance period as CSV. By
+// default the last finished performance period is exported.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	periodDate := flag.String("performance-period-date", "", "A date (YYYY-MM-DD) in the performance period to export")
+	output := flag.String("output", "", "File to write the CSV to, defaults to stdout")
+	flag.Parse()
+
+	date := time.Now().UTC()
+	var err error
+	if *periodDate != "" {
+		date, err = time.Parse("2006-01-02", *periodDate)
+		if err != nil {
+			log.Fatalf("Invalid performance-period-date %s: %v", *periodDate, err)
+		}
+	} else {
+		currentStart, _ := models.GetPerformancePeriod(date)
+		date = currentStart.AddDate(0, 0, -1)
+	}
+	start, end := models.GetPerformancePeriod(date)
+
+	//DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	scores, err := models.FetchTSPSurveyScores(db, start, end)
+	if err != nil {
+		log.Fatalf("Failed to fetch survey scores: %v", err)
+	}
+
+	out := os.Stdout
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer out.Close()
+	}
+
+	w := csv.NewWriter(out)
+	w.Write([]string{"performance_period_start", "performance_period_end", "transportation_service_provider_id", "scac", "traffic_distribution_list_id", "surveys", "ratings", "average_rating"})
+	for _, score := range scores {
+		w.Write([]string{
+			start.Format("2006-01-02"),
+			end.Format("2006-01-02"),
+			score.TransportationServiceProviderID.String(),
+			score.StandardCarrierAlphaCode,
+			score.TrafficDistributionListID.String(),
+			strconv.Itoa(score.Surveys),
+			strconv.Itoa(score.Ratings),
+			floatOrEmpty(score.AverageRating),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Exported survey scores of %d TSPs and TDLs", len(scores))
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+// Ingests SES delivery, bounce and complaint events into the notification log. Reads one event per
+// line, as received from the SES notification queue, from a file or stdin.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	input := flag.String("input", "", "File to read the events from, defaults to stdin")
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+
+	//DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db, err := pop.Connect(*env)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var in io.Reader = os.Stdin
+	if *input != "" {
+		file, err := os.Open(*input)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		in = file
+	}
+
+	ingested, err := notifications.IngestSESEvents(db, logger, in)
+	if err != nil {
+		log.Fatalf("Failed to ingest SES events after %d: %v", ingested, err)
+	}
+	log.Printf("Ingested %d SES events", ingested)
+}
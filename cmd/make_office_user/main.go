@@ -25,6 +25,10 @@ func main() {
 	firstName := flag.String("first_name", "Testy", "First name of the office user to create")
 	lastName := flag.String("last_name", "McTester", "Last name of the office user to create")
 	number := flag.String("number", "415-555-1212", "Phone number of the office user to create")
+	// The Truss office created below has no GBLOC, so default to seeing every move
+	scope := flag.String("scope", string(models.OfficeAccessScopeNational), "Which moves the office user can access: GBLOC, TRANSPORTATION_OFFICE or NATIONAL")
+	supervisor := flag.Bool("supervisor", false, "Whether the office user is a supervisor who can reassign work")
+	admin := flag.Bool("admin", false, "Whether the office user can administer users and feature flags")
 	flag.Parse()
 
 	//DB connection
@@ -67,7 +71,10 @@ func main() {
 		LastName:               *lastName,
 		Telephone:              *number,
 		TransportationOfficeID: office.ID,
-		Email: *email,
+		Email:                  *email,
+		AccessScope:            models.OfficeAccessScope(*scope),
+		IsSupervisor:           *supervisor,
+		IsAdmin:                *admin,
 	}
 	if user.ID != uuid.Nil {
 		newUser.UserID = &user.ID
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	awssession "github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/sla"
+)
+
+var logger *zap.Logger
+
+// Escalates overdue moves, PPM payment requests and documents. Runs once, or every -interval when
+// one is given.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	debugLogging := flag.Bool("debug_logging", false, "log messages at the debug level.")
+	officeHostname := flag.String("http-office-server-name", "officelocal", "Hostname of the office app, used in escalation emails.")
+	emailBackend := flag.String("email-backend", "local", "Email backend to use, either SES or local")
+	sesRegion := flag.String("aws-ses-region", "", "AWS region used for SES")
+	unsubscribeSecretKey := flag.String("unsubscribe-secret-key", "", "Secret used to sign the unsubscribe links in notifications.")
+	unsubscribeSecretKeyFile := flag.String("unsubscribe-secret-key-file", "", "File containing the unsubscribe secret key, used instead of unsubscribe-secret-key when given.")
+	interval := flag.Duration("interval", 0, "How often to escalate, or 0 to run once")
+	flag.Parse()
+
+	// Set up logger for the system
+	var err error
+	if *debugLogging {
+		logger, err = zap.NewDevelopment()
+	} else {
+		logger, err = zap.NewProduction()
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+	zap.ReplaceGlobals(logger)
+
+	unsubscribeSecret, err := notifications.LoadUnsubscribeSecret(*unsubscribeSecretKey, *unsubscribeSecretKeyFile)
+	if err != nil {
+		logger.Fatal("Unsubscribe secret key", zap.Error(err))
+	}
+
+	honeyZapLogger := hnyzap.Logger{Logger: logger}
+
+	// DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Panic(err)
+	}
+	dbConnection, err := pop.Connect(*env)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	var notificationSender notifications.NotificationSender
+	if *emailBackend == "ses" {
+		sesSession, err := awssession.NewSession(&aws.Config{
+			Region: aws.String(*sesRegion),
+		})
+		if err != nil {
+			logger.Fatal("Failed to create a new AWS client config provider", zap.Error(err))
+		}
+		unsubscribeSigner := notifications.NewUnsubscribeSigner(unsubscribeSecret, *officeHostname)
+		notificationSender = notifications.NewNotificationSender(ses.New(sesSession), logger, dbConnection, unsubscribeSigner)
+	} else {
+		notificationSender = notifications.NewStubNotificationSender(logger)
+	}
+
+	escalator := sla.NewEscalator(dbConnection, &honeyZapLogger, notificationSender, models.NewCalendarCache(dbConnection), *officeHostname)
+	for {
+		escalated, err := escalator.Run(context.Background())
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Info("Escalated overdue records", zap.Int("escalated", escalated))
+
+		if *interval == 0 {
+			return
+		}
+		time.Sleep(*interval)
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 	"log"
 
+	"github.com/aws/aws-sdk-go/aws"
+	awssession "github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/gobuffalo/pop"
 	"github.com/namsral/flag"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/awardqueue"
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/notifications"
 )
 
 var logger *zap.Logger
@@ -18,6 +22,11 @@ func main() {
 	config := flag.String("config-dir", "config", "The location of server config files")
 	env := flag.String("env", "development", "The environment to run in, configures the database, presenetly.")
 	debugLogging := flag.Bool("debug_logging", false, "log messages at the debug level.")
+	tspHostname := flag.String("http-tsp-server-name", "tsplocal", "Hostname of the TSP app, used in shipment offer emails.")
+	emailBackend := flag.String("email-backend", "local", "Email backend to use, either SES or local")
+	sesRegion := flag.String("aws-ses-region", "", "AWS region used for SES")
+	unsubscribeSecretKey := flag.String("unsubscribe-secret-key", "", "Secret used to sign the unsubscribe links in notifications.")
+	unsubscribeSecretKeyFile := flag.String("unsubscribe-secret-key-file", "", "File containing the unsubscribe secret key, used instead of unsubscribe-secret-key when given.")
 	flag.Parse()
 
 	// Set up logger for the system
@@ -32,6 +41,12 @@ func main() {
 		log.Fatalf("Failed to initialize Zap logging due to %v", err)
 	}
 	zap.ReplaceGlobals(logger)
+
+	unsubscribeSecret, err := notifications.LoadUnsubscribeSecret(*unsubscribeSecretKey, *unsubscribeSecretKeyFile)
+	if err != nil {
+		logger.Fatal("Unsubscribe secret key", zap.Error(err))
+	}
+
 	honeyZapLogger := hnyzap.Logger{Logger: logger}
 
 	// DB connection
@@ -44,7 +59,21 @@ func main() {
 		log.Panic(err)
 	}
 
-	awardQueue := awardqueue.NewAwardQueue(dbConnection, &honeyZapLogger)
+	var notificationSender notifications.NotificationSender
+	if *emailBackend == "ses" {
+		sesSession, err := awssession.NewSession(&aws.Config{
+			Region: aws.String(*sesRegion),
+		})
+		if err != nil {
+			logger.Fatal("Failed to create a new AWS client config provider", zap.Error(err))
+		}
+		unsubscribeSigner := notifications.NewUnsubscribeSigner(unsubscribeSecret, *tspHostname)
+		notificationSender = notifications.NewNotificationSender(ses.New(sesSession), logger, dbConnection, unsubscribeSigner)
+	} else {
+		notificationSender = notifications.NewStubNotificationSender(logger)
+	}
+
+	awardQueue := awardqueue.NewAwardQueue(dbConnection, &honeyZapLogger, notificationSender)
 	err = awardQueue.Run(context.Background())
 	if err != nil {
 		log.Panic(err)
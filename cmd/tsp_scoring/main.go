@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/scoring"
+)
+
+var logger *zap.Logger
+
+// Scores TSPs for a performance period and writes their performance records for the next one. By
+// default the last finished performance period is scored.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	debugLogging := flag.Bool("debug_logging", false, "log messages at the debug level.")
+	periodDate := flag.String("performance-period-date", "", "A date (YYYY-MM-DD) in the performance period to score")
+	flag.Parse()
+
+	// Set up logger for the system
+	var err error
+	if *debugLogging {
+		logger, err = zap.NewDevelopment()
+	} else {
+		logger, err = zap.NewProduction()
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+	zap.ReplaceGlobals(logger)
+	honeyZapLogger := hnyzap.Logger{Logger: logger}
+
+	date := time.Now().UTC()
+	if *periodDate != "" {
+		date, err = time.Parse("2006-01-02", *periodDate)
+		if err != nil {
+			log.Fatalf("Invalid performance-period-date %s: %v", *periodDate, err)
+		}
+	} else {
+		currentStart, _ := models.GetPerformancePeriod(date)
+		date = currentStart.AddDate(0, 0, -1)
+	}
+
+	// DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Panic(err)
+	}
+	dbConnection, err := pop.Connect(*env)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	scorer := scoring.NewScorer(dbConnection, &honeyZapLogger)
+	written, err := scorer.Run(context.Background(), date)
+	if err != nil {
+		log.Panic(err)
+	}
+	logger.Info("Wrote TSP performance records", zap.Int("written", written))
+}
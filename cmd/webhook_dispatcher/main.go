@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/webhooks"
+)
+
+var logger *zap.Logger
+
+// Queues deliveries of recorded webhook events and posts them to TSP systems. Runs once, or every -interval when one is given.
+func main() {
+	config := flag.String("config-dir", "config", "The location of server config files")
+	env := flag.String("env", "development", "The environment to run in, which configures the database.")
+	debugLogging := flag.Bool("debug_logging", false, "log messages at the debug level.")
+	timeout := flag.Duration("timeout", 10*time.Second, "How long to wait for a webhook to respond")
+	interval := flag.Duration("interval", 0, "How often to send deliveries, or 0 to run once")
+	allowPrivateHosts := flag.Bool("allow-private-hosts", false, "Deliver to webhooks on this machine and private networks. Development only.")
+	flag.Parse()
+
+	// Set up logger for the system
+	var err error
+	if *debugLogging {
+		logger, err = zap.NewDevelopment()
+	} else {
+		logger, err = zap.NewProduction()
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+	zap.ReplaceGlobals(logger)
+	honeyZapLogger := hnyzap.Logger{Logger: logger}
+
+	if *allowPrivateHosts {
+		if err := webhooks.AllowPrivateHosts(*env); err != nil {
+			logger.Fatal("Allowing private webhook hosts", zap.Error(err))
+		}
+	}
+
+	// DB connection
+	err = pop.AddLookupPaths(*config)
+	if err != nil {
+		log.Panic(err)
+	}
+	dbConnection, err := pop.Connect(*env)
+	if err != nil {
+		log.Panic(err)
+	}
+
+	dispatcher := webhooks.NewDispatcher(dbConnection, &honeyZapLogger, webhooks.NewClient(*timeout))
+	for {
+		delivered, err := dispatcher.Run(context.Background())
+		if err != nil {
+			log.Panic(err)
+		}
+		logger.Info("Sent webhook deliveries", zap.Int("delivered", delivered))
+
+		if *interval == 0 {
+			return
+		}
+		time.Sleep(*interval)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/namsral/flag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/webhooks"
+)
+
+// Receives webhook deliveries locally and logs them, for testing webhooks without a TSP system.
+// Webhooks must be on public https hosts, so in development run the webserver with
+// -allow-private-webhook-hosts and the dispatcher with -allow-private-hosts, then register
+// http://localhost:9000/ as a webhook with the same secret. Elsewhere, expose the port through an
+// https tunnel and register the tunnel's URL instead.
+func main() {
+	port := flag.Int("port", 9000, "The port to receive deliveries on")
+	secret := flag.String("secret", "", "The secret the webhook was registered with")
+	flag.Parse()
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Fatalf("Failed to initialize Zap logging due to %v", err)
+	}
+	if *secret == "" {
+		logger.Fatal("A -secret is required to verify deliveries")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := webhooks.Verify(*secret, r.Header, body, time.Now()); err != nil {
+			logger.Error("Rejected delivery", zap.String("delivery", r.Header.Get(webhooks.DeliveryHeader)), zap.Error(err))
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		logger.Info("Received delivery",
+			zap.String("event", r.Header.Get(webhooks.EventHeader)),
+			zap.String("delivery", r.Header.Get(webhooks.DeliveryHeader)),
+			zap.String("payload", string(body)))
+	})
+
+	address := fmt.Sprintf("localhost:%d", *port)
+	logger.Info("Receiving webhook deliveries", zap.String("address", address))
+	logger.Fatal("Receiver stopped", zap.Error(http.ListenAndServe(address, nil)))
+}
@@ -13,10 +13,12 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	awssession "github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gobuffalo/pop"
 	"github.com/honeycombio/beeline-go"
@@ -28,6 +30,7 @@ import (
 	"github.com/transcom/mymove/pkg/auth/authentication"
 	"github.com/transcom/mymove/pkg/dpsauth"
 	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/handlers/adminapi"
 	"github.com/transcom/mymove/pkg/handlers/dpsapi"
 	"github.com/transcom/mymove/pkg/handlers/internalapi"
 	"github.com/transcom/mymove/pkg/handlers/ordersapi"
@@ -38,6 +41,8 @@ import (
 	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/server"
 	"github.com/transcom/mymove/pkg/storage"
+	"github.com/transcom/mymove/pkg/surveys"
+	"github.com/transcom/mymove/pkg/webhooks"
 	"go.uber.org/zap"
 	"goji.io"
 	"goji.io/pat"
@@ -122,9 +127,12 @@ func initFlags(flag *pflag.FlagSet) {
 	flag.String("internal-swagger", "swagger/internal.yaml", "The location of the internal API swagger definition")
 	flag.String("orders-swagger", "swagger/orders.yaml", "The location of the Orders API swagger definition")
 	flag.String("dps-swagger", "swagger/dps.yaml", "The location of the DPS API swagger definition")
+	flag.String("admin-swagger", "swagger/admin.yaml", "The location of the admin API swagger definition")
 
 	flag.Bool("debug-logging", false, "log messages at the debug level.")
 	flag.String("client-auth-secret-key", "", "Client auth secret JWT key.")
+	flag.String("unsubscribe-secret-key", "", "Secret used to sign the unsubscribe links in notifications.")
+	flag.String("unsubscribe-secret-key-file", "", "File containing the unsubscribe secret key, used instead of unsubscribe-secret-key when given.")
 	flag.Bool("no-session-timeout", false, "whether user sessions should timeout.")
 
 	flag.String("dod-ca-package", "", "Path to PKCS#7 package containing certificates of all DoD root and intermediate CAs")
@@ -160,8 +168,12 @@ func initFlags(flag *pflag.FlagSet) {
 	// EDI Invoice Config
 	flag.Bool("send-prod-invoice", false, "Flag (bool) for EDI Invoices to signify if they should go to production GEX")
 
+	// Webhooks Config
+	flag.Bool("allow-private-webhook-hosts", false, "Allow webhooks on this machine and private networks. Development only.")
+
 	flag.String("storage-backend", "filesystem", "Storage backend to use, either filesystem or s3.")
 	flag.String("email-backend", "local", "Email backend to use, either SES or local")
+	flag.String("sms-backend", "none", "SMS backend to use, either local or none")
 	flag.String("aws-s3-bucket-name", "", "S3 bucket used for file storage")
 	flag.String("aws-s3-region", "", "AWS region used for S3 file storage")
 	flag.String("aws-s3-key-namespace", "", "Key prefix for all objects written to S3")
@@ -179,6 +191,17 @@ func initFlags(flag *pflag.FlagSet) {
 
 	// IWS
 	flag.String("iws-rbs-host", "", "Hostname for the IWS RBS")
+	flag.Duration("iws-rbs-timeout", 10*time.Second, "How long to wait for a response from the IWS RBS")
+	flag.Int("iws-rbs-max-attempts", 3, "Number of times an IWS RBS lookup is attempted before giving up")
+	flag.Duration("iws-rbs-retry-backoff", 250*time.Millisecond, "Wait between IWS RBS attempts, multiplied by the attempt number")
+	flag.String("iws-rbs-retryable-fault-codes", "", "Comma-separated IWS RBS fault codes that are transient and may be retried")
+	flag.Duration("iws-rbs-edipi-cache-ttl", 15*time.Minute, "How long IWS RBS lookups are cached in memory by EDIPI")
+	flag.Int("iws-rbs-edipi-cache-size", iws.DefaultEdipiCacheSize, "Most people kept in the in-memory IWS RBS EDIPI cache")
+	flag.Float64("iws-rbs-rate-limit", 10, "Most IWS RBS calls made per second. Zero disables the limit.")
+	flag.Int("iws-rbs-rate-burst", 5, "IWS RBS calls that may be made at once before the rate limit applies")
+	flag.Duration("iws-rbs-maintenance-interval", 5*time.Minute, "How often IWS RBS metrics are logged and expired SSN cache entries are deleted")
+	flag.Duration("iws-rbs-ssn-cache-ttl", 24*time.Hour, "How long IWS RBS matches by SSN are cached in the database")
+	flag.String("iws-cache-encryption-key", "", "Secret used to encrypt cached IWS RBS results. The SSN cache is disabled when empty.")
 
 	// DB Config
 	flag.String("db-name", "dev_db", "Database Name")
@@ -250,7 +273,62 @@ func initRealTimeBrokerService(v *viper.Viper, logger *zap.Logger) (*iws.RealTim
 		v.GetString("iws-rbs-host"),
 		v.GetString("dod-ca-package"),
 		v.GetString("move-mil-dod-tls-cert"),
-		v.GetString("move-mil-dod-tls-key"))
+		v.GetString("move-mil-dod-tls-key"),
+		v.GetDuration("iws-rbs-timeout"))
+}
+
+func initIWSPersonLookup(v *viper.Viper, db *pop.Connection, logger *zap.Logger, rbs *iws.RealTimeBrokerService) (*iws.CachingClient, error) {
+	var faultCodes []uint64
+	for _, code := range strings.Split(v.GetString("iws-rbs-retryable-fault-codes"), ",") {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(code, 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid IWS RBS fault code %s", code)
+		}
+		faultCodes = append(faultCodes, parsed)
+	}
+
+	params := iws.CachingClientParams{
+		MaxAttempts:         v.GetInt("iws-rbs-max-attempts"),
+		RetryBackoff:        v.GetDuration("iws-rbs-retry-backoff"),
+		RetryableFaultCodes: faultCodes,
+		EdipiTTL:            v.GetDuration("iws-rbs-edipi-cache-ttl"),
+		EdipiCacheSize:      v.GetInt("iws-rbs-edipi-cache-size"),
+		RateLimit:           v.GetFloat64("iws-rbs-rate-limit"),
+		RateBurst:           v.GetInt("iws-rbs-rate-burst"),
+		Logger:              logger,
+	}
+
+	if key := v.GetString("iws-cache-encryption-key"); key != "" {
+		ssnCache, err := iws.NewDBSSNCache(db, key, v.GetDuration("iws-rbs-ssn-cache-ttl"))
+		if err != nil {
+			return nil, err
+		}
+		params.SSNCache = ssnCache
+	} else {
+		logger.Info("IWS RBS SSN cache disabled")
+	}
+
+	return iws.NewCachingClient(*rbs, params), nil
+}
+
+// startIWSMaintenance periodically reports IWS lookup metrics and deletes expired SSN cache entries
+func startIWSMaintenance(v *viper.Viper, logger *zap.Logger, client *iws.CachingClient) {
+	interval := v.GetDuration("iws-rbs-maintenance-interval")
+	if interval <= 0 {
+		return
+	}
+	go func() {
+		for range time.Tick(interval) {
+			client.LogMetrics()
+			if err := client.PurgeExpiredSSNCacheEntries(); err != nil {
+				logger.Error("Deleting expired IWS SSN cache entries", zap.Error(err))
+			}
+		}
+	}()
 }
 
 func initDatabase(v *viper.Viper, logger *zap.Logger) (*pop.Connection, error) {
@@ -353,6 +431,10 @@ func main() {
 	if len(loginGovHostname) == 0 {
 		log.Fatal("Must provide the Login.gov hostname parameter, exiting")
 	}
+	unsubscribeSecretKey, err := notifications.LoadUnsubscribeSecret(v.GetString("unsubscribe-secret-key"), v.GetString("unsubscribe-secret-key-file"))
+	if err != nil {
+		logger.Fatal("Unsubscribe secret key", zap.Error(err))
+	}
 
 	// Create a connection to the DB
 	dbConnection, err := initDatabase(v, logger)
@@ -384,6 +466,8 @@ func main() {
 	sessionCookieMiddleware := auth.SessionCookieMiddleware(logger, clientAuthSecretKey, noSessionTimeout)
 	appDetectionMiddleware := auth.DetectorMiddleware(logger, myHostname, officeHostname, tspHostname)
 	userAuthMiddleware := authentication.UserAuthMiddleware(logger)
+	clientCertMiddleware := authentication.ClientCertMiddleware(logger, dbConnection)
+	featureFlagMiddleware := authentication.FeatureFlagMiddleware(logger, dbConnection)
 
 	handlerContext := handlers.NewHandlerContext(dbConnection, logger)
 	handlerContext.SetCookieSecret(clientAuthSecretKey)
@@ -391,6 +475,9 @@ func main() {
 		handlerContext.SetNoSessionTimeout()
 	}
 
+	// Unsubscribe links in emails are signed so that recipients can follow them without logging in
+	unsubscribeSigner := notifications.NewUnsubscribeSigner(unsubscribeSecretKey, myHostname)
+	var sesService sesiface.SESAPI
 	if v.GetString("email-backend") == "ses" {
 		// Setup Amazon SES (email) service
 		// TODO: This might be able to be combined with the AWS Session that we're using for S3 down
@@ -401,11 +488,16 @@ func main() {
 		if err != nil {
 			logger.Fatal("Failed to create a new AWS client config provider", zap.Error(err))
 		}
-		sesService := ses.New(sesSession)
-		handlerContext.SetNotificationSender(notifications.NewNotificationSender(sesService, logger))
+		sesService = ses.New(sesSession)
 	} else {
-		handlerContext.SetNotificationSender(notifications.NewStubNotificationSender(logger))
+		sesService = notifications.NewStubSESClient(logger)
+	}
+	notificationSender := notifications.NewNotificationSender(sesService, logger, dbConnection, unsubscribeSigner)
+	// Text messages are only sent when there's an SMS backend; local logs them instead of sending them
+	if v.GetString("sms-backend") == "local" {
+		notificationSender = notificationSender.WithSMSProvider(notifications.NewFakeSMSProvider(logger))
 	}
+	handlerContext.SetNotificationSender(notificationSender)
 
 	build := v.GetString("build")
 
@@ -420,6 +512,12 @@ func main() {
 	// Set SendProductionInvoice for ediinvoice
 	handlerContext.SetSendProductionInvoice(v.GetBool("send-prod-invoice"))
 
+	if v.GetBool("allow-private-webhook-hosts") {
+		if err := webhooks.AllowPrivateHosts(env); err != nil {
+			logger.Fatal("Allowing private webhook hosts", zap.Error(err))
+		}
+	}
+
 	storageBackend := v.GetString("storage-backend")
 
 	var storer storage.FileStorer
@@ -452,7 +550,12 @@ func main() {
 	if err != nil {
 		logger.Fatal("Could not instantiate IWS RBS", zap.Error(err))
 	}
-	handlerContext.SetIWSRealTimeBrokerService(*rbs)
+	iwsPersonLookup, err := initIWSPersonLookup(v, dbConnection, logger, rbs)
+	if err != nil {
+		logger.Fatal("Could not instantiate IWS client", zap.Error(err))
+	}
+	handlerContext.SetIWSPersonLookup(iwsPersonLookup)
+	startIWSMaintenance(v, logger, iwsPersonLookup)
 
 	sddcHostname := v.GetString("http-sddc-server-name")
 	dpsAuthSecretKey := v.GetString("dps-auth-secret-key")
@@ -525,7 +628,10 @@ func main() {
 	externalAPIMux := goji.SubMux()
 	apiMux.Handle(pat.New("/*"), externalAPIMux)
 	externalAPIMux.Use(noCacheMiddleware)
+	// TSP software authenticates with client certificates on the mutual TLS listener
+	externalAPIMux.Use(clientCertMiddleware)
 	externalAPIMux.Use(userAuthMiddleware)
+	externalAPIMux.Use(featureFlagMiddleware)
 	externalAPIMux.Handle(pat.New("/*"), publicapi.NewPublicAPIHandler(handlerContext))
 
 	internalMux := goji.SubMux()
@@ -537,9 +643,21 @@ func main() {
 	internalAPIMux := goji.SubMux()
 	internalMux.Handle(pat.New("/*"), internalAPIMux)
 	internalAPIMux.Use(userAuthMiddleware)
+	internalAPIMux.Use(featureFlagMiddleware)
 	internalAPIMux.Use(noCacheMiddleware)
 	internalAPIMux.Handle(pat.New("/*"), internalapi.NewInternalAPIHandler(handlerContext))
 
+	adminMux := goji.SubMux()
+	root.Handle(pat.New("/admin/v1/*"), adminMux)
+	adminMux.Handle(pat.Get("/swagger.yaml"), fileHandler(v.GetString("admin-swagger")))
+
+	// Mux for admin API that enforces auth. Handlers check that the user is an admin on office.move.mil
+	adminAPIMux := goji.SubMux()
+	adminMux.Handle(pat.New("/*"), adminAPIMux)
+	adminAPIMux.Use(userAuthMiddleware)
+	adminAPIMux.Use(noCacheMiddleware)
+	adminAPIMux.Handle(pat.New("/*"), adminapi.NewAdminAPIHandler(handlerContext))
+
 	authContext := authentication.NewAuthContext(logger, loginGovProvider, loginGovCallbackProtocol, loginGovCallbackPort)
 	authMux := goji.SubMux()
 	root.Handle(pat.New("/auth/*"), authMux)
@@ -547,6 +665,10 @@ func main() {
 	authMux.Handle(pat.Get("/login-gov/callback"), authentication.NewCallbackHandler(authContext, dbConnection, clientAuthSecretKey, noSessionTimeout))
 	authMux.Handle(pat.Get("/logout"), authentication.NewLogoutHandler(authContext, clientAuthSecretKey, noSessionTimeout))
 
+	root.Handle(pat.New(notifications.UnsubscribePath), notifications.NewUnsubscribeHandler(dbConnection, logger, unsubscribeSigner))
+	// Survey links carry a one-time token in place of a session
+	root.Handle(pat.New(surveys.ResponsePath), surveys.NewResponseHandler(dbConnection, logger))
+
 	if env == "development" || env == "test" {
 		zap.L().Info("Enabling devlocal auth")
 		localAuthMux := goji.SubMux()
@@ -0,0 +1,31 @@
+CREATE TABLE audit_records (
+    id uuid PRIMARY KEY,
+    move_id uuid REFERENCES moves(id),
+    record_type VARCHAR(255) NOT NULL,
+    record_id uuid NOT NULL,
+    event VARCHAR(255) NOT NULL,
+    from_status VARCHAR(255),
+    to_status VARCHAR(255),
+    changes TEXT NOT NULL DEFAULT '{}',
+    reason TEXT,
+    actor_user_id uuid REFERENCES users(id),
+    actor_name VARCHAR(255) NOT NULL DEFAULT '',
+    session_type VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX audit_records_move_id_created_at_idx ON audit_records (move_id, created_at);
+CREATE INDEX audit_records_record_idx ON audit_records (record_type, record_id);
+CREATE INDEX audit_records_created_at_idx ON audit_records (created_at);
+
+-- The audit log is append-only
+CREATE FUNCTION prevent_audit_record_changes() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_records is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_records_append_only
+    BEFORE UPDATE OR DELETE ON audit_records
+    FOR EACH ROW EXECUTE PROCEDURE prevent_audit_record_changes();
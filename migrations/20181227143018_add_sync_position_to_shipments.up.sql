@@ -0,0 +1,33 @@
+-- Shipments are synced in the order their changes were made: by the id of the transaction that made the
+-- change, then by a sequence within it. Unlike updated_at, no shipment can later appear before a
+-- position that has been synced, as long as syncs only go up to transactions that have all finished.
+CREATE SEQUENCE shipment_sync_sequence;
+
+ALTER TABLE shipments
+    ADD COLUMN sync_txid BIGINT,
+    ADD COLUMN sync_sequence BIGINT;
+
+-- Existing shipments come before any new change, in the order they were last updated
+UPDATE shipments
+    SET sync_txid = 0, sync_sequence = ordered.position
+    FROM (SELECT id, row_number() OVER (ORDER BY updated_at, id) AS position FROM shipments) AS ordered
+    WHERE shipments.id = ordered.id;
+SELECT setval('shipment_sync_sequence', (SELECT count(*) + 1 FROM shipments), false);
+
+ALTER TABLE shipments
+    ALTER COLUMN sync_txid SET NOT NULL,
+    ALTER COLUMN sync_sequence SET NOT NULL;
+
+CREATE FUNCTION set_shipment_sync_position() RETURNS trigger AS $$
+BEGIN
+    NEW.sync_txid := txid_current();
+    NEW.sync_sequence := nextval('shipment_sync_sequence');
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER shipments_sync_position
+    BEFORE INSERT OR UPDATE ON shipments
+    FOR EACH ROW EXECUTE PROCEDURE set_shipment_sync_position();
+
+CREATE INDEX shipments_sync_position_idx ON shipments (sync_txid, sync_sequence);
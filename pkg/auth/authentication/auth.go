@@ -57,6 +57,27 @@ func UserAuthMiddleware(logger *zap.Logger) func(next http.Handler) http.Handler
 	}
 }
 
+// FeatureFlagMiddleware evaluates the session's feature flags on every request, so changes to flags
+// take effect without logging in again
+func FeatureFlagMiddleware(logger *zap.Logger, db *pop.Connection) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		mw := func(w http.ResponseWriter, r *http.Request) {
+			session := auth.SessionFromRequestContext(r)
+			if session != nil {
+				features, err := models.EnabledFeatures(db, session)
+				if err != nil {
+					logger.Error("Evaluating feature flags", zap.Error(err))
+					http.Error(w, http.StatusText(500), http.StatusInternalServerError)
+					return
+				}
+				session.Features = features
+			}
+			next.ServeHTTP(w, r)
+		}
+		return http.HandlerFunc(mw)
+	}
+}
+
 func (context Context) landingURL(session *auth.Session) string {
 	return fmt.Sprintf(context.callbackTemplate, session.Hostname)
 }
@@ -224,6 +245,17 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			beeline.AddField(r.Context(), "session.service_member_id", session.ServiceMemberID)
 		}
 
+		if session.IsOfficeApp() && userIdentity.OfficeUserDeactivated != nil && *userIdentity.OfficeUserDeactivated {
+			h.logger.Error("Deactivated office user authenticated at office site", zap.String("email", session.Email))
+			http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+			return
+		}
+		if session.IsTspApp() && userIdentity.TspUserDeactivated != nil && *userIdentity.TspUserDeactivated {
+			h.logger.Error("Deactivated TSP user authenticated at tsp site", zap.String("email", session.Email))
+			http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+			return
+		}
+
 		if userIdentity.OfficeUserID != nil {
 			session.OfficeUserID = *(userIdentity.OfficeUserID)
 		} else if session.IsOfficeApp() {
@@ -237,6 +269,10 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				h.logger.Error("Checking for office user", zap.String("email", session.Email), zap.Error(err))
 				http.Error(w, http.StatusText(500), http.StatusInternalServerError)
 				return
+			} else if officeUser.Deactivated {
+				h.logger.Error("Deactivated office user authenticated at office site", zap.String("email", session.Email))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
 			}
 			session.OfficeUserID = officeUser.ID
 			beeline.AddField(r.Context(), "session.office_user_id", session.OfficeUserID)
@@ -262,6 +298,10 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				h.logger.Error("Checking for TSP user", zap.String("email", session.Email), zap.Error(err))
 				http.Error(w, http.StatusText(500), http.StatusInternalServerError)
 				return
+			} else if tspUser.Deactivated {
+				h.logger.Error("Deactivated TSP user authenticated at tsp site", zap.String("email", session.Email))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
 			}
 			session.TspUserID = tspUser.ID
 			beeline.AddField(r.Context(), "session.tsp_user_id", session.TspUserID)
@@ -290,6 +330,10 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				h.logger.Error("Checking for office user", zap.String("email", session.Email), zap.Error(err))
 				http.Error(w, http.StatusText(500), http.StatusInternalServerError)
 				return
+			} else if officeUser.Deactivated {
+				h.logger.Error("Deactivated office user authenticated at office site", zap.String("email", session.Email))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
 			}
 		}
 
@@ -304,6 +348,10 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				h.logger.Error("Checking for TSP user", zap.String("email", session.Email), zap.Error(err))
 				http.Error(w, http.StatusText(500), http.StatusInternalServerError)
 				return
+			} else if tspUser.Deactivated {
+				h.logger.Error("Deactivated TSP user authenticated at tsp site", zap.String("email", session.Email))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
 			}
 		}
 
@@ -334,13 +382,6 @@ func (h CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	session.Features, err = getAllowedFeatures(h.db, session)
-	if err != nil {
-		h.logger.Error("Error setting roles", zap.Error(err))
-		http.Error(w, http.StatusText(500), http.StatusInternalServerError)
-		return
-	}
-
 	h.logger.Info("logged in", zap.Any("session", session))
 	auth.WriteSessionCookie(w, session, h.clientAuthSecretKey, h.noSessionTimeout, h.logger)
 	http.Redirect(w, r, lURL, http.StatusTemporaryRedirect)
@@ -388,16 +429,3 @@ func fetchToken(logger *zap.Logger, code string, clientID string, loginGovProvid
 	}
 	return &session, err
 }
-
-func getAllowedFeatures(db *pop.Connection, session *auth.Session) ([]string, error) {
-	features := []string{}
-	isDPSUser, err := models.IsDPSUser(db, session.Email)
-	if err != nil {
-		return features, err
-	}
-
-	if isDPSUser {
-		features = append(features, "dps")
-	}
-	return features, nil
-}
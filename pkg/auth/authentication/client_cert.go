@@ -0,0 +1,139 @@
+package authentication
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/felixge/httpsnoop"
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/honeycombio/beeline-go"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// ClientCertMiddleware authenticates requests made with the client certificate of a registered API
+// client. The request's session becomes that of the TSP user who registered the client, so handlers
+// scope it to their TSP as they would a logged in user. Requests without a client certificate are
+// passed on unchanged; those with an unregistered or revoked certificate are rejected.
+//
+// Each client may make its RequestsPerMinute requests a minute on each server, and every request it
+// makes is recorded.
+func ClientCertMiddleware(logger *zap.Logger, db *pop.Connection) func(next http.Handler) http.Handler {
+	limiter := newAPIClientLimiter()
+	return func(next http.Handler) http.Handler {
+		mw := func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+			_, span := beeline.StartSpan(r.Context(), "ClientCertMiddleware")
+			defer span.Send()
+
+			cert := r.TLS.PeerCertificates[0]
+			client, err := models.FetchAPIClientForCertificate(db, cert)
+			if err == models.ErrFetchNotFound {
+				logger.Error("unregistered client certificate",
+					zap.String("sha256_digest", models.CertificateFingerprint(cert)),
+					zap.String("subject", cert.Subject.String()))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
+			} else if err != nil {
+				logger.Error("Fetching API client", zap.Error(err))
+				http.Error(w, http.StatusText(500), http.StatusInternalServerError)
+				return
+			}
+			span.AddField("auth.api_client_id", client.ID)
+
+			tspUser := client.TspUser
+			if tspUser.Deactivated || tspUser.UserID == nil || tspUser.TransportationServiceProviderID != client.TransportationServiceProviderID {
+				logger.Error("API client's TSP user can't use the API", zap.String("api_client_id", client.ID.String()))
+				http.Error(w, http.StatusText(401), http.StatusUnauthorized)
+				return
+			}
+
+			start := time.Now()
+			if allowed, retryAfter := limiter.allow(client.ID, client.RequestsPerMinute, start); !allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(int(retryAfter.Seconds())+1))
+				http.Error(w, http.StatusText(429), http.StatusTooManyRequests)
+				recordAPIClientRequest(logger, db, client.ID, r, http.StatusTooManyRequests, time.Since(start))
+				return
+			}
+
+			session := &auth.Session{
+				ApplicationName: auth.TspApp,
+				UserID:          *tspUser.UserID,
+				Email:           tspUser.Email,
+				FirstName:       tspUser.FirstName,
+				LastName:        tspUser.LastName,
+				TspUserID:       tspUser.ID,
+				APIClientID:     client.ID,
+			}
+			if existing := auth.SessionFromRequestContext(r); existing != nil {
+				session.Hostname = existing.Hostname
+			}
+			span.AddField("auth.tsp_user_id", session.TspUserID)
+			span.AddField("auth.user_id", session.UserID)
+
+			metrics := httpsnoop.CaptureMetrics(next, w, r.WithContext(auth.SetSessionInRequestContext(r, session)))
+			recordAPIClientRequest(logger, db, client.ID, r, metrics.Code, metrics.Duration)
+		}
+		return http.HandlerFunc(mw)
+	}
+}
+
+// recordAPIClientRequest audits a request made by an API client. The response has already been sent,
+// so failures are only logged.
+func recordAPIClientRequest(logger *zap.Logger, db *pop.Connection, clientID uuid.UUID, r *http.Request, statusCode int, duration time.Duration) {
+	request := models.APIClientRequest{
+		APIClientID: clientID,
+		Method:      r.Method,
+		Path:        r.URL.Path,
+		StatusCode:  statusCode,
+		DurationMs:  int(duration / time.Millisecond),
+	}
+	verrs, err := db.ValidateAndCreate(&request)
+	if err != nil || verrs.HasAny() {
+		logger.Error("Recording API client request",
+			zap.String("api_client_id", clientID.String()),
+			zap.Error(err),
+			zap.String("verrs", verrs.String()))
+	}
+}
+
+// apiClientLimiter counts each client's requests in fixed one minute windows
+type apiClientLimiter struct {
+	mu      sync.Mutex
+	windows map[uuid.UUID]*apiClientWindow
+}
+
+type apiClientWindow struct {
+	start    time.Time
+	requests int
+}
+
+func newAPIClientLimiter() *apiClientLimiter {
+	return &apiClientLimiter{windows: map[uuid.UUID]*apiClientWindow{}}
+}
+
+// allow counts a request by the client at now, and returns whether it is within the client's limit
+// and, if it isn't, how long until the next window starts
+func (l *apiClientLimiter) allow(clientID uuid.UUID, requestsPerMinute int, now time.Time) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	window, ok := l.windows[clientID]
+	if !ok || now.Sub(window.start) >= time.Minute {
+		window = &apiClientWindow{start: now}
+		l.windows[clientID] = window
+	}
+	if window.requests >= requestsPerMinute {
+		return false, window.start.Add(time.Minute).Sub(now)
+	}
+	window.requests++
+	return true, 0
+}
@@ -0,0 +1,92 @@
+package authentication
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *AuthSuite) TestClientCertMiddleware() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.db)
+	cert, _ := testdatagen.MakeClientCertificate("tsp-system.example.com")
+	digest := models.CertificateFingerprint(cert)
+	client := models.APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            "Dispatch system",
+		Sha256Digest:                    &digest,
+		RequestsPerMinute:               2,
+	}
+	suite.mustSave(&client)
+
+	var session *auth.Session
+	handler := ClientCertMiddleware(suite.logger, suite.db)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session = auth.SessionFromRequestContext(r)
+	}))
+	request := func(cert *x509.Certificate) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/v1/shipments", nil)
+		req.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+
+	rr := request(cert)
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.True(session.IsAPIClient())
+	suite.True(session.IsTspApp())
+	suite.Equal(client.ID, session.APIClientID)
+	suite.Equal(tspUser.ID, session.TspUserID)
+	suite.Equal(*tspUser.UserID, session.UserID)
+
+	// Clients are limited to their requests a minute
+	suite.Equal(http.StatusOK, request(cert).Code)
+	throttled := request(cert)
+	suite.Equal(http.StatusTooManyRequests, throttled.Code)
+	suite.NotEmpty(throttled.Header().Get("Retry-After"))
+
+	count, err := suite.db.Where("api_client_id = $1", client.ID).Count(&models.APIClientRequests{})
+	suite.NoError(err)
+	suite.Equal(3, count)
+
+	other, _ := testdatagen.MakeClientCertificate("unknown.example.com")
+	suite.Equal(http.StatusUnauthorized, request(other).Code)
+
+	_, err = models.RevokeAPIClient(suite.db, &client, time.Now())
+	suite.NoError(err)
+	suite.Equal(http.StatusUnauthorized, request(cert).Code)
+}
+
+func (suite *AuthSuite) TestClientCertMiddlewarePassesOnRequestsWithoutCertificates() {
+	called := false
+	handler := ClientCertMiddleware(suite.logger, suite.db)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		suite.Equal(uuid.Nil, auth.SessionFromRequestContext(r).APIClientID)
+	}))
+	req := httptest.NewRequest("GET", "/api/v1/shipments", nil)
+	req = req.WithContext(auth.SetSessionInRequestContext(req, &auth.Session{}))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	suite.True(called)
+}
+
+func (suite *AuthSuite) TestAPIClientLimiterResetsEachMinute() {
+	limiter := newAPIClientLimiter()
+	clientID := uuid.Must(uuid.NewV4())
+	now := time.Now()
+
+	allowed, _ := limiter.allow(clientID, 1, now)
+	suite.True(allowed)
+	allowed, retryAfter := limiter.allow(clientID, 1, now.Add(20*time.Second))
+	suite.False(allowed)
+	suite.Equal(40*time.Second, retryAfter)
+	allowed, _ = limiter.allow(clientID, 1, now.Add(time.Minute))
+	suite.True(allowed)
+}
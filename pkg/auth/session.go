@@ -24,6 +24,7 @@ type Session struct {
 	ServiceMemberID uuid.UUID
 	OfficeUserID    uuid.UUID
 	TspUserID       uuid.UUID
+	APIClientID     uuid.UUID
 	Features        []string
 }
 
@@ -54,3 +55,9 @@ func (s *Session) IsOfficeUser() bool {
 func (s *Session) IsTspUser() bool {
 	return s.TspUserID != uuid.Nil
 }
+
+// IsAPIClient checks whether the request was made by TSP software with a client certificate rather
+// than by a logged in user
+func (s *Session) IsAPIClient() bool {
+	return s.APIClientID != uuid.Nil
+}
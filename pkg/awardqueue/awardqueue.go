@@ -18,8 +18,11 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/transcom/mymove/pkg/dates"
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 const awardQueueLockID = 1
@@ -32,8 +35,12 @@ const mps = 0
 
 // AwardQueue encapsulates the TSP award queue process
 type AwardQueue struct {
-	db     *pop.Connection
-	logger *hnyzap.Logger
+	db                 *pop.Connection
+	logger             *hnyzap.Logger
+	notificationSender notifications.NotificationSender
+	calendars          *dates.CalendarCache
+	// offers are the shipment offers made by this run, whose TSPs are notified once it commits
+	offers []models.ShipmentOffer
 }
 
 func (aq *AwardQueue) findAllUnassignedShipments() (models.Shipments, error) {
@@ -153,12 +160,15 @@ func (aq *AwardQueue) assignShipments(ctx context.Context) {
 		awardedCount := 0
 		unawardedCount := 0
 		for _, shipment := range shipments {
-			_, err = aq.attemptShipmentOffer(ctx, shipment)
+			offer, err := aq.attemptShipmentOffer(ctx, shipment)
 			if err != nil {
 				aq.logger.TraceError(ctx, "Failed to offer shipment", zap.Error(err))
 				unawardedCount++
 			} else {
 				awardedCount++
+				if !offer.AdministrativeShipment {
+					aq.offers = append(aq.offers, *offer)
+				}
 			}
 		}
 		aq.logger.TraceInfo(ctx, "Awarded some shipments.",
@@ -245,15 +255,23 @@ func (aq *AwardQueue) assignPerformanceBandsForTSPPerformanceGroup(ctx context.C
 
 // ShipmentWithinBlackoutDates searches the blackout_dates table by TSP ID and shipment details
 // to see if it falls within the window created by the blackout date record and if it matches on
-// optional fields COS, channel, GBLOC, and market.
+// optional fields COS, channel, GBLOC, and market. A requested pickup date on which the TSP's
+// calendar has a closure is treated as a blackout too.
 func (aq *AwardQueue) ShipmentWithinBlackoutDates(tspID uuid.UUID, shipment models.Shipment) (bool, error) {
 	blackoutDates, err := models.FetchTSPBlackoutDates(aq.db, tspID, shipment)
 
 	if err != nil {
 		return false, errors.Wrap(err, "Error retrieving blackout dates from database")
 	}
+	if len(blackoutDates) != 0 || shipment.RequestedPickupDate == nil {
+		return len(blackoutDates) != 0, nil
+	}
 
-	return len(blackoutDates) != 0, nil
+	closed, err := aq.calendars.IsClosure(dates.CalendarScope{TSPID: tspID}, *shipment.RequestedPickupDate)
+	if err != nil {
+		return false, errors.Wrap(err, "Error retrieving TSP calendar closures from database")
+	}
+	return closed, nil
 }
 
 // Run will execute the award queue algorithm.
@@ -263,8 +281,9 @@ func (aq *AwardQueue) Run(ctx context.Context) error {
 
 	originalDB := aq.db
 	defer func() { aq.db = originalDB }()
+	aq.offers = nil
 
-	return aq.db.Transaction(func(tx *pop.Connection) error {
+	err := aq.db.Transaction(func(tx *pop.Connection) error {
 		// ensure that all parts of the AQ run inside the transaction
 		aq.db = tx
 
@@ -284,9 +303,45 @@ func (aq *AwardQueue) Run(ctx context.Context) error {
 
 		// This method should also return an error
 		aq.assignShipments(ctx)
-		return nil
+
+		// Record the webhook events with the offers so that TSPs hear of every offer that is made
+		return aq.recordOfferedEvents(tx)
 	})
+	if err != nil {
+		return err
+	}
+
+	aq.notifyOfferedTSPs(ctx, originalDB)
+	return nil
+}
+
+// notifyOfferedTSPs emails the TSPs offered shipments by the run. Failing to email them doesn't fail
+// the run, as the offers have been made.
+func (aq *AwardQueue) notifyOfferedTSPs(ctx context.Context, db *pop.Connection) {
+	for _, offer := range aq.offers {
+		err := aq.notificationSender.SendNotification(
+			notifications.NewShipmentOffered(db, aq.logger.Logger, offer.ShipmentID, offer.TransportationServiceProviderID),
+		)
+		if err != nil {
+			aq.logger.TraceError(ctx, "Failed to notify TSP of shipment offer",
+				zap.String("shipment_id", offer.ShipmentID.String()),
+				zap.Error(err))
+		}
+	}
+}
 
+// recordOfferedEvents records a shipment offered webhook event for each offer made by the run
+func (aq *AwardQueue) recordOfferedEvents(tx *pop.Connection) error {
+	for _, offer := range aq.offers {
+		err := webhooks.Notify(tx, offer.TransportationServiceProviderID, models.WebhookEventShipmentOffered, webhooks.EventData{
+			ShipmentID:     offer.ShipmentID,
+			ShipmentStatus: string(models.ShipmentStatusAWARDED),
+		})
+		if err != nil {
+			return errors.Wrap(err, "Failed to record shipment offered webhook event")
+		}
+	}
+	return nil
 }
 
 // waitForLock MUST be called within a transaction!
@@ -299,10 +354,13 @@ func waitForLock(ctx context.Context, db *pop.Connection, id int) error {
 	return db.RawQuery("SELECT pg_advisory_xact_lock($1)", id).Exec()
 }
 
-// NewAwardQueue creates a new AwardQueue
-func NewAwardQueue(db *pop.Connection, logger *hnyzap.Logger) *AwardQueue {
+// NewAwardQueue creates a new AwardQueue. TSPs are notified of the shipments they're offered through
+// notificationSender.
+func NewAwardQueue(db *pop.Connection, logger *hnyzap.Logger, notificationSender notifications.NotificationSender) *AwardQueue {
 	return &AwardQueue{
-		db:     db,
-		logger: logger,
+		db:                 db,
+		logger:             logger,
+		notificationSender: notificationSender,
+		calendars:          models.NewCalendarCache(db),
 	}
 }
@@ -14,13 +14,14 @@ import (
 
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/testdatagen"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
 func (suite *AwardQueueSuite) Test_CheckAllTSPsBlackedOut() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	tsp := testdatagen.MakeDefaultTSP(suite.db)
 
@@ -73,7 +74,7 @@ func (suite *AwardQueueSuite) Test_CheckAllTSPsBlackedOut() {
 
 func (suite *AwardQueueSuite) Test_CheckShipmentDuringBlackOut() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	tsp := testdatagen.MakeDefaultTSP(suite.db)
 
@@ -170,7 +171,7 @@ func (suite *AwardQueueSuite) Test_CheckShipmentDuringBlackOut() {
 
 func (suite *AwardQueueSuite) Test_ShipmentWithinBlackoutDates() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 	// Creates a TSP with a blackout date connected to both.
 	testTSP1 := testdatagen.MakeDefaultTSP(suite.db)
 
@@ -252,9 +253,41 @@ func (suite *AwardQueueSuite) Test_ShipmentWithinBlackoutDates() {
 	}
 }
 
+func (suite *AwardQueueSuite) Test_ShipmentOnTSPCalendarClosure() {
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+
+	pickupDate := time.Date(testdatagen.TestYear, time.May, 16, 0, 0, 0, 0, time.UTC)
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			RequestedPickupDate: &pickupDate,
+			BookDate:            &testdatagen.DateInsidePerformancePeriod,
+			Status:              models.ShipmentStatusSUBMITTED,
+		},
+	})
+
+	within, err := queue.ShipmentWithinBlackoutDates(tsp.ID, shipment)
+	suite.Nil(err)
+	suite.False(within)
+
+	verrs, err := suite.db.ValidateAndCreate(&models.CalendarClosure{
+		TransportationServiceProviderID: &tsp.ID,
+		ClosureDate:                     pickupDate,
+		Reason:                          "Warehouse inventory",
+	})
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	// The queue's calendars were cached before the closure was added
+	queue.calendars.Invalidate()
+
+	within, err = queue.ShipmentWithinBlackoutDates(tsp.ID, shipment)
+	suite.Nil(err)
+	suite.True(within)
+}
+
 func (suite *AwardQueueSuite) Test_FindAllUnassignedShipments() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 	_, err := queue.findAllUnassignedShipments()
 
 	if err != nil {
@@ -266,7 +299,7 @@ func (suite *AwardQueueSuite) Test_FindAllUnassignedShipments() {
 // it actually gets offered.
 func (suite *AwardQueueSuite) Test_OfferSingleShipment() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	// Make a shipment
 	market := testdatagen.DefaultMarket
@@ -318,7 +351,7 @@ func (suite *AwardQueueSuite) Test_OfferSingleShipment() {
 // any enabled TSPs.
 func (suite *AwardQueueSuite) Test_FailOfferingSingleShipment() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	// Make a shipment in a new TDL, which inherently has no TSPs
 	market := "dHHG"
@@ -365,7 +398,7 @@ func (suite *AwardQueueSuite) Test_FailOfferingSingleShipment() {
 
 func (suite *AwardQueueSuite) TestAssignShipmentsSingleTSP() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	const shipmentsToMake = 10
 
@@ -424,12 +457,47 @@ func (suite *AwardQueueSuite) TestAssignShipmentsSingleTSP() {
 	}
 }
 
+func (suite *AwardQueueSuite) TestRunNotifiesOfferedTSPs() {
+	notificationSender := notifications.NewRecordingNotificationSender()
+	queue := NewAwardQueue(suite.db, suite.logger, notificationSender)
+
+	market := testdatagen.DefaultMarket
+	sourceGBLOC := testdatagen.DefaultSrcGBLOC
+	pickupDate := testdatagen.DateInsidePeakRateCycle
+	deliveryDate := testdatagen.DateInsidePeakRateCycle.Add(time.Hour)
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			RequestedPickupDate: &pickupDate,
+			ActualPickupDate:    &pickupDate,
+			ActualDeliveryDate:  &deliveryDate,
+			SourceGBLOC:         &sourceGBLOC,
+			Market:              &market,
+			Status:              models.ShipmentStatusSUBMITTED,
+		},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	testdatagen.MakeTSPPerformanceDeprecated(suite.db, tsp, *shipment.TrafficDistributionList, swag.Int(1), mps+1, 0, .3, .3)
+
+	suite.Nil(queue.Run(context.Background()))
+
+	// The TSP is told about the offer once the run has committed it
+	sent := notificationSender.Sent()
+	suite.Len(sent, 1)
+	suite.Equal(notifications.EventShipmentOffered, sent[0].Event)
+	suite.Equal(shipment.ID, sent[0].ShipmentID)
+	suite.Equal(tsp.ID, *sent[0].TSPID)
+
+	// Nothing is left to offer on the next run, so no one is notified again
+	suite.Nil(queue.Run(context.Background()))
+	suite.Len(notificationSender.Sent(), 1)
+}
+
 func (suite *AwardQueueSuite) TestAssignShipmentsToMultipleTSPs() {
 	t := suite.T()
 
 	suite.db.TruncateAll()
 
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 
 	const shipmentsToMake = 17
 
@@ -514,7 +582,7 @@ func (suite *AwardQueueSuite) Test_GetTSPsPerBandNoRemainder() {
 
 func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 	tspsToMake := 5
 
 	tdl := testdatagen.MakeDefaultTDL(suite.db)
@@ -567,7 +635,7 @@ func (suite *AwardQueueSuite) Test_AssignTSPsToBands() {
 // rate cycles get awarded shipments appropriately
 func (suite *AwardQueueSuite) Test_AwardTSPsInDifferentRateCycles() {
 	t := suite.T()
-	queue := NewAwardQueue(suite.db, suite.logger)
+	queue := NewAwardQueue(suite.db, suite.logger, suite.notificationSender)
 	sm := testdatagen.MakeDefaultServiceMember(suite.db)
 
 	twoMonths, _ := time.ParseDuration("2 months")
@@ -742,8 +810,9 @@ func equalSlice(a []int, b []int) bool {
 
 type AwardQueueSuite struct {
 	suite.Suite
-	db     *pop.Connection
-	logger *hnyzap.Logger
+	db                 *pop.Connection
+	logger             *hnyzap.Logger
+	notificationSender notifications.NotificationSender
 }
 
 func (suite *AwardQueueSuite) SetupTest() {
@@ -764,8 +833,9 @@ func TestAwardQueueSuite(t *testing.T) {
 	}
 
 	hs := &AwardQueueSuite{
-		db:     db,
-		logger: &hnyzap.Logger{Logger: logger},
+		db:                 db,
+		logger:             &hnyzap.Logger{Logger: logger},
+		notificationSender: notifications.NewStubNotificationSender(logger),
 	}
 	suite.Run(t, hs)
 }
@@ -63,3 +63,17 @@ func CreateValidDatesBetweenTwoDates(startDate time.Time, endDate time.Time, inc
 	}
 	return dates, nil
 }
+
+// AddBusinessDays returns the date numDays workdays after startDate, keeping startDate's time of day.
+// startDate itself never counts, so work that arrives over a weekend is due numDays workdays after
+// the weekend.
+func AddBusinessDays(startDate time.Time, numDays int, calendar *cal.Calendar) time.Time {
+	d := startDate
+	for daysAdded := 0; daysAdded < numDays; {
+		d = d.AddDate(0, 0, 1)
+		if calendar.IsWorkday(d) {
+			daysAdded++
+		}
+	}
+	return d
+}
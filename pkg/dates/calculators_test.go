@@ -108,6 +108,20 @@ func (suite *DatesSuite) TestCreateValidDatesBetweenTwoDatesEndDateMustBeLater()
 	suite.Error(err)
 }
 
+func (suite *DatesSuite) TestAddBusinessDays() {
+	usCalendar := NewUSCalendar()
+
+	// Friday before Christmas: skips the weekend and the holiday
+	friday := time.Date(2018, 12, 21, 14, 30, 0, 0, time.UTC)
+	suite.Equal(time.Date(2018, 12, 26, 14, 30, 0, 0, time.UTC), AddBusinessDays(friday, 2, usCalendar))
+
+	// Work arriving on a Saturday is due counting from Monday
+	saturday := time.Date(2018, 12, 8, 9, 0, 0, 0, time.UTC)
+	suite.Equal(time.Date(2018, 12, 10, 9, 0, 0, 0, time.UTC), AddBusinessDays(saturday, 1, usCalendar))
+
+	suite.Equal(friday, AddBusinessDays(friday, 0, usCalendar))
+}
+
 type DatesSuite struct {
 	suite.Suite
 	db     *pop.Connection
@@ -1,12 +1,137 @@
 package dates
 
-import "github.com/rickar/cal"
+import (
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid"
+	"github.com/rickar/cal"
+)
 
 // NewUSCalendar returns a new Calendar object initialized with standard US Federal Holidays.
+// Use a CalendarCache to get calendars that also include an installation's or TSP's closures.
 func NewUSCalendar() *cal.Calendar {
-	// NOTE: For now, we are returning a new calendar object for each call.  Could consider
-	// caching this in the future.
 	usCalendar := cal.NewCalendar()
 	cal.AddUsHolidays(usCalendar)
 	return usCalendar
 }
+
+// NewCalendar returns a calendar of the US Federal Holidays plus the given closures. Closures that
+// fall on a weekend are ignored, as the calendar would otherwise observe them on the nearest weekday.
+func NewCalendar(closures []time.Time) *cal.Calendar {
+	calendar := NewUSCalendar()
+	for _, closure := range closures {
+		if closure.Weekday() == time.Saturday || closure.Weekday() == time.Sunday {
+			continue
+		}
+		calendar.AddHoliday(closureHoliday(closure))
+	}
+	return calendar
+}
+
+// closureHoliday returns a holiday that only falls on the closure's date
+func closureHoliday(closure time.Time) cal.Holiday {
+	year, month, day := closure.Date()
+	return cal.NewHolidayFunc(func(y int, loc *time.Location) (time.Month, int) {
+		if y != year {
+			return 0, 0
+		}
+		return month, day
+	})
+}
+
+// CalendarScope identifies whose closures a calendar includes. Unset IDs are uuid.Nil; the zero
+// scope is the federal calendar.
+type CalendarScope struct {
+	DutyStationID          uuid.UUID
+	TransportationOfficeID uuid.UUID
+	TSPID                  uuid.UUID
+}
+
+// ClosureLoader returns the dates on which anyone in a scope is closed
+type ClosureLoader func(scope CalendarScope) ([]time.Time, error)
+
+// CalendarCache builds business-day calendars from the closures of a scope and keeps them for a while,
+// so that repeated date math doesn't reload the closures each time.
+type CalendarCache struct {
+	loader ClosureLoader
+	ttl    time.Duration
+	now    func() time.Time
+
+	mutex   sync.Mutex
+	entries map[CalendarScope]calendarCacheEntry
+}
+
+type calendarCacheEntry struct {
+	calendar  *cal.Calendar
+	closures  map[string]bool
+	expiresAt time.Time
+}
+
+// NewCalendarCache returns a CalendarCache that loads closures with loader and keeps each calendar for ttl
+func NewCalendarCache(loader ClosureLoader, ttl time.Duration) *CalendarCache {
+	return &CalendarCache{
+		loader:  loader,
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[CalendarScope]calendarCacheEntry),
+	}
+}
+
+// Calendar returns the calendar of federal holidays and the scope's closures
+func (c *CalendarCache) Calendar(scope CalendarScope) (*cal.Calendar, error) {
+	entry, err := c.entry(scope)
+	if err != nil {
+		return nil, err
+	}
+	return entry.calendar, nil
+}
+
+// IsClosure returns true if the scope has its own closure on the date, regardless of federal holidays
+func (c *CalendarCache) IsClosure(scope CalendarScope, date time.Time) (bool, error) {
+	entry, err := c.entry(scope)
+	if err != nil {
+		return false, err
+	}
+	return entry.closures[closureKey(date)], nil
+}
+
+// Invalidate drops every cached calendar so the next lookups reload their closures
+func (c *CalendarCache) Invalidate() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.entries = make(map[CalendarScope]calendarCacheEntry)
+}
+
+func (c *CalendarCache) entry(scope CalendarScope) (calendarCacheEntry, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := c.now()
+	if entry, ok := c.entries[scope]; ok && now.Before(entry.expiresAt) {
+		return entry, nil
+	}
+
+	var closures []time.Time
+	if scope != (CalendarScope{}) {
+		var err error
+		if closures, err = c.loader(scope); err != nil {
+			return calendarCacheEntry{}, err
+		}
+	}
+
+	entry := calendarCacheEntry{
+		calendar:  NewCalendar(closures),
+		closures:  make(map[string]bool, len(closures)),
+		expiresAt: now.Add(c.ttl),
+	}
+	for _, closure := range closures {
+		entry.closures[closureKey(closure)] = true
+	}
+	c.entries[scope] = entry
+	return entry, nil
+}
+
+func closureKey(date time.Time) string {
+	return date.Format("2006-01-02")
+}
@@ -0,0 +1,56 @@
+package dates
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+)
+
+func (suite *DatesSuite) TestNewCalendarClosures() {
+	trainingHoliday := time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)
+	saturday := time.Date(2018, 12, 29, 0, 0, 0, 0, time.UTC)
+	calendar := NewCalendar([]time.Time{trainingHoliday, saturday})
+
+	suite.False(calendar.IsWorkday(trainingHoliday))
+	suite.False(calendar.IsWorkday(time.Date(2018, 12, 25, 0, 0, 0, 0, time.UTC)))
+	// Closures only apply to their own year
+	suite.True(calendar.IsWorkday(time.Date(2019, 12, 24, 0, 0, 0, 0, time.UTC)))
+	// A weekend closure isn't observed on the Friday before it
+	suite.True(calendar.IsWorkday(time.Date(2018, 12, 28, 0, 0, 0, 0, time.UTC)))
+}
+
+func (suite *DatesSuite) TestCalendarCache() {
+	trainingHoliday := time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)
+	loads := 0
+	cache := NewCalendarCache(func(scope CalendarScope) ([]time.Time, error) {
+		loads++
+		return []time.Time{trainingHoliday}, nil
+	}, time.Minute)
+	now := time.Date(2018, 12, 10, 9, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	scope := CalendarScope{DutyStationID: uuid.Must(uuid.NewV4())}
+	calendar, err := cache.Calendar(scope)
+	suite.NoError(err)
+	suite.False(calendar.IsWorkday(trainingHoliday))
+	closed, err := cache.IsClosure(scope, trainingHoliday)
+	suite.NoError(err)
+	suite.True(closed)
+	suite.Equal(1, loads)
+
+	// The federal calendar doesn't load any closures
+	federal, err := cache.Calendar(CalendarScope{})
+	suite.NoError(err)
+	suite.True(federal.IsWorkday(trainingHoliday))
+	suite.Equal(1, loads)
+
+	now = now.Add(2 * time.Minute)
+	_, err = cache.Calendar(scope)
+	suite.NoError(err)
+	suite.Equal(2, loads)
+
+	cache.Invalidate()
+	_, err = cache.Calendar(scope)
+	suite.NoError(err)
+	suite.Equal(3, loads)
+}
@@ -2,6 +2,8 @@ package dates
 
 import (
 	"time"
+
+	"github.com/rickar/cal"
 )
 
 // MoveDatesSummary contains the set of dates for a move
@@ -17,25 +19,23 @@ type MoveDatesSummary struct {
 }
 
 // CalculateMoveDates returns a MoveDatesSummary based on the expected move date and estimated pack and transit days
-// This method will not set ReportDays - users must set that manually if its needed
-func (summary *MoveDatesSummary) CalculateMoveDates(moveDate time.Time, estimatedPackDays int, estimatedTransitDays int) {
-	usCalendar := NewUSCalendar()
-
+// on calendar. This method will not set ReportDays - users must set that manually if its needed
+func (summary *MoveDatesSummary) CalculateMoveDates(moveDate time.Time, estimatedPackDays int, estimatedTransitDays int, calendar *cal.Calendar) {
 	summary.MoveDate = moveDate
 	summary.EstimatedPackDays = estimatedPackDays
 	summary.EstimatedTransitDays = estimatedTransitDays
 
 	lastPossiblePackDay := moveDate.AddDate(0, 0, -1)
-	summary.PackDays = CreatePastMoveDates(lastPossiblePackDay, estimatedPackDays, false, usCalendar)
+	summary.PackDays = CreatePastMoveDates(lastPossiblePackDay, estimatedPackDays, false, calendar)
 
 	firstPossiblePickupDay := moveDate
-	pickupDays := CreateFutureMoveDates(firstPossiblePickupDay, 1, false, usCalendar)
+	pickupDays := CreateFutureMoveDates(firstPossiblePickupDay, 1, false, calendar)
 	summary.PickupDays = pickupDays
 
 	firstPossibleTransitDay := time.Time(pickupDays[len(pickupDays)-1]).AddDate(0, 0, 1)
-	transitDays := CreateFutureMoveDates(firstPossibleTransitDay, estimatedTransitDays, true, usCalendar)
+	transitDays := CreateFutureMoveDates(firstPossibleTransitDay, estimatedTransitDays, true, calendar)
 	summary.TransitDays = transitDays
 
 	firstPossibleDeliveryDay := time.Time(transitDays[len(transitDays)-1]).AddDate(0, 0, 1)
-	summary.DeliveryDays = CreateFutureMoveDates(firstPossibleDeliveryDay, 1, false, usCalendar)
+	summary.DeliveryDays = CreateFutureMoveDates(firstPossibleDeliveryDay, 1, false, calendar)
 }
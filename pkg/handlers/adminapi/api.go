@@ -0,0 +1,60 @@
+package adminapi
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/go-openapi/loads"
+
+	"github.com/transcom/mymove/pkg/gen/adminapi"
+	adminops "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations"
+	"github.com/transcom/mymove/pkg/handlers"
+)
+
+// NewAdminAPIHandler returns a handler for the admin API
+func NewAdminAPIHandler(context handlers.HandlerContext) http.Handler {
+	adminSpec, err := loads.Analyzed(adminapi.SwaggerJSON, "")
+	if err != nil {
+		log.Fatalln(err)
+	}
+	adminAPI := adminops.NewMymoveAPI(adminSpec)
+
+	adminAPI.FeatureFlagsIndexFeatureFlagsHandler = IndexFeatureFlagsHandler{context}
+	adminAPI.FeatureFlagsUpdateFeatureFlagHandler = UpdateFeatureFlagHandler{context}
+	adminAPI.FeatureFlagsDeleteFeatureFlagHandler = DeleteFeatureFlagHandler{context}
+
+	adminAPI.OfficeUsersIndexOfficeUsersHandler = IndexOfficeUsersHandler{context}
+	adminAPI.OfficeUsersCreateOfficeUserHandler = CreateOfficeUserHandler{context}
+	adminAPI.OfficeUsersPatchOfficeUserHandler = PatchOfficeUserHandler{context}
+	adminAPI.OfficeUsersDeactivateOfficeUserHandler = DeactivateOfficeUserHandler{context}
+	adminAPI.OfficeUsersReactivateOfficeUserHandler = ReactivateOfficeUserHandler{context}
+
+	adminAPI.TspUsersIndexTspUsersHandler = IndexTspUsersHandler{context}
+	adminAPI.TspUsersCreateTspUserHandler = CreateTspUserHandler{context}
+	adminAPI.TspUsersDeactivateTspUserHandler = DeactivateTspUserHandler{context}
+	adminAPI.TspUsersReactivateTspUserHandler = ReactivateTspUserHandler{context}
+
+	adminAPI.SLAIndexSLAPoliciesHandler = IndexSLAPoliciesHandler{context}
+	adminAPI.SLAUpdateSLAPolicyHandler = UpdateSLAPolicyHandler{context}
+	adminAPI.SLAIndexSLAMetricsHandler = IndexSLAMetricsHandler{context}
+
+	adminAPI.NotificationTemplatesIndexNotificationTemplatesHandler = IndexNotificationTemplatesHandler{context}
+	adminAPI.NotificationTemplatesIndexNotificationTemplateVersionsHandler = IndexNotificationTemplateVersionsHandler{context}
+	adminAPI.NotificationTemplatesCreateNotificationTemplateVersionHandler = CreateNotificationTemplateVersionHandler{context}
+	adminAPI.NotificationTemplatesPreviewNotificationTemplateHandler = PreviewNotificationTemplateHandler{context}
+
+	adminAPI.CalendarClosuresIndexCalendarClosuresHandler = IndexCalendarClosuresHandler{context}
+	adminAPI.CalendarClosuresCreateCalendarClosureHandler = CreateCalendarClosureHandler{context}
+	adminAPI.CalendarClosuresDeleteCalendarClosureHandler = DeleteCalendarClosureHandler{context}
+
+	adminAPI.CapacityIndexCapacityForecastHandler = IndexCapacityForecastHandler{context}
+
+	adminAPI.SurveyQuestionsIndexSurveyQuestionsHandler = IndexSurveyQuestionsHandler{context}
+	adminAPI.SurveyQuestionsCreateSurveyQuestionHandler = CreateSurveyQuestionHandler{context}
+	adminAPI.SurveyQuestionsUpdateSurveyQuestionHandler = UpdateSurveyQuestionHandler{context}
+
+	adminAPI.APIClientsIndexAPIClientsHandler = IndexAPIClientsHandler{context}
+	adminAPI.APIClientsUpdateAPIClientHandler = UpdateAPIClientHandler{context}
+
+	return adminAPI.Serve(nil)
+}
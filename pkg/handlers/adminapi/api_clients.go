@@ -0,0 +1,84 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	apiclientop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/api_clients"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForAPIClientModel(client models.APIClient) *adminmessages.APIClientPayload {
+	return &adminmessages.APIClientPayload{
+		ID:                              handlers.FmtUUID(client.ID),
+		TransportationServiceProviderID: handlers.FmtUUID(client.TransportationServiceProviderID),
+		TspUserID:                       handlers.FmtUUID(client.TspUserID),
+		Name:                            swag.String(client.Name),
+		Sha256Digest:                    client.Sha256Digest,
+		Subject:                         client.Subject,
+		RequestsPerMinute:               swag.Int64(int64(client.RequestsPerMinute)),
+		RevokedAt:                       handlers.FmtDateTimePtr(client.RevokedAt),
+		CreatedAt:                       handlers.FmtDateTime(client.CreatedAt),
+	}
+}
+
+// IndexAPIClientsHandler lists API clients
+type IndexAPIClientsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the API clients of every TSP
+func (h IndexAPIClientsHandler) Handle(params apiclientop.IndexAPIClientsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	clients, err := models.FetchAllAPIClients(h.DB())
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexAPIClientsPayload{}
+	for _, client := range clients {
+		payload = append(payload, payloadForAPIClientModel(client))
+	}
+	return apiclientop.NewIndexAPIClientsOK().WithPayload(payload)
+}
+
+// UpdateAPIClientHandler updates an API client's rate limit
+type UpdateAPIClientHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle changes how many requests a minute the API client may make
+func (h UpdateAPIClientHandler) Handle(params apiclientop.UpdateAPIClientParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	clientID, _ := uuid.FromString(params.APIClientID.String())
+	client, err := models.FetchAPIClientByID(h.DB(), clientID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	client.RequestsPerMinute = int(*params.APIClient.RequestsPerMinute)
+	verrs, err := h.DB().ValidateAndUpdate(client)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("API client rate limit updated",
+		zap.String("api_client_id", client.ID.String()),
+		zap.Int("requests_per_minute", client.RequestsPerMinute),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return apiclientop.NewUpdateAPIClientOK().WithPayload(payloadForAPIClientModel(*client))
+}
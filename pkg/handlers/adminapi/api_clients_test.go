@@ -0,0 +1,49 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	apiclientop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/api_clients"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestAPIClientHandlers() {
+	admin := suite.makeAdmin()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	tspUser := testdatagen.MakeDefaultTspUser(suite.TestDB())
+	subject := "CN=dispatch.example.com"
+	client := models.APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            "Dispatch system",
+		Subject:                         &subject,
+		RequestsPerMinute:               models.DefaultAPIClientRequestsPerMinute,
+	}
+	suite.MustSave(&client)
+
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/api_clients", nil), admin)
+	indexResponse := IndexAPIClientsHandler{context}.Handle(apiclientop.IndexAPIClientsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&apiclientop.IndexAPIClientsOK{}, indexResponse)
+	payload := indexResponse.(*apiclientop.IndexAPIClientsOK).Payload
+	suite.Len(payload, 1)
+	suite.Equal(subject, *payload[0].Subject)
+
+	updateReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("PATCH", "/api_clients/id", nil), admin)
+	updateResponse := UpdateAPIClientHandler{context}.Handle(apiclientop.UpdateAPIClientParams{
+		HTTPRequest: updateReq,
+		APIClientID: *payload[0].ID,
+		APIClient:   &adminmessages.UpdateAPIClientPayload{RequestsPerMinute: swag.Int64(600)},
+	})
+	suite.Assertions.IsType(&apiclientop.UpdateAPIClientOK{}, updateResponse)
+	suite.Equal(int64(600), *updateResponse.(*apiclientop.UpdateAPIClientOK).Payload.RequestsPerMinute)
+
+	// Office users who aren't admins can't see API clients
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	indexReq = suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/api_clients", nil), officeUser)
+	suite.CheckResponseForbidden(IndexAPIClientsHandler{context}.Handle(apiclientop.IndexAPIClientsParams{HTTPRequest: indexReq}))
+}
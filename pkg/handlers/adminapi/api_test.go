@@ -0,0 +1,52 @@
+package adminapi
+
+import (
+	"log"
+	"testing"
+
+	"github.com/gobuffalo/pop"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+// HandlerSuite is an abstraction of our original suite
+type HandlerSuite struct {
+	handlers.BaseTestSuite
+}
+
+// SetupTest sets up the test suite by preparing the DB
+func (suite *HandlerSuite) SetupTest() {
+	suite.TestDB().TruncateAll()
+}
+
+// AfterTest completes tests by trying to close open files
+func (suite *HandlerSuite) AfterTest() {
+	for _, file := range suite.TestFilesToClose() {
+		file.Data.Close()
+	}
+}
+
+// TestHandlerSuite creates our test suite
+func TestHandlerSuite(t *testing.T) {
+	configLocation := "../../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &HandlerSuite{}
+	hs.SetTestDB(db)
+	hs.SetTestLogger(logger)
+	hs.SetTestNotificationSender(notifications.NewStubNotificationSender(logger))
+
+	suite.Run(t, hs)
+}
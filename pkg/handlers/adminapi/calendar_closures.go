@@ -0,0 +1,135 @@
+package adminapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/dates"
+	closureop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/calendar_closures"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForCalendarClosureModel(closure models.CalendarClosure) *adminmessages.CalendarClosurePayload {
+	return &adminmessages.CalendarClosurePayload{
+		ID:                              *handlers.FmtUUID(closure.ID),
+		DutyStationID:                   handlers.FmtUUIDPtr(closure.DutyStationID),
+		TransportationOfficeID:          handlers.FmtUUIDPtr(closure.TransportationOfficeID),
+		TransportationServiceProviderID: handlers.FmtUUIDPtr(closure.TransportationServiceProviderID),
+		ClosureDate:                     handlers.FmtDate(closure.ClosureDate),
+		Reason:                          swag.String(closure.Reason),
+	}
+}
+
+func optionalUUID(param *strfmt.UUID) *uuid.UUID {
+	if param == nil {
+		return nil
+	}
+	id, _ := uuid.FromString(param.String())
+	return &id
+}
+
+// scopeID returns uuid.Nil for a missing parameter, which matches no closures
+func scopeID(param *strfmt.UUID) uuid.UUID {
+	if id := optionalUUID(param); id != nil {
+		return *id
+	}
+	return uuid.Nil
+}
+
+// IndexCalendarClosuresHandler lists calendar closures
+type IndexCalendarClosuresHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the closures of the given duty station, transportation office or TSP, or every closure
+func (h IndexCalendarClosuresHandler) Handle(params closureop.IndexCalendarClosuresParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	scope := dates.CalendarScope{
+		DutyStationID:          scopeID(params.DutyStationID),
+		TransportationOfficeID: scopeID(params.TransportationOfficeID),
+		TSPID:                  scopeID(params.TransportationServiceProviderID),
+	}
+	closures, err := models.FetchCalendarClosures(h.DB(), scope)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexCalendarClosuresPayload{}
+	for _, closure := range closures {
+		payload = append(payload, payloadForCalendarClosureModel(closure))
+	}
+	return closureop.NewIndexCalendarClosuresOK().WithPayload(payload)
+}
+
+// CreateCalendarClosureHandler adds a calendar closure
+type CreateCalendarClosureHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle adds the closure and drops the cached calendars so it applies right away
+func (h CreateCalendarClosureHandler) Handle(params closureop.CreateCalendarClosureParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.CalendarClosure
+	closure := models.CalendarClosure{
+		DutyStationID:                   optionalUUID(payload.DutyStationID),
+		TransportationOfficeID:          optionalUUID(payload.TransportationOfficeID),
+		TransportationServiceProviderID: optionalUUID(payload.TransportationServiceProviderID),
+		ClosureDate:                     time.Time(*payload.ClosureDate),
+		Reason:                          *payload.Reason,
+	}
+	verrs, err := h.DB().ValidateAndCreate(&closure)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	h.Calendars().Invalidate()
+
+	h.Logger().Info("Calendar closure added",
+		zap.String("calendar_closure_id", closure.ID.String()),
+		zap.Time("closure_date", closure.ClosureDate),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return closureop.NewCreateCalendarClosureCreated().WithPayload(payloadForCalendarClosureModel(closure))
+}
+
+// DeleteCalendarClosureHandler deletes a calendar closure
+type DeleteCalendarClosureHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes the closure and drops the cached calendars
+func (h DeleteCalendarClosureHandler) Handle(params closureop.DeleteCalendarClosureParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	closureID, _ := uuid.FromString(params.CalendarClosureID.String())
+	closure, err := models.FetchCalendarClosure(h.DB(), closureID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if err := h.DB().Destroy(closure); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	h.Calendars().Invalidate()
+
+	h.Logger().Info("Calendar closure deleted",
+		zap.String("calendar_closure_id", closure.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return closureop.NewDeleteCalendarClosureNoContent()
+}
@@ -0,0 +1,77 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/dates"
+	closureop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/calendar_closures"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestCalendarClosureHandlers() {
+	admin := suite.makeAdmin()
+	station := testdatagen.FetchOrMakeDefaultDutyStation(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	scope := dates.CalendarScope{DutyStationID: station.ID}
+	trainingHoliday := time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)
+
+	// Warm the cache so we can tell it's dropped when the closure is added
+	calendar, err := context.Calendars().Calendar(scope)
+	suite.NoError(err)
+	suite.True(calendar.IsWorkday(trainingHoliday))
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/calendar_closures", nil), admin)
+	createResponse := CreateCalendarClosureHandler{context}.Handle(closureop.CreateCalendarClosureParams{
+		HTTPRequest: req,
+		CalendarClosure: &adminmessages.CalendarClosurePayload{
+			DutyStationID: handlers.FmtUUID(station.ID),
+			ClosureDate:   handlers.FmtDate(trainingHoliday),
+			Reason:        swag.String("Holiday block leave"),
+		},
+	})
+	suite.Assertions.IsType(&closureop.CreateCalendarClosureCreated{}, createResponse)
+	created := createResponse.(*closureop.CreateCalendarClosureCreated).Payload
+
+	calendar, err = context.Calendars().Calendar(scope)
+	suite.NoError(err)
+	suite.False(calendar.IsWorkday(trainingHoliday))
+
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/calendar_closures", nil), admin)
+	indexResponse := IndexCalendarClosuresHandler{context}.Handle(closureop.IndexCalendarClosuresParams{
+		HTTPRequest:   indexReq,
+		DutyStationID: handlers.FmtUUID(station.ID),
+	})
+	suite.Assertions.IsType(&closureop.IndexCalendarClosuresOK{}, indexResponse)
+	suite.Len(indexResponse.(*closureop.IndexCalendarClosuresOK).Payload, 1)
+
+	deleteReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("DELETE", "/calendar_closures/id", nil), admin)
+	deleteResponse := DeleteCalendarClosureHandler{context}.Handle(closureop.DeleteCalendarClosureParams{
+		HTTPRequest:       deleteReq,
+		CalendarClosureID: created.ID,
+	})
+	suite.Assertions.IsType(&closureop.DeleteCalendarClosureNoContent{}, deleteResponse)
+
+	calendar, err = context.Calendars().Calendar(scope)
+	suite.NoError(err)
+	suite.True(calendar.IsWorkday(trainingHoliday))
+}
+
+func (suite *HandlerSuite) TestCreateCalendarClosureHandlerRequiresOneScope() {
+	admin := suite.makeAdmin()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/calendar_closures", nil), admin)
+	response := CreateCalendarClosureHandler{context}.Handle(closureop.CreateCalendarClosureParams{
+		HTTPRequest: req,
+		CalendarClosure: &adminmessages.CalendarClosurePayload{
+			ClosureDate: handlers.FmtDate(time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)),
+			Reason:      swag.String("Holiday block leave"),
+		},
+	})
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+}
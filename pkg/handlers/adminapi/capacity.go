@@ -0,0 +1,60 @@
+package adminapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/auth"
+	capacityop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/capacity"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// defaultForecastWeeks is how many weeks are forecast when no number is given
+const defaultForecastWeeks = 12
+
+// IndexCapacityForecastHandler projects pickup demand against TSP capacity
+type IndexCapacityForecastHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the demand and capacity of each TDL by week
+func (h IndexCapacityForecastHandler) Handle(params capacityop.IndexCapacityForecastParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	start := time.Now()
+	if params.StartDate != nil {
+		start = time.Time(*params.StartDate)
+	}
+	weeks := defaultForecastWeeks
+	if params.Weeks != nil {
+		weeks = int(*params.Weeks)
+	}
+
+	forecasts, err := models.FetchCapacityForecast(h.DB(), start, weeks)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexCapacityForecastPayload{}
+	for _, forecast := range forecasts {
+		payload = append(payload, &adminmessages.TDLWeekForecastPayload{
+			WeekStart:                 handlers.FmtDate(forecast.WeekStart),
+			TrafficDistributionListID: handlers.FmtUUID(forecast.TrafficDistributionListID),
+			SourceRateArea:            swag.String(forecast.SourceRateArea),
+			DestinationRegion:         swag.String(forecast.DestinationRegion),
+			CodeOfService:             swag.String(forecast.CodeOfService),
+			Demand:                    swag.Int64(int64(forecast.Demand)),
+			Booked:                    swag.Int64(int64(forecast.Booked)),
+			Capacity:                  swag.Int64(int64(forecast.Capacity)),
+			Shortfall:                 swag.Int64(int64(forecast.Shortfall())),
+		})
+	}
+	return capacityop.NewIndexCapacityForecastOK().WithPayload(payload)
+}
@@ -0,0 +1,51 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	capacityop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/capacity"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestIndexCapacityForecastHandler() {
+	admin := suite.makeAdmin()
+	monday := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		testdatagen.MakeShipment(suite.TestDB(), testdatagen.Assertions{
+			Move: models.Move{Status: models.MoveStatusSUBMITTED},
+			Shipment: models.Shipment{
+				Status:              models.ShipmentStatusSUBMITTED,
+				RequestedPickupDate: &monday,
+			},
+		})
+	}
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/capacity_forecast", nil), admin)
+	startDate := strfmt.Date(monday)
+	params := capacityop.IndexCapacityForecastParams{
+		HTTPRequest: req,
+		StartDate:   &startDate,
+		Weeks:       swag.Int64(4),
+	}
+
+	handler := IndexCapacityForecastHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&capacityop.IndexCapacityForecastOK{}, response)
+	payload := response.(*capacityop.IndexCapacityForecastOK).Payload
+	suite.Len(payload, 1)
+	suite.Equal(int64(3), *payload[0].Demand)
+	suite.Equal(int64(0), *payload[0].Capacity)
+	suite.Equal(int64(3), *payload[0].Shortfall)
+
+	// Office users who aren't admins can't see the forecast
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	params.HTTPRequest = suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/capacity_forecast", nil), officeUser)
+	suite.CheckResponseForbidden(handler.Handle(params))
+}
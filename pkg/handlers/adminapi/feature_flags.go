@@ -0,0 +1,128 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	featureflagop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/feature_flags"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForFeatureFlagModel(flag models.FeatureFlag) *adminmessages.FeatureFlagPayload {
+	targets := []*adminmessages.FeatureFlagTarget{}
+	for _, target := range flag.Targets {
+		targets = append(targets, &adminmessages.FeatureFlagTarget{
+			TargetType:  swag.String(string(target.TargetType)),
+			TargetValue: swag.String(target.TargetValue),
+		})
+	}
+	return &adminmessages.FeatureFlagPayload{
+		ID:          *handlers.FmtUUID(flag.ID),
+		Name:        flag.Name,
+		Description: flag.Description,
+		Enabled:     swag.Bool(flag.Enabled),
+		Percentage:  swag.Int64(int64(flag.Percentage)),
+		Targets:     targets,
+		UpdatedAt:   strfmt.DateTime(flag.UpdatedAt),
+	}
+}
+
+// IndexFeatureFlagsHandler lists feature flags
+type IndexFeatureFlagsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists every feature flag
+func (h IndexFeatureFlagsHandler) Handle(params featureflagop.IndexFeatureFlagsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	flags, err := models.FetchFeatureFlags(h.DB())
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexFeatureFlagsPayload{}
+	for _, flag := range flags {
+		payload = append(payload, payloadForFeatureFlagModel(flag))
+	}
+	return featureflagop.NewIndexFeatureFlagsOK().WithPayload(payload)
+}
+
+// UpdateFeatureFlagHandler creates or replaces a feature flag
+type UpdateFeatureFlagHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle creates or replaces a feature flag and its targets
+func (h UpdateFeatureFlagHandler) Handle(params featureflagop.UpdateFeatureFlagParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	flag, err := models.FetchFeatureFlagByName(h.DB(), params.Name)
+	if err == models.ErrFetchNotFound {
+		flag = &models.FeatureFlag{Name: params.Name}
+	} else if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.FeatureFlag
+	flag.Description = payload.Description
+	flag.Enabled = *payload.Enabled
+	flag.Percentage = int(*payload.Percentage)
+	flag.Targets = models.FeatureFlagTargets{}
+	for _, target := range payload.Targets {
+		flag.Targets = append(flag.Targets, models.FeatureFlagTarget{
+			TargetType:  models.FeatureFlagTargetType(*target.TargetType),
+			TargetValue: *target.TargetValue,
+		})
+	}
+
+	verrs, err := models.SaveFeatureFlag(h.DB(), flag)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Feature flag updated",
+		zap.String("feature_flag", flag.Name),
+		zap.Bool("enabled", flag.Enabled),
+		zap.Int("percentage", flag.Percentage),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return featureflagop.NewUpdateFeatureFlagOK().WithPayload(payloadForFeatureFlagModel(*flag))
+}
+
+// DeleteFeatureFlagHandler deletes a feature flag
+type DeleteFeatureFlagHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes a feature flag
+func (h DeleteFeatureFlagHandler) Handle(params featureflagop.DeleteFeatureFlagParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	flag, err := models.FetchFeatureFlagByName(h.DB(), params.Name)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	if err := models.DeleteFeatureFlag(h.DB(), flag); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	h.Logger().Info("Feature flag deleted",
+		zap.String("feature_flag", flag.Name),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return featureflagop.NewDeleteFeatureFlagNoContent()
+}
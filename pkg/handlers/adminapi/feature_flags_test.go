@@ -0,0 +1,77 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	featureflagop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/feature_flags"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) makeAdmin() models.OfficeUser {
+	return testdatagen.MakeOfficeUser(suite.TestDB(), testdatagen.Assertions{
+		User:       models.User{LoginGovEmail: "admin@example.com"},
+		OfficeUser: models.OfficeUser{IsAdmin: true},
+	})
+}
+
+func (suite *HandlerSuite) TestUpdateFeatureFlagHandler() {
+	admin := suite.makeAdmin()
+
+	req := httptest.NewRequest("PUT", "/feature_flags/queue_v2", nil)
+	req = suite.AuthenticateOfficeRequest(req, admin)
+	params := featureflagop.UpdateFeatureFlagParams{
+		HTTPRequest: req,
+		Name:        "queue_v2",
+		FeatureFlag: &adminmessages.FeatureFlagPayload{
+			Enabled:    swag.Bool(true),
+			Percentage: swag.Int64(10),
+			Targets: []*adminmessages.FeatureFlagTarget{
+				{TargetType: swag.String("GBLOC"), TargetValue: swag.String("LKBM")},
+			},
+		},
+	}
+
+	handler := UpdateFeatureFlagHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&featureflagop.UpdateFeatureFlagOK{}, response)
+	okResponse := response.(*featureflagop.UpdateFeatureFlagOK)
+	suite.Equal("queue_v2", okResponse.Payload.Name)
+	suite.Equal(int64(10), *okResponse.Payload.Percentage)
+	suite.Len(okResponse.Payload.Targets, 1)
+
+	// The flag shows up in the index
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/feature_flags", nil), admin)
+	indexHandler := IndexFeatureFlagsHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	indexResponse := indexHandler.Handle(featureflagop.IndexFeatureFlagsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&featureflagop.IndexFeatureFlagsOK{}, indexResponse)
+	suite.Len(indexResponse.(*featureflagop.IndexFeatureFlagsOK).Payload, 1)
+
+	// Invalid target types are rejected
+	params.FeatureFlag.Targets[0].TargetType = swag.String("PLANET")
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	// And the flag can be deleted
+	deleteReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("DELETE", "/feature_flags/queue_v2", nil), admin)
+	deleteHandler := DeleteFeatureFlagHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	deleteResponse := deleteHandler.Handle(featureflagop.DeleteFeatureFlagParams{HTTPRequest: deleteReq, Name: "queue_v2"})
+	suite.Assertions.IsType(&featureflagop.DeleteFeatureFlagNoContent{}, deleteResponse)
+	_, err := models.FetchFeatureFlagByName(suite.TestDB(), "queue_v2")
+	suite.Equal(models.ErrFetchNotFound, err)
+}
+
+func (suite *HandlerSuite) TestFeatureFlagHandlersRequireAdmin() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/feature_flags", nil), officeUser)
+	handler := IndexFeatureFlagsHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(featureflagop.IndexFeatureFlagsParams{HTTPRequest: req})
+
+	suite.CheckResponseForbidden(response)
+}
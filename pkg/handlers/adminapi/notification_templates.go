@@ -0,0 +1,164 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	notificationtemplateop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/notification_templates"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+func payloadForNotificationTemplateModel(template models.NotificationTemplate) *adminmessages.NotificationTemplatePayload {
+	payload := adminmessages.NotificationTemplatePayload{
+		Event:    template.Event,
+		Version:  int64(template.Version),
+		Subject:  swag.String(template.Subject),
+		HTMLBody: swag.String(template.HTMLBody),
+		TextBody: swag.String(template.TextBody),
+	}
+	if template.Version > 0 {
+		payload.CreatedAt = handlers.FmtDateTime(template.CreatedAt)
+	}
+	return &payload
+}
+
+// IndexNotificationTemplatesHandler lists the current notification templates
+type IndexNotificationTemplatesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the latest version of every event's templates
+func (h IndexNotificationTemplatesHandler) Handle(params notificationtemplateop.IndexNotificationTemplatesParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexNotificationTemplatesPayload{}
+	for _, event := range notifications.NotificationEvents() {
+		template, err := notifications.CurrentTemplate(h.DB(), event)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		payload = append(payload, payloadForNotificationTemplateModel(template))
+	}
+	return notificationtemplateop.NewIndexNotificationTemplatesOK().WithPayload(payload)
+}
+
+// IndexNotificationTemplateVersionsHandler lists the versions of an event's templates
+type IndexNotificationTemplateVersionsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists every stored version of an event's templates
+func (h IndexNotificationTemplateVersionsHandler) Handle(params notificationtemplateop.IndexNotificationTemplateVersionsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	// Checks that the event exists
+	if _, err := notifications.CurrentTemplate(h.DB(), params.Event); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	templates, err := models.FetchNotificationTemplateVersions(h.DB(), params.Event)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexNotificationTemplatesPayload{}
+	for _, template := range templates {
+		payload = append(payload, payloadForNotificationTemplateModel(template))
+	}
+	return notificationtemplateop.NewIndexNotificationTemplateVersionsOK().WithPayload(payload)
+}
+
+// CreateNotificationTemplateVersionHandler creates a version of an event's templates
+type CreateNotificationTemplateVersionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle stores the templates as the event's next version once they render with the event's data
+func (h CreateNotificationTemplateVersionHandler) Handle(params notificationtemplateop.CreateNotificationTemplateVersionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	if _, err := notifications.CurrentTemplate(h.DB(), params.Event); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.NotificationTemplate
+	template := models.NotificationTemplate{
+		Event:                 params.Event,
+		Subject:               swag.StringValue(payload.Subject),
+		HTMLBody:              swag.StringValue(payload.HTMLBody),
+		TextBody:              swag.StringValue(payload.TextBody),
+		CreatedByOfficeUserID: &session.OfficeUserID,
+	}
+	verrs := notifications.ValidateTemplate(template)
+	if verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, nil)
+	}
+
+	verrs, err := models.CreateNotificationTemplateVersion(h.DB(), &template)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Notification template version created",
+		zap.String("event", template.Event),
+		zap.Int("version", template.Version),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return notificationtemplateop.NewCreateNotificationTemplateVersionCreated().WithPayload(payloadForNotificationTemplateModel(template))
+}
+
+// PreviewNotificationTemplateHandler renders an event's notification for a move
+type PreviewNotificationTemplateHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle renders the current templates of an event, or drafts of them, with a move's data
+func (h PreviewNotificationTemplateHandler) Handle(params notificationtemplateop.PreviewNotificationTemplateParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	template, err := notifications.CurrentTemplate(h.DB(), params.Event)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.Preview
+	if payload.Subject != nil {
+		template.Subject = *payload.Subject
+	}
+	if payload.HTMLBody != nil {
+		template.HTMLBody = *payload.HTMLBody
+	}
+	if payload.TextBody != nil {
+		template.TextBody = *payload.TextBody
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	moveID, _ := uuid.FromString(payload.MoveID.String())
+	email, verrs, err := notifications.PreviewEmail(h.DB(), h.Logger(), session, template, moveID)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	return notificationtemplateop.NewPreviewNotificationTemplateOK().WithPayload(&adminmessages.RenderedNotificationPayload{
+		Subject:  swag.String(email.Subject),
+		HTMLBody: swag.String(email.HTMLBody),
+		TextBody: swag.String(email.TextBody),
+	})
+}
@@ -0,0 +1,79 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	notificationtemplateop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/notification_templates"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestCreateNotificationTemplateVersionHandler() {
+	admin := suite.makeAdmin()
+
+	req := httptest.NewRequest("POST", "/notification_templates/move_approved", nil)
+	params := notificationtemplateop.CreateNotificationTemplateVersionParams{
+		HTTPRequest: suite.AuthenticateOfficeRequest(req, admin),
+		Event:       notifications.EventMoveApproved,
+		NotificationTemplate: &adminmessages.NotificationTemplatePayload{
+			Subject:  swag.String("Move {{.Locator}} approved"),
+			HTMLBody: swag.String("<p>Move {{.Locator}} approved</p>"),
+			TextBody: swag.String("Move {{.Locator}} approved"),
+		},
+	}
+
+	handler := CreateNotificationTemplateVersionHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&notificationtemplateop.CreateNotificationTemplateVersionCreated{}, response)
+	payload := response.(*notificationtemplateop.CreateNotificationTemplateVersionCreated).Payload
+	suite.Equal(int64(1), payload.Version)
+
+	// Templates that use data the event doesn't have aren't stored
+	params.NotificationTemplate.TextBody = swag.String("Shipment {{.ShipmentID}} approved")
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	params.Event = "move_misplaced"
+	response = handler.Handle(params)
+	suite.CheckResponseNotFound(response)
+
+	// Office users who aren't admins can't change templates
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	params.HTTPRequest = suite.AuthenticateOfficeRequest(req, officeUser)
+	response = handler.Handle(params)
+	suite.CheckResponseForbidden(response)
+}
+
+func (suite *HandlerSuite) TestPreviewNotificationTemplateHandler() {
+	admin := suite.makeAdmin()
+	move := testdatagen.MakeDefaultMove(suite.TestDB())
+
+	req := httptest.NewRequest("POST", "/notification_templates/move_canceled/preview", nil)
+	params := notificationtemplateop.PreviewNotificationTemplateParams{
+		HTTPRequest: suite.AuthenticateOfficeRequest(req, admin),
+		Event:       notifications.EventMoveCanceled,
+		Preview: &adminmessages.PreviewNotificationTemplatePayload{
+			MoveID:   handlers.FmtUUID(move.ID),
+			TextBody: swag.String("Move {{.Locator}} was canceled"),
+		},
+	}
+
+	handler := PreviewNotificationTemplateHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&notificationtemplateop.PreviewNotificationTemplateOK{}, response)
+	payload := response.(*notificationtemplateop.PreviewNotificationTemplateOK).Payload
+	suite.Equal("MOVE.MIL: Your move has been canceled.", *payload.Subject)
+	suite.Contains(*payload.HTMLBody, move.Locator)
+	suite.Equal("Move "+move.Locator+" was canceled", *payload.TextBody)
+
+	params.Preview.MoveID = (*strfmt.UUID)(swag.String("c56a4180-65aa-42ec-a945-5fd21dec0538"))
+	response = handler.Handle(params)
+	suite.CheckResponseNotFound(response)
+}
@@ -0,0 +1,232 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	officeuserop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/office_users"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForOfficeUserModel(officeUser models.OfficeUser) *adminmessages.OfficeUserPayload {
+	return &adminmessages.OfficeUserPayload{
+		ID:                       handlers.FmtUUID(officeUser.ID),
+		UserID:                   handlers.FmtUUIDPtr(officeUser.UserID),
+		FirstName:                handlers.FmtString(officeUser.FirstName),
+		MiddleInitials:           officeUser.MiddleInitials,
+		LastName:                 handlers.FmtString(officeUser.LastName),
+		Email:                    handlers.FmtEmail(officeUser.Email),
+		Telephone:                handlers.FmtString(officeUser.Telephone),
+		TransportationOfficeID:   handlers.FmtUUID(officeUser.TransportationOfficeID),
+		TransportationOfficeName: officeUser.TransportationOffice.Name,
+		AccessScope:              adminmessages.OfficeAccessScope(officeUser.AccessScope),
+		IsSupervisor:             handlers.FmtBool(officeUser.IsSupervisor),
+		IsAdmin:                  handlers.FmtBool(officeUser.IsAdmin),
+		Deactivated:              handlers.FmtBool(officeUser.Deactivated),
+		CreatedAt:                handlers.FmtDateTime(officeUser.CreatedAt),
+		UpdatedAt:                handlers.FmtDateTime(officeUser.UpdatedAt),
+	}
+}
+
+// fetchOfficeUserForAdministration loads an office user with their transportation office
+func fetchOfficeUserForAdministration(h handlers.HandlerContext, officeUserID strfmt.UUID) (*models.OfficeUser, error) {
+	id, err := uuid.FromString(officeUserID.String())
+	if err != nil {
+		return nil, err
+	}
+	return models.FetchOfficeUserByID(h.DB(), id)
+}
+
+// IndexOfficeUsersHandler lists office users
+type IndexOfficeUsersHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists office users, optionally only those in one transportation office
+func (h IndexOfficeUsersHandler) Handle(params officeuserop.IndexOfficeUsersParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	var transportationOfficeID *uuid.UUID
+	if params.TransportationOfficeID != nil {
+		id, err := uuid.FromString(params.TransportationOfficeID.String())
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		transportationOfficeID = &id
+	}
+
+	officeUsers, err := models.FetchOfficeUsers(h.DB(), transportationOfficeID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexOfficeUsersPayload{}
+	for _, officeUser := range officeUsers {
+		payload = append(payload, payloadForOfficeUserModel(officeUser))
+	}
+	return officeuserop.NewIndexOfficeUsersOK().WithPayload(payload)
+}
+
+// CreateOfficeUserHandler invites an office user
+type CreateOfficeUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle creates an office user who can log in once login.gov authenticates their email
+func (h CreateOfficeUserHandler) Handle(params officeuserop.CreateOfficeUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.OfficeUser
+	transportationOfficeID, err := uuid.FromString(payload.TransportationOfficeID.String())
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	transportationOffice, err := models.FetchTransportationOffice(h.DB(), transportationOfficeID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	officeUser := models.OfficeUser{
+		FirstName:              *payload.FirstName,
+		MiddleInitials:         payload.MiddleInitials,
+		LastName:               *payload.LastName,
+		Email:                  payload.Email.String(),
+		Telephone:              *payload.Telephone,
+		TransportationOfficeID: transportationOffice.ID,
+		TransportationOffice:   transportationOffice,
+		AccessScope:            models.OfficeAccessScope(payload.AccessScope),
+		IsSupervisor:           payload.IsSupervisor,
+		IsAdmin:                payload.IsAdmin,
+	}
+	verrs, err := models.InviteOfficeUser(h.DB(), session, &officeUser)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Office user invited",
+		zap.String("invited_office_user_id", officeUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return officeuserop.NewCreateOfficeUserCreated().WithPayload(payloadForOfficeUserModel(officeUser))
+}
+
+// PatchOfficeUserHandler updates an office user
+type PatchOfficeUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle reassigns an office user to another transportation office or changes their access
+func (h PatchOfficeUserHandler) Handle(params officeuserop.PatchOfficeUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	officeUser, err := fetchOfficeUserForAdministration(h.HandlerContext, params.OfficeUserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *officeUser
+
+	payload := params.PatchOfficeUserPayload
+	if payload.TransportationOfficeID != nil {
+		transportationOfficeID, err := uuid.FromString(payload.TransportationOfficeID.String())
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		transportationOffice, err := models.FetchTransportationOffice(h.DB(), transportationOfficeID)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		officeUser.TransportationOfficeID = transportationOffice.ID
+		officeUser.TransportationOffice = transportationOffice
+	}
+	if payload.AccessScope != "" {
+		officeUser.AccessScope = models.OfficeAccessScope(payload.AccessScope)
+	}
+	if payload.IsSupervisor != nil {
+		officeUser.IsSupervisor = *payload.IsSupervisor
+	}
+	if payload.IsAdmin != nil {
+		if officeUser.ID == session.OfficeUserID && !*payload.IsAdmin {
+			return officeuserop.NewPatchOfficeUserBadRequest()
+		}
+		officeUser.IsAdmin = *payload.IsAdmin
+	}
+
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, officeUser, "update", "")
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	return officeuserop.NewPatchOfficeUserOK().WithPayload(payloadForOfficeUserModel(*officeUser))
+}
+
+// DeactivateOfficeUserHandler deactivates an office user
+type DeactivateOfficeUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deactivates an office user so they can no longer log in
+func (h DeactivateOfficeUserHandler) Handle(params officeuserop.DeactivateOfficeUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	officeUser, err := fetchOfficeUserForAdministration(h.HandlerContext, params.OfficeUserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if officeUser.ID == session.OfficeUserID {
+		return officeuserop.NewDeactivateOfficeUserBadRequest()
+	}
+
+	verrs, err := models.SetOfficeUserDeactivated(h.DB(), session, officeUser, true)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Office user deactivated",
+		zap.String("deactivated_office_user_id", officeUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return officeuserop.NewDeactivateOfficeUserOK().WithPayload(payloadForOfficeUserModel(*officeUser))
+}
+
+// ReactivateOfficeUserHandler reactivates an office user
+type ReactivateOfficeUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle reactivates an office user so they can log in again
+func (h ReactivateOfficeUserHandler) Handle(params officeuserop.ReactivateOfficeUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	officeUser, err := fetchOfficeUserForAdministration(h.HandlerContext, params.OfficeUserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.SetOfficeUserDeactivated(h.DB(), session, officeUser, false)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Office user reactivated",
+		zap.String("reactivated_office_user_id", officeUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return officeuserop.NewReactivateOfficeUserOK().WithPayload(payloadForOfficeUserModel(*officeUser))
+}
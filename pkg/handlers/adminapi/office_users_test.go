@@ -0,0 +1,103 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/auth"
+	officeuserop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/office_users"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestCreateAndReassignOfficeUser() {
+	admin := suite.makeAdmin()
+	otherOffice := testdatagen.MakeTransportationOffice(suite.TestDB(), testdatagen.Assertions{
+		TransportationOffice: models.TransportationOffice{Gbloc: "AGFM"},
+	})
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/office_users", nil), admin)
+	params := officeuserop.CreateOfficeUserParams{
+		HTTPRequest: req,
+		OfficeUser: &adminmessages.CreateOfficeUserPayload{
+			FirstName:              swag.String("Ingrid"),
+			LastName:               swag.String("Invitee"),
+			Email:                  handlers.FmtEmail("ingrid.invitee@example.com"),
+			Telephone:              swag.String("415-555-1212"),
+			TransportationOfficeID: handlers.FmtUUID(admin.TransportationOfficeID),
+		},
+	}
+	handler := CreateOfficeUserHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&officeuserop.CreateOfficeUserCreated{}, response)
+	created := response.(*officeuserop.CreateOfficeUserCreated).Payload
+	suite.Equal("ingrid.invitee@example.com", created.Email.String())
+	suite.False(*created.Deactivated)
+
+	// Inviting the same email again is rejected
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	patchReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("PATCH", "/office_users/id", nil), admin)
+	patchHandler := PatchOfficeUserHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	patchResponse := patchHandler.Handle(officeuserop.PatchOfficeUserParams{
+		HTTPRequest:  patchReq,
+		OfficeUserID: *created.ID,
+		PatchOfficeUserPayload: &adminmessages.PatchOfficeUserPayload{
+			TransportationOfficeID: handlers.FmtUUID(otherOffice.ID),
+		},
+	})
+	suite.Assertions.IsType(&officeuserop.PatchOfficeUserOK{}, patchResponse)
+	suite.Equal(strfmt.UUID(otherOffice.ID.String()), *patchResponse.(*officeuserop.PatchOfficeUserOK).Payload.TransportationOfficeID)
+}
+
+func (suite *HandlerSuite) TestDeactivateOfficeUserHandler() {
+	admin := suite.makeAdmin()
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	handler := DeactivateOfficeUserHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/office_users/id/deactivate", nil), admin)
+	response := handler.Handle(officeuserop.DeactivateOfficeUserParams{
+		HTTPRequest:  req,
+		OfficeUserID: *handlers.FmtUUID(officeUser.ID),
+	})
+	suite.Assertions.IsType(&officeuserop.DeactivateOfficeUserOK{}, response)
+	suite.True(*response.(*officeuserop.DeactivateOfficeUserOK).Payload.Deactivated)
+
+	// The deactivated office user can no longer use the office app
+	officeReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/queues/new", nil), officeUser)
+	role, err := models.FetchAccessRole(suite.TestDB(), auth.SessionFromRequestContext(officeReq))
+	suite.Nil(err)
+	suite.Equal(models.AccessRoleNone, role)
+
+	// Admins can't deactivate themselves
+	response = handler.Handle(officeuserop.DeactivateOfficeUserParams{
+		HTTPRequest:  req,
+		OfficeUserID: *handlers.FmtUUID(admin.ID),
+	})
+	suite.Assertions.IsType(&officeuserop.DeactivateOfficeUserBadRequest{}, response)
+
+	reactivateReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/office_users/id/reactivate", nil), admin)
+	reactivateHandler := ReactivateOfficeUserHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	reactivateResponse := reactivateHandler.Handle(officeuserop.ReactivateOfficeUserParams{
+		HTTPRequest:  reactivateReq,
+		OfficeUserID: *handlers.FmtUUID(officeUser.ID),
+	})
+	suite.Assertions.IsType(&officeuserop.ReactivateOfficeUserOK{}, reactivateResponse)
+	suite.False(*reactivateResponse.(*officeuserop.ReactivateOfficeUserOK).Payload.Deactivated)
+}
+
+func (suite *HandlerSuite) TestOfficeUserHandlersRequireAdmin() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/office_users", nil), officeUser)
+	handler := IndexOfficeUsersHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(officeuserop.IndexOfficeUsersParams{HTTPRequest: req})
+
+	suite.CheckResponseForbidden(response)
+}
@@ -0,0 +1,121 @@
+package adminapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	slaop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/sla"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// slaMetricsPeriod is how far back escalations are counted when no start is given
+const slaMetricsPeriod = 30 * 24 * time.Hour
+
+func payloadForSLAPolicyModel(policy models.SLAPolicy) *adminmessages.SLAPolicyPayload {
+	return &adminmessages.SLAPolicyPayload{
+		ID:           *handlers.FmtUUID(policy.ID),
+		RecordType:   policy.RecordType,
+		Status:       policy.Status,
+		BusinessDays: swag.Int64(int64(policy.BusinessDays)),
+	}
+}
+
+// IndexSLAPoliciesHandler lists SLA policies
+type IndexSLAPoliciesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists every SLA policy
+func (h IndexSLAPoliciesHandler) Handle(params slaop.IndexSLAPoliciesParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	policies, err := models.FetchSLAPolicies(h.DB())
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexSLAPoliciesPayload{}
+	for _, policy := range policies {
+		payload = append(payload, payloadForSLAPolicyModel(policy))
+	}
+	return slaop.NewIndexSLAPoliciesOK().WithPayload(payload)
+}
+
+// UpdateSLAPolicyHandler updates an SLA policy
+type UpdateSLAPolicyHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle changes how many business days records may wait in a policy's status
+func (h UpdateSLAPolicyHandler) Handle(params slaop.UpdateSLAPolicyParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	policyID, _ := uuid.FromString(params.SLAPolicyID.String())
+	policy, err := models.FetchSLAPolicy(h.DB(), policyID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	policy.BusinessDays = int(*params.SLAPolicy.BusinessDays)
+	verrs, err := h.DB().ValidateAndUpdate(policy)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("SLA policy updated",
+		zap.String("record_type", policy.RecordType),
+		zap.String("status", policy.Status),
+		zap.Int("business_days", policy.BusinessDays),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return slaop.NewUpdateSLAPolicyOK().WithPayload(payloadForSLAPolicyModel(*policy))
+}
+
+// IndexSLAMetricsHandler reports SLA breaches per transportation office
+type IndexSLAMetricsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle counts each transportation office's waiting, overdue and escalated records
+func (h IndexSLAMetricsHandler) Handle(params slaop.IndexSLAMetricsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	now := time.Now()
+	since := now.Add(-slaMetricsPeriod)
+	if params.Since != nil {
+		since = time.Time(*params.Since)
+	}
+
+	metrics, err := models.FetchSLAMetrics(h.DB(), h.Calendars(), now, since)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexSLAMetricsPayload{}
+	for _, officeMetrics := range metrics {
+		payload = append(payload, &adminmessages.SLAOfficeMetricsPayload{
+			TransportationOfficeID:   handlers.FmtUUIDPtr(officeMetrics.TransportationOfficeID),
+			TransportationOfficeName: swag.String(officeMetrics.TransportationOfficeName),
+			Open:                     swag.Int64(int64(officeMetrics.Open)),
+			Overdue:                  swag.Int64(int64(officeMetrics.Overdue)),
+			Escalations:              swag.Int64(int64(officeMetrics.Escalations)),
+		})
+	}
+	return slaop.NewIndexSLAMetricsOK().WithPayload(payload)
+}
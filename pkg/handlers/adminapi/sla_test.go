@@ -0,0 +1,54 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	slaop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/sla"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestUpdateSLAPolicyHandler() {
+	admin := suite.makeAdmin()
+	policy := models.SLAPolicy{RecordType: "move", Status: "SUBMITTED", BusinessDays: 3}
+	suite.MustSave(&policy)
+
+	req := httptest.NewRequest("PUT", "/sla_policies/"+policy.ID.String(), nil)
+	params := slaop.UpdateSLAPolicyParams{
+		HTTPRequest: suite.AuthenticateOfficeRequest(req, admin),
+		SLAPolicyID: strfmt.UUID(policy.ID.String()),
+		SLAPolicy:   &adminmessages.SLAPolicyPayload{BusinessDays: swag.Int64(5)},
+	}
+
+	handler := UpdateSLAPolicyHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&slaop.UpdateSLAPolicyOK{}, response)
+	okResponse := response.(*slaop.UpdateSLAPolicyOK)
+	suite.Equal("SUBMITTED", okResponse.Payload.Status)
+	suite.Equal(int64(5), *okResponse.Payload.BusinessDays)
+
+	// Office users who aren't admins can't change policies
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	params.HTTPRequest = suite.AuthenticateOfficeRequest(req, officeUser)
+	response = handler.Handle(params)
+	suite.CheckResponseForbidden(response)
+}
+
+func (suite *HandlerSuite) TestIndexSLAMetricsHandler() {
+	admin := suite.makeAdmin()
+
+	req := httptest.NewRequest("GET", "/sla_metrics", nil)
+	params := slaop.IndexSLAMetricsParams{HTTPRequest: suite.AuthenticateOfficeRequest(req, admin)}
+
+	handler := IndexSLAMetricsHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	suite.Assertions.IsType(&slaop.IndexSLAMetricsOK{}, response)
+	suite.Len(response.(*slaop.IndexSLAMetricsOK).Payload, 0)
+}
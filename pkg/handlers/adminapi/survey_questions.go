@@ -0,0 +1,113 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	surveyop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/survey_questions"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForSurveyQuestionModel(question models.SurveyQuestion) *adminmessages.SurveyQuestionPayload {
+	return &adminmessages.SurveyQuestionPayload{
+		ID:       *handlers.FmtUUID(question.ID),
+		Prompt:   swag.String(question.Prompt),
+		Kind:     swag.String(string(question.Kind)),
+		Position: int64(question.Position),
+		Active:   swag.Bool(question.Active),
+	}
+}
+
+// setSurveyQuestionFields copies the editable fields of a payload onto a survey question
+func setSurveyQuestionFields(question *models.SurveyQuestion, payload *adminmessages.SurveyQuestionPayload) {
+	question.Prompt = *payload.Prompt
+	question.Kind = models.SurveyQuestionKind(*payload.Kind)
+	question.Position = int(payload.Position)
+	question.Active = *payload.Active
+}
+
+// IndexSurveyQuestionsHandler lists survey questions
+type IndexSurveyQuestionsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists every survey question in the order they are asked
+func (h IndexSurveyQuestionsHandler) Handle(params surveyop.IndexSurveyQuestionsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	questions, err := models.FetchSurveyQuestions(h.DB(), false)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexSurveyQuestionsPayload{}
+	for _, question := range questions {
+		payload = append(payload, payloadForSurveyQuestionModel(question))
+	}
+	return surveyop.NewIndexSurveyQuestionsOK().WithPayload(payload)
+}
+
+// CreateSurveyQuestionHandler adds a survey question
+type CreateSurveyQuestionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle adds the question to the surveys sent from now on
+func (h CreateSurveyQuestionHandler) Handle(params surveyop.CreateSurveyQuestionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	question := models.SurveyQuestion{}
+	setSurveyQuestionFields(&question, params.SurveyQuestion)
+	verrs, err := h.DB().ValidateAndCreate(&question)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Survey question added",
+		zap.String("survey_question_id", question.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return surveyop.NewCreateSurveyQuestionCreated().WithPayload(payloadForSurveyQuestionModel(question))
+}
+
+// UpdateSurveyQuestionHandler updates a survey question
+type UpdateSurveyQuestionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle changes a survey question. Answers already given to it are kept.
+func (h UpdateSurveyQuestionHandler) Handle(params surveyop.UpdateSurveyQuestionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	questionID, _ := uuid.FromString(params.SurveyQuestionID.String())
+	question, err := models.FetchSurveyQuestion(h.DB(), questionID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	setSurveyQuestionFields(question, params.SurveyQuestion)
+	verrs, err := h.DB().ValidateAndUpdate(question)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("Survey question updated",
+		zap.String("survey_question_id", question.ID.String()),
+		zap.Bool("active", question.Active),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return surveyop.NewUpdateSurveyQuestionOK().WithPayload(payloadForSurveyQuestionModel(*question))
+}
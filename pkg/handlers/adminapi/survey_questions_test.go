@@ -0,0 +1,75 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	surveyop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/survey_questions"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestSurveyQuestionHandlers() {
+	admin := suite.makeAdmin()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/survey_questions", nil), admin)
+	createResponse := CreateSurveyQuestionHandler{context}.Handle(surveyop.CreateSurveyQuestionParams{
+		HTTPRequest: req,
+		SurveyQuestion: &adminmessages.SurveyQuestionPayload{
+			Prompt: swag.String("How well did your mover communicate with you?"),
+			Kind:   swag.String("RATING"),
+			Active: swag.Bool(true),
+		},
+	})
+	suite.Assertions.IsType(&surveyop.CreateSurveyQuestionCreated{}, createResponse)
+	created := createResponse.(*surveyop.CreateSurveyQuestionCreated).Payload
+
+	updateReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("PUT", "/survey_questions/id", nil), admin)
+	created.Active = swag.Bool(false)
+	updateResponse := UpdateSurveyQuestionHandler{context}.Handle(surveyop.UpdateSurveyQuestionParams{
+		HTTPRequest:      updateReq,
+		SurveyQuestionID: created.ID,
+		SurveyQuestion:   created,
+	})
+	suite.Assertions.IsType(&surveyop.UpdateSurveyQuestionOK{}, updateResponse)
+
+	// Deactivated questions are listed but no longer asked
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/survey_questions", nil), admin)
+	indexResponse := IndexSurveyQuestionsHandler{context}.Handle(surveyop.IndexSurveyQuestionsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&surveyop.IndexSurveyQuestionsOK{}, indexResponse)
+	payload := indexResponse.(*surveyop.IndexSurveyQuestionsOK).Payload
+	suite.Len(payload, 1)
+	suite.False(*payload[0].Active)
+	active, err := models.FetchSurveyQuestions(suite.TestDB(), true)
+	suite.NoError(err)
+	suite.Empty(active)
+
+	// Office users who aren't admins can't change the survey
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	req = suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/survey_questions", nil), officeUser)
+	createResponse = CreateSurveyQuestionHandler{context}.Handle(surveyop.CreateSurveyQuestionParams{
+		HTTPRequest:    req,
+		SurveyQuestion: created,
+	})
+	suite.CheckResponseForbidden(createResponse)
+}
+
+func (suite *HandlerSuite) TestCreateSurveyQuestionHandlerRejectsUnknownKind() {
+	admin := suite.makeAdmin()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/survey_questions", nil), admin)
+	response := CreateSurveyQuestionHandler{context}.Handle(surveyop.CreateSurveyQuestionParams{
+		HTTPRequest: req,
+		SurveyQuestion: &adminmessages.SurveyQuestionPayload{
+			Prompt: swag.String("Would you move with them again?"),
+			Kind:   swag.String("YES_NO"),
+			Active: swag.Bool(true),
+		},
+	})
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+}
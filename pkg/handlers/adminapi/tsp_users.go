@@ -0,0 +1,170 @@
+package adminapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	tspuserop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/tsp_users"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForTspUserModel(tspUser models.TspUser) *adminmessages.TspUserPayload {
+	return &adminmessages.TspUserPayload{
+		ID:                              handlers.FmtUUID(tspUser.ID),
+		UserID:                          handlers.FmtUUIDPtr(tspUser.UserID),
+		FirstName:                       handlers.FmtString(tspUser.FirstName),
+		MiddleInitials:                  tspUser.MiddleInitials,
+		LastName:                        handlers.FmtString(tspUser.LastName),
+		Email:                           handlers.FmtEmail(tspUser.Email),
+		Telephone:                       handlers.FmtString(tspUser.Telephone),
+		TransportationServiceProviderID: handlers.FmtUUID(tspUser.TransportationServiceProviderID),
+		Deactivated:                     handlers.FmtBool(tspUser.Deactivated),
+		CreatedAt:                       handlers.FmtDateTime(tspUser.CreatedAt),
+		UpdatedAt:                       handlers.FmtDateTime(tspUser.UpdatedAt),
+	}
+}
+
+// fetchTspForAdministration loads the Transportation Service Provider in the path
+func fetchTspForAdministration(h handlers.HandlerContext, tspID strfmt.UUID) (*models.TransportationServiceProvider, error) {
+	id, err := uuid.FromString(tspID.String())
+	if err != nil {
+		return nil, err
+	}
+	return models.FetchTransportationServiceProvider(h.DB(), id)
+}
+
+// fetchTspUserForAdministration loads the TSP user in the path
+func fetchTspUserForAdministration(h handlers.HandlerContext, tspUserID strfmt.UUID) (*models.TspUser, error) {
+	id, err := uuid.FromString(tspUserID.String())
+	if err != nil {
+		return nil, err
+	}
+	return models.FetchTspUserByID(h.DB(), id)
+}
+
+// IndexTspUsersHandler lists a TSP's users
+type IndexTspUsersHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the users of a Transportation Service Provider
+func (h IndexTspUsersHandler) Handle(params tspuserop.IndexTspUsersParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	tsp, err := fetchTspForAdministration(h.HandlerContext, params.TspID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	tspUsers, err := models.FetchTspUsersForTSP(h.DB(), tsp.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := adminmessages.IndexTspUsersPayload{}
+	for _, tspUser := range tspUsers {
+		payload = append(payload, payloadForTspUserModel(tspUser))
+	}
+	return tspuserop.NewIndexTspUsersOK().WithPayload(payload)
+}
+
+// CreateTspUserHandler invites a TSP user
+type CreateTspUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle creates a TSP user who can log in once login.gov authenticates their email
+func (h CreateTspUserHandler) Handle(params tspuserop.CreateTspUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	tsp, err := fetchTspForAdministration(h.HandlerContext, params.TspID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.TspUser
+	tspUser := models.TspUser{
+		FirstName:                       *payload.FirstName,
+		MiddleInitials:                  payload.MiddleInitials,
+		LastName:                        *payload.LastName,
+		Email:                           payload.Email.String(),
+		Telephone:                       *payload.Telephone,
+		TransportationServiceProviderID: tsp.ID,
+	}
+	verrs, err := models.InviteTspUser(h.DB(), session, &tspUser)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("TSP user invited",
+		zap.String("invited_tsp_user_id", tspUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return tspuserop.NewCreateTspUserCreated().WithPayload(payloadForTspUserModel(tspUser))
+}
+
+// DeactivateTspUserHandler deactivates a TSP user
+type DeactivateTspUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deactivates a TSP user so they can no longer log in
+func (h DeactivateTspUserHandler) Handle(params tspuserop.DeactivateTspUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	tspUser, err := fetchTspUserForAdministration(h.HandlerContext, params.TspUserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.SetTspUserDeactivated(h.DB(), session, tspUser, true)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("TSP user deactivated",
+		zap.String("deactivated_tsp_user_id", tspUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return tspuserop.NewDeactivateTspUserOK().WithPayload(payloadForTspUserModel(*tspUser))
+}
+
+// ReactivateTspUserHandler reactivates a TSP user
+type ReactivateTspUserHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle reactivates a TSP user so they can log in again
+func (h ReactivateTspUserHandler) Handle(params tspuserop.ReactivateTspUserParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if err := models.AuthorizeAdministration(h.DB(), session); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	tspUser, err := fetchTspUserForAdministration(h.HandlerContext, params.TspUserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.SetTspUserDeactivated(h.DB(), session, tspUser, false)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("TSP user reactivated",
+		zap.String("reactivated_tsp_user_id", tspUser.ID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()))
+	return tspuserop.NewReactivateTspUserOK().WithPayload(payloadForTspUserModel(*tspUser))
+}
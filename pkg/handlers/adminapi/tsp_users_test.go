@@ -0,0 +1,55 @@
+package adminapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	tspuserop "github.com/transcom/mymove/pkg/gen/adminapi/adminoperations/tsp_users"
+	"github.com/transcom/mymove/pkg/gen/adminmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestTspUserHandlers() {
+	admin := suite.makeAdmin()
+	tsp := testdatagen.MakeDefaultTSP(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	createReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/transportation_service_providers/id/tsp_users", nil), admin)
+	createResponse := CreateTspUserHandler{context}.Handle(tspuserop.CreateTspUserParams{
+		HTTPRequest: createReq,
+		TspID:       *handlers.FmtUUID(tsp.ID),
+		TspUser: &adminmessages.CreateTspUserPayload{
+			FirstName: swag.String("Ivan"),
+			LastName:  swag.String("Invitee"),
+			Email:     handlers.FmtEmail("ivan.invitee@example.com"),
+			Telephone: swag.String("415-555-1212"),
+		},
+	})
+	suite.Assertions.IsType(&tspuserop.CreateTspUserCreated{}, createResponse)
+	created := createResponse.(*tspuserop.CreateTspUserCreated).Payload
+
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/transportation_service_providers/id/tsp_users", nil), admin)
+	indexResponse := IndexTspUsersHandler{context}.Handle(tspuserop.IndexTspUsersParams{
+		HTTPRequest: indexReq,
+		TspID:       *handlers.FmtUUID(tsp.ID),
+	})
+	suite.Assertions.IsType(&tspuserop.IndexTspUsersOK{}, indexResponse)
+	suite.Len(indexResponse.(*tspuserop.IndexTspUsersOK).Payload, 1)
+
+	deactivateReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/tsp_users/id/deactivate", nil), admin)
+	deactivateResponse := DeactivateTspUserHandler{context}.Handle(tspuserop.DeactivateTspUserParams{
+		HTTPRequest: deactivateReq,
+		TspUserID:   *created.ID,
+	})
+	suite.Assertions.IsType(&tspuserop.DeactivateTspUserOK{}, deactivateResponse)
+	suite.True(*deactivateResponse.(*tspuserop.DeactivateTspUserOK).Payload.Deactivated)
+
+	// Unknown TSP users are not found
+	missingResponse := ReactivateTspUserHandler{context}.Handle(tspuserop.ReactivateTspUserParams{
+		HTTPRequest: deactivateReq,
+		TspUserID:   *handlers.FmtUUID(tsp.ID),
+	})
+	suite.CheckResponseNotFound(missingResponse)
+}
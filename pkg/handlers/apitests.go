@@ -125,6 +125,11 @@ func (suite *BaseTestSuite) CheckResponseNotFound(resp middleware.Responder) {
 	suite.CheckErrorResponse(resp, http.StatusNotFound, "NotFound")
 }
 
+// CheckResponseConflict looks at Conflict errors
+func (suite *BaseTestSuite) CheckResponseConflict(resp middleware.Responder) {
+	suite.CheckErrorResponse(resp, http.StatusConflict, "Conflict")
+}
+
 // CheckResponseInternalServerError looks at InternalServerError errors
 func (suite *BaseTestSuite) CheckResponseInternalServerError(resp middleware.Responder) {
 	suite.CheckErrorResponse(resp, http.StatusInternalServerError, "InternalServerError")
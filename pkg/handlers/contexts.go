@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"github.com/gobuffalo/pop"
+	"github.com/transcom/mymove/pkg/dates"
 	"github.com/transcom/mymove/pkg/dpsauth"
 	"github.com/transcom/mymove/pkg/iws"
 	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/storage"
@@ -26,33 +28,37 @@ type HandlerContext interface {
 	SetCookieSecret(secret string)
 	NoSessionTimeout() bool
 	SetNoSessionTimeout()
-	IWSRealTimeBrokerService() iws.RealTimeBrokerService
-	SetIWSRealTimeBrokerService(rbs iws.RealTimeBrokerService)
+	IWSPersonLookup() iws.PersonLookup
+	SetIWSPersonLookup(rbs iws.PersonLookup)
 	SendProductionInvoice() bool
 	SetSendProductionInvoice(sendProductionInvoice bool)
 	DPSAuthParams() dpsauth.Params
 	SetDPSAuthParams(params dpsauth.Params)
+	Calendars() *dates.CalendarCache
+	SetCalendars(calendars *dates.CalendarCache)
 }
 
 // A single handlerContext is passed to each handler
 type handlerContext struct {
-	db                       *pop.Connection
-	logger                   *zap.Logger
-	cookieSecret             string
-	noSessionTimeout         bool
-	planner                  route.Planner
-	storage                  storage.FileStorer
-	notificationSender       notifications.NotificationSender
-	iwsRealTimeBrokerService iws.RealTimeBrokerService
-	sendProductionInvoice    bool
-	dpsAuthParams            dpsauth.Params
+	db                    *pop.Connection
+	logger                *zap.Logger
+	cookieSecret          string
+	noSessionTimeout      bool
+	planner               route.Planner
+	storage               storage.FileStorer
+	notificationSender    notifications.NotificationSender
+	iwsPersonLookup       iws.PersonLookup
+	sendProductionInvoice bool
+	dpsAuthParams         dpsauth.Params
+	calendars             *dates.CalendarCache
 }
 
 // NewHandlerContext returns a new handlerContext with its required private fields set.
 func NewHandlerContext(db *pop.Connection, logger *zap.Logger) HandlerContext {
 	return &handlerContext{
-		db:     db,
-		logger: logger,
+		db:        db,
+		logger:    logger,
+		calendars: models.NewCalendarCache(db),
 	}
 }
 
@@ -121,12 +127,14 @@ func (context *handlerContext) SetNoSessionTimeout() {
 	context.noSessionTimeout = true
 }
 
-func (context *handlerContext) IWSRealTimeBrokerService() iws.RealTimeBrokerService {
-	return context.iwsRealTimeBrokerService
+// IWSPersonLookup returns the client used to query IWS
+func (context *handlerContext) IWSPersonLookup() iws.PersonLookup {
+	return context.iwsPersonLookup
 }
 
-func (context *handlerContext) SetIWSRealTimeBrokerService(rbs iws.RealTimeBrokerService) {
-	context.iwsRealTimeBrokerService = rbs
+// SetIWSPersonLookup is a simple setter for the iwsPersonLookup private field
+func (context *handlerContext) SetIWSPersonLookup(rbs iws.PersonLookup) {
+	context.iwsPersonLookup = rbs
 }
 
 // InvoiceIsATest is a flag to notify EDI invoice generation whether it should be sent as a test transaction
@@ -146,3 +154,13 @@ func (context *handlerContext) DPSAuthParams() dpsauth.Params {
 func (context *handlerContext) SetDPSAuthParams(params dpsauth.Params) {
 	context.dpsAuthParams = params
 }
+
+// Calendars returns the business-day calendars of duty stations, transportation offices and TSPs
+func (context *handlerContext) Calendars() *dates.CalendarCache {
+	return context.calendars
+}
+
+// SetCalendars is a simple setter for the calendars private field
+func (context *handlerContext) SetCalendars(calendars *dates.CalendarCache) {
+	context.calendars = calendars
+}
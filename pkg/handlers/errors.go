@@ -74,6 +74,12 @@ func ResponseForError(logger *zap.Logger, err error) middleware.Responder {
 	case models.ErrInvalidTransition:
 		skipLogger.Debug("invalid transition", zap.Error(err))
 		return newErrResponse(http.StatusBadRequest, err)
+	case models.ErrInvalidQueueParams:
+		skipLogger.Debug("invalid queue params", zap.Error(err))
+		return newErrResponse(http.StatusBadRequest, err)
+	case models.ErrWorkClaimed:
+		skipLogger.Debug("work claimed by another office user", zap.Error(err))
+		return newErrResponse(http.StatusConflict, err)
 	default:
 		skipLogger.Error("unexpected error", zap.Error(err))
 		return newErrResponse(http.StatusInternalServerError, err)
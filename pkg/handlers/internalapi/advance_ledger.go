@@ -0,0 +1,70 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	moveop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/moves"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForAdvanceLedgerEntry(entry models.AdvanceLedgerEntry) *internalmessages.AdvanceLedgerEntry {
+	status := internalmessages.ReimbursementStatus(entry.Status)
+	methodOfReceipt := internalmessages.MethodOfReceipt(entry.MethodOfReceipt)
+	payload := internalmessages.AdvanceLedgerEntry{
+		PersonallyProcuredMoveID: handlers.FmtUUID(entry.PersonallyProcuredMoveID),
+		ReimbursementID:          handlers.FmtUUID(entry.ReimbursementID),
+		Status:                   &status,
+		MethodOfReceipt:          &methodOfReceipt,
+		RequestedAmount:          swag.Int64(entry.Requested.Int64()),
+		ApprovedAmount:           swag.Int64(entry.Approved.Int64()),
+		DisbursedAmount:          swag.Int64(entry.Disbursed.Int64()),
+		DisbursedDate:            (*strfmt.Date)(entry.DisbursedDate),
+		DeductedAmount:           swag.Int64(entry.Deducted.Int64()),
+		RecoupmentOwed:           swag.Int64(entry.RecoupmentOwed.Int64()),
+	}
+	if entry.Incentive != nil {
+		payload.IncentiveActual = swag.Int64(entry.Incentive.Int64())
+	}
+	return &payload
+}
+
+func payloadForAdvanceLedger(ledger models.AdvanceLedger) *internalmessages.AdvanceLedgerPayload {
+	entries := make([]*internalmessages.AdvanceLedgerEntry, len(ledger.Entries))
+	for i, entry := range ledger.Entries {
+		entries[i] = payloadForAdvanceLedgerEntry(entry)
+	}
+	return &internalmessages.AdvanceLedgerPayload{
+		MoveID:              handlers.FmtUUID(ledger.MoveID),
+		Entries:             entries,
+		TotalRequested:      swag.Int64(ledger.TotalRequested.Int64()),
+		TotalApproved:       swag.Int64(ledger.TotalApproved.Int64()),
+		TotalDisbursed:      swag.Int64(ledger.TotalDisbursed.Int64()),
+		TotalDeducted:       swag.Int64(ledger.TotalDeducted.Int64()),
+		TotalRecoupmentOwed: swag.Int64(ledger.TotalRecoupmentOwed.Int64()),
+	}
+}
+
+// ShowAdvanceLedgerHandler returns the advance ledger of a move
+type ShowAdvanceLedgerHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the requested, approved and disbursed advances of a move and their settlement
+func (h ShowAdvanceLedgerHandler) Handle(params moveop.ShowAdvanceLedgerParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+
+	/* #nosec UUID is pattern matched by swagger which checks the format */
+	moveID, _ := uuid.FromString(params.MoveID.String())
+
+	ledger, err := models.FetchAdvanceLedger(h.DB(), session, moveID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return moveop.NewShowAdvanceLedgerOK().WithPayload(payloadForAdvanceLedger(*ledger))
+}
@@ -20,6 +20,10 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI := internalops.NewMymoveAPI(internalSpec)
 
 	internalAPI.UsersShowLoggedInUserHandler = ShowLoggedInUserHandler{context}
+	internalAPI.UsersIndexNotificationPreferencesHandler = IndexNotificationPreferencesHandler{context}
+	internalAPI.UsersUpdateNotificationPreferenceHandler = UpdateNotificationPreferenceHandler{context}
+	internalAPI.UsersIndexInboxNotificationsHandler = IndexInboxNotificationsHandler{context}
+	internalAPI.UsersMarkInboxNotificationReadHandler = MarkInboxNotificationReadHandler{context}
 
 	internalAPI.IssuesCreateIssueHandler = CreateIssueHandler{context}
 	internalAPI.IssuesIndexIssuesHandler = IndexIssuesHandler{context}
@@ -35,6 +39,18 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI.PpmRequestPPMPaymentHandler = RequestPPMPaymentHandler{context}
 	internalAPI.PpmCreatePPMAttachmentsHandler = CreatePersonallyProcuredMoveAttachmentsHandler{context}
 	internalAPI.PpmRequestPPMExpenseSummaryHandler = RequestPPMExpenseSummaryHandler{context}
+	internalAPI.PpmIndexPPMTripsHandler = IndexPPMTripsHandler{context}
+	internalAPI.PpmCreatePPMTripHandler = CreatePPMTripHandler{context}
+	internalAPI.PpmUpdatePPMTripHandler = UpdatePPMTripHandler{context}
+	internalAPI.PpmDeletePPMTripHandler = DeletePPMTripHandler{context}
+
+	internalAPI.ClaimsIndexClaimsHandler = IndexClaimsHandler{context}
+	internalAPI.ClaimsCreateClaimHandler = CreateClaimHandler{context}
+	internalAPI.ClaimsAcceptClaimSettlementHandler = AcceptClaimSettlementHandler{context}
+	internalAPI.ClaimsTransferClaimHandler = TransferClaimHandler{context}
+
+	internalAPI.PremoveSurveysIndexPremoveSurveySlotsHandler = IndexPremoveSurveySlotsHandler{context}
+	internalAPI.PremoveSurveysConfirmPremoveSurveySlotHandler = ConfirmPremoveSurveySlotHandler{context}
 
 	internalAPI.DutyStationsSearchDutyStationsHandler = SearchDutyStationsHandler{context}
 
@@ -49,12 +65,14 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI.MovesShowMoveHandler = ShowMoveHandler{context}
 	internalAPI.MovesSubmitMoveForApprovalHandler = SubmitMoveHandler{context}
 	internalAPI.MovesShowMoveDatesSummaryHandler = ShowMoveDatesSummaryHandler{context}
+	internalAPI.MovesShowAdvanceLedgerHandler = ShowAdvanceLedgerHandler{context}
 
 	internalAPI.MoveDocsCreateGenericMoveDocumentHandler = CreateGenericMoveDocumentHandler{context}
 	internalAPI.MoveDocsUpdateMoveDocumentHandler = UpdateMoveDocumentHandler{context}
 	internalAPI.MoveDocsIndexMoveDocumentsHandler = IndexMoveDocumentsHandler{context}
 
 	internalAPI.MoveDocsCreateMovingExpenseDocumentHandler = CreateMovingExpenseDocumentHandler{context}
+	internalAPI.MoveDocsCreateWeightTicketDocumentHandler = CreateWeightTicketDocumentHandler{context}
 
 	internalAPI.ServiceMembersCreateServiceMemberHandler = CreateServiceMemberHandler{context}
 	internalAPI.ServiceMembersPatchServiceMemberHandler = PatchServiceMemberHandler{context}
@@ -73,6 +91,14 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 	internalAPI.UploadsDeleteUploadsHandler = DeleteUploadsHandler{context}
 
 	internalAPI.QueuesShowQueueHandler = ShowQueueHandler{context}
+	internalAPI.QueuesSearchQueueHandler = SearchQueueHandler{context}
+	internalAPI.QueuesIndexQueueViewsHandler = IndexQueueViewsHandler{context}
+	internalAPI.QueuesCreateQueueViewHandler = CreateQueueViewHandler{context}
+	internalAPI.QueuesUpdateQueueViewHandler = UpdateQueueViewHandler{context}
+	internalAPI.QueuesDeleteQueueViewHandler = DeleteQueueViewHandler{context}
+	internalAPI.QueuesIndexWorkClaimsHandler = IndexWorkClaimsHandler{context}
+	internalAPI.QueuesClaimWorkHandler = ClaimWorkHandler{context}
+	internalAPI.QueuesReleaseWorkHandler = ReleaseWorkHandler{context}
 
 	internalAPI.ShipmentsCreateShipmentHandler = CreateShipmentHandler{context}
 	internalAPI.ShipmentsPatchShipmentHandler = PatchShipmentHandler{context}
@@ -83,8 +109,11 @@ func NewInternalAPIHandler(context handlers.HandlerContext) http.Handler {
 
 	internalAPI.OfficeApproveMoveHandler = ApproveMoveHandler{context}
 	internalAPI.OfficeApprovePPMHandler = ApprovePPMHandler{context}
+	internalAPI.OfficeApprovePPMPaymentHandler = ApprovePPMPaymentHandler{context}
 	internalAPI.OfficeApproveReimbursementHandler = ApproveReimbursementHandler{context}
+	internalAPI.OfficeDisburseReimbursementHandler = DisburseReimbursementHandler{context}
 	internalAPI.OfficeCancelMoveHandler = CancelMoveHandler{context}
+	internalAPI.OfficeShowMoveTimelineHandler = ShowMoveTimelineHandler{context}
 
 	internalAPI.EntitlementsValidateEntitlementHandler = ValidateEntitlementHandler{context}
 
@@ -0,0 +1,79 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForAuditRecordModel(record models.AuditRecord) (*internalmessages.AuditRecordPayload, error) {
+	changeSet, err := record.ChangeSet()
+	if err != nil {
+		return nil, err
+	}
+	changes := map[string]internalmessages.AuditRecordChange{}
+	for field, change := range changeSet {
+		changes[field] = internalmessages.AuditRecordChange{
+			Before: change.Before,
+			After:  change.After,
+		}
+	}
+
+	var moveID strfmt.UUID
+	if record.MoveID != nil {
+		moveID = *handlers.FmtUUID(*record.MoveID)
+	}
+
+	return &internalmessages.AuditRecordPayload{
+		ID:          handlers.FmtUUID(record.ID),
+		MoveID:      moveID,
+		RecordType:  swag.String(record.RecordType),
+		RecordID:    handlers.FmtUUID(record.RecordID),
+		Event:       swag.String(record.Event),
+		FromStatus:  record.FromStatus,
+		ToStatus:    record.ToStatus,
+		Changes:     changes,
+		Reason:      record.Reason,
+		ActorName:   swag.String(record.ActorName),
+		SessionType: swag.String(string(record.SessionType)),
+		CreatedAt:   handlers.FmtDateTime(record.CreatedAt),
+	}, nil
+}
+
+// ShowMoveTimelineHandler returns the audit records of a move via GET /moves/{moveId}/timeline
+type ShowMoveTimelineHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the timeline of changes to a move
+func (h ShowMoveTimelineHandler) Handle(params officeop.ShowMoveTimelineParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return officeop.NewShowMoveTimelineForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	moveID, _ := uuid.FromString(params.MoveID.String())
+
+	records, err := models.FetchAuditRecordsForMove(h.DB(), session, moveID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	timeline := internalmessages.MoveTimeline{}
+	for _, record := range records {
+		payload, err := payloadForAuditRecordModel(record)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		timeline = append(timeline, payload)
+	}
+	return officeop.NewShowMoveTimelineOK().WithPayload(timeline)
+}
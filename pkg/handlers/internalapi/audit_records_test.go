@@ -0,0 +1,65 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestShowMoveTimelineHandler() {
+	// Given: a move that was submitted and approved by an office user
+	move := testdatagen.MakeDefaultMove(suite.TestDB())
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+
+	req := httptest.NewRequest("GET", "/moves/some_id/timeline", nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+
+	before := move
+	suite.Nil(move.Submit())
+	suite.MustSave(&move)
+	_, err := models.RecordAudit(suite.TestDB(), nil, &before, &move, "submit", "")
+	suite.Nil(err)
+
+	params := officeop.ShowMoveTimelineParams{
+		HTTPRequest: req,
+		MoveID:      strfmt.UUID(move.ID.String()),
+	}
+
+	// When: the timeline is requested
+	handler := ShowMoveTimelineHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	// Then: the submission is on the timeline
+	suite.Assertions.IsType(&officeop.ShowMoveTimelineOK{}, response)
+	okResponse := response.(*officeop.ShowMoveTimelineOK)
+	suite.Len(okResponse.Payload, 1)
+	suite.Equal("submit", *okResponse.Payload[0].Event)
+	suite.Equal(string(models.MoveStatusDRAFT), *okResponse.Payload[0].FromStatus)
+	suite.Equal(string(models.MoveStatusSUBMITTED), *okResponse.Payload[0].ToStatus)
+	suite.Equal(string(models.AuditSessionTypeSystem), *okResponse.Payload[0].SessionType)
+}
+
+func (suite *HandlerSuite) TestShowMoveTimelineHandlerForbidden() {
+	// Given: a move and a service member
+	move := testdatagen.MakeDefaultMove(suite.TestDB())
+
+	req := httptest.NewRequest("GET", "/moves/some_id/timeline", nil)
+	req = suite.AuthenticateRequest(req, move.Orders.ServiceMember)
+
+	params := officeop.ShowMoveTimelineParams{
+		HTTPRequest: req,
+		MoveID:      strfmt.UUID(move.ID.String()),
+	}
+
+	// When: the service member requests the timeline
+	handler := ShowMoveTimelineHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	// Then: expect a 403 status code
+	suite.Assertions.IsType(&officeop.ShowMoveTimelineForbidden{}, response)
+}
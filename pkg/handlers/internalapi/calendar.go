@@ -3,10 +3,14 @@ package internalapi
 import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/dates"
 	calendarop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/calendar"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
 	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
 	"time"
 )
 
@@ -15,8 +19,26 @@ type ShowAvailableMoveDatesHandler struct {
 	handlers.HandlerContext
 }
 
-// Handle returns the available move dates.
+// Handle returns the available move dates. Service members get the dates their duty station and its
+// transportation office are open on. Given a shipment, dates on which the TSPs of its TDL are booked up
+// are left out.
 func (h ShowAvailableMoveDatesHandler) Handle(params calendarop.ShowAvailableMoveDatesParams) middleware.Responder {
+	scope := dates.CalendarScope{}
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if session != nil && session.IsServiceMember() {
+		serviceMember, err := models.FetchServiceMemberForUser(h.DB(), session, session.ServiceMemberID)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		if serviceMember.DutyStationID != nil {
+			scope = models.DutyStationCalendarScope(serviceMember.DutyStation)
+		}
+	}
+	calendar, err := h.Calendars().Calendar(scope)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
 	startDate := time.Time(params.StartDate)
 
 	var availableMoveDatesPayload internalmessages.AvailableMoveDates
@@ -28,19 +50,49 @@ func (h ShowAvailableMoveDatesHandler) Handle(params calendarop.ShowAvailableMov
 	daysChecked := 0
 	shortFuseDaysFound := 0
 
-	usCalendar := dates.NewUSCalendar()
 	firstPossibleDate := startDate.AddDate(0, 0, 1) // We never include the start date.
+
+	capacityByDate := map[string]models.TDLDayCapacity{}
+	if params.ShipmentID != nil {
+		if session == nil {
+			return calendarop.NewShowAvailableMoveDatesUnauthorized()
+		}
+		shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+		shipment, err := models.FetchShipment(h.DB(), session, shipmentID)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		if shipment.TrafficDistributionListID != nil {
+			lastPossibleDate := firstPossibleDate.AddDate(0, 0, daysToCheckAfterStartDate-1)
+			days, err := models.FetchTDLCapacity(h.DB(), *shipment.TrafficDistributionListID, firstPossibleDate, lastPossibleDate)
+			if err != nil {
+				return handlers.ResponseForError(h.Logger(), err)
+			}
+			for _, day := range days {
+				capacityByDate[day.Date.Format("2006-01-02")] = day
+			}
+		}
+	}
+
+	var capacityPayload []*internalmessages.MoveDateCapacity
 	for d := firstPossibleDate; daysChecked < daysToCheckAfterStartDate; d = d.AddDate(0, 0, 1) {
-		if usCalendar.IsWorkday(d) {
+		if calendar.IsWorkday(d) {
 			if shortFuseDaysFound < shortFuseTotalDays {
 				shortFuseDaysFound++
-			} else {
+			} else if capacity := capacityByDate[d.Format("2006-01-02")]; !capacity.IsFull() {
 				datesPayload = append(datesPayload, strfmt.Date(d))
+				if capacity.IsDeclared() {
+					capacityPayload = append(capacityPayload, &internalmessages.MoveDateCapacity{
+						Date:      handlers.FmtDate(d),
+						Remaining: swag.Int64(int64(capacity.Remaining())),
+					})
+				}
 			}
 		}
 		daysChecked++
 	}
 	availableMoveDatesPayload.Available = datesPayload
+	availableMoveDatesPayload.Capacity = capacityPayload
 
 	return calendarop.NewShowAvailableMoveDatesOK().WithPayload(&availableMoveDatesPayload)
 }
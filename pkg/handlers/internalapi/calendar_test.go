@@ -4,6 +4,8 @@ import (
 	"github.com/go-openapi/strfmt"
 	calendarop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/calendar"
 	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
 	"net/http/httptest"
 	"time"
 )
@@ -84,3 +86,80 @@ func (suite *HandlerSuite) TestShowAvailableMoveDatesHandler() {
 	suite.Equal(startDate, *okResponse.Payload.StartDate)
 	suite.Equal(availableDates, okResponse.Payload.Available)
 }
+
+func (suite *HandlerSuite) TestShowAvailableMoveDatesHandlerDutyStationClosure() {
+	sm := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	trainingHoliday := time.Date(2018, 10, 10, 0, 0, 0, 0, time.UTC)
+	suite.MustSave(&models.CalendarClosure{
+		DutyStationID: sm.DutyStationID,
+		ClosureDate:   trainingHoliday,
+		Reason:        "Training holiday",
+	})
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("GET", "/calendar/available_move_dates", nil), sm)
+	params := calendarop.ShowAvailableMoveDatesParams{
+		HTTPRequest: req,
+		StartDate:   strfmt.Date(time.Date(2018, 9, 27, 0, 0, 0, 0, time.UTC)),
+	}
+
+	showHandler := ShowAvailableMoveDatesHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := showHandler.Handle(params)
+
+	suite.IsType(&calendarop.ShowAvailableMoveDatesOK{}, response)
+	available := response.(*calendarop.ShowAvailableMoveDatesOK).Payload.Available
+	// The day after Columbus Day is followed by the closure
+	suite.Equal([]strfmt.Date{
+		strfmt.Date(time.Date(2018, 10, 5, 0, 0, 0, 0, time.UTC)),
+		strfmt.Date(time.Date(2018, 10, 9, 0, 0, 0, 0, time.UTC)),
+		strfmt.Date(time.Date(2018, 10, 11, 0, 0, 0, 0, time.UTC)),
+	}, available[:3])
+}
+
+func (suite *HandlerSuite) TestShowAvailableMoveDatesHandlerTDLCapacity() {
+	shipment := testdatagen.MakeDefaultShipment(suite.TestDB())
+	tdlID := *shipment.TrafficDistributionListID
+
+	// The TSPs of the TDL can take one pickup on Monday, which is booked, and two on Tuesday
+	monday := time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	testdatagen.MakeShipment(suite.TestDB(), testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status:              models.ShipmentStatusACCEPTED,
+			RequestedPickupDate: &monday,
+		},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.TestDB())
+	suite.MustSave(&models.TSPCapacity{
+		TransportationServiceProviderID: tsp.ID,
+		TrafficDistributionListID:       tdlID,
+		StartDate:                       monday,
+		EndDate:                         monday,
+		DailyShipments:                  1,
+	})
+	suite.MustSave(&models.TSPCapacity{
+		TransportationServiceProviderID: tsp.ID,
+		TrafficDistributionListID:       tdlID,
+		StartDate:                       tuesday,
+		EndDate:                         tuesday,
+		DailyShipments:                  2,
+	})
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("GET", "/calendar/available_move_dates", nil), shipment.ServiceMember)
+	shipmentID := strfmt.UUID(shipment.ID.String())
+	params := calendarop.ShowAvailableMoveDatesParams{
+		HTTPRequest: req,
+		// The short fuse days run through the Friday after Memorial Day
+		StartDate:  strfmt.Date(time.Date(2019, 5, 23, 0, 0, 0, 0, time.UTC)),
+		ShipmentID: &shipmentID,
+	}
+
+	showHandler := ShowAvailableMoveDatesHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := showHandler.Handle(params)
+
+	suite.IsType(&calendarop.ShowAvailableMoveDatesOK{}, response)
+	payload := response.(*calendarop.ShowAvailableMoveDatesOK).Payload
+	suite.Equal(strfmt.Date(tuesday), payload.Available[0])
+	suite.Len(payload.Capacity, 1)
+	suite.Equal(strfmt.Date(tuesday), *payload.Capacity[0].Date)
+	suite.Equal(int64(2), *payload.Capacity[0].Remaining)
+}
@@ -0,0 +1,200 @@
+package internalapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	claimop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/claims"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/storage"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func payloadForClaimModel(storer storage.FileStorer, claim models.Claim) (*internalmessages.ClaimPayload, error) {
+	documentPayload, err := payloadForDocumentModel(storer, claim.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*internalmessages.ClaimItemPayload, len(claim.Items))
+	for i, item := range claim.Items {
+		itemPayload := &internalmessages.ClaimItemPayload{
+			ID:            handlers.FmtUUID(item.ID),
+			Description:   swag.String(item.Description),
+			Condition:     internalmessages.ClaimItemCondition(item.Condition),
+			ClaimedAmount: swag.Int64(item.ClaimedAmount.Int64()),
+		}
+		if item.OfferedAmount != nil {
+			itemPayload.OfferedAmount = swag.Int64(item.OfferedAmount.Int64())
+		}
+		items[i] = itemPayload
+	}
+
+	claimPayload := &internalmessages.ClaimPayload{
+		ID:                 handlers.FmtUUID(claim.ID),
+		ShipmentID:         handlers.FmtUUID(claim.ShipmentID),
+		Status:             internalmessages.ClaimStatus(claim.Status),
+		Description:        swag.String(claim.Description),
+		ClaimDate:          handlers.FmtDateTime(claim.ClaimDate),
+		TspResponseDueDate: handlers.FmtDate(claim.TSPResponseDueDate),
+		TspResponse:        claim.TSPResponse,
+		ClaimedAmount:      swag.Int64(claim.ClaimedAmount().Int64()),
+		ResolvedAt:         handlers.FmtDateTimePtr(claim.ResolvedAt),
+		Document:           documentPayload,
+		Items:              items,
+	}
+	if claim.SettlementOfferAmount != nil {
+		claimPayload.SettlementOfferAmount = swag.Int64(claim.SettlementOfferAmount.Int64())
+	}
+	return claimPayload, nil
+}
+
+// IndexClaimsHandler returns the claims filed against a shipment
+type IndexClaimsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the claims if the user may access the shipment
+func (h IndexClaimsHandler) Handle(params claimop.IndexClaimsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+
+	shipment, err := models.FetchShipment(h.DB(), session, shipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	claims, err := models.FetchClaimsForShipment(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	claimPayloads := make(internalmessages.IndexClaimsPayload, len(claims))
+	for i, claim := range claims {
+		claimPayload, err := payloadForClaimModel(h.FileStorer(), claim)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		claimPayloads[i] = claimPayload
+	}
+	return claimop.NewIndexClaimsOK().WithPayload(claimPayloads)
+}
+
+// CreateClaimHandler files a service member's claim against a shipment
+type CreateClaimHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle files the claim with its items
+func (h CreateClaimHandler) Handle(params claimop.CreateClaimParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsServiceMember() {
+		return claimop.NewCreateClaimForbidden()
+	}
+	shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+
+	shipment, err := models.FetchShipment(h.DB(), session, shipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.CreateClaimPayload
+	claim := models.Claim{
+		Description: *payload.Description,
+		ClaimDate:   time.Now(),
+	}
+	for _, item := range payload.Items {
+		claim.Items = append(claim.Items, models.ClaimItem{
+			Description:   *item.Description,
+			Condition:     models.ClaimItemCondition(item.Condition),
+			ClaimedAmount: unit.Cents(*item.ClaimedAmount),
+		})
+	}
+
+	verrs, err := models.FileClaim(h.DB(), session, *shipment, &claim)
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewCreateClaimCreated().WithPayload(claimPayload)
+}
+
+// AcceptClaimSettlementHandler settles a claim for the amount the TSP offered
+type AcceptClaimSettlementHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle settles the claim
+func (h AcceptClaimSettlementHandler) Handle(params claimop.AcceptClaimSettlementParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsServiceMember() {
+		return claimop.NewAcceptClaimSettlementForbidden()
+	}
+	claimID, _ := uuid.FromString(params.ClaimID.String())
+
+	claim, err := models.FetchClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *claim
+	if err = claim.AcceptSettlement(time.Now()); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	audit := models.NewAuditEvent(session, &before, claim, "accept_settlement", "")
+	verrs, err := models.SaveClaim(h.DB(), claim, audit)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), *claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewAcceptClaimSettlementOK().WithPayload(claimPayload)
+}
+
+// TransferClaimHandler transfers a claim to the military claims office
+type TransferClaimHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle transfers the claim
+func (h TransferClaimHandler) Handle(params claimop.TransferClaimParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsServiceMember() {
+		return claimop.NewTransferClaimForbidden()
+	}
+	claimID, _ := uuid.FromString(params.ClaimID.String())
+
+	claim, err := models.FetchClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *claim
+	if err = claim.Transfer(time.Now()); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	audit := models.NewAuditEvent(session, &before, claim, "transfer", "")
+	verrs, err := models.SaveClaim(h.DB(), claim, audit)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), *claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewTransferClaimOK().WithPayload(claimPayload)
+}
@@ -0,0 +1,98 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	claimop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/claims"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestClaimHandlers() {
+	delivered := time.Now().AddDate(0, -1, 0)
+	shipment := testdatagen.MakeShipment(suite.TestDB(), testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status:             models.ShipmentStatusDELIVERED,
+			ActualDeliveryDate: &delivered,
+		},
+	})
+	sm := shipment.ServiceMember
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("POST", "/fake/path", nil), sm)
+	createResponse := CreateClaimHandler{context}.Handle(claimop.CreateClaimParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+		CreateClaimPayload: &internalmessages.CreateClaimPayload{
+			Description: swag.String("Movers dropped the clock"),
+			Items: []*internalmessages.CreateClaimItemPayload{
+				{
+					Description:   swag.String("Grandfather clock"),
+					Condition:     internalmessages.ClaimItemConditionDAMAGED,
+					ClaimedAmount: swag.Int64(50000),
+				},
+				{
+					Description:   swag.String("Box of books"),
+					Condition:     internalmessages.ClaimItemConditionLOST,
+					ClaimedAmount: swag.Int64(12000),
+				},
+			},
+		},
+	})
+	suite.Assertions.IsType(&claimop.CreateClaimCreated{}, createResponse)
+	claimPayload := createResponse.(*claimop.CreateClaimCreated).Payload
+	suite.Equal(internalmessages.ClaimStatusFILED, claimPayload.Status)
+	suite.Equal(int64(62000), *claimPayload.ClaimedAmount)
+	suite.Len(claimPayload.Items, 2)
+
+	// The TSP still has time to respond, so the claim can't be transferred yet
+	transferParams := claimop.TransferClaimParams{
+		HTTPRequest: req,
+		ClaimID:     *claimPayload.ID,
+	}
+	transferResponse := TransferClaimHandler{context}.Handle(transferParams)
+	suite.CheckResponseBadRequest(transferResponse)
+
+	// Someone else can't see the claims
+	otherSM := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	indexParams := claimop.IndexClaimsParams{
+		HTTPRequest: suite.AuthenticateRequest(httptest.NewRequest("GET", "/fake/path", nil), otherSM),
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+	}
+	suite.CheckResponseForbidden(IndexClaimsHandler{context}.Handle(indexParams))
+
+	indexParams.HTTPRequest = suite.AuthenticateRequest(httptest.NewRequest("GET", "/fake/path", nil), sm)
+	indexResponse := IndexClaimsHandler{context}.Handle(indexParams)
+	suite.Assertions.IsType(&claimop.IndexClaimsOK{}, indexResponse)
+	suite.Len(indexResponse.(*claimop.IndexClaimsOK).Payload, 1)
+}
+
+func (suite *HandlerSuite) TestCreateClaimHandlerUndeliveredShipment() {
+	shipment := testdatagen.MakeShipment(suite.TestDB(), testdatagen.Assertions{
+		Shipment: models.Shipment{Status: models.ShipmentStatusINTRANSIT},
+	})
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("POST", "/fake/path", nil), shipment.ServiceMember)
+	response := CreateClaimHandler{context}.Handle(claimop.CreateClaimParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+		CreateClaimPayload: &internalmessages.CreateClaimPayload{
+			Description: swag.String("Movers dropped the clock"),
+			Items: []*internalmessages.CreateClaimItemPayload{
+				{
+					Description:   swag.String("Grandfather clock"),
+					Condition:     internalmessages.ClaimItemConditionDAMAGED,
+					ClaimedAmount: swag.Int64(50000),
+				},
+			},
+		},
+	})
+	suite.CheckResponseBadRequest(response)
+}
@@ -0,0 +1,77 @@
+package internalapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	userop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/users"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForInboxNotificationModel(notification models.InboxNotification) *internalmessages.InboxNotificationPayload {
+	return &internalmessages.InboxNotificationPayload{
+		ID:        handlers.FmtUUID(notification.ID),
+		Event:     swag.String(notification.Event),
+		Subject:   swag.String(notification.Subject),
+		Body:      swag.String(notification.Body),
+		CreatedAt: handlers.FmtDateTime(notification.CreatedAt),
+		ReadAt:    handlers.FmtDateTimePtr(notification.ReadAt),
+	}
+}
+
+// IndexInboxNotificationsHandler returns the in-app inbox of the logged in user
+type IndexInboxNotificationsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the notifications in the user's inbox and how many are unread
+func (h IndexInboxNotificationsHandler) Handle(params userop.IndexInboxNotificationsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+
+	notifications, err := models.FetchInboxNotifications(h.DB(), session.UserID, swag.BoolValue(params.UnreadOnly))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	unreadCount, err := models.CountUnreadInboxNotifications(h.DB(), session.UserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := internalmessages.IndexInboxNotificationsPayload{
+		UnreadCount:   swag.Int64(int64(unreadCount)),
+		Notifications: []*internalmessages.InboxNotificationPayload{},
+	}
+	for _, notification := range notifications {
+		payload.Notifications = append(payload.Notifications, payloadForInboxNotificationModel(notification))
+	}
+	return userop.NewIndexInboxNotificationsOK().WithPayload(&payload)
+}
+
+// MarkInboxNotificationReadHandler marks a notification in the logged in user's inbox as read
+type MarkInboxNotificationReadHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle marks the notification as read
+func (h MarkInboxNotificationReadHandler) Handle(params userop.MarkInboxNotificationReadParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	notificationID, _ := uuid.FromString(params.InboxNotificationID.String())
+
+	notification, err := models.FetchInboxNotificationForUser(h.DB(), session, notificationID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	notification.MarkRead(time.Now())
+	verrs, err := h.DB().ValidateAndUpdate(notification)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return userop.NewMarkInboxNotificationReadOK().WithPayload(payloadForInboxNotificationModel(*notification))
+}
@@ -0,0 +1,56 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	userop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/users"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestInboxNotificationsHandlers() {
+	sm := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	otherSM := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	notification := models.InboxNotification{
+		UserID:  sm.UserID,
+		Event:   "move_approved",
+		Subject: "MOVE.MIL: Your move has been approved.",
+		Body:    "Your move has been approved and you are ready to move!",
+	}
+	suite.MustSave(&notification)
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := httptest.NewRequest("GET", "/users/inbox_notifications", nil)
+	indexParams := userop.IndexInboxNotificationsParams{
+		HTTPRequest: suite.AuthenticateRequest(req, sm),
+		UnreadOnly:  swag.Bool(true),
+	}
+	response := IndexInboxNotificationsHandler{context}.Handle(indexParams)
+	suite.Assertions.IsType(&userop.IndexInboxNotificationsOK{}, response)
+	payload := response.(*userop.IndexInboxNotificationsOK).Payload
+	suite.Equal(int64(1), *payload.UnreadCount)
+	suite.Len(payload.Notifications, 1)
+
+	// Other service members can't read the notification
+	req = httptest.NewRequest("POST", "/users/inbox_notifications/"+notification.ID.String()+"/read", nil)
+	readParams := userop.MarkInboxNotificationReadParams{
+		HTTPRequest:         suite.AuthenticateRequest(req, otherSM),
+		InboxNotificationID: strfmt.UUID(notification.ID.String()),
+	}
+	response = MarkInboxNotificationReadHandler{context}.Handle(readParams)
+	suite.CheckResponseForbidden(response)
+
+	readParams.HTTPRequest = suite.AuthenticateRequest(req, sm)
+	response = MarkInboxNotificationReadHandler{context}.Handle(readParams)
+	suite.Assertions.IsType(&userop.MarkInboxNotificationReadOK{}, response)
+	suite.NotNil(response.(*userop.MarkInboxNotificationReadOK).Payload.ReadAt)
+
+	response = IndexInboxNotificationsHandler{context}.Handle(indexParams)
+	payload = response.(*userop.IndexInboxNotificationsOK).Payload
+	suite.Equal(int64(0), *payload.UnreadCount)
+	suite.Len(payload.Notifications, 0)
+}
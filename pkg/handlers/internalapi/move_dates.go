@@ -6,15 +6,18 @@ import (
 	"github.com/gobuffalo/pop"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"github.com/rickar/cal"
 
 	"github.com/transcom/mymove/pkg/dates"
+	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/route"
 	"github.com/transcom/mymove/pkg/unit"
 )
 
-// calculateMoveDates is used on the hhg wizard DatePicker page to show move dates summary
-func calculateMoveDatesFromMove(db *pop.Connection, planner route.Planner, moveID uuid.UUID, moveDate time.Time) (dates.MoveDatesSummary, error) {
+// calculateMoveDates is used on the hhg wizard DatePicker page to show move dates summary. Dates are
+// counted on the calendar of the service member's duty station.
+func calculateMoveDatesFromMove(db *pop.Connection, planner route.Planner, calendars *dates.CalendarCache, moveID uuid.UUID, moveDate time.Time) (dates.MoveDatesSummary, error) {
 	var summary dates.MoveDatesSummary
 
 	// FetchMoveForMoveDates will get all the required associations used below.
@@ -54,18 +57,31 @@ func calculateMoveDatesFromMove(db *pop.Connection, planner route.Planner, moveI
 		return summary, err
 	}
 
-	summary.CalculateMoveDates(moveDate, estimatedPackDays, estimatedTransitDays)
+	calendar, err := calendars.Calendar(models.DutyStationCalendarScope(move.Orders.ServiceMember.DutyStation))
+	if err != nil {
+		return summary, err
+	}
+
+	summary.CalculateMoveDates(moveDate, estimatedPackDays, estimatedTransitDays, calendar)
 	// ReportDays isn't set by CalculateMoveDates and must be added here to display in the calendar widget
 	summary.ReportDays = []time.Time{move.Orders.ReportByDate.UTC()}
 
 	return summary, nil
 }
 
+// serviceMemberCalendar returns the calendar of the service member's duty station, which the move dates of
+// their shipments are counted on
+func serviceMemberCalendar(h handlers.HandlerContext, serviceMemberID uuid.UUID) (*cal.Calendar, error) {
+	scope, err := models.FetchServiceMemberCalendarScope(h.DB(), serviceMemberID)
+	if err != nil {
+		return nil, err
+	}
+	return h.Calendars().Calendar(scope)
+}
+
 // calculateMoveDatesFromShipment takes stored values on the shipment to calculate the most up-to-date move date ranges
 // this is used to display date ranges for the SM HHG review page and the status timeline on the post-hhg-submission landing page
-func calculateMoveDatesFromShipment(shipment *models.Shipment) (dates.MoveDatesSummary, error) {
-	usCalendar := dates.NewUSCalendar()
-
+func calculateMoveDatesFromShipment(shipment *models.Shipment, calendar *cal.Calendar) (dates.MoveDatesSummary, error) {
 	if shipment.EstimatedPackDays == nil {
 		return dates.MoveDatesSummary{}, errors.New("Shipment must have EstimatedPackDays")
 	}
@@ -87,7 +103,7 @@ func calculateMoveDatesFromShipment(shipment *models.Shipment) (dates.MoveDatesS
 		mostCurrentPackDate = *shipment.OriginalPackDate
 	} else {
 		lastPossiblePackDay := shipment.RequestedPickupDate.AddDate(0, 0, -1)
-		mostCurrentPackDate = dates.CreatePastMoveDates(lastPossiblePackDay, int(*shipment.EstimatedPackDays), false, usCalendar)[0]
+		mostCurrentPackDate = dates.CreatePastMoveDates(lastPossiblePackDay, int(*shipment.EstimatedPackDays), false, calendar)[0]
 	}
 
 	var mostCurrentPickupDate time.Time
@@ -109,24 +125,24 @@ func calculateMoveDatesFromShipment(shipment *models.Shipment) (dates.MoveDatesS
 		mostCurrentDeliveryDate = *shipment.OriginalDeliveryDate
 	} else {
 		// transit days can be on weekends and holidays and delivery cannot, so calculations must be separated out
-		estimatedTransitDates := dates.CreateFutureMoveDates(*shipment.RequestedPickupDate, int(*shipment.EstimatedTransitDays), true, usCalendar)
+		estimatedTransitDates := dates.CreateFutureMoveDates(*shipment.RequestedPickupDate, int(*shipment.EstimatedTransitDays), true, calendar)
 		lastEstimatedTransitDate := estimatedTransitDates[len(estimatedTransitDates)-1]
-		mostCurrentDeliveryDate = dates.CreateFutureMoveDates(lastEstimatedTransitDate.AddDate(0, 0, 1), 1, false, usCalendar)[0]
+		mostCurrentDeliveryDate = dates.CreateFutureMoveDates(lastEstimatedTransitDate.AddDate(0, 0, 1), 1, false, calendar)[0]
 	}
 	// assigns the pack dates
-	packDates, err := dates.CreateValidDatesBetweenTwoDates(mostCurrentPackDate, mostCurrentPickupDate, false, true, usCalendar)
+	packDates, err := dates.CreateValidDatesBetweenTwoDates(mostCurrentPackDate, mostCurrentPickupDate, false, true, calendar)
 	if err != nil {
 		return dates.MoveDatesSummary{}, err
 	}
-	pickupDates := dates.CreateFutureMoveDates(mostCurrentPickupDate, 1, false, usCalendar)
+	pickupDates := dates.CreateFutureMoveDates(mostCurrentPickupDate, 1, false, calendar)
 
 	firstPossibleTransitDay := time.Time(pickupDates[len(pickupDates)-1]).AddDate(0, 0, 1)
 
-	transitDates, err := dates.CreateValidDatesBetweenTwoDates(firstPossibleTransitDay, mostCurrentDeliveryDate, true, true, usCalendar)
+	transitDates, err := dates.CreateValidDatesBetweenTwoDates(firstPossibleTransitDay, mostCurrentDeliveryDate, true, true, calendar)
 	if err != nil {
 		return dates.MoveDatesSummary{}, err
 	}
-	deliveryDates := dates.CreateFutureMoveDates(mostCurrentDeliveryDate, 1, false, usCalendar)
+	deliveryDates := dates.CreateFutureMoveDates(mostCurrentDeliveryDate, 1, false, calendar)
 
 	summary := dates.MoveDatesSummary{
 		MoveDate:             mostCurrentPickupDate,
@@ -16,7 +16,7 @@ func (suite *HandlerSuite) TestCalculateMoveDatesFromShipmentMissingPickupDate()
 		EstimatedPackDays:    &packDays,
 	}
 
-	_, err := calculateMoveDatesFromShipment(&shipment)
+	_, err := calculateMoveDatesFromShipment(&shipment, dates.NewUSCalendar())
 
 	suite.Error(err)
 }
@@ -30,7 +30,7 @@ func (suite *HandlerSuite) TestCalculateMoveDatesFromShipmentMissingEstimatedPac
 		RequestedPickupDate:  &pickupDate,
 	}
 
-	_, err := calculateMoveDatesFromShipment(&shipment)
+	_, err := calculateMoveDatesFromShipment(&shipment, dates.NewUSCalendar())
 
 	suite.Error(err)
 }
@@ -44,7 +44,7 @@ func (suite *HandlerSuite) TestCalculateMoveDatesFromShipmentMissingEstimatedTra
 		RequestedPickupDate:  &pickupDate,
 	}
 
-	_, err := calculateMoveDatesFromShipment(&shipment)
+	_, err := calculateMoveDatesFromShipment(&shipment, dates.NewUSCalendar())
 
 	suite.Error(err)
 }
@@ -239,7 +239,7 @@ func (suite *HandlerSuite) TestCalculateMoveDatesFromShipment() {
 
 	for _, testCase := range cases {
 		suite.T().Run(testCase.name, func(t *testing.T) {
-			summary, err := calculateMoveDatesFromShipment(&testCase.shipment)
+			summary, err := calculateMoveDatesFromShipment(&testCase.shipment, dates.NewUSCalendar())
 			suite.Nil(err)
 			suite.Equal(testCase.summary.PackDays, summary.PackDays, "%v: PackDays did not match, expected %v, got %v", testCase.name, testCase.summary.PackDays, summary.PackDays)
 			suite.Equal(testCase.summary.PickupDays, summary.PickupDays, "%v: PickupDays did not match, expected %v, got %v", testCase.name, testCase.summary.PickupDays, summary.PickupDays)
@@ -2,9 +2,9 @@ package internalapi
 
 import (
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 
-	"github.com/pkg/errors"
 	"github.com/transcom/mymove/pkg/auth"
 	movedocop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/move_docs"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
@@ -36,6 +36,13 @@ func payloadForMoveDocument(storer storage.FileStorer, moveDoc models.MoveDocume
 		payload.MovingExpenseType = internalmessages.MovingExpenseType(moveDoc.MovingExpenseDocument.MovingExpenseType)
 		payload.RequestedAmountCents = int64(moveDoc.MovingExpenseDocument.RequestedAmountCents)
 		payload.PaymentMethod = moveDoc.MovingExpenseDocument.PaymentMethod
+		payload.PpmTripID = handlers.FmtUUIDPtr(moveDoc.MovingExpenseDocument.PPMTripID)
+	}
+
+	if moveDoc.WeightTicketDocument != nil {
+		payload.TripNumber = swag.Int64(int64(moveDoc.WeightTicketDocument.TripNumber))
+		payload.EmptyWeight = swag.Int64(moveDoc.WeightTicketDocument.EmptyWeight.Int64())
+		payload.FullWeight = swag.Int64(moveDoc.WeightTicketDocument.FullWeight.Int64())
 	}
 
 	return &payload, nil
@@ -74,6 +81,16 @@ func payloadForMoveDocumentExtractor(storer storage.FileStorer, docExtractor mod
 		RequestedAmountCents:     int64(requestedAmt),
 		PaymentMethod:            paymentMethod,
 	}
+	payload.PpmTripID = handlers.FmtUUIDPtr(docExtractor.PPMTripID)
+	if docExtractor.TripNumber != nil {
+		payload.TripNumber = swag.Int64(int64(*docExtractor.TripNumber))
+	}
+	if docExtractor.EmptyWeight != nil {
+		payload.EmptyWeight = swag.Int64(docExtractor.EmptyWeight.Int64())
+	}
+	if docExtractor.FullWeight != nil {
+		payload.FullWeight = swag.Int64(docExtractor.FullWeight.Int64())
+	}
 
 	return &payload, nil
 }
@@ -130,6 +147,7 @@ func (h UpdateMoveDocumentHandler) Handle(params movedocop.UpdateMoveDocumentPar
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := *moveDoc
 
 	payload := params.UpdateMoveDocument
 	if payload.PersonallyProcuredMoveID != nil {
@@ -143,29 +161,13 @@ func (h UpdateMoveDocumentHandler) Handle(params movedocop.UpdateMoveDocumentPar
 
 	newStatus := models.MoveDocumentStatus(payload.Status)
 
-	// If this is a shipment summary and it has been approved, we process the ppm.
+	// Approving a shipment summary doesn't complete its PPM. The office closes the PPM out by approving
+	// its payment, which computes the incentive from the approved weight tickets.
 	if newStatus != moveDoc.Status {
 		err = moveDoc.AttemptTransition(newStatus)
 		if err != nil {
 			return handlers.ResponseForError(h.Logger(), err)
 		}
-
-		if newStatus == models.MoveDocumentStatusOK && moveDoc.MoveDocumentType == models.MoveDocumentTypeSHIPMENTSUMMARY {
-			if moveDoc.PersonallyProcuredMoveID == nil {
-				return handlers.ResponseForError(h.Logger(), errors.New("No PPM loaded for Approved Move Doc"))
-			}
-
-			ppm := &moveDoc.PersonallyProcuredMove
-			// If the status has already been completed
-			// (because the document has been toggled between OK and HAS_ISSUE and back)
-			// then don't complete it again.
-			if ppm.Status != models.PPMStatusCOMPLETED {
-				err := ppm.Complete()
-				if err != nil {
-					return handlers.ResponseForError(h.Logger(), err)
-				}
-			}
-		}
 	}
 
 	var saveAction models.MoveDocumentSaveAction
@@ -199,7 +201,8 @@ func (h UpdateMoveDocumentHandler) Handle(params movedocop.UpdateMoveDocumentPar
 		}
 	}
 
-	verrs, err := models.SaveMoveDocument(h.DB(), moveDoc, saveAction)
+	audit := models.NewAuditEvent(session, &before, moveDoc, "update", "")
+	verrs, err := models.SaveMoveDocument(h.DB(), moveDoc, saveAction, audit)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -237,6 +237,8 @@ func (suite *HandlerSuite) TestApproveMoveDocumentHandler() {
 	q.All(&ppms)
 	suite.Require().Equal(len(ppms), 1, "Should have a PPM!")
 	reloadedPPM := ppms[0]
-	suite.Require().Equal(string(models.PPMStatusCOMPLETED), string(reloadedPPM.Status))
+	// And: the PPM is left for the office to close out by approving its payment
+	suite.Require().Equal(string(models.PPMStatusPAYMENTREQUESTED), string(reloadedPPM.Status))
+	suite.Nil(reloadedPPM.IncentiveActual)
 
 }
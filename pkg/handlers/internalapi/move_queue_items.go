@@ -1,8 +1,11 @@
 package internalapi
 
 import (
+	"time"
+
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
 
 	"github.com/transcom/mymove/pkg/auth"
 	queueop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/queues"
@@ -18,6 +21,7 @@ func payloadForMoveQueueItem(MoveQueueItem models.MoveQueueItem) *internalmessag
 		CreatedAt:        handlers.FmtDateTime(MoveQueueItem.CreatedAt),
 		Edipi:            swag.String(MoveQueueItem.Edipi),
 		Rank:             MoveQueueItem.Rank,
+		Branch:           (*internalmessages.Affiliation)(MoveQueueItem.Branch),
 		CustomerName:     swag.String(MoveQueueItem.CustomerName),
 		Locator:          swag.String(MoveQueueItem.Locator),
 		Status:           swag.String(MoveQueueItem.Status),
@@ -28,6 +32,8 @@ func payloadForMoveQueueItem(MoveQueueItem models.MoveQueueItem) *internalmessag
 		CustomerDeadline: handlers.FmtDate(MoveQueueItem.CustomerDeadline),
 		LastModifiedDate: handlers.FmtDateTime(MoveQueueItem.LastModifiedDate),
 		LastModifiedName: swag.String(MoveQueueItem.LastModifiedName),
+		AssigneeID:       handlers.FmtUUIDPtr(MoveQueueItem.AssigneeID),
+		AssigneeName:     MoveQueueItem.AssigneeName,
 	}
 	return &MoveQueueItemPayload
 }
@@ -47,7 +53,7 @@ func (h ShowQueueHandler) Handle(params queueop.ShowQueueParams) middleware.Resp
 
 	lifecycleState := params.QueueType
 
-	MoveQueueItems, err := models.GetMoveQueueItems(h.DB(), lifecycleState)
+	MoveQueueItems, err := models.GetMoveQueueItems(h.DB(), h.Calendars(), session, lifecycleState)
 	if err != nil {
 		h.Logger().Error("Loading Queue", zap.String("State", lifecycleState), zap.Error(err))
 		return handlers.ResponseForError(h.Logger(), err)
@@ -60,3 +66,89 @@ func (h ShowQueueHandler) Handle(params queueop.ShowQueueParams) middleware.Resp
 	}
 	return queueop.NewShowQueueOK().WithPayload(MoveQueueItemPayloads)
 }
+
+func moveQueueFiltersFromParams(params queueop.SearchQueueParams) models.MoveQueueFilters {
+	filters := models.MoveQueueFilters{
+		Branches:    params.Branch,
+		Ranks:       params.Rank,
+		OrdersTypes: params.OrdersType,
+		PpmStatuses: params.PpmStatus,
+		HhgStatuses: params.HhgStatus,
+	}
+	if params.Gbloc != nil {
+		filters.GBLOC = *params.Gbloc
+	}
+	if params.MoveDateFrom != nil {
+		from := time.Time(*params.MoveDateFrom)
+		filters.MoveDateFrom = &from
+	}
+	if params.MoveDateTo != nil {
+		to := time.Time(*params.MoveDateTo)
+		filters.MoveDateTo = &to
+	}
+	if params.Assignee != nil {
+		filters.Assignee = *params.Assignee
+	}
+	return filters
+}
+
+// SearchQueueHandler returns a page of the moves in a queue
+type SearchQueueHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle filters, sorts and paginates the moves in a queue, optionally using a saved view
+func (h SearchQueueHandler) Handle(params queueop.SearchQueueParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+
+	if !session.IsOfficeUser() {
+		return queueop.NewSearchQueueForbidden()
+	}
+
+	limit := 50
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+
+	var queueParams models.MoveQueueParams
+	if params.View != nil {
+		viewID, _ := uuid.FromString(params.View.String())
+		view, err := models.FetchOfficeQueueView(h.DB(), session, viewID)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		queueParams, err = view.MoveQueueParams(limit)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	} else {
+		sorts, err := models.ParseMoveQueueSort(params.Sort)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		queueParams = models.MoveQueueParams{
+			LifecycleState: params.QueueType,
+			Filters:        moveQueueFiltersFromParams(params),
+			Sort:           sorts,
+			Limit:          limit,
+		}
+	}
+	if params.Cursor != nil {
+		queueParams.Cursor = *params.Cursor
+	}
+
+	page, err := models.SearchMoveQueue(h.DB(), h.Calendars(), session, queueParams)
+	if err != nil {
+		h.Logger().Error("Searching Queue", zap.String("State", queueParams.LifecycleState), zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := internalmessages.MoveQueuePage{
+		Items:      make([]*internalmessages.MoveQueueItem, len(page.Items)),
+		NextCursor: page.NextCursor,
+	}
+	for i, item := range page.Items {
+		payload.Items[i] = payloadForMoveQueueItem(item)
+	}
+	return queueop.NewSearchQueueOK().WithPayload(&payload)
+}
@@ -89,3 +89,41 @@ func (suite *HandlerSuite) TestShowQueueHandlerForbidden() {
 		suite.Assertions.IsType(&queueop.ShowQueueForbidden{}, showResponse)
 	}
 }
+
+func (suite *HandlerSuite) TestSearchQueueHandler() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	for i := 0; i < 3; i++ {
+		testdatagen.MakeMove(suite.TestDB(), testdatagen.Assertions{
+			Move: models.Move{Status: models.MoveStatusSUBMITTED},
+		})
+	}
+
+	req := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/queues/new/moves", nil), officeUser)
+	limit := int64(2)
+	params := queueop.SearchQueueParams{
+		HTTPRequest: req,
+		QueueType:   "new",
+		Sort:        []string{"-created_at"},
+		Limit:       &limit,
+	}
+	handler := SearchQueueHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+
+	response := handler.Handle(params)
+	suite.Assertions.IsType(&queueop.SearchQueueOK{}, response)
+	page := response.(*queueop.SearchQueueOK).Payload
+	suite.Len(page.Items, 2)
+	suite.NotNil(page.NextCursor)
+
+	params.Cursor = page.NextCursor
+	response = handler.Handle(params)
+	suite.Assertions.IsType(&queueop.SearchQueueOK{}, response)
+	page = response.(*queueop.SearchQueueOK).Payload
+	suite.Len(page.Items, 1)
+	suite.Nil(page.NextCursor)
+
+	// Unknown sort columns are rejected
+	params.Cursor = nil
+	params.Sort = []string{"ssn"}
+	response = handler.Handle(params)
+	suite.CheckResponseBadRequest(response)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 	"github.com/honeycombio/beeline-go"
+	"github.com/rickar/cal"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/auth"
@@ -19,7 +20,7 @@ import (
 	"github.com/transcom/mymove/pkg/storage"
 )
 
-func payloadForMoveModel(storer storage.FileStorer, order models.Order, move models.Move) (*internalmessages.MovePayload, error) {
+func payloadForMoveModel(storer storage.FileStorer, calendar *cal.Calendar, order models.Order, move models.Move) (*internalmessages.MovePayload, error) {
 
 	var ppmPayloads internalmessages.IndexPersonallyProcuredMovePayload
 	for _, ppm := range move.PersonallyProcuredMoves {
@@ -37,7 +38,7 @@ func payloadForMoveModel(storer storage.FileStorer, order models.Order, move mod
 
 	var shipmentPayloads []*internalmessages.Shipment
 	for _, shipment := range move.Shipments {
-		payload, err := payloadForShipmentModel(shipment)
+		payload, err := payloadForShipmentModel(calendar, shipment)
 		if err != nil {
 			return nil, err
 		}
@@ -82,7 +83,11 @@ func (h CreateMoveHandler) Handle(params moveop.CreateMoveParams) middleware.Res
 		}
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
-	movePayload, err := payloadForMoveModel(h.FileStorer(), orders, *move)
+	calendar, err := serviceMemberCalendar(h, orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -112,7 +117,11 @@ func (h ShowMoveHandler) Handle(params moveop.ShowMoveParams) middleware.Respond
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	movePayload, err := payloadForMoveModel(h.FileStorer(), orders, *move)
+	calendar, err := serviceMemberCalendar(h, orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -140,6 +149,7 @@ func (h PatchMoveHandler) Handle(params moveop.PatchMoveParams) middleware.Respo
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := *move
 	payload := params.PatchMovePayload
 	newSelectedMoveType := payload.SelectedMoveType
 
@@ -150,11 +160,15 @@ func (h PatchMoveHandler) Handle(params moveop.PatchMoveParams) middleware.Respo
 		}
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(move)
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, move, "update", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
-	movePayload, err := payloadForMoveModel(h.FileStorer(), orders, *move)
+	calendar, err := serviceMemberCalendar(h, orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -182,6 +196,7 @@ func (h SubmitMoveHandler) Handle(params moveop.SubmitMoveForApprovalParams) mid
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
+	before := move.AuditSnapshot()
 	err = move.Submit()
 	span.AddField("move-status", string(move.Status))
 	if err != nil {
@@ -192,7 +207,8 @@ func (h SubmitMoveHandler) Handle(params moveop.SubmitMoveForApprovalParams) mid
 	}
 
 	// Transaction to save move and dependencies
-	verrs, err := models.SaveMoveDependencies(h.DB(), move)
+	audits := move.TransitionAuditEvents(session, before, "submit", "")
+	verrs, err := models.SaveMoveDependencies(h.DB(), move, audits...)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -206,10 +222,14 @@ func (h SubmitMoveHandler) Handle(params moveop.SubmitMoveForApprovalParams) mid
 	}
 
 	if len(move.Shipments) > 0 {
-		go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger()).Run(ctx)
+		go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger(), h.NotificationSender()).Run(ctx)
 	}
 
-	movePayload, err := payloadForMoveModel(h.FileStorer(), move.Orders, *move)
+	calendar, err := serviceMemberCalendar(h, move.Orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, move.Orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -226,7 +246,7 @@ func (h ShowMoveDatesSummaryHandler) Handle(params moveop.ShowMoveDatesSummaryPa
 	moveDate := time.Time(params.MoveDate)
 	moveID, _ := uuid.FromString(params.MoveID.String())
 
-	summary, err := calculateMoveDatesFromMove(h.DB(), h.Planner(), moveID, moveDate)
+	summary, err := calculateMoveDatesFromMove(h.DB(), h.Planner(), h.Calendars(), moveID, moveDate)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
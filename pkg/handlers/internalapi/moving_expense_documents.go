@@ -31,6 +31,7 @@ func payloadForMovingExpenseDocumentModel(storer storage.FileStorer, movingExpen
 		MovingExpenseType:    internalmessages.MovingExpenseType(movingExpenseDocument.MovingExpenseType),
 		RequestedAmountCents: int64(movingExpenseDocument.RequestedAmountCents),
 		PaymentMethod:        movingExpenseDocument.PaymentMethod,
+		PpmTripID:            handlers.FmtUUIDPtr(movingExpenseDocument.PPMTripID),
 	}
 
 	return &movingExpenseDocumentPayload, nil
@@ -87,6 +88,20 @@ func (h CreateMovingExpenseDocumentHandler) Handle(params movedocop.CreateMoving
 		ppmID = &id
 	}
 
+	// Expenses can be attributed to one trip of a PPM moved in several trips
+	var ppmTripID *uuid.UUID
+	if payload.PpmTripID != nil {
+		id := uuid.Must(uuid.FromString(payload.PpmTripID.String()))
+		trip, err := models.FetchPPMTrip(h.DB(), session, id)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		if ppmID == nil || trip.PersonallyProcuredMoveID != *ppmID {
+			return movedocop.NewCreateMovingExpenseDocumentBadRequest()
+		}
+		ppmTripID = &id
+	}
+
 	newMovingExpenseDocument, verrs, err := move.CreateMovingExpenseDocument(
 		h.DB(),
 		uploads,
@@ -97,6 +112,7 @@ func (h CreateMovingExpenseDocumentHandler) Handle(params movedocop.CreateMoving
 		unit.Cents(*payload.RequestedAmountCents),
 		*payload.PaymentMethod,
 		models.MovingExpenseType(payload.MovingExpenseType),
+		ppmTripID,
 		*move.SelectedMoveType,
 	)
 
@@ -0,0 +1,101 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/validate"
+
+	"github.com/transcom/mymove/pkg/auth"
+	userop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/users"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+// notificationEventsForSession returns the events the session's user is notified of
+func notificationEventsForSession(session *auth.Session) []string {
+	events := []string{}
+	if session.IsServiceMember() {
+		events = append(events, notifications.ServiceMemberEvents...)
+	}
+	if session.IsOfficeUser() {
+		events = append(events, notifications.OfficeUserEvents...)
+	}
+	return events
+}
+
+func payloadForNotificationPreference(event string, channel models.NotificationChannel, enabled bool) *internalmessages.NotificationPreferencePayload {
+	return &internalmessages.NotificationPreferencePayload{
+		Event:   swag.String(event),
+		Channel: swag.String(string(channel)),
+		Enabled: swag.Bool(enabled),
+	}
+}
+
+// IndexNotificationPreferencesHandler returns the notification preferences of the logged in user
+type IndexNotificationPreferencesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns a preference for every event the user is notified of and channel the event is sent through
+func (h IndexNotificationPreferencesHandler) Handle(params userop.IndexNotificationPreferencesParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+
+	preferences, err := models.FetchNotificationPreferences(h.DB(), session.UserID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	enabled := map[string]bool{}
+	for _, preference := range preferences {
+		enabled[preference.Event+":"+string(preference.Channel)] = preference.Enabled
+	}
+
+	payload := internalmessages.IndexNotificationPreferencesPayload{}
+	for _, event := range notificationEventsForSession(session) {
+		for _, channel := range notifications.EventChannels(event) {
+			isEnabled, ok := enabled[event+":"+string(channel)]
+			payload = append(payload, payloadForNotificationPreference(event, channel, isEnabled || !ok))
+		}
+	}
+	return userop.NewIndexNotificationPreferencesOK().WithPayload(payload)
+}
+
+// UpdateNotificationPreferenceHandler updates a notification preference of the logged in user
+type UpdateNotificationPreferenceHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle turns an event's notifications through a channel on or off
+func (h UpdateNotificationPreferenceHandler) Handle(params userop.UpdateNotificationPreferenceParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	payload := params.NotificationPreference
+	event := swag.StringValue(payload.Event)
+
+	notified := false
+	for _, sessionEvent := range notificationEventsForSession(session) {
+		notified = notified || sessionEvent == event
+	}
+	if !notified {
+		verrs := validate.NewErrors()
+		verrs.Add("event", "You aren't notified of "+event+".")
+		return handlers.ResponseForVErrors(h.Logger(), verrs, nil)
+	}
+
+	channel := models.NotificationChannel(swag.StringValue(payload.Channel))
+	supported := false
+	for _, eventChannel := range notifications.EventChannels(event) {
+		supported = supported || eventChannel == channel
+	}
+	if !supported {
+		verrs := validate.NewErrors()
+		verrs.Add("channel", event+" isn't sent through "+string(channel)+".")
+		return handlers.ResponseForVErrors(h.Logger(), verrs, nil)
+	}
+
+	preference, verrs, err := models.SetNotificationPreference(h.DB(), session.UserID, event, channel, swag.BoolValue(payload.Enabled))
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return userop.NewUpdateNotificationPreferenceOK().WithPayload(payloadForNotificationPreference(preference.Event, preference.Channel, preference.Enabled))
+}
@@ -0,0 +1,52 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	userop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/users"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestNotificationPreferencesHandlers() {
+	sm := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := httptest.NewRequest("PUT", "/users/notification_preferences", nil)
+	updateParams := userop.UpdateNotificationPreferenceParams{
+		HTTPRequest: suite.AuthenticateRequest(req, sm),
+		NotificationPreference: &internalmessages.NotificationPreferencePayload{
+			Event:   swag.String(notifications.EventMoveApproved),
+			Channel: swag.String("email"),
+			Enabled: swag.Bool(false),
+		},
+	}
+	response := UpdateNotificationPreferenceHandler{context}.Handle(updateParams)
+	suite.Assertions.IsType(&userop.UpdateNotificationPreferenceOK{}, response)
+	suite.False(*response.(*userop.UpdateNotificationPreferenceOK).Payload.Enabled)
+
+	req = httptest.NewRequest("GET", "/users/notification_preferences", nil)
+	indexParams := userop.IndexNotificationPreferencesParams{HTTPRequest: suite.AuthenticateRequest(req, sm)}
+	response = IndexNotificationPreferencesHandler{context}.Handle(indexParams)
+	suite.Assertions.IsType(&userop.IndexNotificationPreferencesOK{}, response)
+	payload := response.(*userop.IndexNotificationPreferencesOK).Payload
+	suite.Len(payload, len(notifications.ServiceMemberEvents)*len(notifications.EventChannels(notifications.EventMoveApproved)))
+	for _, preference := range payload {
+		unsubscribed := *preference.Event == notifications.EventMoveApproved && *preference.Channel == "email"
+		suite.Equal(!unsubscribed, *preference.Enabled, *preference.Event+" "+*preference.Channel)
+	}
+
+	// Service member events can also be turned off for text messages and the inbox
+	updateParams.NotificationPreference.Channel = swag.String("sms")
+	response = UpdateNotificationPreferenceHandler{context}.Handle(updateParams)
+	suite.Assertions.IsType(&userop.UpdateNotificationPreferenceOK{}, response)
+
+	// Service members aren't notified of office events
+	updateParams.NotificationPreference.Event = swag.String(notifications.EventSLABreached)
+	response = UpdateNotificationPreferenceHandler{context}.Handle(updateParams)
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+}
@@ -1,7 +1,10 @@
 package internalapi
 
 import (
+	"time"
+
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
 	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 // ApproveMoveHandler approves a move via POST /moves/{moveId}/approve
@@ -38,21 +42,30 @@ func (h ApproveMoveHandler) Handle(params officeop.ApproveMoveParams) middleware
 	if orders.IsComplete() != true {
 		return officeop.NewApprovePPMBadRequest()
 	}
+	err = models.AuthorizeClaimedWork(h.DB(), session, models.WorkClaimRecordTypeMove, move.ID, swag.BoolValue(params.Override))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
 
+	before := *move
 	err = move.Approve()
 	if err != nil {
 		h.Logger().Info("Attempted to approve move, got invalid transition", zap.Error(err), zap.String("move_status", string(move.Status)))
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(move)
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, move, "approve", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
 	// TODO: Save and/or update the move association status' (PPM, Reimbursement, Orders) a la Cancel handler
 
-	movePayload, err := payloadForMoveModel(h.FileStorer(), move.Orders, *move)
+	calendar, err := serviceMemberCalendar(h, move.Orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, move.Orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -80,6 +93,7 @@ func (h CancelMoveHandler) Handle(params officeop.CancelMoveParams) middleware.R
 	}
 
 	// Canceling move will result in canceled associated PPMs
+	before := move.AuditSnapshot()
 	err = move.Cancel(*params.CancelMove.CancelReason)
 	if err != nil {
 		h.Logger().Error("Attempted to cancel move, got invalid transition", zap.Error(err), zap.String("move_status", string(move.Status)))
@@ -87,7 +101,8 @@ func (h CancelMoveHandler) Handle(params officeop.CancelMoveParams) middleware.R
 	}
 
 	// Save move, orders, and PPMs statuses
-	verrs, err := models.SaveMoveDependencies(h.DB(), move)
+	audits := move.TransitionAuditEvents(session, before, "cancel", *params.CancelMove.CancelReason)
+	verrs, err := models.SaveMoveDependencies(h.DB(), move, audits...)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -101,7 +116,11 @@ func (h CancelMoveHandler) Handle(params officeop.CancelMoveParams) middleware.R
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	movePayload, err := payloadForMoveModel(h.FileStorer(), move.Orders, *move)
+	calendar, err := serviceMemberCalendar(h, move.Orders.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	movePayload, err := payloadForMoveModel(h.FileStorer(), calendar, move.Orders, *move)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -127,14 +146,19 @@ func (h ApprovePPMHandler) Handle(params officeop.ApprovePPMParams) middleware.R
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	err = models.AuthorizeClaimedWork(h.DB(), session, models.WorkClaimRecordTypePPM, ppm.ID, swag.BoolValue(params.Override))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
 	moveID := ppm.MoveID
+	before := *ppm
 	err = ppm.Approve()
 	if err != nil {
 		h.Logger().Error("Attempted to approve PPM, got invalid transition", zap.Error(err), zap.String("move_status", string(ppm.Status)))
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(ppm)
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, ppm, "approve", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -154,6 +178,47 @@ func (h ApprovePPMHandler) Handle(params officeop.ApprovePPMParams) middleware.R
 	return officeop.NewApprovePPMOK().WithPayload(ppmPayload)
 }
 
+// ApprovePPMPaymentHandler approves a PPM payment request via POST /personally_procured_moves/{personallyProcuredMoveId}/approve_payment
+type ApprovePPMPaymentHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle ... recomputes the incentive of a Personally Procured Move from its approved weight tickets and completes it
+func (h ApprovePPMPaymentHandler) Handle(params officeop.ApprovePPMPaymentParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return officeop.NewApprovePPMPaymentForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	ppmID, _ := uuid.FromString(params.PersonallyProcuredMoveID.String())
+
+	ppm, err := models.FetchPersonallyProcuredMove(h.DB(), session, ppmID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	err = models.AuthorizeClaimedWork(h.DB(), session, models.WorkClaimRecordTypePPM, ppm.ID, swag.BoolValue(params.Override))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *ppm
+	verrs, err := closeOutPPM(h.HandlerContext, ppm)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	verrs, err = models.SaveWithAudit(h.DB(), session, &before, ppm, "complete", "")
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	ppmPayload, err := payloadForPPMModel(h.FileStorer(), *ppm)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return officeop.NewApprovePPMPaymentOK().WithPayload(ppmPayload)
+}
+
 // ApproveReimbursementHandler approves a move via POST /reimbursement/{reimbursementId}/approve
 type ApproveReimbursementHandler struct {
 	handlers.HandlerContext
@@ -174,14 +239,19 @@ func (h ApproveReimbursementHandler) Handle(params officeop.ApproveReimbursement
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	err = models.AuthorizeClaimedReimbursement(h.DB(), session, reimbursement.ID, swag.BoolValue(params.Override))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
 
+	before := *reimbursement
 	err = reimbursement.Approve()
 	if err != nil {
 		h.Logger().Error("Attempted to approve, got invalid transition", zap.Error(err), zap.String("reimbursement_status", string(reimbursement.Status)))
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(reimbursement)
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, reimbursement, "approve", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -189,3 +259,45 @@ func (h ApproveReimbursementHandler) Handle(params officeop.ApproveReimbursement
 	reimbursementPayload := payloadForReimbursementModel(reimbursement)
 	return officeop.NewApproveReimbursementOK().WithPayload(reimbursementPayload)
 }
+
+// DisburseReimbursementHandler records the disbursement of a reimbursement via POST /reimbursement/{reimbursementId}/disburse
+type DisburseReimbursementHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle ... marks an approved Reimbursement as paid with the amount and date finance paid it
+func (h DisburseReimbursementHandler) Handle(params officeop.DisburseReimbursementParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+
+	if !session.IsOfficeUser() {
+		return officeop.NewDisburseReimbursementForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	reimbursementID, _ := uuid.FromString(params.ReimbursementID.String())
+
+	reimbursement, err := models.FetchReimbursement(h.DB(), session, reimbursementID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	err = models.AuthorizeClaimedReimbursement(h.DB(), session, reimbursement.ID, swag.BoolValue(params.Override))
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.DisburseReimbursementPayload
+	before := *reimbursement
+	err = reimbursement.Disburse(unit.Cents(*payload.PaidAmount), time.Time(*payload.PaidDate))
+	if err != nil {
+		h.Logger().Error("Attempted to disburse, got invalid transition", zap.Error(err), zap.String("reimbursement_status", string(reimbursement.Status)))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, reimbursement, "disburse", "")
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	reimbursementPayload := payloadForReimbursementModel(reimbursement)
+	return officeop.NewDisburseReimbursementOK().WithPayload(reimbursementPayload)
+}
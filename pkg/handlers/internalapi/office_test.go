@@ -241,6 +241,37 @@ func (suite *HandlerSuite) TestApprovePPMHandlerForbidden() {
 	suite.Assertions.IsType(&officeop.ApprovePPMForbidden{}, response)
 }
 
+func (suite *HandlerSuite) TestApprovePPMPaymentHandlerRefusesUndisbursedAdvance() {
+	// Given: a PPM awaiting payment whose advance was approved but not yet disbursed
+	ppm := testdatagen.MakePPM(suite.TestDB(), testdatagen.Assertions{
+		PersonallyProcuredMove: models.PersonallyProcuredMove{
+			Status: models.PPMStatusPAYMENTREQUESTED,
+		},
+	})
+	suite.Nil(ppm.Advance.Request())
+	suite.Nil(ppm.Advance.Approve())
+	suite.MustSave(ppm.Advance)
+
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	req := httptest.NewRequest("POST", "/personally_procured_moves/some_id/approve_payment", nil)
+	req = suite.AuthenticateOfficeRequest(req, officeUser)
+	params := officeop.ApprovePPMPaymentParams{
+		HTTPRequest:              req,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+	}
+
+	// When: payment is approved
+	handler := ApprovePPMPaymentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	response := handler.Handle(params)
+
+	// Then: it is refused and the PPM is left awaiting payment
+	suite.Assertions.IsType(&handlers.ValidationErrorsResponse{}, response)
+	var fetched models.PersonallyProcuredMove
+	suite.Nil(suite.TestDB().Find(&fetched, ppm.ID))
+	suite.Equal(models.PPMStatusPAYMENTREQUESTED, fetched.Status)
+	suite.Nil(fetched.IncentiveActual)
+}
+
 func (suite *HandlerSuite) TestApproveReimbursementHandler() {
 	// Given: a set of orders, a move, user and servicemember
 	reimbursement, _ := testdatagen.MakeRequestedReimbursement(suite.TestDB())
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gofrs/uuid"
+	"github.com/rickar/cal"
 	"github.com/transcom/mymove/pkg/auth"
 	ordersop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/orders"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
@@ -13,7 +14,7 @@ import (
 	"github.com/transcom/mymove/pkg/storage"
 )
 
-func payloadForOrdersModel(storer storage.FileStorer, order models.Order) (*internalmessages.Orders, error) {
+func payloadForOrdersModel(storer storage.FileStorer, calendar *cal.Calendar, order models.Order) (*internalmessages.Orders, error) {
 	documentPayload, err := payloadForDocumentModel(storer, order.UploadedOrders)
 	if err != nil {
 		return nil, err
@@ -21,7 +22,7 @@ func payloadForOrdersModel(storer storage.FileStorer, order models.Order) (*inte
 
 	var moves internalmessages.IndexMovesPayload
 	for _, move := range order.Moves {
-		payload, err := payloadForMoveModel(storer, order, move)
+		payload, err := payloadForMoveModel(storer, calendar, order, move)
 		if err != nil {
 			return nil, err
 		}
@@ -113,7 +114,11 @@ func (h CreateOrdersHandler) Handle(params ordersop.CreateOrdersParams) middlewa
 	}
 	newOrder.Moves = append(newOrder.Moves, *newMove)
 
-	orderPayload, err := payloadForOrdersModel(h.FileStorer(), newOrder)
+	calendar, err := serviceMemberCalendar(h, newOrder.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	orderPayload, err := payloadForOrdersModel(h.FileStorer(), calendar, newOrder)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -135,7 +140,11 @@ func (h ShowOrdersHandler) Handle(params ordersop.ShowOrdersParams) middleware.R
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	orderPayload, err := payloadForOrdersModel(h.FileStorer(), order)
+	calendar, err := serviceMemberCalendar(h, order.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	orderPayload, err := payloadForOrdersModel(h.FileStorer(), calendar, order)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -159,6 +168,7 @@ func (h UpdateOrdersHandler) Handle(params ordersop.UpdateOrdersParams) middlewa
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := order
 
 	payload := params.UpdateOrders
 	stationID, err := uuid.FromString(payload.NewDutyStationID.String())
@@ -188,12 +198,16 @@ func (h UpdateOrdersHandler) Handle(params ordersop.UpdateOrdersParams) middlewa
 		order.DepartmentIndicator = handlers.FmtString(string(*payload.DepartmentIndicator))
 	}
 
-	verrs, err := models.SaveOrder(h.DB(), &order)
+	verrs, err := models.SaveOrder(h.DB(), &order, models.NewAuditEvent(session, &before, &order, "update", ""))
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
-	orderPayload, err := payloadForOrdersModel(h.FileStorer(), order)
+	calendar, err := serviceMemberCalendar(h, order.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	orderPayload, err := payloadForOrdersModel(h.FileStorer(), calendar, order)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 
@@ -61,6 +62,20 @@ func payloadForPPMModel(storer storage.FileStorer, personallyProcuredMove models
 		max := (*personallyProcuredMove.SITMax).Int64()
 		ppmPayload.SitMax = &max
 	}
+	ppmPayload.ActualMoveDate = handlers.FmtDatePtr(personallyProcuredMove.ActualMoveDate)
+	ppmPayload.NetWeight = personallyProcuredMove.NetWeight
+	if personallyProcuredMove.IncentiveActual != nil {
+		incentive := (*personallyProcuredMove.IncentiveActual).Int64()
+		ppmPayload.IncentiveActual = &incentive
+	}
+	if personallyProcuredMove.AdvanceDeduction != nil {
+		deduction := (*personallyProcuredMove.AdvanceDeduction).Int64()
+		ppmPayload.AdvanceDeduction = &deduction
+	}
+	if personallyProcuredMove.SettlementAmount != nil {
+		settlement := (*personallyProcuredMove.SettlementAmount).Int64()
+		ppmPayload.SettlementAmount = &settlement
+	}
 	return &ppmPayload, nil
 }
 
@@ -171,6 +186,9 @@ func patchPPMWithPayload(ppm *models.PersonallyProcuredMove, payload *internalme
 	if payload.DestinationPostalCode != nil {
 		ppm.DestinationPostalCode = payload.DestinationPostalCode
 	}
+	if payload.ActualMoveDate != nil {
+		ppm.ActualMoveDate = (*time.Time)(payload.ActualMoveDate)
+	}
 	if payload.HasSit != nil {
 		if *payload.HasSit == false {
 			ppm.DaysInStorage = nil
@@ -231,6 +249,7 @@ func (h PatchPersonallyProcuredMoveHandler) Handle(params ppmop.PatchPersonallyP
 		return ppmop.NewPatchPersonallyProcuredMoveBadRequest()
 	}
 
+	before := *ppm
 	needsEstimatesRecalculated := h.ppmNeedsEstimatesRecalculated(ppm, params.PatchPersonallyProcuredMovePayload)
 
 	patchPPMWithPayload(ppm, params.PatchPersonallyProcuredMovePayload)
@@ -243,7 +262,8 @@ func (h PatchPersonallyProcuredMoveHandler) Handle(params ppmop.PatchPersonallyP
 		}
 	}
 
-	verrs, err := models.SavePersonallyProcuredMove(h.DB(), ppm)
+	audit := models.NewAuditEvent(session, &before, ppm, "update", "")
+	verrs, err := models.SavePersonallyProcuredMove(h.DB(), ppm, audit)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -372,9 +392,103 @@ func (h PatchPersonallyProcuredMoveHandler) updateEstimates(ppm *models.Personal
 	ppm.IncentiveEstimateMin = &min
 	ppm.IncentiveEstimateMax = &max
 
+	// PPMs moved in several trips are estimated trip by trip instead
+	trips, err := models.FetchPPMTrips(h.DB(), ppm.ID)
+	if err != nil {
+		return err
+	}
+	if len(trips) > 0 {
+		return updatePPMTripEstimates(h.HandlerContext, ppm, trips)
+	}
+
 	return nil
 }
 
+// closeOutPPM completes a PPM whose payment the office approved. The incentive is computed from the
+// approved weight tickets and the disbursed advance is deducted from it, so every completed PPM is
+// settled. It is the only way a PPM is completed.
+func closeOutPPM(h handlers.HandlerContext, ppm *models.PersonallyProcuredMove) (*validate.Errors, error) {
+	if err := ppm.Complete(); err != nil {
+		h.Logger().Error("Attempted to complete PPM, got invalid transition", zap.Error(err), zap.String("ppm_status", string(ppm.Status)))
+		return validate.NewErrors(), err
+	}
+
+	// An advance disbursed after the PPM is paid wouldn't be deducted from the payment
+	if ppm.AdvanceIsPending() {
+		verrs := validate.NewErrors()
+		verrs.Add("advance", "The advance must be disbursed or rejected before payment is approved.")
+		return verrs, nil
+	}
+
+	return computePPMCloseout(h, ppm, true)
+}
+
+// computePPMCloseout sets the net weight of a PPM from its weight tickets and computes its incentive
+// for the net weight and actual move date. PPMs moved in several trips are computed trip by trip
+// using each trip's postal codes, date and weight tickets. Any disbursed advance is deducted from the incentive.
+func computePPMCloseout(h handlers.HandlerContext, ppm *models.PersonallyProcuredMove, requireApproved bool) (*validate.Errors, error) {
+	verrs := validate.NewErrors()
+	trips, err := models.FetchPPMTrips(h.DB(), ppm.ID)
+	if err != nil {
+		return verrs, err
+	}
+	if len(trips) == 0 {
+		if ppm.ActualMoveDate == nil {
+			verrs.Add("actual_move_date", "Actual move date is required to request payment.")
+		}
+		if ppm.PickupPostalCode == nil || ppm.DestinationPostalCode == nil {
+			verrs.Add("postal_codes", "Pickup and destination postal codes are required to request payment.")
+		}
+		if verrs.HasAny() {
+			return verrs, nil
+		}
+	}
+
+	netWeight, verrs, err := models.ComputePPMNetWeight(h.DB(), ppm, requireApproved)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+
+	re := rateengine.NewRateEngine(h.DB(), h.Logger(), h.Planner())
+	var gcc unit.Cents
+	if len(trips) == 0 {
+		// Like the estimates, the incentive doesn't include SIT
+		cost, err := computeTripCost(h, re, netWeight, *ppm.PickupPostalCode, *ppm.DestinationPostalCode, *ppm.ActualMoveDate)
+		if err != nil {
+			return verrs, err
+		}
+		gcc = cost.GCC
+	} else {
+		tripNetWeights, err := models.FetchPPMTripNetWeights(h.DB(), ppm.ID)
+		if err != nil {
+			return verrs, err
+		}
+		// ComputePPMNetWeight has checked that every trip has a weight ticket
+		for _, trip := range trips {
+			cost, err := computeTripCost(h, re, tripNetWeights[trip.ID], trip.PickupPostalCode, trip.DestinationPostalCode, trip.MoveDate)
+			if err != nil {
+				return verrs, err
+			}
+			gcc += cost.GCC
+		}
+	}
+
+	net := netWeight.Int64()
+	incentive := gcc.MultiplyFloat64(0.95)
+	ppm.NetWeight = &net
+	ppm.IncentiveActual = &incentive
+	ppm.SettleAdvance()
+
+	h.Logger().Info("computed PPM incentive for actual move",
+		zap.String("ppm_id", ppm.ID.String()),
+		zap.Int64("netWeight", net),
+		zap.Int("trips", len(trips)),
+		zap.Int64("incentive", incentive.Int64()),
+		zap.Int64("settlement", ppm.SettlementAmount.Int64()),
+	)
+	return verrs, nil
+}
+
 // RequestPPMPaymentHandler requests a payment for a PPM
 type RequestPPMPaymentHandler struct {
 	handlers.HandlerContext
@@ -392,12 +506,20 @@ func (h RequestPPMPaymentHandler) Handle(params ppmop.RequestPPMPaymentParams) m
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
+	before := *ppm
 	err = ppm.RequestPayment()
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := models.SavePersonallyProcuredMove(h.DB(), ppm)
+	// Weight tickets are reviewed by the office after payment is requested, so they don't need to be approved yet
+	verrs, err := computePPMCloseout(h.HandlerContext, ppm, false)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	audit := models.NewAuditEvent(session, &before, ppm, "request_payment", "")
+	verrs, err = models.SavePersonallyProcuredMove(h.DB(), ppm, audit)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
@@ -417,6 +539,7 @@ func buildExpenseSummaryPayload(moveDocsExpense []models.MoveDocument) internalm
 			PaymentMethodTotals: &internalmessages.PaymentMethodsTotals{},
 		},
 		Categories: []*internalmessages.CategoryExpenseSummary{},
+		Trips:      []*internalmessages.TripExpenseSummary{},
 	}
 
 	if len(moveDocsExpense) < 1 {
@@ -424,6 +547,7 @@ func buildExpenseSummaryPayload(moveDocsExpense []models.MoveDocument) internalm
 	}
 
 	catMap := map[internalmessages.MovingExpenseType]*internalmessages.CategoryExpenseSummary{}
+	tripMap := map[uuid.UUID]*internalmessages.TripExpenseSummary{}
 
 	for _, moveDoc := range moveDocsExpense {
 		// First add up grand totals by payment type and grand total
@@ -438,6 +562,28 @@ func buildExpenseSummaryPayload(moveDocsExpense []models.MoveDocument) internalm
 		}
 		expenseSummaryPayload.GrandTotal.Total += amount
 
+		// Total expenses by trip, with expenses that aren't attributed to a trip under uuid.Nil
+		tripID := uuid.Nil
+		if expenseDoc.PPMTripID != nil {
+			tripID = *expenseDoc.PPMTripID
+		}
+		tripSummary, ok := tripMap[tripID]
+		if !ok {
+			tripSummary = &internalmessages.TripExpenseSummary{
+				PpmTripID:      handlers.FmtUUIDPtr(expenseDoc.PPMTripID),
+				PaymentMethods: &internalmessages.PaymentMethodsTotals{},
+			}
+			tripMap[tripID] = tripSummary
+			expenseSummaryPayload.Trips = append(expenseSummaryPayload.Trips, tripSummary)
+		}
+		switch expenseDoc.PaymentMethod {
+		case "OTHER":
+			tripSummary.PaymentMethods.OTHER += amount
+		case "GTCC":
+			tripSummary.PaymentMethods.GTCC += amount
+		}
+		tripSummary.Total += amount
+
 		// Build categories by expense type
 		expenseType := internalmessages.MovingExpenseType(string(expenseDoc.MovingExpenseType))
 		// Check if expense type exists in catMap - increment values if so
@@ -667,4 +667,8 @@ func (suite *HandlerSuite) TestRequestPPMExpenseSummaryHandler() {
 	suite.Assertions.Equal(int64(5178), expenseSummary.Payload.Categories[0].Total)
 	suite.Assertions.Equal(int64(5178), expenseSummary.Payload.GrandTotal.PaymentMethodTotals.GTCC)
 	suite.Assertions.Equal(int64(5178), expenseSummary.Payload.GrandTotal.Total)
+	// Expenses that aren't attributed to a trip are totaled together
+	suite.Assertions.Len(expenseSummary.Payload.Trips, 1)
+	suite.Assertions.Nil(expenseSummary.Payload.Trips[0].PpmTripID)
+	suite.Assertions.Equal(int64(5178), expenseSummary.Payload.Trips[0].Total)
 }
@@ -0,0 +1,249 @@
+package internalapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	ppmop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/ppm"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func payloadForPPMTripModel(trip models.PPMTrip) *internalmessages.PPMTripPayload {
+	payload := internalmessages.PPMTripPayload{
+		ID:                       handlers.FmtUUID(trip.ID),
+		PersonallyProcuredMoveID: handlers.FmtUUID(trip.PersonallyProcuredMoveID),
+		TripNumber:               swag.Int64(int64(trip.TripNumber)),
+		PickupPostalCode:         swag.String(trip.PickupPostalCode),
+		DestinationPostalCode:    swag.String(trip.DestinationPostalCode),
+		MoveDate:                 handlers.FmtDate(trip.MoveDate),
+		WeightEstimate:           swag.Int64(trip.WeightEstimate.Int64()),
+	}
+	if trip.IncentiveEstimate != nil {
+		incentive := trip.IncentiveEstimate.Int64()
+		payload.IncentiveEstimate = &incentive
+	}
+	return &payload
+}
+
+// computeTripCost computes the cost of moving one trip of a PPM, without SIT
+func computeTripCost(h handlers.HandlerContext, re *rateengine.RateEngine, weight unit.Pound, origin string, destination string, date time.Time) (rateengine.CostComputation, error) {
+	lhDiscount, _, err := PPMDiscountFetch(h.DB(), h.Logger(), origin, destination, date)
+	if err != nil {
+		return rateengine.CostComputation{}, err
+	}
+	return re.ComputePPM(weight, origin, destination, date, 0, lhDiscount, 0.0)
+}
+
+// updatePPMTripEstimates estimates the incentive of each trip of a PPM and sets the PPM's weight,
+// mileage and incentive estimates to the totals across its trips
+func updatePPMTripEstimates(h handlers.HandlerContext, ppm *models.PersonallyProcuredMove, trips models.PPMTrips) error {
+	re := rateengine.NewRateEngine(h.DB(), h.Logger(), h.Planner())
+
+	var gcc unit.Cents
+	var weight, mileage int64
+	for i, trip := range trips {
+		cost, err := computeTripCost(h, re, trip.WeightEstimate, trip.PickupPostalCode, trip.DestinationPostalCode, trip.MoveDate)
+		if err != nil {
+			return err
+		}
+		incentive := cost.GCC.MultiplyFloat64(0.95)
+		trips[i].IncentiveEstimate = &incentive
+		gcc += cost.GCC
+		weight += trip.WeightEstimate.Int64()
+		mileage += int64(cost.LinehaulCostComputation.Mileage)
+	}
+
+	min := gcc.MultiplyFloat64(0.95)
+	max := gcc.MultiplyFloat64(1.05)
+	ppm.WeightEstimate = &weight
+	ppm.Mileage = &mileage
+	ppm.IncentiveEstimateMin = &min
+	ppm.IncentiveEstimateMax = &max
+	return nil
+}
+
+// savePPMTrips recomputes the estimates of a PPM after its trips have changed and saves them. Once
+// the last trip is removed, the incentive estimates are cleared until the PPM is estimated again.
+func savePPMTrips(h handlers.HandlerContext, ppm *models.PersonallyProcuredMove, trips models.PPMTrips, deleted *models.PPMTrip) middleware.Responder {
+	if len(trips) > 0 {
+		if err := updatePPMTripEstimates(h, ppm, trips); err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	} else {
+		ppm.Mileage = nil
+		ppm.IncentiveEstimateMin = nil
+		ppm.IncentiveEstimateMax = nil
+	}
+
+	verrs, err := models.SavePPMTrips(h.DB(), ppm, trips, deleted)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return nil
+}
+
+// IndexPPMTripsHandler lists the trips of a PPM
+type IndexPPMTripsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle is the handler
+func (h IndexPPMTripsHandler) Handle(params ppmop.IndexPPMTripsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	ppmID, _ := uuid.FromString(params.PersonallyProcuredMoveID.String())
+
+	if _, err := models.FetchPersonallyProcuredMove(h.DB(), session, ppmID); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	trips, err := models.FetchPPMTrips(h.DB(), ppmID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := internalmessages.IndexPPMTripsPayload{}
+	for _, trip := range trips {
+		payload = append(payload, payloadForPPMTripModel(trip))
+	}
+	return ppmop.NewIndexPPMTripsOK().WithPayload(payload)
+}
+
+// CreatePPMTripHandler adds a trip to a PPM
+type CreatePPMTripHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle is the handler
+func (h CreatePPMTripHandler) Handle(params ppmop.CreatePPMTripParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	ppmID, _ := uuid.FromString(params.PersonallyProcuredMoveID.String())
+
+	ppm, err := models.FetchPersonallyProcuredMove(h.DB(), session, ppmID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	trips, err := models.FetchPPMTrips(h.DB(), ppmID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.CreatePPMTripPayload
+	trips = append(trips, models.PPMTrip{
+		PersonallyProcuredMoveID: ppm.ID,
+		TripNumber:               len(trips) + 1,
+		PickupPostalCode:         *payload.PickupPostalCode,
+		DestinationPostalCode:    *payload.DestinationPostalCode,
+		MoveDate:                 time.Time(*payload.MoveDate),
+		WeightEstimate:           unit.Pound(*payload.WeightEstimate),
+	})
+
+	if response := savePPMTrips(h.HandlerContext, ppm, trips, nil); response != nil {
+		return response
+	}
+
+	ppmPayload, err := payloadForPPMModel(h.FileStorer(), *ppm)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return ppmop.NewCreatePPMTripCreated().WithPayload(ppmPayload)
+}
+
+// UpdatePPMTripHandler updates a trip of a PPM
+type UpdatePPMTripHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle is the handler
+func (h UpdatePPMTripHandler) Handle(params ppmop.UpdatePPMTripParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	tripID, _ := uuid.FromString(params.PpmTripID.String())
+
+	trip, err := models.FetchPPMTrip(h.DB(), session, tripID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	ppm, err := models.FetchPersonallyProcuredMove(h.DB(), session, trip.PersonallyProcuredMoveID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	trips, err := models.FetchPPMTrips(h.DB(), ppm.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.UpdatePPMTripPayload
+	for i := range trips {
+		if trips[i].ID == trip.ID {
+			trips[i].PickupPostalCode = *payload.PickupPostalCode
+			trips[i].DestinationPostalCode = *payload.DestinationPostalCode
+			trips[i].MoveDate = time.Time(*payload.MoveDate)
+			trips[i].WeightEstimate = unit.Pound(*payload.WeightEstimate)
+		}
+	}
+
+	if response := savePPMTrips(h.HandlerContext, ppm, trips, nil); response != nil {
+		return response
+	}
+
+	ppmPayload, err := payloadForPPMModel(h.FileStorer(), *ppm)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return ppmop.NewUpdatePPMTripOK().WithPayload(ppmPayload)
+}
+
+// DeletePPMTripHandler removes a trip from a PPM
+type DeletePPMTripHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle is the handler
+func (h DeletePPMTripHandler) Handle(params ppmop.DeletePPMTripParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	tripID, _ := uuid.FromString(params.PpmTripID.String())
+
+	trip, err := models.FetchPPMTrip(h.DB(), session, tripID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	ppm, err := models.FetchPersonallyProcuredMove(h.DB(), session, trip.PersonallyProcuredMoveID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	trips, err := models.FetchPPMTrips(h.DB(), ppm.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	// Later trips move up so the trips stay numbered without gaps
+	remaining := models.PPMTrips{}
+	for _, t := range trips {
+		if t.ID != trip.ID {
+			t.TripNumber = len(remaining) + 1
+			remaining = append(remaining, t)
+		}
+	}
+
+	if response := savePPMTrips(h.HandlerContext, ppm, remaining, trip); response != nil {
+		return response
+	}
+
+	ppmPayload, err := payloadForPPMModel(h.FileStorer(), *ppm)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return ppmop.NewDeletePPMTripOK().WithPayload(ppmPayload)
+}
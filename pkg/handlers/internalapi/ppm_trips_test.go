@@ -0,0 +1,117 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	ppmop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/ppm"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/testdatagen/scenario"
+)
+
+func (suite *HandlerSuite) TestPPMTripHandlers() {
+	scenario.RunRateEngineScenario1(suite.TestDB())
+
+	ppm := testdatagen.MakeDefaultPPM(suite.TestDB())
+	sm := ppm.Move.Orders.ServiceMember
+
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetPlanner(route.NewTestingPlanner(900))
+
+	// Add two trips
+	createHandler := CreatePPMTripHandler{context}
+	var ppmPayload *internalmessages.PersonallyProcuredMovePayload
+	for _, weight := range []int64{2000, 2100} {
+		req := suite.AuthenticateRequest(httptest.NewRequest("POST", "/fake/path", nil), sm)
+		response := createHandler.Handle(ppmop.CreatePPMTripParams{
+			HTTPRequest:              req,
+			PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+			CreatePPMTripPayload: &internalmessages.CreatePPMTripPayload{
+				PickupPostalCode:      swag.String("32168"),
+				DestinationPostalCode: swag.String("29401"),
+				MoveDate:              handlers.FmtDate(time.Now()),
+				WeightEstimate:        swag.Int64(weight),
+			},
+		})
+		suite.Assertions.IsType(&ppmop.CreatePPMTripCreated{}, response)
+		ppmPayload = response.(*ppmop.CreatePPMTripCreated).Payload
+	}
+
+	// The PPM estimates are the totals of its trips
+	suite.Equal(int64(4100), *ppmPayload.WeightEstimate)
+	suite.Equal(int64(1800), *ppmPayload.Mileage)
+	suite.True(*ppmPayload.IncentiveEstimateMin > 0)
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("GET", "/fake/path", nil), sm)
+	indexResponse := IndexPPMTripsHandler{context}.Handle(ppmop.IndexPPMTripsParams{
+		HTTPRequest:              req,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+	})
+	suite.Assertions.IsType(&ppmop.IndexPPMTripsOK{}, indexResponse)
+	trips := indexResponse.(*ppmop.IndexPPMTripsOK).Payload
+	suite.Len(trips, 2)
+	suite.Equal(int64(1), *trips[0].TripNumber)
+	suite.Equal(int64(2), *trips[1].TripNumber)
+	suite.NotNil(trips[0].IncentiveEstimate)
+
+	// Someone else can't remove a trip
+	otherSM := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	deleteParams := ppmop.DeletePPMTripParams{
+		HTTPRequest: suite.AuthenticateRequest(httptest.NewRequest("DELETE", "/fake/path", nil), otherSM),
+		PpmTripID:   *trips[0].ID,
+	}
+	deleteHandler := DeletePPMTripHandler{context}
+	suite.CheckResponseForbidden(deleteHandler.Handle(deleteParams))
+
+	// A weight ticket for the second trip follows it when it is renumbered
+	secondTripID := uuid.Must(uuid.FromString(trips[1].ID.String()))
+	weightTicket := testdatagen.MakeWeightTicketDocument(suite.TestDB(), testdatagen.Assertions{
+		MoveDocument: models.MoveDocument{
+			MoveID:                   ppm.MoveID,
+			Move:                     ppm.Move,
+			PersonallyProcuredMoveID: &ppm.ID,
+		},
+		WeightTicketDocument: models.WeightTicketDocument{TripNumber: 2, PPMTripID: &secondTripID},
+	})
+
+	// Removing the first trip renumbers the second
+	deleteParams.HTTPRequest = suite.AuthenticateRequest(httptest.NewRequest("DELETE", "/fake/path", nil), sm)
+	deleteResponse := deleteHandler.Handle(deleteParams)
+	suite.Assertions.IsType(&ppmop.DeletePPMTripOK{}, deleteResponse)
+	suite.Equal(int64(2100), *deleteResponse.(*ppmop.DeletePPMTripOK).Payload.WeightEstimate)
+
+	indexResponse = IndexPPMTripsHandler{context}.Handle(ppmop.IndexPPMTripsParams{
+		HTTPRequest:              req,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+	})
+	trips = indexResponse.(*ppmop.IndexPPMTripsOK).Payload
+	suite.Len(trips, 1)
+	suite.Equal(int64(1), *trips[0].TripNumber)
+	suite.Equal(int64(2100), *trips[0].WeightEstimate)
+
+	suite.Nil(suite.TestDB().Find(&weightTicket, weightTicket.ID))
+	suite.Equal(1, weightTicket.TripNumber)
+	suite.Equal(secondTripID, *weightTicket.PPMTripID)
+
+	// Trips can't change once payment has been requested
+	ppm.Status = models.PPMStatusPAYMENTREQUESTED
+	suite.MustSave(&ppm)
+	deleteParams.PpmTripID = *trips[0].ID
+	suite.CheckResponseBadRequest(deleteHandler.Handle(deleteParams))
+
+	// Removing the last trip clears the incentive estimates
+	ppm.Status = models.PPMStatusAPPROVED
+	suite.MustSave(&ppm)
+	deleteResponse = deleteHandler.Handle(deleteParams)
+	suite.Assertions.IsType(&ppmop.DeletePPMTripOK{}, deleteResponse)
+	suite.Nil(deleteResponse.(*ppmop.DeletePPMTripOK).Payload.IncentiveEstimateMin)
+	suite.Nil(deleteResponse.(*ppmop.DeletePPMTripOK).Payload.Mileage)
+}
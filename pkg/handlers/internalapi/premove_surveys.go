@@ -0,0 +1,93 @@
+package internalapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	premovesurveyop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/premove_surveys"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+func payloadForPremoveSurveySlotModel(slot models.PremoveSurveySlot) *internalmessages.PremoveSurveySlotPayload {
+	return &internalmessages.PremoveSurveySlotPayload{
+		ID:          handlers.FmtUUID(slot.ID),
+		ShipmentID:  handlers.FmtUUID(slot.ShipmentID),
+		StartsAt:    handlers.FmtDateTime(slot.StartsAt),
+		EndsAt:      handlers.FmtDateTime(slot.EndsAt),
+		Status:      internalmessages.PremoveSurveySlotStatus(slot.Status),
+		ConfirmedAt: handlers.FmtDateTimePtr(slot.ConfirmedAt),
+	}
+}
+
+// IndexPremoveSurveySlotsHandler returns the times proposed for a shipment's pre-move survey
+type IndexPremoveSurveySlotsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the slots if the user may access the shipment
+func (h IndexPremoveSurveySlotsHandler) Handle(params premovesurveyop.IndexPremoveSurveySlotsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+
+	shipment, err := models.FetchShipment(h.DB(), session, shipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	slots, err := models.FetchPremoveSurveySlots(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	slotPayloads := make(internalmessages.IndexPremoveSurveySlotsPayload, len(slots))
+	for i, slot := range slots {
+		slotPayloads[i] = payloadForPremoveSurveySlotModel(slot)
+	}
+	return premovesurveyop.NewIndexPremoveSurveySlotsOK().WithPayload(slotPayloads)
+}
+
+// ConfirmPremoveSurveySlotHandler books the pre-move survey at a time the TSP proposed
+type ConfirmPremoveSurveySlotHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle confirms the slot if the service member owns the shipment and notifies the TSP
+func (h ConfirmPremoveSurveySlotHandler) Handle(params premovesurveyop.ConfirmPremoveSurveySlotParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsServiceMember() {
+		return premovesurveyop.NewConfirmPremoveSurveySlotForbidden()
+	}
+	slotID, _ := uuid.FromString(params.PremoveSurveySlotID.String())
+
+	slot, err := models.FetchPremoveSurveySlot(h.DB(), slotID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipment, err := models.FetchShipment(h.DB(), session, slot.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.ConfirmPremoveSurveySlot(h.DB(), shipment, slot, time.Now())
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	err = h.NotificationSender().SendNotification(
+		notifications.NewPremoveSurveyConfirmed(h.DB(), h.Logger(), shipment.ID, slot.TransportationServiceProviderID),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to TSP", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return premovesurveyop.NewConfirmPremoveSurveySlotOK().WithPayload(payloadForPremoveSurveySlotModel(*slot))
+}
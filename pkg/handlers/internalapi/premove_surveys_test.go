@@ -0,0 +1,58 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+
+	premovesurveyop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/premove_surveys"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestConfirmPremoveSurveySlotHandler() {
+	now := time.Now()
+	shipment := testdatagen.MakeShipment(suite.TestDB(), testdatagen.Assertions{
+		Shipment: models.Shipment{Status: models.ShipmentStatusACCEPTED},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.TestDB())
+	slots := models.PremoveSurveySlots{
+		{StartsAt: now.AddDate(0, 0, 2), EndsAt: now.AddDate(0, 0, 2).Add(2 * time.Hour)},
+		{StartsAt: now.AddDate(0, 0, 3), EndsAt: now.AddDate(0, 0, 3).Add(2 * time.Hour)},
+	}
+	verrs, err := models.ProposePremoveSurveySlots(suite.TestDB(), shipment, tsp.ID, slots, now)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetNotificationSender(suite.TestNotificationSender())
+
+	req := suite.AuthenticateRequest(httptest.NewRequest("POST", "/fake/path", nil), shipment.ServiceMember)
+	confirmResponse := ConfirmPremoveSurveySlotHandler{context}.Handle(premovesurveyop.ConfirmPremoveSurveySlotParams{
+		HTTPRequest:         req,
+		PremoveSurveySlotID: strfmt.UUID(slots[0].ID.String()),
+	})
+	suite.Assertions.IsType(&premovesurveyop.ConfirmPremoveSurveySlotOK{}, confirmResponse)
+	suite.Equal(internalmessages.PremoveSurveySlotStatusCONFIRMED, confirmResponse.(*premovesurveyop.ConfirmPremoveSurveySlotOK).Payload.Status)
+
+	indexResponse := IndexPremoveSurveySlotsHandler{context}.Handle(premovesurveyop.IndexPremoveSurveySlotsParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+	})
+	suite.Assertions.IsType(&premovesurveyop.IndexPremoveSurveySlotsOK{}, indexResponse)
+	indexed := indexResponse.(*premovesurveyop.IndexPremoveSurveySlotsOK).Payload
+	suite.Len(indexed, 2)
+	suite.Equal(internalmessages.PremoveSurveySlotStatusDECLINED, indexed[1].Status)
+
+	// Another service member can't book the survey
+	other := testdatagen.MakeDefaultServiceMember(suite.TestDB())
+	req = suite.AuthenticateRequest(httptest.NewRequest("POST", "/fake/path", nil), other)
+	forbiddenResponse := ConfirmPremoveSurveySlotHandler{context}.Handle(premovesurveyop.ConfirmPremoveSurveySlotParams{
+		HTTPRequest:         req,
+		PremoveSurveySlotID: strfmt.UUID(slots[1].ID.String()),
+	})
+	suite.CheckResponseForbidden(forbiddenResponse)
+}
@@ -0,0 +1,211 @@
+package internalapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	queueop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/queues"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForQueueViewModel(view models.OfficeQueueView) (*internalmessages.QueueViewPayload, error) {
+	filters, err := view.MoveQueueFilters()
+	if err != nil {
+		return nil, err
+	}
+	sorts, err := view.MoveQueueSort()
+	if err != nil {
+		return nil, err
+	}
+
+	filtersPayload := internalmessages.MoveQueueFilters{
+		Branches:    filters.Branches,
+		Ranks:       filters.Ranks,
+		OrdersTypes: filters.OrdersTypes,
+		PpmStatuses: filters.PpmStatuses,
+		HhgStatuses: filters.HhgStatuses,
+	}
+	if filters.GBLOC != "" {
+		filtersPayload.Gbloc = swag.String(filters.GBLOC)
+	}
+	filtersPayload.MoveDateFrom = handlers.FmtDatePtr(filters.MoveDateFrom)
+	filtersPayload.MoveDateTo = handlers.FmtDatePtr(filters.MoveDateTo)
+	if filters.Assignee != "" {
+		filtersPayload.Assignee = swag.String(filters.Assignee)
+	}
+
+	sortPayload := []string{}
+	for _, sort := range sorts {
+		sortPayload = append(sortPayload, sort.String())
+	}
+
+	return &internalmessages.QueueViewPayload{
+		ID:        *handlers.FmtUUID(view.ID),
+		Name:      swag.String(view.Name),
+		QueueType: swag.String(view.QueueType),
+		Filters:   &filtersPayload,
+		Sort:      sortPayload,
+	}, nil
+}
+
+// applyQueueViewPayload copies a queue view payload onto a view
+func applyQueueViewPayload(view *models.OfficeQueueView, payload *internalmessages.QueueViewPayload) error {
+	view.Name = *payload.Name
+	view.QueueType = *payload.QueueType
+
+	filters := models.MoveQueueFilters{}
+	if payload.Filters != nil {
+		filters.Branches = payload.Filters.Branches
+		filters.Ranks = payload.Filters.Ranks
+		filters.OrdersTypes = payload.Filters.OrdersTypes
+		filters.PpmStatuses = payload.Filters.PpmStatuses
+		filters.HhgStatuses = payload.Filters.HhgStatuses
+		if payload.Filters.Gbloc != nil {
+			filters.GBLOC = *payload.Filters.Gbloc
+		}
+		if payload.Filters.MoveDateFrom != nil {
+			from := time.Time(*payload.Filters.MoveDateFrom)
+			filters.MoveDateFrom = &from
+		}
+		if payload.Filters.MoveDateTo != nil {
+			to := time.Time(*payload.Filters.MoveDateTo)
+			filters.MoveDateTo = &to
+		}
+		if payload.Filters.Assignee != nil {
+			filters.Assignee = *payload.Filters.Assignee
+		}
+	}
+	if err := view.SetMoveQueueFilters(filters); err != nil {
+		return err
+	}
+
+	sorts, err := models.ParseMoveQueueSort(payload.Sort)
+	if err != nil {
+		return err
+	}
+	view.SetMoveQueueSort(sorts)
+	return nil
+}
+
+func fetchQueueViewForParams(h handlers.HandlerContext, session *auth.Session, queueViewID strfmt.UUID) (*models.OfficeQueueView, error) {
+	id, _ := uuid.FromString(queueViewID.String())
+	return models.FetchOfficeQueueView(h.DB(), session, id)
+}
+
+// IndexQueueViewsHandler lists the office user's saved queue views
+type IndexQueueViewsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the office user's saved queue views
+func (h IndexQueueViewsHandler) Handle(params queueop.IndexQueueViewsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewIndexQueueViewsForbidden()
+	}
+
+	views, err := models.FetchOfficeQueueViews(h.DB(), session)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := internalmessages.IndexQueueViewsPayload{}
+	for _, view := range views {
+		viewPayload, err := payloadForQueueViewModel(view)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		payload = append(payload, viewPayload)
+	}
+	return queueop.NewIndexQueueViewsOK().WithPayload(payload)
+}
+
+// CreateQueueViewHandler saves a queue view
+type CreateQueueViewHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle saves a named queue view for the office user
+func (h CreateQueueViewHandler) Handle(params queueop.CreateQueueViewParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewCreateQueueViewForbidden()
+	}
+
+	view := models.OfficeQueueView{OfficeUserID: session.OfficeUserID}
+	if err := applyQueueViewPayload(&view, params.QueueView); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := h.DB().ValidateAndCreate(&view)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	payload, err := payloadForQueueViewModel(view)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return queueop.NewCreateQueueViewCreated().WithPayload(payload)
+}
+
+// UpdateQueueViewHandler updates a saved queue view
+type UpdateQueueViewHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle replaces a saved queue view
+func (h UpdateQueueViewHandler) Handle(params queueop.UpdateQueueViewParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewUpdateQueueViewForbidden()
+	}
+
+	view, err := fetchQueueViewForParams(h.HandlerContext, session, params.QueueViewID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if err := applyQueueViewPayload(view, params.QueueView); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := h.DB().ValidateAndUpdate(view)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	payload, err := payloadForQueueViewModel(*view)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return queueop.NewUpdateQueueViewOK().WithPayload(payload)
+}
+
+// DeleteQueueViewHandler deletes a saved queue view
+type DeleteQueueViewHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes a saved queue view
+func (h DeleteQueueViewHandler) Handle(params queueop.DeleteQueueViewParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewDeleteQueueViewForbidden()
+	}
+
+	view, err := fetchQueueViewForParams(h.HandlerContext, session, params.QueueViewID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if err := h.DB().Destroy(view); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return queueop.NewDeleteQueueViewNoContent()
+}
@@ -0,0 +1,58 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	queueop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/queues"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestQueueViewHandlers() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	createReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/queue_views", nil), officeUser)
+	createResponse := CreateQueueViewHandler{context}.Handle(queueop.CreateQueueViewParams{
+		HTTPRequest: createReq,
+		QueueView: &internalmessages.QueueViewPayload{
+			Name:      swag.String("Navy PPMs"),
+			QueueType: swag.String("ppm"),
+			Filters:   &internalmessages.MoveQueueFilters{Branches: []string{"NAVY"}},
+			Sort:      []string{"-move_date"},
+		},
+	})
+	suite.Assertions.IsType(&queueop.CreateQueueViewCreated{}, createResponse)
+	created := createResponse.(*queueop.CreateQueueViewCreated).Payload
+	suite.Equal([]string{"NAVY"}, created.Filters.Branches)
+	suite.Equal([]string{"-move_date"}, created.Sort)
+
+	// The view can be used to search its queue
+	searchReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/queues/ppm/moves", nil), officeUser)
+	searchResponse := SearchQueueHandler{context}.Handle(queueop.SearchQueueParams{
+		HTTPRequest: searchReq,
+		QueueType:   "new",
+		View:        &created.ID,
+	})
+	suite.Assertions.IsType(&queueop.SearchQueueOK{}, searchResponse)
+
+	// Other office users can't see or change it
+	otherOfficeUser := testdatagen.MakeOfficeUser(suite.TestDB(), testdatagen.Assertions{
+		User: models.User{LoginGovEmail: "other_office_user@example.com"},
+	})
+	deleteReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("DELETE", "/queue_views/id", nil), otherOfficeUser)
+	deleteResponse := DeleteQueueViewHandler{context}.Handle(queueop.DeleteQueueViewParams{
+		HTTPRequest: deleteReq,
+		QueueViewID: created.ID,
+	})
+	suite.CheckResponseForbidden(deleteResponse)
+
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/queue_views", nil), otherOfficeUser)
+	indexResponse := IndexQueueViewsHandler{context}.Handle(queueop.IndexQueueViewsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&queueop.IndexQueueViewsOK{}, indexResponse)
+	suite.Len(indexResponse.(*queueop.IndexQueueViewsOK).Payload, 0)
+}
@@ -15,11 +15,19 @@ func payloadForReimbursementModel(r *models.Reimbursement) *internalmessages.Rei
 	methodOfReceipt := internalmessages.MethodOfReceipt(r.MethodOfReceipt)
 	status := internalmessages.ReimbursementStatus(r.Status)
 
-	return &internalmessages.Reimbursement{
+	payload := internalmessages.Reimbursement{
 		ID:              strfmt.UUID(r.ID.String()),
 		MethodOfReceipt: &methodOfReceipt,
 		RequestedAmount: swag.Int64(int64(r.RequestedAmount)),
 		RequestedDate:   (*strfmt.Date)(r.RequestedDate),
 		Status:          &status,
+		PaidDate:        (*strfmt.Date)(r.PaidDate),
 	}
+	if r.ApprovedAmount != nil {
+		payload.ApprovedAmount = swag.Int64(r.ApprovedAmount.Int64())
+	}
+	if r.PaidAmount != nil {
+		payload.PaidAmount = swag.Int64(r.PaidAmount.Int64())
+	}
+	return &payload
 }
@@ -4,6 +4,7 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/rickar/cal"
 
 	"github.com/transcom/mymove/pkg/auth"
 	servicememberop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/service_members"
@@ -13,12 +14,12 @@ import (
 	"github.com/transcom/mymove/pkg/storage"
 )
 
-func payloadForServiceMemberModel(storer storage.FileStorer, serviceMember models.ServiceMember) *internalmessages.ServiceMemberPayload {
+func payloadForServiceMemberModel(storer storage.FileStorer, calendar *cal.Calendar, serviceMember models.ServiceMember) *internalmessages.ServiceMemberPayload {
 	var dutyStationPayload *internalmessages.DutyStationPayload
 	dutyStationPayload = payloadForDutyStationModel(serviceMember.DutyStation)
 	orders := make([]*internalmessages.Orders, len(serviceMember.Orders))
 	for i, order := range serviceMember.Orders {
-		orderPayload, _ := payloadForOrdersModel(storer, order)
+		orderPayload, _ := payloadForOrdersModel(storer, calendar, order)
 		orders[i] = orderPayload
 	}
 
@@ -136,7 +137,11 @@ func (h CreateServiceMemberHandler) Handle(params servicememberop.CreateServiceM
 		session.LastName = *(newServiceMember.LastName)
 	}
 	// And return
-	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), newServiceMember)
+	calendar, err := serviceMemberCalendar(h, newServiceMember.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), calendar, newServiceMember)
 	responder := servicememberop.NewCreateServiceMemberCreated().WithPayload(serviceMemberPayload)
 	return handlers.NewCookieUpdateResponder(params.HTTPRequest, h.CookieSecret(), h.NoSessionTimeout(), h.Logger(), responder)
 }
@@ -156,7 +161,11 @@ func (h ShowServiceMemberHandler) Handle(params servicememberop.ShowServiceMembe
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), serviceMember)
+	calendar, err := serviceMemberCalendar(h, serviceMember.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), calendar, serviceMember)
 	return servicememberop.NewShowServiceMemberOK().WithPayload(serviceMemberPayload)
 }
 
@@ -183,7 +192,11 @@ func (h PatchServiceMemberHandler) Handle(params servicememberop.PatchServiceMem
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
-	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), serviceMember)
+	calendar, err := serviceMemberCalendar(h, serviceMember.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	serviceMemberPayload := payloadForServiceMemberModel(h.FileStorer(), calendar, serviceMember)
 	return servicememberop.NewPatchServiceMemberOK().WithPayload(serviceMemberPayload)
 }
 
@@ -288,7 +301,11 @@ func (h ShowServiceMemberOrdersHandler) Handle(params servicememberop.ShowServic
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	orderPayload, err := payloadForOrdersModel(h.FileStorer(), order)
+	calendar, err := serviceMemberCalendar(h, order.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	orderPayload, err := payloadForOrdersModel(h.FileStorer(), calendar, order)
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
@@ -6,7 +6,11 @@ import (
 
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/rickar/cal"
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/auth"
@@ -17,9 +21,11 @@ import (
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/rateengine"
+	"github.com/transcom/mymove/pkg/rdd"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
-func payloadForShipmentModel(s models.Shipment) (*internalmessages.Shipment, error) {
+func payloadForShipmentModel(calendar *cal.Calendar, s models.Shipment) (*internalmessages.Shipment, error) {
 	// TODO: For now, we keep the Shipment structure the same but change where the CodeOfService
 	// TODO: is coming from.  Ultimately we should probably rework the structure below to more
 	// TODO: closely match the database structure.
@@ -36,7 +42,7 @@ func payloadForShipmentModel(s models.Shipment) (*internalmessages.Shipment, err
 
 	var moveDatesSummary internalmessages.ShipmentMoveDatesSummary
 	if s.RequestedPickupDate != nil && s.EstimatedPackDays != nil && s.EstimatedTransitDays != nil {
-		summary, err := calculateMoveDatesFromShipment(&s)
+		summary, err := calculateMoveDatesFromShipment(&s, calendar)
 		if err != nil {
 			return nil, err
 		}
@@ -74,6 +80,11 @@ func payloadForShipmentModel(s models.Shipment) (*internalmessages.Shipment, err
 		OriginalPackDate:     handlers.FmtDatePtr(s.OriginalPackDate),
 		MoveDatesSummary:     &moveDatesSummary,
 
+		// required delivery date
+		RequiredDeliveryDate:      handlers.FmtDatePtr(s.RequiredDeliveryDate),
+		RequiredDeliveryDateBasis: s.RequiredDeliveryDateBasisLines(),
+		DeliveredLate:             s.IsDeliveredLate(),
+
 		// calculated durations
 		EstimatedPackDays:    s.EstimatedPackDays,
 		EstimatedTransitDays: s.EstimatedTransitDays,
@@ -175,13 +186,18 @@ func (h CreateShipmentHandler) Handle(params shipmentop.CreateShipmentParams) mi
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := models.SaveShipmentAndAddresses(h.DB(), &newShipment)
+	audit := models.NewAuditEvent(session, nil, &newShipment, "create", "")
+	verrs, err := models.SaveShipmentAndAddresses(h.DB(), &newShipment, audit)
 
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
-	shipmentPayload, err := payloadForShipmentModel(newShipment)
+	calendar, err := serviceMemberCalendar(h, newShipment.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipmentPayload, err := payloadForShipmentModel(calendar, newShipment)
 	if err != nil {
 		h.Logger().Error("Error in shipment payload: ", zap.Error(err))
 	}
@@ -305,6 +321,7 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := *shipment
 
 	patchShipmentWithPayload(shipment, params.Shipment)
 	if err = updateShipmentDatesWithPayload(h, shipment, params.Shipment); err != nil {
@@ -316,13 +333,24 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 		patchShipmentWithPremoveSurveyFields(shipment, params.Shipment)
 	}
 
-	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment)
+	// The RDD follows the pickup date and weight estimates. The update isn't refused if it can't be recalculated.
+	calculator := rdd.NewCalculator(h.DB(), h.Logger(), h.Planner(), h.Calendars())
+	if err = calculator.UpdateIfChanged(before, shipment); err != nil {
+		h.Logger().Error("Error calculating required delivery date", zap.Error(err))
+	}
+
+	audit := models.NewAuditEvent(session, &before, shipment, "update", "")
+	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment, audit)
 
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
-	shipmentPayload, err := payloadForShipmentModel(*shipment)
+	calendar, err := serviceMemberCalendar(h, shipment.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipmentPayload, err := payloadForShipmentModel(calendar, *shipment)
 	if err != nil {
 		h.Logger().Error("Error in shipment payload: ", zap.Error(err))
 	}
@@ -337,7 +365,7 @@ func updateShipmentDatesWithPayload(h handlers.HandlerContext, shipment *models.
 
 	moveDate := time.Time(*payload.RequestedPickupDate)
 
-	summary, err := calculateMoveDatesFromMove(h.DB(), h.Planner(), shipment.MoveID, moveDate)
+	summary, err := calculateMoveDatesFromMove(h.DB(), h.Planner(), h.Calendars(), shipment.MoveID, moveDate)
 	if err != nil {
 		return nil
 	}
@@ -373,7 +401,11 @@ func (h GetShipmentHandler) Handle(params shipmentop.GetShipmentParams) middlewa
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	shipmentPayload, err := payloadForShipmentModel(*shipment)
+	calendar, err := serviceMemberCalendar(h, shipment.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipmentPayload, err := payloadForShipmentModel(calendar, *shipment)
 	if err != nil {
 		h.Logger().Error("Error in shipment payload: ", zap.Error(err))
 	}
@@ -400,17 +432,35 @@ func (h ApproveHHGHandler) Handle(params shipmentop.ApproveHHGParams) middleware
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := *shipment
 	err = shipment.Approve()
 	if err != nil {
 		h.Logger().Error("Attempted to approve HHG, got invalid transition", zap.Error(err), zap.String("shipment_status", string(shipment.Status)))
 		return handlers.ResponseForError(h.Logger(), err)
 	}
-	verrs, err := h.DB().ValidateAndUpdate(shipment)
+	var verrs *validate.Errors
+	err = h.DB().Transaction(func(tx *pop.Connection) error {
+		var err error
+		verrs, err = models.SaveWithAudit(tx, session, &before, shipment, "approve", "")
+		if err != nil {
+			return err
+		} else if verrs.HasAny() {
+			return verrs
+		}
+		return webhooks.NotifyShipmentTSP(tx, models.WebhookEventShipmentApproved, webhooks.EventData{
+			ShipmentID:     shipment.ID,
+			ShipmentStatus: string(shipment.Status),
+		})
+	})
 	if err != nil || verrs.HasAny() {
-		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		return handlers.ResponseForVErrors(h.Logger(), verrs, errors.Cause(err))
 	}
 
-	shipmentPayload, err := payloadForShipmentModel(*shipment)
+	calendar, err := serviceMemberCalendar(h, shipment.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipmentPayload, err := payloadForShipmentModel(calendar, *shipment)
 	if err != nil {
 		h.Logger().Error("Error in shipment payload: ", zap.Error(err))
 	}
@@ -436,17 +486,22 @@ func (h CompleteHHGHandler) Handle(params shipmentop.CompleteHHGParams) middlewa
 	if err != nil {
 		return handlers.ResponseForError(h.Logger(), err)
 	}
+	before := *shipment
 	err = shipment.Complete()
 	if err != nil {
 		h.Logger().Error("Attempted to complete HHG, got invalid transition", zap.Error(err), zap.String("shipment_status", string(shipment.Status)))
 		return handlers.ResponseForError(h.Logger(), err)
 	}
-	verrs, err := h.DB().ValidateAndUpdate(shipment)
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, shipment, "complete", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
-	shipmentPayload, err := payloadForShipmentModel(*shipment)
+	calendar, err := serviceMemberCalendar(h, shipment.ServiceMemberID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	shipmentPayload, err := payloadForShipmentModel(calendar, *shipment)
 	if err != nil {
 		h.Logger().Error("Error in shipment payload: ", zap.Error(err))
 	}
@@ -508,9 +563,27 @@ func (h ShipmentInvoiceHandler) Handle(params shipmentop.SendHHGInvoiceParams) m
 	// get response from gex --> use status as status for this invoice call
 	switch responseStatus {
 	case 200:
+		err = h.notifyInvoiceStatus(shipment.ID, models.InvoiceStatusSUBMITTED)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
 		return shipmentop.NewSendHHGInvoiceOK()
 	default:
 		h.Logger().Error("Invoice POST request to GEX failed", zap.Int("status", responseStatus))
+		err = h.notifyInvoiceStatus(shipment.ID, models.InvoiceStatusSUBMISSIONFAILURE)
+		if err != nil {
+			h.Logger().Error("problem recording invoice status webhook event", zap.Error(err))
+		}
 		return shipmentop.NewSendHHGInvoiceInternalServerError()
 	}
 }
+
+// notifyInvoiceStatus tells the shipment's TSP how submitting its invoice went. Sending the
+// invoice changes nothing in the database, so the webhook event is the only write and is
+// recorded on its own.
+func (h ShipmentInvoiceHandler) notifyInvoiceStatus(shipmentID uuid.UUID, status models.InvoiceStatus) error {
+	return webhooks.NotifyShipmentTSP(h.DB(), models.WebhookEventInvoiceStatusChanged, webhooks.EventData{
+		ShipmentID:    shipmentID,
+		InvoiceStatus: string(status),
+	})
+}
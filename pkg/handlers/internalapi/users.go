@@ -23,7 +23,8 @@ func (h ShowLoggedInUserHandler) Handle(params userop.ShowLoggedInUserParams) mi
 
 	if !session.IsServiceMember() {
 		userPayload := internalmessages.LoggedInUserPayload{
-			ID: handlers.FmtUUID(session.UserID),
+			ID:       handlers.FmtUUID(session.UserID),
+			Features: session.Features,
 		}
 		return userop.NewShowLoggedInUserOK().WithPayload(&userPayload)
 	}
@@ -92,9 +93,15 @@ func (h ShowLoggedInUserHandler) Handle(params userop.ShowLoggedInUserParams) mi
 		}
 	}
 
+	calendar, err := serviceMemberCalendar(h, serviceMember.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
 	userPayload := internalmessages.LoggedInUserPayload{
 		ID:            handlers.FmtUUID(session.UserID),
-		ServiceMember: payloadForServiceMemberModel(h.FileStorer(), serviceMember),
+		ServiceMember: payloadForServiceMemberModel(h.FileStorer(), calendar, serviceMember),
+		Features:      session.Features,
 	}
 	return userop.NewShowLoggedInUserOK().WithPayload(&userPayload)
 }
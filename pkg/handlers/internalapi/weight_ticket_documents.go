@@ -0,0 +1,87 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	movedocop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/move_docs"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/storage"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func payloadForWeightTicketDocumentModel(storer storage.FileStorer, weightTicketDocument models.WeightTicketDocument) (*internalmessages.MoveDocumentPayload, error) {
+	moveDocument := weightTicketDocument.MoveDocument
+	moveDocument.WeightTicketDocument = &weightTicketDocument
+	return payloadForMoveDocument(storer, moveDocument)
+}
+
+// CreateWeightTicketDocumentHandler creates a WeightTicketDocument
+type CreateWeightTicketDocumentHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle is the handler
+func (h CreateWeightTicketDocumentHandler) Handle(params movedocop.CreateWeightTicketDocumentParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	moveID, _ := uuid.FromString(params.MoveID.String())
+
+	// Validate that this move belongs to the current user
+	move, err := models.FetchMove(h.DB(), session, moveID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := params.CreateWeightTicketDocumentPayload
+
+	// Fetch uploads to confirm ownership
+	uploadIds := payload.UploadIds
+	if len(uploadIds) == 0 {
+		return movedocop.NewCreateWeightTicketDocumentBadRequest()
+	}
+
+	uploads := models.Uploads{}
+	for _, id := range uploadIds {
+		converted := uuid.Must(uuid.FromString(id.String()))
+		upload, err := models.FetchUpload(h.DB(), session, converted)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		uploads = append(uploads, upload)
+	}
+
+	// Enforce that the ppm's move_id matches our move
+	ppmID := uuid.Must(uuid.FromString(payload.PersonallyProcuredMoveID.String()))
+	ppm, err := models.FetchPersonallyProcuredMove(h.DB(), session, ppmID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if ppm.MoveID != moveID {
+		return movedocop.NewCreateWeightTicketDocumentBadRequest()
+	}
+
+	newWeightTicketDocument, verrs, err := move.CreateWeightTicketDocument(
+		h.DB(),
+		uploads,
+		ppmID,
+		*payload.Title,
+		payload.Notes,
+		int(*payload.TripNumber),
+		unit.Pound(*payload.EmptyWeight),
+		unit.Pound(*payload.FullWeight),
+	)
+
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	newPayload, err := payloadForWeightTicketDocumentModel(h.FileStorer(), *newWeightTicketDocument)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return movedocop.NewCreateWeightTicketDocumentOK().WithPayload(newPayload)
+}
@@ -0,0 +1,67 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+
+	movedocop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/move_docs"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	storageTest "github.com/transcom/mymove/pkg/storage/test"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestCreateWeightTicketDocumentHandler() {
+	ppm := testdatagen.MakeDefaultPPM(suite.TestDB())
+	sm := ppm.Move.Orders.ServiceMember
+
+	upload := testdatagen.MakeUpload(suite.TestDB(), testdatagen.Assertions{
+		Upload: models.Upload{
+			UploaderID: sm.UserID,
+		},
+	})
+	upload.DocumentID = nil
+	suite.MustSave(&upload)
+
+	request := httptest.NewRequest("POST", "/fake/path", nil)
+	request = suite.AuthenticateRequest(request, sm)
+
+	params := movedocop.CreateWeightTicketDocumentParams{
+		HTTPRequest: request,
+		MoveID:      strfmt.UUID(ppm.MoveID.String()),
+		CreateWeightTicketDocumentPayload: &internalmessages.CreateWeightTicketDocumentPayload{
+			PersonallyProcuredMoveID: handlers.FmtUUID(ppm.ID),
+			UploadIds:                []strfmt.UUID{*handlers.FmtUUID(upload.ID)},
+			Title:                    handlers.FmtString("weight_ticket.pdf"),
+			TripNumber:               handlers.FmtInt64(1),
+			EmptyWeight:              handlers.FmtInt64(3000),
+			FullWeight:               handlers.FmtInt64(5200),
+		},
+	}
+
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetFileStorer(storageTest.NewFakeS3Storage(true))
+	handler := CreateWeightTicketDocumentHandler{context}
+	response := handler.Handle(params)
+
+	suite.IsNotErrResponse(response)
+	payload := response.(*movedocop.CreateWeightTicketDocumentOK).Payload
+	suite.Equal(internalmessages.MoveDocumentTypeWEIGHTTICKET, payload.MoveDocumentType)
+	suite.Equal(internalmessages.MoveDocumentStatusAWAITINGREVIEW, payload.Status)
+	suite.Equal(int64(1), *payload.TripNumber)
+	suite.Equal(int64(5200), *payload.FullWeight)
+
+	// A full weight that isn't above the empty weight is rejected
+	params.CreateWeightTicketDocumentPayload.FullWeight = handlers.FmtInt64(2000)
+	response = handler.Handle(params)
+	suite.IsType(&handlers.ValidationErrorsResponse{}, response)
+
+	// Service members can't add weight tickets to someone else's PPM
+	otherPPM := testdatagen.MakeDefaultPPM(suite.TestDB())
+	params.CreateWeightTicketDocumentPayload.PersonallyProcuredMoveID = handlers.FmtUUID(otherPPM.ID)
+	params.CreateWeightTicketDocumentPayload.FullWeight = handlers.FmtInt64(5200)
+	response = handler.Handle(params)
+	suite.CheckResponseForbidden(response)
+}
@@ -0,0 +1,139 @@
+package internalapi
+
+import (
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	queueop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/queues"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForWorkClaimModel(claim models.WorkClaim) *internalmessages.WorkClaimPayload {
+	var expiresAt *strfmt.DateTime
+	if claim.ExpiresAt != nil {
+		expiresAt = handlers.FmtDateTime(*claim.ExpiresAt)
+	}
+	return &internalmessages.WorkClaimPayload{
+		ID:                     handlers.FmtUUID(claim.ID),
+		RecordType:             internalmessages.WorkClaimRecordType(claim.RecordType),
+		RecordID:               handlers.FmtUUID(claim.RecordID),
+		OfficeUserID:           handlers.FmtUUID(claim.OfficeUserID),
+		OfficeUserName:         handlers.FmtString(claim.OfficeUser.LastName + ", " + claim.OfficeUser.FirstName),
+		AssignedByOfficeUserID: handlers.FmtUUIDPtr(claim.AssignedByOfficeUserID),
+		ExpiresAt:              expiresAt,
+		CreatedAt:              handlers.FmtDateTime(claim.CreatedAt),
+	}
+}
+
+// IndexWorkClaimsHandler lists active work claims
+type IndexWorkClaimsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the active claim on a move or PPM, or an office user's active claims
+func (h IndexWorkClaimsHandler) Handle(params queueop.IndexWorkClaimsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewIndexWorkClaimsForbidden()
+	}
+
+	claims := models.WorkClaims{}
+	switch {
+	case params.RecordType != nil && params.RecordID != nil:
+		recordID, _ := uuid.FromString(params.RecordID.String())
+		claim, err := models.FetchActiveWorkClaim(h.DB(), session, models.WorkClaimRecordType(*params.RecordType), recordID)
+		if err == nil {
+			claims = append(claims, *claim)
+		} else if err != models.ErrFetchNotFound {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	case params.RecordType != nil || params.RecordID != nil:
+		return queueop.NewIndexWorkClaimsBadRequest()
+	default:
+		officeUserID := session.OfficeUserID
+		if params.OfficeUserID != nil {
+			officeUserID, _ = uuid.FromString(params.OfficeUserID.String())
+		}
+		var err error
+		claims, err = models.FetchWorkClaimsForOfficeUser(h.DB(), officeUserID)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	}
+
+	payload := internalmessages.IndexWorkClaimsPayload{}
+	for _, claim := range claims {
+		payload = append(payload, payloadForWorkClaimModel(claim))
+	}
+	return queueop.NewIndexWorkClaimsOK().WithPayload(payload)
+}
+
+// ClaimWorkHandler claims or assigns a move or PPM
+type ClaimWorkHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle claims a move or PPM for the office user, or assigns it to another office user for supervisors
+func (h ClaimWorkHandler) Handle(params queueop.ClaimWorkParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewClaimWorkForbidden()
+	}
+
+	payload := params.WorkClaim
+	recordType := models.WorkClaimRecordType(payload.RecordType)
+	// #nosec UUID is pattern matched by swagger and will be ok
+	recordID, _ := uuid.FromString(payload.RecordID.String())
+
+	var claim *models.WorkClaim
+	var verrs *validate.Errors
+	var err error
+	if payload.OfficeUserID != nil {
+		officeUserID, _ := uuid.FromString(payload.OfficeUserID.String())
+		claim, verrs, err = models.AssignWork(h.DB(), session, recordType, recordID, officeUserID)
+	} else {
+		claim, verrs, err = models.ClaimWork(h.DB(), session, recordType, recordID)
+	}
+	if err != nil || verrs.HasAny() {
+		if err == models.ErrWorkClaimed {
+			h.Logger().Info("Work already claimed",
+				zap.String("work_claim_id", claim.ID.String()),
+				zap.String("claimed_by_office_user_id", claim.OfficeUserID.String()))
+		}
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	return queueop.NewClaimWorkCreated().WithPayload(payloadForWorkClaimModel(*claim))
+}
+
+// ReleaseWorkHandler releases a work claim
+type ReleaseWorkHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle releases a work claim so that other office users can pick up the work
+func (h ReleaseWorkHandler) Handle(params queueop.ReleaseWorkParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsOfficeUser() {
+		return queueop.NewReleaseWorkForbidden()
+	}
+
+	// #nosec UUID is pattern matched by swagger and will be ok
+	claimID, _ := uuid.FromString(params.WorkClaimID.String())
+	claim, err := models.FetchWorkClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	verrs, err := models.ReleaseWork(h.DB(), session, claim)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return queueop.NewReleaseWorkNoContent()
+}
@@ -0,0 +1,112 @@
+package internalapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	officeop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/office"
+	queueop "github.com/transcom/mymove/pkg/gen/internalapi/internaloperations/queues"
+	"github.com/transcom/mymove/pkg/gen/internalmessages"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestWorkClaimHandlers() {
+	ppm := testdatagen.MakePPM(suite.TestDB(), testdatagen.Assertions{
+		PersonallyProcuredMove: models.PersonallyProcuredMove{
+			Status: models.PPMStatusSUBMITTED,
+		},
+	})
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.TestDB())
+	otherOfficeUser := testdatagen.MakeOfficeUser(suite.TestDB(), testdatagen.Assertions{
+		User: models.User{LoginGovEmail: "other_office_user@example.com"},
+	})
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetNotificationSender(suite.TestNotificationSender())
+	recordType := internalmessages.WorkClaimRecordTypePersonallyProcuredMove
+
+	claimReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/work_claims", nil), officeUser)
+	claimResponse := ClaimWorkHandler{context}.Handle(queueop.ClaimWorkParams{
+		HTTPRequest: claimReq,
+		WorkClaim: &internalmessages.CreateWorkClaimPayload{
+			RecordType: recordType,
+			RecordID:   handlers.FmtUUID(ppm.ID),
+		},
+	})
+	suite.Assertions.IsType(&queueop.ClaimWorkCreated{}, claimResponse)
+	claim := claimResponse.(*queueop.ClaimWorkCreated).Payload
+	suite.Equal(officeUser.ID.String(), claim.OfficeUserID.String())
+	suite.NotNil(claim.ExpiresAt)
+
+	// Another office user can't claim or approve the PPM
+	otherClaimReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/work_claims", nil), otherOfficeUser)
+	otherClaimResponse := ClaimWorkHandler{context}.Handle(queueop.ClaimWorkParams{
+		HTTPRequest: otherClaimReq,
+		WorkClaim: &internalmessages.CreateWorkClaimPayload{
+			RecordType: recordType,
+			RecordID:   handlers.FmtUUID(ppm.ID),
+		},
+	})
+	suite.CheckResponseConflict(otherClaimResponse)
+
+	approveReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("POST", "/personally_procured_moves/some_id/approve", nil), otherOfficeUser)
+	approveResponse := ApprovePPMHandler{context}.Handle(officeop.ApprovePPMParams{
+		HTTPRequest:              approveReq,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+	})
+	suite.CheckResponseConflict(approveResponse)
+
+	// Overriding requires a supervisor
+	overrideResponse := ApprovePPMHandler{context}.Handle(officeop.ApprovePPMParams{
+		HTTPRequest:              approveReq,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+		Override:                 swag.Bool(true),
+	})
+	suite.CheckResponseForbidden(overrideResponse)
+
+	// The claim shows up for the record and in the queue
+	indexReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/work_claims", nil), otherOfficeUser)
+	indexResponse := IndexWorkClaimsHandler{context}.Handle(queueop.IndexWorkClaimsParams{
+		HTTPRequest: indexReq,
+		RecordType:  swag.String(string(recordType)),
+		RecordID:    handlers.FmtUUID(ppm.ID),
+	})
+	suite.Assertions.IsType(&queueop.IndexWorkClaimsOK{}, indexResponse)
+	suite.Len(indexResponse.(*queueop.IndexWorkClaimsOK).Payload, 1)
+
+	searchReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("GET", "/queues/all/moves", nil), officeUser)
+	searchResponse := SearchQueueHandler{context}.Handle(queueop.SearchQueueParams{
+		HTTPRequest: searchReq,
+		QueueType:   "all",
+		Assignee:    swag.String(models.MoveQueueAssignedToMe),
+	})
+	suite.Assertions.IsType(&queueop.SearchQueueOK{}, searchResponse)
+	items := searchResponse.(*queueop.SearchQueueOK).Payload.Items
+	suite.Len(items, 1)
+	suite.Equal(ppm.MoveID.String(), items[0].ID.String())
+	suite.Equal(officeUser.ID.String(), items[0].AssigneeID.String())
+
+	// Only the claimant can release it
+	releaseReq := suite.AuthenticateOfficeRequest(httptest.NewRequest("DELETE", "/work_claims/id", nil), otherOfficeUser)
+	releaseResponse := ReleaseWorkHandler{context}.Handle(queueop.ReleaseWorkParams{
+		HTTPRequest: releaseReq,
+		WorkClaimID: *claim.ID,
+	})
+	suite.CheckResponseForbidden(releaseResponse)
+
+	releaseReq = suite.AuthenticateOfficeRequest(httptest.NewRequest("DELETE", "/work_claims/id", nil), officeUser)
+	releaseResponse = ReleaseWorkHandler{context}.Handle(queueop.ReleaseWorkParams{
+		HTTPRequest: releaseReq,
+		WorkClaimID: *claim.ID,
+	})
+	suite.Assertions.IsType(&queueop.ReleaseWorkNoContent{}, releaseResponse)
+
+	approveResponse = ApprovePPMHandler{context}.Handle(officeop.ApprovePPMParams{
+		HTTPRequest:              approveReq,
+		PersonallyProcuredMoveID: strfmt.UUID(ppm.ID.String()),
+	})
+	suite.Assertions.IsType(&officeop.ApprovePPMOK{}, approveResponse)
+}
@@ -39,8 +39,33 @@ func NewPublicAPIHandler(context handlers.HandlerContext) http.Handler {
 	publicAPI.ShipmentsRejectShipmentHandler = RejectShipmentHandler{context}
 	publicAPI.ShipmentsTransportShipmentHandler = TransportShipmentHandler{context}
 	publicAPI.ShipmentsDeliverShipmentHandler = DeliverShipmentHandler{context}
+	publicAPI.ShipmentsSyncShipmentsHandler = SyncShipmentsHandler{context}
 
 	publicAPI.ShipmentsCreateGovBillOfLadingHandler = CreateGovBillOfLadingHandler{context}
+	publicAPI.ShipmentsGetShipmentClaimsHandler = GetShipmentClaimsHandler{context}
+
+	// Claims
+	publicAPI.ClaimsReviewClaimHandler = ReviewClaimHandler{context}
+	publicAPI.ClaimsOfferSettlementForClaimHandler = OfferSettlementForClaimHandler{context}
+	publicAPI.ClaimsDenyClaimHandler = DenyClaimHandler{context}
+
+	// Pre-move surveys
+	publicAPI.PremoveSurveysIndexPremoveSurveySlotsHandler = IndexPremoveSurveySlotsHandler{context}
+	publicAPI.PremoveSurveysProposePremoveSurveySlotsHandler = ProposePremoveSurveySlotsHandler{context}
+	publicAPI.PremoveSurveysIndexPremoveSurveyItemsHandler = IndexPremoveSurveyItemsHandler{context}
+	publicAPI.PremoveSurveysCreatePremoveSurveyItemHandler = CreatePremoveSurveyItemHandler{context}
+	publicAPI.PremoveSurveysUpdatePremoveSurveyItemHandler = UpdatePremoveSurveyItemHandler{context}
+	publicAPI.PremoveSurveysDeletePremoveSurveyItemHandler = DeletePremoveSurveyItemHandler{context}
+	publicAPI.PremoveSurveysCreatePremoveSurveyReportHandler = CreatePremoveSurveyReportHandler{context}
+
+	// Inventories
+	publicAPI.InventoriesGetShipmentInventoryHandler = GetShipmentInventoryHandler{context}
+	publicAPI.InventoriesCreateShipmentInventoryHandler = CreateShipmentInventoryHandler{context}
+	publicAPI.InventoriesCreateShipmentInventoryItemHandler = CreateShipmentInventoryItemHandler{context}
+	publicAPI.InventoriesUpdateShipmentInventoryItemHandler = UpdateShipmentInventoryItemHandler{context}
+	publicAPI.InventoriesRecordInventoryDeliveryExceptionHandler = RecordInventoryDeliveryExceptionHandler{context}
+	publicAPI.InventoriesSignShipmentInventoryHandler = SignShipmentInventoryHandler{context}
+	publicAPI.InventoriesCreateShipmentInventoryReportHandler = CreateShipmentInventoryReportHandler{context}
 
 	// Accessorials
 	publicAPI.AccessorialsGetShipmentLineItemsHandler = GetShipmentLineItemsHandler{context}
@@ -57,6 +82,21 @@ func NewPublicAPIHandler(context handlers.HandlerContext) http.Handler {
 	publicAPI.ServiceAgentsCreateServiceAgentHandler = CreateServiceAgentHandler{context}
 	publicAPI.ServiceAgentsPatchServiceAgentHandler = PatchServiceAgentHandler{context}
 
+	// Capacities
+	publicAPI.CapacitiesIndexTSPCapacitiesHandler = IndexTSPCapacitiesHandler{context}
+	publicAPI.CapacitiesCreateTSPCapacityHandler = CreateTSPCapacityHandler{context}
+	publicAPI.CapacitiesDeleteTSPCapacityHandler = DeleteTSPCapacityHandler{context}
+
+	// API clients
+	publicAPI.APIClientsIndexAPIClientsHandler = IndexAPIClientsHandler{context}
+	publicAPI.APIClientsCreateAPIClientHandler = CreateAPIClientHandler{context}
+	publicAPI.APIClientsRevokeAPIClientHandler = RevokeAPIClientHandler{context}
+
+	// Webhooks
+	publicAPI.WebhooksIndexWebhookSubscriptionsHandler = IndexWebhookSubscriptionsHandler{context}
+	publicAPI.WebhooksCreateWebhookSubscriptionHandler = CreateWebhookSubscriptionHandler{context}
+	publicAPI.WebhooksDeleteWebhookSubscriptionHandler = DeleteWebhookSubscriptionHandler{context}
+
 	// TSPs
 	publicAPI.TspsIndexTSPsHandler = TspsIndexTSPsHandler{context}
 	publicAPI.TspsGetTspShipmentsHandler = TspsGetTspShipmentsHandler{context}
@@ -0,0 +1,140 @@
+package publicapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	apiclientop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/api_clients"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForAPIClientModel(client models.APIClient) *apimessages.APIClientPayload {
+	return &apimessages.APIClientPayload{
+		ID:                *handlers.FmtUUID(client.ID),
+		Name:              swag.String(client.Name),
+		Subject:           swag.StringValue(client.Subject),
+		Sha256Digest:      swag.StringValue(client.Sha256Digest),
+		RequestsPerMinute: int64(client.RequestsPerMinute),
+		RevokedAt:         handlers.FmtDateTimePtr(client.RevokedAt),
+		CreatedAt:         strfmt.DateTime(client.CreatedAt),
+	}
+}
+
+// fetchTspUserForAPIClients returns the logged in TSP user, if they may manage API clients. Clients
+// can't manage each other, so that a leaked certificate can't be used to register more.
+func fetchTspUserForAPIClients(h handlers.HandlerContext, session *auth.Session) (*models.TspUser, bool) {
+	if !session.IsTspUser() || session.IsAPIClient() {
+		return nil, false
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return nil, false
+	}
+	return tspUser, true
+}
+
+// IndexAPIClientsHandler lists the API clients a TSP registered
+type IndexAPIClientsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the API clients of the logged in user's TSP
+func (h IndexAPIClientsHandler) Handle(params apiclientop.IndexAPIClientsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, ok := fetchTspUserForAPIClients(h, session)
+	if !ok {
+		return apiclientop.NewIndexAPIClientsForbidden()
+	}
+
+	clients, err := models.FetchAPIClients(h.DB(), tspUser.TransportationServiceProviderID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := make(apimessages.IndexAPIClientsPayload, len(clients))
+	for i, client := range clients {
+		payload[i] = payloadForAPIClientModel(client)
+	}
+	return apiclientop.NewIndexAPIClientsOK().WithPayload(payload)
+}
+
+// CreateAPIClientHandler registers an API client
+type CreateAPIClientHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle registers a client certificate that makes requests as the logged in user
+func (h CreateAPIClientHandler) Handle(params apiclientop.CreateAPIClientParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, ok := fetchTspUserForAPIClients(h, session)
+	if !ok {
+		return apiclientop.NewCreateAPIClientForbidden()
+	}
+
+	payload := params.Payload
+	client := models.APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            *payload.Name,
+		RequestsPerMinute:               models.DefaultAPIClientRequestsPerMinute,
+	}
+	if payload.Certificate != "" {
+		cert, err := models.ParseClientCertificate(payload.Certificate)
+		if err != nil {
+			h.Logger().Info("Invalid client certificate", zap.Error(err))
+			return apiclientop.NewCreateAPIClientBadRequest()
+		}
+		client.Sha256Digest = swag.String(models.CertificateFingerprint(cert))
+	}
+	if payload.Subject != "" {
+		client.Subject = swag.String(payload.Subject)
+	}
+
+	verrs, err := h.DB().ValidateAndCreate(&client)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("API client registered",
+		zap.String("api_client_id", client.ID.String()),
+		zap.String("tsp_user_id", tspUser.ID.String()))
+	return apiclientop.NewCreateAPIClientCreated().WithPayload(payloadForAPIClientModel(client))
+}
+
+// RevokeAPIClientHandler revokes an API client
+type RevokeAPIClientHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle revokes the API client if the logged in user's TSP registered it
+func (h RevokeAPIClientHandler) Handle(params apiclientop.RevokeAPIClientParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, ok := fetchTspUserForAPIClients(h, session)
+	if !ok {
+		return apiclientop.NewRevokeAPIClientForbidden()
+	}
+
+	clientID, _ := uuid.FromString(params.APIClientID.String())
+	client, err := models.FetchAPIClient(h.DB(), tspUser.TransportationServiceProviderID, clientID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	verrs, err := models.RevokeAPIClient(h.DB(), client, time.Now())
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	h.Logger().Info("API client revoked",
+		zap.String("api_client_id", client.ID.String()),
+		zap.String("tsp_user_id", tspUser.ID.String()))
+	return apiclientop.NewRevokeAPIClientOK().WithPayload(payloadForAPIClientModel(*client))
+}
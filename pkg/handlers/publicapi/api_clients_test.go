@@ -0,0 +1,77 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	apiclientop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/api_clients"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestAPIClientHandlers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	cert, certPEM := testdatagen.MakeClientCertificate("dispatch.example.com")
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/api_clients", nil), tspUser)
+	invalidResponse := CreateAPIClientHandler{context}.Handle(apiclientop.CreateAPIClientParams{
+		HTTPRequest: req,
+		Payload: &apimessages.APIClientPayload{
+			Name:        swag.String("Dispatch system"),
+			Certificate: "not a certificate",
+		},
+	})
+	suite.Assertions.IsType(&apiclientop.CreateAPIClientBadRequest{}, invalidResponse)
+
+	createResponse := CreateAPIClientHandler{context}.Handle(apiclientop.CreateAPIClientParams{
+		HTTPRequest: req,
+		Payload: &apimessages.APIClientPayload{
+			Name:        swag.String("Dispatch system"),
+			Certificate: certPEM,
+		},
+	})
+	suite.Assertions.IsType(&apiclientop.CreateAPIClientCreated{}, createResponse)
+	created := createResponse.(*apiclientop.CreateAPIClientCreated).Payload
+	suite.Equal(models.CertificateFingerprint(cert), created.Sha256Digest)
+	suite.Equal(int64(models.DefaultAPIClientRequestsPerMinute), created.RequestsPerMinute)
+
+	// A certificate identifies one client
+	duplicateResponse := CreateAPIClientHandler{context}.Handle(apiclientop.CreateAPIClientParams{
+		HTTPRequest: req,
+		Payload: &apimessages.APIClientPayload{
+			Name:        swag.String("Dispatch system again"),
+			Certificate: certPEM,
+		},
+	})
+	suite.IsType(&handlers.ValidationErrorsResponse{}, duplicateResponse)
+
+	indexReq := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/api_clients", nil), tspUser)
+	indexResponse := IndexAPIClientsHandler{context}.Handle(apiclientop.IndexAPIClientsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&apiclientop.IndexAPIClientsOK{}, indexResponse)
+	suite.Len(indexResponse.(*apiclientop.IndexAPIClientsOK).Payload, 1)
+
+	// API clients can't manage clients
+	clientReq := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/api_clients", nil), tspUser)
+	session := auth.SessionFromRequestContext(clientReq)
+	session.APIClientID = uuid.FromStringOrNil(created.ID.String())
+	suite.CheckResponseForbidden(IndexAPIClientsHandler{context}.Handle(apiclientop.IndexAPIClientsParams{HTTPRequest: clientReq}))
+
+	// Other TSPs can't revoke it
+	revokeParams := apiclientop.RevokeAPIClientParams{APIClientID: created.ID}
+	otherTspUser := testdatagen.MakeTspUser(suite.TestDB(), testdatagen.Assertions{
+		User: models.User{LoginGovEmail: "other_tsp@example.com"},
+	})
+	revokeParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/api_clients/id", nil), otherTspUser)
+	suite.CheckResponseForbidden(RevokeAPIClientHandler{context}.Handle(revokeParams))
+
+	revokeParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/api_clients/id", nil), tspUser)
+	revokeResponse := RevokeAPIClientHandler{context}.Handle(revokeParams)
+	suite.Assertions.IsType(&apiclientop.RevokeAPIClientOK{}, revokeResponse)
+	suite.NotNil(revokeResponse.(*apiclientop.RevokeAPIClientOK).Payload.RevokedAt)
+}
@@ -0,0 +1,125 @@
+package publicapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	capacityop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/capacities"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForTSPCapacityModel(capacity models.TSPCapacity) *apimessages.TSPCapacityPayload {
+	return &apimessages.TSPCapacityPayload{
+		ID:                      *handlers.FmtUUID(capacity.ID),
+		TrafficDistributionList: payloadForTrafficDistributionListModel(&capacity.TrafficDistributionList),
+		StartDate:               handlers.FmtDate(capacity.StartDate),
+		EndDate:                 handlers.FmtDate(capacity.EndDate),
+		DailyShipments:          swag.Int64(int64(capacity.DailyShipments)),
+	}
+}
+
+// IndexTSPCapacitiesHandler lists the capacity a TSP has declared
+type IndexTSPCapacitiesHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the capacity declared by the TSP of the logged in user
+func (h IndexTSPCapacitiesHandler) Handle(params capacityop.IndexTSPCapacitiesParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return capacityop.NewIndexTSPCapacitiesForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return capacityop.NewIndexTSPCapacitiesForbidden()
+	}
+
+	capacities, err := models.FetchTSPCapacities(h.DB(), tspUser.TransportationServiceProviderID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := make(apimessages.IndexTSPCapacitiesPayload, len(capacities))
+	for i, capacity := range capacities {
+		payload[i] = payloadForTSPCapacityModel(capacity)
+	}
+	return capacityop.NewIndexTSPCapacitiesOK().WithPayload(payload)
+}
+
+// CreateTSPCapacityHandler declares a TSP's capacity in a TDL
+type CreateTSPCapacityHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle declares the capacity for the TSP of the logged in user
+func (h CreateTSPCapacityHandler) Handle(params capacityop.CreateTSPCapacityParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return capacityop.NewCreateTSPCapacityForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return capacityop.NewCreateTSPCapacityForbidden()
+	}
+
+	payload := params.Payload
+	tdl, err := models.FetchTDL(h.DB(),
+		*payload.TrafficDistributionList.SourceRateArea,
+		*payload.TrafficDistributionList.DestinationRegion,
+		*payload.TrafficDistributionList.CodeOfService)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	capacity := models.TSPCapacity{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TrafficDistributionListID:       tdl.ID,
+		TrafficDistributionList:         tdl,
+		StartDate:                       time.Time(*payload.StartDate),
+		EndDate:                         time.Time(*payload.EndDate),
+		DailyShipments:                  int(*payload.DailyShipments),
+	}
+	verrs, err := h.DB().ValidateAndCreate(&capacity)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	return capacityop.NewCreateTSPCapacityCreated().WithPayload(payloadForTSPCapacityModel(capacity))
+}
+
+// DeleteTSPCapacityHandler withdraws a declared capacity
+type DeleteTSPCapacityHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes the capacity if the logged in user's TSP declared it
+func (h DeleteTSPCapacityHandler) Handle(params capacityop.DeleteTSPCapacityParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return capacityop.NewDeleteTSPCapacityForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return capacityop.NewDeleteTSPCapacityForbidden()
+	}
+
+	capacityID, _ := uuid.FromString(params.CapacityID.String())
+	capacity, err := models.FetchTSPCapacity(h.DB(), tspUser.TransportationServiceProviderID, capacityID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if err := h.DB().Destroy(capacity); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return capacityop.NewDeleteTSPCapacityNoContent()
+}
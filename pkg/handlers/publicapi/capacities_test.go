@@ -0,0 +1,52 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	capacityop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/capacities"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestTSPCapacityHandlers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.TestDB())
+	tdl := testdatagen.MakeDefaultTDL(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/capacities", nil), tspUser)
+	createResponse := CreateTSPCapacityHandler{context}.Handle(capacityop.CreateTSPCapacityParams{
+		HTTPRequest: req,
+		Payload: &apimessages.TSPCapacityPayload{
+			TrafficDistributionList: payloadForTrafficDistributionListModel(&tdl),
+			StartDate:               handlers.FmtDate(time.Date(2019, time.May, 15, 0, 0, 0, 0, time.UTC)),
+			EndDate:                 handlers.FmtDate(time.Date(2019, time.September, 30, 0, 0, 0, 0, time.UTC)),
+			DailyShipments:          swag.Int64(12),
+		},
+	})
+	suite.Assertions.IsType(&capacityop.CreateTSPCapacityCreated{}, createResponse)
+	created := createResponse.(*capacityop.CreateTSPCapacityCreated).Payload
+	suite.Equal(int64(12), *created.DailyShipments)
+
+	indexReq := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/capacities", nil), tspUser)
+	indexResponse := IndexTSPCapacitiesHandler{context}.Handle(capacityop.IndexTSPCapacitiesParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&capacityop.IndexTSPCapacitiesOK{}, indexResponse)
+	suite.Len(indexResponse.(*capacityop.IndexTSPCapacitiesOK).Payload, 1)
+
+	// Other TSPs can't withdraw it
+	deleteParams := capacityop.DeleteTSPCapacityParams{CapacityID: strfmt.UUID(created.ID.String())}
+	otherTspUser := testdatagen.MakeTspUser(suite.TestDB(), testdatagen.Assertions{
+		User: models.User{LoginGovEmail: "other_tsp@example.com"},
+	})
+	deleteParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/capacities/id", nil), otherTspUser)
+	suite.CheckResponseForbidden(DeleteTSPCapacityHandler{context}.Handle(deleteParams))
+
+	deleteParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/capacities/id", nil), tspUser)
+	deleteResponse := DeleteTSPCapacityHandler{context}.Handle(deleteParams)
+	suite.Assertions.IsType(&capacityop.DeleteTSPCapacityNoContent{}, deleteResponse)
+}
@@ -0,0 +1,175 @@
+package publicapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	claimop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/claims"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/storage"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func payloadForClaimModel(storer storage.FileStorer, claim models.Claim) (*apimessages.Claim, error) {
+	documentPayload, err := payloadForDocumentModel(storer, claim.Document)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*apimessages.ClaimItem, len(claim.Items))
+	for i, item := range claim.Items {
+		itemPayload := &apimessages.ClaimItem{
+			ID:            handlers.FmtUUID(item.ID),
+			Description:   swag.String(item.Description),
+			Condition:     apimessages.ClaimItemCondition(item.Condition),
+			ClaimedAmount: swag.Int64(item.ClaimedAmount.Int64()),
+		}
+		if item.OfferedAmount != nil {
+			itemPayload.OfferedAmount = swag.Int64(item.OfferedAmount.Int64())
+		}
+		items[i] = itemPayload
+	}
+
+	claimPayload := &apimessages.Claim{
+		ID:                 handlers.FmtUUID(claim.ID),
+		ShipmentID:         handlers.FmtUUID(claim.ShipmentID),
+		Status:             apimessages.ClaimStatus(claim.Status),
+		ClaimDate:          handlers.FmtDateTime(claim.ClaimDate),
+		Description:        swag.String(claim.Description),
+		TspResponseDueDate: handlers.FmtDate(claim.TSPResponseDueDate),
+		TspResponse:        claim.TSPResponse,
+		ClaimedAmount:      swag.Int64(claim.ClaimedAmount().Int64()),
+		ResolvedAt:         handlers.FmtDateTimePtr(claim.ResolvedAt),
+		Document:           documentPayload,
+		Items:              items,
+	}
+	if claim.SettlementOfferAmount != nil {
+		claimPayload.SettlementOfferAmount = swag.Int64(claim.SettlementOfferAmount.Int64())
+	}
+	return claimPayload, nil
+}
+
+// ReviewClaimHandler starts a TSP's review of a claim
+type ReviewClaimHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle marks the claim as being reviewed - checks that currently logged in user is authorized to act for the TSP the claim is against
+func (h ReviewClaimHandler) Handle(params claimop.ReviewClaimParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return claimop.NewReviewClaimForbidden()
+	}
+	claimID, _ := uuid.FromString(params.ClaimID.String())
+
+	claim, err := models.FetchClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *claim
+	if err = claim.Review(); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	audit := models.NewAuditEvent(session, &before, claim, "review", "")
+	verrs, err := models.SaveClaim(h.DB(), claim, audit)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), *claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewReviewClaimOK().WithPayload(claimPayload)
+}
+
+// OfferSettlementForClaimHandler records the settlement a TSP offers for a claim
+type OfferSettlementForClaimHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle records the amount offered for each item and the TSP's response
+func (h OfferSettlementForClaimHandler) Handle(params claimop.OfferSettlementForClaimParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return claimop.NewOfferSettlementForClaimForbidden()
+	}
+	claimID, _ := uuid.FromString(params.ClaimID.String())
+
+	claim, err := models.FetchClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *claim
+
+	itemIndexes := map[uuid.UUID]int{}
+	for i, item := range claim.Items {
+		itemIndexes[item.ID] = i
+	}
+	for _, offer := range params.Payload.ItemOffers {
+		itemID, _ := uuid.FromString(offer.ClaimItemID.String())
+		i, ok := itemIndexes[itemID]
+		if !ok {
+			h.Logger().Info("Settlement offered for an item not in the claim", zap.String("claim_item_id", itemID.String()))
+			return claimop.NewOfferSettlementForClaimBadRequest()
+		}
+		offeredAmount := unit.Cents(*offer.OfferedAmount)
+		claim.Items[i].OfferedAmount = &offeredAmount
+	}
+
+	if err = claim.OfferSettlement(*params.Payload.TspResponse); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	audit := models.NewAuditEvent(session, &before, claim, "offer_settlement", "")
+	verrs, err := models.SaveClaim(h.DB(), claim, audit)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), *claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewOfferSettlementForClaimOK().WithPayload(claimPayload)
+}
+
+// DenyClaimHandler denies a claim against a TSP
+type DenyClaimHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle denies the claim and records why
+func (h DenyClaimHandler) Handle(params claimop.DenyClaimParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return claimop.NewDenyClaimForbidden()
+	}
+	claimID, _ := uuid.FromString(params.ClaimID.String())
+
+	claim, err := models.FetchClaim(h.DB(), session, claimID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	before := *claim
+	if err = claim.Deny(*params.Payload.TspResponse, time.Now()); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	audit := models.NewAuditEvent(session, &before, claim, "deny", "")
+	verrs, err := models.SaveClaim(h.DB(), claim, audit)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	claimPayload, err := payloadForClaimModel(h.FileStorer(), *claim)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return claimop.NewDenyClaimOK().WithPayload(claimPayload)
+}
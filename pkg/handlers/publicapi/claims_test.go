@@ -0,0 +1,105 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	claimop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/claims"
+	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *HandlerSuite) setupClaim() (models.TspUser, models.Shipment, models.Claim) {
+	status := []models.ShipmentStatus{models.ShipmentStatusDELIVERED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.TestDB(), 1, 1, []int{1}, status)
+	suite.NoError(err)
+
+	shipment := shipments[0]
+	delivered := time.Now().AddDate(0, -1, 0)
+	shipment.ActualDeliveryDate = &delivered
+	suite.MustSave(&shipment)
+
+	claim := models.Claim{
+		Description: "Movers dropped the clock",
+		ClaimDate:   time.Now(),
+		Items: models.ClaimItems{
+			{Description: "Grandfather clock", Condition: models.ClaimItemConditionDAMAGED, ClaimedAmount: unit.Cents(50000)},
+		},
+	}
+	verrs, err := models.FileClaim(suite.TestDB(), nil, shipment, &claim)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	return tspUsers[0], shipment, claim
+}
+
+func (suite *HandlerSuite) TestClaimSettlementOffer() {
+	tspUser, shipment, claim := suite.setupClaim()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/fake/path", nil), tspUser)
+	indexResponse := GetShipmentClaimsHandler{context}.Handle(shipmentop.GetShipmentClaimsParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+	})
+	suite.Assertions.IsType(&shipmentop.GetShipmentClaimsOK{}, indexResponse)
+	suite.Len(indexResponse.(*shipmentop.GetShipmentClaimsOK).Payload, 1)
+
+	req = suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/fake/path", nil), tspUser)
+	reviewResponse := ReviewClaimHandler{context}.Handle(claimop.ReviewClaimParams{
+		HTTPRequest: req,
+		ClaimID:     strfmt.UUID(claim.ID.String()),
+	})
+	suite.Assertions.IsType(&claimop.ReviewClaimOK{}, reviewResponse)
+	suite.Equal(apimessages.ClaimStatusTSPREVIEWING, reviewResponse.(*claimop.ReviewClaimOK).Payload.Status)
+
+	offerResponse := OfferSettlementForClaimHandler{context}.Handle(claimop.OfferSettlementForClaimParams{
+		HTTPRequest: req,
+		ClaimID:     strfmt.UUID(claim.ID.String()),
+		Payload: &apimessages.ClaimSettlementOfferPayload{
+			TspResponse: swag.String("The clock face was already cracked at pickup"),
+			ItemOffers: []*apimessages.ClaimItemOffer{
+				{
+					ClaimItemID:   handlers.FmtUUID(claim.Items[0].ID),
+					OfferedAmount: swag.Int64(30000),
+				},
+			},
+		},
+	})
+	suite.Assertions.IsType(&claimop.OfferSettlementForClaimOK{}, offerResponse)
+	offered := offerResponse.(*claimop.OfferSettlementForClaimOK).Payload
+	suite.Equal(int64(30000), *offered.SettlementOfferAmount)
+	suite.Equal(int64(30000), *offered.Items[0].OfferedAmount)
+}
+
+func (suite *HandlerSuite) TestDenyClaimHandler() {
+	tspUser, _, claim := suite.setupClaim()
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	params := claimop.DenyClaimParams{
+		ClaimID: strfmt.UUID(claim.ID.String()),
+		Payload: &apimessages.ClaimDenialPayload{
+			TspResponse: swag.String("Damage was not noted at delivery"),
+		},
+	}
+
+	// TSPs the shipment wasn't offered to can't deny the claim
+	otherTspUser := testdatagen.MakeDefaultTspUser(suite.TestDB())
+	params.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/fake/path", nil), otherTspUser)
+	suite.CheckResponseForbidden(DenyClaimHandler{context}.Handle(params))
+
+	params.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/fake/path", nil), tspUser)
+	response := DenyClaimHandler{context}.Handle(params)
+	suite.Assertions.IsType(&claimop.DenyClaimOK{}, response)
+	denied := response.(*claimop.DenyClaimOK).Payload
+	suite.Equal(apimessages.ClaimStatusDENIED, denied.Status)
+	suite.Equal("Damage was not noted at delivery", *denied.TspResponse)
+	suite.NotNil(denied.ResolvedAt)
+}
@@ -0,0 +1,311 @@
+package publicapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	inventoryop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/inventories"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/paperwork"
+	uploaderpkg "github.com/transcom/mymove/pkg/uploader"
+)
+
+func payloadForConditionCodes(codes string) []apimessages.InventoryConditionCode {
+	split := models.SplitConditionCodes(codes)
+	payload := make([]apimessages.InventoryConditionCode, len(split))
+	for i, code := range split {
+		payload[i] = apimessages.InventoryConditionCode(code)
+	}
+	return payload
+}
+
+func conditionCodesForPayload(payload []apimessages.InventoryConditionCode) string {
+	codes := make([]string, len(payload))
+	for i, code := range payload {
+		codes[i] = string(code)
+	}
+	return models.JoinConditionCodes(codes)
+}
+
+func payloadForShipmentInventoryItemModel(item models.ShipmentInventoryItem) *apimessages.ShipmentInventoryItemPayload {
+	return &apimessages.ShipmentInventoryItemPayload{
+		ID:                     *handlers.FmtUUID(item.ID),
+		TagNumber:              swag.Int64(int64(item.TagNumber)),
+		Description:            swag.String(item.Description),
+		PickupConditionCodes:   payloadForConditionCodes(item.PickupConditionCodes),
+		DeliveryConditionCodes: payloadForConditionCodes(swag.StringValue(item.DeliveryConditionCodes)),
+		DeliveryExceptionNotes: item.DeliveryExceptionNotes,
+		MissingAtDelivery:      item.MissingAtDelivery,
+	}
+}
+
+func payloadForShipmentInventoryModel(inventory models.ShipmentInventory) *apimessages.ShipmentInventoryPayload {
+	items := make([]*apimessages.ShipmentInventoryItemPayload, len(inventory.Items))
+	for i, item := range inventory.Items {
+		items[i] = payloadForShipmentInventoryItemModel(item)
+	}
+	signatures := make([]*apimessages.ShipmentInventorySignaturePayload, len(inventory.Signatures))
+	for i, signature := range inventory.Signatures {
+		signatures[i] = &apimessages.ShipmentInventorySignaturePayload{
+			ID:         *handlers.FmtUUID(signature.ID),
+			Stage:      apimessages.InventoryStage(signature.Stage),
+			SignerRole: apimessages.InventorySignerRole(signature.SignerRole),
+			SignerName: swag.String(signature.SignerName),
+			Signature:  swag.String(signature.Signature),
+			SignedAt:   strfmt.DateTime(signature.SignedAt),
+		}
+	}
+	return &apimessages.ShipmentInventoryPayload{
+		ID:         *handlers.FmtUUID(inventory.ID),
+		ShipmentID: *handlers.FmtUUID(inventory.ShipmentID),
+		LotNumber:  swag.String(inventory.LotNumber),
+		Items:      items,
+		Signatures: signatures,
+	}
+}
+
+// fetchShipmentInventory returns the logged in TSP user, the shipment and its inventory, if the
+// user's TSP accepted the shipment
+func fetchShipmentInventory(h handlers.HandlerContext, session *auth.Session, shipmentID strfmt.UUID) (*models.TspUser, *models.Shipment, *models.ShipmentInventory, error) {
+	tspUser, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, shipmentID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	inventory, err := models.FetchShipmentInventory(h.DB(), shipment.ID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tspUser, shipment, inventory, nil
+}
+
+// fetchShipmentInventoryItem returns an item on the inventory
+func fetchShipmentInventoryItem(inventory *models.ShipmentInventory, itemID strfmt.UUID) (*models.ShipmentInventoryItem, error) {
+	id, _ := uuid.FromString(itemID.String())
+	for i := range inventory.Items {
+		if inventory.Items[i].ID == id {
+			return &inventory.Items[i], nil
+		}
+	}
+	return nil, models.ErrFetchNotFound
+}
+
+// GetShipmentInventoryHandler returns a shipment's inventory
+type GetShipmentInventoryHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns the inventory if the logged in user's TSP accepted the shipment
+func (h GetShipmentInventoryHandler) Handle(params inventoryop.GetShipmentInventoryParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, _, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewGetShipmentInventoryOK().WithPayload(payloadForShipmentInventoryModel(*inventory))
+}
+
+// CreateShipmentInventoryHandler starts a shipment's inventory
+type CreateShipmentInventoryHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle starts the inventory under the lot number for the logged in user's TSP
+func (h CreateShipmentInventoryHandler) Handle(params inventoryop.CreateShipmentInventoryParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	inventory, verrs, err := models.CreateShipmentInventory(h.DB(), *shipment, tspUser.TransportationServiceProviderID, *params.Payload.LotNumber)
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewCreateShipmentInventoryCreated().WithPayload(payloadForShipmentInventoryModel(*inventory))
+}
+
+// CreateShipmentInventoryItemHandler adds an item to a shipment's inventory
+type CreateShipmentInventoryItemHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle adds the item with its condition at pickup
+func (h CreateShipmentInventoryItemHandler) Handle(params inventoryop.CreateShipmentInventoryItemParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	item := models.ShipmentInventoryItem{
+		TagNumber:            int(*params.Payload.TagNumber),
+		Description:          *params.Payload.Description,
+		PickupConditionCodes: conditionCodesForPayload(params.Payload.PickupConditionCodes),
+	}
+	verrs, err := models.SaveShipmentInventoryItem(h.DB(), *shipment, inventory, &item)
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewCreateShipmentInventoryItemCreated().WithPayload(payloadForShipmentInventoryItemModel(item))
+}
+
+// UpdateShipmentInventoryItemHandler updates an item on a shipment's inventory
+type UpdateShipmentInventoryItemHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle updates the item's tag number, description and condition at pickup
+func (h UpdateShipmentInventoryItemHandler) Handle(params inventoryop.UpdateShipmentInventoryItemParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	item, err := fetchShipmentInventoryItem(inventory, params.InventoryItemID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	item.TagNumber = int(*params.Payload.TagNumber)
+	item.Description = *params.Payload.Description
+	item.PickupConditionCodes = conditionCodesForPayload(params.Payload.PickupConditionCodes)
+	verrs, err := models.SaveShipmentInventoryItem(h.DB(), *shipment, inventory, item)
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewUpdateShipmentInventoryItemOK().WithPayload(payloadForShipmentInventoryItemModel(*item))
+}
+
+// RecordInventoryDeliveryExceptionHandler notes an exception against an inventory item at delivery
+type RecordInventoryDeliveryExceptionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle records the item's condition at delivery, or that it is missing
+func (h RecordInventoryDeliveryExceptionHandler) Handle(params inventoryop.RecordInventoryDeliveryExceptionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	item, err := fetchShipmentInventoryItem(inventory, params.InventoryItemID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	item.DeliveryConditionCodes = swag.String(conditionCodesForPayload(params.Payload.DeliveryConditionCodes))
+	item.DeliveryExceptionNotes = params.Payload.Notes
+	item.MissingAtDelivery = params.Payload.MissingAtDelivery
+	verrs, err := models.RecordInventoryDeliveryException(h.DB(), *shipment, inventory, item)
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewRecordInventoryDeliveryExceptionOK().WithPayload(payloadForShipmentInventoryItemModel(*item))
+}
+
+// SignShipmentInventoryHandler adds an electronic signature to a shipment's inventory
+type SignShipmentInventoryHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle signs the inventory for the service member or the TSP, as captured on the crew's device
+func (h SignShipmentInventoryHandler) Handle(params inventoryop.SignShipmentInventoryParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	signature := models.ShipmentInventorySignature{
+		Stage:      models.InventoryStage(params.Payload.Stage),
+		SignerRole: models.InventorySignerRole(params.Payload.SignerRole),
+		SignerName: *params.Payload.SignerName,
+		Signature:  *params.Payload.Signature,
+	}
+	verrs, err := models.SignShipmentInventory(h.DB(), *shipment, inventory, &signature, time.Now())
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return inventoryop.NewSignShipmentInventoryCreated().WithPayload(payloadForShipmentInventoryModel(*inventory))
+}
+
+// CreateShipmentInventoryReportHandler draws a shipment's inventory & uploads it as a move document
+type CreateShipmentInventoryReportHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle generates the inventory PDF & uploads it as a document associated to the shipment and move
+func (h CreateShipmentInventoryReportHandler) Handle(params inventoryop.CreateShipmentInventoryReportParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, shipment, inventory, err := fetchShipmentInventory(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	aFile, err := h.FileStorer().FileSystem().Create(fmt.Sprintf("inventory-%s.pdf", shipment.ID))
+	if err != nil {
+		h.Logger().Error("Error creating a new afero file for inventory.", zap.Error(err))
+		return inventoryop.NewCreateShipmentInventoryReportInternalServerError()
+	}
+	err = paperwork.NewShipmentInventoryReport(shipment, inventory).DrawForm(aFile)
+	if err != nil {
+		h.Logger().Error("Failure drawing inventory.", zap.Error(err))
+		return inventoryop.NewCreateShipmentInventoryReportInternalServerError()
+	}
+
+	uploader := uploaderpkg.NewUploader(h.DB(), h.Logger(), h.FileStorer())
+	upload, verrs, err := uploader.CreateUpload(nil, *tspUser.UserID, aFile)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	// The inventory is filed as the origin inventory until it's signed at delivery
+	var moveDocumentType models.MoveDocumentType = models.MoveDocumentTypeORIGININVENTORY
+	title := "Origin Inventory"
+	if inventory.IsSigned(models.InventoryStageDELIVERY) {
+		moveDocumentType = models.MoveDocumentTypeDESTINATIONINVENTORY
+		title = "Destination Inventory"
+	}
+	doc, verrs, err := shipment.Move.CreateMoveDocument(h.DB(),
+		models.Uploads{*upload},
+		&shipment.ID,
+		moveDocumentType,
+		title,
+		nil,
+		models.SelectedMoveType(apimessages.SelectedMoveTypeHHG),
+	)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	moveDocumentPayload, err := payloadForGenericMoveDocumentModel(h.FileStorer(), *doc, shipment.ID)
+	if err != nil {
+		h.Logger().Error("Error fetching document for inventory", zap.Error(err))
+		return inventoryop.NewCreateShipmentInventoryReportInternalServerError()
+	}
+	return inventoryop.NewCreateShipmentInventoryReportCreated().WithPayload(moveDocumentPayload)
+}
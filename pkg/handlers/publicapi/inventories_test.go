@@ -0,0 +1,82 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	inventoryop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/inventories"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestShipmentInventoryHandlers() {
+	status := []models.ShipmentStatus{models.ShipmentStatusAPPROVED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.TestDB(), 1, 1, []int{1}, status)
+	suite.NoError(err)
+	tspUser := tspUsers[0]
+	shipmentID := strfmt.UUID(shipments[0].ID.String())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/fake/path", nil), tspUser)
+	createResponse := CreateShipmentInventoryHandler{context}.Handle(inventoryop.CreateShipmentInventoryParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+		Payload:     &apimessages.ShipmentInventoryPayload{LotNumber: swag.String("A4417")},
+	})
+	suite.Assertions.IsType(&inventoryop.CreateShipmentInventoryCreated{}, createResponse)
+
+	itemResponse := CreateShipmentInventoryItemHandler{context}.Handle(inventoryop.CreateShipmentInventoryItemParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+		Payload: &apimessages.ShipmentInventoryItemPayload{
+			TagNumber:   swag.Int64(1),
+			Description: swag.String("Dresser, 6 drawer"),
+			PickupConditionCodes: []apimessages.InventoryConditionCode{
+				apimessages.InventoryConditionCodeSC,
+				apimessages.InventoryConditionCodeM,
+			},
+		},
+	})
+	suite.Assertions.IsType(&inventoryop.CreateShipmentInventoryItemCreated{}, itemResponse)
+
+	for _, role := range []apimessages.InventorySignerRole{apimessages.InventorySignerRoleSERVICEMEMBER, apimessages.InventorySignerRoleTSP} {
+		signResponse := SignShipmentInventoryHandler{context}.Handle(inventoryop.SignShipmentInventoryParams{
+			HTTPRequest: req,
+			ShipmentID:  shipmentID,
+			Payload: &apimessages.ShipmentInventorySignaturePayload{
+				Stage:      apimessages.InventoryStagePICKUP,
+				SignerRole: role,
+				SignerName: swag.String("Jane Smith"),
+				Signature:  swag.String("Jane Smith"),
+			},
+		})
+		suite.Assertions.IsType(&inventoryop.SignShipmentInventoryCreated{}, signResponse)
+	}
+
+	// Items can't be added once the inventory is signed at pickup
+	lateResponse := CreateShipmentInventoryItemHandler{context}.Handle(inventoryop.CreateShipmentInventoryItemParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+		Payload: &apimessages.ShipmentInventoryItemPayload{
+			TagNumber:   swag.Int64(2),
+			Description: swag.String("Carton, books"),
+		},
+	})
+	suite.CheckResponseBadRequest(lateResponse)
+
+	req = suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/fake/path", nil), tspUser)
+	getResponse := GetShipmentInventoryHandler{context}.Handle(inventoryop.GetShipmentInventoryParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+	})
+	suite.Assertions.IsType(&inventoryop.GetShipmentInventoryOK{}, getResponse)
+	inventory := getResponse.(*inventoryop.GetShipmentInventoryOK).Payload
+	suite.Equal("A4417", *inventory.LotNumber)
+	suite.Len(inventory.Items, 1)
+	suite.Len(inventory.Items[0].PickupConditionCodes, 2)
+	suite.Len(inventory.Signatures, 2)
+}
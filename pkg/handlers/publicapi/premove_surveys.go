@@ -0,0 +1,330 @@
+package publicapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	premovesurveyop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/premove_surveys"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/paperwork"
+	"github.com/transcom/mymove/pkg/rdd"
+	"github.com/transcom/mymove/pkg/unit"
+	uploaderpkg "github.com/transcom/mymove/pkg/uploader"
+)
+
+func payloadForPremoveSurveySlotModel(slot models.PremoveSurveySlot) *apimessages.PremoveSurveySlotPayload {
+	return &apimessages.PremoveSurveySlotPayload{
+		ID:          *handlers.FmtUUID(slot.ID),
+		StartsAt:    handlers.FmtDateTime(slot.StartsAt),
+		EndsAt:      handlers.FmtDateTime(slot.EndsAt),
+		Status:      apimessages.PremoveSurveySlotStatus(slot.Status),
+		ConfirmedAt: handlers.FmtDateTimePtr(slot.ConfirmedAt),
+	}
+}
+
+func payloadForPremoveSurveySlotModels(slots models.PremoveSurveySlots) apimessages.IndexPremoveSurveySlotsPayload {
+	payload := make(apimessages.IndexPremoveSurveySlotsPayload, len(slots))
+	for i, slot := range slots {
+		payload[i] = payloadForPremoveSurveySlotModel(slot)
+	}
+	return payload
+}
+
+func payloadForPremoveSurveyItemModel(item models.PremoveSurveyItem) *apimessages.PremoveSurveyItemPayload {
+	return &apimessages.PremoveSurveyItemPayload{
+		ID:             *handlers.FmtUUID(item.ID),
+		Room:           swag.String(item.Room),
+		Description:    swag.String(item.Description),
+		Quantity:       swag.Int64(int64(item.Quantity)),
+		CubeFeet:       swag.Float64(item.CubeFeet),
+		WeightEstimate: swag.Int64(item.WeightEstimate.Int64()),
+		Notes:          item.Notes,
+	}
+}
+
+// patchPremoveSurveyItemWithPayload sets an item's fields from a create or update payload
+func patchPremoveSurveyItemWithPayload(item *models.PremoveSurveyItem, payload *apimessages.PremoveSurveyItemPayload) {
+	item.Room = *payload.Room
+	item.Description = *payload.Description
+	item.Quantity = int(*payload.Quantity)
+	item.CubeFeet = *payload.CubeFeet
+	item.WeightEstimate = unit.Pound(*payload.WeightEstimate)
+	item.Notes = payload.Notes
+}
+
+// rollUpPremoveSurveyItems sets the shipment's pre-move survey weight estimate to the total of its
+// inventory and saves it. The RDD is recalculated from the new estimate, as when the TSP patches it.
+func rollUpPremoveSurveyItems(h handlers.HandlerContext, tx *pop.Connection, session *auth.Session, shipment *models.Shipment) (*validate.Errors, error) {
+	items, err := models.FetchPremoveSurveyItems(tx, shipment.ID)
+	if err != nil {
+		return validate.NewErrors(), err
+	}
+	before := *shipment
+	shipment.RollUpPremoveSurveyItems(items)
+
+	calculator := rdd.NewCalculator(h.DB(), h.Logger(), h.Planner(), h.Calendars())
+	if err = calculator.UpdateIfChanged(before, shipment); err != nil {
+		h.Logger().Error("Error calculating required delivery date", zap.Error(err))
+	}
+	return models.SaveShipmentAndAddresses(tx, shipment, models.NewAuditEvent(session, &before, shipment, "update", ""))
+}
+
+// changePremoveSurveyItems makes a change to a shipment's pre-move survey inventory and rolls the
+// inventory up into the shipment in the same transaction, so the estimate always matches the items.
+func changePremoveSurveyItems(h handlers.HandlerContext, session *auth.Session, shipment *models.Shipment, change func(tx *pop.Connection) (*validate.Errors, error)) (*validate.Errors, error) {
+	var verrs *validate.Errors
+	err := h.DB().Transaction(func(tx *pop.Connection) error {
+		var err error
+		verrs, err = change(tx)
+		if err == nil && !verrs.HasAny() {
+			verrs, err = rollUpPremoveSurveyItems(h, tx, session, shipment)
+		}
+		if err != nil {
+			return err
+		} else if verrs.HasAny() {
+			return verrs
+		}
+		return nil
+	})
+	if verrs.HasAny() {
+		return verrs, nil
+	}
+	return validate.NewErrors(), errors.Cause(err)
+}
+
+// IndexPremoveSurveySlotsHandler lists the times proposed for a shipment's pre-move survey
+type IndexPremoveSurveySlotsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the slots if the logged in user's TSP accepted the shipment
+func (h IndexPremoveSurveySlotsHandler) Handle(params premovesurveyop.IndexPremoveSurveySlotsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	slots, err := models.FetchPremoveSurveySlots(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return premovesurveyop.NewIndexPremoveSurveySlotsOK().WithPayload(payloadForPremoveSurveySlotModels(slots))
+}
+
+// ProposePremoveSurveySlotsHandler offers the service member times for the pre-move survey
+type ProposePremoveSurveySlotsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle proposes the slots and notifies the service member to choose one
+func (h ProposePremoveSurveySlotsHandler) Handle(params premovesurveyop.ProposePremoveSurveySlotsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	slots := make(models.PremoveSurveySlots, len(params.Payload.Slots))
+	for i, slotPayload := range params.Payload.Slots {
+		slots[i] = models.PremoveSurveySlot{
+			StartsAt: time.Time(*slotPayload.StartsAt),
+			EndsAt:   time.Time(*slotPayload.EndsAt),
+		}
+	}
+	verrs, err := models.ProposePremoveSurveySlots(h.DB(), *shipment, tspUser.TransportationServiceProviderID, slots, time.Now())
+	if err != nil || verrs.HasAny() {
+		if verrs.HasAny() {
+			return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+		}
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	err = h.NotificationSender().SendNotification(
+		notifications.NewPremoveSurveySlotsProposed(h.DB(), h.Logger(), shipment.ID),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to user", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return premovesurveyop.NewProposePremoveSurveySlotsCreated().WithPayload(payloadForPremoveSurveySlotModels(slots))
+}
+
+// IndexPremoveSurveyItemsHandler lists the inventory taken at a shipment's pre-move survey
+type IndexPremoveSurveyItemsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the items if the logged in user's TSP accepted the shipment
+func (h IndexPremoveSurveyItemsHandler) Handle(params premovesurveyop.IndexPremoveSurveyItemsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	items, err := models.FetchPremoveSurveyItems(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	payload := make(apimessages.IndexPremoveSurveyItemsPayload, len(items))
+	for i, item := range items {
+		payload[i] = payloadForPremoveSurveyItemModel(item)
+	}
+	return premovesurveyop.NewIndexPremoveSurveyItemsOK().WithPayload(payload)
+}
+
+// CreatePremoveSurveyItemHandler adds an item to a shipment's pre-move survey inventory
+type CreatePremoveSurveyItemHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle adds the item and rolls the inventory up into the shipment's weight estimate
+func (h CreatePremoveSurveyItemHandler) Handle(params premovesurveyop.CreatePremoveSurveyItemParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if !shipment.PremoveSurveyIsOpen() {
+		return handlers.ResponseForError(h.Logger(), errors.Wrap(models.ErrInvalidTransition, "CreatePremoveSurveyItem"))
+	}
+
+	item := models.PremoveSurveyItem{ShipmentID: shipment.ID}
+	patchPremoveSurveyItemWithPayload(&item, params.Payload)
+	verrs, err := changePremoveSurveyItems(h, session, shipment, func(tx *pop.Connection) (*validate.Errors, error) {
+		return tx.ValidateAndCreate(&item)
+	})
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return premovesurveyop.NewCreatePremoveSurveyItemCreated().WithPayload(payloadForPremoveSurveyItemModel(item))
+}
+
+// UpdatePremoveSurveyItemHandler updates an item in a shipment's pre-move survey inventory
+type UpdatePremoveSurveyItemHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle updates the item and rolls the inventory up into the shipment's weight estimate
+func (h UpdatePremoveSurveyItemHandler) Handle(params premovesurveyop.UpdatePremoveSurveyItemParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if !shipment.PremoveSurveyIsOpen() {
+		return handlers.ResponseForError(h.Logger(), errors.Wrap(models.ErrInvalidTransition, "UpdatePremoveSurveyItem"))
+	}
+
+	itemID, _ := uuid.FromString(params.PremoveSurveyItemID.String())
+	item, err := models.FetchPremoveSurveyItem(h.DB(), shipment.ID, itemID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	patchPremoveSurveyItemWithPayload(item, params.Payload)
+	verrs, err := changePremoveSurveyItems(h, session, shipment, func(tx *pop.Connection) (*validate.Errors, error) {
+		return tx.ValidateAndUpdate(item)
+	})
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return premovesurveyop.NewUpdatePremoveSurveyItemOK().WithPayload(payloadForPremoveSurveyItemModel(*item))
+}
+
+// DeletePremoveSurveyItemHandler removes an item from a shipment's pre-move survey inventory
+type DeletePremoveSurveyItemHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle removes the item and rolls the remaining inventory up into the shipment's weight estimate
+func (h DeletePremoveSurveyItemHandler) Handle(params premovesurveyop.DeletePremoveSurveyItemParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	_, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if !shipment.PremoveSurveyIsOpen() {
+		return handlers.ResponseForError(h.Logger(), errors.Wrap(models.ErrInvalidTransition, "DeletePremoveSurveyItem"))
+	}
+
+	itemID, _ := uuid.FromString(params.PremoveSurveyItemID.String())
+	item, err := models.FetchPremoveSurveyItem(h.DB(), shipment.ID, itemID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	verrs, err := changePremoveSurveyItems(h, session, shipment, func(tx *pop.Connection) (*validate.Errors, error) {
+		return validate.NewErrors(), tx.Destroy(item)
+	})
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+	return premovesurveyop.NewDeletePremoveSurveyItemOK().WithPayload(payloadForPremoveSurveyItemModel(*item))
+}
+
+// CreatePremoveSurveyReportHandler draws a shipment's pre-move survey report & uploads it as a move document
+type CreatePremoveSurveyReportHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle generates the report PDF & uploads it as a document associated to the shipment and move
+func (h CreatePremoveSurveyReportHandler) Handle(params premovesurveyop.CreatePremoveSurveyReportParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	tspUser, shipment, err := fetchShipmentAcceptedByTSPUser(h, session, params.ShipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	items, err := models.FetchPremoveSurveyItems(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	aFile, err := h.FileStorer().FileSystem().Create(fmt.Sprintf("premove-survey-%s.pdf", shipment.ID))
+	if err != nil {
+		h.Logger().Error("Error creating a new afero file for pre-move survey report.", zap.Error(err))
+		return premovesurveyop.NewCreatePremoveSurveyReportInternalServerError()
+	}
+	err = paperwork.NewPremoveSurveyReport(shipment, items).DrawForm(aFile)
+	if err != nil {
+		h.Logger().Error("Failure drawing pre-move survey report.", zap.Error(err))
+		return premovesurveyop.NewCreatePremoveSurveyReportInternalServerError()
+	}
+
+	uploader := uploaderpkg.NewUploader(h.DB(), h.Logger(), h.FileStorer())
+	upload, verrs, err := uploader.CreateUpload(nil, *tspUser.UserID, aFile)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	doc, verrs, err := shipment.Move.CreateMoveDocument(h.DB(),
+		models.Uploads{*upload},
+		&shipment.ID,
+		models.MoveDocumentTypePREMOVESURVEYREPORT,
+		"Pre-Move Survey Report",
+		nil,
+		models.SelectedMoveType(apimessages.SelectedMoveTypeHHG),
+	)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	moveDocumentPayload, err := payloadForGenericMoveDocumentModel(h.FileStorer(), *doc, shipment.ID)
+	if err != nil {
+		h.Logger().Error("Error fetching document for pre-move survey report", zap.Error(err))
+		return premovesurveyop.NewCreatePremoveSurveyReportInternalServerError()
+	}
+	return premovesurveyop.NewCreatePremoveSurveyReportCreated().WithPayload(moveDocumentPayload)
+}
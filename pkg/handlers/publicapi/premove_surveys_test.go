@@ -0,0 +1,67 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/go-openapi/swag"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	premovesurveyop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/premove_surveys"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestPremoveSurveyInventoryRollsUpWeightEstimate() {
+	status := []models.ShipmentStatus{models.ShipmentStatusACCEPTED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.TestDB(), 1, 1, []int{1}, status)
+	suite.NoError(err)
+	tspUser := tspUsers[0]
+	shipment := shipments[0]
+
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetPlanner(route.NewTestingPlanner(1044))
+	shipmentID := strfmt.UUID(shipment.ID.String())
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/fake/path", nil), tspUser)
+	createResponse := CreatePremoveSurveyItemHandler{context}.Handle(premovesurveyop.CreatePremoveSurveyItemParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+		Payload: &apimessages.PremoveSurveyItemPayload{
+			Room:           swag.String("Kitchen"),
+			Description:    swag.String("Dining chair"),
+			Quantity:       swag.Int64(4),
+			CubeFeet:       swag.Float64(5),
+			WeightEstimate: swag.Int64(35),
+		},
+	})
+	suite.Assertions.IsType(&premovesurveyop.CreatePremoveSurveyItemCreated{}, createResponse)
+	created := createResponse.(*premovesurveyop.CreatePremoveSurveyItemCreated).Payload
+
+	updated, err := models.FetchShipmentByTSP(suite.TestDB(), tspUser.TransportationServiceProviderID, shipment.ID)
+	suite.NoError(err)
+	suite.Equal(int64(140), updated.PmSurveyWeightEstimate.Int64())
+
+	records, err := models.FetchAuditRecords(suite.TestDB(), models.AuditRecordFilter{RecordType: "shipment", RecordID: &shipment.ID})
+	suite.NoError(err)
+	suite.Len(records, 1)
+	suite.Equal("update", records[0].Event)
+
+	req = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/fake/path", nil), tspUser)
+	deleteResponse := DeletePremoveSurveyItemHandler{context}.Handle(premovesurveyop.DeletePremoveSurveyItemParams{
+		HTTPRequest:         req,
+		ShipmentID:          shipmentID,
+		PremoveSurveyItemID: created.ID,
+	})
+	suite.Assertions.IsType(&premovesurveyop.DeletePremoveSurveyItemOK{}, deleteResponse)
+
+	req = suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/fake/path", nil), tspUser)
+	indexResponse := IndexPremoveSurveyItemsHandler{context}.Handle(premovesurveyop.IndexPremoveSurveyItemsParams{
+		HTTPRequest: req,
+		ShipmentID:  shipmentID,
+	})
+	suite.Assertions.IsType(&premovesurveyop.IndexPremoveSurveyItemsOK{}, indexResponse)
+	suite.Len(indexResponse.(*premovesurveyop.IndexPremoveSurveyItemsOK).Payload, 0)
+}
@@ -2,8 +2,11 @@ package publicapi
 
 import (
 	"database/sql"
+	"fmt"
 
 	"github.com/go-openapi/runtime/middleware"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -13,7 +16,9 @@ import (
 	accessorialop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/accessorials"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/unit"
+	"github.com/transcom/mymove/pkg/webhooks"
 )
 
 func payloadForShipmentLineItemModels(s []models.ShipmentLineItem) apimessages.ShipmentLineItems {
@@ -131,6 +136,19 @@ func (h CreateShipmentLineItemHandler) Handle(params accessorialop.CreateShipmen
 		h.Logger().Error("Error fetching shipment line items for shipment", zap.Error(err))
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
+
+	// Line items the TSP adds wait on the transportation office's approval
+	if session.IsTspUser() {
+		lineItem := fmt.Sprintf("%s %s", tariff400ngItem.Code, tariff400ngItem.Item)
+		err = h.NotificationSender().SendNotification(
+			notifications.NewPreApprovalRequested(h.DB(), h.Logger(), shipment.ID, lineItem),
+		)
+		if err != nil {
+			h.Logger().Error("problem sending email to user", zap.Error(err))
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	}
+
 	payload := payloadForShipmentLineItemModel(shipmentLineItem)
 	return accessorialop.NewCreateShipmentLineItemCreated().WithPayload(payload)
 }
@@ -288,12 +306,30 @@ func (h ApproveShipmentLineItemHandler) Handle(params accessorialop.ApproveShipm
 	}
 
 	// Approve and save the shipment line item
+	before := shipmentLineItem
 	err = shipmentLineItem.Approve()
 	if err != nil {
 		h.Logger().Error("Error approving shipment line item for shipment", zap.Error(err))
 		return accessorialop.NewApproveShipmentLineItemForbidden()
 	}
-	h.DB().ValidateAndUpdate(&shipmentLineItem)
+	var verrs *validate.Errors
+	err = h.DB().Transaction(func(tx *pop.Connection) error {
+		var err error
+		verrs, err = models.SaveWithAudit(tx, session, &before, &shipmentLineItem, "approve", "")
+		if err != nil {
+			return err
+		} else if verrs.HasAny() {
+			return verrs
+		}
+		return webhooks.NotifyShipmentTSP(tx, models.WebhookEventLineItemApproved, webhooks.EventData{
+			ShipmentID:         shipmentLineItem.ShipmentID,
+			ShipmentLineItemID: &shipmentLineItem.ID,
+			LineItemStatus:     string(shipmentLineItem.Status),
+		})
+	})
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, errors.Cause(err))
+	}
 
 	payload := payloadForShipmentLineItemModel(&shipmentLineItem)
 	return accessorialop.NewApproveShipmentLineItemOK().WithPayload(payload)
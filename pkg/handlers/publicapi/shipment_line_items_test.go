@@ -12,6 +12,7 @@ import (
 	accessorialop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/accessorials"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/testdatagen"
 	"github.com/transcom/mymove/pkg/unit"
 )
@@ -120,7 +121,10 @@ func (suite *HandlerSuite) TestCreateShipmentLineItemHandler() {
 	}
 
 	// And: get shipment is returned
-	handler := CreateShipmentLineItemHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := CreateShipmentLineItemHandler{context}
 	response := handler.Handle(params)
 
 	// Then: expect a 200 status code
@@ -131,6 +135,51 @@ func (suite *HandlerSuite) TestCreateShipmentLineItemHandler() {
 			suite.Equal("Some notes", okResponse.Payload.Notes)
 		}
 	}
+
+	// And: line items the office adds don't need its own approval, so no one is notified
+	suite.Empty(notificationSender.Sent())
+}
+
+func (suite *HandlerSuite) TestCreateShipmentLineItemTSPHandler() {
+	numTspUsers := 1
+	numShipments := 1
+	numShipmentOfferSplit := []int{1}
+	status := []models.ShipmentStatus{models.ShipmentStatusSUBMITTED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.TestDB(), numTspUsers, numShipments, numShipmentOfferSplit, status)
+	suite.NoError(err)
+	tspUser := tspUsers[0]
+	shipment := shipments[0]
+	tariffItem := makePreApprovalItem(suite.TestDB())
+
+	// And: the context contains the auth values
+	req := httptest.NewRequest("POST", "/shipments", nil)
+	req = suite.AuthenticateTspRequest(req, tspUser)
+
+	payload := apimessages.ShipmentLineItem{
+		Tariff400ngItemID: handlers.FmtUUID(tariffItem.ID),
+		Location:          apimessages.ShipmentLineItemLocationORIGIN,
+		Notes:             "Some notes",
+		Quantity1:         handlers.FmtInt64(int64(5)),
+	}
+
+	params := accessorialop.CreateShipmentLineItemParams{
+		HTTPRequest: req,
+		ShipmentID:  strfmt.UUID(shipment.ID.String()),
+		Payload:     &payload,
+	}
+
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := CreateShipmentLineItemHandler{context}
+	response := handler.Handle(params)
+
+	// Then: expect a 201 status code
+	suite.Assertions.IsType(&accessorialop.CreateShipmentLineItemCreated{}, response)
+
+	// And: the transportation office is asked to pre-approve the line item
+	suite.Equal([]string{notifications.EventPreApprovalRequested}, notificationSender.Events())
+	suite.Equal(shipment.ID, notificationSender.Sent()[0].ShipmentID)
 }
 
 func (suite *HandlerSuite) TestCreateShipmentLineItemForbidden() {
@@ -0,0 +1,69 @@
+package publicapi
+
+import (
+	"time"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/swag"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// defaultSyncLimit is how many shipments a sync returns when no limit is given
+const defaultSyncLimit = 100
+
+// SyncShipmentsHandler returns the shipments that changed since a TSP system last synced
+type SyncShipmentsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle returns a page of the logged in user's TSP's shipments changed after the cursor
+func (h SyncShipmentsHandler) Handle(params shipmentop.SyncShipmentsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return shipmentop.NewSyncShipmentsForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return shipmentop.NewSyncShipmentsForbidden()
+	}
+
+	var cursor models.ShipmentSyncCursor
+	var since time.Time
+	if params.Cursor != nil {
+		cursor, err = models.ParseShipmentSyncCursor(*params.Cursor)
+		if err != nil {
+			h.Logger().Info("Invalid sync cursor", zap.String("cursor", *params.Cursor))
+			return shipmentop.NewSyncShipmentsBadRequest()
+		}
+	} else if params.UpdatedSince != nil {
+		since = time.Time(*params.UpdatedSince)
+	}
+	limit := defaultSyncLimit
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+
+	// With nothing new the TSP system keeps its place
+	shipments, cursor, hasMore, err := models.FetchShipmentsUpdatedForTSP(h.DB(), tspUser.TransportationServiceProviderID, cursor, since, limit)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	isp := make(apimessages.IndexShipments, len(shipments))
+	for i, s := range shipments {
+		isp[i] = payloadForShipmentModel(s)
+	}
+	payload := &apimessages.ShipmentSyncPayload{
+		Shipments: isp,
+		Cursor:    swag.String(cursor.String()),
+		HasMore:   swag.Bool(hasMore),
+	}
+	return shipmentop.NewSyncShipmentsOK().WithPayload(payload)
+}
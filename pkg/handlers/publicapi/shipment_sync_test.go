@@ -0,0 +1,55 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/swag"
+
+	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestSyncShipmentsHandler() {
+	status := []models.ShipmentStatus{models.ShipmentStatusAWARDED}
+	tspUsers, shipments, _, err := testdatagen.CreateShipmentOfferData(suite.TestDB(), 1, 3, []int{3}, status)
+	suite.NoError(err)
+	handler := SyncShipmentsHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+
+	sync := func(cursor *string) *shipmentop.SyncShipmentsOK {
+		req := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/shipment_changes", nil), tspUsers[0])
+		response := handler.Handle(shipmentop.SyncShipmentsParams{
+			HTTPRequest: req,
+			Cursor:      cursor,
+			Limit:       swag.Int64(2),
+		})
+		suite.Assertions.IsType(&shipmentop.SyncShipmentsOK{}, response)
+		return response.(*shipmentop.SyncShipmentsOK)
+	}
+
+	first := sync(nil)
+	suite.Len(first.Payload.Shipments, 2)
+	suite.True(*first.Payload.HasMore)
+
+	second := sync(first.Payload.Cursor)
+	suite.Len(second.Payload.Shipments, 1)
+	suite.False(*second.Payload.HasMore)
+
+	// With nothing new the cursor stays put
+	third := sync(second.Payload.Cursor)
+	suite.Empty(third.Payload.Shipments)
+	suite.Equal(*second.Payload.Cursor, *third.Payload.Cursor)
+
+	// Changed shipments are synced again
+	shipment := shipments[0]
+	shipment.Status = models.ShipmentStatusACCEPTED
+	suite.MustSave(&shipment)
+	fourth := sync(third.Payload.Cursor)
+	suite.Len(fourth.Payload.Shipments, 1)
+	suite.Equal(shipment.ID.String(), fourth.Payload.Shipments[0].ID.String())
+
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/shipment_changes", nil), tspUsers[0])
+	response := handler.Handle(shipmentop.SyncShipmentsParams{HTTPRequest: req, Cursor: swag.String("not-a-cursor")})
+	suite.Assertions.IsType(&shipmentop.SyncShipmentsBadRequest{}, response)
+}
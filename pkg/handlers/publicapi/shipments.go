@@ -9,7 +9,10 @@ import (
 	"github.com/go-openapi/runtime/middleware"
 	"github.com/go-openapi/strfmt"
 	"github.com/go-openapi/swag"
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"github.com/transcom/mymove/pkg/assets"
 	"github.com/transcom/mymove/pkg/auth"
 	"github.com/transcom/mymove/pkg/awardqueue"
@@ -17,8 +20,11 @@ import (
 	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	"github.com/transcom/mymove/pkg/paperwork"
+	"github.com/transcom/mymove/pkg/rdd"
 	uploaderpkg "github.com/transcom/mymove/pkg/uploader"
+	"github.com/transcom/mymove/pkg/webhooks"
 	"go.uber.org/zap"
 )
 
@@ -47,6 +53,11 @@ func payloadForShipmentModel(s models.Shipment) *apimessages.Shipment {
 		OriginalDeliveryDate: handlers.FmtDatePtr(s.OriginalDeliveryDate),
 		OriginalPackDate:     handlers.FmtDatePtr(s.OriginalPackDate),
 
+		// required delivery date
+		RequiredDeliveryDate:      handlers.FmtDatePtr(s.RequiredDeliveryDate),
+		RequiredDeliveryDateBasis: s.RequiredDeliveryDateBasisLines(),
+		DeliveredLate:             s.IsDeliveredLate(),
+
 		// calculated durations
 		EstimatedPackDays:    s.EstimatedPackDays,
 		EstimatedTransitDays: s.EstimatedTransitDays,
@@ -82,6 +93,26 @@ func payloadForShipmentModel(s models.Shipment) *apimessages.Shipment {
 	return shipmentpayload
 }
 
+// fetchShipmentAcceptedByTSPUser returns the logged in TSP user and the shipment, if the user's TSP
+// accepted it
+func fetchShipmentAcceptedByTSPUser(h handlers.HandlerContext, session *auth.Session, shipmentID strfmt.UUID) (*models.TspUser, *models.Shipment, error) {
+	if !session.IsTspUser() {
+		return nil, nil, models.ErrFetchForbidden
+	}
+	id, _ := uuid.FromString(shipmentID.String())
+	tspUser, shipment, err := models.FetchShipmentForVerifiedTSPUser(h.DB(), session.TspUserID, id)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return nil, nil, err
+	}
+	for _, offer := range shipment.ShipmentOffers {
+		if offer.TransportationServiceProviderID == tspUser.TransportationServiceProviderID && offer.Accepted != nil && *offer.Accepted {
+			return tspUser, shipment, nil
+		}
+	}
+	return nil, nil, models.ErrFetchForbidden
+}
+
 // IndexShipmentsHandler returns a list of shipments
 type IndexShipmentsHandler struct {
 	handlers.HandlerContext
@@ -169,8 +200,24 @@ func (h AcceptShipmentHandler) Handle(params shipmentop.AcceptShipmentParams) mi
 		return shipmentop.NewAcceptShipmentForbidden()
 	}
 
-	// Accept the shipment
-	shipment, shipmentOffer, verrs, err := models.AcceptShipmentForTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID)
+	// Accept the shipment, recording the webhook event in the same transaction
+	var shipment *models.Shipment
+	var shipmentOffer *models.ShipmentOffer
+	var verrs *validate.Errors
+	err = h.DB().Transaction(func(tx *pop.Connection) error {
+		var err error
+		shipment, shipmentOffer, verrs, err = models.AcceptShipmentForTSP(tx, session, tspUser.TransportationServiceProviderID, shipmentID)
+		if err != nil {
+			return err
+		} else if verrs.HasAny() {
+			return verrs
+		}
+		return webhooks.Notify(tx, tspUser.TransportationServiceProviderID, models.WebhookEventShipmentAccepted, webhooks.EventData{
+			ShipmentID:     shipment.ID,
+			ShipmentStatus: string(shipment.Status),
+		})
+	})
+	err = errors.Cause(err)
 	if err != nil || verrs.HasAny() {
 		if err == models.ErrFetchNotFound {
 			h.Logger().Error("DB Query", zap.Error(err))
@@ -185,6 +232,14 @@ func (h AcceptShipmentHandler) Handle(params shipmentop.AcceptShipmentParams) mi
 		}
 	}
 
+	err = h.NotificationSender().SendNotification(
+		notifications.NewShipmentAccepted(h.DB(), h.Logger(), shipment.ID),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to user", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
 	sp := payloadForShipmentModel(*shipment)
 	return shipmentop.NewAcceptShipmentOK().WithPayload(sp)
 }
@@ -211,7 +266,7 @@ func (h RejectShipmentHandler) Handle(params shipmentop.RejectShipmentParams) mi
 	}
 
 	// Reject the shipment
-	shipment, shipmentOffer, verrs, err := models.RejectShipmentForTSP(h.DB(), tspUser.TransportationServiceProviderID, shipmentID, *params.Payload.Reason)
+	shipment, shipmentOffer, verrs, err := models.RejectShipmentForTSP(h.DB(), session, tspUser.TransportationServiceProviderID, shipmentID, *params.Payload.Reason)
 	if err != nil || verrs.HasAny() {
 		if err == models.ErrFetchNotFound {
 			h.HoneyZapLogger().TraceError(ctx, "DB Query", zap.Error(err))
@@ -230,7 +285,15 @@ func (h RejectShipmentHandler) Handle(params shipmentop.RejectShipmentParams) mi
 		}
 	}
 
-	go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger()).Run(ctx)
+	err = h.NotificationSender().SendNotification(
+		notifications.NewShipmentRejected(h.DB(), h.Logger(), shipment.ID, *params.Payload.Reason),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to user", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	go awardqueue.NewAwardQueue(h.DB(), h.HoneyZapLogger(), h.NotificationSender()).Run(ctx)
 
 	sp := payloadForShipmentModel(*shipment)
 	return shipmentop.NewRejectShipmentOK().WithPayload(sp)
@@ -261,6 +324,7 @@ func (h TransportShipmentHandler) Handle(params shipmentop.TransportShipmentPara
 		h.Logger().Error("DB Query", zap.Error(err))
 		return shipmentop.NewTransportShipmentBadRequest()
 	}
+	before := *shipment
 
 	actualPackDate := (time.Time)(*params.Payload.ActualPackDate)
 
@@ -286,10 +350,25 @@ func (h TransportShipmentHandler) Handle(params shipmentop.TransportShipmentPara
 		shipment.TareWeight = handlers.PoundPtrFromInt64Ptr(params.Payload.TareWeight)
 	}
 
-	verrs, err := h.DB().ValidateAndUpdate(shipment)
+	// The RDD counts from the actual pickup with the weighed shipment. A shipment without an RDD
+	// still goes into transit.
+	calculator := rdd.NewCalculator(h.DB(), h.Logger(), h.Planner(), h.Calendars())
+	if err = calculator.UpdateIfChanged(before, shipment); err != nil {
+		h.Logger().Error("Error calculating required delivery date", zap.Error(err))
+	}
+
+	verrs, err := models.SaveWithAudit(h.DB(), session, &before, shipment, "transport", "")
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
+
+	err = h.NotificationSender().SendNotification(
+		notifications.NewShipmentPickedUp(h.DB(), h.Logger(), shipment.ID),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to user", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
 	sp := payloadForShipmentModel(*shipment)
 	return shipmentop.NewTransportShipmentOK().WithPayload(sp)
 }
@@ -319,6 +398,7 @@ func (h DeliverShipmentHandler) Handle(params shipmentop.DeliverShipmentParams)
 		h.Logger().Error("DB Query", zap.Error(err))
 		return shipmentop.NewDeliverShipmentBadRequest()
 	}
+	before := *shipment
 
 	actualDeliveryDate := (time.Time)(*params.Payload.ActualDeliveryDate)
 
@@ -341,11 +421,20 @@ func (h DeliverShipmentHandler) Handle(params shipmentop.DeliverShipmentParams)
 		return handlers.ResponseForError(h.Logger(), err)
 	}
 
-	verrs, err := shipment.SaveShipmentAndLineItems(h.DB(), lineItems)
+	audit := models.NewAuditEvent(session, &before, shipment, "deliver", "")
+	verrs, err := shipment.SaveShipmentAndLineItems(h.DB(), lineItems, audit)
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
+	err = h.NotificationSender().SendNotification(
+		notifications.NewShipmentDelivered(h.DB(), h.Logger(), shipment.ID),
+	)
+	if err != nil {
+		h.Logger().Error("problem sending email to user", zap.Error(err))
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
 	sp := payloadForShipmentModel(*shipment)
 	return shipmentop.NewDeliverShipmentOK().WithPayload(sp)
 }
@@ -497,6 +586,24 @@ func patchShipmentWithPayload(shipment *models.Shipment, payload *apimessages.Sh
 	}
 }
 
+// pmSurveyScheduleChanged returns whether the pre-move survey is scheduled and its date or the dates
+// planned at it changed
+func pmSurveyScheduleChanged(before models.Shipment, after models.Shipment) bool {
+	if after.PmSurveyConductedDate == nil {
+		return false
+	}
+	dateChanged := func(before *time.Time, after *time.Time) bool {
+		if before == nil || after == nil {
+			return before != after
+		}
+		return !before.Equal(*after)
+	}
+	return dateChanged(before.PmSurveyConductedDate, after.PmSurveyConductedDate) ||
+		dateChanged(before.PmSurveyPlannedPackDate, after.PmSurveyPlannedPackDate) ||
+		dateChanged(before.PmSurveyPlannedPickupDate, after.PmSurveyPlannedPickupDate) ||
+		dateChanged(before.PmSurveyPlannedDeliveryDate, after.PmSurveyPlannedDeliveryDate)
+}
+
 // PatchShipmentHandler allows a TSP to refuse a particular shipment
 type PatchShipmentHandler struct {
 	handlers.HandlerContext
@@ -528,13 +635,33 @@ func (h PatchShipmentHandler) Handle(params shipmentop.PatchShipmentParams) midd
 		return shipmentop.NewPatchShipmentBadRequest()
 	}
 
+	before := *shipment
 	patchShipmentWithPayload(shipment, params.Update)
-	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment)
+
+	// The pre-move survey can change the pickup date and weight the RDD is derived from. The update
+	// isn't refused if the RDD can't be recalculated.
+	calculator := rdd.NewCalculator(h.DB(), h.Logger(), h.Planner(), h.Calendars())
+	if err = calculator.UpdateIfChanged(before, shipment); err != nil {
+		h.Logger().Error("Error calculating required delivery date", zap.Error(err))
+	}
+
+	audit := models.NewAuditEvent(session, &before, shipment, "update", "")
+	verrs, err := models.SaveShipmentAndAddresses(h.DB(), shipment, audit)
 
 	if err != nil || verrs.HasAny() {
 		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
 	}
 
+	if session.IsTspUser() && pmSurveyScheduleChanged(before, *shipment) {
+		err = h.NotificationSender().SendNotification(
+			notifications.NewPremoveSurveyScheduled(h.DB(), h.Logger(), shipment.ID),
+		)
+		if err != nil {
+			h.Logger().Error("problem sending email to user", zap.Error(err))
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+	}
+
 	shipmentPayload := payloadForShipmentModel(*shipment)
 	return shipmentop.NewPatchShipmentOK().WithPayload(shipmentPayload)
 }
@@ -675,12 +802,32 @@ func (h GetShipmentContactDetailsHandler) Handle(p shipmentop.GetShipmentContact
 	return middleware.NotImplemented("operation .shipmentContactDetails has not yet been implemented")
 }
 
-// GetShipmentClaimsHandler allows a TSP to accept a particular shipment
+// GetShipmentClaimsHandler returns the loss and damage claims filed against a shipment
 type GetShipmentClaimsHandler struct {
 	handlers.HandlerContext
 }
 
-// Handle accepts the shipment - checks that currently logged in user is authorized to act for the TSP assigned the shipment
-func (h GetShipmentClaimsHandler) Handle(p shipmentop.GetShipmentClaimsParams) middleware.Responder {
-	return middleware.NotImplemented("operation .shipmentContactDetails has not yet been implemented")
+// Handle returns the claims - checks that currently logged in user is authorized to access the shipment
+func (h GetShipmentClaimsHandler) Handle(params shipmentop.GetShipmentClaimsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	shipmentID, _ := uuid.FromString(params.ShipmentID.String())
+
+	shipment, err := models.FetchShipment(h.DB(), session, shipmentID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	claims, err := models.FetchClaimsForShipment(h.DB(), shipment.ID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	claimPayloads := make([]*apimessages.Claim, len(claims))
+	for i, claim := range claims {
+		claimPayload, err := payloadForClaimModel(h.FileStorer(), claim)
+		if err != nil {
+			return handlers.ResponseForError(h.Logger(), err)
+		}
+		claimPayloads[i] = claimPayload
+	}
+	return shipmentop.NewGetShipmentClaimsOK().WithPayload(claimPayloads)
 }
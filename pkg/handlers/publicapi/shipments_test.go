@@ -14,6 +14,7 @@ import (
 	shipmentop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/shipments"
 	"github.com/transcom/mymove/pkg/handlers"
 	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
 	storageTest "github.com/transcom/mymove/pkg/storage/test"
 	"github.com/transcom/mymove/pkg/testdatagen"
 	"github.com/transcom/mymove/pkg/testdatagen/scenario"
@@ -79,7 +80,10 @@ func (suite *HandlerSuite) TestPatchShipmentHandlerNetWeight() {
 	}
 
 	// And: patch shipment is returned
-	handler := PatchShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := PatchShipmentHandler{context}
 	response := handler.Handle(params)
 
 	// Then: expect a 200 status code
@@ -89,6 +93,9 @@ func (suite *HandlerSuite) TestPatchShipmentHandlerNetWeight() {
 	// And: Payload has new values
 	suite.Equal(int64(17500), *okResponse.Payload.NetWeight)
 	suite.Equal(int64(12500), *okResponse.Payload.GrossWeight)
+
+	// And: nothing about the pre-move survey changed, so no one is notified
+	suite.Empty(notificationSender.Sent())
 }
 
 func (suite *HandlerSuite) TestPatchShipmentHandlerPmSurvey() {
@@ -126,7 +133,10 @@ func (suite *HandlerSuite) TestPatchShipmentHandlerPmSurvey() {
 	}
 
 	// And: patch shipment is returned
-	handler := PatchShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := PatchShipmentHandler{context}
 	response := handler.Handle(params)
 
 	// Then: expect a 200 status code
@@ -144,6 +154,9 @@ func (suite *HandlerSuite) TestPatchShipmentHandlerPmSurvey() {
 	suite.Equal(genericDate, *(*time.Time)(okResponse.Payload.PmSurveyPlannedPickupDate))
 	suite.Equal(genericDate, *(*time.Time)(okResponse.Payload.PmSurveyPlannedPackDate))
 	suite.Equal(genericDate, *(*time.Time)(okResponse.Payload.PmSurveyConductedDate))
+
+	// And: the service member is told the date of their pre-move survey
+	suite.Equal([]string{notifications.EventPremoveSurveyScheduled}, notificationSender.Events())
 }
 
 func (suite *HandlerSuite) TestPatchShipmentHandlerPmSurveyWrongTSP() {
@@ -182,7 +195,9 @@ func (suite *HandlerSuite) TestPatchShipmentHandlerPmSurveyWrongTSP() {
 	}
 
 	// And: patch shipment is returned
-	handler := PatchShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	context.SetNotificationSender(suite.TestNotificationSender())
+	handler := PatchShipmentHandler{context}
 	response := handler.Handle(params)
 
 	// Then: expect a 400 status code
@@ -614,7 +629,10 @@ func (suite *HandlerSuite) TestAcceptShipmentHandler() {
 	shipment := shipments[0]
 
 	// Handler to Test
-	handler := AcceptShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := AcceptShipmentHandler{context}
 
 	// Test query with first user
 	path := fmt.Sprintf("/shipments/%s/accept", shipment.ID.String())
@@ -629,6 +647,10 @@ func (suite *HandlerSuite) TestAcceptShipmentHandler() {
 	suite.Assertions.IsType(&shipmentop.AcceptShipmentOK{}, response)
 	okResponse := response.(*shipmentop.AcceptShipmentOK)
 	suite.Equal("ACCEPTED", string(okResponse.Payload.Status))
+
+	// And: the service member is told the TSP accepted their shipment
+	suite.Equal([]string{notifications.EventShipmentAccepted}, notificationSender.Events())
+	suite.Equal(shipment.ID, notificationSender.Sent()[0].ShipmentID)
 }
 
 // TestRejectShipmentHandler tests the api endpoint that rejects a shipment
@@ -644,7 +666,10 @@ func (suite *HandlerSuite) TestRejectShipmentHandler() {
 	shipment := shipments[0]
 
 	// Handler to Test
-	handler := RejectShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := RejectShipmentHandler{context}
 
 	// Test query with first user
 	path := fmt.Sprintf("/shipments/%s/reject", shipment.ID.String())
@@ -671,6 +696,9 @@ func (suite *HandlerSuite) TestRejectShipmentHandler() {
 	suite.Equal(false, *shipmentOffer.Accepted)
 	suite.Equal(reason, *shipmentOffer.RejectionReason)
 
+	// And: the office is told the TSP rejected the shipment before it is offered to another TSP
+	suite.Equal(notifications.EventShipmentRejected, notificationSender.Events()[0])
+	suite.Equal(shipment.ID, notificationSender.Sent()[0].ShipmentID)
 }
 
 // TestTransportShipmentHandler tests the api endpoint that transports a shipment
@@ -686,7 +714,10 @@ func (suite *HandlerSuite) TestTransportShipmentHandler() {
 	shipment := shipments[0]
 
 	// Handler to Test
-	handler := TransportShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := TransportShipmentHandler{context}
 
 	// Test query with first user
 	path := fmt.Sprintf("/shipments/%s/transport", shipment.ID.String())
@@ -717,6 +748,9 @@ func (suite *HandlerSuite) TestTransportShipmentHandler() {
 	suite.Equal(int64(2000), *okResponse.Payload.NetWeight)
 	suite.Equal(int64(3000), *okResponse.Payload.GrossWeight)
 	suite.Equal(int64(1000), *okResponse.Payload.TareWeight)
+
+	// And: the service member is told their shipment was picked up
+	suite.Equal([]string{notifications.EventShipmentPickedUp}, notificationSender.Events())
 }
 
 // TestDeliverShipmentHandler tests the api endpoint that delivers a shipment
@@ -732,7 +766,10 @@ func (suite *HandlerSuite) TestDeliverShipmentHandler() {
 	shipment := shipments[0]
 
 	// Handler to Test
-	handler := DeliverShipmentHandler{handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())}
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+	notificationSender := notifications.NewRecordingNotificationSender()
+	context.SetNotificationSender(notificationSender)
+	handler := DeliverShipmentHandler{context}
 	handler.SetPlanner(route.NewTestingPlanner(1044))
 
 	// Test query with first user
@@ -761,4 +798,7 @@ func (suite *HandlerSuite) TestDeliverShipmentHandler() {
 	// The details of the line items are tested in the rateengine package.  We just
 	// check the count here.
 	suite.Len(addedLineItems, 4)
+
+	// And: the service member is told their shipment was delivered
+	suite.Equal([]string{notifications.EventShipmentDelivered}, notificationSender.Events())
 }
@@ -0,0 +1,130 @@
+package publicapi
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+
+	"github.com/go-openapi/runtime/middleware"
+	"github.com/go-openapi/strfmt"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	webhookop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/webhooks"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func payloadForWebhookSubscriptionModel(subscription models.WebhookSubscription) *apimessages.WebhookSubscriptionPayload {
+	return &apimessages.WebhookSubscriptionPayload{
+		ID:        *handlers.FmtUUID(subscription.ID),
+		Event:     apimessages.WebhookEvent(subscription.Event),
+		URL:       handlers.FmtURI(subscription.URL),
+		CreatedAt: strfmt.DateTime(subscription.CreatedAt),
+	}
+}
+
+// newWebhookSecret returns a random secret to sign a webhook's deliveries with
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// IndexWebhookSubscriptionsHandler lists the webhooks a TSP registered
+type IndexWebhookSubscriptionsHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle lists the webhooks of the logged in user's TSP
+func (h IndexWebhookSubscriptionsHandler) Handle(params webhookop.IndexWebhookSubscriptionsParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return webhookop.NewIndexWebhookSubscriptionsForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return webhookop.NewIndexWebhookSubscriptionsForbidden()
+	}
+
+	subscriptions, err := models.FetchWebhookSubscriptions(h.DB(), tspUser.TransportationServiceProviderID, "")
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+
+	payload := make(apimessages.IndexWebhookSubscriptionsPayload, len(subscriptions))
+	for i, subscription := range subscriptions {
+		payload[i] = payloadForWebhookSubscriptionModel(subscription)
+	}
+	return webhookop.NewIndexWebhookSubscriptionsOK().WithPayload(payload)
+}
+
+// CreateWebhookSubscriptionHandler registers a webhook
+type CreateWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle registers a webhook for the logged in user's TSP. The secret is only ever returned here.
+func (h CreateWebhookSubscriptionHandler) Handle(params webhookop.CreateWebhookSubscriptionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return webhookop.NewCreateWebhookSubscriptionForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return webhookop.NewCreateWebhookSubscriptionForbidden()
+	}
+
+	secret, err := newWebhookSecret()
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	subscription := models.WebhookSubscription{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		Event:                           models.WebhookEvent(params.Payload.Event),
+		URL:                             params.Payload.URL.String(),
+		Secret:                          secret,
+	}
+	verrs, err := h.DB().ValidateAndCreate(&subscription)
+	if err != nil || verrs.HasAny() {
+		return handlers.ResponseForVErrors(h.Logger(), verrs, err)
+	}
+
+	payload := payloadForWebhookSubscriptionModel(subscription)
+	payload.Secret = secret
+	return webhookop.NewCreateWebhookSubscriptionCreated().WithPayload(payload)
+}
+
+// DeleteWebhookSubscriptionHandler removes a webhook
+type DeleteWebhookSubscriptionHandler struct {
+	handlers.HandlerContext
+}
+
+// Handle deletes the webhook if the logged in user's TSP registered it. Deliveries still queued for it
+// are dropped.
+func (h DeleteWebhookSubscriptionHandler) Handle(params webhookop.DeleteWebhookSubscriptionParams) middleware.Responder {
+	session := auth.SessionFromRequestContext(params.HTTPRequest)
+	if !session.IsTspUser() {
+		return webhookop.NewDeleteWebhookSubscriptionForbidden()
+	}
+	tspUser, err := models.FetchTspUserByID(h.DB(), session.TspUserID)
+	if err != nil {
+		h.Logger().Error("DB Query", zap.Error(err))
+		return webhookop.NewDeleteWebhookSubscriptionForbidden()
+	}
+
+	subscriptionID, _ := uuid.FromString(params.WebhookSubscriptionID.String())
+	subscription, err := models.FetchWebhookSubscription(h.DB(), tspUser.TransportationServiceProviderID, subscriptionID)
+	if err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	if err := h.DB().Destroy(subscription); err != nil {
+		return handlers.ResponseForError(h.Logger(), err)
+	}
+	return webhookop.NewDeleteWebhookSubscriptionNoContent()
+}
@@ -0,0 +1,61 @@
+package publicapi
+
+import (
+	"net/http/httptest"
+
+	"github.com/go-openapi/strfmt"
+
+	"github.com/transcom/mymove/pkg/gen/apimessages"
+	webhookop "github.com/transcom/mymove/pkg/gen/restapi/apioperations/webhooks"
+	"github.com/transcom/mymove/pkg/handlers"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *HandlerSuite) TestWebhookSubscriptionHandlers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.TestDB())
+	context := handlers.NewHandlerContext(suite.TestDB(), suite.TestLogger())
+
+	// Deliveries must be sent over TLS
+	req := suite.AuthenticateTspRequest(httptest.NewRequest("POST", "/webhook_subscriptions", nil), tspUser)
+	insecureResponse := CreateWebhookSubscriptionHandler{context}.Handle(webhookop.CreateWebhookSubscriptionParams{
+		HTTPRequest: req,
+		Payload: &apimessages.WebhookSubscriptionPayload{
+			Event: apimessages.WebhookEventShipmentAccepted,
+			URL:   handlers.FmtURI("http://tsp.example.com/events"),
+		},
+	})
+	suite.IsType(&handlers.ValidationErrorsResponse{}, insecureResponse)
+
+	createResponse := CreateWebhookSubscriptionHandler{context}.Handle(webhookop.CreateWebhookSubscriptionParams{
+		HTTPRequest: req,
+		Payload: &apimessages.WebhookSubscriptionPayload{
+			Event: apimessages.WebhookEventShipmentAccepted,
+			URL:   handlers.FmtURI("https://tsp.example.com/events"),
+		},
+	})
+	suite.Assertions.IsType(&webhookop.CreateWebhookSubscriptionCreated{}, createResponse)
+	created := createResponse.(*webhookop.CreateWebhookSubscriptionCreated).Payload
+	suite.NotEmpty(created.Secret)
+
+	// The secret isn't shown again
+	indexReq := suite.AuthenticateTspRequest(httptest.NewRequest("GET", "/webhook_subscriptions", nil), tspUser)
+	indexResponse := IndexWebhookSubscriptionsHandler{context}.Handle(webhookop.IndexWebhookSubscriptionsParams{HTTPRequest: indexReq})
+	suite.Assertions.IsType(&webhookop.IndexWebhookSubscriptionsOK{}, indexResponse)
+	indexed := indexResponse.(*webhookop.IndexWebhookSubscriptionsOK).Payload
+	suite.Len(indexed, 1)
+	suite.Equal(created.ID, indexed[0].ID)
+	suite.Empty(indexed[0].Secret)
+
+	// Other TSPs can't remove it
+	deleteParams := webhookop.DeleteWebhookSubscriptionParams{WebhookSubscriptionID: strfmt.UUID(created.ID.String())}
+	otherTspUser := testdatagen.MakeTspUser(suite.TestDB(), testdatagen.Assertions{
+		User: models.User{LoginGovEmail: "other_tsp@example.com"},
+	})
+	deleteParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/webhook_subscriptions/id", nil), otherTspUser)
+	suite.CheckResponseForbidden(DeleteWebhookSubscriptionHandler{context}.Handle(deleteParams))
+
+	deleteParams.HTTPRequest = suite.AuthenticateTspRequest(httptest.NewRequest("DELETE", "/webhook_subscriptions/id", nil), tspUser)
+	deleteResponse := DeleteWebhookSubscriptionHandler{context}.Handle(deleteParams)
+	suite.Assertions.IsType(&webhookop.DeleteWebhookSubscriptionNoContent{}, deleteResponse)
+}
@@ -0,0 +1,308 @@
+package iws
+
+import (
+	"container/list"
+	"net"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// CachingClientParams configures a CachingClient
+type CachingClientParams struct {
+	// MaxAttempts is the total number of times a lookup is tried before giving up. Values below 1 are treated as 1.
+	MaxAttempts int
+	// RetryBackoff is multiplied by the attempt number to get the wait before the next attempt
+	RetryBackoff time.Duration
+	// RetryableFaultCodes lists the RbsError fault codes that indicate a transient IWS problem
+	RetryableFaultCodes []uint64
+	// EdipiTTL is how long a successful lookup is kept in the in-memory EDIPI cache. Zero disables the cache.
+	EdipiTTL time.Duration
+	// EdipiCacheSize bounds the in-memory EDIPI cache; the least recently used entry is evicted when it is full.
+	// Values below 1 use DefaultEdipiCacheSize.
+	EdipiCacheSize int
+	// RateLimit is the most IWS calls made per second, retries included. Zero disables the limit.
+	RateLimit float64
+	// RateBurst is how many calls may be made at once before RateLimit applies. Values below 1 are treated as 1.
+	RateBurst int
+	// SSNCache optionally stores successful SSN lookups. Nil disables it.
+	SSNCache SSNCache
+	Logger   *zap.Logger
+}
+
+// DefaultEdipiCacheSize is the number of people kept in the in-memory EDIPI cache when no size is configured
+const DefaultEdipiCacheSize = 10000
+
+// CachingClient wraps a PersonLookup with retries for transient errors, a rate limit, an in-memory LRU cache
+// keyed by EDIPI, an optional persistent cache for SSN lookups, and call metrics.
+type CachingClient struct {
+	lookup    PersonLookup
+	params    CachingClientParams
+	retryable map[uint64]bool
+	metrics   *Metrics
+	limiter   *rateLimiter
+	now       func() time.Time
+	sleep     func(time.Duration)
+
+	mutex      sync.Mutex
+	edipiCache map[uint64]*list.Element
+	edipiOrder *list.List
+}
+
+type edipiCacheEntry struct {
+	edipi     uint64
+	person    Person
+	personnel []Personnel
+	expiresAt time.Time
+}
+
+// NewCachingClient returns a CachingClient that sends its uncached lookups to the given PersonLookup
+func NewCachingClient(lookup PersonLookup, params CachingClientParams) *CachingClient {
+	if params.MaxAttempts < 1 {
+		params.MaxAttempts = 1
+	}
+	if params.EdipiCacheSize < 1 {
+		params.EdipiCacheSize = DefaultEdipiCacheSize
+	}
+	if params.Logger == nil {
+		params.Logger = zap.NewNop()
+	}
+	var limiter *rateLimiter
+	if params.RateLimit > 0 {
+		limiter = newRateLimiter(params.RateLimit, params.RateBurst)
+	}
+	retryable := make(map[uint64]bool, len(params.RetryableFaultCodes))
+	for _, code := range params.RetryableFaultCodes {
+		retryable[code] = true
+	}
+	return &CachingClient{
+		lookup:     lookup,
+		params:     params,
+		retryable:  retryable,
+		metrics:    newMetrics(),
+		limiter:    limiter,
+		now:        time.Now,
+		sleep:      time.Sleep,
+		edipiCache: make(map[uint64]*list.Element),
+		edipiOrder: list.New(),
+	}
+}
+
+// Metrics returns the metrics collected by this client
+func (c *CachingClient) Metrics() *Metrics {
+	return c.metrics
+}
+
+// LogMetrics writes the counts collected so far to the client's logger, one entry per kind of lookup
+func (c *CachingClient) LogMetrics() {
+	snapshot := c.metrics.Snapshot()
+	for op, o := range snapshot.Operations {
+		var meanLatency time.Duration
+		if looked := o.Calls - o.CacheHits; looked > 0 {
+			meanLatency = o.TotalLatency / time.Duration(looked)
+		}
+		c.params.Logger.Info("IWS lookup metrics",
+			zap.String("op", op),
+			zap.Int("calls", o.Calls),
+			zap.Int("cache_hits", o.CacheHits),
+			zap.Int("retries", o.Retries),
+			zap.Int("errors", o.Errors),
+			zap.Duration("mean_latency", meanLatency),
+			zap.Duration("max_latency", o.MaxLatency))
+	}
+	for reason, count := range snapshot.MatchReasons {
+		c.params.Logger.Info("IWS match reason metrics",
+			zap.String("match_reason", string(reason)),
+			zap.Int("count", count))
+	}
+}
+
+// PurgeExpiredSSNCacheEntries removes expired entries from the persistent SSN cache, if it keeps any
+func (c *CachingClient) PurgeExpiredSSNCacheEntries() error {
+	if purger, ok := c.params.SSNCache.(interface{ DeleteExpired() error }); ok {
+		return purger.DeleteExpired()
+	}
+	return nil
+}
+
+// GetPersonUsingEDIPI returns the cached person for an EDIPI if there is one, otherwise it asks IWS
+func (c *CachingClient) GetPersonUsingEDIPI(edipi uint64) (*Person, []Personnel, error) {
+	if person, personnel, ok := c.cachedEdipi(edipi); ok {
+		c.metrics.record(opEdi, 0, 0, true, "", nil)
+		return person, personnel, nil
+	}
+
+	var person *Person
+	var personnel []Personnel
+	start := c.now()
+	attempts, err := c.withRetries(func() error {
+		var lookupErr error
+		person, personnel, lookupErr = c.lookup.GetPersonUsingEDIPI(edipi)
+		return lookupErr
+	})
+	c.observe(opEdi, c.now().Sub(start), attempts, false, "", err)
+	if err != nil {
+		return nil, []Personnel{}, err
+	}
+
+	c.cacheEdipi(edipi, person, personnel)
+	return person, personnel, nil
+}
+
+// GetPersonUsingSSN returns a stored match for the SSN params if there is one, otherwise it asks IWS.
+// Only matches are stored; a miss is always looked up again.
+func (c *CachingClient) GetPersonUsingSSN(params GetPersonUsingSSNParams) (MatchReasonCode, uint64, *Person, []Personnel, error) {
+	if c.params.SSNCache != nil {
+		result, found, err := c.params.SSNCache.Get(params)
+		if err != nil {
+			// A broken cache shouldn't stop the lookup, IWS is still the source of truth
+			c.params.Logger.Error("Reading IWS SSN cache", zap.Error(err))
+		} else if found {
+			c.metrics.record(opPids, 0, 0, true, result.Reason, nil)
+			c.cacheEdipi(result.Edipi, result.Person, result.Personnel)
+			return result.Reason, result.Edipi, result.Person, result.Personnel, nil
+		}
+	}
+
+	result := SSNLookupResult{Reason: MatchReasonCodeNone}
+	start := c.now()
+	attempts, err := c.withRetries(func() error {
+		var lookupErr error
+		result.Reason, result.Edipi, result.Person, result.Personnel, lookupErr = c.lookup.GetPersonUsingSSN(params)
+		return lookupErr
+	})
+	c.observe(opPids, c.now().Sub(start), attempts, false, result.Reason, err)
+	if err != nil {
+		return MatchReasonCodeNone, 0, nil, []Personnel{}, err
+	}
+
+	if result.Reason != MatchReasonCodeNone && result.Person != nil {
+		c.cacheEdipi(result.Edipi, result.Person, result.Personnel)
+		if c.params.SSNCache != nil {
+			if cacheErr := c.params.SSNCache.Put(params, result); cacheErr != nil {
+				c.params.Logger.Error("Writing IWS SSN cache", zap.Error(cacheErr))
+			}
+		}
+	}
+	return result.Reason, result.Edipi, result.Person, result.Personnel, nil
+}
+
+// GetPersonUsingWorkEmail asks IWS for the person with a work e-mail address. E-mail lookups are not cached,
+// but a match is added to the EDIPI cache.
+func (c *CachingClient) GetPersonUsingWorkEmail(workEmail string) (uint64, *Person, []Personnel, error) {
+	var edipi uint64
+	var person *Person
+	var personnel []Personnel
+	start := c.now()
+	attempts, err := c.withRetries(func() error {
+		var lookupErr error
+		edipi, person, personnel, lookupErr = c.lookup.GetPersonUsingWorkEmail(workEmail)
+		return lookupErr
+	})
+	c.observe(opWkEma, c.now().Sub(start), attempts, false, "", err)
+	if err != nil {
+		return 0, nil, []Personnel{}, err
+	}
+
+	c.cacheEdipi(edipi, person, personnel)
+	return edipi, person, personnel, nil
+}
+
+// withRetries calls fn until it succeeds, fails with a non-transient error, or runs out of attempts.
+// It returns the number of attempts made.
+func (c *CachingClient) withRetries(fn func() error) (int, error) {
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if c.limiter != nil {
+			if wait := c.limiter.reserve(c.now()); wait > 0 {
+				c.sleep(wait)
+			}
+		}
+		err = fn()
+		if err == nil || attempt >= c.params.MaxAttempts || !c.isTransient(err) {
+			break
+		}
+		c.sleep(c.params.RetryBackoff * time.Duration(attempt))
+	}
+	return attempt, err
+}
+
+func (c *CachingClient) isTransient(err error) bool {
+	switch e := err.(type) {
+	case *RbsError:
+		return c.retryable[e.FaultCode]
+	case net.Error:
+		return e.Timeout() || e.Temporary()
+	}
+	return false
+}
+
+func (c *CachingClient) cachedEdipi(edipi uint64) (*Person, []Personnel, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.edipiCache[edipi]
+	if !ok {
+		return nil, nil, false
+	}
+	entry := element.Value.(*edipiCacheEntry)
+	if !c.now().Before(entry.expiresAt) {
+		c.edipiOrder.Remove(element)
+		delete(c.edipiCache, edipi)
+		return nil, nil, false
+	}
+	c.edipiOrder.MoveToFront(element)
+	// Hand out copies so callers can't modify what is cached
+	person := entry.person
+	personnel := append([]Personnel{}, entry.personnel...)
+	return &person, personnel, true
+}
+
+func (c *CachingClient) cacheEdipi(edipi uint64, person *Person, personnel []Personnel) {
+	if c.params.EdipiTTL <= 0 || edipi == 0 || person == nil {
+		return
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	entry := &edipiCacheEntry{
+		edipi:     edipi,
+		person:    *person,
+		personnel: append([]Personnel{}, personnel...),
+		expiresAt: c.now().Add(c.params.EdipiTTL),
+	}
+	if element, ok := c.edipiCache[edipi]; ok {
+		element.Value = entry
+		c.edipiOrder.MoveToFront(element)
+		return
+	}
+	c.edipiCache[edipi] = c.edipiOrder.PushFront(entry)
+
+	for c.edipiOrder.Len() > c.params.EdipiCacheSize {
+		oldest := c.edipiOrder.Back()
+		c.edipiOrder.Remove(oldest)
+		delete(c.edipiCache, oldest.Value.(*edipiCacheEntry).edipi)
+	}
+}
+
+func (c *CachingClient) observe(op string, latency time.Duration, attempts int, cached bool, reason MatchReasonCode, err error) {
+	c.metrics.record(op, latency, attempts, cached, reason, err)
+
+	// Never log the query parameters, they are PII
+	fields := []zap.Field{
+		zap.String("op", op),
+		zap.Duration("latency", latency),
+		zap.Int("attempts", attempts),
+	}
+	if reason != "" {
+		fields = append(fields, zap.String("match_reason", string(reason)))
+	}
+	if err != nil {
+		c.params.Logger.Error("IWS lookup failed", append(fields, zap.Error(err))...)
+		return
+	}
+	c.params.Logger.Info("IWS lookup", fields...)
+}
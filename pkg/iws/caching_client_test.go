@@ -0,0 +1,186 @@
+package iws
+
+import (
+	"errors"
+	"time"
+)
+
+type fakePersonLookup struct {
+	ediCalls  int
+	pidsCalls int
+	errs      []error
+	person    *Person
+	edipi     uint64
+	reason    MatchReasonCode
+}
+
+func (f *fakePersonLookup) nextErr() error {
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *fakePersonLookup) GetPersonUsingEDIPI(edipi uint64) (*Person, []Personnel, error) {
+	f.ediCalls++
+	if err := f.nextErr(); err != nil {
+		return nil, []Personnel{}, err
+	}
+	return f.person, []Personnel{}, nil
+}
+
+func (f *fakePersonLookup) GetPersonUsingSSN(params GetPersonUsingSSNParams) (MatchReasonCode, uint64, *Person, []Personnel, error) {
+	f.pidsCalls++
+	if err := f.nextErr(); err != nil {
+		return MatchReasonCodeNone, 0, nil, []Personnel{}, err
+	}
+	return f.reason, f.edipi, f.person, []Personnel{}, nil
+}
+
+func (f *fakePersonLookup) GetPersonUsingWorkEmail(workEmail string) (uint64, *Person, []Personnel, error) {
+	if err := f.nextErr(); err != nil {
+		return 0, nil, []Personnel{}, err
+	}
+	return f.edipi, f.person, []Personnel{}, nil
+}
+
+type memorySSNCache struct {
+	entries map[GetPersonUsingSSNParams]SSNLookupResult
+}
+
+func (m *memorySSNCache) Get(params GetPersonUsingSSNParams) (SSNLookupResult, bool, error) {
+	result, found := m.entries[params]
+	return result, found, nil
+}
+
+func (m *memorySSNCache) Put(params GetPersonUsingSSNParams, result SSNLookupResult) error {
+	m.entries[params] = result
+	return nil
+}
+
+func (suite *iwsSuite) newTestCachingClient(lookup PersonLookup, params CachingClientParams) (*CachingClient, *time.Time) {
+	params.Logger = suite.logger
+	client := NewCachingClient(lookup, params)
+	now := time.Date(2018, time.November, 16, 12, 0, 0, 0, time.UTC)
+	client.now = func() time.Time { return now }
+	client.sleep = func(time.Duration) {}
+	return client, &now
+}
+
+func (suite *iwsSuite) TestCachingClientCachesEdipi() {
+	lookup := &fakePersonLookup{person: &Person{ID: "123456789", TypeCode: PersonTypeCodeSSN}}
+	client, now := suite.newTestCachingClient(lookup, CachingClientParams{EdipiTTL: time.Hour})
+
+	person, _, err := client.GetPersonUsingEDIPI(1234567890)
+	suite.Nil(err)
+	suite.Equal("123456789", person.ID)
+	person, _, err = client.GetPersonUsingEDIPI(1234567890)
+	suite.Nil(err)
+	suite.Equal("123456789", person.ID)
+	suite.Equal(1, lookup.ediCalls)
+
+	// Expired entries are looked up again
+	*now = now.Add(2 * time.Hour)
+	_, _, err = client.GetPersonUsingEDIPI(1234567890)
+	suite.Nil(err)
+	suite.Equal(2, lookup.ediCalls)
+
+	ops := client.Metrics().Snapshot().Operations
+	suite.Equal(3, ops[opEdi].Calls)
+	suite.Equal(1, ops[opEdi].CacheHits)
+}
+
+func (suite *iwsSuite) TestCachingClientRetriesTransientFaults() {
+	lookup := &fakePersonLookup{
+		person: &Person{ID: "123456789"},
+		errs:   []error{&RbsError{FaultCode: 14030}, &RbsError{FaultCode: 14030}},
+	}
+	client, _ := suite.newTestCachingClient(lookup, CachingClientParams{
+		MaxAttempts:         3,
+		RetryableFaultCodes: []uint64{14030},
+	})
+
+	person, _, err := client.GetPersonUsingEDIPI(1234567890)
+	suite.Nil(err)
+	suite.NotNil(person)
+	suite.Equal(3, lookup.ediCalls)
+	suite.Equal(2, client.Metrics().Snapshot().Operations[opEdi].Retries)
+}
+
+func (suite *iwsSuite) TestCachingClientGivesUpOnOtherErrors() {
+	lookup := &fakePersonLookup{errs: []error{&RbsError{FaultCode: 14030}, errors.New("bad")}}
+	client, _ := suite.newTestCachingClient(lookup, CachingClientParams{MaxAttempts: 3})
+
+	_, _, err := client.GetPersonUsingEDIPI(1234567890)
+	suite.NotNil(err)
+	suite.Equal(1, lookup.ediCalls)
+	suite.Equal(1, client.Metrics().Snapshot().Operations[opEdi].Errors)
+}
+
+func (suite *iwsSuite) TestCachingClientStoresOnlySSNMatches() {
+	cache := &memorySSNCache{entries: map[GetPersonUsingSSNParams]SSNLookupResult{}}
+	lookup := &fakePersonLookup{reason: MatchReasonCodeNone}
+	client, _ := suite.newTestCachingClient(lookup, CachingClientParams{SSNCache: cache, EdipiTTL: time.Hour})
+	params := GetPersonUsingSSNParams{Ssn: "123456789", LastName: "Mantle"}
+
+	reason, _, _, _, err := client.GetPersonUsingSSN(params)
+	suite.Nil(err)
+	suite.Equal(MatchReasonCodeNone, reason)
+	suite.Empty(cache.entries)
+
+	lookup.reason = MatchReasonCodeFull
+	lookup.edipi = 1234567890
+	lookup.person = &Person{ID: "123456789"}
+	_, _, _, _, err = client.GetPersonUsingSSN(params)
+	suite.Nil(err)
+	reason, edipi, _, _, err := client.GetPersonUsingSSN(params)
+	suite.Nil(err)
+	suite.Equal(MatchReasonCodeFull, reason)
+	suite.Equal(uint64(1234567890), edipi)
+	suite.Equal(2, lookup.pidsCalls)
+
+	// The SSN match also primes the EDIPI cache
+	_, _, err = client.GetPersonUsingEDIPI(1234567890)
+	suite.Nil(err)
+	suite.Equal(0, lookup.ediCalls)
+
+	reasons := client.Metrics().Snapshot().MatchReasons
+	suite.Equal(1, reasons[MatchReasonCodeNone])
+	suite.Equal(2, reasons[MatchReasonCodeFull])
+}
+
+func (suite *iwsSuite) TestCachingClientEvictsLeastRecentlyUsedEdipi() {
+	lookup := &fakePersonLookup{person: &Person{ID: "123456789"}}
+	client, _ := suite.newTestCachingClient(lookup, CachingClientParams{EdipiTTL: time.Hour, EdipiCacheSize: 2})
+
+	for _, edipi := range []uint64{1, 2, 1, 3} {
+		_, _, err := client.GetPersonUsingEDIPI(edipi)
+		suite.Nil(err)
+	}
+	suite.Equal(3, lookup.ediCalls)
+	suite.Len(client.edipiCache, 2)
+
+	// 2 was the least recently used, so it is the one that was evicted
+	_, _, err := client.GetPersonUsingEDIPI(1)
+	suite.Nil(err)
+	suite.Equal(3, lookup.ediCalls)
+	_, _, err = client.GetPersonUsingEDIPI(2)
+	suite.Nil(err)
+	suite.Equal(4, lookup.ediCalls)
+}
+
+func (suite *iwsSuite) TestCachingClientRateLimitsLookups() {
+	lookup := &fakePersonLookup{person: &Person{ID: "123456789"}}
+	client, _ := suite.newTestCachingClient(lookup, CachingClientParams{RateLimit: 2, RateBurst: 2})
+	var waits []time.Duration
+	client.sleep = func(d time.Duration) { waits = append(waits, d) }
+
+	for edipi := uint64(1); edipi <= 4; edipi++ {
+		_, _, err := client.GetPersonUsingEDIPI(edipi)
+		suite.Nil(err)
+	}
+	suite.Equal(4, lookup.ediCalls)
+	suite.Equal([]time.Duration{500 * time.Millisecond, time.Second}, waits)
+}
@@ -0,0 +1,86 @@
+package iws
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	opEdi   = "edi"
+	opPids  = "pids"
+	opWkEma = "wkEma"
+)
+
+// OperationMetrics summarizes the calls made for one kind of IWS lookup
+type OperationMetrics struct {
+	Calls        int
+	CacheHits    int
+	Retries      int
+	Errors       int
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// MetricsSnapshot is a point-in-time copy of Metrics
+type MetricsSnapshot struct {
+	Operations   map[string]OperationMetrics
+	MatchReasons map[MatchReasonCode]int
+}
+
+// Metrics counts IWS lookups, their latency and the distribution of SSN match reasons. It is safe for concurrent use.
+type Metrics struct {
+	mutex        sync.Mutex
+	operations   map[string]OperationMetrics
+	matchReasons map[MatchReasonCode]int
+}
+
+func newMetrics() *Metrics {
+	return &Metrics{
+		operations:   make(map[string]OperationMetrics),
+		matchReasons: make(map[MatchReasonCode]int),
+	}
+}
+
+func (m *Metrics) record(op string, latency time.Duration, attempts int, cached bool, reason MatchReasonCode, err error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	o := m.operations[op]
+	o.Calls++
+	if cached {
+		o.CacheHits++
+	}
+	if attempts > 1 {
+		o.Retries += attempts - 1
+	}
+	if err != nil {
+		o.Errors++
+	}
+	o.TotalLatency += latency
+	if latency > o.MaxLatency {
+		o.MaxLatency = latency
+	}
+	m.operations[op] = o
+
+	if reason != "" && err == nil {
+		m.matchReasons[reason]++
+	}
+}
+
+// Snapshot returns a copy of the current counts
+func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Operations:   make(map[string]OperationMetrics, len(m.operations)),
+		MatchReasons: make(map[MatchReasonCode]int, len(m.matchReasons)),
+	}
+	for op, o := range m.operations {
+		snapshot.Operations[op] = o
+	}
+	for reason, count := range m.matchReasons {
+		snapshot.MatchReasons[reason] = count
+	}
+	return snapshot
+}
@@ -0,0 +1,9 @@
+package iws
+
+// PersonLookup is the set of IWS:RBS queries used by MyMove. It is satisfied by RealTimeBrokerService
+// as well as by CachingClient, so callers can be handed either one.
+type PersonLookup interface {
+	GetPersonUsingEDIPI(edipi uint64) (*Person, []Personnel, error)
+	GetPersonUsingSSN(params GetPersonUsingSSNParams) (MatchReasonCode, uint64, *Person, []Personnel, error)
+	GetPersonUsingWorkEmail(workEmail string) (uint64, *Person, []Personnel, error)
+}
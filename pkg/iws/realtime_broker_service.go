@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/transcom/mymove/pkg/server"
 )
@@ -80,8 +81,8 @@ func (r RealTimeBrokerService) GetPersonUsingWorkEmail(workEmail string) (uint64
 }
 
 // NewRealTimeBrokerService creates a new instance of RealtimeBrokerService. This should
-// only be instantiated once
-func NewRealTimeBrokerService(host string, dodCACertPackage string, certString string, keyString string) (*RealTimeBrokerService, error) {
+// only be instantiated once. A zero timeout means requests to IWS never time out.
+func NewRealTimeBrokerService(host string, dodCACertPackage string, certString string, keyString string, timeout time.Duration) (*RealTimeBrokerService, error) {
 	if host == "" {
 		return nil, errors.New("IWS host is not set")
 	}
@@ -111,7 +112,7 @@ func NewRealTimeBrokerService(host string, dodCACertPackage string, certString s
 	transport := &http.Transport{TLSClientConfig: tlsConfig}
 
 	return &RealTimeBrokerService{
-		Client: http.Client{Transport: transport},
+		Client: http.Client{Transport: transport, Timeout: timeout},
 		Host:   host,
 	}, nil
 }
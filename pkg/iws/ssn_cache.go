@@ -0,0 +1,174 @@
+package iws
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// SSNLookupResult is everything returned by a successful GetPersonUsingSSN call
+type SSNLookupResult struct {
+	Reason    MatchReasonCode
+	Edipi     uint64
+	Person    *Person
+	Personnel []Personnel
+}
+
+// SSNCache stores the results of SSN lookups that matched a person
+type SSNCache interface {
+	Get(params GetPersonUsingSSNParams) (SSNLookupResult, bool, error)
+	Put(params GetPersonUsingSSNParams, result SSNLookupResult) error
+}
+
+// DBSSNCache is an SSNCache that keeps its entries in the database, encrypted with AES-GCM.
+// Entries are keyed by an HMAC of the query parameters so the SSN itself is never stored.
+type DBSSNCache struct {
+	db     *pop.Connection
+	cipher ssnCacheCipher
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewDBSSNCache returns a DBSSNCache whose encryption and digest keys are derived from secret
+func NewDBSSNCache(db *pop.Connection, secret string, ttl time.Duration) (*DBSSNCache, error) {
+	c, err := newSSNCacheCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &DBSSNCache{
+		db:     db,
+		cipher: c,
+		ttl:    ttl,
+		now:    time.Now,
+	}, nil
+}
+
+// Get returns the unexpired entry for the params, if there is one
+func (c *DBSSNCache) Get(params GetPersonUsingSSNParams) (SSNLookupResult, bool, error) {
+	entry, err := models.FetchUnexpiredIwsPersonCacheEntry(c.db, c.cipher.digest(params), c.now())
+	if err == models.ErrFetchNotFound {
+		return SSNLookupResult{}, false, nil
+	} else if err != nil {
+		return SSNLookupResult{}, false, err
+	}
+
+	result, err := c.cipher.open(entry.EncryptedPayload)
+	if err != nil {
+		return SSNLookupResult{}, false, err
+	}
+	return result, true, nil
+}
+
+// Put stores the result for the params until the cache TTL has passed
+func (c *DBSSNCache) Put(params GetPersonUsingSSNParams, result SSNLookupResult) error {
+	payload, err := c.cipher.seal(result)
+	if err != nil {
+		return err
+	}
+
+	verrs, err := models.SaveIwsPersonCacheEntry(c.db, c.cipher.digest(params), payload, c.now().Add(c.ttl))
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return errors.New(verrs.Error())
+	}
+	return nil
+}
+
+// DeleteExpired removes every entry whose TTL has passed
+func (c *DBSSNCache) DeleteExpired() error {
+	return models.DeleteExpiredIwsPersonCacheEntries(c.db, c.now())
+}
+
+type ssnCacheCipher struct {
+	aead      cipher.AEAD
+	digestKey []byte
+}
+
+func newSSNCacheCipher(secret string) (ssnCacheCipher, error) {
+	if len(secret) < 32 {
+		return ssnCacheCipher{}, errors.New("IWS cache key must be at least 32 characters")
+	}
+
+	// Use separate keys for encryption and hashing, both derived from the one configured secret
+	encryptionKey := deriveKey(secret, "iws-cache-encryption")
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return ssnCacheCipher{}, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return ssnCacheCipher{}, err
+	}
+
+	return ssnCacheCipher{
+		aead:      aead,
+		digestKey: deriveKey(secret, "iws-cache-digest"),
+	}, nil
+}
+
+func deriveKey(secret string, label string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(label))
+	return mac.Sum(nil)
+}
+
+// digest returns a stable, keyed hash of the lookup params. Names are compared case-insensitively, as IWS does.
+func (c ssnCacheCipher) digest(params GetPersonUsingSSNParams) string {
+	mac := hmac.New(sha256.New, c.digestKey)
+	mac.Write([]byte(params.Ssn))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strings.ToUpper(strings.TrimSpace(params.LastName))))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strings.ToUpper(strings.TrimSpace(params.FirstName))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c ssnCacheCipher) seal(result SSNLookupResult) (string, error) {
+	plaintext, err := json.Marshal(result)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", err
+	}
+
+	sealed := c.aead.Seal(nonce, nonce, plaintext, nil)
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+func (c ssnCacheCipher) open(payload string) (SSNLookupResult, error) {
+	result := SSNLookupResult{}
+	sealed, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		return result, err
+	}
+
+	nonceSize := c.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return result, errors.New("IWS cache payload is too short")
+	}
+
+	plaintext, err := c.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return result, err
+	}
+
+	err = json.Unmarshal(plaintext, &result)
+	return result, err
+}
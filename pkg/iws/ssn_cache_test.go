@@ -0,0 +1,43 @@
+package iws
+
+func (suite *iwsSuite) TestSSNCacheCipherRoundTrip() {
+	c, err := newSSNCacheCipher("0123456789abcdef0123456789abcdef")
+	suite.Nil(err)
+
+	result := SSNLookupResult{
+		Reason: MatchReasonCodeFull,
+		Edipi:  1234567890,
+		Person: &Person{ID: "123456789", TypeCode: PersonTypeCodeSSN, LastName: "Mantle"},
+	}
+	payload, err := c.seal(result)
+	suite.Nil(err)
+	suite.NotContains(payload, "Mantle")
+
+	opened, err := c.open(payload)
+	suite.Nil(err)
+	suite.Equal(result.Reason, opened.Reason)
+	suite.Equal(result.Edipi, opened.Edipi)
+	suite.Equal(*result.Person, *opened.Person)
+
+	// A payload sealed with another key can't be opened
+	other, err := newSSNCacheCipher("fedcba9876543210fedcba9876543210")
+	suite.Nil(err)
+	_, err = other.open(payload)
+	suite.NotNil(err)
+}
+
+func (suite *iwsSuite) TestSSNCacheCipherDigest() {
+	c, err := newSSNCacheCipher("0123456789abcdef0123456789abcdef")
+	suite.Nil(err)
+
+	params := GetPersonUsingSSNParams{Ssn: "123456789", LastName: "Mantle", FirstName: "Mickey"}
+	digest := c.digest(params)
+	suite.NotContains(digest, "123456789")
+	suite.Equal(digest, c.digest(GetPersonUsingSSNParams{Ssn: "123456789", LastName: "MANTLE ", FirstName: "mickey"}))
+	suite.NotEqual(digest, c.digest(GetPersonUsingSSNParams{Ssn: "123456780", LastName: "Mantle", FirstName: "Mickey"}))
+}
+
+func (suite *iwsSuite) TestSSNCacheCipherRequiresLongSecret() {
+	_, err := newSSNCacheCipher("short")
+	suite.NotNil(err)
+}
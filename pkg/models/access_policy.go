@@ -0,0 +1,273 @@
+package models
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// AccessRole is the role a session acts in when accessing records
+type AccessRole string
+
+const (
+	// AccessRoleNone is a session that can't be tied to any kind of user
+	AccessRoleNone AccessRole = "NONE"
+	// AccessRoleServiceMember is a service member using my.move.mil
+	AccessRoleServiceMember AccessRole = "SERVICE_MEMBER"
+	// AccessRoleOfficeUser is a transportation office user
+	AccessRoleOfficeUser AccessRole = "OFFICE_USER"
+	// AccessRoleOfficeSupervisor is a transportation office user who can reassign work
+	AccessRoleOfficeSupervisor AccessRole = "OFFICE_SUPERVISOR"
+	// AccessRoleTspUser is a user working for a Transportation Service Provider
+	AccessRoleTspUser AccessRole = "TSP_USER"
+)
+
+// OfficeAccessScope determines which moves an office user can access
+type OfficeAccessScope string
+
+const (
+	// OfficeAccessScopeGBLOC allows access to moves whose origin or destination is in the office user's GBLOC
+	OfficeAccessScopeGBLOC OfficeAccessScope = "GBLOC"
+	// OfficeAccessScopeTransportationOffice allows access to moves whose origin or destination office is the office user's own
+	OfficeAccessScopeTransportationOffice OfficeAccessScope = "TRANSPORTATION_OFFICE"
+	// OfficeAccessScopeNational allows access to every move, for headquarters staff
+	OfficeAccessScopeNational OfficeAccessScope = "NATIONAL"
+)
+
+// Moves are tied to an office through the service member's current duty station (origin) and the
+// orders' new duty station (destination).
+const officeMoveScopeFrom = `
+	FROM moves
+	JOIN orders ON moves.orders_id = orders.id
+	JOIN service_members ON orders.service_member_id = service_members.id
+	JOIN duty_stations new_station ON orders.new_duty_station_id = new_station.id
+	LEFT JOIN duty_stations current_station ON service_members.duty_station_id = current_station.id
+	LEFT JOIN transportation_offices origin_office ON current_station.transportation_office_id = origin_office.id
+	LEFT JOIN transportation_offices destination_office ON new_station.transportation_office_id = destination_office.id
+`
+
+const officeMoveScopeGBLOCCondition = `(
+	origin_office.gbloc = ?
+	OR destination_office.gbloc = ?
+	OR EXISTS (
+		SELECT 1 FROM shipments
+		WHERE shipments.move_id = moves.id
+		AND (shipments.source_gbloc = ? OR shipments.destination_gbloc = ?)
+	)
+)`
+
+const officeMoveScopeOfficeCondition = `(origin_office.id = ? OR destination_office.id = ?)`
+
+// An active TSP user can access a shipment once it has been offered to their TSP, unless the offer was rejected
+const tspShipmentScopeQuery = `
+	SELECT shipments.id FROM shipments
+	JOIN shipment_offers ON shipment_offers.shipment_id = shipments.id
+	JOIN tsp_users ON tsp_users.transportation_service_provider_id = shipment_offers.transportation_service_provider_id
+	WHERE tsp_users.id = $1
+	AND tsp_users.deactivated = false
+	AND shipment_offers.accepted IS NOT FALSE
+`
+
+// FetchAccessRole returns the role the session is acting in
+func FetchAccessRole(db *pop.Connection, session *auth.Session) (AccessRole, error) {
+	switch {
+	case session.IsOfficeApp() && session.IsOfficeUser():
+		officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID)
+		if err != nil {
+			return AccessRoleNone, err
+		}
+		if officeUser.Deactivated {
+			return AccessRoleNone, nil
+		}
+		if officeUser.IsSupervisor {
+			return AccessRoleOfficeSupervisor, nil
+		}
+		return AccessRoleOfficeUser, nil
+	case session.IsTspApp() && session.IsTspUser():
+		tspUser, err := FetchTspUserByID(db, session.TspUserID)
+		if err != nil {
+			return AccessRoleNone, err
+		}
+		if tspUser.Deactivated {
+			return AccessRoleNone, nil
+		}
+		return AccessRoleTspUser, nil
+	case session.IsMyApp() && session.IsServiceMember():
+		return AccessRoleServiceMember, nil
+	}
+	return AccessRoleNone, nil
+}
+
+// AuthorizeReassignment returns ErrFetchForbidden unless the session belongs to an office supervisor
+func AuthorizeReassignment(db *pop.Connection, session *auth.Session) error {
+	role, err := FetchAccessRole(db, session)
+	if err != nil {
+		return err
+	}
+	if role != AccessRoleOfficeSupervisor {
+		return denyAccess(session, "reassignment", uuid.Nil, "not a supervisor")
+	}
+	return nil
+}
+
+// AuthorizeAdministration returns ErrFetchForbidden unless the session belongs to an office user who
+// administers the system, e.g. managing users and feature flags
+func AuthorizeAdministration(db *pop.Connection, session *auth.Session) error {
+	if !session.IsOfficeApp() || !session.IsOfficeUser() {
+		return denyAccess(session, "administration", uuid.Nil, "not an office user")
+	}
+	officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID)
+	if err != nil {
+		return err
+	}
+	if officeUser.Deactivated {
+		return denyAccess(session, "administration", uuid.Nil, "deactivated")
+	}
+	if !officeUser.IsAdmin {
+		return denyAccess(session, "administration", uuid.Nil, "not an admin")
+	}
+	return nil
+}
+
+// AuthorizeMoveAccess checks whether the session may access a move. It returns ErrFetchForbidden
+// and logs the denial if it may not.
+func AuthorizeMoveAccess(db *pop.Connection, session *auth.Session, moveID uuid.UUID) error {
+	switch {
+	case session.IsMyApp():
+		var move Move
+		err := db.Q().Eager("Orders").Find(&move, moveID)
+		if err != nil {
+			if errors.Cause(err).Error() == recordNotFoundErrorString {
+				return ErrFetchNotFound
+			}
+			return err
+		}
+		if move.Orders.ServiceMemberID != session.ServiceMemberID {
+			return denyAccess(session, "move", moveID, "move belongs to another service member")
+		}
+	case session.IsOfficeApp():
+		query, args, err := officeScopeQuery(db, session, "moves.id = ?", moveID)
+		if err != nil {
+			return denyAccess(session, "move", moveID, err.Error())
+		}
+		count, err := db.RawQuery(query, args...).Count(Move{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return denyAccess(session, "move", moveID, "move is outside of office user's scope")
+		}
+	case session.IsTspApp():
+		count, err := db.RawQuery(tspShipmentScopeQuery+" AND shipments.move_id = $2", session.TspUserID, moveID).Count(Shipment{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return denyAccess(session, "move", moveID, "move has no shipments offered to TSP")
+		}
+	}
+	return nil
+}
+
+// AuthorizeShipmentAccess checks whether the session may access a shipment. It returns ErrFetchForbidden
+// and logs the denial if it may not.
+func AuthorizeShipmentAccess(db *pop.Connection, session *auth.Session, shipment *Shipment) error {
+	if session.IsTspApp() {
+		count, err := db.RawQuery(tspShipmentScopeQuery+" AND shipments.id = $2", session.TspUserID, shipment.ID).Count(Shipment{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return denyAccess(session, "shipment", shipment.ID, "shipment was not offered to TSP")
+		}
+		return nil
+	}
+	return AuthorizeMoveAccess(db, session, shipment.MoveID)
+}
+
+// AuthorizeServiceMemberAccess checks whether the session may access a service member. It returns ErrFetchForbidden
+// and logs the denial if it may not.
+func AuthorizeServiceMemberAccess(db *pop.Connection, session *auth.Session, serviceMemberID uuid.UUID) error {
+	switch {
+	case session.IsMyApp():
+		if serviceMemberID != session.ServiceMemberID {
+			return denyAccess(session, "service_member", serviceMemberID, "not the logged in service member")
+		}
+	case session.IsOfficeApp():
+		query, args, err := officeScopeQuery(db, session, "service_members.id = ?", serviceMemberID)
+		if err != nil {
+			return denyAccess(session, "service_member", serviceMemberID, err.Error())
+		}
+		count, err := db.RawQuery(query, args...).Count(Move{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return denyAccess(session, "service_member", serviceMemberID, "service member has no moves in office user's scope")
+		}
+	case session.IsTspApp():
+		count, err := db.RawQuery(tspShipmentScopeQuery+" AND shipments.service_member_id = $2", session.TspUserID, serviceMemberID).Count(Shipment{})
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			return denyAccess(session, "service_member", serviceMemberID, "service member has no shipments offered to TSP")
+		}
+	}
+	return nil
+}
+
+// officeScopeQuery builds a query selecting the IDs of moves matching filter that the session's office user
+// may access, along with its args. filterArgs are the values for the placeholders in filter.
+func officeScopeQuery(db *pop.Connection, session *auth.Session, filter string, filterArgs ...interface{}) (string, []interface{}, error) {
+	condition, conditionArgs, err := officeMoveScope(db, session)
+	if err != nil {
+		return "", nil, err
+	}
+	query := "SELECT moves.id" + officeMoveScopeFrom + "WHERE " + filter + " AND " + condition
+	return query, append(filterArgs, conditionArgs...), nil
+}
+
+// officeMoveScope returns a condition limiting moves to those the session's office user may access, and its args.
+// The condition must be used with officeMoveScopeFrom.
+func officeMoveScope(db *pop.Connection, session *auth.Session) (string, []interface{}, error) {
+	if !session.IsOfficeUser() {
+		return "", nil, errors.New("session has no office user")
+	}
+	officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "fetching office user")
+	}
+	if officeUser.Deactivated {
+		return "FALSE", []interface{}{}, nil
+	}
+
+	switch officeUser.AccessScope {
+	case OfficeAccessScopeNational:
+		return "TRUE", []interface{}{}, nil
+	case OfficeAccessScopeTransportationOffice:
+		officeID := officeUser.TransportationOfficeID
+		return officeMoveScopeOfficeCondition, []interface{}{officeID, officeID}, nil
+	}
+	gbloc := officeUser.TransportationOffice.Gbloc
+	return officeMoveScopeGBLOCCondition, []interface{}{gbloc, gbloc, gbloc, gbloc}, nil
+}
+
+// denyAccess logs an access denial for audit and returns ErrFetchForbidden
+func denyAccess(session *auth.Session, resource string, id uuid.UUID, reason string) error {
+	zap.L().Warn("Access denied",
+		zap.String("audit", "access_denied"),
+		zap.String("resource", resource),
+		zap.String("resource_id", id.String()),
+		zap.String("reason", reason),
+		zap.String("application", string(session.ApplicationName)),
+		zap.String("user_id", session.UserID.String()),
+		zap.String("service_member_id", session.ServiceMemberID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()),
+		zap.String("tsp_user_id", session.TspUserID.String()),
+	)
+	return ErrFetchForbidden
+}
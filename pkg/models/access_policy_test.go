@@ -0,0 +1,137 @@
+package models_test
+
+import (
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/auth"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func officeSession(officeUser OfficeUser) *auth.Session {
+	return &auth.Session{
+		ApplicationName: auth.OfficeApp,
+		UserID:          *officeUser.UserID,
+		OfficeUserID:    officeUser.ID,
+	}
+}
+
+func (suite *ModelSuite) TestAuthorizeMoveAccessForOfficeUsers() {
+	// Default test moves originate from a duty station whose office is in GBLOC LKBM
+	move := testdatagen.MakeDefaultMove(suite.db)
+	otherOffice := testdatagen.MakeTransportationOffice(suite.db, testdatagen.Assertions{
+		TransportationOffice: TransportationOffice{Gbloc: "AGFM"},
+	})
+
+	inGbloc := testdatagen.MakeDefaultOfficeUser(suite.db)
+	suite.Nil(AuthorizeMoveAccess(suite.db, officeSession(inGbloc), move.ID))
+
+	outOfGbloc := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User: User{LoginGovEmail: "out_of_gbloc@example.com"},
+		OfficeUser: OfficeUser{
+			TransportationOffice:   otherOffice,
+			TransportationOfficeID: otherOffice.ID,
+		},
+	})
+	suite.Equal(ErrFetchForbidden, AuthorizeMoveAccess(suite.db, officeSession(outOfGbloc), move.ID))
+
+	_, err := FetchMove(suite.db, officeSession(outOfGbloc), move.ID)
+	suite.Equal(ErrFetchForbidden, err)
+
+	// Office-scoped users only see moves to or from their own office, even within their GBLOC
+	officeScoped := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User:       User{LoginGovEmail: "office_scoped@example.com"},
+		OfficeUser: OfficeUser{AccessScope: OfficeAccessScopeTransportationOffice},
+	})
+	suite.Equal(ErrFetchForbidden, AuthorizeMoveAccess(suite.db, officeSession(officeScoped), move.ID))
+
+	national := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User: User{LoginGovEmail: "national@example.com"},
+		OfficeUser: OfficeUser{
+			TransportationOffice:   otherOffice,
+			TransportationOfficeID: otherOffice.ID,
+			AccessScope:            OfficeAccessScopeNational,
+		},
+	})
+	suite.Nil(AuthorizeMoveAccess(suite.db, officeSession(national), move.ID))
+
+	// Office sessions without an office user are always denied
+	session := &auth.Session{ApplicationName: auth.OfficeApp, UserID: *inGbloc.UserID}
+	suite.Equal(ErrFetchForbidden, AuthorizeMoveAccess(suite.db, session, move.ID))
+}
+
+func (suite *ModelSuite) TestAuthorizeMoveAccessForOfficeUsersByShipmentGBLOC() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	office := testdatagen.MakeTransportationOffice(suite.db, testdatagen.Assertions{
+		TransportationOffice: TransportationOffice{Gbloc: *shipment.DestinationGBLOC},
+	})
+	officeUser := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		OfficeUser: OfficeUser{
+			TransportationOffice:   office,
+			TransportationOfficeID: office.ID,
+		},
+	})
+
+	suite.Nil(AuthorizeShipmentAccess(suite.db, officeSession(officeUser), &shipment))
+}
+
+func (suite *ModelSuite) TestAuthorizeShipmentAccessForTspUsers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.db)
+	offer := testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		ShipmentOffer: ShipmentOffer{
+			TransportationServiceProvider:   tspUser.TransportationServiceProvider,
+			TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		},
+	})
+	otherShipment := testdatagen.MakeDefaultShipment(suite.db)
+
+	session := &auth.Session{
+		ApplicationName: auth.TspApp,
+		UserID:          *tspUser.UserID,
+		TspUserID:       tspUser.ID,
+	}
+	suite.Nil(AuthorizeShipmentAccess(suite.db, session, &offer.Shipment))
+	suite.Nil(AuthorizeMoveAccess(suite.db, session, offer.Shipment.MoveID))
+	suite.Nil(AuthorizeServiceMemberAccess(suite.db, session, offer.Shipment.ServiceMemberID))
+
+	suite.Equal(ErrFetchForbidden, AuthorizeShipmentAccess(suite.db, session, &otherShipment))
+	suite.Equal(ErrFetchForbidden, AuthorizeMoveAccess(suite.db, session, otherShipment.MoveID))
+}
+
+func (suite *ModelSuite) TestAuthorizeShipmentAccessDeniesDeactivatedTspUsers() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.db)
+	offer := testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		ShipmentOffer: ShipmentOffer{
+			TransportationServiceProvider:   tspUser.TransportationServiceProvider,
+			TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		},
+	})
+	tspUser.Deactivated = true
+	suite.mustSave(&tspUser)
+
+	session := &auth.Session{
+		ApplicationName: auth.TspApp,
+		UserID:          *tspUser.UserID,
+		TspUserID:       tspUser.ID,
+	}
+	suite.Equal(ErrFetchForbidden, AuthorizeShipmentAccess(suite.db, session, &offer.Shipment))
+	suite.Equal(ErrFetchForbidden, AuthorizeMoveAccess(suite.db, session, offer.Shipment.MoveID))
+	suite.Equal(ErrFetchForbidden, AuthorizeServiceMemberAccess(suite.db, session, offer.Shipment.ServiceMemberID))
+}
+
+func (suite *ModelSuite) TestAuthorizeReassignment() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	suite.Equal(ErrFetchForbidden, AuthorizeReassignment(suite.db, officeSession(officeUser)))
+
+	supervisor := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User:       User{LoginGovEmail: "supervisor@example.com"},
+		OfficeUser: OfficeUser{IsSupervisor: true},
+	})
+	role, err := FetchAccessRole(suite.db, officeSession(supervisor))
+	suite.Nil(err)
+	suite.Equal(AccessRoleOfficeSupervisor, role)
+	suite.Nil(AuthorizeReassignment(suite.db, officeSession(supervisor)))
+
+	session := &auth.Session{ApplicationName: auth.MyApp, ServiceMemberID: uuid.Must(uuid.NewV4())}
+	suite.Equal(ErrFetchForbidden, AuthorizeReassignment(suite.db, session))
+}
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// AdvanceLedgerEntry tracks the advance of one PPM from request through disbursement and settlement
+type AdvanceLedgerEntry struct {
+	PersonallyProcuredMoveID uuid.UUID
+	ReimbursementID          uuid.UUID
+	Status                   ReimbursementStatus
+	MethodOfReceipt          MethodOfReceipt
+	Requested                unit.Cents
+	Approved                 unit.Cents
+	Disbursed                unit.Cents
+	DisbursedDate            *time.Time
+	Incentive                *unit.Cents
+	Deducted                 unit.Cents
+	RecoupmentOwed           unit.Cents
+}
+
+// AdvanceLedger is every PPM advance of a move with their totals
+type AdvanceLedger struct {
+	MoveID              uuid.UUID
+	Entries             []AdvanceLedgerEntry
+	TotalRequested      unit.Cents
+	TotalApproved       unit.Cents
+	TotalDisbursed      unit.Cents
+	TotalDeducted       unit.Cents
+	TotalRecoupmentOwed unit.Cents
+}
+
+// newAdvanceLedgerEntry builds the ledger entry of a PPM's advance. Advances are deducted from the
+// incentive once the PPM is settled, and anything disbursed beyond the incentive is owed back.
+func newAdvanceLedgerEntry(ppm PersonallyProcuredMove) AdvanceLedgerEntry {
+	advance := *ppm.Advance
+	entry := AdvanceLedgerEntry{
+		PersonallyProcuredMoveID: ppm.ID,
+		ReimbursementID:          advance.ID,
+		Status:                   advance.Status,
+		MethodOfReceipt:          advance.MethodOfReceipt,
+		Requested:                advance.RequestedAmount,
+		DisbursedDate:            advance.PaidDate,
+		Incentive:                ppm.IncentiveActual,
+	}
+	if advance.ApprovedAmount != nil {
+		entry.Approved = *advance.ApprovedAmount
+	}
+	if advance.Status == ReimbursementStatusPAID && advance.PaidAmount != nil {
+		entry.Disbursed = *advance.PaidAmount
+	}
+	if ppm.AdvanceDeduction != nil {
+		entry.Deducted = *ppm.AdvanceDeduction
+	}
+	if ppm.SettlementAmount != nil && *ppm.SettlementAmount < 0 {
+		entry.RecoupmentOwed = -*ppm.SettlementAmount
+	}
+	return entry
+}
+
+// FetchAdvanceLedger returns the advance ledger of a move if the user may access it
+func FetchAdvanceLedger(db *pop.Connection, session *auth.Session, moveID uuid.UUID) (*AdvanceLedger, error) {
+	if err := AuthorizeMoveAccess(db, session, moveID); err != nil {
+		return nil, err
+	}
+
+	var ppms PersonallyProcuredMoves
+	err := db.Eager("Advance").
+		Where("move_id = $1 AND advance_id IS NOT NULL", moveID).
+		Order("created_at asc").
+		All(&ppms)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := AdvanceLedger{MoveID: moveID, Entries: []AdvanceLedgerEntry{}}
+	for _, ppm := range ppms {
+		if ppm.Advance == nil {
+			continue
+		}
+		entry := newAdvanceLedgerEntry(ppm)
+		ledger.Entries = append(ledger.Entries, entry)
+		ledger.TotalRequested += entry.Requested
+		ledger.TotalApproved += entry.Approved
+		ledger.TotalDisbursed += entry.Disbursed
+		ledger.TotalDeducted += entry.Deducted
+		ledger.TotalRecoupmentOwed += entry.RecoupmentOwed
+	}
+	return &ledger, nil
+}
+
+// AdvanceDisbursement is an approved advance waiting to be paid by finance
+type AdvanceDisbursement struct {
+	ReimbursementID uuid.UUID       `db:"reimbursement_id"`
+	MoveID          uuid.UUID       `db:"move_id"`
+	Locator         string          `db:"locator"`
+	Edipi           *string         `db:"edipi"`
+	LastName        *string         `db:"last_name"`
+	FirstName       *string         `db:"first_name"`
+	MethodOfReceipt MethodOfReceipt `db:"method_of_receipt"`
+	ApprovedAmount  unit.Cents      `db:"approved_amount"`
+	ApprovedAt      time.Time       `db:"approved_at"`
+}
+
+// FetchAdvanceDisbursements returns the approved PPM advances that haven't been sent to finance yet,
+// oldest first. It performs no authorization.
+func FetchAdvanceDisbursements(db *pop.Connection) ([]AdvanceDisbursement, error) {
+	sql := `
+		SELECT reimbursements.id AS reimbursement_id,
+			moves.id AS move_id,
+			moves.locator,
+			service_members.edipi,
+			service_members.last_name,
+			service_members.first_name,
+			reimbursements.method_of_receipt,
+			COALESCE(reimbursements.approved_amount, reimbursements.requested_amount) AS approved_amount,
+			reimbursements.updated_at AS approved_at
+		FROM reimbursements
+		JOIN personally_procured_moves ON personally_procured_moves.advance_id = reimbursements.id
+		JOIN moves ON moves.id = personally_procured_moves.move_id
+		JOIN orders ON orders.id = moves.orders_id
+		JOIN service_members ON service_members.id = orders.service_member_id
+		WHERE reimbursements.status = ?
+		AND reimbursements.disbursement_requested_at IS NULL
+		ORDER BY reimbursements.updated_at ASC
+	`
+	disbursements := []AdvanceDisbursement{}
+	err := db.RawQuery(sql, ReimbursementStatusAPPROVED).All(&disbursements)
+	return disbursements, err
+}
+
+// MarkDisbursementsRequested records that the advances were sent to finance so they aren't exported again
+func MarkDisbursementsRequested(db *pop.Connection, disbursements []AdvanceDisbursement, at time.Time) error {
+	if len(disbursements) == 0 {
+		return nil
+	}
+	placeholders := make([]string, len(disbursements))
+	args := []interface{}{at}
+	for i, disbursement := range disbursements {
+		placeholders[i] = "?"
+		args = append(args, disbursement.ReimbursementID)
+	}
+	sql := "UPDATE reimbursements SET disbursement_requested_at = ? WHERE id IN (" + strings.Join(placeholders, ", ") + ")"
+	err := db.RawQuery(sql, args...).Exec()
+	return errors.Wrap(err, "marking disbursements requested")
+}
@@ -0,0 +1,97 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/transcom/mymove/pkg/auth"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ModelSuite) TestSettleAdvance() {
+	incentive := unit.Cents(80000)
+	ppm := PersonallyProcuredMove{IncentiveActual: &incentive}
+
+	// Without an advance the whole incentive is owed
+	ppm.SettleAdvance()
+	suite.Equal(unit.Cents(0), *ppm.AdvanceDeduction)
+	suite.Equal(unit.Cents(80000), *ppm.SettlementAmount)
+
+	// Approved advances aren't deducted until finance disburses them
+	advance := BuildDraftReimbursement(100000, MethodOfReceiptMILPAY)
+	advance.Request()
+	advance.Approve()
+	ppm.Advance = &advance
+	ppm.SettleAdvance()
+	suite.Equal(unit.Cents(0), *ppm.AdvanceDeduction)
+
+	// Disbursing more than the incentive leaves an amount to recoup
+	advance.Disburse(100000, time.Now())
+	ppm.SettleAdvance()
+	suite.Equal(unit.Cents(100000), *ppm.AdvanceDeduction)
+	suite.Equal(unit.Cents(-20000), *ppm.SettlementAmount)
+}
+
+func (suite *ModelSuite) TestFetchAdvanceLedger() {
+	ppm := testdatagen.MakeDefaultPPM(suite.db)
+	advance := ppm.Advance
+	suite.Nil(advance.Request())
+	suite.Nil(advance.Approve())
+	suite.Nil(advance.Disburse(900, time.Now()))
+	suite.mustSave(advance)
+
+	incentive := unit.Cents(600)
+	ppm.IncentiveActual = &incentive
+	ppm.SettleAdvance()
+	suite.mustSave(&ppm)
+
+	session := &auth.Session{
+		ApplicationName: auth.MyApp,
+		UserID:          ppm.Move.Orders.ServiceMember.UserID,
+		ServiceMemberID: ppm.Move.Orders.ServiceMemberID,
+	}
+	ledger, err := FetchAdvanceLedger(suite.db, session, ppm.MoveID)
+	suite.Nil(err)
+	suite.Len(ledger.Entries, 1)
+	entry := ledger.Entries[0]
+	suite.Equal(advance.ID, entry.ReimbursementID)
+	suite.Equal(unit.Cents(1000), entry.Requested)
+	suite.Equal(unit.Cents(1000), entry.Approved)
+	suite.Equal(unit.Cents(900), entry.Disbursed)
+	suite.Equal(unit.Cents(900), entry.Deducted)
+	suite.Equal(unit.Cents(300), entry.RecoupmentOwed)
+	suite.Equal(unit.Cents(300), ledger.TotalRecoupmentOwed)
+
+	// Other service members can't see the ledger
+	otherMember := testdatagen.MakeDefaultServiceMember(suite.db)
+	session.UserID = otherMember.UserID
+	session.ServiceMemberID = otherMember.ID
+	_, err = FetchAdvanceLedger(suite.db, session, ppm.MoveID)
+	suite.Equal(ErrFetchForbidden, err)
+}
+
+func (suite *ModelSuite) TestFetchAdvanceDisbursements() {
+	approved := testdatagen.MakeDefaultPPM(suite.db)
+	suite.Nil(approved.Advance.Request())
+	suite.Nil(approved.Advance.Approve())
+	suite.mustSave(approved.Advance)
+
+	// Advances that are only requested aren't exported
+	requested := testdatagen.MakeDefaultPPM(suite.db)
+	suite.Nil(requested.Advance.Request())
+	suite.mustSave(requested.Advance)
+
+	disbursements, err := FetchAdvanceDisbursements(suite.db)
+	suite.Nil(err)
+	suite.Len(disbursements, 1)
+	suite.Equal(approved.Advance.ID, disbursements[0].ReimbursementID)
+	suite.Equal(approved.Move.Locator, disbursements[0].Locator)
+	suite.Equal(unit.Cents(1000), disbursements[0].ApprovedAmount)
+
+	// Once exported they aren't exported again
+	suite.Nil(MarkDisbursementsRequested(suite.db, disbursements, time.Now()))
+	disbursements, err = FetchAdvanceDisbursements(suite.db)
+	suite.Nil(err)
+	suite.Len(disbursements, 0)
+}
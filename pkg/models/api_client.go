@@ -0,0 +1,259 @@
+package models
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// DefaultAPIClientRequestsPerMinute is how many requests a newly registered API client may make a minute
+const DefaultAPIClientRequestsPerMinute = 120
+
+// ErrInvalidCertificate is returned for client certificates that can't be parsed
+var ErrInvalidCertificate = errors.New("invalid client certificate")
+
+// APIClient is TSP software that calls the public API with a client certificate instead of a user
+// logging in. Requests are made as the TSP user who registered the client. A certificate matches a
+// client by its SHA-256 fingerprint, or by its subject when the client was registered by subject.
+type APIClient struct {
+	ID                              uuid.UUID  `json:"id" db:"id"`
+	CreatedAt                       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time  `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID uuid.UUID  `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	TspUserID                       uuid.UUID  `json:"tsp_user_id" db:"tsp_user_id"`
+	TspUser                         TspUser    `belongs_to:"tsp_users"`
+	Name                            string     `json:"name" db:"name"`
+	Sha256Digest                    *string    `json:"sha256_digest" db:"sha256_digest"`
+	Subject                         *string    `json:"subject" db:"subject"`
+	RequestsPerMinute               int        `json:"requests_per_minute" db:"requests_per_minute"`
+	RevokedAt                       *time.Time `json:"revoked_at" db:"revoked_at"`
+}
+
+// APIClients is not required by pop and may be deleted
+type APIClients []APIClient
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *APIClient) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.UUIDIsPresent{Field: c.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.UUIDIsPresent{Field: c.TspUserID, Name: "TspUserID"},
+		&validators.StringIsPresent{Field: c.Name, Name: "Name"},
+		&validators.IntIsGreaterThan{Field: c.RequestsPerMinute, Name: "RequestsPerMinute", Compared: 0},
+	)
+	if (c.Sha256Digest == nil || *c.Sha256Digest == "") && (c.Subject == nil || *c.Subject == "") {
+		verrs.Add("sha256_digest", "A certificate or subject is required.")
+	}
+	return verrs, nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *APIClient) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.NewErrors()
+	// A certificate must identify a single client
+	if c.Sha256Digest != nil {
+		count, err := tx.Where("revoked_at IS NULL AND sha256_digest = $1", *c.Sha256Digest).Count(&APIClients{})
+		if err != nil {
+			return verrs, err
+		}
+		if count > 0 {
+			verrs.Add("sha256_digest", "This certificate is already registered.")
+		}
+	}
+	if c.Subject != nil {
+		count, err := tx.Where("revoked_at IS NULL AND subject = $1", *c.Subject).Count(&APIClients{})
+		if err != nil {
+			return verrs, err
+		}
+		if count > 0 {
+			verrs.Add("subject", "This subject is already registered.")
+		}
+	}
+	return verrs, nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *APIClient) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// IsRevoked returns whether the client can no longer call the API
+func (c *APIClient) IsRevoked() bool {
+	return c.RevokedAt != nil
+}
+
+// CertificateFingerprint returns the hex SHA-256 digest of a certificate, which identifies it
+func CertificateFingerprint(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return hex.EncodeToString(digest[:])
+}
+
+// ParseClientCertificate parses a PEM encoded client certificate
+func ParseClientCertificate(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, ErrInvalidCertificate
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, ErrInvalidCertificate
+	}
+	return cert, nil
+}
+
+// FetchAPIClients returns the clients a TSP registered, including revoked ones
+func FetchAPIClients(db *pop.Connection, tspID uuid.UUID) (APIClients, error) {
+	var clients APIClients
+	err := db.Where("transportation_service_provider_id = $1", tspID).Order("created_at").All(&clients)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching API clients")
+	}
+	return clients, nil
+}
+
+// FetchAllAPIClients returns every registered client
+func FetchAllAPIClients(db *pop.Connection) (APIClients, error) {
+	var clients APIClients
+	if err := db.Order("created_at").All(&clients); err != nil {
+		return nil, errors.Wrap(err, "fetching API clients")
+	}
+	return clients, nil
+}
+
+// FetchAPIClient returns a client if the TSP registered it
+func FetchAPIClient(db *pop.Connection, tspID uuid.UUID, id uuid.UUID) (*APIClient, error) {
+	client, err := FetchAPIClientByID(db, id)
+	if err != nil {
+		return nil, err
+	}
+	if client.TransportationServiceProviderID != tspID {
+		return nil, ErrFetchForbidden
+	}
+	return client, nil
+}
+
+// FetchAPIClientByID returns a client without checking who registered it
+func FetchAPIClientByID(db *pop.Connection, id uuid.UUID) (*APIClient, error) {
+	var client APIClient
+	err := db.Find(&client, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &client, nil
+}
+
+// FetchAPIClientForCertificate returns the unrevoked client a certificate identifies, with the TSP
+// user it acts as. Clients registered by fingerprint take precedence over those registered by subject.
+func FetchAPIClientForCertificate(db *pop.Connection, cert *x509.Certificate) (*APIClient, error) {
+	var clients APIClients
+	err := db.Eager("TspUser").
+		Where("revoked_at IS NULL").
+		Where("(sha256_digest = ? OR subject = ?)", CertificateFingerprint(cert), cert.Subject.String()).
+		Order("sha256_digest IS NULL").
+		Limit(1).
+		All(&clients)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching API client for certificate")
+	}
+	if len(clients) == 0 {
+		return nil, ErrFetchNotFound
+	}
+	return &clients[0], nil
+}
+
+// RevokeAPIClient stops a client from calling the API. Its usage is kept.
+func RevokeAPIClient(db *pop.Connection, client *APIClient, now time.Time) (*validate.Errors, error) {
+	if client.IsRevoked() {
+		return validate.NewErrors(), nil
+	}
+	client.RevokedAt = &now
+	return db.ValidateAndUpdate(client)
+}
+
+// APIClientRequest records a request an API client made, for auditing how the API is used
+type APIClientRequest struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	APIClientID uuid.UUID `json:"api_client_id" db:"api_client_id"`
+	Method      string    `json:"method" db:"method"`
+	Path        string    `json:"path" db:"path"`
+	StatusCode  int       `json:"status_code" db:"status_code"`
+	DurationMs  int       `json:"duration_ms" db:"duration_ms"`
+}
+
+// APIClientRequests is not required by pop and may be deleted
+type APIClientRequests []APIClientRequest
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *APIClientRequest) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: r.APIClientID, Name: "APIClientID"},
+		&validators.StringIsPresent{Field: r.Method, Name: "Method"},
+		&validators.StringIsPresent{Field: r.Path, Name: "Path"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (r *APIClientRequest) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (r *APIClientRequest) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// APIClientUsage summarizes the requests a client made over a period
+type APIClientUsage struct {
+	APIClientID                     uuid.UUID `db:"api_client_id"`
+	Name                            string    `db:"name"`
+	TransportationServiceProviderID uuid.UUID `db:"transportation_service_provider_id"`
+	StandardCarrierAlphaCode        string    `db:"standard_carrier_alpha_code"`
+	Requests                        int       `db:"requests"`
+	Errors                          int       `db:"errors"`
+	Throttled                       int       `db:"throttled"`
+	AverageDurationMs               *float64  `db:"average_duration_ms"`
+}
+
+// FetchAPIClientUsage summarizes the requests each client made from start until end. Clients that
+// made no requests are left out.
+func FetchAPIClientUsage(db *pop.Connection, start time.Time, end time.Time) ([]APIClientUsage, error) {
+	var usage []APIClientUsage
+	err := db.RawQuery(`
+		SELECT
+			api_clients.id AS api_client_id,
+			api_clients.name,
+			api_clients.transportation_service_provider_id,
+			transportation_service_providers.standard_carrier_alpha_code,
+			COUNT(*) AS requests,
+			COUNT(*) FILTER (WHERE api_client_requests.status_code >= 400 AND api_client_requests.status_code <> 429) AS errors,
+			COUNT(*) FILTER (WHERE api_client_requests.status_code = 429) AS throttled,
+			AVG(api_client_requests.duration_ms)::float AS average_duration_ms
+		FROM api_client_requests
+		JOIN api_clients ON api_clients.id = api_client_requests.api_client_id
+		JOIN transportation_service_providers ON transportation_service_providers.id = api_clients.transportation_service_provider_id
+		WHERE api_client_requests.created_at >= $1 AND api_client_requests.created_at < $2
+		GROUP BY api_clients.id, transportation_service_providers.standard_carrier_alpha_code
+		ORDER BY transportation_service_providers.standard_carrier_alpha_code, api_clients.name`, start, end).All(&usage)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching API client usage")
+	}
+	return usage, nil
+}
@@ -0,0 +1,103 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestAPIClientValidation() {
+	client := &APIClient{}
+	expErrors := map[string][]string{
+		"transportation_service_provider_id": {"TransportationServiceProviderID can not be blank."},
+		"tsp_user_id":                        {"TspUserID can not be blank."},
+		"name":                               {"Name can not be blank."},
+		"requests_per_minute":                {"0 is not greater than 0."},
+		"sha256_digest":                      {"A certificate or subject is required."},
+	}
+	suite.verifyValidationErrors(client, expErrors)
+}
+
+func (suite *ModelSuite) TestFetchAPIClientForCertificate() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.db)
+	cert, _ := testdatagen.MakeClientCertificate("dispatch.example.com")
+	subject := cert.Subject.String()
+	bySubject := APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            "Any dispatch certificate",
+		Subject:                         &subject,
+		RequestsPerMinute:               DefaultAPIClientRequestsPerMinute,
+	}
+	suite.mustSave(&bySubject)
+
+	client, err := FetchAPIClientForCertificate(suite.db, cert)
+	suite.Nil(err)
+	suite.Equal(bySubject.ID, client.ID)
+	suite.Equal(tspUser.ID, client.TspUser.ID)
+
+	// Fingerprints are more specific than subjects
+	digest := CertificateFingerprint(cert)
+	byDigest := APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            "This dispatch certificate",
+		Sha256Digest:                    &digest,
+		RequestsPerMinute:               DefaultAPIClientRequestsPerMinute,
+	}
+	suite.mustSave(&byDigest)
+	client, err = FetchAPIClientForCertificate(suite.db, cert)
+	suite.Nil(err)
+	suite.Equal(byDigest.ID, client.ID)
+
+	now := time.Now()
+	for _, c := range []*APIClient{&bySubject, &byDigest} {
+		_, err = RevokeAPIClient(suite.db, c, now)
+		suite.Nil(err)
+	}
+	_, err = FetchAPIClientForCertificate(suite.db, cert)
+	suite.Equal(ErrFetchNotFound, err)
+
+	// Revoked certificates can be registered again
+	reregistered := byDigest
+	reregistered.ID = uuid.Nil
+	reregistered.RevokedAt = nil
+	verrs, err := suite.db.ValidateAndCreate(&reregistered)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+}
+
+func (suite *ModelSuite) TestFetchAPIClientUsage() {
+	tspUser := testdatagen.MakeDefaultTspUser(suite.db)
+	subject := "CN=dispatch.example.com"
+	client := APIClient{
+		TransportationServiceProviderID: tspUser.TransportationServiceProviderID,
+		TspUserID:                       tspUser.ID,
+		Name:                            "Dispatch system",
+		Subject:                         &subject,
+		RequestsPerMinute:               DefaultAPIClientRequestsPerMinute,
+	}
+	suite.mustSave(&client)
+	for _, statusCode := range []int{200, 200, 404, 429} {
+		suite.mustSave(&APIClientRequest{
+			APIClientID: client.ID,
+			Method:      "GET",
+			Path:        "/api/v1/shipments",
+			StatusCode:  statusCode,
+			DurationMs:  20,
+		})
+	}
+
+	now := time.Now()
+	usage, err := FetchAPIClientUsage(suite.db, now.Add(-time.Hour), now.Add(time.Hour))
+	suite.Nil(err)
+	suite.Len(usage, 1)
+	suite.Equal(client.ID, usage[0].APIClientID)
+	suite.Equal(4, usage[0].Requests)
+	suite.Equal(1, usage[0].Errors)
+	suite.Equal(1, usage[0].Throttled)
+	suite.Equal(20.0, *usage[0].AverageDurationMs)
+}
@@ -0,0 +1,457 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// AuditSessionType is the kind of session that made an audited change
+type AuditSessionType string
+
+const (
+	// AuditSessionTypeMy is a change made by a service member on my.move.mil
+	AuditSessionTypeMy AuditSessionType = "MY"
+	// AuditSessionTypeOffice is a change made by a transportation office user
+	AuditSessionTypeOffice AuditSessionType = "OFFICE"
+	// AuditSessionTypeTsp is a change made by a TSP user
+	AuditSessionTypeTsp AuditSessionType = "TSP"
+	// AuditSessionTypeSystem is a change made without a user session, e.g. by the award queue
+	AuditSessionTypeSystem AuditSessionType = "SYSTEM"
+)
+
+// AuditChange is the before and after value of a single changed field
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditRecord is an append-only record of a change to a model. The audit_records table rejects
+// updates and deletes.
+type AuditRecord struct {
+	ID          uuid.UUID        `json:"id" db:"id"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+	MoveID      *uuid.UUID       `json:"move_id" db:"move_id"`
+	RecordType  string           `json:"record_type" db:"record_type"`
+	RecordID    uuid.UUID        `json:"record_id" db:"record_id"`
+	Event       string           `json:"event" db:"event"`
+	FromStatus  *string          `json:"from_status" db:"from_status"`
+	ToStatus    *string          `json:"to_status" db:"to_status"`
+	Changes     string           `json:"changes" db:"changes"`
+	Reason      *string          `json:"reason" db:"reason"`
+	ActorUserID *uuid.UUID       `json:"actor_user_id" db:"actor_user_id"`
+	ActorName   string           `json:"actor_name" db:"actor_name"`
+	SessionType AuditSessionType `json:"session_type" db:"session_type"`
+}
+
+// AuditRecords is not required by pop and may be deleted
+type AuditRecords []AuditRecord
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (a *AuditRecord) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: a.RecordType, Name: "RecordType"},
+		&validators.UUIDIsPresent{Field: a.RecordID, Name: "RecordID"},
+		&validators.StringIsPresent{Field: a.Event, Name: "Event"},
+		&validators.StringIsPresent{Field: string(a.SessionType), Name: "SessionType"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (a *AuditRecord) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (a *AuditRecord) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ChangeSet decodes the changed fields of the record
+func (a *AuditRecord) ChangeSet() (map[string]AuditChange, error) {
+	changes := map[string]AuditChange{}
+	if a.Changes == "" {
+		return changes, nil
+	}
+	err := json.Unmarshal([]byte(a.Changes), &changes)
+	return changes, err
+}
+
+// auditable is a model whose changes are recorded in the audit log
+type auditable interface {
+	auditRecordType() string
+	auditRecordID() uuid.UUID
+	auditStatus() string
+	auditMoveID(db *pop.Connection) (*uuid.UUID, error)
+}
+
+func (m *Move) auditRecordType() string  { return "move" }
+func (m *Move) auditRecordID() uuid.UUID { return m.ID }
+func (m *Move) auditStatus() string      { return string(m.Status) }
+func (m *Move) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return &m.ID, nil
+}
+
+func (p *PersonallyProcuredMove) auditRecordType() string  { return "personally_procured_move" }
+func (p *PersonallyProcuredMove) auditRecordID() uuid.UUID { return p.ID }
+func (p *PersonallyProcuredMove) auditStatus() string      { return string(p.Status) }
+func (p *PersonallyProcuredMove) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return &p.MoveID, nil
+}
+
+func (s *Shipment) auditRecordType() string  { return "shipment" }
+func (s *Shipment) auditRecordID() uuid.UUID { return s.ID }
+func (s *Shipment) auditStatus() string      { return string(s.Status) }
+func (s *Shipment) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return &s.MoveID, nil
+}
+
+func (m *MoveDocument) auditRecordType() string  { return "move_document" }
+func (m *MoveDocument) auditRecordID() uuid.UUID { return m.ID }
+func (m *MoveDocument) auditStatus() string      { return string(m.Status) }
+func (m *MoveDocument) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return &m.MoveID, nil
+}
+
+func (o *Order) auditRecordType() string  { return "order" }
+func (o *Order) auditRecordID() uuid.UUID { return o.ID }
+func (o *Order) auditStatus() string      { return string(o.Status) }
+
+// Orders are tied to a move through the moves made under them, if there are any
+func (o *Order) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	var moves Moves
+	if err := db.Where("orders_id = $1", o.ID).Order("created_at asc").All(&moves); err != nil {
+		return nil, err
+	}
+	if len(moves) == 0 {
+		return nil, nil
+	}
+	return &moves[0].ID, nil
+}
+
+func (l *ShipmentLineItem) auditRecordType() string  { return "shipment_line_item" }
+func (l *ShipmentLineItem) auditRecordID() uuid.UUID { return l.ID }
+func (l *ShipmentLineItem) auditStatus() string      { return string(l.Status) }
+func (l *ShipmentLineItem) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return shipmentAuditMoveID(db, l.ShipmentID)
+}
+
+func (c *Claim) auditRecordType() string  { return "claim" }
+func (c *Claim) auditRecordID() uuid.UUID { return c.ID }
+func (c *Claim) auditStatus() string      { return string(c.Status) }
+func (c *Claim) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return shipmentAuditMoveID(db, c.ShipmentID)
+}
+
+func (r *Reimbursement) auditRecordType() string  { return "reimbursement" }
+func (r *Reimbursement) auditRecordID() uuid.UUID { return r.ID }
+func (r *Reimbursement) auditStatus() string      { return string(r.Status) }
+
+// Reimbursements are tied to a move through the PPM they advance, if there is one
+func (r *Reimbursement) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	var ppms PersonallyProcuredMoves
+	if err := db.Where("advance_id = $1", r.ID).All(&ppms); err != nil {
+		return nil, err
+	}
+	if len(ppms) == 0 {
+		return nil, nil
+	}
+	return &ppms[0].MoveID, nil
+}
+
+func (o *OfficeUser) auditRecordType() string  { return "office_user" }
+func (o *OfficeUser) auditRecordID() uuid.UUID { return o.ID }
+func (o *OfficeUser) auditStatus() string      { return userAuditStatus(o.Deactivated) }
+func (o *OfficeUser) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return nil, nil
+}
+
+func (t *TspUser) auditRecordType() string  { return "tsp_user" }
+func (t *TspUser) auditRecordID() uuid.UUID { return t.ID }
+func (t *TspUser) auditStatus() string      { return userAuditStatus(t.Deactivated) }
+func (t *TspUser) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	return nil, nil
+}
+
+func (c *WorkClaim) auditRecordType() string  { return "work_claim" }
+func (c *WorkClaim) auditRecordID() uuid.UUID { return c.ID }
+func (c *WorkClaim) auditStatus() string {
+	switch {
+	case c.OfficeUserID == uuid.Nil:
+		return "RELEASED"
+	case c.IsAssignment():
+		return "ASSIGNED"
+	}
+	return "CLAIMED"
+}
+
+// Work claims are tied to a move through the move or PPM they claim
+func (c *WorkClaim) auditMoveID(db *pop.Connection) (*uuid.UUID, error) {
+	if c.RecordType == WorkClaimRecordTypeMove {
+		return &c.RecordID, nil
+	}
+	var ppm PersonallyProcuredMove
+	if err := db.Find(&ppm, c.RecordID); err != nil {
+		return nil, err
+	}
+	return &ppm.MoveID, nil
+}
+
+func shipmentAuditMoveID(db *pop.Connection, shipmentID uuid.UUID) (*uuid.UUID, error) {
+	var shipment Shipment
+	if err := db.Find(&shipment, shipmentID); err != nil {
+		return nil, err
+	}
+	return &shipment.MoveID, nil
+}
+
+func userAuditStatus(deactivated bool) string {
+	if deactivated {
+		return "DEACTIVATED"
+	}
+	return "ACTIVE"
+}
+
+// auditSkippedFields are columns that change on every save and aren't worth recording
+var auditSkippedFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// auditChanges returns the db columns whose values differ between before and after. A nil before
+// records every column as new.
+func auditChanges(before, after auditable) map[string]AuditChange {
+	changes := map[string]AuditChange{}
+	afterValue := reflect.Indirect(reflect.ValueOf(after))
+	var beforeValue reflect.Value
+	if before != nil && !reflect.ValueOf(before).IsNil() {
+		beforeValue = reflect.Indirect(reflect.ValueOf(before))
+	}
+
+	t := afterValue.Type()
+	for i := 0; i < t.NumField(); i++ {
+		column := strings.Split(t.Field(i).Tag.Get("db"), ",")[0]
+		if column == "" || column == "-" || auditSkippedFields[column] {
+			continue
+		}
+		afterField := auditFieldValue(afterValue.Field(i))
+		var beforeField interface{}
+		if beforeValue.IsValid() {
+			beforeField = auditFieldValue(beforeValue.Field(i))
+		}
+		if !reflect.DeepEqual(beforeField, afterField) {
+			changes[column] = AuditChange{Before: beforeField, After: afterField}
+		}
+	}
+	return changes
+}
+
+// auditFieldValue dereferences pointer fields so that nil and set values compare cleanly
+func auditFieldValue(v reflect.Value) interface{} {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	return v.Interface()
+}
+
+// auditActor returns the user ID, display name and session type of the session making a change
+func auditActor(db *pop.Connection, session *auth.Session) (*uuid.UUID, string, AuditSessionType) {
+	if session == nil || session.UserID == uuid.Nil {
+		return nil, "System", AuditSessionTypeSystem
+	}
+
+	userID := session.UserID
+	name := ""
+	sessionType := AuditSessionTypeSystem
+	switch {
+	case session.IsMyApp():
+		sessionType = AuditSessionTypeMy
+		if serviceMember, err := FetchServiceMember(db, session.ServiceMemberID); err == nil {
+			name = auditActorName(serviceMember.LastName, serviceMember.FirstName)
+		}
+	case session.IsOfficeApp():
+		sessionType = AuditSessionTypeOffice
+		if officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID); err == nil {
+			name = auditActorName(&officeUser.LastName, &officeUser.FirstName)
+		}
+	case session.IsTspApp():
+		sessionType = AuditSessionTypeTsp
+		if tspUser, err := FetchTspUserByID(db, session.TspUserID); err == nil {
+			name = auditActorName(&tspUser.LastName, &tspUser.FirstName)
+		}
+	}
+
+	if name == "" {
+		name = auditActorName(&session.LastName, &session.FirstName)
+	}
+	if name == "" {
+		name = session.Email
+	}
+	return &userID, name, sessionType
+}
+
+// auditActorName formats a name as "Last, First", skipping missing parts
+func auditActorName(lastName *string, firstName *string) string {
+	names := []string{}
+	if lastName != nil && *lastName != "" {
+		names = append(names, *lastName)
+	}
+	if firstName != nil && *firstName != "" {
+		names = append(names, *firstName)
+	}
+	return strings.Join(names, ", ")
+}
+
+// RecordAudit appends an audit record describing the change from before to after. before may be nil
+// for newly created records; session may be nil for changes made by the system. reason is optional.
+func RecordAudit(db *pop.Connection, session *auth.Session, before, after auditable, event string, reason string) (*validate.Errors, error) {
+	moveID, err := after.auditMoveID(db)
+	if err != nil {
+		return validate.NewErrors(), errors.Wrap(err, "finding move for audit record")
+	}
+
+	changes, err := json.Marshal(auditChanges(before, after))
+	if err != nil {
+		return validate.NewErrors(), errors.Wrap(err, "encoding audit changes")
+	}
+
+	actorUserID, actorName, sessionType := auditActor(db, session)
+	record := AuditRecord{
+		MoveID:      moveID,
+		RecordType:  after.auditRecordType(),
+		RecordID:    after.auditRecordID(),
+		Event:       event,
+		Changes:     string(changes),
+		ActorUserID: actorUserID,
+		ActorName:   actorName,
+		SessionType: sessionType,
+	}
+	if before != nil && !reflect.ValueOf(before).IsNil() {
+		fromStatus := before.auditStatus()
+		record.FromStatus = &fromStatus
+	}
+	toStatus := after.auditStatus()
+	record.ToStatus = &toStatus
+	if reason != "" {
+		record.Reason = &reason
+	}
+
+	return db.ValidateAndCreate(&record)
+}
+
+// AuditEvent is a change to record in the audit log. Save functions that accept audit events record them in
+// the same transaction as the change they describe.
+type AuditEvent struct {
+	Session *auth.Session
+	Before  auditable
+	After   auditable
+	Event   string
+	Reason  string
+}
+
+// NewAuditEvent returns an AuditEvent for the change from before to after made in the session
+func NewAuditEvent(session *auth.Session, before, after auditable, event string, reason string) AuditEvent {
+	return AuditEvent{Session: session, Before: before, After: after, Event: event, Reason: reason}
+}
+
+// recordAuditEvents records each of the events. It is meant to be called inside the transaction that saves them.
+func recordAuditEvents(db *pop.Connection, events []AuditEvent) (*validate.Errors, error) {
+	for _, e := range events {
+		if verrs, err := RecordAudit(db, e.Session, e.Before, e.After, e.Event, e.Reason); verrs.HasAny() || err != nil {
+			return verrs, err
+		}
+	}
+	return validate.NewErrors(), nil
+}
+
+// SaveWithAudit updates after and records the change from before in a single transaction
+func SaveWithAudit(db *pop.Connection, session *auth.Session, before, after auditable, event string, reason string) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		if verrs, err := db.ValidateAndUpdate(after); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = err
+			return transactionError
+		}
+
+		if verrs, err := RecordAudit(db, session, before, after, event, reason); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// FetchAuditRecordsForMove returns the timeline of changes to a move and its records, oldest first
+func FetchAuditRecordsForMove(db *pop.Connection, session *auth.Session, moveID uuid.UUID) (AuditRecords, error) {
+	if err := AuthorizeMoveAccess(db, session, moveID); err != nil {
+		return nil, err
+	}
+
+	var records AuditRecords
+	err := db.Where("move_id = $1", moveID).Order("created_at asc").All(&records)
+	return records, err
+}
+
+// AuditRecordFilter narrows down the audit records returned by FetchAuditRecords. Empty fields don't filter.
+type AuditRecordFilter struct {
+	MoveID      *uuid.UUID
+	RecordType  string
+	RecordID    *uuid.UUID
+	ActorUserID *uuid.UUID
+	Since       *time.Time
+	Until       *time.Time
+}
+
+// FetchAuditRecords returns audit records matching filter, oldest first. It performs no authorization
+// and is meant for exporting records for investigations.
+func FetchAuditRecords(db *pop.Connection, filter AuditRecordFilter) (AuditRecords, error) {
+	query := db.Q()
+	if filter.MoveID != nil {
+		query = query.Where("move_id = ?", *filter.MoveID)
+	}
+	if filter.RecordType != "" {
+		query = query.Where("record_type = ?", filter.RecordType)
+	}
+	if filter.RecordID != nil {
+		query = query.Where("record_id = ?", *filter.RecordID)
+	}
+	if filter.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *filter.ActorUserID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+
+	var records AuditRecords
+	err := query.Order("created_at asc").All(&records)
+	return records, err
+}
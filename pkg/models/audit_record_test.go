@@ -0,0 +1,155 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestSaveWithAuditRecordsTransition() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	session := officeSession(officeUser)
+
+	suite.Nil(move.Submit())
+	suite.mustSave(&move)
+
+	before := move
+	suite.Nil(move.Cancel("Orders were rescinded"))
+	verrs, err := SaveWithAudit(suite.db, session, &before, &move, "cancel", "Orders were rescinded")
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	records, err := FetchAuditRecordsForMove(suite.db, session, move.ID)
+	suite.Nil(err)
+	suite.Len(records, 1)
+
+	record := records[0]
+	suite.Equal("move", record.RecordType)
+	suite.Equal(move.ID, record.RecordID)
+	suite.Equal(move.ID, *record.MoveID)
+	suite.Equal("cancel", record.Event)
+	suite.Equal(string(MoveStatusSUBMITTED), *record.FromStatus)
+	suite.Equal(string(MoveStatusCANCELED), *record.ToStatus)
+	suite.Equal("Orders were rescinded", *record.Reason)
+	suite.Equal(*officeUser.UserID, *record.ActorUserID)
+	suite.Equal(officeUser.LastName+", "+officeUser.FirstName, record.ActorName)
+	suite.Equal(AuditSessionTypeOffice, record.SessionType)
+
+	changes, err := record.ChangeSet()
+	suite.Nil(err)
+	suite.Equal(string(MoveStatusSUBMITTED), changes["status"].Before)
+	suite.Equal(string(MoveStatusCANCELED), changes["status"].After)
+	suite.Equal("Orders were rescinded", changes["cancel_reason"].After)
+	suite.NotContains(changes, "locator")
+	suite.NotContains(changes, "updated_at")
+}
+
+func (suite *ModelSuite) TestRecordAuditWithoutSession() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+
+	verrs, err := RecordAudit(suite.db, nil, nil, &shipment, "create", "")
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{RecordType: "shipment", RecordID: &shipment.ID})
+	suite.Nil(err)
+	suite.Len(records, 1)
+	suite.Equal(AuditSessionTypeSystem, records[0].SessionType)
+	suite.Nil(records[0].ActorUserID)
+	suite.Nil(records[0].FromStatus)
+	suite.Nil(records[0].Reason)
+}
+
+func (suite *ModelSuite) TestAuditRecordsAreAppendOnly() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	verrs, err := RecordAudit(suite.db, nil, nil, &move, "create", "")
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{MoveID: &move.ID})
+	suite.Nil(err)
+	suite.Len(records, 1)
+
+	record := records[0]
+	record.ActorName = "Someone Else"
+	suite.NotNil(suite.db.Update(&record))
+	suite.NotNil(suite.db.Destroy(&record))
+}
+
+func (suite *ModelSuite) TestFetchAuditRecordsForMoveIsScoped() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	otherOffice := testdatagen.MakeTransportationOffice(suite.db, testdatagen.Assertions{
+		TransportationOffice: TransportationOffice{Gbloc: "AGFM"},
+	})
+	outOfGbloc := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		OfficeUser: OfficeUser{
+			TransportationOffice:   otherOffice,
+			TransportationOfficeID: otherOffice.ID,
+		},
+	})
+
+	_, err := FetchAuditRecordsForMove(suite.db, officeSession(outOfGbloc), move.ID)
+	suite.Equal(ErrFetchForbidden, err)
+}
+
+func (suite *ModelSuite) TestSaveMoveDependenciesAuditsCascadedTransitions() {
+	orders := testdatagen.MakeDefaultOrder(suite.db)
+	orders.Status = OrderStatusSUBMITTED // NEVER do this outside of a test.
+	suite.mustSave(&orders)
+
+	selectedMoveType := SelectedMoveTypePPM
+	move, verrs, err := orders.CreateNewMove(suite.db, &selectedMoveType)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	move.Orders = orders
+	ppm, verrs, err := move.CreatePPM(suite.db, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, false, nil)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	move.PersonallyProcuredMoves = append(move.PersonallyProcuredMoves, *ppm)
+	suite.Nil(move.Submit())
+	suite.mustSave(move)
+
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	session := officeSession(officeUser)
+	before := move.AuditSnapshot()
+	suite.Nil(move.Cancel("Orders were rescinded"))
+	verrs, err = SaveMoveDependencies(suite.db, move, move.TransitionAuditEvents(session, before, "cancel", "Orders were rescinded")...)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{MoveID: &move.ID})
+	suite.Nil(err)
+	recordTypes := map[string]string{}
+	for _, record := range records {
+		suite.Equal("cancel", record.Event)
+		recordTypes[record.RecordType] = *record.ToStatus
+	}
+	suite.Equal(map[string]string{
+		"move":                     string(MoveStatusCANCELED),
+		"personally_procured_move": string(PPMStatusCANCELED),
+		"order":                    string(OrderStatusCANCELED),
+	}, recordTypes)
+}
+
+func (suite *ModelSuite) TestSaveOrderRecordsAudit() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	order := move.Orders
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	session := officeSession(officeUser)
+
+	before := order
+	order.HasDependents = !order.HasDependents
+	verrs, err := SaveOrder(suite.db, &order, NewAuditEvent(session, &before, &order, "update", ""))
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{RecordType: "order", RecordID: &order.ID})
+	suite.Nil(err)
+	suite.Len(records, 1)
+	suite.Equal(move.ID, *records[0].MoveID)
+	suite.Equal("update", records[0].Event)
+
+	changes, err := records[0].ChangeSet()
+	suite.Nil(err)
+	suite.Equal(order.HasDependents, changes["has_dependents"].After)
+}
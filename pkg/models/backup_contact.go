@@ -74,8 +74,8 @@ func FetchBackupContact(db *pop.Connection, session *auth.Session, id uuid.UUID)
 		return BackupContact{}, err
 	}
 	// TODO: Handle case where more than one user is authorized to modify contact
-	if session.IsMyApp() && contact.ServiceMember.ID != session.ServiceMemberID {
-		return BackupContact{}, ErrFetchForbidden
+	if authErr := AuthorizeServiceMemberAccess(db, session, contact.ServiceMemberID); authErr != nil {
+		return BackupContact{}, authErr
 	}
 	return contact, nil
 }
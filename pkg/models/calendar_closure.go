@@ -0,0 +1,162 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/rickar/cal"
+
+	"github.com/transcom/mymove/pkg/dates"
+)
+
+// calendarCacheTTL is how long a calendar is used before its closures are reloaded
+const calendarCacheTTL = 10 * time.Minute
+
+// CalendarClosure is a day on which a duty station, transportation office or TSP doesn't work, on top
+// of the federal holidays. Exactly one of the three is set.
+type CalendarClosure struct {
+	ID                              uuid.UUID  `json:"id" db:"id"`
+	CreatedAt                       time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time  `json:"updated_at" db:"updated_at"`
+	DutyStationID                   *uuid.UUID `json:"duty_station_id" db:"duty_station_id"`
+	TransportationOfficeID          *uuid.UUID `json:"transportation_office_id" db:"transportation_office_id"`
+	TransportationServiceProviderID *uuid.UUID `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	ClosureDate                     time.Time  `json:"closure_date" db:"closure_date"`
+	Reason                          string     `json:"reason" db:"reason"`
+}
+
+// CalendarClosures is not required by pop and may be deleted
+type CalendarClosures []CalendarClosure
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *CalendarClosure) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.TimeIsPresent{Field: c.ClosureDate, Name: "ClosureDate"},
+		&validators.StringIsPresent{Field: c.Reason, Name: "Reason"},
+	)
+
+	scopes := 0
+	for _, id := range []*uuid.UUID{c.DutyStationID, c.TransportationOfficeID, c.TransportationServiceProviderID} {
+		if id != nil {
+			scopes++
+		}
+	}
+	if scopes != 1 {
+		verrs.Add("scope", "Exactly one of DutyStationID, TransportationOfficeID and TransportationServiceProviderID must be set.")
+	}
+	return verrs, nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *CalendarClosure) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *CalendarClosure) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchCalendarClosures returns the closures of the duty station, transportation office or TSP of the
+// scope, earliest first. The zero scope returns every closure.
+func FetchCalendarClosures(db *pop.Connection, scope dates.CalendarScope) (CalendarClosures, error) {
+	var closures CalendarClosures
+	query := db.Q()
+	if scope != (dates.CalendarScope{}) {
+		query = db.Where("duty_station_id = $1 OR transportation_office_id = $2 OR transportation_service_provider_id = $3",
+			scope.DutyStationID, scope.TransportationOfficeID, scope.TSPID)
+	}
+	if err := query.Order("closure_date").All(&closures); err != nil {
+		return nil, errors.Wrap(err, "fetching calendar closures")
+	}
+	return closures, nil
+}
+
+// FetchCalendarClosure returns a calendar closure by id
+func FetchCalendarClosure(db *pop.Connection, id uuid.UUID) (*CalendarClosure, error) {
+	var closure CalendarClosure
+	err := db.Find(&closure, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &closure, nil
+}
+
+// NewCalendarCache returns a cache of calendars whose closures are loaded from db
+func NewCalendarCache(db *pop.Connection) *dates.CalendarCache {
+	return dates.NewCalendarCache(func(scope dates.CalendarScope) ([]time.Time, error) {
+		closures, err := FetchCalendarClosures(db, scope)
+		if err != nil {
+			return nil, err
+		}
+		closureDates := make([]time.Time, len(closures))
+		for i, closure := range closures {
+			closureDates[i] = closure.ClosureDate
+		}
+		return closureDates, nil
+	}, calendarCacheTTL)
+}
+
+// FetchCalendar returns the calendar of federal holidays and the scope's closures. It reads the closures
+// from db instead of a cache, for callers such as model hooks that only have a connection.
+func FetchCalendar(db *pop.Connection, scope dates.CalendarScope) (*cal.Calendar, error) {
+	if scope == (dates.CalendarScope{}) {
+		return dates.NewUSCalendar(), nil
+	}
+	closures, err := FetchCalendarClosures(db, scope)
+	if err != nil {
+		return nil, err
+	}
+	closureDates := make([]time.Time, len(closures))
+	for i, closure := range closures {
+		closureDates[i] = closure.ClosureDate
+	}
+	return dates.NewCalendar(closureDates), nil
+}
+
+// FetchServiceMemberCalendarScope returns the scope of the service member's current duty station, whose
+// calendar their shipments' move dates are counted on. Service members without a duty station get the
+// federal calendar.
+func FetchServiceMemberCalendarScope(db *pop.Connection, serviceMemberID uuid.UUID) (dates.CalendarScope, error) {
+	var serviceMember ServiceMember
+	if err := db.Find(&serviceMember, serviceMemberID); err != nil {
+		return dates.CalendarScope{}, errors.Wrap(err, "fetching service member for calendar")
+	}
+	if serviceMember.DutyStationID == nil {
+		return dates.CalendarScope{}, nil
+	}
+	var station DutyStation
+	if err := db.Find(&station, *serviceMember.DutyStationID); err != nil {
+		return dates.CalendarScope{}, errors.Wrap(err, "fetching duty station for calendar")
+	}
+	return DutyStationCalendarScope(station), nil
+}
+
+// DutyStationCalendarScope returns the scope of a duty station and the transportation office serving it
+func DutyStationCalendarScope(station DutyStation) dates.CalendarScope {
+	scope := dates.CalendarScope{DutyStationID: station.ID}
+	if station.TransportationOfficeID != nil {
+		scope.TransportationOfficeID = *station.TransportationOfficeID
+	}
+	return scope
+}
+
+// TransportationOfficeCalendarScope returns the scope of a transportation office. A nil office is the
+// federal calendar.
+func TransportationOfficeCalendarScope(officeID *uuid.UUID) dates.CalendarScope {
+	scope := dates.CalendarScope{}
+	if officeID != nil {
+		scope.TransportationOfficeID = *officeID
+	}
+	return scope
+}
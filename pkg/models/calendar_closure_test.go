@@ -0,0 +1,49 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/transcom/mymove/pkg/dates"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestCalendarClosureValidations() {
+	closure := &CalendarClosure{}
+
+	expErrors := map[string][]string{
+		"closure_date": {"ClosureDate can not be blank."},
+		"reason":       {"Reason can not be blank."},
+		"scope":        {"Exactly one of DutyStationID, TransportationOfficeID and TransportationServiceProviderID must be set."},
+	}
+
+	suite.verifyValidationErrors(closure, expErrors)
+}
+
+func (suite *ModelSuite) TestFetchCalendarClosures() {
+	station := testdatagen.FetchOrMakeDefaultDutyStation(suite.db)
+	office := testdatagen.MakeDefaultTransportationOffice(suite.db)
+	station.TransportationOfficeID = &office.ID
+	suite.mustSave(&station)
+
+	stationClosure := time.Date(2018, 12, 24, 0, 0, 0, 0, time.UTC)
+	officeClosure := time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)
+	suite.mustSave(&CalendarClosure{DutyStationID: &station.ID, ClosureDate: stationClosure, Reason: "Holiday block leave"})
+	suite.mustSave(&CalendarClosure{TransportationOfficeID: &office.ID, ClosureDate: officeClosure, Reason: "Office closed"})
+
+	closures, err := FetchCalendarClosures(suite.db, DutyStationCalendarScope(station))
+	suite.Nil(err)
+	suite.Len(closures, 2)
+	suite.True(stationClosure.Equal(closures[0].ClosureDate))
+	suite.True(officeClosure.Equal(closures[1].ClosureDate))
+
+	// The office's other stations only get its closures
+	closures, err = FetchCalendarClosures(suite.db, dates.CalendarScope{TransportationOfficeID: office.ID})
+	suite.Nil(err)
+	suite.Len(closures, 1)
+
+	calendar, err := NewCalendarCache(suite.db).Calendar(DutyStationCalendarScope(station))
+	suite.Nil(err)
+	suite.False(calendar.IsWorkday(stationClosure))
+	suite.False(calendar.IsWorkday(officeClosure))
+}
@@ -0,0 +1,367 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// ClaimStatus is the status of a loss and damage claim
+type ClaimStatus string
+
+const (
+	// ClaimStatusFILED captures enum value "FILED"
+	ClaimStatusFILED ClaimStatus = "FILED"
+	// ClaimStatusTSPREVIEWING captures enum value "TSP_REVIEWING"
+	ClaimStatusTSPREVIEWING ClaimStatus = "TSP_REVIEWING"
+	// ClaimStatusSETTLED captures enum value "SETTLED"
+	ClaimStatusSETTLED ClaimStatus = "SETTLED"
+	// ClaimStatusDENIED captures enum value "DENIED"
+	ClaimStatusDENIED ClaimStatus = "DENIED"
+	// ClaimStatusTRANSFERRED captures enum value "TRANSFERRED", when the claim was transferred to the
+	// military claims office
+	ClaimStatusTRANSFERRED ClaimStatus = "TRANSFERRED"
+)
+
+// ClaimItemCondition is what happened to a claimed item
+type ClaimItemCondition string
+
+const (
+	// ClaimItemConditionLOST captures enum value "LOST"
+	ClaimItemConditionLOST ClaimItemCondition = "LOST"
+	// ClaimItemConditionDAMAGED captures enum value "DAMAGED"
+	ClaimItemConditionDAMAGED ClaimItemCondition = "DAMAGED"
+)
+
+// Service members have nine months from delivery to file a claim with the TSP for full replacement
+// value, and the TSP has 60 days from filing to settle or deny it.
+const (
+	claimFilingPeriodMonths = 9
+	claimTSPResponseDays    = 60
+)
+
+// Claim is a service member's claim against the TSP for items lost or damaged in a shipment
+type Claim struct {
+	ID                    uuid.UUID   `json:"id" db:"id"`
+	CreatedAt             time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time   `json:"updated_at" db:"updated_at"`
+	ShipmentID            uuid.UUID   `json:"shipment_id" db:"shipment_id"`
+	ServiceMemberID       uuid.UUID   `json:"service_member_id" db:"service_member_id"`
+	DocumentID            uuid.UUID   `json:"document_id" db:"document_id"`
+	Document              Document    `belongs_to:"documents"`
+	Status                ClaimStatus `json:"status" db:"status"`
+	Description           string      `json:"description" db:"description"`
+	ClaimDate             time.Time   `json:"claim_date" db:"claim_date"`
+	TSPResponseDueDate    time.Time   `json:"tsp_response_due_date" db:"tsp_response_due_date"`
+	TSPResponse           *string     `json:"tsp_response" db:"tsp_response"`
+	SettlementOfferAmount *unit.Cents `json:"settlement_offer_amount" db:"settlement_offer_amount"`
+	ResolvedAt            *time.Time  `json:"resolved_at" db:"resolved_at"`
+	Items                 ClaimItems  `has_many:"claim_items" order_by:"created_at asc"`
+}
+
+// Claims is not required by pop and may be deleted
+type Claims []Claim
+
+// ClaimItem is one lost or damaged item in a claim
+type ClaimItem struct {
+	ID            uuid.UUID          `json:"id" db:"id"`
+	CreatedAt     time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" db:"updated_at"`
+	ClaimID       uuid.UUID          `json:"claim_id" db:"claim_id"`
+	Description   string             `json:"description" db:"description"`
+	Condition     ClaimItemCondition `json:"condition" db:"condition"`
+	ClaimedAmount unit.Cents         `json:"claimed_amount" db:"claimed_amount"`
+	OfferedAmount *unit.Cents        `json:"offered_amount" db:"offered_amount"`
+}
+
+// ClaimItems is not required by pop and may be deleted
+type ClaimItems []ClaimItem
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *Claim) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validStatuses := []string{
+		string(ClaimStatusFILED),
+		string(ClaimStatusTSPREVIEWING),
+		string(ClaimStatusSETTLED),
+		string(ClaimStatusDENIED),
+		string(ClaimStatusTRANSFERRED),
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: c.ShipmentID, Name: "ShipmentID"},
+		&validators.UUIDIsPresent{Field: c.ServiceMemberID, Name: "ServiceMemberID"},
+		&validators.UUIDIsPresent{Field: c.DocumentID, Name: "DocumentID"},
+		&validators.StringInclusion{Field: string(c.Status), Name: "Status", List: validStatuses},
+		&validators.StringIsPresent{Field: c.Description, Name: "Description"},
+		&validators.TimeIsPresent{Field: c.ClaimDate, Name: "ClaimDate"},
+		&validators.TimeIsPresent{Field: c.TSPResponseDueDate, Name: "TSPResponseDueDate"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *Claim) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *Claim) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *ClaimItem) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validConditions := []string{
+		string(ClaimItemConditionLOST),
+		string(ClaimItemConditionDAMAGED),
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: i.ClaimID, Name: "ClaimID"},
+		&validators.StringIsPresent{Field: i.Description, Name: "Description"},
+		&validators.StringInclusion{Field: string(i.Condition), Name: "Condition", List: validConditions},
+		&validators.IntIsGreaterThan{Field: i.ClaimedAmount.Int(), Name: "ClaimedAmount", Compared: 0},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (i *ClaimItem) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (i *ClaimItem) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ClaimFilingDeadline returns the last day a claim can be filed against the shipment's TSP, or nil
+// if the shipment hasn't been delivered
+func ClaimFilingDeadline(shipment Shipment) *time.Time {
+	if shipment.ActualDeliveryDate == nil {
+		return nil
+	}
+	deadline := shipment.ActualDeliveryDate.AddDate(0, claimFilingPeriodMonths, 0)
+	return &deadline
+}
+
+// ClaimedAmount is the total amount claimed for the claim's items
+func (c *Claim) ClaimedAmount() unit.Cents {
+	var total unit.Cents
+	for _, item := range c.Items {
+		total = total.AddCents(item.ClaimedAmount)
+	}
+	return total
+}
+
+// State Machine
+// Avoid calling Claim.Status = ... ever. Use these methods to change the state.
+
+// Review marks the claim as being reviewed by the TSP
+func (c *Claim) Review() error {
+	if c.Status != ClaimStatusFILED {
+		return errors.Wrap(ErrInvalidTransition, "Review")
+	}
+
+	c.Status = ClaimStatusTSPREVIEWING
+	return nil
+}
+
+// OfferSettlement records the TSP's response and the settlement it offers, which is the total
+// offered for the claim's items. Every item must have an offered amount.
+func (c *Claim) OfferSettlement(response string) error {
+	if c.Status != ClaimStatusTSPREVIEWING {
+		return errors.Wrap(ErrInvalidTransition, "OfferSettlement")
+	}
+
+	var offer unit.Cents
+	for _, item := range c.Items {
+		if item.OfferedAmount == nil {
+			return errors.Wrap(ErrInvalidTransition, "OfferSettlement without an offer for every item")
+		}
+		offer = offer.AddCents(*item.OfferedAmount)
+	}
+	c.TSPResponse = &response
+	c.SettlementOfferAmount = &offer
+	return nil
+}
+
+// Deny records that the TSP denied the claim and why
+func (c *Claim) Deny(response string, at time.Time) error {
+	if c.Status != ClaimStatusFILED && c.Status != ClaimStatusTSPREVIEWING {
+		return errors.Wrap(ErrInvalidTransition, "Deny")
+	}
+
+	c.Status = ClaimStatusDENIED
+	c.TSPResponse = &response
+	c.ResolvedAt = &at
+	return nil
+}
+
+// AcceptSettlement settles the claim for the amount the TSP offered
+func (c *Claim) AcceptSettlement(at time.Time) error {
+	if c.Status != ClaimStatusTSPREVIEWING || c.SettlementOfferAmount == nil {
+		return errors.Wrap(ErrInvalidTransition, "AcceptSettlement")
+	}
+
+	c.Status = ClaimStatusSETTLED
+	c.ResolvedAt = &at
+	return nil
+}
+
+// Transfer transfers the claim to the military claims office. Service members can transfer claims
+// the TSP denied, settlements offered they don't accept, and claims the TSP didn't respond to in time.
+func (c *Claim) Transfer(at time.Time) error {
+	switch {
+	case c.Status == ClaimStatusDENIED:
+	case c.Status == ClaimStatusTSPREVIEWING && c.SettlementOfferAmount != nil:
+	case (c.Status == ClaimStatusFILED || c.Status == ClaimStatusTSPREVIEWING) && at.After(c.TSPResponseDueDate):
+	default:
+		return errors.Wrap(ErrInvalidTransition, "Transfer")
+	}
+
+	c.Status = ClaimStatusTRANSFERRED
+	c.ResolvedAt = &at
+	return nil
+}
+
+// END State Machine
+
+// FileClaim files a claim with its items against a delivered shipment, creating the document its
+// supporting evidence is uploaded to, and records the filing in the audit log. Claims filed after the
+// filing deadline are invalid.
+func FileClaim(db *pop.Connection, session *auth.Session, shipment Shipment, claim *Claim) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	deadline := ClaimFilingDeadline(shipment)
+	if deadline == nil || (shipment.Status != ShipmentStatusDELIVERED && shipment.Status != ShipmentStatusCOMPLETED) {
+		return responseVErrors, errors.Wrap(ErrInvalidTransition, "FileClaim on a shipment that wasn't delivered")
+	}
+	// Claims can be filed at any time on the day of the deadline
+	if !claim.ClaimDate.Before(deadline.AddDate(0, 0, 1)) {
+		responseVErrors.Add("claim_date", "Claims must be filed within nine months of delivery.")
+		return responseVErrors, nil
+	}
+	if len(claim.Items) == 0 {
+		responseVErrors.Add("items", "Claims must include at least one lost or damaged item.")
+		return responseVErrors, nil
+	}
+
+	claim.ShipmentID = shipment.ID
+	claim.ServiceMemberID = shipment.ServiceMemberID
+	claim.Status = ClaimStatusFILED
+	claim.TSPResponseDueDate = claim.ClaimDate.AddDate(0, 0, claimTSPResponseDays)
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		claim.Document = Document{ServiceMemberID: shipment.ServiceMemberID}
+		if verrs, err := db.ValidateAndCreate(&claim.Document); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Creating Claim Document")
+			return transactionError
+		}
+		claim.DocumentID = claim.Document.ID
+
+		if verrs, err := db.ValidateAndCreate(claim); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Creating Claim")
+			return transactionError
+		}
+
+		for i := range claim.Items {
+			claim.Items[i].ClaimID = claim.ID
+			if verrs, err := db.ValidateAndCreate(&claim.Items[i]); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error Creating Claim Item")
+				return transactionError
+			}
+		}
+
+		if verrs, err := RecordAudit(db, session, nil, claim, "file", ""); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// SaveClaim saves a claim along with its items and audit records for the changes in a single transaction
+func SaveClaim(db *pop.Connection, claim *Claim, audits ...AuditEvent) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		for i := range claim.Items {
+			if verrs, err := db.ValidateAndSave(&claim.Items[i]); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error Saving Claim Item")
+				return transactionError
+			}
+		}
+
+		if verrs, err := db.ValidateAndSave(claim); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Saving Claim")
+			return transactionError
+		}
+
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// FetchClaimsForShipment returns the claims filed against a shipment, newest first. It performs no
+// authorization.
+func FetchClaimsForShipment(db *pop.Connection, shipmentID uuid.UUID) (Claims, error) {
+	var claims Claims
+	err := db.Eager("Items", "Document.Uploads").
+		Where("shipment_id = $1", shipmentID).
+		Order("claim_date desc").
+		All(&claims)
+	return claims, err
+}
+
+// FetchClaim fetches a claim if the user may access its shipment
+func FetchClaim(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Claim, error) {
+	var claim Claim
+	err := db.Eager("Items", "Document.Uploads").Find(&claim, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+
+	var shipment Shipment
+	if err := db.Find(&shipment, claim.ShipmentID); err != nil {
+		return nil, err
+	}
+	if err := AuthorizeShipmentAccess(db, session, &shipment); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
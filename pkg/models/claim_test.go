@@ -0,0 +1,139 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ModelSuite) TestClaimValidations() {
+	claim := &Claim{}
+
+	expErrors := map[string][]string{
+		"shipment_id":           {"ShipmentID can not be blank."},
+		"service_member_id":     {"ServiceMemberID can not be blank."},
+		"document_id":           {"DocumentID can not be blank."},
+		"status":                {"Status is not in the list [FILED, TSP_REVIEWING, SETTLED, DENIED, TRANSFERRED]."},
+		"description":           {"Description can not be blank."},
+		"claim_date":            {"ClaimDate can not be blank."},
+		"tsp_response_due_date": {"TSPResponseDueDate can not be blank."},
+	}
+
+	suite.verifyValidationErrors(claim, expErrors)
+}
+
+func (suite *ModelSuite) TestClaimStateMachine() {
+	filed := time.Date(2018, time.December, 3, 0, 0, 0, 0, time.UTC)
+	claim := Claim{
+		Status:             ClaimStatusFILED,
+		ClaimDate:          filed,
+		TSPResponseDueDate: filed.AddDate(0, 0, 60),
+		Items: ClaimItems{
+			{Description: "Grandfather clock", Condition: ClaimItemConditionDAMAGED, ClaimedAmount: unit.Cents(50000)},
+			{Description: "Box of books", Condition: ClaimItemConditionLOST, ClaimedAmount: unit.Cents(12000)},
+		},
+	}
+
+	// Nothing was offered yet, and the TSP still has time to respond
+	err := claim.AcceptSettlement(filed)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	err = claim.Transfer(filed.AddDate(0, 0, 30))
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+
+	err = claim.Review()
+	suite.Nil(err)
+	suite.Equal(ClaimStatusTSPREVIEWING, claim.Status)
+
+	// Every item needs an offer
+	clockOffer := unit.Cents(30000)
+	booksOffer := unit.Cents(12000)
+	claim.Items[0].OfferedAmount = &clockOffer
+	err = claim.OfferSettlement("The clock was already scratched at pickup")
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+
+	claim.Items[1].OfferedAmount = &booksOffer
+	err = claim.OfferSettlement("The clock was already scratched at pickup")
+	suite.Nil(err)
+	suite.Equal(unit.Cents(42000), *claim.SettlementOfferAmount)
+	suite.Equal(unit.Cents(62000), claim.ClaimedAmount())
+
+	settled := filed.AddDate(0, 0, 20)
+	err = claim.AcceptSettlement(settled)
+	suite.Nil(err)
+	suite.Equal(ClaimStatusSETTLED, claim.Status)
+	suite.Equal(settled, *claim.ResolvedAt)
+
+	err = claim.Deny("Too late", settled)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+}
+
+func (suite *ModelSuite) TestClaimTransfer() {
+	filed := time.Date(2018, time.December, 3, 0, 0, 0, 0, time.UTC)
+	claim := Claim{Status: ClaimStatusFILED, ClaimDate: filed, TSPResponseDueDate: filed.AddDate(0, 0, 60)}
+
+	// The TSP didn't respond in time
+	err := claim.Transfer(filed.AddDate(0, 0, 61))
+	suite.Nil(err)
+	suite.Equal(ClaimStatusTRANSFERRED, claim.Status)
+
+	denied := Claim{Status: ClaimStatusTSPREVIEWING, ClaimDate: filed, TSPResponseDueDate: filed.AddDate(0, 0, 60)}
+	err = denied.Deny("Damage was not noted at delivery", filed.AddDate(0, 0, 10))
+	suite.Nil(err)
+	err = denied.Transfer(filed.AddDate(0, 0, 11))
+	suite.Nil(err)
+	suite.Equal(ClaimStatusTRANSFERRED, denied.Status)
+}
+
+func (suite *ModelSuite) TestFileClaim() {
+	delivered := time.Date(2018, time.March, 15, 0, 0, 0, 0, time.UTC)
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{
+			Status:             ShipmentStatusDELIVERED,
+			ActualDeliveryDate: &delivered,
+		},
+	})
+	suite.Equal(delivered.AddDate(0, 9, 0), *ClaimFilingDeadline(shipment))
+
+	claim := Claim{
+		Description: "Movers dropped the clock",
+		ClaimDate:   time.Date(2018, time.December, 15, 12, 0, 0, 0, time.UTC),
+		Items: ClaimItems{
+			{Description: "Grandfather clock", Condition: ClaimItemConditionDAMAGED, ClaimedAmount: unit.Cents(50000)},
+		},
+	}
+	verrs, err := FileClaim(suite.db, nil, shipment, &claim)
+	suite.Nil(err)
+	suite.False(verrs.HasAny(), verrs.String())
+	suite.Equal(ClaimStatusFILED, claim.Status)
+	suite.Equal(shipment.ServiceMemberID, claim.ServiceMemberID)
+	suite.Equal(time.Date(2019, time.February, 13, 12, 0, 0, 0, time.UTC), claim.TSPResponseDueDate)
+
+	claims, err := FetchClaimsForShipment(suite.db, shipment.ID)
+	suite.Nil(err)
+	suite.Len(claims, 1)
+	suite.Len(claims[0].Items, 1)
+	suite.Equal(claim.DocumentID, claims[0].Document.ID)
+
+	// Past the deadline
+	late := Claim{
+		Description: "Found a broken lamp",
+		ClaimDate:   time.Date(2018, time.December, 16, 0, 0, 0, 0, time.UTC),
+		Items: ClaimItems{
+			{Description: "Lamp", Condition: ClaimItemConditionDAMAGED, ClaimedAmount: unit.Cents(4000)},
+		},
+	}
+	verrs, err = FileClaim(suite.db, nil, shipment, &late)
+	suite.Nil(err)
+	suite.Equal([]string{"Claims must be filed within nine months of delivery."}, verrs.Get("claim_date"))
+
+	// Shipments that weren't delivered can't be claimed against
+	inTransit := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{Status: ShipmentStatusINTRANSIT},
+	})
+	_, err = FileClaim(suite.db, nil, inTransit, &late)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+}
@@ -27,6 +27,12 @@ var ErrInvalidPatchGate = errors.New("INVALID_PATCH_GATE")
 // ErrInvalidTransition is an error representing an invalid state transition.
 var ErrInvalidTransition = errors.New("INVALID_TRANSITION")
 
+// ErrInvalidQueueParams means that a move queue was requested with an unknown queue, sort column or cursor
+var ErrInvalidQueueParams = errors.New("INVALID_QUEUE_PARAMS")
+
+// ErrWorkClaimed means that another office user has claimed the move or PPM being acted on
+var ErrWorkClaimed = errors.New("WORK_CLAIMED")
+
 // recordNotFoundErrorString is the error string returned when no matching rows exist in the database
 // This is ugly, but the best we can do with go's Postgresql adapter
 const recordNotFoundErrorString = "sql: no rows in result set"
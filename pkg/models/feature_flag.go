@@ -0,0 +1,304 @@
+package models
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// FeatureDPS is the feature that gives access to the DPS integration. It is also enabled for everyone in dps_users.
+const FeatureDPS = "dps"
+
+// FeatureFlagTargetType is the kind of thing a feature flag can be enabled for
+type FeatureFlagTargetType string
+
+const (
+	// FeatureFlagTargetTypeUser targets a user by ID or login.gov email
+	FeatureFlagTargetTypeUser FeatureFlagTargetType = "USER"
+	// FeatureFlagTargetTypeRole targets every session acting in an AccessRole
+	FeatureFlagTargetTypeRole FeatureFlagTargetType = "ROLE"
+	// FeatureFlagTargetTypeTransportationOffice targets users of a transportation office by ID
+	FeatureFlagTargetTypeTransportationOffice FeatureFlagTargetType = "TRANSPORTATION_OFFICE"
+	// FeatureFlagTargetTypeGBLOC targets users of transportation offices in a GBLOC
+	FeatureFlagTargetTypeGBLOC FeatureFlagTargetType = "GBLOC"
+)
+
+// FeatureFlag turns a feature on for the users it targets. An enabled flag is on for a session if any
+// of its targets match the session or the session's user falls within its percentage rollout.
+type FeatureFlag struct {
+	ID          uuid.UUID          `json:"id" db:"id"`
+	CreatedAt   time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" db:"updated_at"`
+	Name        string             `json:"name" db:"name"`
+	Description *string            `json:"description" db:"description"`
+	Enabled     bool               `json:"enabled" db:"enabled"`
+	Percentage  int                `json:"percentage" db:"percentage"`
+	Targets     FeatureFlagTargets `has_many:"feature_flag_targets" order_by:"target_type, target_value"`
+}
+
+// FeatureFlags is not required by pop and may be deleted
+type FeatureFlags []FeatureFlag
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (f *FeatureFlag) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: f.Name, Name: "Name"},
+		&validators.IntIsGreaterThan{Field: f.Percentage, Name: "Percentage", Compared: -1},
+		&validators.IntIsLessThan{Field: f.Percentage, Name: "Percentage", Compared: 101},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (f *FeatureFlag) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (f *FeatureFlag) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FeatureFlagTarget enables a feature flag for a user, role, transportation office or GBLOC
+type FeatureFlagTarget struct {
+	ID            uuid.UUID             `json:"id" db:"id"`
+	CreatedAt     time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at" db:"updated_at"`
+	FeatureFlagID uuid.UUID             `json:"feature_flag_id" db:"feature_flag_id"`
+	TargetType    FeatureFlagTargetType `json:"target_type" db:"target_type"`
+	TargetValue   string                `json:"target_value" db:"target_value"`
+}
+
+// FeatureFlagTargets is not required by pop and may be deleted
+type FeatureFlagTargets []FeatureFlagTarget
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (f *FeatureFlagTarget) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: f.FeatureFlagID, Name: "FeatureFlagID"},
+		&validators.StringInclusion{Field: string(f.TargetType), Name: "TargetType", List: []string{
+			string(FeatureFlagTargetTypeUser),
+			string(FeatureFlagTargetTypeRole),
+			string(FeatureFlagTargetTypeTransportationOffice),
+			string(FeatureFlagTargetTypeGBLOC),
+		}},
+		&validators.StringIsPresent{Field: f.TargetValue, Name: "TargetValue"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (f *FeatureFlagTarget) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (f *FeatureFlagTarget) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchFeatureFlags returns every feature flag and its targets, ordered by name
+func FetchFeatureFlags(db *pop.Connection) (FeatureFlags, error) {
+	var flags FeatureFlags
+	err := db.Q().Eager("Targets").Order("name asc").All(&flags)
+	return flags, err
+}
+
+// FetchFeatureFlagByName returns a feature flag and its targets
+func FetchFeatureFlagByName(db *pop.Connection, name string) (*FeatureFlag, error) {
+	var flag FeatureFlag
+	err := db.Q().Eager("Targets").Where("name = $1", name).First(&flag)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &flag, nil
+}
+
+// SaveFeatureFlag saves a feature flag and replaces its targets with flag.Targets
+func SaveFeatureFlag(db *pop.Connection, flag *FeatureFlag) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		if verrs, err := db.ValidateAndSave(flag); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving feature flag")
+			return transactionError
+		}
+
+		if err := db.RawQuery("DELETE FROM feature_flag_targets WHERE feature_flag_id = $1", flag.ID).Exec(); err != nil {
+			responseError = errors.Wrap(err, "Error clearing feature flag targets")
+			return transactionError
+		}
+
+		for i := range flag.Targets {
+			target := &flag.Targets[i]
+			target.ID = uuid.Nil
+			target.FeatureFlagID = flag.ID
+			if verrs, err := db.ValidateAndCreate(target); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error saving feature flag target")
+				return transactionError
+			}
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// DeleteFeatureFlag deletes a feature flag and its targets
+func DeleteFeatureFlag(db *pop.Connection, flag *FeatureFlag) error {
+	return db.Destroy(flag)
+}
+
+// featureSubject is what a session's feature flags are evaluated against
+type featureSubject struct {
+	userID  uuid.UUID
+	email   string
+	role    AccessRole
+	offices map[string]bool
+	gblocs  map[string]bool
+}
+
+func newFeatureSubject(db *pop.Connection, session *auth.Session) (featureSubject, error) {
+	subject := featureSubject{
+		userID:  session.UserID,
+		email:   strings.ToLower(session.Email),
+		offices: map[string]bool{},
+		gblocs:  map[string]bool{},
+	}
+
+	role, err := FetchAccessRole(db, session)
+	if err != nil {
+		return subject, err
+	}
+	subject.role = role
+
+	var offices []TransportationOffice
+	switch role {
+	case AccessRoleOfficeUser, AccessRoleOfficeSupervisor:
+		officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID)
+		if err != nil {
+			return subject, err
+		}
+		offices = append(offices, officeUser.TransportationOffice)
+	case AccessRoleServiceMember:
+		// Service members belong to the office of their current duty station
+		serviceMember, err := FetchServiceMember(db, session.ServiceMemberID)
+		if err != nil {
+			return subject, err
+		}
+		if serviceMember.DutyStationID != nil {
+			office, err := FetchDutyStationTransportationOffice(db, *serviceMember.DutyStationID)
+			if err == nil {
+				offices = append(offices, office)
+			} else if errors.Cause(err) != ErrFetchNotFound {
+				return subject, err
+			}
+		}
+	}
+	for _, office := range offices {
+		subject.offices[office.ID.String()] = true
+		subject.gblocs[office.Gbloc] = true
+	}
+	return subject, nil
+}
+
+// matches reports whether the target applies to the subject
+func (s featureSubject) matches(target FeatureFlagTarget) bool {
+	switch target.TargetType {
+	case FeatureFlagTargetTypeUser:
+		return target.TargetValue == s.userID.String() || strings.ToLower(target.TargetValue) == s.email
+	case FeatureFlagTargetTypeRole:
+		return AccessRole(target.TargetValue) == s.role
+	case FeatureFlagTargetTypeTransportationOffice:
+		return s.offices[target.TargetValue]
+	case FeatureFlagTargetTypeGBLOC:
+		return s.gblocs[target.TargetValue]
+	}
+	return false
+}
+
+// inRollout deterministically places a user in one of 100 buckets per flag, so the same users stay
+// enabled as the percentage grows
+func (s featureSubject) inRollout(flag FeatureFlag) bool {
+	if flag.Percentage <= 0 || s.userID == uuid.Nil {
+		return false
+	}
+	hash := fnv.New32a()
+	hash.Write([]byte(flag.Name + ":" + s.userID.String()))
+	return int(hash.Sum32()%100) < flag.Percentage
+}
+
+// isEnabledFor reports whether the flag is on for the subject
+func (f FeatureFlag) isEnabledFor(subject featureSubject) bool {
+	if !f.Enabled {
+		return false
+	}
+	for _, target := range f.Targets {
+		if subject.matches(target) {
+			return true
+		}
+	}
+	return subject.inRollout(f)
+}
+
+// EnabledFeatures returns the names of the features enabled for the session, sorted by name
+func EnabledFeatures(db *pop.Connection, session *auth.Session) ([]string, error) {
+	features := []string{}
+	if session == nil || session.UserID == uuid.Nil {
+		return features, nil
+	}
+
+	subject, err := newFeatureSubject(db, session)
+	if err != nil {
+		return features, errors.Wrap(err, "loading feature flag subject")
+	}
+
+	flags, err := FetchFeatureFlags(db)
+	if err != nil {
+		return features, err
+	}
+	enabled := map[string]bool{}
+	for _, flag := range flags {
+		if flag.isEnabledFor(subject) {
+			enabled[flag.Name] = true
+		}
+	}
+
+	if !enabled[FeatureDPS] {
+		isDPSUser, err := IsDPSUser(db, session.Email)
+		if err != nil {
+			return features, err
+		}
+		enabled[FeatureDPS] = isDPSUser
+	}
+
+	for name, on := range enabled {
+		if on {
+			features = append(features, name)
+		}
+	}
+	sort.Strings(features)
+	return features, nil
+}
@@ -0,0 +1,84 @@
+package models_test
+
+import (
+	"github.com/transcom/mymove/pkg/auth"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestFeatureFlagValidation() {
+	flag := &FeatureFlag{Percentage: 101}
+
+	expErrors := map[string][]string{
+		"name":       {"Name can not be blank."},
+		"percentage": {"101 is not less than 101."},
+	}
+	suite.verifyValidationErrors(flag, expErrors)
+}
+
+func (suite *ModelSuite) TestSaveFeatureFlagReplacesTargets() {
+	flag := &FeatureFlag{
+		Name:    "queue_v2",
+		Enabled: true,
+		Targets: FeatureFlagTargets{
+			{TargetType: FeatureFlagTargetTypeGBLOC, TargetValue: "LKBM"},
+			{TargetType: FeatureFlagTargetTypeRole, TargetValue: string(AccessRoleTspUser)},
+		},
+	}
+	verrs, err := SaveFeatureFlag(suite.db, flag)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	flag.Targets = FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeGBLOC, TargetValue: "AGFM"}}
+	verrs, err = SaveFeatureFlag(suite.db, flag)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	fetched, err := FetchFeatureFlagByName(suite.db, "queue_v2")
+	suite.Nil(err)
+	suite.Len(fetched.Targets, 1)
+	suite.Equal("AGFM", fetched.Targets[0].TargetValue)
+
+	_, err = FetchFeatureFlagByName(suite.db, "missing")
+	suite.Equal(ErrFetchNotFound, err)
+}
+
+func (suite *ModelSuite) TestEnabledFeaturesTargeting() {
+	// Default office users belong to an office in GBLOC LKBM
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	session := officeSession(officeUser)
+
+	flags := []FeatureFlag{
+		{Name: "by_gbloc", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeGBLOC, TargetValue: "LKBM"}}},
+		{Name: "by_office", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeTransportationOffice, TargetValue: officeUser.TransportationOfficeID.String()}}},
+		{Name: "by_role", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeRole, TargetValue: string(AccessRoleOfficeUser)}}},
+		{Name: "by_user", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeUser, TargetValue: officeUser.UserID.String()}}},
+		{Name: "everyone", Enabled: true, Percentage: 100},
+		{Name: "disabled", Enabled: false, Percentage: 100},
+		{Name: "other_gbloc", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeGBLOC, TargetValue: "AGFM"}}},
+		{Name: "other_role", Enabled: true, Targets: FeatureFlagTargets{{TargetType: FeatureFlagTargetTypeRole, TargetValue: string(AccessRoleTspUser)}}},
+	}
+	for i := range flags {
+		verrs, err := SaveFeatureFlag(suite.db, &flags[i])
+		suite.Nil(err)
+		suite.False(verrs.HasAny())
+	}
+
+	features, err := EnabledFeatures(suite.db, session)
+	suite.Nil(err)
+	suite.Equal([]string{"by_gbloc", "by_office", "by_role", "by_user", "everyone"}, features)
+}
+
+func (suite *ModelSuite) TestEnabledFeaturesIncludesDPSUsers() {
+	user := testdatagen.MakeDefaultUser(suite.db)
+	suite.mustSave(&DpsUser{LoginGovEmail: user.LoginGovEmail})
+
+	session := &auth.Session{ApplicationName: auth.MyApp, UserID: user.ID, Email: user.LoginGovEmail}
+	features, err := EnabledFeatures(suite.db, session)
+	suite.Nil(err)
+	suite.Equal([]string{FeatureDPS}, features)
+
+	features, err = EnabledFeatures(suite.db, &auth.Session{})
+	suite.Nil(err)
+	suite.Empty(features)
+}
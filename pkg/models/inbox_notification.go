@@ -0,0 +1,89 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// InboxNotification is a notification in a user's in-app inbox
+type InboxNotification struct {
+	ID        uuid.UUID  `json:"id" db:"id"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at" db:"updated_at"`
+	UserID    uuid.UUID  `json:"user_id" db:"user_id"`
+	Event     string     `json:"event" db:"event"`
+	Subject   string     `json:"subject" db:"subject"`
+	Body      string     `json:"body" db:"body"`
+	ReadAt    *time.Time `json:"read_at" db:"read_at"`
+}
+
+// InboxNotifications is not required by pop and may be deleted
+type InboxNotifications []InboxNotification
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *InboxNotification) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: i.UserID, Name: "UserID"},
+		&validators.StringIsPresent{Field: i.Event, Name: "Event"},
+		&validators.StringIsPresent{Field: i.Subject, Name: "Subject"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (i *InboxNotification) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (i *InboxNotification) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// MarkRead marks the notification as read, keeping the time it was first read
+func (i *InboxNotification) MarkRead(at time.Time) {
+	if i.ReadAt == nil {
+		i.ReadAt = &at
+	}
+}
+
+// FetchInboxNotifications returns the notifications in a user's inbox, newest first
+func FetchInboxNotifications(db *pop.Connection, userID uuid.UUID, unreadOnly bool) (InboxNotifications, error) {
+	var notifications InboxNotifications
+	query := db.Where("user_id = $1", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	err := query.Order("created_at desc").All(&notifications)
+	return notifications, err
+}
+
+// CountUnreadInboxNotifications returns how many notifications in a user's inbox haven't been read
+func CountUnreadInboxNotifications(db *pop.Connection, userID uuid.UUID) (int, error) {
+	return db.Where("user_id = $1 AND read_at IS NULL", userID).Count(&InboxNotification{})
+}
+
+// FetchInboxNotificationForUser returns a notification from the inbox of the session's user
+func FetchInboxNotificationForUser(db *pop.Connection, session *auth.Session, id uuid.UUID) (*InboxNotification, error) {
+	var notification InboxNotification
+	err := db.Find(&notification, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if notification.UserID != session.UserID {
+		return nil, ErrFetchForbidden
+	}
+	return &notification, nil
+}
@@ -0,0 +1,60 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/transcom/mymove/pkg/auth"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestInboxNotificationValidations() {
+	notification := &InboxNotification{}
+
+	expErrors := map[string][]string{
+		"user_id": {"UserID can not be blank."},
+		"event":   {"Event can not be blank."},
+		"subject": {"Subject can not be blank."},
+	}
+
+	suite.verifyValidationErrors(notification, expErrors)
+}
+
+func (suite *ModelSuite) TestInboxNotifications() {
+	user := testdatagen.MakeDefaultUser(suite.db)
+	otherUser := testdatagen.MakeDefaultUser(suite.db)
+
+	notification := InboxNotification{
+		UserID:  user.ID,
+		Event:   "move_approved",
+		Subject: "MOVE.MIL: Your move has been approved.",
+		Body:    "Your move has been approved and you are ready to move!",
+	}
+	suite.mustSave(&notification)
+
+	unread, err := CountUnreadInboxNotifications(suite.db, user.ID)
+	suite.Nil(err)
+	suite.Equal(1, unread)
+
+	session := &auth.Session{ApplicationName: auth.MyApp, UserID: user.ID}
+	fetched, err := FetchInboxNotificationForUser(suite.db, session, notification.ID)
+	suite.Nil(err)
+
+	readAt := time.Date(2018, time.December, 6, 12, 0, 0, 0, time.UTC)
+	fetched.MarkRead(readAt)
+	fetched.MarkRead(readAt.Add(time.Hour))
+	suite.mustSave(fetched)
+	suite.True(readAt.Equal(*fetched.ReadAt))
+
+	notifications, err := FetchInboxNotifications(suite.db, user.ID, true)
+	suite.Nil(err)
+	suite.Len(notifications, 0)
+	notifications, err = FetchInboxNotifications(suite.db, user.ID, false)
+	suite.Nil(err)
+	suite.Len(notifications, 1)
+
+	// Users can't read each other's notifications
+	otherSession := &auth.Session{ApplicationName: auth.MyApp, UserID: otherUser.ID}
+	_, err = FetchInboxNotificationForUser(suite.db, otherSession, notification.ID)
+	suite.Equal(ErrFetchForbidden, err)
+}
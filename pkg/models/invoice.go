@@ -71,6 +71,8 @@ func FetchInvoice(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Inv
 	} else if !session.IsOfficeUser() {
 		// Allow office users to fetch invoices
 		return nil, ErrFetchForbidden
+	} else if authErr := AuthorizeMoveAccess(db, session, invoice.Shipment.MoveID); authErr != nil {
+		return nil, authErr
 	}
 
 	return &invoice, nil
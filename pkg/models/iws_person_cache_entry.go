@@ -0,0 +1,85 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// IwsPersonCacheEntry holds an encrypted IWS person lookup result. The lookup digest is a keyed hash
+// of the query parameters, so neither the digest nor the payload reveal PII without the cache key.
+type IwsPersonCacheEntry struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	LookupDigest     string    `json:"lookup_digest" db:"lookup_digest"`
+	EncryptedPayload string    `json:"encrypted_payload" db:"encrypted_payload"`
+	ExpiresAt        time.Time `json:"expires_at" db:"expires_at"`
+}
+
+// IwsPersonCacheEntries is not required by pop and may be deleted
+type IwsPersonCacheEntries []IwsPersonCacheEntry
+
+// String is not required by pop and may be deleted
+func (e IwsPersonCacheEntry) String() string {
+	je, _ := json.Marshal(e)
+	return string(je)
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *IwsPersonCacheEntry) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: e.LookupDigest, Name: "LookupDigest"},
+		&validators.StringIsPresent{Field: e.EncryptedPayload, Name: "EncryptedPayload"},
+		&validators.TimeIsPresent{Field: e.ExpiresAt, Name: "ExpiresAt"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *IwsPersonCacheEntry) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *IwsPersonCacheEntry) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchUnexpiredIwsPersonCacheEntry returns the cache entry for a digest if one exists and has not expired
+func FetchUnexpiredIwsPersonCacheEntry(db *pop.Connection, digest string, now time.Time) (IwsPersonCacheEntry, error) {
+	var entry IwsPersonCacheEntry
+	err := db.Where("lookup_digest = ?", digest).Where("expires_at > ?", now).First(&entry)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return IwsPersonCacheEntry{}, ErrFetchNotFound
+		}
+		return IwsPersonCacheEntry{}, err
+	}
+	return entry, nil
+}
+
+// SaveIwsPersonCacheEntry creates or replaces the cache entry for a digest
+func SaveIwsPersonCacheEntry(db *pop.Connection, digest string, encryptedPayload string, expiresAt time.Time) (*validate.Errors, error) {
+	var entry IwsPersonCacheEntry
+	err := db.Where("lookup_digest = ?", digest).First(&entry)
+	if err != nil && errors.Cause(err).Error() != recordNotFoundErrorString {
+		return validate.NewErrors(), err
+	}
+	entry.LookupDigest = digest
+	entry.EncryptedPayload = encryptedPayload
+	entry.ExpiresAt = expiresAt
+	return db.ValidateAndSave(&entry)
+}
+
+// DeleteExpiredIwsPersonCacheEntries removes every cache entry that expired before now
+func DeleteExpiredIwsPersonCacheEntries(db *pop.Connection, now time.Time) error {
+	return db.RawQuery("DELETE FROM iws_person_cache_entries WHERE expires_at <= ?", now).Exec()
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func (suite *ModelSuite) Test_IwsPersonCacheEntryValidations() {
+	entry := &models.IwsPersonCacheEntry{}
+
+	var expErrors = map[string][]string{
+		"lookup_digest":     {"LookupDigest can not be blank."},
+		"encrypted_payload": {"EncryptedPayload can not be blank."},
+		"expires_at":        {"ExpiresAt can not be blank."},
+	}
+
+	suite.verifyValidationErrors(entry, expErrors)
+}
+
+func (suite *ModelSuite) Test_FetchUnexpiredIwsPersonCacheEntry() {
+	now := time.Now()
+
+	verrs, err := models.SaveIwsPersonCacheEntry(suite.db, "fresh", "payload", now.Add(time.Hour))
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+	verrs, err = models.SaveIwsPersonCacheEntry(suite.db, "stale", "payload", now.Add(-time.Hour))
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	entry, err := models.FetchUnexpiredIwsPersonCacheEntry(suite.db, "fresh", now)
+	suite.NoError(err)
+	suite.Equal("payload", entry.EncryptedPayload)
+
+	_, err = models.FetchUnexpiredIwsPersonCacheEntry(suite.db, "stale", now)
+	suite.Equal(models.ErrFetchNotFound, err)
+
+	// Saving the same digest again replaces the payload rather than adding a row
+	_, err = models.SaveIwsPersonCacheEntry(suite.db, "fresh", "updated", now.Add(time.Hour))
+	suite.NoError(err)
+	count, err := suite.db.Where("lookup_digest = ?", "fresh").Count(&models.IwsPersonCacheEntry{})
+	suite.NoError(err)
+	suite.Equal(1, count)
+
+	suite.NoError(models.DeleteExpiredIwsPersonCacheEntries(suite.db, now))
+	_, err = models.FetchUnexpiredIwsPersonCacheEntry(suite.db, "fresh", now)
+	suite.NoError(err)
+}
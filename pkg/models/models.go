@@ -2,6 +2,8 @@ package models
 
 import (
 	"time"
+
+	"github.com/gobuffalo/pop"
 )
 
 // StringPointer allows you to take the address of a string literal.
@@ -39,3 +41,13 @@ func TimePointer(t time.Time) *time.Time {
 func BoolPointer(b bool) *bool {
 	return &b
 }
+
+// inTransaction runs fn in a new transaction, or in db's when db is already one. Save functions use it
+// so that callers can make them part of a larger transaction; pop would otherwise commit the caller's
+// transaction when fn returns.
+func inTransaction(db *pop.Connection, fn func(tx *pop.Connection) error) error {
+	if db.TX != nil {
+		return fn(db)
+	}
+	return db.Transaction(fn)
+}
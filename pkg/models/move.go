@@ -190,6 +190,59 @@ func (m *Move) Cancel(reason string) error {
 	return nil
 }
 
+// AuditSnapshot returns a copy of the move that won't change when the move, its PPMs, their advances or its
+// shipments transition, for TransitionAuditEvents to compare against
+func (m *Move) AuditSnapshot() Move {
+	snapshot := *m
+	snapshot.PersonallyProcuredMoves = make(PersonallyProcuredMoves, len(m.PersonallyProcuredMoves))
+	for i, ppm := range m.PersonallyProcuredMoves {
+		if ppm.Advance != nil {
+			advance := *ppm.Advance
+			ppm.Advance = &advance
+		}
+		snapshot.PersonallyProcuredMoves[i] = ppm
+	}
+	snapshot.Shipments = append(Shipments{}, m.Shipments...)
+	return snapshot
+}
+
+// TransitionAuditEvents returns audit events for the move and for each of its PPMs, advances, shipments and
+// orders whose status changed since before was snapshotted
+func (m *Move) TransitionAuditEvents(session *auth.Session, before Move, event string, reason string) []AuditEvent {
+	events := []AuditEvent{NewAuditEvent(session, &before, m, event, reason)}
+
+	for i := range m.PersonallyProcuredMoves {
+		ppm := &m.PersonallyProcuredMoves[i]
+		for j := range before.PersonallyProcuredMoves {
+			beforePPM := &before.PersonallyProcuredMoves[j]
+			if beforePPM.ID != ppm.ID {
+				continue
+			}
+			if beforePPM.Status != ppm.Status {
+				events = append(events, NewAuditEvent(session, beforePPM, ppm, event, reason))
+			}
+			if ppm.Advance != nil && beforePPM.Advance != nil && beforePPM.Advance.Status != ppm.Advance.Status {
+				events = append(events, NewAuditEvent(session, beforePPM.Advance, ppm.Advance, event, reason))
+			}
+		}
+	}
+
+	for i := range m.Shipments {
+		shipment := &m.Shipments[i]
+		for j := range before.Shipments {
+			beforeShipment := &before.Shipments[j]
+			if beforeShipment.ID == shipment.ID && beforeShipment.Status != shipment.Status {
+				events = append(events, NewAuditEvent(session, beforeShipment, shipment, event, reason))
+			}
+		}
+	}
+
+	if before.Orders.Status != m.Orders.Status {
+		events = append(events, NewAuditEvent(session, &before.Orders, &m.Orders, event, reason))
+	}
+	return events
+}
+
 // FetchMove fetches and validates a Move for this User
 func FetchMove(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Move, error) {
 	var move Move
@@ -239,7 +292,7 @@ func FetchMove(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Move,
 	}
 
 	// Ensure that the logged-in user is authorized to access this move
-	_, authErr := FetchOrderForUser(db, session, move.OrdersID)
+	authErr := AuthorizeMoveAccess(db, session, move.ID)
 	if authErr != nil {
 		return nil, authErr
 	}
@@ -333,7 +386,7 @@ func (m Move) CreateMoveDocument(
 	var responseError error
 	responseVErrors := validate.NewErrors()
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		newMoveDocument, responseVErrors, responseError = m.createMoveDocumentWithoutTransaction(
@@ -367,13 +420,14 @@ func (m Move) CreateMovingExpenseDocument(
 	requestedAmountCents unit.Cents,
 	paymentMethod string,
 	movingExpenseType MovingExpenseType,
+	ppmTripID *uuid.UUID,
 	moveType SelectedMoveType) (*MovingExpenseDocument, *validate.Errors, error) {
 
 	var newMovingExpenseDocument *MovingExpenseDocument
 	var responseError error
 	responseVErrors := validate.NewErrors()
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		var newMoveDocument *MoveDocument
@@ -396,6 +450,7 @@ func (m Move) CreateMovingExpenseDocument(
 			MovingExpenseType:    movingExpenseType,
 			RequestedAmountCents: requestedAmountCents,
 			PaymentMethod:        paymentMethod,
+			PPMTripID:            ppmTripID,
 		}
 		verrs, err := db.ValidateAndCreate(newMovingExpenseDocument)
 		if err != nil || verrs.HasAny() {
@@ -540,12 +595,12 @@ func createNewMove(db *pop.Connection,
 }
 
 // SaveMoveDependencies safely saves a Move status, ppms' advances' statuses, orders statuses,
-// and shipment GBLOCs.
-func SaveMoveDependencies(db *pop.Connection, move *Move) (*validate.Errors, error) {
+// and shipment GBLOCs, along with audit records for the changes.
+func SaveMoveDependencies(db *pop.Connection, move *Move, audits ...AuditEvent) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		for _, ppm := range move.PersonallyProcuredMoves {
@@ -620,6 +675,12 @@ func SaveMoveDependencies(db *pop.Connection, move *Move) (*validate.Errors, err
 			responseError = errors.Wrap(err, "Error Saving Move")
 			return transactionError
 		}
+
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
 		return nil
 	})
 
@@ -74,6 +74,8 @@ const (
 	MoveDocumentTypeFIREARMSCHAINOFCUSTODY = "FIREARMS_CHAIN_OF_CUSTODY"
 	// MoveDocumentTypePHOTO captures enum value "PHOTO"
 	MoveDocumentTypePHOTO = "PHOTO"
+	// MoveDocumentTypePREMOVESURVEYREPORT captures enum value "PREMOVE_SURVEY_REPORT"
+	MoveDocumentTypePREMOVESURVEYREPORT MoveDocumentType = "PREMOVE_SURVEY_REPORT"
 )
 
 // MoveDocumentSaveAction represents actions that can be taken during save
@@ -101,6 +103,7 @@ type MoveDocument struct {
 	Status                   MoveDocumentStatus     `json:"status" db:"status"`
 	MoveDocumentType         MoveDocumentType       `json:"move_document_type" db:"move_document_type"`
 	MovingExpenseDocument    *MovingExpenseDocument `has_one:"moving_expense_document"`
+	WeightTicketDocument     *WeightTicketDocument  `has_one:"weight_ticket_document"`
 	Notes                    *string                `json:"notes" db:"notes"`
 	CreatedAt                time.Time              `json:"created_at" db:"created_at"`
 	UpdatedAt                time.Time              `json:"updated_at" db:"updated_at"`
@@ -175,11 +178,6 @@ func (m *MoveDocument) ValidateUpdate(tx *pop.Connection) (*validate.Errors, err
 
 // FetchMoveDocument fetches a MoveDocument model
 func FetchMoveDocument(db *pop.Connection, session *auth.Session, id uuid.UUID) (*MoveDocument, error) {
-	// Allow all office users to fetch move doc
-	if session.IsOfficeApp() && session.OfficeUserID == uuid.Nil {
-		return &MoveDocument{}, ErrFetchForbidden
-	}
-
 	var moveDoc MoveDocument
 	err := db.Q().Eager("Document.Uploads", "Move", "PersonallyProcuredMove", "Shipment").Find(&moveDoc, id)
 	if err != nil {
@@ -201,9 +199,20 @@ func FetchMoveDocument(db *pop.Connection, session *auth.Session, id uuid.UUID)
 		moveDoc.MovingExpenseDocument = &movingExpenseDocument
 	}
 
-	// Check that the logged-in service member is associated to the document
-	if session.IsMyApp() && moveDoc.Document.ServiceMemberID != session.ServiceMemberID {
-		return &MoveDocument{}, ErrFetchForbidden
+	weightTicketDocument := WeightTicketDocument{}
+	moveDoc.WeightTicketDocument = nil
+	err = db.Where("move_document_id = $1", moveDoc.ID.String()).First(&weightTicketDocument)
+	if err != nil {
+		if errors.Cause(err).Error() != recordNotFoundErrorString {
+			return nil, err
+		}
+	} else {
+		moveDoc.WeightTicketDocument = &weightTicketDocument
+	}
+
+	// Check that the logged-in user may access the document's move
+	if authErr := AuthorizeMoveAccess(db, session, moveDoc.MoveID); authErr != nil {
+		return &MoveDocument{}, authErr
 	}
 
 	return &moveDoc, nil
@@ -211,14 +220,10 @@ func FetchMoveDocument(db *pop.Connection, session *auth.Session, id uuid.UUID)
 
 // FetchApprovedMovingExpenseDocuments fetches all approved move expense document for a ppm
 func FetchApprovedMovingExpenseDocuments(db *pop.Connection, session *auth.Session, ppmID uuid.UUID) (MoveDocuments, error) {
-	// Allow all logged in office users to fetch move docs
-	if session.IsOfficeApp() && session.OfficeUserID == uuid.Nil {
-		return nil, ErrFetchForbidden
-	}
-	// Validate the move is associated to the logged-in service member
+	// Check that the logged-in user may access the PPM's move
 	_, fetchErr := FetchPersonallyProcuredMove(db, session, ppmID)
 	if fetchErr != nil {
-		return nil, ErrFetchForbidden
+		return nil, fetchErr
 	}
 
 	var moveDocuments MoveDocuments
@@ -248,32 +253,20 @@ func FetchApprovedMovingExpenseDocuments(db *pop.Connection, session *auth.Sessi
 // FetchMoveDocumentsByTypeForShipment fetches move documents for shipment and move document type
 func FetchMoveDocumentsByTypeForShipment(db *pop.Connection, session *auth.Session, moveDocumentType MoveDocumentType, shipmentID uuid.UUID) (MoveDocuments, error) {
 
-	// Verify that the logged-in TSP user is authorized to generate GBL
-	// Does this need to be checked here if already checked in create gbl handler?
-	if session.IsTspApp() {
-		if session.TspUserID == uuid.Nil {
-			return nil, ErrFetchForbidden
-		}
-		tspUser, err := FetchTspUserByID(db, session.TspUserID)
-		if err != nil {
+	var shipment Shipment
+	err := db.Find(&shipment, shipmentID)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
 			return nil, ErrFetchNotFound
 		}
-		shipment, err := FetchShipmentByTSP(db, tspUser.TransportationServiceProviderID, shipmentID)
-		if err != nil {
-			return nil, ErrFetchForbidden
-		}
-		if shipment.ID != shipmentID {
-			return nil, ErrFetchForbidden
-		}
+		return nil, err
 	}
-
-	// Allow all logged in office users to fetch move docs
-	if session.IsOfficeApp() && session.OfficeUserID == uuid.Nil {
-		return nil, ErrFetchForbidden
+	if err := AuthorizeShipmentAccess(db, session, &shipment); err != nil {
+		return nil, err
 	}
 
 	var moveDocuments MoveDocuments
-	err := db.Where("move_document_type = $1", string(moveDocumentType)).Where("shipment_id = $2", shipmentID.String()).All(&moveDocuments)
+	err = db.Where("move_document_type = $1", string(moveDocumentType)).Where("shipment_id = $2", shipmentID.String()).All(&moveDocuments)
 	if err != nil {
 		if errors.Cause(err).Error() != recordNotFoundErrorString {
 			return nil, err
@@ -282,12 +275,12 @@ func FetchMoveDocumentsByTypeForShipment(db *pop.Connection, session *auth.Sessi
 	return moveDocuments, nil
 }
 
-// SaveMoveDocument saves a move document
-func SaveMoveDocument(db *pop.Connection, moveDocument *MoveDocument, saveAction MoveDocumentSaveAction) (*validate.Errors, error) {
+// SaveMoveDocument saves a move document along with audit records for the changes
+func SaveMoveDocument(db *pop.Connection, moveDocument *MoveDocument, saveAction MoveDocumentSaveAction, audits ...AuditEvent) (*validate.Errors, error) {
 	var responseError error
 	responseVErrors := validate.NewErrors()
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		if saveAction == MoveDocumentSaveActionSAVEEXPENSEMODEL {
@@ -337,6 +330,12 @@ func SaveMoveDocument(db *pop.Connection, moveDocument *MoveDocument, saveAction
 			return transactionError
 		}
 
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
 		return nil
 	})
 
@@ -91,7 +91,7 @@ func (suite *ModelSuite) TestFetchMoveDocumentsByTypeForShipment() {
 	}
 	_, err = FetchMoveDocumentsByTypeForShipment(suite.db, session, MoveDocumentTypeSHIPMENTSUMMARY, shipment.ID)
 	// Then: FetchForbiddenError should be returned
-	suite.Equal("FETCH_NOT_FOUND", err.Error())
+	suite.Equal(ErrFetchForbidden, err)
 }
 
 func (suite *ModelSuite) TestFetchApprovedMovingExpenseDocuments() {
@@ -23,6 +23,10 @@ type MoveDocumentExtractor struct {
 	MovingExpenseType        *MovingExpenseType `json:"moving_expense_type" db:"moving_expense_type"`
 	RequestedAmountCents     *unit.Cents        `json:"requested_amount_cents" db:"requested_amount_cents"`
 	PaymentMethod            *string            `json:"payment_method" db:"payment_method"`
+	PPMTripID                *uuid.UUID         `json:"ppm_trip_id" db:"ppm_trip_id"`
+	TripNumber               *int               `json:"trip_number" db:"trip_number"`
+	EmptyWeight              *unit.Pound        `json:"empty_weight" db:"empty_weight"`
+	FullWeight               *unit.Pound        `json:"full_weight" db:"full_weight"`
 	Notes                    *string            `json:"notes" db:"notes"`
 	CreatedAt                time.Time          `json:"created_at" db:"created_at"`
 	UpdatedAt                time.Time          `json:"updated_at" db:"updated_at"`
@@ -35,10 +39,11 @@ type MoveDocumentExtractors []MoveDocumentExtractor
 func (m *Move) FetchAllMoveDocumentsForMove(db *pop.Connection) (MoveDocumentExtractors, error) {
 	var moveDocs MoveDocumentExtractors
 	query := db.Q().LeftJoin("moving_expense_documents ed", "ed.move_document_id=move_documents.id").
+		LeftJoin("weight_ticket_documents wt", "wt.move_document_id=move_documents.id").
 		Where("move_documents.move_id=$1", m.ID.String())
 
 	sql, args := query.ToSQL(&pop.Model{Value: MoveDocument{}},
-		"move_documents.*, ed.moving_expense_type, ed.requested_amount_cents, ed.payment_method")
+		"move_documents.*, ed.moving_expense_type, ed.requested_amount_cents, ed.payment_method, ed.ppm_trip_id, wt.trip_number, wt.empty_weight, wt.full_weight")
 
 	err := db.RawQuery(sql, args...).Eager("Document.Uploads").All(&moveDocs)
 	if err != nil {
@@ -55,6 +55,7 @@ type MovingExpenseDocument struct {
 	MovingExpenseType    MovingExpenseType `json:"moving_expense_type" db:"moving_expense_type"`
 	RequestedAmountCents unit.Cents        `json:"requested_amount_cents" db:"requested_amount_cents"`
 	PaymentMethod        string            `json:"payment_method" db:"payment_method"`
+	PPMTripID            *uuid.UUID        `json:"ppm_trip_id" db:"ppm_trip_id"`
 	CreatedAt            time.Time         `json:"created_at" db:"created_at"`
 	UpdatedAt            time.Time         `json:"updated_at" db:"updated_at"`
 }
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// NotificationChannel is how a notification reaches its recipient
+type NotificationChannel string
+
+const (
+	// NotificationChannelEmail sends notifications by email through SES
+	NotificationChannelEmail NotificationChannel = "email"
+	// NotificationChannelSMS sends notifications by text message through an SMS provider
+	NotificationChannelSMS NotificationChannel = "sms"
+	// NotificationChannelInbox stores notifications in the user's in-app inbox
+	NotificationChannelInbox NotificationChannel = "inbox"
+)
+
+// NotificationChannels are every channel notifications can be sent through
+var NotificationChannels = []NotificationChannel{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelInbox}
+
+// NotificationStatus is the delivery status of a notification
+type NotificationStatus string
+
+const (
+	// NotificationStatusSENT is a notification that was handed to the channel's provider
+	NotificationStatusSENT NotificationStatus = "SENT"
+	// NotificationStatusFAILED is a notification the provider refused
+	NotificationStatusFAILED NotificationStatus = "FAILED"
+	// NotificationStatusSUPPRESSED is a notification that wasn't sent because of the recipient's
+	// preferences or earlier bounces and complaints
+	NotificationStatusSUPPRESSED NotificationStatus = "SUPPRESSED"
+	// NotificationStatusDELIVERED is a notification the provider delivered
+	NotificationStatusDELIVERED NotificationStatus = "DELIVERED"
+	// NotificationStatusBOUNCED is a notification that permanently bounced
+	NotificationStatusBOUNCED NotificationStatus = "BOUNCED"
+	// NotificationStatusCOMPLAINED is a notification the recipient marked as spam
+	NotificationStatusCOMPLAINED NotificationStatus = "COMPLAINED"
+)
+
+// NotificationLog records a notification sent, or suppressed, for a recipient
+type NotificationLog struct {
+	ID           uuid.UUID           `json:"id" db:"id"`
+	CreatedAt    time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at" db:"updated_at"`
+	UserID       *uuid.UUID          `json:"user_id" db:"user_id"`
+	Event        string              `json:"event" db:"event"`
+	Channel      NotificationChannel `json:"channel" db:"channel"`
+	Recipient    string              `json:"recipient" db:"recipient"`
+	Subject      string              `json:"subject" db:"subject"`
+	Status       NotificationStatus  `json:"status" db:"status"`
+	StatusDetail *string             `json:"status_detail" db:"status_detail"`
+	SESMessageID *string             `json:"ses_message_id" db:"ses_message_id"`
+}
+
+// NotificationLogs is not required by pop and may be deleted
+type NotificationLogs []NotificationLog
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (n *NotificationLog) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validStatuses := []string{
+		string(NotificationStatusSENT),
+		string(NotificationStatusFAILED),
+		string(NotificationStatusSUPPRESSED),
+		string(NotificationStatusDELIVERED),
+		string(NotificationStatusBOUNCED),
+		string(NotificationStatusCOMPLAINED),
+	}
+	return validate.Validate(
+		&validators.StringIsPresent{Field: n.Event, Name: "Event"},
+		&validators.StringIsPresent{Field: string(n.Channel), Name: "Channel"},
+		&validators.StringIsPresent{Field: n.Recipient, Name: "Recipient"},
+		&validators.StringInclusion{Field: string(n.Status), Name: "Status", List: validStatuses},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (n *NotificationLog) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (n *NotificationLog) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchNotificationLogBySESMessageID returns the log of the email SES sent with the message ID
+func FetchNotificationLogBySESMessageID(db *pop.Connection, messageID string) (*NotificationLog, error) {
+	var log NotificationLog
+	err := db.Where("ses_message_id = $1", messageID).First(&log)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &log, nil
+}
+
+// FetchNotificationLogs returns the notifications sent to a user, newest first
+func FetchNotificationLogs(db *pop.Connection, userID uuid.UUID) (NotificationLogs, error) {
+	var logs NotificationLogs
+	err := db.Where("user_id = $1", userID).Order("created_at desc").All(&logs)
+	return logs, err
+}
+
+// IsRecipientSuppressed returns whether earlier notifications to the recipient bounced permanently or
+// were marked as spam, in which case nothing more may be sent to them through the channel
+func IsRecipientSuppressed(db *pop.Connection, channel NotificationChannel, recipient string) (bool, error) {
+	count, err := db.Where("channel = $1 AND recipient = $2 AND status IN ($3, $4)",
+		channel, recipient, NotificationStatusBOUNCED, NotificationStatusCOMPLAINED).
+		Count(&NotificationLog{})
+	return count > 0, err
+}
@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+)
+
+// NotificationPreference is whether a user receives an event's notifications through a channel.
+// Users receive every notification they have no preference for.
+type NotificationPreference struct {
+	ID        uuid.UUID           `json:"id" db:"id"`
+	CreatedAt time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time           `json:"updated_at" db:"updated_at"`
+	UserID    uuid.UUID           `json:"user_id" db:"user_id"`
+	Event     string              `json:"event" db:"event"`
+	Channel   NotificationChannel `json:"channel" db:"channel"`
+	Enabled   bool                `json:"enabled" db:"enabled"`
+}
+
+// NotificationPreferences is not required by pop and may be deleted
+type NotificationPreferences []NotificationPreference
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (n *NotificationPreference) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validChannels := make([]string, len(NotificationChannels))
+	for i, channel := range NotificationChannels {
+		validChannels[i] = string(channel)
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: n.UserID, Name: "UserID"},
+		&validators.StringIsPresent{Field: n.Event, Name: "Event"},
+		&validators.StringInclusion{Field: string(n.Channel), Name: "Channel", List: validChannels},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (n *NotificationPreference) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (n *NotificationPreference) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchNotificationPreferences returns the preferences a user has set
+func FetchNotificationPreferences(db *pop.Connection, userID uuid.UUID) (NotificationPreferences, error) {
+	var preferences NotificationPreferences
+	err := db.Where("user_id = $1", userID).Order("event asc, channel asc").All(&preferences)
+	return preferences, err
+}
+
+// IsNotificationEnabled returns whether a user receives an event's notifications through a channel
+func IsNotificationEnabled(db *pop.Connection, userID uuid.UUID, event string, channel NotificationChannel) (bool, error) {
+	var preferences NotificationPreferences
+	err := db.Where("user_id = $1 AND event = $2 AND channel = $3", userID, event, channel).All(&preferences)
+	if err != nil || len(preferences) == 0 {
+		return true, err
+	}
+	return preferences[0].Enabled, nil
+}
+
+// SetNotificationPreference turns an event's notifications through a channel on or off for a user
+func SetNotificationPreference(db *pop.Connection, userID uuid.UUID, event string, channel NotificationChannel, enabled bool) (*NotificationPreference, *validate.Errors, error) {
+	var preferences NotificationPreferences
+	err := db.Where("user_id = $1 AND event = $2 AND channel = $3", userID, event, channel).All(&preferences)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+
+	preference := NotificationPreference{UserID: userID, Event: event, Channel: channel}
+	if len(preferences) > 0 {
+		preference = preferences[0]
+	}
+	preference.Enabled = enabled
+	verrs, err := db.ValidateAndSave(&preference)
+	return &preference, verrs, err
+}
@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestNotificationPreferenceValidations() {
+	preference := &NotificationPreference{Channel: "carrier_pigeon"}
+
+	expErrors := map[string][]string{
+		"user_id": {"UserID can not be blank."},
+		"event":   {"Event can not be blank."},
+		"channel": {"Channel is not in the list [email, sms, inbox]."},
+	}
+
+	suite.verifyValidationErrors(preference, expErrors)
+}
+
+func (suite *ModelSuite) TestSetNotificationPreference() {
+	user := testdatagen.MakeDefaultUser(suite.db)
+
+	// Users receive notifications they have no preference for
+	enabled, err := IsNotificationEnabled(suite.db, user.ID, "move_approved", NotificationChannelEmail)
+	suite.Nil(err)
+	suite.True(enabled)
+
+	_, verrs, err := SetNotificationPreference(suite.db, user.ID, "move_approved", NotificationChannelEmail, false)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	enabled, err = IsNotificationEnabled(suite.db, user.ID, "move_approved", NotificationChannelEmail)
+	suite.Nil(err)
+	suite.False(enabled)
+
+	// Setting it again updates the same preference
+	_, verrs, err = SetNotificationPreference(suite.db, user.ID, "move_approved", NotificationChannelEmail, true)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	preferences, err := FetchNotificationPreferences(suite.db, user.ID)
+	suite.Nil(err)
+	suite.Len(preferences, 1)
+	suite.True(preferences[0].Enabled)
+}
+
+func (suite *ModelSuite) TestIsRecipientSuppressed() {
+	log := NotificationLog{
+		Event:     "move_approved",
+		Channel:   NotificationChannelEmail,
+		Recipient: "bounce@example.com",
+		Subject:   "Approved",
+		Status:    NotificationStatusDELIVERED,
+	}
+	suite.mustSave(&log)
+
+	suppressed, err := IsRecipientSuppressed(suite.db, NotificationChannelEmail, "bounce@example.com")
+	suite.Nil(err)
+	suite.False(suppressed)
+
+	log.Status = NotificationStatusBOUNCED
+	suite.mustSave(&log)
+	suppressed, err = IsRecipientSuppressed(suite.db, NotificationChannelEmail, "bounce@example.com")
+	suite.Nil(err)
+	suite.True(suppressed)
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+)
+
+// NotificationTemplate is a version of the subject and bodies of the email sent for a notification
+// event. The latest version of an event is sent, and events without versions use the templates
+// in the notifications package.
+type NotificationTemplate struct {
+	ID                    uuid.UUID  `json:"id" db:"id"`
+	CreatedAt             time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at" db:"updated_at"`
+	Event                 string     `json:"event" db:"event"`
+	Version               int        `json:"version" db:"version"`
+	Subject               string     `json:"subject" db:"subject"`
+	HTMLBody              string     `json:"html_body" db:"html_body"`
+	TextBody              string     `json:"text_body" db:"text_body"`
+	CreatedByOfficeUserID *uuid.UUID `json:"created_by_office_user_id" db:"created_by_office_user_id"`
+}
+
+// NotificationTemplates is not required by pop and may be deleted
+type NotificationTemplates []NotificationTemplate
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (n *NotificationTemplate) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: n.Event, Name: "Event"},
+		&validators.IntIsGreaterThan{Field: n.Version, Name: "Version", Compared: 0},
+		&validators.StringIsPresent{Field: n.Subject, Name: "Subject"},
+		&validators.StringIsPresent{Field: n.HTMLBody, Name: "HTMLBody"},
+		&validators.StringIsPresent{Field: n.TextBody, Name: "TextBody"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (n *NotificationTemplate) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (n *NotificationTemplate) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchNotificationTemplateVersions returns every version of an event's templates, newest first
+func FetchNotificationTemplateVersions(db *pop.Connection, event string) (NotificationTemplates, error) {
+	var templates NotificationTemplates
+	err := db.Where("event = $1", event).Order("version desc").All(&templates)
+	return templates, err
+}
+
+// FetchLatestNotificationTemplate returns the latest version of an event's templates, or nil if
+// the event has no stored versions
+func FetchLatestNotificationTemplate(db *pop.Connection, event string) (*NotificationTemplate, error) {
+	var templates NotificationTemplates
+	err := db.Where("event = $1", event).Order("version desc").Limit(1).All(&templates)
+	if err != nil || len(templates) == 0 {
+		return nil, err
+	}
+	return &templates[0], nil
+}
+
+// CreateNotificationTemplateVersion saves the template as the next version of its event
+func CreateNotificationTemplateVersion(db *pop.Connection, template *NotificationTemplate) (*validate.Errors, error) {
+	latest, err := FetchLatestNotificationTemplate(db, template.Event)
+	if err != nil {
+		return validate.NewErrors(), err
+	}
+	template.Version = 1
+	if latest != nil {
+		template.Version = latest.Version + 1
+	}
+	return db.ValidateAndCreate(template)
+}
@@ -0,0 +1,53 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+)
+
+func (suite *ModelSuite) TestNotificationTemplateValidations() {
+	template := &NotificationTemplate{}
+
+	expErrors := map[string][]string{
+		"event":     {"Event can not be blank."},
+		"version":   {"0 is not greater than 0."},
+		"subject":   {"Subject can not be blank."},
+		"html_body": {"HTMLBody can not be blank."},
+		"text_body": {"TextBody can not be blank."},
+	}
+
+	suite.verifyValidationErrors(template, expErrors)
+}
+
+func (suite *ModelSuite) TestCreateNotificationTemplateVersion() {
+	latest, err := FetchLatestNotificationTemplate(suite.db, "move_approved")
+	suite.Nil(err)
+	suite.Nil(latest)
+
+	for i := 1; i <= 2; i++ {
+		template := NotificationTemplate{
+			Event:    "move_approved",
+			Subject:  "Approved",
+			HTMLBody: "<p>Approved</p>",
+			TextBody: "Approved",
+		}
+		verrs, err := CreateNotificationTemplateVersion(suite.db, &template)
+		suite.Nil(err)
+		suite.False(verrs.HasAny())
+		suite.Equal(i, template.Version)
+	}
+
+	// Versions are numbered per event
+	other := NotificationTemplate{Event: "move_canceled", Subject: "Canceled", HTMLBody: "Canceled", TextBody: "Canceled"}
+	_, err = CreateNotificationTemplateVersion(suite.db, &other)
+	suite.Nil(err)
+	suite.Equal(1, other.Version)
+
+	latest, err = FetchLatestNotificationTemplate(suite.db, "move_approved")
+	suite.Nil(err)
+	suite.Equal(2, latest.Version)
+
+	versions, err := FetchNotificationTemplateVersions(suite.db, "move_approved")
+	suite.Nil(err)
+	suite.Len(versions, 2)
+	suite.Equal(2, versions[0].Version)
+}
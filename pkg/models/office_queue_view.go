@@ -0,0 +1,128 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// OfficeQueueView is a named set of move queue filters and sorts saved by an office user
+type OfficeQueueView struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	OfficeUserID uuid.UUID `json:"office_user_id" db:"office_user_id"`
+	Name         string    `json:"name" db:"name"`
+	QueueType    string    `json:"queue_type" db:"queue_type"`
+	Filters      string    `json:"filters" db:"filters"`
+	Sort         string    `json:"sort" db:"sort"`
+}
+
+// OfficeQueueViews is not required by pop and may be deleted
+type OfficeQueueViews []OfficeQueueView
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (v *OfficeQueueView) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.UUIDIsPresent{Field: v.OfficeUserID, Name: "OfficeUserID"},
+		&validators.StringIsPresent{Field: v.Name, Name: "Name"},
+		&validators.StringInclusion{Field: v.QueueType, Name: "QueueType", List: MoveQueueTypes},
+	)
+	if filters, err := v.MoveQueueFilters(); err != nil || !filters.hasValidAssignee() {
+		verrs.Add("filters", "Filters must be valid move queue filters.")
+	}
+	if _, err := v.MoveQueueSort(); err != nil {
+		verrs.Add("sort", "Sort must only use known columns.")
+	}
+	return verrs, nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (v *OfficeQueueView) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (v *OfficeQueueView) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// MoveQueueFilters decodes the view's filters
+func (v *OfficeQueueView) MoveQueueFilters() (MoveQueueFilters, error) {
+	var filters MoveQueueFilters
+	if v.Filters == "" {
+		return filters, nil
+	}
+	err := json.Unmarshal([]byte(v.Filters), &filters)
+	return filters, err
+}
+
+// SetMoveQueueFilters encodes filters into the view
+func (v *OfficeQueueView) SetMoveQueueFilters(filters MoveQueueFilters) error {
+	encoded, err := json.Marshal(filters)
+	if err != nil {
+		return err
+	}
+	v.Filters = string(encoded)
+	return nil
+}
+
+// MoveQueueSort decodes the view's comma separated sorts
+func (v *OfficeQueueView) MoveQueueSort() ([]MoveQueueSort, error) {
+	if v.Sort == "" {
+		return []MoveQueueSort{}, nil
+	}
+	return ParseMoveQueueSort(strings.Split(v.Sort, ","))
+}
+
+// SetMoveQueueSort encodes sorts into the view
+func (v *OfficeQueueView) SetMoveQueueSort(sorts []MoveQueueSort) {
+	v.Sort = sortSignature(sorts)
+}
+
+// MoveQueueParams returns the params for the first page of the view's queue
+func (v *OfficeQueueView) MoveQueueParams(limit int) (MoveQueueParams, error) {
+	filters, err := v.MoveQueueFilters()
+	if err != nil {
+		return MoveQueueParams{}, err
+	}
+	sorts, err := v.MoveQueueSort()
+	if err != nil {
+		return MoveQueueParams{}, err
+	}
+	return MoveQueueParams{LifecycleState: v.QueueType, Filters: filters, Sort: sorts, Limit: limit}, nil
+}
+
+// FetchOfficeQueueViews returns the session's office user's saved views ordered by name
+func FetchOfficeQueueViews(db *pop.Connection, session *auth.Session) (OfficeQueueViews, error) {
+	var views OfficeQueueViews
+	err := db.Where("office_user_id = $1", session.OfficeUserID).Order("name asc").All(&views)
+	return views, err
+}
+
+// FetchOfficeQueueView returns a saved view if it belongs to the session's office user
+func FetchOfficeQueueView(db *pop.Connection, session *auth.Session, id uuid.UUID) (*OfficeQueueView, error) {
+	var view OfficeQueueView
+	err := db.Find(&view, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if view.OfficeUserID != session.OfficeUserID {
+		return nil, denyAccess(session, "office_queue_view", id, "view belongs to another office user")
+	}
+	return &view, nil
+}
@@ -0,0 +1,46 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestOfficeQueueViewValidation() {
+	view := &OfficeQueueView{QueueType: "troubleshooting", Filters: "not json", Sort: "ssn"}
+	expErrors := map[string][]string{
+		"office_user_id": {"OfficeUserID can not be blank."},
+		"name":           {"Name can not be blank."},
+		"queue_type":     {"QueueType is not in the list [new, ppm, hhg_accepted, hhg_in_transit, hhg_delivered, hhg_completed, all]."},
+		"filters":        {"Filters must be valid move queue filters."},
+		"sort":           {"Sort must only use known columns."},
+	}
+	suite.verifyValidationErrors(view, expErrors)
+}
+
+func (suite *ModelSuite) TestFetchOfficeQueueView() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	otherOfficeUser := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User: User{LoginGovEmail: "other_office_user@example.com"},
+	})
+
+	view := OfficeQueueView{OfficeUserID: officeUser.ID, Name: "Navy", QueueType: "new"}
+	suite.Nil(view.SetMoveQueueFilters(MoveQueueFilters{Branches: []string{"NAVY"}}))
+	view.SetMoveQueueSort([]MoveQueueSort{{Column: "move_date", Descending: true}})
+	suite.mustSave(&view)
+
+	views, err := FetchOfficeQueueViews(suite.db, officeSession(officeUser))
+	suite.Nil(err)
+	suite.Len(views, 1)
+
+	fetched, err := FetchOfficeQueueView(suite.db, officeSession(officeUser), view.ID)
+	suite.Nil(err)
+	params, err := fetched.MoveQueueParams(25)
+	suite.Nil(err)
+	suite.Equal("new", params.LifecycleState)
+	suite.Equal([]string{"NAVY"}, params.Filters.Branches)
+	suite.Equal([]MoveQueueSort{{Column: "move_date", Descending: true}}, params.Sort)
+	suite.Equal(25, params.Limit)
+
+	_, err = FetchOfficeQueueView(suite.db, officeSession(otherOfficeUser), view.ID)
+	suite.Equal(ErrFetchForbidden, err)
+}
@@ -5,8 +5,11 @@ import (
 	"github.com/gobuffalo/validate"
 	"github.com/gobuffalo/validate/validators"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"strings"
 	"time"
+
+	"github.com/transcom/mymove/pkg/auth"
 )
 
 // OfficeUser is someone who works in one of the TransportationOffices
@@ -21,6 +24,10 @@ type OfficeUser struct {
 	Telephone              string               `json:"telephone" db:"telephone"`
 	TransportationOfficeID uuid.UUID            `json:"transportation_office_id" db:"transportation_office_id"`
 	TransportationOffice   TransportationOffice `belongs_to:"transportation_office"`
+	AccessScope            OfficeAccessScope    `json:"access_scope" db:"access_scope"`
+	IsSupervisor           bool                 `json:"is_supervisor" db:"is_supervisor"`
+	IsAdmin                bool                 `json:"is_admin" db:"is_admin"`
+	Deactivated            bool                 `json:"deactivated" db:"deactivated"`
 	CreatedAt              time.Time            `json:"created_at" db:"created_at"`
 	UpdatedAt              time.Time            `json:"updated_at" db:"updated_at"`
 }
@@ -31,14 +38,31 @@ type OfficeUsers []OfficeUser
 // Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
 // This method is not required and may be deleted.
 func (o *OfficeUser) Validate(tx *pop.Connection) (*validate.Errors, error) {
-
-	return validate.Validate(
+	vs := []validate.Validator{
 		&validators.StringIsPresent{Field: o.LastName, Name: "LastName"},
 		&validators.StringIsPresent{Field: o.FirstName, Name: "FirstName"},
 		&validators.StringIsPresent{Field: o.Email, Name: "Email"},
 		&validators.StringIsPresent{Field: o.Telephone, Name: "Telephone"},
 		&validators.UUIDIsPresent{Field: o.TransportationOfficeID, Name: "TransportationOfficeID"},
-	), nil
+	}
+	// A blank scope is defaulted in BeforeSave
+	if o.AccessScope != "" {
+		validScopes := []string{
+			string(OfficeAccessScopeGBLOC),
+			string(OfficeAccessScopeTransportationOffice),
+			string(OfficeAccessScopeNational),
+		}
+		vs = append(vs, &validators.StringInclusion{Field: string(o.AccessScope), Name: "AccessScope", List: validScopes})
+	}
+	return validate.Validate(vs...), nil
+}
+
+// BeforeSave defaults office users to seeing moves in their transportation office's GBLOC
+func (o *OfficeUser) BeforeSave(tx *pop.Connection) error {
+	if o.AccessScope == "" {
+		o.AccessScope = OfficeAccessScopeGBLOC
+	}
+	return nil
 }
 
 // ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
@@ -65,3 +89,94 @@ func FetchOfficeUserByEmail(tx *pop.Connection, email string) (*OfficeUser, erro
 	}
 	return &users[0], nil
 }
+
+// FetchOfficeUserByID fetches an office user and their transportation office by ID
+func FetchOfficeUserByID(tx *pop.Connection, id uuid.UUID) (*OfficeUser, error) {
+	var user OfficeUser
+	err := tx.Eager("TransportationOffice").Find(&user, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FetchOfficeUsers lists office users ordered by name, optionally only those in one transportation office
+func FetchOfficeUsers(tx *pop.Connection, transportationOfficeID *uuid.UUID) (OfficeUsers, error) {
+	var users OfficeUsers
+	query := tx.Eager("TransportationOffice")
+	if transportationOfficeID != nil {
+		query = query.Where("transportation_office_id = $1", *transportationOfficeID)
+	}
+	err := query.Order("last_name asc, first_name asc").All(&users)
+	return users, err
+}
+
+// FetchMoveOfficeUsers lists the active office users of the transportation office responsible for a
+// move: the service member's origin office, or their destination office if the origin isn't known
+func FetchMoveOfficeUsers(tx *pop.Connection, moveID uuid.UUID) (OfficeUsers, error) {
+	var users OfficeUsers
+	query := `SELECT * FROM office_users
+		WHERE deactivated = false
+		AND transportation_office_id = (SELECT COALESCE(origin_office.id, destination_office.id)` + officeMoveScopeFrom + `WHERE moves.id = ?)
+		ORDER BY last_name ASC, first_name ASC`
+	err := tx.RawQuery(query, moveID).All(&users)
+	return users, err
+}
+
+// InviteOfficeUser creates an office user who can log in to office.move.mil with their login.gov account.
+// If the email already belongs to a user the office user is linked to them.
+func InviteOfficeUser(db *pop.Connection, session *auth.Session, officeUser *OfficeUser) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	officeUser.Email = strings.ToLower(strings.TrimSpace(officeUser.Email))
+	if _, err := FetchOfficeUserByEmail(db, officeUser.Email); err == nil {
+		responseVErrors.Add("email", "An office user with this email already exists.")
+		return responseVErrors, nil
+	} else if err != ErrFetchNotFound {
+		return responseVErrors, err
+	}
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		var users Users
+		if err := db.Where("login_gov_email = $1", officeUser.Email).All(&users); err != nil {
+			responseError = errors.Wrap(err, "Error looking up user")
+			return transactionError
+		}
+		if len(users) > 0 {
+			officeUser.UserID = &users[0].ID
+		}
+
+		if verrs, err := db.ValidateAndCreate(officeUser); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error creating office user")
+			return transactionError
+		}
+
+		if verrs, err := RecordAudit(db, session, nil, officeUser, "invite", ""); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// SetOfficeUserDeactivated deactivates or reactivates an office user. Deactivated office users can't log in.
+func SetOfficeUserDeactivated(db *pop.Connection, session *auth.Session, officeUser *OfficeUser, deactivated bool) (*validate.Errors, error) {
+	before := *officeUser
+	officeUser.Deactivated = deactivated
+	event := "reactivate"
+	if deactivated {
+		event = "deactivate"
+	}
+	return SaveWithAudit(db, session, &before, officeUser, event, "")
+}
@@ -3,6 +3,7 @@ package models_test
 import (
 	"github.com/gofrs/uuid"
 	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
 )
 
 func (suite *ModelSuite) Test_OfficeUserInstantiation() {
@@ -17,6 +18,15 @@ func (suite *ModelSuite) Test_OfficeUserInstantiation() {
 	suite.verifyValidationErrors(user, expErrors)
 }
 
+func (suite *ModelSuite) TestOfficeUserAccessScopeValidation() {
+	user := testdatagen.MakeDefaultOfficeUser(suite.db)
+	user.AccessScope = OfficeAccessScope("EVERYWHERE")
+
+	verrs, err := suite.db.ValidateAndSave(&user)
+	suite.Nil(err)
+	suite.Equal([]string{"AccessScope is not in the list [GBLOC, TRANSPORTATION_OFFICE, NATIONAL]."}, verrs.Get("access_scope"))
+}
+
 func (suite *ModelSuite) Test_BasicOfficeUser() {
 	fakeUUID, _ := uuid.FromString("39b28c92-0506-4bef-8b57-e39519f42dc1")
 	userEmail := "sally@government.gov"
@@ -67,3 +77,89 @@ func (suite *ModelSuite) TestFetchOfficeUserByEmail() {
 	suite.NotNil(user)
 	suite.Equal(newUser.ID, user.ID)
 }
+
+func (suite *ModelSuite) TestInviteOfficeUser() {
+	user := testdatagen.MakeUser(suite.db, testdatagen.Assertions{
+		User: User{LoginGovEmail: "invitee@example.com"},
+	})
+	office := CreateTestShippingOffice(suite)
+
+	officeUser := OfficeUser{
+		LastName:               "Invitee",
+		FirstName:              "Ingrid",
+		Email:                  " Invitee@Example.com",
+		Telephone:              "907-555-1212",
+		TransportationOfficeID: office.ID,
+	}
+	verrs, err := InviteOfficeUser(suite.db, nil, &officeUser)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal("invitee@example.com", officeUser.Email)
+	suite.Equal(user.ID, *officeUser.UserID)
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{RecordType: "office_user", RecordID: &officeUser.ID})
+	suite.Nil(err)
+	suite.Len(records, 1)
+	suite.Equal("invite", records[0].Event)
+
+	// The same email can't be invited twice
+	duplicate := officeUser
+	duplicate.ID = uuid.Nil
+	verrs, err = InviteOfficeUser(suite.db, nil, &duplicate)
+	suite.Nil(err)
+	suite.NotEmpty(verrs.Get("email"))
+}
+
+func (suite *ModelSuite) TestSetOfficeUserDeactivated() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	session := officeSession(officeUser)
+
+	verrs, err := SetOfficeUserDeactivated(suite.db, nil, &officeUser, true)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	role, err := FetchAccessRole(suite.db, session)
+	suite.Nil(err)
+	suite.Equal(AccessRoleNone, role)
+
+	identity, err := FetchUserIdentity(suite.db, officeUser.User.LoginGovUUID.String())
+	suite.Nil(err)
+	suite.True(*identity.OfficeUserDeactivated)
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{RecordType: "office_user", RecordID: &officeUser.ID})
+	suite.Nil(err)
+	suite.Len(records, 1)
+	suite.Equal("deactivate", records[0].Event)
+	suite.Equal("ACTIVE", *records[0].FromStatus)
+	suite.Equal("DEACTIVATED", *records[0].ToStatus)
+
+	verrs, err = SetOfficeUserDeactivated(suite.db, nil, &officeUser, false)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	role, err = FetchAccessRole(suite.db, session)
+	suite.Nil(err)
+	suite.Equal(AccessRoleOfficeUser, role)
+}
+
+func (suite *ModelSuite) TestFetchMoveOfficeUsers() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	office := TransportationOffice{ID: *move.Orders.ServiceMember.DutyStation.TransportationOfficeID}
+	officeUser := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		OfficeUser: OfficeUser{TransportationOffice: office},
+	})
+	deactivated := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		OfficeUser: OfficeUser{TransportationOffice: office, Deactivated: true},
+	})
+	elsewhere := testdatagen.MakeDefaultOfficeUser(suite.db)
+
+	officeUsers, err := FetchMoveOfficeUsers(suite.db, move.ID)
+	suite.Nil(err)
+	ids := map[uuid.UUID]bool{}
+	for _, user := range officeUsers {
+		ids[user.ID] = true
+	}
+	suite.True(ids[officeUser.ID])
+	suite.False(ids[deactivated.ID])
+	suite.False(ids[elsewhere.ID])
+}
@@ -91,9 +91,30 @@ func (o *Order) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
 	return validate.NewErrors(), nil
 }
 
-// SaveOrder saves an order
-func SaveOrder(db *pop.Connection, order *Order) (*validate.Errors, error) {
-	return db.ValidateAndSave(order)
+// SaveOrder saves an order and audit records for the changes
+func SaveOrder(db *pop.Connection, order *Order, audits ...AuditEvent) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		if verrs, err := db.ValidateAndSave(order); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = err
+			return transactionError
+		}
+
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
 }
 
 // State Machine
@@ -170,8 +191,8 @@ func FetchOrderForUser(db *pop.Connection, session *auth.Session, id uuid.UUID)
 		return Order{}, err
 	}
 	// TODO: Handle case where more than one user is authorized to modify orders
-	if session.IsMyApp() && order.ServiceMember.ID != session.ServiceMemberID {
-		return Order{}, ErrFetchForbidden
+	if authErr := AuthorizeServiceMemberAccess(db, session, order.ServiceMemberID); authErr != nil {
+		return Order{}, authErr
 	}
 	return order, nil
 }
@@ -60,6 +60,11 @@ type PersonallyProcuredMove struct {
 	Advance                       *Reimbursement               `belongs_to:"reimbursements"`
 	AdvanceWorksheet              Document                     `belongs_to:"documents"`
 	AdvanceWorksheetID            *uuid.UUID                   `json:"advance_worksheet_id" db:"advance_worksheet_id"`
+	ActualMoveDate                *time.Time                   `json:"actual_move_date" db:"actual_move_date"`
+	NetWeight                     *int64                       `json:"net_weight" db:"net_weight"`
+	IncentiveActual               *unit.Cents                  `json:"incentive_actual" db:"incentive_actual"`
+	AdvanceDeduction              *unit.Cents                  `json:"advance_deduction" db:"advance_deduction"`
+	SettlementAmount              *unit.Cents                  `json:"settlement_amount" db:"settlement_amount"`
 }
 
 // PersonallyProcuredMoves is a list of PPMs
@@ -140,6 +145,28 @@ func (p *PersonallyProcuredMove) Cancel() error {
 	return nil
 }
 
+// SettleAdvance deducts the advance finance disbursed from the actual incentive. The settlement is
+// negative when more was advanced than earned and the difference must be recouped.
+func (p *PersonallyProcuredMove) SettleAdvance() {
+	if p.IncentiveActual == nil {
+		return
+	}
+
+	var deduction unit.Cents
+	if p.Advance != nil && p.Advance.Status == ReimbursementStatusPAID && p.Advance.PaidAmount != nil {
+		deduction = *p.Advance.PaidAmount
+	}
+	settlement := *p.IncentiveActual - deduction
+	p.AdvanceDeduction = &deduction
+	p.SettlementAmount = &settlement
+}
+
+// AdvanceIsPending returns true if the PPM's advance has been requested or approved but finance hasn't
+// disbursed it yet, so SettleAdvance can't deduct it
+func (p *PersonallyProcuredMove) AdvanceIsPending() bool {
+	return p.Advance != nil && (p.Advance.Status == ReimbursementStatusREQUESTED || p.Advance.Status == ReimbursementStatusAPPROVED)
+}
+
 // FetchMoveDocumentsForTypes returns all the linked move documents with the given document types
 func (p *PersonallyProcuredMove) FetchMoveDocumentsForTypes(db *pop.Connection, docTypes []string) (MoveDocuments, error) {
 	var moveDocs MoveDocuments
@@ -177,19 +204,19 @@ func FetchPersonallyProcuredMove(db *pop.Connection, session *auth.Session, id u
 		return nil, err
 	}
 	// TODO: Handle case where more than one user is authorized to modify ppm
-	if session.IsMyApp() && ppm.Move.Orders.ServiceMember.ID != session.ServiceMemberID {
-		return nil, ErrFetchForbidden
+	if authErr := AuthorizeMoveAccess(db, session, ppm.MoveID); authErr != nil {
+		return nil, authErr
 	}
 
 	return &ppm, nil
 }
 
-// SavePersonallyProcuredMove Safely saves a PPM and it's associated Advance.
-func SavePersonallyProcuredMove(db *pop.Connection, ppm *PersonallyProcuredMove) (*validate.Errors, error) {
+// SavePersonallyProcuredMove Safely saves a PPM, it's associated Advance and audit records for the changes.
+func SavePersonallyProcuredMove(db *pop.Connection, ppm *PersonallyProcuredMove, audits ...AuditEvent) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		if ppm.HasRequestedAdvance {
@@ -239,6 +266,12 @@ func SavePersonallyProcuredMove(db *pop.Connection, ppm *PersonallyProcuredMove)
 			return transactionError
 		}
 
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
 		return nil
 
 	})
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// PPMTrip is one load of a PPM that is moved separately, with its own postal codes, date and weight
+type PPMTrip struct {
+	ID                       uuid.UUID   `json:"id" db:"id"`
+	CreatedAt                time.Time   `json:"created_at" db:"created_at"`
+	UpdatedAt                time.Time   `json:"updated_at" db:"updated_at"`
+	PersonallyProcuredMoveID uuid.UUID   `json:"personally_procured_move_id" db:"personally_procured_move_id"`
+	TripNumber               int         `json:"trip_number" db:"trip_number"`
+	PickupPostalCode         string      `json:"pickup_postal_code" db:"pickup_postal_code"`
+	DestinationPostalCode    string      `json:"destination_postal_code" db:"destination_postal_code"`
+	MoveDate                 time.Time   `json:"move_date" db:"move_date"`
+	WeightEstimate           unit.Pound  `json:"weight_estimate" db:"weight_estimate"`
+	IncentiveEstimate        *unit.Cents `json:"incentive_estimate" db:"incentive_estimate"`
+}
+
+// PPMTrips is not required by pop and may be deleted
+type PPMTrips []PPMTrip
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (t *PPMTrip) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: t.PersonallyProcuredMoveID, Name: "PersonallyProcuredMoveID"},
+		&validators.IntIsGreaterThan{Field: t.TripNumber, Name: "TripNumber", Compared: 0},
+		&validators.StringIsPresent{Field: t.PickupPostalCode, Name: "PickupPostalCode"},
+		&validators.StringIsPresent{Field: t.DestinationPostalCode, Name: "DestinationPostalCode"},
+		&validators.TimeIsPresent{Field: t.MoveDate, Name: "MoveDate"},
+		&validators.IntIsGreaterThan{Field: t.WeightEstimate.Int(), Name: "WeightEstimate", Compared: 0},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (t *PPMTrip) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (t *PPMTrip) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchPPMTrips returns the trips of a PPM in trip order. It performs no authorization.
+func FetchPPMTrips(db *pop.Connection, ppmID uuid.UUID) (PPMTrips, error) {
+	var trips PPMTrips
+	err := db.Where("personally_procured_move_id = $1", ppmID).Order("trip_number asc").All(&trips)
+	return trips, err
+}
+
+// FetchPPMTrip fetches a PPM trip if the user may access its PPM
+func FetchPPMTrip(db *pop.Connection, session *auth.Session, id uuid.UUID) (*PPMTrip, error) {
+	var trip PPMTrip
+	err := db.Find(&trip, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if _, err := FetchPersonallyProcuredMove(db, session, trip.PersonallyProcuredMoveID); err != nil {
+		return nil, err
+	}
+	return &trip, nil
+}
+
+// PPMTripsAreEditable returns true if trips can still be added to, changed on or removed from the PPM.
+// Once payment has been requested the trips are what the office reviews.
+func (p PersonallyProcuredMove) PPMTripsAreEditable() bool {
+	return p.Status == PPMStatusDRAFT || p.Status == PPMStatusSUBMITTED || p.Status == PPMStatusAPPROVED
+}
+
+// SavePPMTrips saves a PPM along with the trips whose estimates were recomputed and removes a
+// deleted trip, if given, in a single transaction. Weight tickets follow their trips when trips are
+// renumbered.
+func SavePPMTrips(db *pop.Connection, ppm *PersonallyProcuredMove, trips PPMTrips, deleted *PPMTrip) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	if !ppm.PPMTripsAreEditable() {
+		return responseVErrors, errors.Wrapf(ErrInvalidTransition, "PPM trips can't change once the PPM is %s", ppm.Status)
+	}
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		if deleted != nil {
+			if err := db.Destroy(deleted); err != nil {
+				responseError = errors.Wrap(err, "Error Deleting PPM Trip")
+				return transactionError
+			}
+		}
+
+		for i := range trips {
+			if verrs, err := db.ValidateAndSave(&trips[i]); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error Saving PPM Trip")
+				return transactionError
+			}
+		}
+
+		err := db.RawQuery(`UPDATE weight_ticket_documents SET trip_number = ppm_trips.trip_number
+			FROM ppm_trips WHERE ppm_trips.id = weight_ticket_documents.ppm_trip_id
+			AND ppm_trips.personally_procured_move_id = ?`, ppm.ID).Exec()
+		if err != nil {
+			responseError = errors.Wrap(err, "Error Renumbering Weight Tickets")
+			return transactionError
+		}
+
+		if verrs, err := db.ValidateAndSave(ppm); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Saving PPM")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// FetchPPMTripNetWeights totals the net weights of a PPM's weight tickets that haven't been rejected
+// by the trip they are linked to. It performs no authorization.
+func FetchPPMTripNetWeights(db *pop.Connection, ppmID uuid.UUID) (map[uuid.UUID]unit.Pound, error) {
+	var weightTickets WeightTicketDocuments
+	err := db.Q().
+		Join("ppm_trips", "ppm_trips.id = weight_ticket_documents.ppm_trip_id").
+		Join("move_documents", "move_documents.id = weight_ticket_documents.move_document_id").
+		Where("ppm_trips.personally_procured_move_id = $1", ppmID).
+		Where("move_documents.status != $2", MoveDocumentStatusHASISSUE).
+		All(&weightTickets)
+	if err != nil {
+		return nil, err
+	}
+	netWeights := map[uuid.UUID]unit.Pound{}
+	for _, weightTicket := range weightTickets {
+		netWeights[*weightTicket.PPMTripID] += weightTicket.NetWeight()
+	}
+	return netWeights, nil
+}
@@ -0,0 +1,20 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+)
+
+func (suite *ModelSuite) TestPPMTripValidations() {
+	trip := &PPMTrip{}
+
+	expErrors := map[string][]string{
+		"personally_procured_move_id": {"PersonallyProcuredMoveID can not be blank."},
+		"trip_number":                 {"0 is not greater than 0."},
+		"pickup_postal_code":          {"PickupPostalCode can not be blank."},
+		"destination_postal_code":     {"DestinationPostalCode can not be blank."},
+		"move_date":                   {"MoveDate can not be blank."},
+		"weight_estimate":             {"0 is not greater than 0."},
+	}
+
+	suite.verifyValidationErrors(trip, expErrors)
+}
@@ -0,0 +1,305 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// PremoveSurveySlotStatus is the status of a time a TSP offered for the pre-move survey
+type PremoveSurveySlotStatus string
+
+const (
+	// PremoveSurveySlotStatusPROPOSED captures enum value "PROPOSED"
+	PremoveSurveySlotStatusPROPOSED PremoveSurveySlotStatus = "PROPOSED"
+	// PremoveSurveySlotStatusCONFIRMED captures enum value "CONFIRMED", when the service member
+	// chose the slot
+	PremoveSurveySlotStatusCONFIRMED PremoveSurveySlotStatus = "CONFIRMED"
+	// PremoveSurveySlotStatusDECLINED captures enum value "DECLINED", when the service member chose
+	// another slot
+	PremoveSurveySlotStatusDECLINED PremoveSurveySlotStatus = "DECLINED"
+	// PremoveSurveySlotStatusWITHDRAWN captures enum value "WITHDRAWN", when the TSP proposed new
+	// slots or the survey was rescheduled
+	PremoveSurveySlotStatusWITHDRAWN PremoveSurveySlotStatus = "WITHDRAWN"
+)
+
+// PremoveSurveySlot is a time the TSP can conduct the pre-move survey at the service member's
+// residence. The TSP proposes several and the service member confirms one of them.
+type PremoveSurveySlot struct {
+	ID                              uuid.UUID               `json:"id" db:"id"`
+	CreatedAt                       time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time               `json:"updated_at" db:"updated_at"`
+	ShipmentID                      uuid.UUID               `json:"shipment_id" db:"shipment_id"`
+	TransportationServiceProviderID uuid.UUID               `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	StartsAt                        time.Time               `json:"starts_at" db:"starts_at"`
+	EndsAt                          time.Time               `json:"ends_at" db:"ends_at"`
+	Status                          PremoveSurveySlotStatus `json:"status" db:"status"`
+	ConfirmedAt                     *time.Time              `json:"confirmed_at" db:"confirmed_at"`
+}
+
+// PremoveSurveySlots is not required by pop and may be deleted
+type PremoveSurveySlots []PremoveSurveySlot
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *PremoveSurveySlot) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validStatuses := []string{
+		string(PremoveSurveySlotStatusPROPOSED),
+		string(PremoveSurveySlotStatusCONFIRMED),
+		string(PremoveSurveySlotStatusDECLINED),
+		string(PremoveSurveySlotStatusWITHDRAWN),
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.ShipmentID, Name: "ShipmentID"},
+		&validators.UUIDIsPresent{Field: s.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.TimeIsPresent{Field: s.StartsAt, Name: "StartsAt"},
+		&validators.TimeAfterTime{FirstTime: s.EndsAt, FirstName: "EndsAt", SecondTime: s.StartsAt, SecondName: "StartsAt"},
+		&validators.StringInclusion{Field: string(s.Status), Name: "Status", List: validStatuses},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *PremoveSurveySlot) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *PremoveSurveySlot) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// PremoveSurveyItem is an item the TSP found in a room at the pre-move survey, with the estimated
+// cube and weight of each of them
+type PremoveSurveyItem struct {
+	ID             uuid.UUID  `json:"id" db:"id"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	ShipmentID     uuid.UUID  `json:"shipment_id" db:"shipment_id"`
+	Room           string     `json:"room" db:"room"`
+	Description    string     `json:"description" db:"description"`
+	Quantity       int        `json:"quantity" db:"quantity"`
+	CubeFeet       float64    `json:"cube_feet" db:"cube_feet"`
+	WeightEstimate unit.Pound `json:"weight_estimate" db:"weight_estimate"`
+	Notes          *string    `json:"notes" db:"notes"`
+}
+
+// PremoveSurveyItems is not required by pop and may be deleted
+type PremoveSurveyItems []PremoveSurveyItem
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *PremoveSurveyItem) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.UUIDIsPresent{Field: i.ShipmentID, Name: "ShipmentID"},
+		&validators.StringIsPresent{Field: i.Room, Name: "Room"},
+		&validators.StringIsPresent{Field: i.Description, Name: "Description"},
+		&validators.IntIsGreaterThan{Field: i.Quantity, Name: "Quantity", Compared: 0},
+		&validators.IntIsGreaterThan{Field: i.WeightEstimate.Int(), Name: "WeightEstimate", Compared: -1},
+	)
+	if i.CubeFeet < 0 {
+		verrs.Add("cube_feet", "CubeFeet can not be negative.")
+	}
+	return verrs, nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (i *PremoveSurveyItem) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (i *PremoveSurveyItem) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// TotalWeightEstimate is the estimated weight of every item in the inventory
+func (items PremoveSurveyItems) TotalWeightEstimate() unit.Pound {
+	var total unit.Pound
+	for _, item := range items {
+		total += item.WeightEstimate * unit.Pound(item.Quantity)
+	}
+	return total
+}
+
+// TotalCubeFeet is the estimated cube of every item in the inventory
+func (items PremoveSurveyItems) TotalCubeFeet() float64 {
+	var total float64
+	for _, item := range items {
+		total += item.CubeFeet * float64(item.Quantity)
+	}
+	return total
+}
+
+// RollUpPremoveSurveyItems sets the shipment's pre-move survey weight estimate to the total of its
+// inventory. The estimate is kept when there is no inventory, so that estimates entered without one
+// aren't lost.
+func (s *Shipment) RollUpPremoveSurveyItems(items PremoveSurveyItems) {
+	if len(items) == 0 {
+		return
+	}
+	total := items.TotalWeightEstimate()
+	s.PmSurveyWeightEstimate = &total
+}
+
+// PremoveSurveyIsOpen returns whether the pre-move survey can be scheduled and recorded: the
+// shipment was accepted by a TSP and hasn't been picked up
+func (s *Shipment) PremoveSurveyIsOpen() bool {
+	return s.Status == ShipmentStatusACCEPTED || s.Status == ShipmentStatusAPPROVED
+}
+
+// ProposePremoveSurveySlots offers the service member times for the pre-move survey. Slots the TSP
+// proposed before and the service member didn't confirm are withdrawn.
+func ProposePremoveSurveySlots(db *pop.Connection, shipment Shipment, tspID uuid.UUID, slots PremoveSurveySlots, now time.Time) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	if !shipment.PremoveSurveyIsOpen() {
+		return responseVErrors, errors.Wrap(ErrInvalidTransition, "ProposePremoveSurveySlots on a shipment that isn't awaiting pickup")
+	}
+	if len(slots) == 0 {
+		responseVErrors.Add("slots", "At least one time must be proposed.")
+		return responseVErrors, nil
+	}
+	for _, slot := range slots {
+		if !slot.StartsAt.After(now) {
+			responseVErrors.Add("starts_at", "Proposed times must be in the future.")
+			return responseVErrors, nil
+		}
+	}
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		err := db.RawQuery("UPDATE premove_survey_slots SET status = $1, updated_at = $2 WHERE shipment_id = $3 AND status = $4",
+			PremoveSurveySlotStatusWITHDRAWN, now, shipment.ID, PremoveSurveySlotStatusPROPOSED).Exec()
+		if err != nil {
+			responseError = errors.Wrap(err, "Error Withdrawing Premove Survey Slots")
+			return transactionError
+		}
+
+		for i := range slots {
+			slots[i].ShipmentID = shipment.ID
+			slots[i].TransportationServiceProviderID = tspID
+			slots[i].Status = PremoveSurveySlotStatusPROPOSED
+			if verrs, err := db.ValidateAndCreate(&slots[i]); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error Creating Premove Survey Slot")
+				return transactionError
+			}
+		}
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// ConfirmPremoveSurveySlot books the pre-move survey at a slot the TSP proposed and sets the
+// shipment's survey date to it. The other proposed slots are declined, and a survey confirmed
+// before is withdrawn.
+func ConfirmPremoveSurveySlot(db *pop.Connection, shipment *Shipment, slot *PremoveSurveySlot, now time.Time) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	if slot.ShipmentID != shipment.ID {
+		return responseVErrors, ErrFetchNotFound
+	}
+	if slot.Status != PremoveSurveySlotStatusPROPOSED || !slot.StartsAt.After(now) || !shipment.PremoveSurveyIsOpen() {
+		return responseVErrors, errors.Wrap(ErrInvalidTransition, "ConfirmPremoveSurveySlot")
+	}
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		err := db.RawQuery("UPDATE premove_survey_slots SET status = $1, updated_at = $2 WHERE shipment_id = $3 AND status = $4 AND id <> $5",
+			PremoveSurveySlotStatusDECLINED, now, shipment.ID, PremoveSurveySlotStatusPROPOSED, slot.ID).Exec()
+		if err == nil {
+			err = db.RawQuery("UPDATE premove_survey_slots SET status = $1, updated_at = $2 WHERE shipment_id = $3 AND status = $4",
+				PremoveSurveySlotStatusWITHDRAWN, now, shipment.ID, PremoveSurveySlotStatusCONFIRMED).Exec()
+		}
+		if err != nil {
+			responseError = errors.Wrap(err, "Error Updating Premove Survey Slots")
+			return transactionError
+		}
+
+		slot.Status = PremoveSurveySlotStatusCONFIRMED
+		slot.ConfirmedAt = &now
+		if verrs, err := db.ValidateAndUpdate(slot); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Confirming Premove Survey Slot")
+			return transactionError
+		}
+
+		surveyDate := slot.StartsAt
+		shipment.PmSurveyConductedDate = &surveyDate
+		if verrs, err := db.ValidateAndUpdate(shipment); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Saving Shipment")
+			return transactionError
+		}
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// FetchPremoveSurveySlots returns the slots proposed for a shipment's pre-move survey, earliest
+// first. It performs no authorization.
+func FetchPremoveSurveySlots(db *pop.Connection, shipmentID uuid.UUID) (PremoveSurveySlots, error) {
+	var slots PremoveSurveySlots
+	err := db.Where("shipment_id = $1", shipmentID).Order("starts_at").All(&slots)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching premove survey slots")
+	}
+	return slots, nil
+}
+
+// FetchPremoveSurveySlot returns a slot. It performs no authorization; callers check access to the
+// slot's shipment.
+func FetchPremoveSurveySlot(db *pop.Connection, id uuid.UUID) (*PremoveSurveySlot, error) {
+	var slot PremoveSurveySlot
+	err := db.Find(&slot, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &slot, nil
+}
+
+// FetchPremoveSurveyItems returns the inventory taken at a shipment's pre-move survey, room by room.
+// It performs no authorization.
+func FetchPremoveSurveyItems(db *pop.Connection, shipmentID uuid.UUID) (PremoveSurveyItems, error) {
+	var items PremoveSurveyItems
+	err := db.Where("shipment_id = $1", shipmentID).Order("room, created_at").All(&items)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching premove survey items")
+	}
+	return items, nil
+}
+
+// FetchPremoveSurveyItem returns an inventory item of a shipment
+func FetchPremoveSurveyItem(db *pop.Connection, shipmentID uuid.UUID, id uuid.UUID) (*PremoveSurveyItem, error) {
+	var item PremoveSurveyItem
+	err := db.Find(&item, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if item.ShipmentID != shipmentID {
+		return nil, ErrFetchNotFound
+	}
+	return &item, nil
+}
@@ -0,0 +1,101 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ModelSuite) TestPremoveSurveyItemValidations() {
+	item := &PremoveSurveyItem{CubeFeet: -1, WeightEstimate: unit.Pound(-5)}
+
+	expErrors := map[string][]string{
+		"shipment_id":     {"ShipmentID can not be blank."},
+		"room":            {"Room can not be blank."},
+		"description":     {"Description can not be blank."},
+		"quantity":        {"0 is not greater than 0."},
+		"weight_estimate": {"-5 is not greater than -1."},
+		"cube_feet":       {"CubeFeet can not be negative."},
+	}
+
+	suite.verifyValidationErrors(item, expErrors)
+}
+
+func (suite *ModelSuite) TestPremoveSurveyItemsRollUp() {
+	shipment := Shipment{}
+	shipment.RollUpPremoveSurveyItems(PremoveSurveyItems{})
+	suite.Nil(shipment.PmSurveyWeightEstimate)
+
+	items := PremoveSurveyItems{
+		{Room: "Living room", Description: "Sofa", Quantity: 1, CubeFeet: 35, WeightEstimate: unit.Pound(245)},
+		{Room: "Kitchen", Description: "Dining chair", Quantity: 4, CubeFeet: 5, WeightEstimate: unit.Pound(35)},
+	}
+	suite.Equal(unit.Pound(385), items.TotalWeightEstimate())
+	suite.Equal(55.0, items.TotalCubeFeet())
+
+	shipment.RollUpPremoveSurveyItems(items)
+	suite.Equal(unit.Pound(385), *shipment.PmSurveyWeightEstimate)
+}
+
+func (suite *ModelSuite) TestConfirmPremoveSurveySlot() {
+	now := time.Now()
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{Status: ShipmentStatusACCEPTED},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+
+	slots := PremoveSurveySlots{
+		{StartsAt: now.AddDate(0, 0, 2), EndsAt: now.AddDate(0, 0, 2).Add(2 * time.Hour)},
+		{StartsAt: now.AddDate(0, 0, 3), EndsAt: now.AddDate(0, 0, 3).Add(2 * time.Hour)},
+	}
+	verrs, err := ProposePremoveSurveySlots(suite.db, shipment, tsp.ID, slots, now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	verrs, err = ConfirmPremoveSurveySlot(suite.db, &shipment, &slots[1], now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.True(shipment.PmSurveyConductedDate.Equal(slots[1].StartsAt))
+
+	// A slot can only be confirmed once, and the others were declined
+	_, err = ConfirmPremoveSurveySlot(suite.db, &shipment, &slots[1], now)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	fetched, err := FetchPremoveSurveySlots(suite.db, shipment.ID)
+	suite.Nil(err)
+	suite.Equal(PremoveSurveySlotStatusDECLINED, fetched[0].Status)
+	suite.Equal(PremoveSurveySlotStatusCONFIRMED, fetched[1].Status)
+
+	// Rescheduling withdraws the confirmed slot once a new one is confirmed
+	rescheduled := PremoveSurveySlots{
+		{StartsAt: now.AddDate(0, 0, 4), EndsAt: now.AddDate(0, 0, 4).Add(2 * time.Hour)},
+	}
+	_, err = ProposePremoveSurveySlots(suite.db, shipment, tsp.ID, rescheduled, now)
+	suite.Nil(err)
+	_, err = ConfirmPremoveSurveySlot(suite.db, &shipment, &rescheduled[0], now)
+	suite.Nil(err)
+	fetched, err = FetchPremoveSurveySlots(suite.db, shipment.ID)
+	suite.Nil(err)
+	suite.Equal(PremoveSurveySlotStatusWITHDRAWN, fetched[1].Status)
+	suite.Equal(PremoveSurveySlotStatusCONFIRMED, fetched[2].Status)
+}
+
+func (suite *ModelSuite) TestProposePremoveSurveySlotsInThePast() {
+	now := time.Now()
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{Status: ShipmentStatusACCEPTED},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+
+	slots := PremoveSurveySlots{{StartsAt: now.Add(-time.Hour), EndsAt: now}}
+	verrs, err := ProposePremoveSurveySlots(suite.db, shipment, tsp.ID, slots, now)
+	suite.Nil(err)
+	suite.True(verrs.HasAny())
+
+	shipment.Status = ShipmentStatusINTRANSIT
+	_, err = ProposePremoveSurveySlots(suite.db, shipment, tsp.ID, PremoveSurveySlots{}, now)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+}
@@ -1,10 +1,18 @@
 package models
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/gobuffalo/pop"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/dates"
 	"github.com/transcom/mymove/pkg/gen/internalmessages"
 )
 
@@ -14,6 +22,7 @@ type MoveQueueItem struct {
 	CreatedAt        time.Time                           `json:"created_at" db:"created_at"`
 	Edipi            string                              `json:"edipi" db:"edipi"`
 	Rank             *internalmessages.ServiceMemberRank `json:"rank" db:"rank"`
+	Branch           *string                             `json:"branch" db:"branch"`
 	CustomerName     string                              `json:"customer_name" db:"customer_name"`
 	Locator          string                              `json:"locator" db:"locator"`
 	Status           string                              `json:"status" db:"status"`
@@ -24,152 +33,499 @@ type MoveQueueItem struct {
 	CustomerDeadline time.Time                           `json:"customer_deadline" db:"customer_deadline"`
 	LastModifiedDate time.Time                           `json:"last_modified_date" db:"last_modified_date"`
 	LastModifiedName string                              `json:"last_modified_name" db:"last_modified_name"`
+	AssigneeID       *uuid.UUID                          `json:"assignee_id" db:"assignee_id"`
+	AssigneeName     *string                             `json:"assignee_name" db:"assignee_name"`
+	// RowID is the PPM or shipment that put the move in the queue. A move is listed once for each of them.
+	RowID uuid.UUID `json:"row_id" db:"row_id"`
+}
+
+// MoveQueueFilters narrows down the moves in a queue. Empty fields don't filter.
+type MoveQueueFilters struct {
+	GBLOC        string     `json:"gbloc,omitempty"`
+	Branches     []string   `json:"branches,omitempty"`
+	Ranks        []string   `json:"ranks,omitempty"`
+	OrdersTypes  []string   `json:"orders_types,omitempty"`
+	MoveDateFrom *time.Time `json:"move_date_from,omitempty"`
+	MoveDateTo   *time.Time `json:"move_date_to,omitempty"`
+	PpmStatuses  []string   `json:"ppm_statuses,omitempty"`
+	HhgStatuses  []string   `json:"hhg_statuses,omitempty"`
+	// Assignee is an office user ID, MoveQueueAssignedToMe or MoveQueueUnassigned
+	Assignee string `json:"assignee,omitempty"`
+}
+
+const (
+	// MoveQueueAssignedToMe filters a queue to moves claimed by the office user searching it
+	MoveQueueAssignedToMe = "me"
+	// MoveQueueUnassigned filters a queue to moves without an active work claim
+	MoveQueueUnassigned = "unassigned"
+)
+
+func (f MoveQueueFilters) hasValidAssignee() bool {
+	switch f.Assignee {
+	case "", MoveQueueAssignedToMe, MoveQueueUnassigned:
+		return true
+	}
+	_, err := uuid.FromString(f.Assignee)
+	return err == nil
+}
+
+// MoveQueueSort orders a queue by one of its columns
+type MoveQueueSort struct {
+	Column     string `json:"column"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// ParseMoveQueueSort parses sorts of the form "column" or "-column" for descending order
+func ParseMoveQueueSort(sorts []string) ([]MoveQueueSort, error) {
+	parsed := []MoveQueueSort{}
+	for _, sort := range sorts {
+		s := MoveQueueSort{Column: sort}
+		if strings.HasPrefix(sort, "-") {
+			s = MoveQueueSort{Column: strings.TrimPrefix(sort, "-"), Descending: true}
+		}
+		if _, ok := moveQueueSortColumns[s.Column]; !ok {
+			return nil, errors.Wrapf(ErrInvalidQueueParams, "unknown sort column %s", s.Column)
+		}
+		parsed = append(parsed, s)
+	}
+	return parsed, nil
+}
+
+// String formats the sort the way ParseMoveQueueSort parses it
+func (s MoveQueueSort) String() string {
+	if s.Descending {
+		return "-" + s.Column
+	}
+	return s.Column
+}
+
+// MoveQueueParams selects a page of a move queue
+type MoveQueueParams struct {
+	LifecycleState string
+	Filters        MoveQueueFilters
+	Sort           []MoveQueueSort
+	// Limit is the page size, or 0 to return every matching move
+	Limit int
+	// Cursor is the NextCursor of the previous page, or empty for the first page
+	Cursor string
+}
+
+// MoveQueuePage is a page of a move queue. NextCursor is nil on the last page.
+type MoveQueuePage struct {
+	Items      []MoveQueueItem
+	NextCursor *string
+}
+
+// The last person to modify a move is the actor on its most recent audit record
+const lastModifiedNameColumn = `COALESCE((
+	SELECT audit_records.actor_name FROM audit_records
+	WHERE audit_records.move_id = queue_items.id
+	ORDER BY audit_records.created_at DESC
+	LIMIT 1
+), '') AS last_modified_name`
+
+// A move is assigned to whoever holds the active claim on it, or failing that on one of its PPMs
+const assigneeColumns = `assignee.office_user_id AS assignee_id,
+	CASE WHEN assignee.office_user_id IS NULL THEN NULL
+		ELSE CONCAT(assignee_user.last_name, ', ', assignee_user.first_name) END AS assignee_name`
+
+const assigneeJoin = `
+	LEFT JOIN LATERAL (
+		SELECT work_claims.office_user_id FROM work_claims
+		WHERE (
+			(work_claims.record_type = 'move' AND work_claims.record_id = queue_items.id)
+			OR (work_claims.record_type = 'personally_procured_move' AND work_claims.record_id IN (
+				SELECT personally_procured_moves.id FROM personally_procured_moves
+				WHERE personally_procured_moves.move_id = queue_items.id
+			))
+		)
+		AND (work_claims.expires_at IS NULL OR work_claims.expires_at > ?)
+		ORDER BY work_claims.record_type = 'move' DESC, work_claims.created_at ASC
+		LIMIT 1
+	) AS assignee ON TRUE
+	LEFT JOIN office_users AS assignee_user ON assignee_user.id = assignee.office_user_id
+`
+
+const moveQueueSelect = `
+	SELECT moves.ID,
+		COALESCE(sm.edipi, '*missing*') as edipi,
+		COALESCE(sm.rank, '*missing*') as rank,
+		sm.affiliation as branch,
+		CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
+		moves.locator as locator,
+		ord.orders_type as orders_type,
+		%s as move_date,
+		moves.created_at as created_at,
+		moves.updated_at as last_modified_date,
+		moves.status as status,
+		%s,
+		%s as row_id
+	FROM moves
+	JOIN orders as ord ON moves.orders_id = ord.id
+	JOIN service_members AS sm ON ord.service_member_id = sm.id
+	%s
+	WHERE %s
+`
+
+// moveQueue describes which moves are in a queue, which of their dates is the move date and which of their
+// PPMs or shipments each row is for
+type moveQueue struct {
+	moveDate  string
+	status    string
+	rowID     string
+	join      string
+	condition string
+}
+
+var ppmQueueStatus = "ppm.status as ppm_status, NULL as hhg_status"
+var hhgQueueStatus = "NULL as ppm_status, shipment.status as hhg_status"
+
+// Moves without a PPM or shipment are still listed once in the queues that left join them
+var ppmQueueRowID = "COALESCE(ppm.id, moves.id)"
+var hhgQueueRowID = "COALESCE(shipment.id, moves.id)"
+
+// Move date is the Requested Pickup Date for accepted shipments because they haven't yet gone through the
+// premove survey to set the actual Pickup Date. Later HHG queues use the Actual Pickup Date.
+var moveQueues = map[string]moveQueue{
+	"new": {"ppm.planned_move_date", ppmQueueStatus, ppmQueueRowID,
+		"LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id", "moves.status = 'SUBMITTED'"},
+	"ppm": {"ppm.planned_move_date", ppmQueueStatus, ppmQueueRowID,
+		"JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id", "moves.status = 'APPROVED'"},
+	"hhg_accepted": {"shipment.requested_pickup_date", hhgQueueStatus, hhgQueueRowID,
+		"LEFT JOIN shipments as shipment ON moves.id = shipment.move_id", "shipment.status = 'ACCEPTED'"},
+	"hhg_in_transit": {"shipment.actual_pickup_date", hhgQueueStatus, hhgQueueRowID,
+		"LEFT JOIN shipments as shipment ON moves.id = shipment.move_id", "shipment.status = 'IN_TRANSIT'"},
+	"hhg_delivered": {"shipment.actual_pickup_date", hhgQueueStatus, hhgQueueRowID,
+		"LEFT JOIN shipments as shipment ON moves.id = shipment.move_id", "shipment.status = 'DELIVERED'"},
+	"hhg_completed": {"shipment.actual_pickup_date", hhgQueueStatus, hhgQueueRowID,
+		"LEFT JOIN shipments as shipment ON moves.id = shipment.move_id", "shipment.status = 'COMPLETED'"},
+	"all": {"ppm.planned_move_date", ppmQueueStatus, ppmQueueRowID,
+		"LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id", "TRUE"},
+}
+
+// MoveQueueTypes are the names of the move queues
+var MoveQueueTypes = []string{"new", "ppm", "hhg_accepted", "hhg_in_transit", "hhg_delivered", "hhg_completed", "all"}
+
+// moveQueueSortColumns are the columns a queue can be sorted by. Nullable columns are coalesced so that
+// cursors can compare against them.
+var moveQueueSortColumns = map[string]string{
+	"customer_name":      "queue_items.customer_name",
+	"edipi":              "queue_items.edipi",
+	"rank":               "queue_items.rank",
+	"branch":             "COALESCE(queue_items.branch, '')",
+	"locator":            "queue_items.locator",
+	"orders_type":        "queue_items.orders_type",
+	"status":             "queue_items.status",
+	"ppm_status":         "COALESCE(queue_items.ppm_status, '')",
+	"hhg_status":         "COALESCE(queue_items.hhg_status, '')",
+	"move_date":          "COALESCE(queue_items.move_date, '0001-01-01')",
+	"created_at":         "queue_items.created_at",
+	"last_modified_date": "queue_items.last_modified_date",
+	"last_modified_name": "queue_items.last_modified_name",
+	"assignee_name":      "COALESCE(queue_items.assignee_name, '')",
+}
+
+var defaultMoveQueueSort = []MoveQueueSort{{Column: "created_at"}}
+
+// sortValue returns the item's value for a sort column, formatted so Postgres compares it like the column
+func (item MoveQueueItem) sortValue(column string) string {
+	stringOrEmpty := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	switch column {
+	case "customer_name":
+		return item.CustomerName
+	case "edipi":
+		return item.Edipi
+	case "rank":
+		if item.Rank == nil {
+			return ""
+		}
+		return string(*item.Rank)
+	case "branch":
+		return stringOrEmpty(item.Branch)
+	case "locator":
+		return item.Locator
+	case "orders_type":
+		return item.OrdersType
+	case "status":
+		return item.Status
+	case "ppm_status":
+		return stringOrEmpty(item.PpmStatus)
+	case "hhg_status":
+		return stringOrEmpty(item.HhgStatus)
+	case "move_date":
+		if item.MoveDate == nil {
+			return "0001-01-01"
+		}
+		return item.MoveDate.Format("2006-01-02")
+	case "created_at":
+		return item.CreatedAt.Format(time.RFC3339Nano)
+	case "last_modified_date":
+		return item.LastModifiedDate.Format(time.RFC3339Nano)
+	case "last_modified_name":
+		return item.LastModifiedName
+	case "assignee_name":
+		return stringOrEmpty(item.AssigneeName)
+	}
+	return ""
+}
+
+// moveQueueCursor marks where the previous page ended. Sort is kept so a cursor can't be reused with a
+// different sort order.
+type moveQueueCursor struct {
+	Sort   string    `json:"sort"`
+	Values []string  `json:"values"`
+	ID     uuid.UUID `json:"id"`
+	RowID  uuid.UUID `json:"row_id"`
+}
+
+func sortSignature(sorts []MoveQueueSort) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		parts[i] = sort.String()
+	}
+	return strings.Join(parts, ",")
+}
+
+func encodeMoveQueueCursor(sorts []MoveQueueSort, item MoveQueueItem) string {
+	cursor := moveQueueCursor{Sort: sortSignature(sorts), ID: item.ID, RowID: item.RowID}
+	for _, sort := range sorts {
+		cursor.Values = append(cursor.Values, item.sortValue(sort.Column))
+	}
+	encoded, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+func decodeMoveQueueCursor(sorts []MoveQueueSort, encoded string) (moveQueueCursor, error) {
+	var cursor moveQueueCursor
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return cursor, errors.Wrap(ErrInvalidQueueParams, "malformed cursor")
+	}
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		return cursor, errors.Wrap(ErrInvalidQueueParams, "malformed cursor")
+	}
+	if cursor.Sort != sortSignature(sorts) || len(cursor.Values) != len(sorts) {
+		return cursor, errors.Wrap(ErrInvalidQueueParams, "cursor is for a different sort")
+	}
+	return cursor, nil
+}
+
+// moveQueueQuery accumulates the conditions and args of a queue query
+type moveQueueQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *moveQueueQuery) where(condition string, args ...interface{}) {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+}
+
+// whereIn adds a condition that column is one of values, unless values is empty
+func (q *moveQueueQuery) whereIn(column string, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = "?"
+		q.args = append(q.args, value)
+	}
+	q.conditions = append(q.conditions, column+" IN ("+strings.Join(placeholders, ", ")+")")
+}
+
+func (q *moveQueueQuery) addFilters(filters MoveQueueFilters) error {
+	if filters.GBLOC != "" {
+		gbloc := filters.GBLOC
+		q.where("queue_items.id IN (SELECT moves.id"+officeMoveScopeFrom+"WHERE "+officeMoveScopeGBLOCCondition+")",
+			gbloc, gbloc, gbloc, gbloc)
+	}
+	q.whereIn("queue_items.branch", filters.Branches)
+	q.whereIn("queue_items.rank", filters.Ranks)
+	q.whereIn("queue_items.orders_type", filters.OrdersTypes)
+	if filters.MoveDateFrom != nil {
+		q.where("queue_items.move_date >= ?", filters.MoveDateFrom.Format("2006-01-02"))
+	}
+	if filters.MoveDateTo != nil {
+		q.where("queue_items.move_date <= ?", filters.MoveDateTo.Format("2006-01-02"))
+	}
+	// Statuses are matched against the move's PPMs and shipments rather than the queue columns, which
+	// only hold the status relevant to the queue
+	if len(filters.PpmStatuses) > 0 {
+		statuses := moveQueueQuery{}
+		statuses.whereIn("ppm_filter.status", filters.PpmStatuses)
+		q.where("EXISTS (SELECT 1 FROM personally_procured_moves AS ppm_filter WHERE ppm_filter.move_id = queue_items.id AND "+statuses.conditions[0]+")", statuses.args...)
+	}
+	if len(filters.HhgStatuses) > 0 {
+		statuses := moveQueueQuery{}
+		statuses.whereIn("shipment_filter.status", filters.HhgStatuses)
+		q.where("EXISTS (SELECT 1 FROM shipments AS shipment_filter WHERE shipment_filter.move_id = queue_items.id AND "+statuses.conditions[0]+")", statuses.args...)
+	}
+	if !filters.hasValidAssignee() {
+		return errors.Wrapf(ErrInvalidQueueParams, "unknown assignee %s", filters.Assignee)
+	}
+	switch filters.Assignee {
+	case "":
+	case MoveQueueUnassigned:
+		q.where("queue_items.assignee_id IS NULL")
+	default:
+		q.where("queue_items.assignee_id = ?", uuid.FromStringOrNil(filters.Assignee))
+	}
+	return nil
+}
+
+// addCursor limits the query to rows after the cursor in sort order
+func (q *moveQueueQuery) addCursor(sorts []MoveQueueSort, cursor moveQueueCursor) {
+	keys := []string{}
+	values := []interface{}{}
+	ops := []string{}
+	for i, sort := range sorts {
+		keys = append(keys, moveQueueSortColumns[sort.Column])
+		values = append(values, cursor.Values[i])
+		op := ">"
+		if sort.Descending {
+			op = "<"
+		}
+		ops = append(ops, op)
+	}
+	// A move can be listed more than once, so its PPM or shipment breaks the tie
+	keys = append(keys, "queue_items.id", "queue_items.row_id")
+	values = append(values, cursor.ID, cursor.RowID)
+	ops = append(ops, ">", ">")
+
+	// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+	alternatives := []string{}
+	args := []interface{}{}
+	for i := range keys {
+		terms := []string{}
+		for j := 0; j < i; j++ {
+			terms = append(terms, keys[j]+" = ?")
+			args = append(args, values[j])
+		}
+		terms = append(terms, keys[i]+" "+ops[i]+" ?")
+		args = append(args, values[i])
+		alternatives = append(alternatives, "("+strings.Join(terms, " AND ")+")")
+	}
+	q.where("("+strings.Join(alternatives, " OR ")+")", args...)
+}
+
+// SearchMoveQueue returns a page of the moves in a queue that the office user may access, filtered and sorted
+// by params. Customer deadlines are counted on the office calendars in calendars.
+func SearchMoveQueue(db *pop.Connection, calendars *dates.CalendarCache, session *auth.Session, params MoveQueueParams) (MoveQueuePage, error) {
+	page := MoveQueuePage{Items: []MoveQueueItem{}}
+
+	queue, ok := moveQueues[params.LifecycleState]
+	if !ok {
+		return page, errors.Wrapf(ErrInvalidQueueParams, "unknown queue %s", params.LifecycleState)
+	}
+	sorts := params.Sort
+	if len(sorts) == 0 {
+		sorts = defaultMoveQueueSort
+	}
+	for _, sort := range sorts {
+		if _, ok := moveQueueSortColumns[sort.Column]; !ok {
+			return page, errors.Wrapf(ErrInvalidQueueParams, "unknown sort column %s", sort.Column)
+		}
+	}
+
+	scope, scopeArgs, err := officeMoveScope(db, session)
+	if err != nil {
+		return page, denyAccess(session, "queue", uuid.Nil, err.Error())
+	}
+
+	query := moveQueueQuery{}
+	query.where("queue_items.id IN (SELECT moves.id"+officeMoveScopeFrom+"WHERE "+scope+")", scopeArgs...)
+	filters := params.Filters
+	if filters.Assignee == MoveQueueAssignedToMe {
+		filters.Assignee = session.OfficeUserID.String()
+	}
+	if err := query.addFilters(filters); err != nil {
+		return page, err
+	}
+	if params.Cursor != "" {
+		cursor, err := decodeMoveQueueCursor(sorts, params.Cursor)
+		if err != nil {
+			return page, err
+		}
+		query.addCursor(sorts, cursor)
+	}
+
+	orderBy := []string{}
+	for _, sort := range sorts {
+		direction := "ASC"
+		if sort.Descending {
+			direction = "DESC"
+		}
+		orderBy = append(orderBy, moveQueueSortColumns[sort.Column]+" "+direction)
+	}
+	orderBy = append(orderBy, "queue_items.id ASC", "queue_items.row_id ASC")
+
+	inner := fmt.Sprintf(moveQueueSelect, queue.moveDate, queue.status, queue.rowID, queue.join, queue.condition)
+	sql := "SELECT * FROM (SELECT queue_items.*, " + lastModifiedNameColumn + ", " + assigneeColumns +
+		" FROM (" + inner + ") AS queue_items" + assigneeJoin + ") AS queue_items" +
+		" WHERE " + strings.Join(query.conditions, " AND ") +
+		" ORDER BY " + strings.Join(orderBy, ", ")
+	if params.Limit > 0 {
+		// Fetch one extra row to find out whether there is another page
+		sql += fmt.Sprintf(" LIMIT %d", params.Limit+1)
+	}
+
+	var items []MoveQueueItem
+	args := append([]interface{}{time.Now()}, query.args...)
+	if err := db.RawQuery(sql, args...).All(&items); err != nil {
+		return page, err
+	}
+
+	if params.Limit > 0 && len(items) > params.Limit {
+		items = items[:params.Limit]
+		next := encodeMoveQueueCursor(sorts, items[len(items)-1])
+		page.NextCursor = &next
+	}
+	if err := applySLADeadlines(db, calendars, items); err != nil {
+		return page, err
+	}
+	page.Items = items
+	return page, nil
+}
+
+// applySLADeadlines sets each item's customer deadline to the earliest SLA due date of its move's
+// waiting records. Moves with nothing waiting on the office keep their creation date.
+func applySLADeadlines(db *pop.Connection, calendars *dates.CalendarCache, items []MoveQueueItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	moveIDs := make([]uuid.UUID, len(items))
+	for i, item := range items {
+		moveIDs[i] = item.ID
+	}
+	slaItems, err := FetchSLAItems(db, calendars, SLAItemFilter{MoveIDs: moveIDs})
+	if err != nil {
+		return err
+	}
+
+	// SLA items are earliest due first, so the first one seen for a move is its deadline
+	deadlines := map[uuid.UUID]time.Time{}
+	for _, slaItem := range slaItems {
+		if _, ok := deadlines[slaItem.MoveID]; !ok {
+			deadlines[slaItem.MoveID] = slaItem.DueAt
+		}
+	}
+	for i := range items {
+		items[i].CustomerDeadline = items[i].CreatedAt
+		if deadline, ok := deadlines[items[i].ID]; ok {
+			items[i].CustomerDeadline = deadline
+		}
+	}
+	return nil
 }
 
-// GetMoveQueueItems gets all moveQueueItems for a specific lifecycleState
-func GetMoveQueueItems(db *pop.Connection, lifecycleState string) ([]MoveQueueItem, error) {
-	var moveQueueItems []MoveQueueItem
-	var query string
-
-	if lifecycleState == "new" {
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				ppm.planned_move_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				ppm.status as ppm_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
-			WHERE moves.status = 'SUBMITTED'
-		`
-	} else if lifecycleState == "ppm" {
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				ppm.planned_move_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				ppm.status as ppm_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
-			WHERE moves.status = 'APPROVED'
-		`
-	} else if lifecycleState == "hhg_accepted" {
-		// Move date is the Requested Pickup Date because accepted shipments haven't yet gone through the
-		// premove survey to set the actual Pickup Date.
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				shipment.requested_pickup_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				shipment.status as hhg_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
-			WHERE shipment.status = 'ACCEPTED'
-		`
-	} else if lifecycleState == "hhg_in_transit" {
-		// Move date is the Actual Pickup Date.
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				shipment.actual_pickup_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				shipment.status as hhg_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
-			WHERE shipment.status = 'IN_TRANSIT'
-		`
-	} else if lifecycleState == "hhg_delivered" {
-		// Move date is the Actual Pickup Date.
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				shipment.actual_pickup_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				shipment.status as hhg_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
-			WHERE shipment.status = 'DELIVERED'
-		`
-	} else if lifecycleState == "hhg_completed" {
-		// Move date is the Actual Pickup Date.
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				shipment.actual_pickup_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				shipment.status as hhg_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN shipments as shipment ON moves.id = shipment.move_id
-			WHERE shipment.status = 'COMPLETED'
-		`
-	} else if lifecycleState == "all" {
-		query = `
-			SELECT moves.ID,
-				COALESCE(sm.edipi, '*missing*') as edipi,
-				COALESCE(sm.rank, '*missing*') as rank,
-				CONCAT(COALESCE(sm.last_name, '*missing*'), ', ', COALESCE(sm.first_name, '*missing*')) AS customer_name,
-				moves.locator as locator,
-				ord.orders_type as orders_type,
-				ppm.planned_move_date as move_date,
-				moves.created_at as created_at,
-				moves.updated_at as last_modified_date,
-				moves.status as status,
-				ppm.status as ppm_status
-			FROM moves
-			JOIN orders as ord ON moves.orders_id = ord.id
-			JOIN service_members AS sm ON ord.service_member_id = sm.id
-			LEFT JOIN personally_procured_moves AS ppm ON moves.id = ppm.move_id
-		`
-	}
-
-	err := db.RawQuery(query).All(&moveQueueItems)
-	return moveQueueItems, err
+// GetMoveQueueItems gets all moveQueueItems for a specific lifecycleState that the office user may access
+func GetMoveQueueItems(db *pop.Connection, calendars *dates.CalendarCache, session *auth.Session, lifecycleState string) ([]MoveQueueItem, error) {
+	page, err := SearchMoveQueue(db, calendars, session, MoveQueueParams{LifecycleState: lifecycleState})
+	return page.Items, err
 }
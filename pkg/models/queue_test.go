@@ -0,0 +1,122 @@
+package models_test
+
+import (
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) makeSubmittedMove(affiliation ServiceMemberAffiliation) Move {
+	return testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		ServiceMember: ServiceMember{Affiliation: &affiliation},
+		Move:          Move{Status: MoveStatusSUBMITTED},
+	})
+}
+
+func (suite *ModelSuite) TestSearchMoveQueueFilters() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	suite.makeSubmittedMove(AffiliationARMY)
+	navyMove := suite.makeSubmittedMove(AffiliationNAVY)
+	testdatagen.MakeMove(suite.db, testdatagen.Assertions{Move: Move{Status: MoveStatusDRAFT}})
+
+	page, err := SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{LifecycleState: "new"})
+	suite.Nil(err)
+	suite.Len(page.Items, 2)
+	suite.Nil(page.NextCursor)
+
+	page, err = SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{
+		LifecycleState: "new",
+		Filters:        MoveQueueFilters{Branches: []string{"NAVY"}},
+	})
+	suite.Nil(err)
+	suite.Len(page.Items, 1)
+	suite.Equal(navyMove.ID, page.Items[0].ID)
+	suite.Equal("NAVY", *page.Items[0].Branch)
+
+	page, err = SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{
+		LifecycleState: "new",
+		Filters:        MoveQueueFilters{GBLOC: "AGFM"},
+	})
+	suite.Nil(err)
+	suite.Len(page.Items, 0)
+}
+
+func (suite *ModelSuite) TestSearchMoveQueuePagination() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	for i := 0; i < 5; i++ {
+		suite.makeSubmittedMove(AffiliationARMY)
+	}
+
+	for _, sorts := range [][]MoveQueueSort{
+		{},
+		{{Column: "created_at", Descending: true}},
+		{{Column: "customer_name"}, {Column: "move_date", Descending: true}},
+	} {
+		seen := map[string]bool{}
+		params := MoveQueueParams{LifecycleState: "new", Sort: sorts, Limit: 2}
+		pages := 0
+		for {
+			page, err := SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), params)
+			suite.Nil(err)
+			pages++
+			for _, item := range page.Items {
+				suite.False(seen[item.ID.String()], "move returned twice")
+				seen[item.ID.String()] = true
+			}
+			if page.NextCursor == nil {
+				break
+			}
+			params.Cursor = *page.NextCursor
+		}
+		suite.Equal(3, pages)
+		suite.Len(seen, 5)
+	}
+
+	// Cursors can't be used with a different sort
+	page, err := SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{LifecycleState: "new", Limit: 2})
+	suite.Nil(err)
+	_, err = SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{
+		LifecycleState: "new",
+		Sort:           []MoveQueueSort{{Column: "locator"}},
+		Cursor:         *page.NextCursor,
+	})
+	suite.Equal(ErrInvalidQueueParams, errors.Cause(err))
+}
+
+func (suite *ModelSuite) TestSearchMoveQueuePaginatesMovesListedMoreThanOnce() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	move := testdatagen.MakeMove(suite.db, testdatagen.Assertions{Move: Move{Status: MoveStatusAPPROVED}})
+	for i := 0; i < 3; i++ {
+		testdatagen.MakePPM(suite.db, testdatagen.Assertions{
+			PersonallyProcuredMove: PersonallyProcuredMove{Move: move, MoveID: move.ID},
+		})
+	}
+
+	seen := map[string]bool{}
+	params := MoveQueueParams{LifecycleState: "ppm", Limit: 1}
+	for {
+		page, err := SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), params)
+		suite.Nil(err)
+		for _, item := range page.Items {
+			suite.Equal(move.ID, item.ID)
+			suite.False(seen[item.RowID.String()], "PPM returned twice")
+			seen[item.RowID.String()] = true
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		params.Cursor = *page.NextCursor
+	}
+	suite.Len(seen, 3)
+}
+
+func (suite *ModelSuite) TestSearchMoveQueueRejectsUnknownColumns() {
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+
+	_, err := ParseMoveQueueSort([]string{"-move_date", "ssn"})
+	suite.Equal(ErrInvalidQueueParams, errors.Cause(err))
+
+	_, err = SearchMoveQueue(suite.db, NewCalendarCache(suite.db), officeSession(officeUser), MoveQueueParams{LifecycleState: "troubleshooting"})
+	suite.Equal(ErrInvalidQueueParams, errors.Cause(err))
+}
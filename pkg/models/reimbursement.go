@@ -50,6 +50,11 @@ type Reimbursement struct {
 	MethodOfReceipt MethodOfReceipt     `json:"method_of_receipt" db:"method_of_receipt"`
 	Status          ReimbursementStatus `json:"status" db:"status"`
 	RequestedDate   *time.Time          `json:"requested_date" db:"requested_date"`
+	ApprovedAmount  *unit.Cents         `json:"approved_amount" db:"approved_amount"`
+	PaidAmount      *unit.Cents         `json:"paid_amount" db:"paid_amount"`
+	PaidDate        *time.Time          `json:"paid_date" db:"paid_date"`
+	// DisbursementRequestedAt is when the reimbursement was exported to finance to be paid
+	DisbursementRequestedAt *time.Time `json:"disbursement_requested_at" db:"disbursement_requested_at"`
 }
 
 // State Machine
@@ -74,6 +79,10 @@ func (r *Reimbursement) Approve() error {
 	}
 
 	r.Status = ReimbursementStatusAPPROVED
+	if r.ApprovedAmount == nil {
+		approved := r.RequestedAmount
+		r.ApprovedAmount = &approved
+	}
 	return nil
 }
 
@@ -97,6 +106,17 @@ func (r *Reimbursement) Pay() error {
 	return nil
 }
 
+// Disburse pays the Reimbursement and records the amount and date finance actually paid
+func (r *Reimbursement) Disburse(amount unit.Cents, date time.Time) error {
+	if err := r.Pay(); err != nil {
+		return err
+	}
+
+	r.PaidAmount = &amount
+	r.PaidDate = &date
+	return nil
+}
+
 // END State Machine
 
 // BuildDraftReimbursement makes a Reimbursement in the DRAFT state, but does not save it
@@ -144,11 +164,15 @@ func (r *Reimbursement) Validate(tx *pop.Connection) (*validate.Errors, error) {
 		string(MethodOfReceiptGTCC),
 	}
 
-	return validate.Validate(
+	verrs := validate.Validate(
 		&validators.IntIsGreaterThan{Field: int(r.RequestedAmount), Name: "RequestedAmount", Compared: 0},
 		&validators.StringInclusion{Field: string(r.Status), Name: "Status", List: validStatuses},
 		&validators.StringInclusion{Field: string(r.MethodOfReceipt), Name: "Status", List: validMethodsOfReceipt},
-	), nil
+	)
+	if r.PaidAmount != nil && r.PaidAmount.Int() <= 0 {
+		verrs.Add(validators.GenerateKey("PaidAmount"), "PaidAmount must be greater than 0.")
+	}
+	return verrs, nil
 }
 
 // ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
@@ -175,5 +199,19 @@ func FetchReimbursement(db *pop.Connection, session *auth.Session, id uuid.UUID)
 		return nil, err
 	}
 
+	// Advances are authorized through the PPM they belong to. Any other reimbursement is office-only.
+	var ppms PersonallyProcuredMoves
+	err = db.Where("advance_id = $1", reimbursement.ID).All(&ppms)
+	if err != nil {
+		return nil, err
+	}
+	if len(ppms) > 0 {
+		if authErr := AuthorizeMoveAccess(db, session, ppms[0].MoveID); authErr != nil {
+			return nil, authErr
+		}
+	} else if !session.IsOfficeApp() || !session.IsOfficeUser() {
+		return nil, denyAccess(session, "reimbursement", reimbursement.ID, "reimbursement has no PPM")
+	}
+
 	return &reimbursement, nil
 }
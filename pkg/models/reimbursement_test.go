@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 
 	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
 )
 
 func (suite *ModelSuite) TestReimbursementStateMachine() {
@@ -56,3 +57,29 @@ func (suite *ModelSuite) TestBasicReimbursement() {
 	}
 
 }
+
+func (suite *ModelSuite) TestReimbursementDisburse() {
+	reimbursement := BuildDraftReimbursement(1200, MethodOfReceiptOTHERDD)
+	reimbursement.Request()
+
+	paidDate := time.Date(2018, time.December, 3, 0, 0, 0, 0, time.UTC)
+	err := reimbursement.Disburse(1100, paidDate)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	suite.Nil(reimbursement.PaidAmount)
+
+	err = reimbursement.Approve()
+	suite.Nil(err)
+	suite.Equal(unit.Cents(1200), *reimbursement.ApprovedAmount)
+
+	err = reimbursement.Disburse(1100, paidDate)
+	suite.Nil(err)
+	suite.Equal(ReimbursementStatusPAID, reimbursement.Status, "expected Paid")
+	suite.Equal(unit.Cents(1100), *reimbursement.PaidAmount)
+	suite.Equal(paidDate, *reimbursement.PaidDate)
+
+	var zero unit.Cents
+	reimbursement.PaidAmount = &zero
+	suite.verifyValidationErrors(&reimbursement, map[string][]string{
+		"paid_amount": {"PaidAmount must be greater than 0."},
+	})
+}
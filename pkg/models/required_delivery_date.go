@@ -0,0 +1,230 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/rickar/cal"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// StopOffTransitDays is the transit time the tariff adds for each extra pickup or delivery stop
+const StopOffTransitDays = 1
+
+const rddDateFormat = "2006-01-02"
+
+// RequiredDeliveryDate is the date by which a TSP must deliver a shipment, along with what it was
+// derived from
+type RequiredDeliveryDate struct {
+	Date             time.Time
+	PickupDate       time.Time
+	Weight           unit.Pound
+	Miles            int
+	TableTransitDays int
+	StopOffs         int
+	TransitDays      int
+	Basis            []string
+}
+
+// rddPickupDate returns the date transit starts from: the actual pickup date once the shipment has
+// been picked up, then the date planned at the pre-move survey, then the requested pickup date
+func (s *Shipment) rddPickupDate() (time.Time, string, error) {
+	if s.ActualPickupDate != nil {
+		return *s.ActualPickupDate, "actual pickup date", nil
+	}
+	if s.PmSurveyPlannedPickupDate != nil {
+		return *s.PmSurveyPlannedPickupDate, "pickup date planned at the pre-move survey", nil
+	}
+	if s.RequestedPickupDate != nil {
+		return *s.RequestedPickupDate, "requested pickup date", nil
+	}
+	return time.Time{}, "", errors.New("Shipment must have a pickup date")
+}
+
+// rddWeight returns the weight transit time is looked up with: the net weight once the shipment has
+// been weighed, then the pre-move survey estimate, then the member's estimate. Estimates include pro-gear.
+func (s *Shipment) rddWeight() (unit.Pound, string, error) {
+	if s.NetWeight != nil {
+		return *s.NetWeight, "net weight", nil
+	}
+	if s.PmSurveyWeightEstimate != nil {
+		weight := *s.PmSurveyWeightEstimate
+		if s.PmSurveyProgearWeightEstimate != nil {
+			weight += *s.PmSurveyProgearWeightEstimate
+		}
+		if s.PmSurveySpouseProgearWeightEstimate != nil {
+			weight += *s.PmSurveySpouseProgearWeightEstimate
+		}
+		return weight, "weight estimated at the pre-move survey, including pro-gear", nil
+	}
+	if s.WeightEstimate != nil {
+		weight := *s.WeightEstimate
+		if s.ProgearWeightEstimate != nil {
+			weight += *s.ProgearWeightEstimate
+		}
+		if s.SpouseProgearWeightEstimate != nil {
+			weight += *s.SpouseProgearWeightEstimate
+		}
+		return weight, "weight estimated by the service member, including pro-gear", nil
+	}
+	return 0, "", errors.New("Shipment must have a weight or weight estimate")
+}
+
+// rddStopOffs returns the descriptions of the stops the shipment makes besides its pickup and delivery
+func (s *Shipment) rddStopOffs() []string {
+	var stopOffs []string
+	if s.HasSecondaryPickupAddress {
+		stopOffs = append(stopOffs, "secondary pickup address")
+	}
+	if s.HasPartialSITDeliveryAddress {
+		stopOffs = append(stopOffs, "partial delivery out of SIT")
+	}
+	return stopOffs
+}
+
+// CalculateRequiredDeliveryDate derives a shipment's RDD from the tariff's transit time table for its
+// weight and the miles it travels, plus a day for each extra stop. A date that falls on a weekend or
+// holiday of calendar moves to the next workday.
+func CalculateRequiredDeliveryDate(s *Shipment, miles int, calendar *cal.Calendar) (RequiredDeliveryDate, error) {
+	var rdd RequiredDeliveryDate
+
+	pickupDate, pickupBasis, err := s.rddPickupDate()
+	if err != nil {
+		return rdd, err
+	}
+	weight, weightBasis, err := s.rddWeight()
+	if err != nil {
+		return rdd, err
+	}
+	tableTransitDays, err := TransitDays(weight, miles)
+	if err != nil {
+		return rdd, err
+	}
+	stopOffs := s.rddStopOffs()
+
+	rdd.PickupDate = pickupDate
+	rdd.Weight = weight
+	rdd.Miles = miles
+	rdd.TableTransitDays = tableTransitDays
+	rdd.StopOffs = len(stopOffs)
+	rdd.TransitDays = tableTransitDays + len(stopOffs)*StopOffTransitDays
+	rdd.Basis = []string{
+		fmt.Sprintf("Transit starts %s, the %s.", pickupDate.Format(rddDateFormat), pickupBasis),
+		fmt.Sprintf("%d lbs, the %s, over %d miles takes %d days in the transit time table.",
+			weight.Int(), weightBasis, miles, tableTransitDays),
+	}
+	for _, stopOff := range stopOffs {
+		rdd.Basis = append(rdd.Basis, fmt.Sprintf("The %s adds %d day.", stopOff, StopOffTransitDays))
+	}
+
+	date := pickupDate.AddDate(0, 0, rdd.TransitDays)
+	rdd.Date = date
+	for !calendar.IsWorkday(rdd.Date) {
+		rdd.Date = rdd.Date.AddDate(0, 0, 1)
+	}
+	if rdd.Date.Equal(date) {
+		rdd.Basis = append(rdd.Basis, fmt.Sprintf("Delivery is required by %s.", rdd.Date.Format(rddDateFormat)))
+	} else {
+		rdd.Basis = append(rdd.Basis, fmt.Sprintf("%s is not a workday, so delivery is required by %s.",
+			date.Format(rddDateFormat), rdd.Date.Format(rddDateFormat)))
+	}
+	return rdd, nil
+}
+
+// SetRequiredDeliveryDate records a calculated RDD and its basis on the shipment
+func (s *Shipment) SetRequiredDeliveryDate(rdd RequiredDeliveryDate) {
+	date := rdd.Date
+	basis := strings.Join(rdd.Basis, "\n")
+	s.RequiredDeliveryDate = &date
+	s.RequiredDeliveryDateBasis = &basis
+}
+
+// RequiredDeliveryDateBasisLines returns the explanation of the shipment's RDD, one step per line
+func (s *Shipment) RequiredDeliveryDateBasisLines() []string {
+	if s.RequiredDeliveryDateBasis == nil || *s.RequiredDeliveryDateBasis == "" {
+		return nil
+	}
+	return strings.Split(*s.RequiredDeliveryDateBasis, "\n")
+}
+
+// RequiredDeliveryDateInputsChanged returns whether anything the RDD is derived from changed between two
+// versions of a shipment
+func RequiredDeliveryDateInputsChanged(before Shipment, after Shipment) bool {
+	dateChanged := func(before *time.Time, after *time.Time) bool {
+		if before == nil || after == nil {
+			return before != after
+		}
+		return !before.Equal(*after)
+	}
+	poundsChanged := func(before *unit.Pound, after *unit.Pound) bool {
+		if before == nil || after == nil {
+			return before != after
+		}
+		return *before != *after
+	}
+	return dateChanged(before.RequestedPickupDate, after.RequestedPickupDate) ||
+		dateChanged(before.PmSurveyPlannedPickupDate, after.PmSurveyPlannedPickupDate) ||
+		dateChanged(before.ActualPickupDate, after.ActualPickupDate) ||
+		poundsChanged(before.WeightEstimate, after.WeightEstimate) ||
+		poundsChanged(before.ProgearWeightEstimate, after.ProgearWeightEstimate) ||
+		poundsChanged(before.SpouseProgearWeightEstimate, after.SpouseProgearWeightEstimate) ||
+		poundsChanged(before.PmSurveyWeightEstimate, after.PmSurveyWeightEstimate) ||
+		poundsChanged(before.PmSurveyProgearWeightEstimate, after.PmSurveyProgearWeightEstimate) ||
+		poundsChanged(before.PmSurveySpouseProgearWeightEstimate, after.PmSurveySpouseProgearWeightEstimate) ||
+		poundsChanged(before.NetWeight, after.NetWeight) ||
+		before.HasSecondaryPickupAddress != after.HasSecondaryPickupAddress ||
+		before.HasPartialSITDeliveryAddress != after.HasPartialSITDeliveryAddress ||
+		before.HasDeliveryAddress != after.HasDeliveryAddress
+}
+
+// IsDeliveredLate returns true if the shipment was delivered after its RDD
+func (s *Shipment) IsDeliveredLate() bool {
+	if s.ActualDeliveryDate == nil || s.RequiredDeliveryDate == nil {
+		return false
+	}
+	return dateOnly(*s.ActualDeliveryDate).After(dateOnly(*s.RequiredDeliveryDate))
+}
+
+func dateOnly(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DeliveryPerformance counts the shipments a TSP delivered in a TDL and how many of them were delivered
+// after their RDD
+type DeliveryPerformance struct {
+	Delivered int `db:"delivered"`
+	Late      int `db:"late"`
+}
+
+// OnTimeRate returns the share of deliveries made by the RDD, or 1 if there were none
+func (p DeliveryPerformance) OnTimeRate() float64 {
+	if p.Delivered == 0 {
+		return 1
+	}
+	return float64(p.Delivered-p.Late) / float64(p.Delivered)
+}
+
+// FetchDeliveryPerformance returns how a TSP's deliveries in a TDL between start and end, inclusive,
+// measured up against their RDDs
+func FetchDeliveryPerformance(db *pop.Connection, tspID uuid.UUID, tdlID uuid.UUID, start time.Time, end time.Time) (DeliveryPerformance, error) {
+	sql := `SELECT COUNT(*) AS delivered,
+			COUNT(*) FILTER (WHERE shipments.actual_delivery_date::date > shipments.required_delivery_date) AS late
+		FROM shipments
+		JOIN shipment_offers ON shipment_offers.shipment_id = shipments.id
+		WHERE shipment_offers.transportation_service_provider_id = $1
+		AND shipment_offers.accepted = true
+		AND shipments.traffic_distribution_list_id = $2
+		AND shipments.actual_delivery_date::date BETWEEN $3::date AND $4::date
+	`
+	var performance DeliveryPerformance
+	if err := db.RawQuery(sql, tspID, tdlID, start, end).First(&performance); err != nil {
+		return performance, errors.Wrap(err, "fetching delivery performance")
+	}
+	return performance, nil
+}
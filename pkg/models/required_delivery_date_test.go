@@ -0,0 +1,111 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/dates"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ModelSuite) TestCalculateRequiredDeliveryDate() {
+	calendar := dates.NewUSCalendar()
+	requestedPickupDate := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	weightEstimate := unit.Pound(4500)
+	progearWeightEstimate := unit.Pound(500)
+	shipment := Shipment{
+		RequestedPickupDate:   &requestedPickupDate,
+		WeightEstimate:        &weightEstimate,
+		ProgearWeightEstimate: &progearWeightEstimate,
+	}
+
+	// 5000 lbs over 1100 miles takes 10 days
+	rdd, err := CalculateRequiredDeliveryDate(&shipment, 1100, calendar)
+	suite.NoError(err)
+	suite.Equal(unit.Pound(5000), rdd.Weight)
+	suite.Equal(10, rdd.TransitDays)
+	suite.Equal(time.Date(2019, time.June, 13, 0, 0, 0, 0, time.UTC), rdd.Date)
+	suite.Len(rdd.Basis, 3)
+
+	// The pre-move survey moves pickup to Wednesday and lowers the weight to 2900 lbs, which takes
+	// 11 days and lands on a Sunday
+	plannedPickupDate := time.Date(2019, time.June, 5, 0, 0, 0, 0, time.UTC)
+	pmSurveyWeightEstimate := unit.Pound(2500)
+	pmSurveyProgearWeightEstimate := unit.Pound(400)
+	before := shipment
+	shipment.PmSurveyPlannedPickupDate = &plannedPickupDate
+	shipment.PmSurveyWeightEstimate = &pmSurveyWeightEstimate
+	shipment.PmSurveyProgearWeightEstimate = &pmSurveyProgearWeightEstimate
+	suite.True(RequiredDeliveryDateInputsChanged(before, shipment))
+
+	rdd, err = CalculateRequiredDeliveryDate(&shipment, 1100, calendar)
+	suite.NoError(err)
+	suite.Equal(11, rdd.TransitDays)
+	suite.Equal(time.Date(2019, time.June, 17, 0, 0, 0, 0, time.UTC), rdd.Date)
+
+	// A secondary pickup and a partial delivery out of SIT each add a day
+	shipment.HasSecondaryPickupAddress = true
+	shipment.HasPartialSITDeliveryAddress = true
+	rdd, err = CalculateRequiredDeliveryDate(&shipment, 1100, calendar)
+	suite.NoError(err)
+	suite.Equal(2, rdd.StopOffs)
+	suite.Equal(13, rdd.TransitDays)
+	suite.Equal(time.Date(2019, time.June, 18, 0, 0, 0, 0, time.UTC), rdd.Date)
+
+	shipment.SetRequiredDeliveryDate(rdd)
+	suite.Equal(rdd.Basis, shipment.RequiredDeliveryDateBasisLines())
+	suite.False(RequiredDeliveryDateInputsChanged(shipment, shipment))
+
+	onTime := time.Date(2019, time.June, 18, 0, 0, 0, 0, time.UTC)
+	shipment.ActualDeliveryDate = &onTime
+	suite.False(shipment.IsDeliveredLate())
+	late := time.Date(2019, time.June, 19, 0, 0, 0, 0, time.UTC)
+	shipment.ActualDeliveryDate = &late
+	suite.True(shipment.IsDeliveredLate())
+
+	// Without a weight there's no RDD
+	_, err = CalculateRequiredDeliveryDate(&Shipment{RequestedPickupDate: &requestedPickupDate}, 1100, calendar)
+	suite.Error(err)
+}
+
+func (suite *ModelSuite) TestFetchDeliveryPerformance() {
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	requiredDeliveryDate := time.Date(2019, time.June, 18, 0, 0, 0, 0, time.UTC)
+
+	tdlID := suite.makeDeliveredShipmentOffer(tsp, requiredDeliveryDate, requiredDeliveryDate)
+	suite.makeDeliveredShipmentOffer(tsp, requiredDeliveryDate, requiredDeliveryDate.AddDate(0, 0, 2))
+
+	start := time.Date(2019, time.June, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2019, time.June, 30, 0, 0, 0, 0, time.UTC)
+	performance, err := FetchDeliveryPerformance(suite.db, tsp.ID, tdlID, start, end)
+	suite.NoError(err)
+	suite.Equal(2, performance.Delivered)
+	suite.Equal(1, performance.Late)
+	suite.Equal(0.5, performance.OnTimeRate())
+
+	// Deliveries outside of the period don't count
+	performance, err = FetchDeliveryPerformance(suite.db, tsp.ID, tdlID, end.AddDate(0, 0, 1), end.AddDate(0, 1, 0))
+	suite.NoError(err)
+	suite.Equal(0, performance.Delivered)
+	suite.Equal(1.0, performance.OnTimeRate())
+}
+
+func (suite *ModelSuite) makeDeliveredShipmentOffer(tsp TransportationServiceProvider, requiredDeliveryDate time.Time, actualDeliveryDate time.Time) uuid.UUID {
+	accepted := true
+	offer := testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{
+			Status:               ShipmentStatusDELIVERED,
+			RequiredDeliveryDate: &requiredDeliveryDate,
+			ActualDeliveryDate:   &actualDeliveryDate,
+		},
+		ShipmentOffer: ShipmentOffer{
+			TransportationServiceProviderID: tsp.ID,
+			TransportationServiceProvider:   tsp,
+			Accepted:                        &accepted,
+		},
+	})
+	return *offer.Shipment.TrafficDistributionListID
+}
@@ -98,27 +98,8 @@ func FetchServiceMemberForUser(db *pop.Connection, session *auth.Session, id uui
 		return ServiceMember{}, err
 	}
 	// TODO: Handle case where more than one user is authorized to modify serviceMember
-	if session.IsMyApp() && serviceMember.ID != session.ServiceMemberID {
-		return ServiceMember{}, ErrFetchForbidden
-	} else if session.IsTspApp() {
-		// A TspUser is only allowed to interact with a service member if they are associated with one of their shipments.
-		query := `
-			SELECT tsp_users.id FROM tsp_users, shipment_offers, shipments
-			WHERE
-				tsp_users.transportation_service_provider_id = shipment_offers.transportation_service_provider_id
-				AND shipment_offers.shipment_id = shipments.id
-				AND shipment_offers.accepted IS NOT FALSE
-				AND tsp_users.id = $1
-				AND shipments.service_member_id = $2
-		`
-
-		count, err := db.RawQuery(query, session.TspUserID, serviceMember.ID).Count(TspUser{})
-		if err != nil {
-			return ServiceMember{}, err
-		}
-		if count == 0 {
-			return ServiceMember{}, ErrFetchForbidden
-		}
+	if authErr := AuthorizeServiceMemberAccess(db, session, serviceMember.ID); authErr != nil {
+		return ServiceMember{}, authErr
 	}
 
 	// TODO: Remove this when Pop's eager loader stops populating blank structs into these fields
@@ -158,7 +139,7 @@ func SaveServiceMember(dbConnection *pop.Connection, serviceMember *ServiceMembe
 	var responseError error
 
 	// If the passed in function returns an error, the transaction is rolled back
-	dbConnection.Transaction(func(dbConnection *pop.Connection) error {
+	inTransaction(dbConnection, func(dbConnection *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 
 		if serviceMember.ResidentialAddress != nil {
@@ -238,7 +219,7 @@ func (s ServiceMember) CreateOrder(db *pop.Connection,
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
-	db.Transaction(func(dbConnection *pop.Connection) error {
+	inTransaction(db, func(dbConnection *pop.Connection) error {
 		transactionError := errors.New("Rollback The transaction")
 		uploadedOrders := Document{
 			ServiceMemberID: s.ID,
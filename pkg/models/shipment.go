@@ -61,13 +61,15 @@ type Shipment struct {
 	ServiceAgents             ServiceAgents            `has_many:"service_agents" order_by:"created_at desc"`
 
 	// dates
-	ActualPickupDate     *time.Time `json:"actual_pickup_date" db:"actual_pickup_date"`         // when shipment is scheduled to be picked up by the TSP
-	ActualPackDate       *time.Time `json:"actual_pack_date" db:"actual_pack_date"`             // when packing began
-	ActualDeliveryDate   *time.Time `json:"actual_delivery_date" db:"actual_delivery_date"`     // when shipment was delivered
-	BookDate             *time.Time `json:"book_date" db:"book_date"`                           // when shipment was most recently offered to a TSP
-	RequestedPickupDate  *time.Time `json:"requested_pickup_date" db:"requested_pickup_date"`   // when shipment was originally scheduled to be picked up
-	OriginalDeliveryDate *time.Time `json:"original_delivery_date" db:"original_delivery_date"` // when shipment is to be delivered
-	OriginalPackDate     *time.Time `json:"original_pack_date" db:"original_pack_date"`         // when packing is to begin
+	ActualPickupDate          *time.Time `json:"actual_pickup_date" db:"actual_pickup_date"`                     // when shipment is scheduled to be picked up by the TSP
+	ActualPackDate            *time.Time `json:"actual_pack_date" db:"actual_pack_date"`                         // when packing began
+	ActualDeliveryDate        *time.Time `json:"actual_delivery_date" db:"actual_delivery_date"`                 // when shipment was delivered
+	BookDate                  *time.Time `json:"book_date" db:"book_date"`                                       // when shipment was most recently offered to a TSP
+	RequestedPickupDate       *time.Time `json:"requested_pickup_date" db:"requested_pickup_date"`               // when shipment was originally scheduled to be picked up
+	OriginalDeliveryDate      *time.Time `json:"original_delivery_date" db:"original_delivery_date"`             // when shipment is to be delivered
+	OriginalPackDate          *time.Time `json:"original_pack_date" db:"original_pack_date"`                     // when packing is to begin
+	RequiredDeliveryDate      *time.Time `json:"required_delivery_date" db:"required_delivery_date"`             // when the TSP must deliver by, per the tariff
+	RequiredDeliveryDateBasis *string    `json:"required_delivery_date_basis" db:"required_delivery_date_basis"` // how RequiredDeliveryDate was derived
 
 	// calculated durations
 	EstimatedPackDays    *int64 `json:"estimated_pack_days" db:"estimated_pack_days"`       // how many days it will take to pack
@@ -228,9 +230,23 @@ func (s *Shipment) BeforeSave(tx *pop.Connection) error {
 	// Ensure that OriginalPackDate and OriginalDeliveryDate are set
 	// Requires that we know RequestedPickupDate, EstimatedPackDays, and EstimatedTransitDays
 	if s.RequestedPickupDate != nil && s.EstimatedPackDays != nil && s.EstimatedTransitDays != nil &&
-		(s.OriginalPackDate == nil || s.OriginalDeliveryDate != nil) {
+		(s.OriginalPackDate == nil || s.OriginalDeliveryDate == nil) {
+		// The dates are counted on the calendar of the service member's duty station, like the ones
+		// they were shown when picking their move date
+		scope := dates.CalendarScope{}
+		if s.ServiceMemberID != uuid.Nil {
+			scope, err = FetchServiceMemberCalendarScope(tx, s.ServiceMemberID)
+			if err != nil {
+				return err
+			}
+		}
+		calendar, err := FetchCalendar(tx, scope)
+		if err != nil {
+			return err
+		}
+
 		var summary dates.MoveDatesSummary
-		summary.CalculateMoveDates(*s.RequestedPickupDate, int(*s.EstimatedPackDays), int(*s.EstimatedTransitDays))
+		summary.CalculateMoveDates(*s.RequestedPickupDate, int(*s.EstimatedPackDays), int(*s.EstimatedTransitDays), calendar)
 
 		if s.OriginalPackDate == nil {
 			s.OriginalPackDate = &summary.PackDays[0]
@@ -483,13 +499,10 @@ func FetchShipment(db *pop.Connection, session *auth.Session, id uuid.UUID) (*Sh
 		return nil, err
 	}
 	// TODO: Handle case where more than one user is authorized to modify shipment
-	move, err := FetchMove(db, session, shipment.MoveID)
+	err = AuthorizeShipmentAccess(db, session, &shipment)
 	if err != nil {
 		return nil, err
 	}
-	if session.IsMyApp() && move.Orders.ServiceMemberID != session.ServiceMemberID {
-		return nil, ErrFetchForbidden
-	}
 
 	return &shipment, nil
 }
@@ -543,11 +556,11 @@ func FetchShipmentForVerifiedTSPUser(db *pop.Connection, tspUserID uuid.UUID, sh
 }
 
 // saveShipmentAndOffer Validates and updates the Shipment and Shipment Offer
-func saveShipmentAndOffer(db *pop.Connection, shipment *Shipment, offer *ShipmentOffer) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
+func saveShipmentAndOffer(db *pop.Connection, session *auth.Session, before *Shipment, shipment *Shipment, offer *ShipmentOffer, event string, reason string) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
 	// wrapped in a transaction because if one fails this actions should roll back.
 	responseVErrors := validate.NewErrors()
 	var responseError error
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("rollback")
 
 		if verrs, err := db.ValidateAndUpdate(shipment); verrs.HasAny() || err != nil {
@@ -562,6 +575,12 @@ func saveShipmentAndOffer(db *pop.Connection, shipment *Shipment, offer *Shipmen
 			return transactionError
 		}
 
+		if verrs, err := RecordAudit(db, session, before, shipment, event, reason); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
 		return nil
 	})
 
@@ -575,11 +594,12 @@ func AwardShipment(db *pop.Connection, shipmentID uuid.UUID) error {
 		return err
 	}
 
+	before := shipment
 	if err := shipment.Award(); err != nil {
 		return err
 	}
 
-	verrs, err := db.ValidateAndUpdate(&shipment)
+	verrs, err := SaveWithAudit(db, nil, &before, &shipment, "award", "")
 	if err != nil {
 		return err
 	} else if verrs.HasAny() {
@@ -590,7 +610,7 @@ func AwardShipment(db *pop.Connection, shipmentID uuid.UUID) error {
 }
 
 // AcceptShipmentForTSP accepts a shipment and shipment_offer
-func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.UUID) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
+func AcceptShipmentForTSP(db *pop.Connection, session *auth.Session, tspID uuid.UUID, shipmentID uuid.UUID) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
 
 	// Get the Shipment and Shipment Offer
 	shipment, err := FetchShipmentByTSP(db, tspID, shipmentID)
@@ -604,6 +624,7 @@ func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.U
 	}
 
 	// Accept the Shipment and Shipment Offer
+	before := *shipment
 	err = shipment.Accept()
 	if err != nil {
 		return shipment, shipmentOffer, nil, err
@@ -614,11 +635,11 @@ func AcceptShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.U
 		return shipment, shipmentOffer, nil, err
 	}
 
-	return saveShipmentAndOffer(db, shipment, shipmentOffer)
+	return saveShipmentAndOffer(db, session, &before, shipment, shipmentOffer, "accept", "")
 }
 
 // RejectShipmentForTSP accepts a shipment and shipment_offer
-func RejectShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.UUID, rejectionReason string) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
+func RejectShipmentForTSP(db *pop.Connection, session *auth.Session, tspID uuid.UUID, shipmentID uuid.UUID, rejectionReason string) (*Shipment, *ShipmentOffer, *validate.Errors, error) {
 
 	// Get the Shipment and Shipment Offer
 	shipment, err := FetchShipmentByTSP(db, tspID, shipmentID)
@@ -632,6 +653,7 @@ func RejectShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.U
 	}
 
 	// Move the shipment back to Submitted and Reject the shipment offer.
+	before := *shipment
 	err = shipment.Reject()
 	if err != nil {
 		return shipment, shipmentOffer, nil, err
@@ -642,16 +664,16 @@ func RejectShipmentForTSP(db *pop.Connection, tspID uuid.UUID, shipmentID uuid.U
 		return shipment, shipmentOffer, nil, err
 	}
 
-	return saveShipmentAndOffer(db, shipment, shipmentOffer)
+	return saveShipmentAndOffer(db, session, &before, shipment, shipmentOffer, "reject", rejectionReason)
 
 }
 
-// SaveShipmentAndAddresses saves a Shipment and its Addresses atomically.
-func SaveShipmentAndAddresses(db *pop.Connection, shipment *Shipment) (*validate.Errors, error) {
+// SaveShipmentAndAddresses saves a Shipment, its Addresses and audit records for the changes atomically.
+func SaveShipmentAndAddresses(db *pop.Connection, shipment *Shipment, audits ...AuditEvent) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
-	db.Transaction(func(db *pop.Connection) error {
+	inTransaction(db, func(db *pop.Connection) error {
 		transactionError := errors.New("rollback")
 
 		if shipment.PickupAddress != nil {
@@ -696,18 +718,24 @@ func SaveShipmentAndAddresses(db *pop.Connection, shipment *Shipment) (*validate
 			return transactionError
 		}
 
+		if verrs, err := recordAuditEvents(db, audits); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
 		return nil
 	})
 
 	return responseVErrors, responseError
 }
 
-// SaveShipmentAndLineItems saves a shipment and a slice of line items in a single transaction.
-func (s *Shipment) SaveShipmentAndLineItems(db *pop.Connection, lineItems []ShipmentLineItem) (*validate.Errors, error) {
+// SaveShipmentAndLineItems saves a shipment, a slice of line items and audit records for the changes in a single transaction.
+func (s *Shipment) SaveShipmentAndLineItems(db *pop.Connection, lineItems []ShipmentLineItem, audits ...AuditEvent) (*validate.Errors, error) {
 	responseVErrors := validate.NewErrors()
 	var responseError error
 
-	db.Transaction(func(tx *pop.Connection) error {
+	inTransaction(db, func(tx *pop.Connection) error {
 		transactionError := errors.New("rollback")
 
 		verrs, err := tx.ValidateAndSave(s)
@@ -727,6 +755,13 @@ func (s *Shipment) SaveShipmentAndLineItems(db *pop.Connection, lineItems []Ship
 			}
 		}
 
+		verrs, err = recordAuditEvents(tx, audits)
+		if err != nil || verrs.HasAny() {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
 		return nil
 	})
 
@@ -0,0 +1,363 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// InventoryStage is when an inventory was checked and signed
+type InventoryStage string
+
+const (
+	// InventoryStagePICKUP captures enum value "PICKUP"
+	InventoryStagePICKUP InventoryStage = "PICKUP"
+	// InventoryStageDELIVERY captures enum value "DELIVERY"
+	InventoryStageDELIVERY InventoryStage = "DELIVERY"
+)
+
+// InventorySignerRole is who signed an inventory
+type InventorySignerRole string
+
+const (
+	// InventorySignerRoleSERVICEMEMBER captures enum value "SERVICE_MEMBER", the member or their agent
+	InventorySignerRoleSERVICEMEMBER InventorySignerRole = "SERVICE_MEMBER"
+	// InventorySignerRoleTSP captures enum value "TSP", the crew chief or driver
+	InventorySignerRoleTSP InventorySignerRole = "TSP"
+)
+
+// InventoryConditionCodes are the standard descriptive symbols crews use to note the condition of
+// household goods, keyed by symbol
+var InventoryConditionCodes = map[string]string{
+	"BE":  "Bent",
+	"BR":  "Broken",
+	"BU":  "Burned",
+	"CH":  "Chipped",
+	"CP":  "Carrier packed",
+	"CU":  "Contaminated",
+	"D":   "Dented",
+	"DBO": "Disassembled by owner",
+	"F":   "Faded",
+	"G":   "Gouged",
+	"L":   "Loose",
+	"M":   "Marred",
+	"MI":  "Mildew",
+	"MO":  "Moth-eaten",
+	"PBO": "Packed by owner",
+	"R":   "Rubbed",
+	"RU":  "Rusted",
+	"SC":  "Scratched",
+	"SH":  "Short",
+	"SO":  "Soiled",
+	"T":   "Torn",
+	"W":   "Badly worn",
+	"Z":   "Cracked",
+}
+
+// SplitConditionCodes returns the condition codes stored in a comma separated list
+func SplitConditionCodes(codes string) []string {
+	if codes == "" {
+		return []string{}
+	}
+	return strings.Split(codes, ",")
+}
+
+// JoinConditionCodes returns condition codes as a comma separated list to store
+func JoinConditionCodes(codes []string) string {
+	return strings.Join(codes, ",")
+}
+
+// ShipmentInventory is the descriptive inventory of a shipment's household goods that the crew
+// writes at pickup. Exceptions are noted against it at delivery, and the service member and the TSP
+// sign it at both.
+type ShipmentInventory struct {
+	ID                              uuid.UUID                   `json:"id" db:"id"`
+	CreatedAt                       time.Time                   `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time                   `json:"updated_at" db:"updated_at"`
+	ShipmentID                      uuid.UUID                   `json:"shipment_id" db:"shipment_id"`
+	TransportationServiceProviderID uuid.UUID                   `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	LotNumber                       string                      `json:"lot_number" db:"lot_number"`
+	Items                           ShipmentInventoryItems      `has_many:"shipment_inventory_items" order_by:"tag_number asc"`
+	Signatures                      ShipmentInventorySignatures `has_many:"shipment_inventory_signatures" order_by:"signed_at asc"`
+}
+
+// ShipmentInventories is not required by pop and may be deleted
+type ShipmentInventories []ShipmentInventory
+
+// ShipmentInventoryItem is one tagged item on an inventory. Condition codes are stored as comma
+// separated lists.
+type ShipmentInventoryItem struct {
+	ID                     uuid.UUID `json:"id" db:"id"`
+	CreatedAt              time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time `json:"updated_at" db:"updated_at"`
+	ShipmentInventoryID    uuid.UUID `json:"shipment_inventory_id" db:"shipment_inventory_id"`
+	TagNumber              int       `json:"tag_number" db:"tag_number"`
+	Description            string    `json:"description" db:"description"`
+	PickupConditionCodes   string    `json:"pickup_condition_codes" db:"pickup_condition_codes"`
+	DeliveryConditionCodes *string   `json:"delivery_condition_codes" db:"delivery_condition_codes"`
+	DeliveryExceptionNotes *string   `json:"delivery_exception_notes" db:"delivery_exception_notes"`
+	MissingAtDelivery      bool      `json:"missing_at_delivery" db:"missing_at_delivery"`
+}
+
+// ShipmentInventoryItems is not required by pop and may be deleted
+type ShipmentInventoryItems []ShipmentInventoryItem
+
+// ShipmentInventorySignature is an electronic signature on an inventory. Signature holds the
+// signer's typed name or an encoded image of the signature they drew.
+type ShipmentInventorySignature struct {
+	ID                  uuid.UUID           `json:"id" db:"id"`
+	CreatedAt           time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at" db:"updated_at"`
+	ShipmentInventoryID uuid.UUID           `json:"shipment_inventory_id" db:"shipment_inventory_id"`
+	Stage               InventoryStage      `json:"stage" db:"stage"`
+	SignerRole          InventorySignerRole `json:"signer_role" db:"signer_role"`
+	SignerName          string              `json:"signer_name" db:"signer_name"`
+	Signature           string              `json:"signature" db:"signature"`
+	SignedAt            time.Time           `json:"signed_at" db:"signed_at"`
+}
+
+// ShipmentInventorySignatures is not required by pop and may be deleted
+type ShipmentInventorySignatures []ShipmentInventorySignature
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventory) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: i.ShipmentID, Name: "ShipmentID"},
+		&validators.UUIDIsPresent{Field: i.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.StringIsPresent{Field: i.LotNumber, Name: "LotNumber"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventory) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventory) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventoryItem) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	var deliveryConditionCodes string
+	if i.DeliveryConditionCodes != nil {
+		deliveryConditionCodes = *i.DeliveryConditionCodes
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: i.ShipmentInventoryID, Name: "ShipmentInventoryID"},
+		&validators.IntIsGreaterThan{Field: i.TagNumber, Name: "TagNumber", Compared: 0},
+		&validators.StringIsPresent{Field: i.Description, Name: "Description"},
+		&ConditionCodesAreValid{Field: i.PickupConditionCodes, Name: "PickupConditionCodes"},
+		&ConditionCodesAreValid{Field: deliveryConditionCodes, Name: "DeliveryConditionCodes"},
+		&StringIsNilOrNotBlank{Field: i.DeliveryExceptionNotes, Name: "DeliveryExceptionNotes"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventoryItem) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (i *ShipmentInventoryItem) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *ShipmentInventorySignature) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	validStages := []string{
+		string(InventoryStagePICKUP),
+		string(InventoryStageDELIVERY),
+	}
+	validSignerRoles := []string{
+		string(InventorySignerRoleSERVICEMEMBER),
+		string(InventorySignerRoleTSP),
+	}
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.ShipmentInventoryID, Name: "ShipmentInventoryID"},
+		&validators.StringInclusion{Field: string(s.Stage), Name: "Stage", List: validStages},
+		&validators.StringInclusion{Field: string(s.SignerRole), Name: "SignerRole", List: validSignerRoles},
+		&validators.StringIsPresent{Field: s.SignerName, Name: "SignerName"},
+		&validators.StringIsPresent{Field: s.Signature, Name: "Signature"},
+		&validators.TimeIsPresent{Field: s.SignedAt, Name: "SignedAt"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *ShipmentInventorySignature) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *ShipmentInventorySignature) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Signature returns the inventory's signature by the signer at the stage, or nil if they haven't signed
+func (i ShipmentInventory) Signature(stage InventoryStage, role InventorySignerRole) *ShipmentInventorySignature {
+	for _, signature := range i.Signatures {
+		if signature.Stage == stage && signature.SignerRole == role {
+			return &signature
+		}
+	}
+	return nil
+}
+
+func (i ShipmentInventory) hasSignatureAt(stage InventoryStage) bool {
+	return i.Signature(stage, InventorySignerRoleSERVICEMEMBER) != nil || i.Signature(stage, InventorySignerRoleTSP) != nil
+}
+
+// IsSigned returns true if both the service member and the TSP signed the inventory at the stage
+func (i ShipmentInventory) IsSigned(stage InventoryStage) bool {
+	return i.Signature(stage, InventorySignerRoleSERVICEMEMBER) != nil && i.Signature(stage, InventorySignerRoleTSP) != nil
+}
+
+// PickupIsOpen returns true if items can be added to the inventory or their pickup condition noted.
+// The inventory is closed at pickup once anyone signs it.
+func (i ShipmentInventory) PickupIsOpen(shipment Shipment) bool {
+	if i.hasSignatureAt(InventoryStagePICKUP) {
+		return false
+	}
+	return shipment.Status == ShipmentStatusACCEPTED ||
+		shipment.Status == ShipmentStatusAPPROVED ||
+		shipment.Status == ShipmentStatusINTRANSIT
+}
+
+// DeliveryIsOpen returns true if exceptions can be noted against the inventory's items. Exceptions
+// are noted once the inventory was signed at pickup, until anyone signs it at delivery.
+func (i ShipmentInventory) DeliveryIsOpen(shipment Shipment) bool {
+	if !i.IsSigned(InventoryStagePICKUP) || i.hasSignatureAt(InventoryStageDELIVERY) {
+		return false
+	}
+	return shipment.Status == ShipmentStatusINTRANSIT || shipment.Status == ShipmentStatusDELIVERED
+}
+
+// verrsForDuplicateTag returns an error if another item on the inventory has the item's tag number
+func (i ShipmentInventory) verrsForDuplicateTag(item ShipmentInventoryItem) *validate.Errors {
+	verrs := validate.NewErrors()
+	for _, other := range i.Items {
+		if other.ID != item.ID && other.TagNumber == item.TagNumber {
+			verrs.Add("tag_number", fmt.Sprintf("Tag number %d is already on the inventory.", item.TagNumber))
+		}
+	}
+	return verrs
+}
+
+// CreateShipmentInventory starts the inventory of a shipment for the TSP that's moving it
+func CreateShipmentInventory(db *pop.Connection, shipment Shipment, tspID uuid.UUID, lotNumber string) (*ShipmentInventory, *validate.Errors, error) {
+	inventory := ShipmentInventory{
+		ShipmentID:                      shipment.ID,
+		TransportationServiceProviderID: tspID,
+		LotNumber:                       lotNumber,
+	}
+	if !inventory.PickupIsOpen(shipment) {
+		return nil, validate.NewErrors(), errors.Wrap(ErrInvalidTransition, "CreateShipmentInventory on a shipment that isn't being picked up")
+	}
+
+	count, err := db.Where("shipment_id = $1", shipment.ID).Count(&ShipmentInventory{})
+	if err != nil {
+		return nil, validate.NewErrors(), errors.Wrap(err, "checking for shipment inventory")
+	}
+	if count > 0 {
+		verrs := validate.NewErrors()
+		verrs.Add("shipment_id", "The shipment already has an inventory.")
+		return nil, verrs, nil
+	}
+
+	verrs, err := db.ValidateAndCreate(&inventory)
+	if err != nil || verrs.HasAny() {
+		return nil, verrs, err
+	}
+	return &inventory, verrs, nil
+}
+
+// SaveShipmentInventoryItem adds an item to the inventory or updates its description and pickup
+// condition
+func SaveShipmentInventoryItem(db *pop.Connection, shipment Shipment, inventory *ShipmentInventory, item *ShipmentInventoryItem) (*validate.Errors, error) {
+	if item.ShipmentInventoryID != uuid.Nil && item.ShipmentInventoryID != inventory.ID {
+		return validate.NewErrors(), ErrFetchNotFound
+	}
+	if !inventory.PickupIsOpen(shipment) {
+		return validate.NewErrors(), errors.Wrap(ErrInvalidTransition, "SaveShipmentInventoryItem after pickup")
+	}
+	if verrs := inventory.verrsForDuplicateTag(*item); verrs.HasAny() {
+		return verrs, nil
+	}
+
+	item.ShipmentInventoryID = inventory.ID
+	return db.ValidateAndSave(item)
+}
+
+// RecordInventoryDeliveryException saves the condition codes, notes and whether the item is missing
+// as set on the item at delivery
+func RecordInventoryDeliveryException(db *pop.Connection, shipment Shipment, inventory *ShipmentInventory, item *ShipmentInventoryItem) (*validate.Errors, error) {
+	if item.ShipmentInventoryID != inventory.ID {
+		return validate.NewErrors(), ErrFetchNotFound
+	}
+	if !inventory.DeliveryIsOpen(shipment) {
+		return validate.NewErrors(), errors.Wrap(ErrInvalidTransition, "RecordInventoryDeliveryException outside of delivery")
+	}
+	return db.ValidateAndUpdate(item)
+}
+
+// SignShipmentInventory adds the signature to the inventory. Each of the service member and the TSP
+// signs once at pickup, and once at delivery after the inventory was signed at pickup.
+func SignShipmentInventory(db *pop.Connection, shipment Shipment, inventory *ShipmentInventory, signature *ShipmentInventorySignature, now time.Time) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+
+	var open bool
+	switch signature.Stage {
+	case InventoryStagePICKUP:
+		open = inventory.Signature(signature.Stage, signature.SignerRole) == nil &&
+			(inventory.hasSignatureAt(InventoryStagePICKUP) || inventory.PickupIsOpen(shipment))
+	case InventoryStageDELIVERY:
+		open = inventory.Signature(signature.Stage, signature.SignerRole) == nil &&
+			(inventory.hasSignatureAt(InventoryStageDELIVERY) || inventory.DeliveryIsOpen(shipment))
+	}
+	if !open {
+		return responseVErrors, errors.Wrap(ErrInvalidTransition, "SignShipmentInventory")
+	}
+	if len(inventory.Items) == 0 {
+		responseVErrors.Add("items", "An inventory must list at least one item to be signed.")
+		return responseVErrors, nil
+	}
+
+	signature.ShipmentInventoryID = inventory.ID
+	signature.SignedAt = now
+	verrs, err := db.ValidateAndCreate(signature)
+	if err != nil || verrs.HasAny() {
+		return verrs, err
+	}
+	inventory.Signatures = append(inventory.Signatures, *signature)
+	return verrs, nil
+}
+
+// FetchShipmentInventory returns a shipment's inventory with its items and signatures. It performs
+// no authorization.
+func FetchShipmentInventory(db *pop.Connection, shipmentID uuid.UUID) (*ShipmentInventory, error) {
+	var inventories ShipmentInventories
+	err := db.Eager("Items", "Signatures").Where("shipment_id = $1", shipmentID).All(&inventories)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching shipment inventory")
+	}
+	if len(inventories) == 0 {
+		return nil, ErrFetchNotFound
+	}
+	return &inventories[0], nil
+}
@@ -0,0 +1,90 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/go-openapi/swag"
+	"github.com/pkg/errors"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestShipmentInventoryItemValidations() {
+	item := &ShipmentInventoryItem{
+		PickupConditionCodes:   "SC,XX",
+		DeliveryConditionCodes: swag.String("BR"),
+		DeliveryExceptionNotes: swag.String(" "),
+	}
+
+	expErrors := map[string][]string{
+		"shipment_inventory_id":    {"ShipmentInventoryID can not be blank."},
+		"tag_number":               {"0 is not greater than 0."},
+		"description":              {"Description can not be blank."},
+		"pickup_condition_codes":   {"XX is not a valid condition code."},
+		"delivery_exception_notes": {"DeliveryExceptionNotes can not be blank."},
+	}
+
+	suite.verifyValidationErrors(item, expErrors)
+}
+
+func (suite *ModelSuite) TestShipmentInventoryPickupAndDelivery() {
+	now := time.Now()
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{Status: ShipmentStatusAPPROVED},
+	})
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+
+	inventory, verrs, err := CreateShipmentInventory(suite.db, shipment, tsp.ID, "A4417")
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	_, verrs, err = CreateShipmentInventory(suite.db, shipment, tsp.ID, "A4418")
+	suite.Nil(err)
+	suite.True(verrs.HasAny())
+
+	// An empty inventory can't be signed
+	signature := ShipmentInventorySignature{Stage: InventoryStagePICKUP, SignerRole: InventorySignerRoleSERVICEMEMBER, SignerName: "Jane Smith", Signature: "Jane Smith"}
+	verrs, err = SignShipmentInventory(suite.db, shipment, inventory, &signature, now)
+	suite.Nil(err)
+	suite.True(verrs.HasAny())
+
+	dresser := ShipmentInventoryItem{TagNumber: 1, Description: "Dresser, 6 drawer", PickupConditionCodes: "SC"}
+	verrs, err = SaveShipmentInventoryItem(suite.db, shipment, inventory, &dresser)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	inventory, err = FetchShipmentInventory(suite.db, shipment.ID)
+	suite.Nil(err)
+	duplicate := ShipmentInventoryItem{TagNumber: 1, Description: "Carton, books"}
+	verrs, err = SaveShipmentInventoryItem(suite.db, shipment, inventory, &duplicate)
+	suite.Nil(err)
+	suite.True(verrs.HasAny())
+
+	// Exceptions can't be noted before the inventory is signed at pickup
+	dresser.MissingAtDelivery = true
+	_, err = RecordInventoryDeliveryException(suite.db, shipment, inventory, &dresser)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+
+	verrs, err = SignShipmentInventory(suite.db, shipment, inventory, &signature, now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.False(inventory.PickupIsOpen(shipment))
+	_, err = SignShipmentInventory(suite.db, shipment, inventory, &ShipmentInventorySignature{Stage: InventoryStagePICKUP, SignerRole: InventorySignerRoleSERVICEMEMBER, SignerName: "Jane Smith", Signature: "Jane Smith"}, now)
+	suite.Equal(ErrInvalidTransition, errors.Cause(err))
+	verrs, err = SignShipmentInventory(suite.db, shipment, inventory, &ShipmentInventorySignature{Stage: InventoryStagePICKUP, SignerRole: InventorySignerRoleTSP, SignerName: "Sam Driver", Signature: "Sam Driver"}, now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.True(inventory.IsSigned(InventoryStagePICKUP))
+
+	shipment.Status = ShipmentStatusDELIVERED
+	dresser.DeliveryConditionCodes = swag.String("BR")
+	dresser.MissingAtDelivery = false
+	verrs, err = RecordInventoryDeliveryException(suite.db, shipment, inventory, &dresser)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	fetched, err := FetchShipmentInventory(suite.db, shipment.ID)
+	suite.Nil(err)
+	suite.Len(fetched.Items, 1)
+	suite.Equal("BR", *fetched.Items[0].DeliveryConditionCodes)
+	suite.Len(fetched.Signatures, 2)
+}
@@ -0,0 +1,263 @@
+package models
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// SurveyLinkDays is how long after it is sent a survey link can be answered. Shipments delivered
+// longer ago than that aren't surveyed.
+const SurveyLinkDays = 30
+
+// ErrSurveyClosed is returned when a survey that was already answered or has expired is answered
+var ErrSurveyClosed = errors.New("survey is closed")
+
+// ShipmentSurvey is the satisfaction survey a service member is asked to answer about the TSP that
+// delivered their shipment. Its link carries a random token of which only a hash is stored, and it
+// can be answered once.
+type ShipmentSurvey struct {
+	ID                              uuid.UUID       `json:"id" db:"id"`
+	CreatedAt                       time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time       `json:"updated_at" db:"updated_at"`
+	ShipmentID                      uuid.UUID       `json:"shipment_id" db:"shipment_id"`
+	ServiceMemberID                 uuid.UUID       `json:"service_member_id" db:"service_member_id"`
+	TransportationServiceProviderID uuid.UUID       `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	TokenHash                       string          `json:"-" db:"token_hash"`
+	ExpiresAt                       time.Time       `json:"expires_at" db:"expires_at"`
+	CompletedAt                     *time.Time      `json:"completed_at" db:"completed_at"`
+	Responses                       SurveyResponses `has_many:"survey_responses"`
+}
+
+// ShipmentSurveys is not required by pop and may be deleted
+type ShipmentSurveys []ShipmentSurvey
+
+// SurveyResponse is a service member's answer to one survey question: a rating for RATING questions
+// and a comment for TEXT questions
+type SurveyResponse struct {
+	ID               uuid.UUID `json:"id" db:"id"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+	ShipmentSurveyID uuid.UUID `json:"shipment_survey_id" db:"shipment_survey_id"`
+	SurveyQuestionID uuid.UUID `json:"survey_question_id" db:"survey_question_id"`
+	Rating           *int      `json:"rating" db:"rating"`
+	Comment          *string   `json:"comment" db:"comment"`
+}
+
+// SurveyResponses is not required by pop and may be deleted
+type SurveyResponses []SurveyResponse
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *ShipmentSurvey) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: s.ShipmentID, Name: "ShipmentID"},
+		&validators.UUIDIsPresent{Field: s.ServiceMemberID, Name: "ServiceMemberID"},
+		&validators.UUIDIsPresent{Field: s.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.StringIsPresent{Field: s.TokenHash, Name: "TokenHash"},
+		&validators.TimeIsPresent{Field: s.ExpiresAt, Name: "ExpiresAt"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *ShipmentSurvey) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *ShipmentSurvey) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (r *SurveyResponse) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	verrs := validate.Validate(
+		&validators.UUIDIsPresent{Field: r.ShipmentSurveyID, Name: "ShipmentSurveyID"},
+		&validators.UUIDIsPresent{Field: r.SurveyQuestionID, Name: "SurveyQuestionID"},
+	)
+	if r.Rating == nil && r.Comment == nil {
+		verrs.Add("rating", "Either a rating or a comment must be given.")
+	}
+	if r.Rating != nil && (*r.Rating < 1 || *r.Rating > SurveyRatingMax) {
+		verrs.Add("rating", "Rating must be between 1 and 5.")
+	}
+	return verrs, nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (r *SurveyResponse) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (r *SurveyResponse) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// IsOpen returns whether the survey can still be answered
+func (s ShipmentSurvey) IsOpen(now time.Time) bool {
+	return s.CompletedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// hashSurveyToken returns the hash a survey token is stored and looked up by
+func hashSurveyToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateShipmentSurvey creates the survey of a delivered shipment for the TSP that accepted it and
+// returns it with the token its link carries. The token can't be recovered later.
+func CreateShipmentSurvey(db *pop.Connection, shipment Shipment, now time.Time) (*ShipmentSurvey, string, *validate.Errors, error) {
+	if shipment.Status != ShipmentStatusDELIVERED && shipment.Status != ShipmentStatusCOMPLETED {
+		return nil, "", validate.NewErrors(), errors.Wrap(ErrInvalidTransition, "CreateShipmentSurvey on a shipment that wasn't delivered")
+	}
+
+	var offer ShipmentOffer
+	err := db.Where("shipment_id = $1 AND accepted = true", shipment.ID).First(&offer)
+	if err != nil {
+		return nil, "", validate.NewErrors(), errors.Wrap(err, "fetching accepted shipment offer")
+	}
+
+	randomBytes := make([]byte, 32)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return nil, "", validate.NewErrors(), errors.Wrap(err, "generating survey token")
+	}
+	token := base64.RawURLEncoding.EncodeToString(randomBytes)
+
+	survey := ShipmentSurvey{
+		ShipmentID:                      shipment.ID,
+		ServiceMemberID:                 shipment.ServiceMemberID,
+		TransportationServiceProviderID: offer.TransportationServiceProviderID,
+		TokenHash:                       hashSurveyToken(token),
+		ExpiresAt:                       now.AddDate(0, 0, SurveyLinkDays),
+	}
+	verrs, err := db.ValidateAndCreate(&survey)
+	if err != nil || verrs.HasAny() {
+		return nil, "", verrs, err
+	}
+	return &survey, token, verrs, nil
+}
+
+// FetchShipmentSurveyByToken returns the survey a link's token belongs to
+func FetchShipmentSurveyByToken(db *pop.Connection, token string) (*ShipmentSurvey, error) {
+	var survey ShipmentSurvey
+	err := db.Where("token_hash = $1", hashSurveyToken(token)).First(&survey)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &survey, nil
+}
+
+// CompleteShipmentSurvey stores the answers to a survey and closes it. Answering a survey that is
+// closed, including by a concurrent answer, returns ErrSurveyClosed.
+func CompleteShipmentSurvey(db *pop.Connection, survey *ShipmentSurvey, responses SurveyResponses, now time.Time) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		var locked ShipmentSurvey
+		if err := db.RawQuery("SELECT * FROM shipment_surveys WHERE id = $1 FOR UPDATE", survey.ID).First(&locked); err != nil {
+			responseError = errors.Wrap(err, "Error Locking Shipment Survey")
+			return transactionError
+		}
+		if !locked.IsOpen(now) {
+			responseError = ErrSurveyClosed
+			return transactionError
+		}
+
+		for i := range responses {
+			responses[i].ShipmentSurveyID = survey.ID
+			if verrs, err := db.ValidateAndCreate(&responses[i]); verrs.HasAny() || err != nil {
+				responseVErrors.Append(verrs)
+				responseError = errors.Wrap(err, "Error Creating Survey Response")
+				return transactionError
+			}
+		}
+
+		locked.CompletedAt = &now
+		if verrs, err := db.ValidateAndUpdate(&locked); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error Completing Shipment Survey")
+			return transactionError
+		}
+		*survey = locked
+		survey.Responses = responses
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// FetchShipmentsAwaitingSurvey returns the shipments delivered in the last SurveyLinkDays that a TSP
+// accepted and that haven't been surveyed
+func FetchShipmentsAwaitingSurvey(db *pop.Connection, now time.Time) (Shipments, error) {
+	var shipments Shipments
+	err := db.Q().
+		Where("shipments.status IN (?, ?)", ShipmentStatusDELIVERED, ShipmentStatusCOMPLETED).
+		Where("shipments.actual_delivery_date >= ?", now.AddDate(0, 0, -SurveyLinkDays)).
+		Where("EXISTS (SELECT 1 FROM shipment_offers WHERE shipment_offers.shipment_id = shipments.id AND shipment_offers.accepted = true)").
+		Where("NOT EXISTS (SELECT 1 FROM shipment_surveys WHERE shipment_surveys.shipment_id = shipments.id)").
+		Order("shipments.actual_delivery_date").
+		All(&shipments)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching shipments awaiting survey")
+	}
+	return shipments, nil
+}
+
+// TSPSurveyScore is how the service members whose shipments a TSP delivered in a TDL during a period
+// rated it
+type TSPSurveyScore struct {
+	TransportationServiceProviderID uuid.UUID `db:"transportation_service_provider_id"`
+	StandardCarrierAlphaCode        string    `db:"standard_carrier_alpha_code"`
+	TrafficDistributionListID       uuid.UUID `db:"traffic_distribution_list_id"`
+	Surveys                         int       `db:"surveys"`
+	Ratings                         int       `db:"ratings"`
+	AverageRating                   *float64  `db:"average_rating"`
+}
+
+// FetchTSPSurveyScores aggregates the answered surveys of the shipments delivered between start and
+// end, inclusive, per TSP and TDL. Surveys count by when the shipment was delivered, like claims.
+func FetchTSPSurveyScores(db *pop.Connection, start time.Time, end time.Time) ([]TSPSurveyScore, error) {
+	var scores []TSPSurveyScore
+	err := db.RawQuery(`
+		SELECT
+			shipment_surveys.transportation_service_provider_id,
+			transportation_service_providers.standard_carrier_alpha_code,
+			shipments.traffic_distribution_list_id,
+			COUNT(DISTINCT shipment_surveys.id) AS surveys,
+			COUNT(survey_responses.rating) AS ratings,
+			AVG(survey_responses.rating)::float AS average_rating
+		FROM shipment_surveys
+		JOIN shipments ON shipments.id = shipment_surveys.shipment_id
+		JOIN transportation_service_providers ON transportation_service_providers.id = shipment_surveys.transportation_service_provider_id
+		LEFT JOIN survey_responses ON survey_responses.shipment_survey_id = shipment_surveys.id
+		WHERE shipment_surveys.completed_at IS NOT NULL
+		AND shipments.actual_delivery_date::date BETWEEN $1::date AND $2::date
+		GROUP BY shipment_surveys.transportation_service_provider_id,
+			transportation_service_providers.standard_carrier_alpha_code,
+			shipments.traffic_distribution_list_id
+		ORDER BY transportation_service_providers.standard_carrier_alpha_code`, start, end).All(&scores)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching TSP survey scores")
+	}
+	return scores, nil
+}
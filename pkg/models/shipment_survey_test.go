@@ -0,0 +1,107 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestShipmentSurveyIsAnsweredOnce() {
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	now := time.Now()
+	shipmentID := suite.makeAcceptedShipment(tsp, now)
+	question := SurveyQuestion{Prompt: "How did it go?", Kind: SurveyQuestionKindRATING, Active: true}
+	suite.mustSave(&question)
+
+	awaiting, err := FetchShipmentsAwaitingSurvey(suite.db, now)
+	suite.Nil(err)
+	suite.Len(awaiting, 1)
+
+	survey, token, verrs, err := CreateShipmentSurvey(suite.db, awaiting[0], now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(shipmentID, survey.ShipmentID)
+	suite.Equal(tsp.ID, survey.TransportationServiceProviderID)
+	suite.NotEqual(token, survey.TokenHash)
+
+	// Surveyed shipments aren't asked about again
+	awaiting, err = FetchShipmentsAwaitingSurvey(suite.db, now)
+	suite.Nil(err)
+	suite.Empty(awaiting)
+
+	_, err = FetchShipmentSurveyByToken(suite.db, "not-a-token")
+	suite.Equal(ErrFetchNotFound, err)
+	fetched, err := FetchShipmentSurveyByToken(suite.db, token)
+	suite.Nil(err)
+	suite.Equal(survey.ID, fetched.ID)
+
+	invalidRating := SurveyRatingMax + 1
+	verrs, err = CompleteShipmentSurvey(suite.db, fetched, SurveyResponses{
+		{SurveyQuestionID: question.ID, Rating: &invalidRating},
+	}, now)
+	suite.True(verrs.HasAny())
+	suite.True(fetched.IsOpen(now))
+
+	rating := 4
+	verrs, err = CompleteShipmentSurvey(suite.db, fetched, SurveyResponses{
+		{SurveyQuestionID: question.ID, Rating: &rating},
+	}, now)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.False(fetched.IsOpen(now))
+
+	_, err = CompleteShipmentSurvey(suite.db, survey, SurveyResponses{
+		{SurveyQuestionID: question.ID, Rating: &rating},
+	}, now)
+	suite.Equal(ErrSurveyClosed, err)
+}
+
+func (suite *ModelSuite) TestFetchTSPSurveyScores() {
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	deliveryDate := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	question := SurveyQuestion{Prompt: "How did it go?", Kind: SurveyQuestionKindRATING, Active: true}
+	suite.mustSave(&question)
+
+	for _, rating := range []int{2, 5} {
+		shipmentID := suite.makeAcceptedShipment(tsp, deliveryDate)
+		var shipment Shipment
+		suite.Nil(suite.db.Find(&shipment, shipmentID))
+		survey, _, _, err := CreateShipmentSurvey(suite.db, shipment, deliveryDate)
+		suite.Nil(err)
+		rating := rating
+		_, err = CompleteShipmentSurvey(suite.db, survey, SurveyResponses{
+			{SurveyQuestionID: question.ID, Rating: &rating},
+		}, deliveryDate)
+		suite.Nil(err)
+	}
+	// Unanswered surveys don't count
+	suite.makeAcceptedShipment(tsp, deliveryDate)
+
+	start, end := GetPerformancePeriod(deliveryDate)
+	scores, err := FetchTSPSurveyScores(suite.db, start, end)
+	suite.Nil(err)
+	suite.Len(scores, 1)
+	suite.Equal(tsp.ID, scores[0].TransportationServiceProviderID)
+	suite.Equal(2, scores[0].Surveys)
+	suite.Equal(2, scores[0].Ratings)
+	suite.Equal(3.5, *scores[0].AverageRating)
+}
+
+func (suite *ModelSuite) makeAcceptedShipment(tsp TransportationServiceProvider, actualDeliveryDate time.Time) uuid.UUID {
+	accepted := true
+	offer := testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{
+			Status:             ShipmentStatusDELIVERED,
+			ActualDeliveryDate: &actualDeliveryDate,
+		},
+		ShipmentOffer: ShipmentOffer{
+			TransportationServiceProviderID: tsp.ID,
+			TransportationServiceProvider:   tsp,
+			Accepted:                        &accepted,
+		},
+	})
+	return offer.ShipmentID
+}
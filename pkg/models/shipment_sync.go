@@ -0,0 +1,124 @@
+package models
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidSyncCursor is returned for sync cursors that weren't returned by a sync
+var ErrInvalidSyncCursor = errors.New("invalid sync cursor")
+
+// ShipmentSyncCursor is the position a TSP system has synced shipments up to: the last change it
+// received. Changes are ordered by the id of the transaction that made them, then by a sequence a
+// trigger on shipments assigns to every insert and update.
+type ShipmentSyncCursor struct {
+	TxID     int64
+	Sequence int64
+}
+
+// String encodes the cursor for TSP systems to send back in their next sync
+func (c ShipmentSyncCursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d.%d", c.TxID, c.Sequence)))
+}
+
+// ParseShipmentSyncCursor decodes a cursor returned by a sync
+func ParseShipmentSyncCursor(s string) (ShipmentSyncCursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return ShipmentSyncCursor{}, ErrInvalidSyncCursor
+	}
+	parts := strings.SplitN(string(decoded), ".", 2)
+	if len(parts) != 2 {
+		return ShipmentSyncCursor{}, ErrInvalidSyncCursor
+	}
+	txID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || txID < 0 {
+		return ShipmentSyncCursor{}, ErrInvalidSyncCursor
+	}
+	sequence, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || sequence < 0 {
+		return ShipmentSyncCursor{}, ErrInvalidSyncCursor
+	}
+	return ShipmentSyncCursor{TxID: txID, Sequence: sequence}, nil
+}
+
+type shipmentSyncPosition struct {
+	ID       uuid.UUID `db:"id"`
+	TxID     int64     `db:"sync_txid"`
+	Sequence int64     `db:"sync_sequence"`
+}
+
+// Only changes made by transactions older than every transaction still running are synced. Any change
+// committed later belongs to a newer transaction, so it can't land behind a cursor already handed out.
+const shipmentSyncPositionsQuery = `
+	SELECT shipments.id, shipments.sync_txid, shipments.sync_sequence
+	FROM shipments
+	WHERE EXISTS (
+		SELECT 1 FROM shipment_offers
+		WHERE shipment_offers.shipment_id = shipments.id
+		AND shipment_offers.transportation_service_provider_id = $1
+	)
+	AND (shipments.sync_txid, shipments.sync_sequence) > ($2, $3)
+	AND shipments.sync_txid < txid_snapshot_xmin(txid_current_snapshot())
+	AND shipments.updated_at >= $4
+	ORDER BY shipments.sync_txid, shipments.sync_sequence
+	LIMIT $5
+`
+
+// FetchShipmentsUpdatedForTSP returns up to limit of the shipments offered to a TSP that changed after
+// the cursor, in the order they changed, along with the cursor to continue from and whether there are
+// more. The zero cursor starts from the first shipment, skipping those last updated before since.
+func FetchShipmentsUpdatedForTSP(db *pop.Connection, tspID uuid.UUID, after ShipmentSyncCursor, since time.Time, limit int) (Shipments, ShipmentSyncCursor, bool, error) {
+	positions := []shipmentSyncPosition{}
+	// Fetch one more than asked for to tell whether there are more
+	err := db.RawQuery(shipmentSyncPositionsQuery, tspID, after.TxID, after.Sequence, since, limit+1).All(&positions)
+	if err != nil {
+		return nil, after, false, errors.Wrap(err, "fetching updated shipment positions")
+	}
+	hasMore := len(positions) > limit
+	if hasMore {
+		positions = positions[:limit]
+	}
+	if len(positions) == 0 {
+		return Shipments{}, after, false, nil
+	}
+
+	ids := make([]interface{}, len(positions))
+	for i, position := range positions {
+		ids[i] = position.ID
+	}
+	var fetched Shipments
+	err = db.Q().Eager(
+		"TrafficDistributionList",
+		"ServiceMember",
+		"Move",
+		"PickupAddress",
+		"SecondaryPickupAddress",
+		"DeliveryAddress",
+		"PartialSITDeliveryAddress").
+		Where("shipments.id in (?)", ids...).
+		All(&fetched)
+	if err != nil {
+		return nil, after, false, errors.Wrap(err, "fetching updated shipments")
+	}
+
+	byID := make(map[uuid.UUID]Shipment, len(fetched))
+	for _, shipment := range fetched {
+		byID[shipment.ID] = shipment
+	}
+	shipments := make(Shipments, 0, len(positions))
+	for _, position := range positions {
+		if shipment, ok := byID[position.ID]; ok {
+			shipments = append(shipments, shipment)
+		}
+	}
+	last := positions[len(positions)-1]
+	return shipments, ShipmentSyncCursor{TxID: last.TxID, Sequence: last.Sequence}, hasMore, nil
+}
@@ -148,6 +148,32 @@ func (suite *ModelSuite) TestSetBookDateWhenSubmitted() {
 	suite.NotNil(shipment.BookDate)
 }
 
+func (suite *ModelSuite) TestShipmentOriginalDatesSkipDutyStationClosures() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	serviceMember := shipment.ServiceMember
+	suite.NotNil(serviceMember.DutyStationID)
+
+	// The service member's duty station is closed on the Monday before pickup
+	closure := time.Date(2018, 12, 17, 0, 0, 0, 0, time.UTC)
+	suite.mustSave(&CalendarClosure{DutyStationID: serviceMember.DutyStationID, ClosureDate: closure, Reason: "Holiday block leave"})
+
+	pickupDate := time.Date(2018, 12, 18, 0, 0, 0, 0, time.UTC)
+	packDays := int64(1)
+	transitDays := int64(1)
+	shipment.RequestedPickupDate = &pickupDate
+	shipment.EstimatedPackDays = &packDays
+	shipment.EstimatedTransitDays = &transitDays
+	shipment.OriginalPackDate = nil
+	shipment.OriginalDeliveryDate = nil
+	suite.mustSave(&shipment)
+
+	// Packing moves back to the Friday
+	if suite.NotNil(shipment.OriginalPackDate) {
+		suite.Equal(time.Date(2018, 12, 14, 0, 0, 0, 0, time.UTC), *shipment.OriginalPackDate)
+	}
+	suite.NotNil(shipment.OriginalDeliveryDate)
+}
+
 // TestAcceptShipmentForTSP tests that a shipment and shipment offer is correctly accepted
 func (suite *ModelSuite) TestAcceptShipmentForTSP() {
 	numTspUsers := 1
@@ -165,7 +191,7 @@ func (suite *ModelSuite) TestAcceptShipmentForTSP() {
 	suite.Nil(shipmentOffer.Accepted)
 	suite.Nil(shipmentOffer.RejectionReason)
 
-	newShipment, newShipmentOffer, _, err := AcceptShipmentForTSP(suite.db, tspUser.TransportationServiceProviderID, shipment.ID)
+	newShipment, newShipmentOffer, _, err := AcceptShipmentForTSP(suite.db, nil, tspUser.TransportationServiceProviderID, shipment.ID)
 	suite.NoError(err)
 
 	suite.Equal(ShipmentStatusACCEPTED, newShipment.Status, "expected Awarded")
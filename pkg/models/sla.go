@@ -0,0 +1,306 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/dates"
+)
+
+// SLAPolicy is how many business days a record may stay in a status before it is overdue
+type SLAPolicy struct {
+	ID           uuid.UUID `json:"id" db:"id"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+	RecordType   string    `json:"record_type" db:"record_type"`
+	Status       string    `json:"status" db:"status"`
+	BusinessDays int       `json:"business_days" db:"business_days"`
+}
+
+// SLAPolicies is not required by pop and may be deleted
+type SLAPolicies []SLAPolicy
+
+// slaRecordTables are the tables of the records SLAs can be tracked for, and the column tying each to its move
+var slaRecordTables = map[string]struct {
+	table        string
+	moveIDColumn string
+}{
+	"move":                     {"moves", "records.id"},
+	"personally_procured_move": {"personally_procured_moves", "records.move_id"},
+	"move_document":            {"move_documents", "records.move_id"},
+}
+
+// SLARecordTypes are the kinds of records SLAs can be tracked for
+var SLARecordTypes = []string{"move", "personally_procured_move", "move_document"}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (p *SLAPolicy) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringInclusion{Field: p.RecordType, Name: "RecordType", List: SLARecordTypes},
+		&validators.StringIsPresent{Field: p.Status, Name: "Status"},
+		&validators.IntIsGreaterThan{Field: p.BusinessDays, Name: "BusinessDays", Compared: 0},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (p *SLAPolicy) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (p *SLAPolicy) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchSLAPolicies returns every SLA policy ordered by record type and status
+func FetchSLAPolicies(db *pop.Connection) (SLAPolicies, error) {
+	var policies SLAPolicies
+	err := db.Order("record_type asc, status asc").All(&policies)
+	return policies, err
+}
+
+// FetchSLAPolicy returns an SLA policy by ID
+func FetchSLAPolicy(db *pop.Connection, id uuid.UUID) (*SLAPolicy, error) {
+	var policy SLAPolicy
+	err := db.Find(&policy, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// SLAEscalation records that an overdue record was escalated so that it is only escalated once each
+// time it enters a status
+type SLAEscalation struct {
+	ID                     uuid.UUID  `json:"id" db:"id"`
+	CreatedAt              time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time  `json:"updated_at" db:"updated_at"`
+	SLAPolicyID            uuid.UUID  `json:"sla_policy_id" db:"sla_policy_id"`
+	RecordType             string     `json:"record_type" db:"record_type"`
+	RecordID               uuid.UUID  `json:"record_id" db:"record_id"`
+	MoveID                 uuid.UUID  `json:"move_id" db:"move_id"`
+	TransportationOfficeID *uuid.UUID `json:"transportation_office_id" db:"transportation_office_id"`
+	EnteredAt              time.Time  `json:"entered_at" db:"entered_at"`
+	DueAt                  time.Time  `json:"due_at" db:"due_at"`
+}
+
+// SLAEscalations is not required by pop and may be deleted
+type SLAEscalations []SLAEscalation
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *SLAEscalation) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: e.SLAPolicyID, Name: "SLAPolicyID"},
+		&validators.StringInclusion{Field: e.RecordType, Name: "RecordType", List: SLARecordTypes},
+		&validators.UUIDIsPresent{Field: e.RecordID, Name: "RecordID"},
+		&validators.UUIDIsPresent{Field: e.MoveID, Name: "MoveID"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *SLAEscalation) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *SLAEscalation) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// SLAItem is a record waiting in a status that has an SLA policy
+type SLAItem struct {
+	Policy                 SLAPolicy  `db:"-"`
+	RecordID               uuid.UUID  `db:"record_id"`
+	MoveID                 uuid.UUID  `db:"move_id"`
+	TransportationOfficeID *uuid.UUID `db:"transportation_office_id"`
+	EnteredAt              time.Time  `db:"entered_at"`
+	DueAt                  time.Time  `db:"-"`
+}
+
+// IsOverdue returns true if the item is past its due date
+func (i SLAItem) IsOverdue(now time.Time) bool {
+	return now.After(i.DueAt)
+}
+
+// Escalation returns the escalation record for an overdue item
+func (i SLAItem) Escalation() SLAEscalation {
+	return SLAEscalation{
+		SLAPolicyID:            i.Policy.ID,
+		RecordType:             i.Policy.RecordType,
+		RecordID:               i.RecordID,
+		MoveID:                 i.MoveID,
+		TransportationOfficeID: i.TransportationOfficeID,
+		EnteredAt:              i.EnteredAt,
+		DueAt:                  i.DueAt,
+	}
+}
+
+// SLAItemFilter narrows down the SLA items fetched. Empty fields don't filter.
+type SLAItemFilter struct {
+	MoveIDs                []uuid.UUID
+	TransportationOfficeID *uuid.UUID
+}
+
+// A record entered its status when the audit log last recorded the transition. Records that changed
+// before the audit log existed are counted from their creation, which unrelated edits don't move. Moves belong to their origin
+// transportation office, or their destination office if the origin isn't known.
+const slaItemsQuery = `
+	SELECT records.id AS record_id,
+		move_offices.move_id AS move_id,
+		move_offices.transportation_office_id AS transportation_office_id,
+		COALESCE((
+			SELECT MAX(audit_records.created_at) FROM audit_records
+			WHERE audit_records.record_type = ?
+			AND audit_records.record_id = records.id
+			AND audit_records.to_status = records.status
+		), records.created_at) AS entered_at
+	FROM %s AS records
+	JOIN (
+		SELECT moves.id AS move_id, COALESCE(origin_office.id, destination_office.id) AS transportation_office_id
+		%s
+	) AS move_offices ON move_offices.move_id = %s
+	WHERE records.status = ?
+`
+
+// FetchSLAItems returns the records waiting in a status with an SLA policy, earliest due first. Due
+// dates are counted in business days on the calendar of each item's transportation office. It performs
+// no authorization.
+func FetchSLAItems(db *pop.Connection, calendars *dates.CalendarCache, filter SLAItemFilter) ([]SLAItem, error) {
+	policies, err := FetchSLAPolicies(db)
+	if err != nil {
+		return nil, err
+	}
+
+	items := []SLAItem{}
+	for _, policy := range policies {
+		records, ok := slaRecordTables[policy.RecordType]
+		if !ok {
+			continue
+		}
+		query := moveQueueQuery{}
+		if len(filter.MoveIDs) > 0 {
+			moveIDs := make([]string, len(filter.MoveIDs))
+			for i, id := range filter.MoveIDs {
+				moveIDs[i] = id.String()
+			}
+			query.whereIn("move_offices.move_id", moveIDs)
+		}
+		if filter.TransportationOfficeID != nil {
+			query.where("move_offices.transportation_office_id = ?", *filter.TransportationOfficeID)
+		}
+
+		sql := fmt.Sprintf(slaItemsQuery, records.table, officeMoveScopeFrom, records.moveIDColumn)
+		for _, condition := range query.conditions {
+			sql += " AND " + condition
+		}
+		args := append([]interface{}{policy.RecordType, policy.Status}, query.args...)
+
+		var policyItems []SLAItem
+		if err := db.RawQuery(sql, args...).All(&policyItems); err != nil {
+			return nil, errors.Wrapf(err, "fetching SLA items for %s %s", policy.RecordType, policy.Status)
+		}
+		for _, item := range policyItems {
+			calendar, err := calendars.Calendar(TransportationOfficeCalendarScope(item.TransportationOfficeID))
+			if err != nil {
+				return nil, err
+			}
+			item.Policy = policy
+			item.DueAt = dates.AddBusinessDays(item.EnteredAt, policy.BusinessDays, calendar)
+			items = append(items, item)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return items[i].DueAt.Before(items[j].DueAt) })
+	return items, nil
+}
+
+// FetchSLAEscalation returns the escalation of an item, or nil if it hasn't been escalated since it
+// entered its status
+func FetchSLAEscalation(db *pop.Connection, item SLAItem) (*SLAEscalation, error) {
+	var escalations SLAEscalations
+	err := db.Where("record_type = $1 AND record_id = $2 AND entered_at = $3", item.Policy.RecordType, item.RecordID, item.EnteredAt).
+		All(&escalations)
+	if err != nil || len(escalations) == 0 {
+		return nil, err
+	}
+	return &escalations[0], nil
+}
+
+// SLAOfficeMetrics summarizes SLA performance for one transportation office
+type SLAOfficeMetrics struct {
+	TransportationOfficeID   *uuid.UUID
+	TransportationOfficeName string
+	Open                     int
+	Overdue                  int
+	Escalations              int
+}
+
+// FetchSLAMetrics counts the open and overdue SLA items of each transportation office and the
+// escalations made since the given time. Items whose move isn't tied to an office are counted under
+// a nil office ID.
+func FetchSLAMetrics(db *pop.Connection, calendars *dates.CalendarCache, now time.Time, since time.Time) ([]SLAOfficeMetrics, error) {
+	items, err := FetchSLAItems(db, calendars, SLAItemFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	byOffice := map[uuid.UUID]*SLAOfficeMetrics{}
+	metricsFor := func(officeID *uuid.UUID) *SLAOfficeMetrics {
+		key := uuid.Nil
+		if officeID != nil {
+			key = *officeID
+		}
+		if _, ok := byOffice[key]; !ok {
+			byOffice[key] = &SLAOfficeMetrics{TransportationOfficeID: officeID}
+		}
+		return byOffice[key]
+	}
+
+	for _, item := range items {
+		metrics := metricsFor(item.TransportationOfficeID)
+		metrics.Open++
+		if item.IsOverdue(now) {
+			metrics.Overdue++
+		}
+	}
+
+	var escalations SLAEscalations
+	if err := db.Where("created_at >= $1", since).All(&escalations); err != nil {
+		return nil, err
+	}
+	for _, escalation := range escalations {
+		metricsFor(escalation.TransportationOfficeID).Escalations++
+	}
+
+	officeMetrics := []SLAOfficeMetrics{}
+	for officeID, metrics := range byOffice {
+		if officeID != uuid.Nil {
+			office, err := FetchTransportationOffice(db, officeID)
+			if err != nil {
+				return nil, err
+			}
+			metrics.TransportationOfficeName = office.Name
+		}
+		officeMetrics = append(officeMetrics, *metrics)
+	}
+	sort.Slice(officeMetrics, func(i, j int) bool {
+		return officeMetrics[i].TransportationOfficeName < officeMetrics[j].TransportationOfficeName
+	})
+	return officeMetrics, nil
+}
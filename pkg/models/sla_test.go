@@ -0,0 +1,65 @@
+package models_test
+
+import (
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	"github.com/transcom/mymove/pkg/dates"
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestSLAPolicyValidations() {
+	policy := &SLAPolicy{RecordType: "shipment"}
+
+	expErrors := map[string][]string{
+		"record_type":   {"RecordType is not in the list [move, personally_procured_move, move_document]."},
+		"status":        {"Status can not be blank."},
+		"business_days": {"0 is not greater than 0."},
+	}
+
+	suite.verifyValidationErrors(policy, expErrors)
+}
+
+func (suite *ModelSuite) TestFetchSLAItems() {
+	policy := SLAPolicy{RecordType: "move", Status: "SUBMITTED", BusinessDays: 3}
+	suite.mustSave(&policy)
+
+	submitted := testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		Move: Move{Status: MoveStatusSUBMITTED},
+	})
+	testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		Move: Move{Status: MoveStatusAPPROVED},
+	})
+
+	calendars := NewCalendarCache(suite.db)
+	items, err := FetchSLAItems(suite.db, calendars, SLAItemFilter{})
+	suite.Nil(err)
+	suite.Len(items, 1)
+	item := items[0]
+	suite.Equal(submitted.ID, item.RecordID)
+	suite.Equal(submitted.ID, item.MoveID)
+	calendar, err := calendars.Calendar(TransportationOfficeCalendarScope(item.TransportationOfficeID))
+	suite.Nil(err)
+	suite.Equal(dates.AddBusinessDays(item.EnteredAt, 3, calendar), item.DueAt)
+	suite.False(item.IsOverdue(time.Now()))
+	suite.True(item.IsOverdue(time.Now().AddDate(0, 0, 14)))
+
+	escalation, err := FetchSLAEscalation(suite.db, item)
+	suite.Nil(err)
+	suite.Nil(escalation)
+
+	// Editing a record doesn't restart its clock
+	suite.mustSave(&submitted)
+	items, err = FetchSLAItems(suite.db, calendars, SLAItemFilter{})
+	suite.Nil(err)
+	suite.Len(items, 1)
+	suite.True(item.EnteredAt.Equal(items[0].EnteredAt))
+
+	// Filtering by another move leaves nothing
+	otherMove := testdatagen.MakeDefaultMove(suite.db)
+	items, err = FetchSLAItems(suite.db, calendars, SLAItemFilter{MoveIDs: []uuid.UUID{otherMove.ID}})
+	suite.Nil(err)
+	suite.Len(items, 0)
+}
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// SurveyQuestionKind is how a satisfaction survey question is answered
+type SurveyQuestionKind string
+
+const (
+	// SurveyQuestionKindRATING captures enum value "RATING", answered from 1 to SurveyRatingMax
+	SurveyQuestionKindRATING SurveyQuestionKind = "RATING"
+	// SurveyQuestionKindTEXT captures enum value "TEXT", answered with a comment
+	SurveyQuestionKindTEXT SurveyQuestionKind = "TEXT"
+)
+
+// SurveyRatingMax is the best rating a service member can give a RATING question
+const SurveyRatingMax = 5
+
+// SurveyQuestion is a question service members are asked about their move once their shipment is
+// delivered. Deactivated questions are no longer asked but keep the answers already given.
+type SurveyQuestion struct {
+	ID        uuid.UUID          `json:"id" db:"id"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" db:"updated_at"`
+	Prompt    string             `json:"prompt" db:"prompt"`
+	Kind      SurveyQuestionKind `json:"kind" db:"kind"`
+	Position  int                `json:"position" db:"position"`
+	Active    bool               `json:"active" db:"active"`
+}
+
+// SurveyQuestions is not required by pop and may be deleted
+type SurveyQuestions []SurveyQuestion
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (q *SurveyQuestion) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: q.Prompt, Name: "Prompt"},
+		&validators.StringInclusion{Field: string(q.Kind), Name: "Kind", List: []string{
+			string(SurveyQuestionKindRATING),
+			string(SurveyQuestionKindTEXT),
+		}},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (q *SurveyQuestion) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (q *SurveyQuestion) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchSurveyQuestions returns the survey questions in the order they are asked, or only the active
+// ones when activeOnly is set
+func FetchSurveyQuestions(db *pop.Connection, activeOnly bool) (SurveyQuestions, error) {
+	var questions SurveyQuestions
+	query := db.Q()
+	if activeOnly {
+		query = db.Where("active = true")
+	}
+	if err := query.Order("position, created_at").All(&questions); err != nil {
+		return nil, errors.Wrap(err, "fetching survey questions")
+	}
+	return questions, nil
+}
+
+// FetchSurveyQuestion returns a survey question by id
+func FetchSurveyQuestion(db *pop.Connection, id uuid.UUID) (*SurveyQuestion, error) {
+	var question SurveyQuestion
+	err := db.Find(&question, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &question, nil
+}
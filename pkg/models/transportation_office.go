@@ -7,6 +7,7 @@ import (
 	"github.com/gobuffalo/validate"
 	"github.com/gobuffalo/validate/validators"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 )
 
 // TransportationOffice is a PPPO, PPSO or JPPSO. If it is its own shipping office, ShippingOffice will be nil,
@@ -54,3 +55,16 @@ func (t *TransportationOffice) ValidateCreate(tx *pop.Connection) (*validate.Err
 func (t *TransportationOffice) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
 	return validate.NewErrors(), nil
 }
+
+// FetchTransportationOffice returns a transportation office by ID
+func FetchTransportationOffice(db *pop.Connection, id uuid.UUID) (TransportationOffice, error) {
+	var office TransportationOffice
+	err := db.Find(&office, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return TransportationOffice{}, ErrFetchNotFound
+		}
+		return TransportationOffice{}, err
+	}
+	return office, nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/gobuffalo/validate"
 	"github.com/gobuffalo/validate/validators"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -58,3 +59,16 @@ func (t TransportationServiceProvider) MarshalLogObject(encoder zapcore.ObjectEn
 	encoder.AddString("id", t.ID.String())
 	return nil
 }
+
+// FetchTransportationServiceProvider returns a Transportation Service Provider by ID
+func FetchTransportationServiceProvider(db *pop.Connection, id uuid.UUID) (*TransportationServiceProvider, error) {
+	var tsp TransportationServiceProvider
+	err := db.Find(&tsp, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	return &tsp, nil
+}
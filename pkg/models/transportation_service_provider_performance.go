@@ -297,6 +297,38 @@ func GetRateCycle(year int, peak bool) (start time.Time, end time.Time) {
 	return start, end
 }
 
+// GetPerformancePeriod returns the start and end dates, inclusive, of the performance period that
+// contains the given date. Performance periods are defined in DTR 402.
+func GetPerformancePeriod(date time.Time) (start time.Time, end time.Time) {
+	year := date.Year()
+	periodStarts := []time.Time{
+		time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.April, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.May, 15, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.August, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year, time.October, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(year+1, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	day := time.Date(year, date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	for i := len(periodStarts) - 2; i >= 0; i-- {
+		if !day.Before(periodStarts[i]) {
+			return periodStarts[i], periodStarts[i+1].AddDate(0, 0, -1)
+		}
+	}
+	return periodStarts[0], periodStarts[1].AddDate(0, 0, -1)
+}
+
+// FetchTSPPerformancesForPerformancePeriod returns the TSPPs of every TDL for the performance period
+// starting on the given date
+func FetchTSPPerformancesForPerformancePeriod(db *pop.Connection, performancePeriodStart time.Time) (TransportationServiceProviderPerformances, error) {
+	var perfs TransportationServiceProviderPerformances
+	err := db.Where("performance_period_start = ?", performancePeriodStart).
+		Order("traffic_distribution_list_id, transportation_service_provider_id, rate_cycle_start").
+		All(&perfs)
+	return perfs, err
+}
+
 // FetchDiscountRates returns the discount linehaul and SIT rates for the TSP with the highest
 // BVS during the specified date, limited to those TSPs in the channel defined by the
 // originZip and destinationZip.
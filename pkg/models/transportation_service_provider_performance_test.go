@@ -90,6 +90,20 @@ func (suite *ModelSuite) Test_GetRateCycle() {
 	}
 }
 
+func (suite *ModelSuite) Test_GetPerformancePeriod() {
+	start, end := GetPerformancePeriod(testdatagen.DateInsidePerformancePeriod)
+	suite.Equal(testdatagen.PerformancePeriodStart, start)
+	suite.Equal(testdatagen.PerformancePeriodEnd, end)
+
+	start, end = GetPerformancePeriod(time.Date(testdatagen.TestYear, time.December, 31, 15, 0, 0, 0, time.UTC))
+	suite.Equal(time.Date(testdatagen.TestYear, time.October, 1, 0, 0, 0, 0, time.UTC), start)
+	suite.Equal(time.Date(testdatagen.TestYear, time.December, 31, 0, 0, 0, 0, time.UTC), end)
+
+	start, end = GetPerformancePeriod(time.Date(testdatagen.TestYear, time.May, 14, 0, 0, 0, 0, time.UTC))
+	suite.Equal(time.Date(testdatagen.TestYear, time.April, 1, 0, 0, 0, 0, time.UTC), start)
+	suite.Equal(time.Date(testdatagen.TestYear, time.May, 14, 0, 0, 0, 0, time.UTC), end)
+}
+
 func (suite *ModelSuite) Test_IncrementTSPPerformanceOfferCount() {
 	t := suite.T()
 
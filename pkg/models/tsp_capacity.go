@@ -0,0 +1,212 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// TSPCapacity is how many shipments a day a TSP says it can pick up in a TDL over a period
+type TSPCapacity struct {
+	ID                              uuid.UUID               `json:"id" db:"id"`
+	CreatedAt                       time.Time               `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time               `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID uuid.UUID               `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	TrafficDistributionListID       uuid.UUID               `json:"traffic_distribution_list_id" db:"traffic_distribution_list_id"`
+	TrafficDistributionList         TrafficDistributionList `belongs_to:"traffic_distribution_lists"`
+	StartDate                       time.Time               `json:"start_date" db:"start_date"`
+	EndDate                         time.Time               `json:"end_date" db:"end_date"`
+	DailyShipments                  int                     `json:"daily_shipments" db:"daily_shipments"`
+}
+
+// TSPCapacities is not required by pop and may be deleted
+type TSPCapacities []TSPCapacity
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *TSPCapacity) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: c.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.UUIDIsPresent{Field: c.TrafficDistributionListID, Name: "TrafficDistributionListID"},
+		&validators.TimeIsPresent{Field: c.StartDate, Name: "StartDate"},
+		&validators.TimeIsPresent{Field: c.EndDate, Name: "EndDate"},
+		&validators.TimeAfterTime{FirstTime: c.EndDate, FirstName: "EndDate", SecondTime: c.StartDate, SecondName: "StartDate"},
+		&validators.IntIsGreaterThan{Field: c.DailyShipments, Name: "DailyShipments", Compared: 0},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *TSPCapacity) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *TSPCapacity) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchTSPCapacities returns the capacity a TSP has declared, latest period first
+func FetchTSPCapacities(db *pop.Connection, tspID uuid.UUID) (TSPCapacities, error) {
+	var capacities TSPCapacities
+	err := db.Eager("TrafficDistributionList").
+		Where("transportation_service_provider_id = $1", tspID).
+		Order("start_date desc").
+		All(&capacities)
+	return capacities, err
+}
+
+// FetchTSPCapacity returns a capacity if it was declared by the given TSP
+func FetchTSPCapacity(db *pop.Connection, tspID uuid.UUID, id uuid.UUID) (*TSPCapacity, error) {
+	var capacity TSPCapacity
+	err := db.Eager("TrafficDistributionList").Find(&capacity, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if capacity.TransportationServiceProviderID != tspID {
+		return nil, ErrFetchForbidden
+	}
+	return &capacity, nil
+}
+
+// bookedShipmentStatuses are the statuses of shipments a TSP has accepted and not yet picked up
+const bookedShipmentStatuses = "'ACCEPTED', 'APPROVED'"
+
+// TDLDayCapacity is how many pickups the TSPs of a TDL can do on a day and how many are booked
+type TDLDayCapacity struct {
+	Date     time.Time `db:"date"`
+	Capacity int       `db:"capacity"`
+	Booked   int       `db:"booked"`
+}
+
+// IsDeclared returns true if any TSP declared capacity for the day. Days without it aren't constrained.
+func (c TDLDayCapacity) IsDeclared() bool {
+	return c.Capacity > 0
+}
+
+// Remaining returns how many more pickups can be booked on the day
+func (c TDLDayCapacity) Remaining() int {
+	if c.Booked > c.Capacity {
+		return 0
+	}
+	return c.Capacity - c.Booked
+}
+
+// IsFull returns true if the declared capacity of the day is booked
+func (c TDLDayCapacity) IsFull() bool {
+	return c.IsDeclared() && c.Remaining() == 0
+}
+
+const tdlCapacityQuery = `
+	SELECT days.date,
+		COALESCE((
+			SELECT SUM(capacities.daily_shipments)
+			FROM tsp_capacities AS capacities
+			WHERE capacities.traffic_distribution_list_id = $1
+			AND days.date BETWEEN capacities.start_date AND capacities.end_date
+		), 0) AS capacity,
+		(
+			SELECT COUNT(*)
+			FROM shipments
+			WHERE shipments.traffic_distribution_list_id = $1
+			AND shipments.status IN (` + bookedShipmentStatuses + `)
+			AND shipments.requested_pickup_date = days.date
+		) AS booked
+	FROM (SELECT generate_series($2::date, $3::date, '1 day')::date AS date) AS days
+	ORDER BY days.date
+`
+
+// FetchTDLCapacity returns the capacity of a TDL for each day from start to end, inclusive
+func FetchTDLCapacity(db *pop.Connection, tdlID uuid.UUID, start time.Time, end time.Time) ([]TDLDayCapacity, error) {
+	days := []TDLDayCapacity{}
+	if err := db.RawQuery(tdlCapacityQuery, tdlID, start, end).All(&days); err != nil {
+		return nil, errors.Wrap(err, "fetching TDL capacity")
+	}
+	return days, nil
+}
+
+// TDLWeekForecast compares the shipments of submitted moves due to be picked up in a TDL during a week
+// with the capacity TSPs declared for it
+type TDLWeekForecast struct {
+	WeekStart                 time.Time `db:"week_start"`
+	TrafficDistributionListID uuid.UUID `db:"traffic_distribution_list_id"`
+	SourceRateArea            string    `db:"source_rate_area"`
+	DestinationRegion         string    `db:"destination_region"`
+	CodeOfService             string    `db:"code_of_service"`
+	Demand                    int       `db:"demand"`
+	Booked                    int       `db:"booked"`
+	Capacity                  int       `db:"capacity"`
+}
+
+// Shortfall returns how many more shipments are expected than the TSPs can pick up
+func (f TDLWeekForecast) Shortfall() int {
+	if f.Demand < f.Capacity {
+		return 0
+	}
+	return f.Demand - f.Capacity
+}
+
+const capacityForecastQuery = `
+	SELECT * FROM (
+		SELECT weeks.week_start,
+			tdls.id AS traffic_distribution_list_id,
+			tdls.source_rate_area,
+			tdls.destination_region,
+			tdls.code_of_service,
+			(
+				SELECT COUNT(*)
+				FROM shipments
+				JOIN moves ON moves.id = shipments.move_id
+				WHERE shipments.traffic_distribution_list_id = tdls.id
+				AND moves.status IN ('SUBMITTED', 'APPROVED')
+				AND shipments.status IN ('SUBMITTED', 'AWARDED', ` + bookedShipmentStatuses + `)
+				AND shipments.requested_pickup_date >= weeks.week_start
+				AND shipments.requested_pickup_date < weeks.week_start + 7
+			) AS demand,
+			(
+				SELECT COUNT(*)
+				FROM shipments
+				WHERE shipments.traffic_distribution_list_id = tdls.id
+				AND shipments.status IN (` + bookedShipmentStatuses + `)
+				AND shipments.requested_pickup_date >= weeks.week_start
+				AND shipments.requested_pickup_date < weeks.week_start + 7
+			) AS booked,
+			COALESCE((
+				SELECT SUM(capacities.daily_shipments *
+					(LEAST(capacities.end_date, weeks.week_start + 6) - GREATEST(capacities.start_date, weeks.week_start) + 1))
+				FROM tsp_capacities AS capacities
+				WHERE capacities.traffic_distribution_list_id = tdls.id
+				AND capacities.start_date <= weeks.week_start + 6
+				AND capacities.end_date >= weeks.week_start
+			), 0) AS capacity
+		FROM (SELECT generate_series($1::date, $1::date + ($2::integer - 1) * 7, '7 days')::date AS week_start) AS weeks
+		CROSS JOIN traffic_distribution_lists AS tdls
+	) AS forecasts
+	WHERE demand > 0 OR capacity > 0
+	ORDER BY week_start, source_rate_area, destination_region, code_of_service
+`
+
+// FetchCapacityForecast returns the demand and capacity of every TDL with either for the given number
+// of weeks, starting with the week (from Monday) that contains start
+func FetchCapacityForecast(db *pop.Connection, start time.Time, weeks int) ([]TDLWeekForecast, error) {
+	year, month, day := start.Date()
+	monday := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	monday = monday.AddDate(0, 0, -((int(monday.Weekday()) + 6) % 7))
+
+	forecasts := []TDLWeekForecast{}
+	if weeks < 1 {
+		return forecasts, nil
+	}
+	if err := db.RawQuery(capacityForecastQuery, monday, weeks).All(&forecasts); err != nil {
+		return nil, errors.Wrap(err, "fetching capacity forecast")
+	}
+	return forecasts, nil
+}
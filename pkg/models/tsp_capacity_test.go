@@ -0,0 +1,90 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestTSPCapacityValidations() {
+	capacity := &TSPCapacity{}
+
+	expErrors := map[string][]string{
+		"transportation_service_provider_id": {"TransportationServiceProviderID can not be blank."},
+		"traffic_distribution_list_id":       {"TrafficDistributionListID can not be blank."},
+		"start_date":                         {"StartDate can not be blank."},
+		"end_date":                           {"EndDate can not be blank."},
+		"daily_shipments":                    {"0 is not greater than 0."},
+	}
+
+	suite.verifyValidationErrors(capacity, expErrors)
+}
+
+func (suite *ModelSuite) TestFetchTDLCapacity() {
+	monday := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	tuesday := monday.AddDate(0, 0, 1)
+	booked := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: Shipment{
+			Status:              ShipmentStatusACCEPTED,
+			RequestedPickupDate: &monday,
+		},
+	})
+	tdlID := *booked.TrafficDistributionListID
+
+	for _, dailyShipments := range []int{1, 2} {
+		suite.mustSave(&TSPCapacity{
+			TransportationServiceProviderID: testdatagen.MakeDefaultTSP(suite.db).ID,
+			TrafficDistributionListID:       tdlID,
+			StartDate:                       monday,
+			EndDate:                         monday,
+			DailyShipments:                  dailyShipments,
+		})
+	}
+
+	days, err := FetchTDLCapacity(suite.db, tdlID, monday, tuesday)
+	suite.Nil(err)
+	suite.Len(days, 2)
+
+	// Overlapping capacities add up
+	suite.True(monday.Equal(days[0].Date))
+	suite.Equal(3, days[0].Capacity)
+	suite.Equal(1, days[0].Booked)
+	suite.Equal(2, days[0].Remaining())
+	suite.False(days[0].IsFull())
+
+	// Nobody declared anything for Tuesday, so it isn't constrained
+	suite.False(days[1].IsDeclared())
+	suite.False(days[1].IsFull())
+}
+
+func (suite *ModelSuite) TestFetchCapacityForecast() {
+	monday := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	wednesday := monday.AddDate(0, 0, 2)
+	submitted := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Move: Move{Status: MoveStatusSUBMITTED},
+		Shipment: Shipment{
+			Status:              ShipmentStatusSUBMITTED,
+			RequestedPickupDate: &wednesday,
+		},
+	})
+	suite.mustSave(&TSPCapacity{
+		TransportationServiceProviderID: testdatagen.MakeDefaultTSP(suite.db).ID,
+		TrafficDistributionListID:       *submitted.TrafficDistributionListID,
+		StartDate:                       monday.AddDate(0, 0, -10),
+		EndDate:                         monday.AddDate(0, 0, 1),
+		DailyShipments:                  2,
+	})
+
+	forecasts, err := FetchCapacityForecast(suite.db, wednesday, 2)
+	suite.Nil(err)
+	suite.Len(forecasts, 1)
+	forecast := forecasts[0]
+	suite.True(monday.Equal(forecast.WeekStart))
+	suite.Equal(*submitted.TrafficDistributionListID, forecast.TrafficDistributionListID)
+	suite.Equal(1, forecast.Demand)
+	suite.Equal(0, forecast.Booked)
+	// Only Monday and Tuesday of the capacity fall in the week
+	suite.Equal(4, forecast.Capacity)
+	suite.Equal(0, forecast.Shortfall())
+}
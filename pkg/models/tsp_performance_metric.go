@@ -0,0 +1,173 @@
+package models
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// TSPPerformanceMetric is how a TSP performed in a TDL during a performance period, as measured from
+// the shipments it handled in MyMove, and the scores derived from that
+type TSPPerformanceMetric struct {
+	ID                              uuid.UUID `json:"id" db:"id"`
+	CreatedAt                       time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID uuid.UUID `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	TrafficDistributionListID       uuid.UUID `json:"traffic_distribution_list_id" db:"traffic_distribution_list_id"`
+	PerformancePeriodStart          time.Time `json:"performance_period_start" db:"performance_period_start"`
+	PerformancePeriodEnd            time.Time `json:"performance_period_end" db:"performance_period_end"`
+	Offers                          int       `json:"offers" db:"offers"`
+	Rejections                      int       `json:"rejections" db:"rejections"`
+	PickedUp                        int       `json:"picked_up" db:"picked_up"`
+	PickedUpLate                    int       `json:"picked_up_late" db:"picked_up_late"`
+	Delivered                       int       `json:"delivered" db:"delivered"`
+	DeliveredLate                   int       `json:"delivered_late" db:"delivered_late"`
+	Claims                          int       `json:"claims" db:"claims"`
+	Surveys                         int       `json:"surveys" db:"surveys"`
+	AverageRating                   *float64  `json:"average_rating" db:"average_rating"`
+	PerformanceScore                float64   `json:"performance_score" db:"performance_score"`
+	RateScore                       float64   `json:"rate_score" db:"rate_score"`
+	BestValueScore                  float64   `json:"best_value_score" db:"best_value_score"`
+}
+
+// TSPPerformanceMetrics is not required by pop and may be deleted
+type TSPPerformanceMetrics []TSPPerformanceMetric
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (m *TSPPerformanceMetric) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: m.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.UUIDIsPresent{Field: m.TrafficDistributionListID, Name: "TrafficDistributionListID"},
+		&validators.TimeIsBeforeTime{FirstTime: m.PerformancePeriodStart, FirstName: "PerformancePeriodStart",
+			SecondTime: m.PerformancePeriodEnd, SecondName: "PerformancePeriodEnd"},
+		&validators.IntIsGreaterThan{Field: int(m.BestValueScore), Name: "BestValueScore", Compared: -1},
+		&validators.IntIsLessThan{Field: int(m.BestValueScore), Name: "BestValueScore", Compared: 101},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (m *TSPPerformanceMetric) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (m *TSPPerformanceMetric) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// HasShipments returns whether the TSP was offered or moved any shipments in the period
+func (m TSPPerformanceMetric) HasShipments() bool {
+	return m.Offers > 0 || m.PickedUp > 0 || m.Delivered > 0
+}
+
+const tspPerformanceCountsQuery = `
+	SELECT
+		(
+			SELECT COUNT(*)
+			FROM shipment_offers
+			JOIN shipments ON shipments.id = shipment_offers.shipment_id
+			WHERE shipment_offers.transportation_service_provider_id = $1
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipment_offers.created_at::date BETWEEN $3::date AND $4::date
+		) AS offers,
+		(
+			SELECT COUNT(*)
+			FROM shipment_offers
+			JOIN shipments ON shipments.id = shipment_offers.shipment_id
+			WHERE shipment_offers.transportation_service_provider_id = $1
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipment_offers.created_at::date BETWEEN $3::date AND $4::date
+			AND shipment_offers.accepted = false
+		) AS rejections,
+		(
+			SELECT COUNT(*)
+			FROM shipment_offers
+			JOIN shipments ON shipments.id = shipment_offers.shipment_id
+			WHERE shipment_offers.transportation_service_provider_id = $1
+			AND shipment_offers.accepted = true
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipments.actual_pickup_date::date BETWEEN $3::date AND $4::date
+		) AS picked_up,
+		(
+			SELECT COUNT(*)
+			FROM shipment_offers
+			JOIN shipments ON shipments.id = shipment_offers.shipment_id
+			WHERE shipment_offers.transportation_service_provider_id = $1
+			AND shipment_offers.accepted = true
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipments.actual_pickup_date::date BETWEEN $3::date AND $4::date
+			AND shipments.actual_pickup_date::date >
+				COALESCE(shipments.pm_survey_planned_pickup_date, shipments.requested_pickup_date)::date
+		) AS picked_up_late,
+		(
+			SELECT COUNT(*)
+			FROM claims
+			JOIN shipments ON shipments.id = claims.shipment_id
+			JOIN shipment_offers ON shipment_offers.shipment_id = shipments.id
+			WHERE shipment_offers.transportation_service_provider_id = $1
+			AND shipment_offers.accepted = true
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipments.actual_delivery_date::date BETWEEN $3::date AND $4::date
+		) AS claims,
+		(
+			SELECT COUNT(*)
+			FROM shipment_surveys
+			JOIN shipments ON shipments.id = shipment_surveys.shipment_id
+			WHERE shipment_surveys.transportation_service_provider_id = $1
+			AND shipment_surveys.completed_at IS NOT NULL
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipments.actual_delivery_date::date BETWEEN $3::date AND $4::date
+		) AS surveys,
+		(
+			SELECT AVG(survey_responses.rating)::float
+			FROM survey_responses
+			JOIN shipment_surveys ON shipment_surveys.id = survey_responses.shipment_survey_id
+			JOIN shipments ON shipments.id = shipment_surveys.shipment_id
+			WHERE shipment_surveys.transportation_service_provider_id = $1
+			AND shipment_surveys.completed_at IS NOT NULL
+			AND shipments.traffic_distribution_list_id = $2
+			AND shipments.actual_delivery_date::date BETWEEN $3::date AND $4::date
+		) AS average_rating
+`
+
+// FetchTSPPerformanceMetric counts the offers, pickups, deliveries, claims and satisfaction surveys of a
+// TSP in a TDL during a performance period. Offers count by when they were made, pickups and deliveries
+// by when they happened and claims and surveys by when the shipment was delivered. Scores are left for
+// the caller to fill in.
+func FetchTSPPerformanceMetric(db *pop.Connection, tspID uuid.UUID, tdlID uuid.UUID, start time.Time, end time.Time) (TSPPerformanceMetric, error) {
+	metric := TSPPerformanceMetric{
+		TransportationServiceProviderID: tspID,
+		TrafficDistributionListID:       tdlID,
+		PerformancePeriodStart:          start,
+		PerformancePeriodEnd:            end,
+	}
+	if err := db.RawQuery(tspPerformanceCountsQuery, tspID, tdlID, start, end).First(&metric); err != nil {
+		return metric, errors.Wrap(err, "fetching TSP performance counts")
+	}
+
+	deliveries, err := FetchDeliveryPerformance(db, tspID, tdlID, start, end)
+	if err != nil {
+		return metric, err
+	}
+	metric.Delivered = deliveries.Delivered
+	metric.DeliveredLate = deliveries.Late
+	return metric, nil
+}
+
+// FetchTSPPerformanceMetrics returns the metrics recorded for a TDL and performance period, best
+// value first
+func FetchTSPPerformanceMetrics(db *pop.Connection, tdlID uuid.UUID, performancePeriodStart time.Time) (TSPPerformanceMetrics, error) {
+	var metrics TSPPerformanceMetrics
+	err := db.Where("traffic_distribution_list_id = $1", tdlID).
+		Where("performance_period_start = $2", performancePeriodStart).
+		Order("best_value_score desc").
+		All(&metrics)
+	return metrics, err
+}
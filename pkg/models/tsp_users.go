@@ -5,8 +5,11 @@ import (
 	"github.com/gobuffalo/validate"
 	"github.com/gobuffalo/validate/validators"
 	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
 	"strings"
 	"time"
+
+	"github.com/transcom/mymove/pkg/auth"
 )
 
 // TspUser is someone who works for a Transportation Service Provider
@@ -21,6 +24,7 @@ type TspUser struct {
 	Telephone                       string                        `json:"telephone" db:"telephone"`
 	TransportationServiceProviderID uuid.UUID                     `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
 	TransportationServiceProvider   TransportationServiceProvider `belongs_to:"transportation_service_provider"`
+	Deactivated                     bool                          `json:"deactivated" db:"deactivated"`
 	CreatedAt                       time.Time                     `json:"created_at" db:"created_at"`
 	UpdatedAt                       time.Time                     `json:"updated_at" db:"updated_at"`
 }
@@ -58,6 +62,9 @@ func FetchTspUserByID(tx *pop.Connection, id uuid.UUID) (*TspUser, error) {
 	var user TspUser
 	err := tx.Find(&user, id)
 	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
 		return nil, err
 	}
 	return &user, nil
@@ -75,3 +82,65 @@ func FetchTspUserByEmail(tx *pop.Connection, email string) (*TspUser, error) {
 	}
 	return &users[0], nil
 }
+
+// FetchTspUsersForTSP lists the users of a Transportation Service Provider ordered by name
+func FetchTspUsersForTSP(tx *pop.Connection, tspID uuid.UUID) (TspUsers, error) {
+	var users TspUsers
+	err := tx.Where("transportation_service_provider_id = $1", tspID).Order("last_name asc, first_name asc").All(&users)
+	return users, err
+}
+
+// InviteTspUser creates a TSP user who can log in to tsp.move.mil with their login.gov account.
+// If the email already belongs to a user the TSP user is linked to them.
+func InviteTspUser(db *pop.Connection, session *auth.Session, tspUser *TspUser) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	tspUser.Email = strings.ToLower(strings.TrimSpace(tspUser.Email))
+	if _, err := FetchTspUserByEmail(db, tspUser.Email); err == nil {
+		responseVErrors.Add("email", "A TSP user with this email already exists.")
+		return responseVErrors, nil
+	} else if err != ErrFetchNotFound {
+		return responseVErrors, err
+	}
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		var users Users
+		if err := db.Where("login_gov_email = $1", tspUser.Email).All(&users); err != nil {
+			responseError = errors.Wrap(err, "Error looking up user")
+			return transactionError
+		}
+		if len(users) > 0 {
+			tspUser.UserID = &users[0].ID
+		}
+
+		if verrs, err := db.ValidateAndCreate(tspUser); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error creating TSP user")
+			return transactionError
+		}
+
+		if verrs, err := RecordAudit(db, session, nil, tspUser, "invite", ""); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// SetTspUserDeactivated deactivates or reactivates a TSP user. Deactivated TSP users can't log in.
+func SetTspUserDeactivated(db *pop.Connection, session *auth.Session, tspUser *TspUser, deactivated bool) (*validate.Errors, error) {
+	before := *tspUser
+	tspUser.Deactivated = deactivated
+	event := "reactivate"
+	if deactivated {
+		event = "deactivate"
+	}
+	return SaveWithAudit(db, session, &before, tspUser, event, "")
+}
@@ -67,3 +67,35 @@ func (suite *ModelSuite) TestFetchTspUserByEmail() {
 	suite.NotNil(user)
 	suite.Equal(newUser.ID, user.ID)
 }
+
+func (suite *ModelSuite) TestInviteAndDeactivateTspUser() {
+	tsp := CreateTestTsp(suite)
+	tspUser := TspUser{
+		LastName:                        "Invitee",
+		FirstName:                       "Ivan",
+		Email:                           "Ivan.Invitee@example.com",
+		Telephone:                       "907-555-1212",
+		TransportationServiceProviderID: tsp.ID,
+	}
+	verrs, err := InviteTspUser(suite.db, nil, &tspUser)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal("ivan.invitee@example.com", tspUser.Email)
+	suite.Nil(tspUser.UserID)
+
+	users, err := FetchTspUsersForTSP(suite.db, tsp.ID)
+	suite.Nil(err)
+	suite.Len(users, 1)
+
+	verrs, err = SetTspUserDeactivated(suite.db, nil, &tspUser, true)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	reloaded, err := FetchTspUserByID(suite.db, tspUser.ID)
+	suite.Nil(err)
+	suite.True(reloaded.Deactivated)
+
+	records, err := FetchAuditRecords(suite.db, AuditRecordFilter{RecordType: "tsp_user", RecordID: &tspUser.ID})
+	suite.Nil(err)
+	suite.Len(records, 2)
+}
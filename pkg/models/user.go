@@ -83,10 +83,12 @@ type UserIdentity struct {
 	OfficeUserFirstName    *string    `db:"ou_fname"`
 	OfficeUserLastName     *string    `db:"ou_lname"`
 	OfficeUserMiddle       *string    `db:"ou_middle"`
+	OfficeUserDeactivated  *bool      `db:"ou_deactivated"`
 	TspUserID              *uuid.UUID `db:"tu_id"`
 	TspUserFirstName       *string    `db:"tu_fname"`
 	TspUserLastName        *string    `db:"tu_lname"`
 	TspUserMiddle          *string    `db:"tu_middle"`
+	TspUserDeactivated     *bool      `db:"tu_deactivated"`
 }
 
 // FetchUserIdentity queries the database for information about the logged in user
@@ -102,10 +104,12 @@ func FetchUserIdentity(db *pop.Connection, loginGovID string) (*UserIdentity, er
 				ou.first_name as ou_fname,
 				ou.last_name as ou_lname,
 				ou.middle_initials as ou_middle,
+				ou.deactivated as ou_deactivated,
 				tu.id as tu_id,
 				tu.first_name as tu_fname,
 				tu.last_name as tu_lname,
-				tu.middle_initials as tu_middle
+				tu.middle_initials as tu_middle,
+				tu.deactivated as tu_deactivated
 			FROM users
 			LEFT OUTER JOIN service_members as sm on sm.user_id = users.id
 			LEFT OUTER JOIN office_users as ou on ou.user_id = users.id
@@ -171,6 +171,21 @@ func (v *CannotBeTrueIfFalse) IsValid(errors *validate.Errors) {
 	}
 }
 
+// ConditionCodesAreValid validates a comma separated list of inventory condition codes
+type ConditionCodesAreValid struct {
+	Name  string
+	Field string
+}
+
+// IsValid adds an error for each code that isn't a standard inventory condition code
+func (v *ConditionCodesAreValid) IsValid(errors *validate.Errors) {
+	for _, code := range SplitConditionCodes(v.Field) {
+		if _, ok := InventoryConditionCodes[code]; !ok {
+			errors.Add(validators.GenerateKey(v.Name), fmt.Sprintf("%s is not a valid condition code.", code))
+		}
+	}
+}
+
 // ValidateableModel is here simply because `validateable` is private to `pop`
 type ValidateableModel interface {
 	Validate(*pop.Connection) (*validate.Errors, error)
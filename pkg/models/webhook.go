@@ -0,0 +1,318 @@
+package models
+
+import (
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+)
+
+// WebhookEvent is a change TSP systems can be told about through webhooks
+type WebhookEvent string
+
+const (
+	// WebhookEventShipmentOffered captures enum value "shipment.offered"
+	WebhookEventShipmentOffered WebhookEvent = "shipment.offered"
+	// WebhookEventShipmentAccepted captures enum value "shipment.accepted"
+	WebhookEventShipmentAccepted WebhookEvent = "shipment.accepted"
+	// WebhookEventShipmentApproved captures enum value "shipment.approved"
+	WebhookEventShipmentApproved WebhookEvent = "shipment.approved"
+	// WebhookEventLineItemApproved captures enum value "line_item.approved"
+	WebhookEventLineItemApproved WebhookEvent = "line_item.approved"
+	// WebhookEventInvoiceStatusChanged captures enum value "invoice.status_changed"
+	WebhookEventInvoiceStatusChanged WebhookEvent = "invoice.status_changed"
+)
+
+// WebhookEvents are every event webhooks can be registered for
+var WebhookEvents = []WebhookEvent{
+	WebhookEventShipmentOffered,
+	WebhookEventShipmentAccepted,
+	WebhookEventShipmentApproved,
+	WebhookEventLineItemApproved,
+	WebhookEventInvoiceStatusChanged,
+}
+
+// WebhookDeliveryStatus is where a webhook delivery is in being sent
+type WebhookDeliveryStatus string
+
+const (
+	// WebhookDeliveryStatusPENDING captures enum value "PENDING", waiting for its next attempt
+	WebhookDeliveryStatusPENDING WebhookDeliveryStatus = "PENDING"
+	// WebhookDeliveryStatusDELIVERED captures enum value "DELIVERED"
+	WebhookDeliveryStatusDELIVERED WebhookDeliveryStatus = "DELIVERED"
+	// WebhookDeliveryStatusFAILED captures enum value "FAILED", when every attempt failed
+	WebhookDeliveryStatusFAILED WebhookDeliveryStatus = "FAILED"
+)
+
+// WebhookSubscription is a URL a TSP registered to receive an event at. Deliveries are signed with
+// its secret.
+type WebhookSubscription struct {
+	ID                              uuid.UUID    `json:"id" db:"id"`
+	CreatedAt                       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time    `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID uuid.UUID    `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	Event                           WebhookEvent `json:"event" db:"event"`
+	URL                             string       `json:"url" db:"url"`
+	Secret                          string       `json:"-" db:"secret"`
+}
+
+// WebhookSubscriptions is not required by pop and may be deleted
+type WebhookSubscriptions []WebhookSubscription
+
+// WebhookDelivery is an event queued to be posted to a webhook. Its payload is fixed when it is queued
+// so that every attempt sends the same body.
+type WebhookDelivery struct {
+	ID                    uuid.UUID             `json:"id" db:"id"`
+	CreatedAt             time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt             time.Time             `json:"updated_at" db:"updated_at"`
+	WebhookSubscriptionID uuid.UUID             `json:"webhook_subscription_id" db:"webhook_subscription_id"`
+	WebhookSubscription   WebhookSubscription   `belongs_to:"webhook_subscriptions"`
+	Event                 WebhookEvent          `json:"event" db:"event"`
+	Payload               string                `json:"payload" db:"payload"`
+	Status                WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts              int                   `json:"attempts" db:"attempts"`
+	NextAttemptAt         time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	DeliveredAt           *time.Time            `json:"delivered_at" db:"delivered_at"`
+	LastResponseCode      *int                  `json:"last_response_code" db:"last_response_code"`
+	LastError             *string               `json:"last_error" db:"last_error"`
+}
+
+// WebhookDeliveries is not required by pop and may be deleted
+type WebhookDeliveries []WebhookDelivery
+
+// WebhookOutboxEvent is an event recorded when it happens, to be queued as deliveries to the webhooks
+// registered for it. When no TSP is given, the event goes to the TSP that accepted its shipment. Events
+// that can't be queued keep the error and are retried until QueuedAt is set.
+type WebhookOutboxEvent struct {
+	ID                              uuid.UUID    `json:"id" db:"id"`
+	CreatedAt                       time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt                       time.Time    `json:"updated_at" db:"updated_at"`
+	TransportationServiceProviderID *uuid.UUID   `json:"transportation_service_provider_id" db:"transportation_service_provider_id"`
+	Event                           WebhookEvent `json:"event" db:"event"`
+	Data                            string       `json:"data" db:"data"`
+	Attempts                        int          `json:"attempts" db:"attempts"`
+	NextAttemptAt                   time.Time    `json:"next_attempt_at" db:"next_attempt_at"`
+	QueuedAt                        *time.Time   `json:"queued_at" db:"queued_at"`
+	LastError                       *string      `json:"last_error" db:"last_error"`
+}
+
+// WebhookOutboxEvents is not required by pop and may be deleted
+type WebhookOutboxEvents []WebhookOutboxEvent
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (s *WebhookSubscription) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	events := make([]string, len(WebhookEvents))
+	for i, event := range WebhookEvents {
+		events[i] = string(event)
+	}
+	verrs := validate.Validate(
+		&validators.UUIDIsPresent{Field: s.TransportationServiceProviderID, Name: "TransportationServiceProviderID"},
+		&validators.StringInclusion{Field: string(s.Event), Name: "Event", List: events},
+		&validators.StringIsPresent{Field: s.Secret, Name: "Secret"},
+	)
+	// Payloads describe shipments, so they are only sent over TLS, and only to hosts on the internet so
+	// that TSPs can't have the dispatcher post to our own network
+	u, err := url.Parse(s.URL)
+	if AllowPrivateWebhookHosts {
+		if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+			verrs.Add("url", "URL must be an absolute http or https URL.")
+		}
+	} else if err != nil || u.Host == "" || u.Scheme != "https" {
+		verrs.Add("url", "URL must be an absolute https URL.")
+	} else if !isPublicHost(u.Hostname()) {
+		verrs.Add("url", "URL must be on a public host.")
+	}
+	return verrs, nil
+}
+
+// AllowPrivateWebhookHosts lets webhooks be registered on plain http and on this machine or a private
+// network, and lets deliveries be posted to them, so that a receiver can be run locally. It is only
+// for development; set it with webhooks.AllowPrivateHosts, which refuses to outside development and test.
+var AllowPrivateWebhookHosts bool
+
+// nonPublicNetworks are the private, shared and benchmarking ranges that aren't reachable over the
+// internet, beyond those the net package recognizes
+var nonPublicNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"fc00::/7",
+)
+
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks[i] = network
+	}
+	return networks
+}
+
+// IsPublicIP returns whether an address is on the internet rather than this machine, a link such as
+// the one cloud metadata services are on, or a private network
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPublicHost returns whether a host could be on the internet. Names are only checked for being local;
+// the addresses they resolve to are checked when deliveries are sent.
+func isPublicHost(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		return IsPublicIP(ip)
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if !strings.Contains(host, ".") {
+		return false
+	}
+	for _, suffix := range []string{".localhost", ".local", ".internal"} {
+		if strings.HasSuffix(host, suffix) {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (s *WebhookSubscription) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (s *WebhookSubscription) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (d *WebhookDelivery) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: d.WebhookSubscriptionID, Name: "WebhookSubscriptionID"},
+		&validators.StringIsPresent{Field: string(d.Event), Name: "Event"},
+		&validators.StringIsPresent{Field: d.Payload, Name: "Payload"},
+		&validators.StringInclusion{Field: string(d.Status), Name: "Status", List: []string{
+			string(WebhookDeliveryStatusPENDING),
+			string(WebhookDeliveryStatusDELIVERED),
+			string(WebhookDeliveryStatusFAILED),
+		}},
+		&validators.TimeIsPresent{Field: d.NextAttemptAt, Name: "NextAttemptAt"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (d *WebhookDelivery) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (d *WebhookDelivery) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (e *WebhookOutboxEvent) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringIsPresent{Field: string(e.Event), Name: "Event"},
+		&validators.StringIsPresent{Field: e.Data, Name: "Data"},
+		&validators.TimeIsPresent{Field: e.NextAttemptAt, Name: "NextAttemptAt"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (e *WebhookOutboxEvent) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (e *WebhookOutboxEvent) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// FetchWebhookSubscriptions returns the webhooks a TSP registered, or only those for one event when
+// event isn't empty
+func FetchWebhookSubscriptions(db *pop.Connection, tspID uuid.UUID, event WebhookEvent) (WebhookSubscriptions, error) {
+	var subscriptions WebhookSubscriptions
+	query := db.Where("transportation_service_provider_id = ?", tspID)
+	if event != "" {
+		query = query.Where("event = ?", event)
+	}
+	if err := query.Order("created_at").All(&subscriptions); err != nil {
+		return nil, errors.Wrap(err, "fetching webhook subscriptions")
+	}
+	return subscriptions, nil
+}
+
+// FetchWebhookSubscription returns a webhook if the TSP registered it
+func FetchWebhookSubscription(db *pop.Connection, tspID uuid.UUID, id uuid.UUID) (*WebhookSubscription, error) {
+	var subscription WebhookSubscription
+	err := db.Find(&subscription, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if subscription.TransportationServiceProviderID != tspID {
+		return nil, ErrFetchForbidden
+	}
+	return &subscription, nil
+}
+
+// FetchDueWebhookDeliveries returns up to limit pending deliveries whose next attempt is due, oldest
+// first, with their subscriptions
+func FetchDueWebhookDeliveries(db *pop.Connection, now time.Time, limit int) (WebhookDeliveries, error) {
+	var deliveries WebhookDeliveries
+	err := db.Eager("WebhookSubscription").
+		Where("status = ?", WebhookDeliveryStatusPENDING).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		All(&deliveries)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching due webhook deliveries")
+	}
+	return deliveries, nil
+}
+
+// FetchDueWebhookOutboxEvents returns up to limit events that haven't been queued, have attempts
+// left and whose next attempt is due, oldest first
+func FetchDueWebhookOutboxEvents(db *pop.Connection, now time.Time, maxAttempts int, limit int) (WebhookOutboxEvents, error) {
+	var events WebhookOutboxEvents
+	err := db.Where("queued_at IS NULL").
+		Where("attempts < ?", maxAttempts).
+		Where("next_attempt_at <= ?", now).
+		Order("created_at").
+		Limit(limit).
+		All(&events)
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching due webhook outbox events")
+	}
+	return events, nil
+}
@@ -0,0 +1,134 @@
+package models_test
+
+import (
+	"net"
+	"time"
+
+	"github.com/gofrs/uuid"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestWebhookSubscriptionValidation() {
+	subscription := &WebhookSubscription{URL: "http://tsp.example.com/events"}
+	expErrors := map[string][]string{
+		"transportation_service_provider_id": {"TransportationServiceProviderID can not be blank."},
+		"event":                              {"Event is not in the list [shipment.offered, shipment.accepted, shipment.approved, line_item.approved, invoice.status_changed]."},
+		"secret":                             {"Secret can not be blank."},
+		"url":                                {"URL must be an absolute https URL."},
+	}
+	suite.verifyValidationErrors(subscription, expErrors)
+
+	subscription = &WebhookSubscription{
+		TransportationServiceProviderID: uuid.Must(uuid.NewV4()),
+		Event:                           WebhookEventShipmentOffered,
+		URL:                             "https://tsp.example.com/events",
+		Secret:                          "s3cret",
+	}
+	suite.verifyValidationErrors(subscription, map[string][]string{})
+
+	// Deliveries aren't posted to this machine or private networks
+	for _, url := range []string{
+		"https://localhost:9000/",
+		"https://127.0.0.1/",
+		"https://[::1]/",
+		"https://169.254.169.254/latest/meta-data/",
+		"https://10.0.0.5/",
+		"https://192.168.1.10/",
+		"https://db.internal/",
+		"https://intranet/",
+	} {
+		subscription.URL = url
+		suite.verifyValidationErrors(subscription, map[string][]string{
+			"url": {"URL must be on a public host."},
+		})
+	}
+}
+
+func (suite *ModelSuite) TestWebhookSubscriptionValidationAllowingPrivateHosts() {
+	AllowPrivateWebhookHosts = true
+	defer func() { AllowPrivateWebhookHosts = false }()
+
+	subscription := &WebhookSubscription{
+		TransportationServiceProviderID: uuid.Must(uuid.NewV4()),
+		Event:                           WebhookEventShipmentOffered,
+		Secret:                          "s3cret",
+	}
+	for _, url := range []string{"http://localhost:9000/", "https://10.0.0.5/"} {
+		subscription.URL = url
+		suite.verifyValidationErrors(subscription, map[string][]string{})
+	}
+
+	subscription.URL = "ftp://localhost/"
+	suite.verifyValidationErrors(subscription, map[string][]string{
+		"url": {"URL must be an absolute http or https URL."},
+	})
+}
+
+func (suite *ModelSuite) TestIsPublicIP() {
+	suite.True(IsPublicIP(net.ParseIP("93.184.216.34")))
+	suite.True(IsPublicIP(net.ParseIP("2606:2800:220:1:248:1893:25c8:1946")))
+	suite.False(IsPublicIP(net.ParseIP("172.20.0.1")))
+	suite.False(IsPublicIP(net.ParseIP("100.64.0.1")))
+	suite.False(IsPublicIP(net.ParseIP("::ffff:127.0.0.1")))
+	suite.False(IsPublicIP(net.ParseIP("fd00::1")))
+	suite.False(IsPublicIP(net.ParseIP("fe80::1")))
+}
+
+func (suite *ModelSuite) TestShipmentSyncCursorRoundTrips() {
+	cursor := ShipmentSyncCursor{TxID: 5730, Sequence: 12}
+	parsed, err := ParseShipmentSyncCursor(cursor.String())
+	suite.Nil(err)
+	suite.Equal(cursor, parsed)
+
+	_, err = ParseShipmentSyncCursor("not-a-cursor")
+	suite.Equal(ErrInvalidSyncCursor, err)
+}
+
+func (suite *ModelSuite) TestFetchShipmentsUpdatedForTSP() {
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	var offers []ShipmentOffer
+	for i := 0; i < 3; i++ {
+		offers = append(offers, testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+			ShipmentOffer: ShipmentOffer{
+				TransportationServiceProviderID: tsp.ID,
+				TransportationServiceProvider:   tsp,
+			},
+		}))
+	}
+	// Shipments offered to other TSPs aren't synced
+	testdatagen.MakeDefaultShipmentOffer(suite.db)
+
+	shipments, cursor, hasMore, err := FetchShipmentsUpdatedForTSP(suite.db, tsp.ID, ShipmentSyncCursor{}, time.Time{}, 2)
+	suite.Nil(err)
+	suite.Len(shipments, 2)
+	suite.True(hasMore)
+	suite.Equal(offers[0].ShipmentID, shipments[0].ID)
+	suite.Equal(offers[1].ShipmentID, shipments[1].ID)
+
+	shipments, cursor, hasMore, err = FetchShipmentsUpdatedForTSP(suite.db, tsp.ID, cursor, time.Time{}, 2)
+	suite.Nil(err)
+	suite.Len(shipments, 1)
+	suite.False(hasMore)
+	suite.Equal(offers[2].ShipmentID, shipments[0].ID)
+
+	// With nothing new the cursor stays put
+	shipments, next, hasMore, err := FetchShipmentsUpdatedForTSP(suite.db, tsp.ID, cursor, time.Time{}, 2)
+	suite.Nil(err)
+	suite.Empty(shipments)
+	suite.False(hasMore)
+	suite.Equal(cursor, next)
+
+	// A changed shipment comes after the cursor, even when its updated_at doesn't
+	suite.Nil(suite.db.RawQuery("UPDATE shipments SET updated_at = $1 WHERE id = $2", time.Now().Add(-time.Hour), offers[0].ShipmentID).Exec())
+	shipments, _, _, err = FetchShipmentsUpdatedForTSP(suite.db, tsp.ID, cursor, time.Time{}, 2)
+	suite.Nil(err)
+	suite.Len(shipments, 1)
+	suite.Equal(offers[0].ShipmentID, shipments[0].ID)
+
+	// Shipments last updated before since are skipped
+	shipments, _, _, err = FetchShipmentsUpdatedForTSP(suite.db, tsp.ID, ShipmentSyncCursor{}, time.Now().Add(-time.Minute), 3)
+	suite.Nil(err)
+	suite.Len(shipments, 2)
+}
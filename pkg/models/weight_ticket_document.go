@@ -0,0 +1,233 @@
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// IsWeightTicketModelDocumentType determines whether a MoveDocumentType is associated with a WeightTicketDocument
+func IsWeightTicketModelDocumentType(docType MoveDocumentType) bool {
+	return docType == MoveDocumentTypeWEIGHTTICKET
+}
+
+// WeightTicketDocument records the empty and full weights of one trip of a PPM
+type WeightTicketDocument struct {
+	ID             uuid.UUID    `json:"id" db:"id"`
+	MoveDocumentID uuid.UUID    `json:"move_document_id" db:"move_document_id"`
+	MoveDocument   MoveDocument `belongs_to:"move_documents"`
+	TripNumber     int          `json:"trip_number" db:"trip_number"`
+	PPMTripID      *uuid.UUID   `json:"ppm_trip_id" db:"ppm_trip_id"`
+	EmptyWeight    unit.Pound   `json:"empty_weight" db:"empty_weight"`
+	FullWeight     unit.Pound   `json:"full_weight" db:"full_weight"`
+	CreatedAt      time.Time    `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at" db:"updated_at"`
+}
+
+// WeightTicketDocuments is not required by pop and may be deleted
+type WeightTicketDocuments []WeightTicketDocument
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (w *WeightTicketDocument) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.UUIDIsPresent{Field: w.MoveDocumentID, Name: "MoveDocumentID"},
+		&validators.IntIsGreaterThan{Field: w.TripNumber, Name: "TripNumber", Compared: 0},
+		&validators.IntIsGreaterThan{Field: w.EmptyWeight.Int(), Name: "EmptyWeight", Compared: 0},
+		&validators.IntIsGreaterThan{Field: w.FullWeight.Int(), Name: "FullWeight", Compared: w.EmptyWeight.Int()},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (w *WeightTicketDocument) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (w *WeightTicketDocument) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// NetWeight is the weight moved on the trip
+func (w WeightTicketDocument) NetWeight() unit.Pound {
+	return w.FullWeight - w.EmptyWeight
+}
+
+// CreateWeightTicketDocument creates a weight ticket document associated to a move, PPM and move document.
+// For PPMs moved in several trips, the ticket is linked to the PPM's trip with the trip number.
+func (m Move) CreateWeightTicketDocument(
+	db *pop.Connection,
+	uploads Uploads,
+	personallyProcuredMoveID uuid.UUID,
+	title string,
+	notes *string,
+	tripNumber int,
+	emptyWeight unit.Pound,
+	fullWeight unit.Pound) (*WeightTicketDocument, *validate.Errors, error) {
+
+	var newWeightTicketDocument *WeightTicketDocument
+	var responseError error
+	responseVErrors := validate.NewErrors()
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("Rollback The transaction")
+
+		var newMoveDocument *MoveDocument
+		newMoveDocument, responseVErrors, responseError = m.createMoveDocumentWithoutTransaction(
+			db,
+			uploads,
+			&personallyProcuredMoveID,
+			MoveDocumentTypeWEIGHTTICKET,
+			title,
+			notes,
+			SelectedMoveTypePPM)
+		if responseVErrors.HasAny() || responseError != nil {
+			return transactionError
+		}
+
+		trips, err := FetchPPMTrips(db, personallyProcuredMoveID)
+		if err != nil {
+			responseError = err
+			return transactionError
+		}
+		var ppmTripID *uuid.UUID
+		for _, trip := range trips {
+			if trip.TripNumber == tripNumber {
+				id := trip.ID
+				ppmTripID = &id
+			}
+		}
+		if len(trips) > 0 && ppmTripID == nil {
+			responseVErrors.Add("trip_number", fmt.Sprintf("The PPM has no trip %d.", tripNumber))
+			return transactionError
+		}
+
+		newWeightTicketDocument = &WeightTicketDocument{
+			MoveDocumentID: newMoveDocument.ID,
+			MoveDocument:   *newMoveDocument,
+			TripNumber:     tripNumber,
+			PPMTripID:      ppmTripID,
+			EmptyWeight:    emptyWeight,
+			FullWeight:     fullWeight,
+		}
+		verrs, err := db.ValidateAndCreate(newWeightTicketDocument)
+		if err != nil || verrs.HasAny() {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error creating weight ticket document")
+			newWeightTicketDocument = nil
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return newWeightTicketDocument, responseVErrors, responseError
+}
+
+// FetchWeightTicketDocuments returns the weight tickets of a PPM that haven't been rejected, in trip
+// order. It performs no authorization.
+func FetchWeightTicketDocuments(db *pop.Connection, ppmID uuid.UUID) (WeightTicketDocuments, error) {
+	var weightTickets WeightTicketDocuments
+	err := db.Q().Eager("MoveDocument").
+		Join("move_documents", "move_documents.id = weight_ticket_documents.move_document_id").
+		Where("move_documents.personally_procured_move_id = $1", ppmID).
+		Where("move_documents.status != $2", MoveDocumentStatusHASISSUE).
+		Order("weight_ticket_documents.trip_number asc").
+		All(&weightTickets)
+	return weightTickets, err
+}
+
+// ComputePPMNetWeight totals the net weight of a PPM's weight tickets and checks it against the
+// service member's weight entitlement, less the weight of any household goods shipments on the
+// same move. Trips must be numbered from 1 without gaps. PPMs moved in several trips need a weight
+// ticket linked to each of their trips instead. If requireApproved is set, every weight ticket must
+// have been reviewed and marked OK by the office. The PPM must have been fetched with its
+// Move.Orders.ServiceMember.
+func ComputePPMNetWeight(db *pop.Connection, ppm *PersonallyProcuredMove, requireApproved bool) (unit.Pound, *validate.Errors, error) {
+	verrs := validate.NewErrors()
+
+	weightTickets, err := FetchWeightTicketDocuments(db, ppm.ID)
+	if err != nil {
+		return 0, verrs, err
+	}
+	if len(weightTickets) == 0 {
+		verrs.Add("weight_tickets", "At least one weight ticket is required.")
+		return 0, verrs, nil
+	}
+
+	trips, err := FetchPPMTrips(db, ppm.ID)
+	if err != nil {
+		return 0, verrs, err
+	}
+	if len(trips) == 0 {
+		for i, weightTicket := range weightTickets {
+			if weightTicket.TripNumber != i+1 {
+				verrs.Add("weight_tickets", fmt.Sprintf("Expected a weight ticket for trip %d.", i+1))
+				return 0, verrs, nil
+			}
+		}
+	} else {
+		tripNetWeights, err := FetchPPMTripNetWeights(db, ppm.ID)
+		if err != nil {
+			return 0, verrs, err
+		}
+		for _, trip := range trips {
+			if _, ok := tripNetWeights[trip.ID]; !ok {
+				verrs.Add("weight_tickets", fmt.Sprintf("Expected a weight ticket for trip %d.", trip.TripNumber))
+				return 0, verrs, nil
+			}
+		}
+		// Tickets lose their trip when it is removed from the PPM
+		for _, weightTicket := range weightTickets {
+			if weightTicket.PPMTripID == nil {
+				verrs.Add("weight_tickets", fmt.Sprintf("The weight ticket for trip %d is for a trip that was removed.", weightTicket.TripNumber))
+				return 0, verrs, nil
+			}
+		}
+	}
+
+	var netWeight unit.Pound
+	for _, weightTicket := range weightTickets {
+		if requireApproved && weightTicket.MoveDocument.Status != MoveDocumentStatusOK {
+			verrs.Add("weight_tickets", fmt.Sprintf("The weight ticket for trip %d has not been approved.", weightTicket.TripNumber))
+		}
+		netWeight += weightTicket.NetWeight()
+	}
+
+	orders := ppm.Move.Orders
+	if orders.ServiceMember.Rank == nil {
+		return netWeight, verrs, errors.Wrap(ErrFetchNotFound, "service member rank")
+	}
+	entitlement, err := GetEntitlement(*orders.ServiceMember.Rank, orders.HasDependents, orders.SpouseHasProGear)
+	if err != nil {
+		return netWeight, verrs, err
+	}
+
+	// For partial PPMs, the household goods shipped on the same move count against the entitlement
+	var shipments Shipments
+	if err := db.Where("move_id = $1", ppm.MoveID).All(&shipments); err != nil {
+		return netWeight, verrs, err
+	}
+	for _, shipment := range shipments {
+		if shipment.NetWeight != nil {
+			entitlement -= shipment.NetWeight.Int()
+		} else if shipment.WeightEstimate != nil {
+			entitlement -= shipment.WeightEstimate.Int()
+		}
+	}
+
+	if netWeight.Int() > entitlement {
+		verrs.Add("net_weight", fmt.Sprintf("Net weight of %d lbs exceeds the remaining weight entitlement of %d lbs.", netWeight.Int(), entitlement))
+	}
+
+	return netWeight, verrs, nil
+}
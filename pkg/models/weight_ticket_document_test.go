@@ -0,0 +1,64 @@
+package models_test
+
+import (
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ModelSuite) TestWeightTicketDocumentValidations() {
+	weightTicket := &WeightTicketDocument{EmptyWeight: 5000, FullWeight: 3000}
+
+	expErrors := map[string][]string{
+		"move_document_id": {"MoveDocumentID can not be blank."},
+		"trip_number":      {"0 is not greater than 0."},
+		"full_weight":      {"3000 is not greater than 5000."},
+	}
+
+	suite.verifyValidationErrors(weightTicket, expErrors)
+}
+
+func (suite *ModelSuite) TestComputePPMNetWeight() {
+	ppm := testdatagen.MakeDefaultPPM(suite.db)
+	suite.Nil(suite.db.Eager("Move.Orders.ServiceMember").Find(&ppm, ppm.ID))
+
+	// Weight tickets are required
+	_, verrs, err := ComputePPMNetWeight(suite.db, &ppm, false)
+	suite.Nil(err)
+	suite.True(verrs.HasAny())
+
+	assertions := testdatagen.Assertions{
+		MoveDocument: MoveDocument{
+			MoveID:                   ppm.MoveID,
+			Move:                     ppm.Move,
+			PersonallyProcuredMoveID: &ppm.ID,
+		},
+	}
+	testdatagen.MakeWeightTicketDocument(suite.db, assertions)
+	assertions.WeightTicketDocument = WeightTicketDocument{TripNumber: 3, EmptyWeight: 3000, FullWeight: 4500}
+	thirdTrip := testdatagen.MakeWeightTicketDocument(suite.db, assertions)
+
+	// Trips can't be skipped
+	_, verrs, err = ComputePPMNetWeight(suite.db, &ppm, false)
+	suite.Nil(err)
+	suite.Equal([]string{"Expected a weight ticket for trip 2."}, verrs.Get("weight_tickets"))
+
+	thirdTrip.TripNumber = 2
+	suite.mustSave(&thirdTrip)
+	netWeight, verrs, err := ComputePPMNetWeight(suite.db, &ppm, false)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(unit.Pound(3500), netWeight)
+
+	// Unreviewed tickets don't count once approval is required
+	_, verrs, err = ComputePPMNetWeight(suite.db, &ppm, true)
+	suite.Nil(err)
+	suite.Len(verrs.Get("weight_tickets"), 2)
+
+	// The net weight can't exceed the entitlement
+	thirdTrip.FullWeight = 20000
+	suite.mustSave(&thirdTrip)
+	_, verrs, err = ComputePPMNetWeight(suite.db, &ppm, false)
+	suite.Nil(err)
+	suite.Len(verrs.Get("net_weight"), 1)
+}
@@ -0,0 +1,358 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gobuffalo/validate/validators"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+)
+
+// WorkClaimRecordType is the kind of office work that can be claimed
+type WorkClaimRecordType string
+
+const (
+	// WorkClaimRecordTypeMove is a move waiting for approval
+	WorkClaimRecordTypeMove WorkClaimRecordType = "move"
+	// WorkClaimRecordTypePPM is a PPM or its payment request waiting for approval
+	WorkClaimRecordTypePPM WorkClaimRecordType = "personally_procured_move"
+)
+
+// WorkClaimTTL is how long a claim lasts without activity from the office user holding it
+const WorkClaimTTL = 30 * time.Minute
+
+// WorkClaim records which office user is working on a move or PPM. Claims made by the office user
+// themselves expire after WorkClaimTTL of inactivity; assignments made by a supervisor last until
+// released.
+type WorkClaim struct {
+	ID                     uuid.UUID           `json:"id" db:"id"`
+	CreatedAt              time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt              time.Time           `json:"updated_at" db:"updated_at"`
+	RecordType             WorkClaimRecordType `json:"record_type" db:"record_type"`
+	RecordID               uuid.UUID           `json:"record_id" db:"record_id"`
+	OfficeUserID           uuid.UUID           `json:"office_user_id" db:"office_user_id"`
+	OfficeUser             OfficeUser          `belongs_to:"office_users"`
+	AssignedByOfficeUserID *uuid.UUID          `json:"assigned_by_office_user_id" db:"assigned_by_office_user_id"`
+	ExpiresAt              *time.Time          `json:"expires_at" db:"expires_at"`
+}
+
+// WorkClaims is not required by pop and may be deleted
+type WorkClaims []WorkClaim
+
+// Validate gets run every time you call a "pop.Validate*" (pop.ValidateAndSave, pop.ValidateAndCreate, pop.ValidateAndUpdate) method.
+// This method is not required and may be deleted.
+func (c *WorkClaim) Validate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.Validate(
+		&validators.StringInclusion{Field: string(c.RecordType), Name: "RecordType", List: []string{
+			string(WorkClaimRecordTypeMove),
+			string(WorkClaimRecordTypePPM),
+		}},
+		&validators.UUIDIsPresent{Field: c.RecordID, Name: "RecordID"},
+		&validators.UUIDIsPresent{Field: c.OfficeUserID, Name: "OfficeUserID"},
+	), nil
+}
+
+// ValidateCreate gets run every time you call "pop.ValidateAndCreate" method.
+// This method is not required and may be deleted.
+func (c *WorkClaim) ValidateCreate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// ValidateUpdate gets run every time you call "pop.ValidateAndUpdate" method.
+// This method is not required and may be deleted.
+func (c *WorkClaim) ValidateUpdate(tx *pop.Connection) (*validate.Errors, error) {
+	return validate.NewErrors(), nil
+}
+
+// IsActive returns true if the claim hasn't expired
+func (c WorkClaim) IsActive() bool {
+	return c.ExpiresAt == nil || c.ExpiresAt.After(time.Now())
+}
+
+// IsAssignment returns true if a supervisor assigned the work rather than the office user claiming it
+func (c WorkClaim) IsAssignment() bool {
+	return c.AssignedByOfficeUserID != nil
+}
+
+func (c *WorkClaim) extend() {
+	if c.IsAssignment() {
+		return
+	}
+	expiresAt := time.Now().Add(WorkClaimTTL)
+	c.ExpiresAt = &expiresAt
+}
+
+// authorizeWorkRecord checks that the session can access the claimed move or PPM
+func authorizeWorkRecord(db *pop.Connection, session *auth.Session, recordType WorkClaimRecordType, recordID uuid.UUID) error {
+	if !session.IsOfficeApp() || !session.IsOfficeUser() {
+		return denyAccess(session, "work_claim", recordID, "not an office user")
+	}
+	switch recordType {
+	case WorkClaimRecordTypeMove:
+		return AuthorizeMoveAccess(db, session, recordID)
+	case WorkClaimRecordTypePPM:
+		_, err := FetchPersonallyProcuredMove(db, session, recordID)
+		return err
+	}
+	return errors.Errorf("unknown work claim record type %s", recordType)
+}
+
+// fetchWorkClaim returns the claim on a record, active or not, or nil if there is none
+func fetchWorkClaim(db *pop.Connection, recordType WorkClaimRecordType, recordID uuid.UUID) (*WorkClaim, error) {
+	var claims WorkClaims
+	err := db.Eager("OfficeUser").Where("record_type = $1 AND record_id = $2", recordType, recordID).All(&claims)
+	if err != nil {
+		return nil, err
+	}
+	if len(claims) == 0 {
+		return nil, nil
+	}
+	return &claims[0], nil
+}
+
+// FetchActiveWorkClaim returns the active claim on a record, or ErrFetchNotFound if nobody holds one
+func FetchActiveWorkClaim(db *pop.Connection, session *auth.Session, recordType WorkClaimRecordType, recordID uuid.UUID) (*WorkClaim, error) {
+	if err := authorizeWorkRecord(db, session, recordType, recordID); err != nil {
+		return nil, err
+	}
+	claim, err := fetchWorkClaim(db, recordType, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if claim == nil || !claim.IsActive() {
+		return nil, ErrFetchNotFound
+	}
+	return claim, nil
+}
+
+// FetchWorkClaim returns a claim by ID if the session can access the claimed record
+func FetchWorkClaim(db *pop.Connection, session *auth.Session, id uuid.UUID) (*WorkClaim, error) {
+	var claim WorkClaim
+	err := db.Eager("OfficeUser").Find(&claim, id)
+	if err != nil {
+		if errors.Cause(err).Error() == recordNotFoundErrorString {
+			return nil, ErrFetchNotFound
+		}
+		return nil, err
+	}
+	if err := authorizeWorkRecord(db, session, claim.RecordType, claim.RecordID); err != nil {
+		return nil, err
+	}
+	return &claim, nil
+}
+
+// FetchWorkClaimsForOfficeUser returns an office user's active claims, oldest first
+func FetchWorkClaimsForOfficeUser(db *pop.Connection, officeUserID uuid.UUID) (WorkClaims, error) {
+	var claims WorkClaims
+	err := db.Eager("OfficeUser").
+		Where("office_user_id = $1 AND (expires_at IS NULL OR expires_at > $2)", officeUserID, time.Now()).
+		Order("created_at asc").
+		All(&claims)
+	return claims, err
+}
+
+// saveWorkClaim replaces any existing claim on the record with claim, recording the change in the audit log
+func saveWorkClaim(db *pop.Connection, session *auth.Session, existing *WorkClaim, claim *WorkClaim, event string) (*validate.Errors, error) {
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		if existing != nil && existing.ID != claim.ID {
+			if err := db.Destroy(existing); err != nil {
+				responseError = err
+				return transactionError
+			}
+		}
+
+		if verrs, err := db.ValidateAndSave(claim); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = err
+			// Someone else claimed the record between our fetch and save
+			if err != nil && strings.HasPrefix(errors.Cause(err).Error(), uniqueConstraintViolationErrorPrefix) {
+				responseError = ErrWorkClaimed
+			}
+			return transactionError
+		}
+
+		if verrs, err := RecordAudit(db, session, existing, claim, event, ""); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// ClaimWork claims a move or PPM for the session's office user. Claiming a record the office user
+// already holds extends the claim. Returns ErrWorkClaimed if another office user holds an active claim.
+func ClaimWork(db *pop.Connection, session *auth.Session, recordType WorkClaimRecordType, recordID uuid.UUID) (*WorkClaim, *validate.Errors, error) {
+	if err := authorizeWorkRecord(db, session, recordType, recordID); err != nil {
+		return nil, validate.NewErrors(), err
+	}
+
+	existing, err := fetchWorkClaim(db, recordType, recordID)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	if existing != nil && existing.IsActive() && existing.OfficeUserID != session.OfficeUserID {
+		return existing, validate.NewErrors(), ErrWorkClaimed
+	}
+
+	if existing != nil && existing.IsActive() {
+		existing.extend()
+		verrs, err := db.ValidateAndUpdate(existing)
+		return existing, verrs, err
+	}
+
+	officeUser, err := FetchOfficeUserByID(db, session.OfficeUserID)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	claim := WorkClaim{
+		RecordType:   recordType,
+		RecordID:     recordID,
+		OfficeUserID: officeUser.ID,
+		OfficeUser:   *officeUser,
+	}
+	claim.extend()
+	verrs, err := saveWorkClaim(db, session, existing, &claim, "claim")
+	return &claim, verrs, err
+}
+
+// AssignWork lets a supervisor assign a move or PPM to an office user, replacing any existing claim.
+// Assignments don't expire.
+func AssignWork(db *pop.Connection, session *auth.Session, recordType WorkClaimRecordType, recordID uuid.UUID, officeUserID uuid.UUID) (*WorkClaim, *validate.Errors, error) {
+	if err := AuthorizeReassignment(db, session); err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	if err := authorizeWorkRecord(db, session, recordType, recordID); err != nil {
+		return nil, validate.NewErrors(), err
+	}
+
+	assignee, err := FetchOfficeUserByID(db, officeUserID)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+	if assignee.Deactivated {
+		verrs := validate.NewErrors()
+		verrs.Add("office_user_id", "Work can't be assigned to a deactivated office user.")
+		return nil, verrs, nil
+	}
+
+	existing, err := fetchWorkClaim(db, recordType, recordID)
+	if err != nil {
+		return nil, validate.NewErrors(), err
+	}
+
+	assignedBy := session.OfficeUserID
+	claim := WorkClaim{
+		RecordType:             recordType,
+		RecordID:               recordID,
+		OfficeUserID:           assignee.ID,
+		OfficeUser:             *assignee,
+		AssignedByOfficeUserID: &assignedBy,
+	}
+	event := "assign"
+	if existing != nil && existing.IsActive() {
+		event = "reassign"
+	}
+	verrs, err := saveWorkClaim(db, session, existing, &claim, event)
+	return &claim, verrs, err
+}
+
+// ReleaseWork removes a claim. Only the office user holding the claim or a supervisor can release it.
+func ReleaseWork(db *pop.Connection, session *auth.Session, claim *WorkClaim) (*validate.Errors, error) {
+	if claim.OfficeUserID != session.OfficeUserID {
+		if err := AuthorizeReassignment(db, session); err != nil {
+			return validate.NewErrors(), err
+		}
+	}
+
+	responseVErrors := validate.NewErrors()
+	var responseError error
+
+	inTransaction(db, func(db *pop.Connection) error {
+		transactionError := errors.New("rollback")
+
+		if err := db.Destroy(claim); err != nil {
+			responseError = err
+			return transactionError
+		}
+
+		released := *claim
+		released.ExpiresAt = nil
+		released.AssignedByOfficeUserID = nil
+		released.OfficeUserID = uuid.Nil
+		if verrs, err := RecordAudit(db, session, claim, &released, "release", ""); verrs.HasAny() || err != nil {
+			responseVErrors.Append(verrs)
+			responseError = errors.Wrap(err, "Error saving audit record")
+			return transactionError
+		}
+
+		return nil
+	})
+
+	return responseVErrors, responseError
+}
+
+// AuthorizeClaimedWork checks that the session's office user may act on a move or PPM. Work claimed
+// by another office user can only be acted on when a supervisor overrides the claim. Activity by the
+// office user holding the claim extends it.
+func AuthorizeClaimedWork(db *pop.Connection, session *auth.Session, recordType WorkClaimRecordType, recordID uuid.UUID, override bool) error {
+	claim, err := fetchWorkClaim(db, recordType, recordID)
+	if err != nil {
+		return err
+	}
+	if claim == nil || !claim.IsActive() {
+		return nil
+	}
+
+	if claim.OfficeUserID == session.OfficeUserID {
+		claim.extend()
+		if verrs, err := db.ValidateAndUpdate(claim); verrs.HasAny() || err != nil {
+			return errors.Wrapf(err, "extending work claim %s: %v", claim.ID, verrs)
+		}
+		return nil
+	}
+
+	if !override {
+		return ErrWorkClaimed
+	}
+	if err := AuthorizeReassignment(db, session); err != nil {
+		return err
+	}
+	zap.L().Info("Work claim overridden",
+		zap.String("audit", "work_claim_override"),
+		zap.String("work_claim_id", claim.ID.String()),
+		zap.String("record_type", string(claim.RecordType)),
+		zap.String("record_id", claim.RecordID.String()),
+		zap.String("claimed_by_office_user_id", claim.OfficeUserID.String()),
+		zap.String("office_user_id", session.OfficeUserID.String()),
+	)
+	return nil
+}
+
+// AuthorizeClaimedReimbursement checks the claim on the PPM that a reimbursement advances, if any
+func AuthorizeClaimedReimbursement(db *pop.Connection, session *auth.Session, reimbursementID uuid.UUID, override bool) error {
+	var ppms PersonallyProcuredMoves
+	if err := db.Where("advance_id = $1", reimbursementID).All(&ppms); err != nil {
+		return err
+	}
+	for _, ppm := range ppms {
+		if err := AuthorizeClaimedWork(db, session, WorkClaimRecordTypePPM, ppm.ID, override); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package models_test
+
+import (
+	"time"
+
+	. "github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *ModelSuite) TestClaimWork() {
+	move := testdatagen.MakeDefaultMove(suite.db)
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	otherOfficeUser := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User: User{LoginGovEmail: "other_office_user@example.com"},
+	})
+
+	claim, verrs, err := ClaimWork(suite.db, officeSession(officeUser), WorkClaimRecordTypeMove, move.ID)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(officeUser.ID, claim.OfficeUserID)
+	suite.NotNil(claim.ExpiresAt)
+
+	// Claiming again extends the claim
+	firstExpiry := *claim.ExpiresAt
+	again, _, err := ClaimWork(suite.db, officeSession(officeUser), WorkClaimRecordTypeMove, move.ID)
+	suite.Nil(err)
+	suite.Equal(claim.ID, again.ID)
+	suite.False(again.ExpiresAt.Before(firstExpiry))
+
+	// Nobody else can claim or act on it
+	_, _, err = ClaimWork(suite.db, officeSession(otherOfficeUser), WorkClaimRecordTypeMove, move.ID)
+	suite.Equal(ErrWorkClaimed, err)
+	err = AuthorizeClaimedWork(suite.db, officeSession(otherOfficeUser), WorkClaimRecordTypeMove, move.ID, false)
+	suite.Equal(ErrWorkClaimed, err)
+	err = AuthorizeClaimedWork(suite.db, officeSession(otherOfficeUser), WorkClaimRecordTypeMove, move.ID, true)
+	suite.Equal(ErrFetchForbidden, err)
+	suite.Nil(AuthorizeClaimedWork(suite.db, officeSession(officeUser), WorkClaimRecordTypeMove, move.ID, false))
+
+	// Once the claim expires it can be picked up by someone else
+	expired := time.Now().Add(-time.Minute)
+	again.ExpiresAt = &expired
+	suite.mustSave(again)
+	suite.Nil(AuthorizeClaimedWork(suite.db, officeSession(otherOfficeUser), WorkClaimRecordTypeMove, move.ID, false))
+	claim, _, err = ClaimWork(suite.db, officeSession(otherOfficeUser), WorkClaimRecordTypeMove, move.ID)
+	suite.Nil(err)
+	suite.Equal(otherOfficeUser.ID, claim.OfficeUserID)
+
+	audits, err := FetchAuditRecords(suite.db, AuditRecordFilter{MoveID: &move.ID})
+	suite.Nil(err)
+	claimEvents := 0
+	for _, audit := range audits {
+		if audit.RecordType == "work_claim" && audit.Event == "claim" {
+			claimEvents++
+		}
+	}
+	suite.Equal(2, claimEvents)
+}
+
+func (suite *ModelSuite) TestAssignAndReleaseWork() {
+	ppm := testdatagen.MakeDefaultPPM(suite.db)
+	officeUser := testdatagen.MakeDefaultOfficeUser(suite.db)
+	supervisor := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		User:       User{LoginGovEmail: "supervisor@example.com"},
+		OfficeUser: OfficeUser{IsSupervisor: true},
+	})
+
+	// Only supervisors can assign work
+	_, _, err := AssignWork(suite.db, officeSession(officeUser), WorkClaimRecordTypePPM, ppm.ID, officeUser.ID)
+	suite.Equal(ErrFetchForbidden, err)
+
+	claim, verrs, err := AssignWork(suite.db, officeSession(supervisor), WorkClaimRecordTypePPM, ppm.ID, officeUser.ID)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	suite.Equal(officeUser.ID, claim.OfficeUserID)
+	suite.Equal(supervisor.ID, *claim.AssignedByOfficeUserID)
+	suite.Nil(claim.ExpiresAt)
+
+	// Supervisors can act on assigned work by overriding the claim
+	suite.Equal(ErrWorkClaimed, AuthorizeClaimedWork(suite.db, officeSession(supervisor), WorkClaimRecordTypePPM, ppm.ID, false))
+	suite.Nil(AuthorizeClaimedWork(suite.db, officeSession(supervisor), WorkClaimRecordTypePPM, ppm.ID, true))
+
+	verrs, err = ReleaseWork(suite.db, officeSession(officeUser), claim)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	_, err = FetchActiveWorkClaim(suite.db, officeSession(officeUser), WorkClaimRecordTypePPM, ppm.ID)
+	suite.Equal(ErrFetchNotFound, err)
+}
@@ -0,0 +1,145 @@
+package notifications
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// serviceMemberChannels are the channels service members are notified through
+var serviceMemberChannels = []models.NotificationChannel{
+	models.NotificationChannelEmail,
+	models.NotificationChannelSMS,
+	models.NotificationChannelInbox,
+}
+
+// officeUserChannels are the channels office users are notified through
+var officeUserChannels = []models.NotificationChannel{
+	models.NotificationChannelEmail,
+	models.NotificationChannelInbox,
+}
+
+// tspUserChannels are the channels TSP users are notified through
+var tspUserChannels = []models.NotificationChannel{
+	models.NotificationChannelEmail,
+}
+
+// eventChannels are the channels each event's notification declares it supports
+var eventChannels = map[string][]models.NotificationChannel{
+	EventMoveApproved:  MoveApproved{}.channels(),
+	EventMoveCanceled:  MoveCanceled{}.channels(),
+	EventMoveSubmitted: MoveSubmitted{}.channels(),
+	EventSLABreached:   SLABreached{}.channels(),
+
+	EventShipmentOffered:            ShipmentEvent{event: EventShipmentOffered}.channels(),
+	EventShipmentAccepted:           ShipmentEvent{event: EventShipmentAccepted}.channels(),
+	EventShipmentRejected:           ShipmentEvent{event: EventShipmentRejected}.channels(),
+	EventPremoveSurveyScheduled:     ShipmentEvent{event: EventPremoveSurveyScheduled}.channels(),
+	EventPremoveSurveySlotsProposed: ShipmentEvent{event: EventPremoveSurveySlotsProposed}.channels(),
+	EventPremoveSurveyConfirmed:     ShipmentEvent{event: EventPremoveSurveyConfirmed}.channels(),
+	EventShipmentPickedUp:           ShipmentEvent{event: EventShipmentPickedUp}.channels(),
+	EventShipmentDelivered:          ShipmentEvent{event: EventShipmentDelivered}.channels(),
+	EventPreApprovalRequested:       ShipmentEvent{event: EventPreApprovalRequested}.channels(),
+	EventSurveyRequested:            ShipmentEvent{event: EventSurveyRequested}.channels(),
+}
+
+// EventChannels returns the channels an event's notifications are sent through
+func EventChannels(event string) []models.NotificationChannel {
+	return eventChannels[event]
+}
+
+// textMessageNotification is a notification that can be sent by text message
+type textMessageNotification interface {
+	textMessages() ([]textMessageContent, error)
+}
+
+// inboxNotification is a notification that can be stored in in-app inboxes
+type inboxNotification interface {
+	inboxMessages() ([]inboxContent, error)
+}
+
+type textMessageContent struct {
+	event       string
+	userID      *uuid.UUID
+	phoneNumber string
+	body        string
+}
+
+type inboxContent struct {
+	event   string
+	userID  uuid.UUID
+	subject string
+	body    string
+}
+
+// SMSProvider sends text messages and returns the provider's ID for the message
+type SMSProvider interface {
+	SendTextMessage(phoneNumber string, body string) (string, error)
+}
+
+// FakeTextMessage is a text message the FakeSMSProvider pretended to send
+type FakeTextMessage struct {
+	PhoneNumber string
+	Body        string
+}
+
+// FakeSMSProvider logs text messages instead of sending them, for local usage and tests
+type FakeSMSProvider struct {
+	logger *zap.Logger
+	Sent   []FakeTextMessage
+}
+
+// NewFakeSMSProvider returns a new FakeSMSProvider
+func NewFakeSMSProvider(logger *zap.Logger) *FakeSMSProvider {
+	return &FakeSMSProvider{logger: logger}
+}
+
+// SendTextMessage records the text message and returns a dummy ID
+func (p *FakeSMSProvider) SendTextMessage(phoneNumber string, body string) (string, error) {
+	p.Sent = append(p.Sent, FakeTextMessage{PhoneNumber: phoneNumber, Body: body})
+	p.logger.Info("Not sending this text message",
+		zap.String("phone_number", phoneNumber),
+		zap.String("body", body))
+	return "fake-" + uuid.Must(uuid.NewV4()).String(), nil
+}
+
+// serviceMemberTextMessages renders the event's text message for the service member. Text messages
+// are only sent to service members who said they prefer them, and carry the email's subject line.
+func serviceMemberTextMessages(db *pop.Connection, event string, data interface{}, serviceMember models.ServiceMember) ([]textMessageContent, error) {
+	if serviceMember.Telephone == nil || serviceMember.TextMessageIsPreferred == nil || !*serviceMember.TextMessageIsPreferred {
+		return nil, nil
+	}
+	rendered, err := renderEmail(db, event, data, "")
+	if err != nil {
+		return nil, err
+	}
+	return []textMessageContent{{
+		event:       event,
+		userID:      &serviceMember.UserID,
+		phoneNumber: *serviceMember.Telephone,
+		body:        rendered.subject,
+	}}, nil
+}
+
+// renderInboxMessages renders the event's inbox message for each user from its email templates
+func renderInboxMessages(db *pop.Connection, event string, data interface{}, userIDs []uuid.UUID) ([]inboxContent, error) {
+	var messages []inboxContent
+	if len(userIDs) == 0 {
+		return messages, nil
+	}
+	rendered, err := renderEmail(db, event, data, "")
+	if err != nil {
+		return messages, err
+	}
+	for _, userID := range userIDs {
+		messages = append(messages, inboxContent{
+			event:   event,
+			userID:  userID,
+			subject: rendered.subject,
+			body:    rendered.textBody,
+		})
+	}
+	return messages, nil
+}
@@ -34,63 +34,75 @@ func NewMoveApproved(db *pop.Connection,
 	}
 }
 
-func (m MoveApproved) emails() ([]emailContent, error) {
-	var emails []emailContent
-
+func (m MoveApproved) templateData() (moveApprovedData, *models.ServiceMember, error) {
 	move, err := models.FetchMove(m.db, m.session, m.moveID)
 	if err != nil {
-		return emails, err
+		return moveApprovedData{}, nil, err
 	}
 
 	orders, err := models.FetchOrderForUser(m.db, m.session, move.OrdersID)
 	if err != nil {
-		return emails, err
+		return moveApprovedData{}, nil, err
 	}
 
 	serviceMember, err := models.FetchServiceMemberForUser(m.db, m.session, orders.ServiceMemberID)
 	if err != nil {
-		return emails, err
+		return moveApprovedData{}, nil, err
 	}
 
-	if serviceMember.PersonalEmail == nil {
-		return emails, fmt.Errorf("no email found for service member")
-	}
-
-	// Set up various text segments. Copy comes from here:
-	// https://docs.google.com/document/d/1bgE0Q_-_c93uruMP8dcNSHugXo8Pidz6YFojWBKn1Gg/edit#heading=h.h3ys1ur2qhpn
-	// TODO: we will want some sort of templating system
-
 	ppmInfoSheetURL := url.URL{
 		Scheme: "https",
 		Host:   m.session.Hostname,
 		Path:   "downloads/ppm_info_sheet.pdf",
 	}
 
-	introText := `Your move has been approved and you are ready to move!`
-	if move.PersonallyProcuredMoves != nil {
-		introText = fmt.Sprintf("%s %s %s",
-			introText,
-			`Please review the PPM info sheet for more detailed instructions: `,
-			ppmInfoSheetURL.String())
+	data := moveApprovedData{
+		Locator:         move.Locator,
+		HasPPM:          len(move.PersonallyProcuredMoves) > 0,
+		PPMInfoSheetURL: ppmInfoSheetURL.String(),
 	}
+	return data, &serviceMember, nil
+}
 
-	nextStepsText := `Next steps:`
+func (m MoveApproved) emails() ([]emailContent, error) {
+	var emails []emailContent
 
-	ppmText := ""
-	if move.PersonallyProcuredMoves != nil {
-		ppmText = `For your “Do-it-Yourself” shipment, you can begin your move whenever you are ready. Be sure to save your weight tickets and any receipts associated with your move for when you request payment later on in the process.`
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return emails, err
 	}
 
-	// TODO: Add the PPPO contact info
-	closingText := `If you have any questions, contact your origin PPPO.`
+	if serviceMember.PersonalEmail == nil {
+		return emails, fmt.Errorf("no email found for service member")
+	}
 
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        "MOVE.MIL: Your move has been approved.",
-		htmlBody:       fmt.Sprintf("%s<br/>%s<br/>%s<br/>%s", introText, nextStepsText, ppmText, closingText),
-		textBody:       fmt.Sprintf("%s\n%s\n%s\n%s", introText, nextStepsText, ppmText, closingText),
+	// TODO: Add the PPPO contact info
+	smEmail, err := renderEmail(m.db, EventMoveApproved, data, *serviceMember.PersonalEmail)
+	if err != nil {
+		return emails, err
 	}
+	smEmail.userID = &serviceMember.UserID
 
 	// TODO: Send email to trusted contacts when that's supported
 	return append(emails, smEmail), nil
 }
+
+func (m MoveApproved) channels() []models.NotificationChannel {
+	return serviceMemberChannels
+}
+
+func (m MoveApproved) textMessages() ([]textMessageContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return serviceMemberTextMessages(m.db, EventMoveApproved, data, *serviceMember)
+}
+
+func (m MoveApproved) inboxMessages() ([]inboxContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return renderInboxMessages(m.db, EventMoveApproved, data, []uuid.UUID{serviceMember.UserID})
+}
@@ -33,54 +33,78 @@ func NewMoveCanceled(db *pop.Connection,
 	}
 }
 
-func (m MoveCanceled) emails() ([]emailContent, error) {
-	var emails []emailContent
-
+func (m MoveCanceled) templateData() (moveCanceledData, *models.ServiceMember, error) {
 	move, err := models.FetchMove(m.db, m.session, m.moveID)
 	if err != nil {
-		return emails, err
+		return moveCanceledData{}, nil, err
 	}
 
 	orders, err := models.FetchOrderForUser(m.db, m.session, move.OrdersID)
 	if err != nil {
-		return emails, err
+		return moveCanceledData{}, nil, err
 	}
 
 	serviceMember, err := models.FetchServiceMemberForUser(m.db, m.session, orders.ServiceMemberID)
 	if err != nil {
-		return emails, err
+		return moveCanceledData{}, nil, err
 	}
 
-	if serviceMember.PersonalEmail == nil {
-		return emails, fmt.Errorf("no email found for service member")
+	dsTransportInfo, err := models.FetchDSContactInfo(m.db, serviceMember.DutyStationID)
+	if err != nil {
+		return moveCanceledData{}, nil, err
 	}
 
-	dsTransportInfo, err := models.FetchDSContactInfo(m.db, serviceMember.DutyStationID)
+	if orders.NewDutyStation.Name == "" {
+		return moveCanceledData{}, nil, fmt.Errorf("missing new duty station for service member")
+	}
+
+	data := moveCanceledData{
+		Locator:                move.Locator,
+		OriginDutyStation:      dsTransportInfo.Name,
+		DestinationDutyStation: orders.NewDutyStation.Name,
+		PPPOPhone:              dsTransportInfo.PhoneLine,
+	}
+	return data, &serviceMember, nil
+}
+
+func (m MoveCanceled) emails() ([]emailContent, error) {
+	var emails []emailContent
+
+	data, serviceMember, err := m.templateData()
 	if err != nil {
 		return emails, err
 	}
 
-	if orders.NewDutyStation.Name == "" {
-		return emails, fmt.Errorf("missing new duty station for service member")
+	if serviceMember.PersonalEmail == nil {
+		return emails, fmt.Errorf("no email found for service member")
 	}
 
-	// Set up various text segments. Copy comes from here:
-	// https://docs.google.com/document/d/1bgE0Q_-_c93uruMP8dcNSHugXo8Pidz6YFojWBKn1Gg/edit#heading=h.h3ys1ur2qhpn
-	// TODO: we will want some sort of templating system
-
-	introText := `Your move has been canceled.`
-	nextSteps := fmt.Sprintf("Your move from %s to %s with the move locator ID %s was canceled.",
-		dsTransportInfo.Name, orders.NewDutyStation.Name, move.Locator)
-	closingText := fmt.Sprintf("Contact your local PPPO %s at %s if you have any questions.",
-		dsTransportInfo.Name, dsTransportInfo.PhoneLine)
-
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        fmt.Sprintf("MOVE.MIL: %s", introText),
-		htmlBody:       fmt.Sprintf("%s<br/>%s", nextSteps, closingText),
-		textBody:       fmt.Sprintf("%s\n%s", nextSteps, closingText),
+	smEmail, err := renderEmail(m.db, EventMoveCanceled, data, *serviceMember.PersonalEmail)
+	if err != nil {
+		return emails, err
 	}
+	smEmail.userID = &serviceMember.UserID
 
 	// TODO: Send email to trusted contacts when that's supported
 	return append(emails, smEmail), nil
 }
+
+func (m MoveCanceled) channels() []models.NotificationChannel {
+	return serviceMemberChannels
+}
+
+func (m MoveCanceled) textMessages() ([]textMessageContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return serviceMemberTextMessages(m.db, EventMoveCanceled, data, *serviceMember)
+}
+
+func (m MoveCanceled) inboxMessages() ([]inboxContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return renderInboxMessages(m.db, EventMoveCanceled, data, []uuid.UUID{serviceMember.UserID})
+}
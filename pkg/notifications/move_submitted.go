@@ -33,65 +33,81 @@ func NewMoveSubmitted(db *pop.Connection,
 	}
 }
 
-func (m MoveSubmitted) emails() ([]emailContent, error) {
-	var emails []emailContent
-
+func (m MoveSubmitted) templateData() (moveSubmittedData, *models.ServiceMember, error) {
 	move, err := models.FetchMove(m.db, m.session, m.moveID)
 	if err != nil {
-		return emails, err
+		return moveSubmittedData{}, nil, err
 	}
 
 	orders, err := models.FetchOrderForUser(m.db, m.session, move.OrdersID)
 	if err != nil {
-		return emails, err
+		return moveSubmittedData{}, nil, err
 	}
 
 	serviceMember, err := models.FetchServiceMemberForUser(m.db, m.session, orders.ServiceMemberID)
 	if err != nil {
-		return emails, err
-	}
-
-	if serviceMember.PersonalEmail == nil {
-		return emails, fmt.Errorf("no email found for service member")
+		return moveSubmittedData{}, nil, err
 	}
 
-	submittedText := "Your move has been submitted to your local transportation office for review. "
-	processText := "This process can take up to 3 business days. "
-	pppoText := "If you have questions or need expedited processing contact your local transportation office."
-
+	data := moveSubmittedData{Locator: move.Locator}
 	if serviceMember.DutyStationID != nil {
 		originDSTransportInfo, err := models.FetchDSContactInfo(m.db, serviceMember.DutyStationID)
 		if err != nil {
-			return emails, err
+			return moveSubmittedData{}, nil, err
 		}
 		destinationDutyStation, err := models.FetchDutyStation(m.db, orders.NewDutyStationID)
 		if err != nil {
-			return emails, err
+			return moveSubmittedData{}, nil, err
 		}
 
-		pppoText = fmt.Sprintf(
-			"If you have questions or need expedited processing contact your local PPPO %s at %s.",
-			originDSTransportInfo.Name,
-			originDSTransportInfo.PhoneLine,
-		)
-		submittedText = fmt.Sprintf(
-			"Your move from %s to %s has been submitted to your local transportation office for review. ",
-			originDSTransportInfo.Name,
-			destinationDutyStation.Name,
-		)
+		data.OriginDutyStation = originDSTransportInfo.Name
+		data.DestinationDutyStation = destinationDutyStation.Name
+		data.PPPOPhone = originDSTransportInfo.PhoneLine
 	}
+	return data, &serviceMember, nil
+}
 
-	text := submittedText + processText + pppoText
-	smEmail := emailContent{
-		recipientEmail: *serviceMember.PersonalEmail,
-		subject:        "MOVE.MIL: Your move has been submitted.",
-		htmlBody:       text,
-		textBody:       text,
+func (m MoveSubmitted) emails() ([]emailContent, error) {
+	var emails []emailContent
+
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return emails, err
 	}
 
+	if serviceMember.PersonalEmail == nil {
+		return emails, fmt.Errorf("no email found for service member")
+	}
+
+	smEmail, err := renderEmail(m.db, EventMoveSubmitted, data, *serviceMember.PersonalEmail)
+	if err != nil {
+		return emails, err
+	}
+	smEmail.userID = &serviceMember.UserID
+
 	m.logger.Info("Generated move submitted email to service member",
 		zap.String("service member email address", *serviceMember.PersonalEmail))
 
 	// TODO: Send email to trusted contacts when that's supported
 	return append(emails, smEmail), nil
 }
+
+func (m MoveSubmitted) channels() []models.NotificationChannel {
+	return serviceMemberChannels
+}
+
+func (m MoveSubmitted) textMessages() ([]textMessageContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return serviceMemberTextMessages(m.db, EventMoveSubmitted, data, *serviceMember)
+}
+
+func (m MoveSubmitted) inboxMessages() ([]inboxContent, error) {
+	data, serviceMember, err := m.templateData()
+	if err != nil {
+		return nil, err
+	}
+	return renderInboxMessages(m.db, EventMoveSubmitted, data, []uuid.UUID{serviceMember.UserID})
+}
@@ -8,20 +8,31 @@ import (
 	"github.com/aws/aws-sdk-go/service/ses"
 	"github.com/aws/aws-sdk-go/service/ses/sesiface"
 	"github.com/go-gomail/gomail"
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
 	"github.com/pkg/errors"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
 )
 
+// notification is sent through each channel it declares. Besides emails, notifications implement
+// textMessageNotification and inboxNotification for the SMS and inbox channels they declare.
 type notification interface {
+	channels() []models.NotificationChannel
 	emails() ([]emailContent, error)
 }
 
 type emailContent struct {
 	attachments    []string
+	event          string
+	userID         *uuid.UUID
 	recipientEmail string
 	subject        string
 	htmlBody       string
 	textBody       string
+	unsubscribeURL string
 }
 
 // NotificationSender is an interface for sending notifications
@@ -31,51 +42,247 @@ type NotificationSender interface {
 
 // NotificationSendingContext provides context to a notification sender
 type NotificationSendingContext struct {
-	svc    sesiface.SESAPI
-	logger *zap.Logger
+	svc          sesiface.SESAPI
+	logger       *zap.Logger
+	db           *pop.Connection
+	unsubscriber UnsubscribeSigner
+	smsProvider  SMSProvider
 }
 
-// NewNotificationSender returns a new NotificationSendingContext
-func NewNotificationSender(svc sesiface.SESAPI, logger *zap.Logger) NotificationSendingContext {
+// NewNotificationSender returns a new NotificationSendingContext. It records every email in the
+// notification log and adds unsubscribe links signed by unsubscriber to emails sent to users.
+func NewNotificationSender(svc sesiface.SESAPI, logger *zap.Logger, db *pop.Connection, unsubscriber UnsubscribeSigner) NotificationSendingContext {
 	return NotificationSendingContext{
-		svc:    svc,
-		logger: logger,
+		svc:          svc,
+		logger:       logger,
+		db:           db,
+		unsubscriber: unsubscriber,
 	}
 }
 
+// WithSMSProvider returns a copy of the sender that sends text messages through provider. Without
+// one, no text messages are sent.
+func (n NotificationSendingContext) WithSMSProvider(provider SMSProvider) NotificationSendingContext {
+	n.smsProvider = provider
+	return n
+}
+
 // SendNotification sends a one or more notifications for all supported mediums
 func (n NotificationSendingContext) SendNotification(notification notification) error {
+	// Every channel is tried, so a failed email doesn't also keep the message out of the inbox
+	var errs error
+	for _, channel := range notification.channels() {
+		var err error
+		switch channel {
+		case models.NotificationChannelEmail:
+			err = n.sendEmails(notification)
+		case models.NotificationChannelSMS:
+			err = n.sendTextMessages(notification)
+		case models.NotificationChannelInbox:
+			err = n.storeInboxMessages(notification)
+		default:
+			err = errors.Errorf("Unknown notification channel %s", channel)
+		}
+		errs = multierr.Append(errs, err)
+	}
+	return errs
+}
+
+func (n NotificationSendingContext) sendEmails(notification notification) error {
 	emails, err := notification.emails()
 	if err != nil {
 		return err
 	}
 
-	return sendEmails(emails, n.svc, n.logger)
+	var errs error
+	for _, email := range emails {
+		errs = multierr.Append(errs, n.sendEmail(email))
+	}
+	return errs
 }
 
-func sendEmails(emails []emailContent, svc sesiface.SESAPI, logger *zap.Logger) error {
-	for _, email := range emails {
-		rawMessage, err := formatRawEmailMessage(email)
+func (n NotificationSendingContext) sendTextMessages(notification notification) error {
+	textNotification, ok := notification.(textMessageNotification)
+	if !ok {
+		return errors.New("Notification declares the SMS channel but has no text messages")
+	}
+	if n.smsProvider == nil {
+		return nil
+	}
+	messages, err := textNotification.textMessages()
+	if err != nil {
+		return err
+	}
+
+	var errs error
+	for _, message := range messages {
+		errs = multierr.Append(errs, n.sendTextMessage(message))
+	}
+	return errs
+}
+
+func (n NotificationSendingContext) storeInboxMessages(notification notification) error {
+	inbox, ok := notification.(inboxNotification)
+	if !ok {
+		return errors.New("Notification declares the inbox channel but has no inbox messages")
+	}
+	messages, err := inbox.inboxMessages()
+	if err != nil {
+		return err
+	}
+
+	for _, message := range messages {
+		enabled, err := models.IsNotificationEnabled(n.db, message.userID, message.event, models.NotificationChannelInbox)
 		if err != nil {
 			return err
 		}
-
-		input := ses.SendRawEmailInput{
-			Destinations: []*string{aws.String(email.recipientEmail)},
-			RawMessage:   &ses.RawMessage{Data: rawMessage},
-			Source:       aws.String(senderEmail()),
+		if !enabled {
+			continue
+		}
+		stored := models.InboxNotification{
+			UserID:  message.userID,
+			Event:   message.event,
+			Subject: message.subject,
+			Body:    message.body,
+		}
+		verrs, err := n.db.ValidateAndCreate(&stored)
+		if err != nil {
+			return errors.Wrap(err, "Failed to store inbox notification")
+		}
+		if verrs.HasAny() {
+			return errors.New(verrs.Error())
 		}
+	}
+	return nil
+}
 
-		// Returns the message ID. Should we store that somewhere?
-		_, err = svc.SendRawEmail(&input)
+// suppressionReason returns why the notification mustn't be sent to the recipient through the channel,
+// or nil if it may be. Users may unsubscribe from an event's notifications, and nothing more is sent to
+// recipients whose earlier notifications bounced or were complained about.
+func (n NotificationSendingContext) suppressionReason(userID *uuid.UUID, event string, channel models.NotificationChannel, recipient string) (*string, error) {
+	if userID != nil {
+		enabled, err := models.IsNotificationEnabled(n.db, *userID, event, channel)
 		if err != nil {
-			return errors.Wrap(err, "Failed to send email using SES")
+			return nil, err
 		}
+		if !enabled {
+			return models.StringPointer("unsubscribed"), nil
+		}
+	}
+
+	suppressed, err := models.IsRecipientSuppressed(n.db, channel, recipient)
+	if err != nil {
+		return nil, err
+	}
+	if suppressed {
+		return models.StringPointer("earlier notification bounced or was marked as spam"), nil
+	}
+	return nil, nil
+}
+
+// logNotification records the notification in the notification log. Failing to record it doesn't
+// fail the send.
+func (n NotificationSendingContext) logNotification(log models.NotificationLog) {
+	verrs, err := n.db.ValidateAndCreate(&log)
+	if err != nil || verrs.HasAny() {
+		n.logger.Error("Failed to record notification",
+			zap.String("event", log.Event),
+			zap.String("channel", string(log.Channel)),
+			zap.String("status", string(log.Status)),
+			zap.String("validation_errors", verrs.String()),
+			zap.Error(err))
+	}
+}
+
+func (n NotificationSendingContext) logEmail(email emailContent, status models.NotificationStatus, detail *string, messageID *string) {
+	n.logNotification(models.NotificationLog{
+		UserID:       email.userID,
+		Event:        email.event,
+		Channel:      models.NotificationChannelEmail,
+		Recipient:    email.recipientEmail,
+		Subject:      email.subject,
+		Status:       status,
+		StatusDetail: detail,
+		SESMessageID: messageID,
+	})
+}
+
+func (n NotificationSendingContext) sendTextMessage(message textMessageContent) error {
+	log := models.NotificationLog{
+		UserID:    message.userID,
+		Event:     message.event,
+		Channel:   models.NotificationChannelSMS,
+		Recipient: message.phoneNumber,
+		Subject:   message.body,
+	}
+
+	reason, err := n.suppressionReason(message.userID, message.event, models.NotificationChannelSMS, message.phoneNumber)
+	if err != nil {
+		return err
+	}
+	if reason != nil {
+		n.logger.Info("Not sending suppressed text message",
+			zap.String("event", message.event),
+			zap.String("reason", *reason))
+		log.Status = models.NotificationStatusSUPPRESSED
+		log.StatusDetail = reason
+		n.logNotification(log)
+		return nil
+	}
+
+	if _, err := n.smsProvider.SendTextMessage(message.phoneNumber, message.body); err != nil {
+		log.Status = models.NotificationStatusFAILED
+		log.StatusDetail = models.StringPointer(err.Error())
+		n.logNotification(log)
+		return errors.Wrap(err, "Failed to send text message")
+	}
+
+	log.Status = models.NotificationStatusSENT
+	n.logNotification(log)
+	return nil
+}
+
+func (n NotificationSendingContext) sendEmail(email emailContent) error {
+	reason, err := n.suppressionReason(email.userID, email.event, models.NotificationChannelEmail, email.recipientEmail)
+	if err != nil {
+		return err
+	}
+	if reason != nil {
+		n.logger.Info("Not sending suppressed email",
+			zap.String("event", email.event),
+			zap.String("reason", *reason))
+		n.logEmail(email, models.NotificationStatusSUPPRESSED, reason, nil)
+		return nil
+	}
+
+	if email.userID != nil && email.event != "" {
+		email.unsubscribeURL = n.unsubscriber.URL(*email.userID, email.event)
+		textFooter, htmlFooter := unsubscribeFooters(email.unsubscribeURL)
+		email.textBody += textFooter
+		email.htmlBody += htmlFooter
+	}
 
-		logger.Info("Sent email to service member",
-			zap.String("service member email address", email.recipientEmail))
+	rawMessage, err := formatRawEmailMessage(email)
+	if err != nil {
+		return err
 	}
 
+	input := ses.SendRawEmailInput{
+		Destinations: []*string{aws.String(email.recipientEmail)},
+		RawMessage:   &ses.RawMessage{Data: rawMessage},
+		Source:       aws.String(senderEmail()),
+	}
+
+	output, err := n.svc.SendRawEmail(&input)
+	if err != nil {
+		n.logEmail(email, models.NotificationStatusFAILED, models.StringPointer(err.Error()), nil)
+		return errors.Wrap(err, "Failed to send email using SES")
+	}
+
+	n.logEmail(email, models.NotificationStatusSENT, nil, output.MessageId)
+	n.logger.Info("Sent email to service member",
+		zap.String("service member email address", email.recipientEmail))
+
 	return nil
 }
 
@@ -84,6 +291,9 @@ func formatRawEmailMessage(email emailContent) ([]byte, error) {
 	m.SetHeader("From", senderEmail())
 	m.SetHeader("To", email.recipientEmail)
 	m.SetHeader("Subject", email.subject)
+	if email.unsubscribeURL != "" {
+		m.SetHeader("List-Unsubscribe", "<"+email.unsubscribeURL+">")
+	}
 	m.SetBody("text/plain", email.textBody)
 	m.AddAlternative("text/html", email.htmlBody)
 	for _, attachment := range email.attachments {
@@ -0,0 +1,261 @@
+package notifications
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+type mockSESClient struct {
+	sesiface.SESAPI
+	sent []*ses.SendRawEmailInput
+}
+
+func (m *mockSESClient) SendRawEmail(input *ses.SendRawEmailInput) (*ses.SendRawEmailOutput, error) {
+	m.sent = append(m.sent, input)
+	return &ses.SendRawEmailOutput{MessageId: aws.String(uuid.Must(uuid.NewV4()).String())}, nil
+}
+
+type failingSESClient struct {
+	sesiface.SESAPI
+}
+
+func (f failingSESClient) SendRawEmail(input *ses.SendRawEmailInput) (*ses.SendRawEmailOutput, error) {
+	return nil, errors.New("SES is unavailable")
+}
+
+func (suite *NotificationSuite) TestSendNotificationRecordsAndSuppressesEmails() {
+	user := testdatagen.MakeDefaultUser(suite.db)
+	email := suite.GetTestEmailContent()
+	email.event = EventMoveApproved
+	email.userID = &user.ID
+	email.recipientEmail = uuid.Must(uuid.NewV4()).String() + "@example.com"
+
+	svc := &mockSESClient{}
+	sender := NewNotificationSender(svc, suite.logger, suite.db, NewUnsubscribeSigner("secret", "my.move.mil"))
+
+	suite.NoError(sender.SendNotification(testNotification{email: email}))
+	suite.Len(svc.sent, 1)
+	suite.Contains(string(svc.sent[0].RawMessage.Data), "List-Unsubscribe")
+
+	logs, err := models.FetchNotificationLogs(suite.db, user.ID)
+	suite.NoError(err)
+	suite.Len(logs, 1)
+	suite.Equal(models.NotificationStatusSENT, logs[0].Status)
+	suite.NotNil(logs[0].SESMessageID)
+
+	// Once the user unsubscribes from the event it isn't sent
+	_, _, err = models.SetNotificationPreference(suite.db, user.ID, EventMoveApproved, models.NotificationChannelEmail, false)
+	suite.NoError(err)
+	suite.NoError(sender.SendNotification(testNotification{email: email}))
+	suite.Len(svc.sent, 1)
+
+	// Nor are emails to addresses that bounced
+	suite.NoError(IngestSESEvent(suite.db, suite.logger, []byte(`{
+		"notificationType": "Bounce",
+		"bounce": {"bounceType": "Permanent", "bounceSubType": "General"},
+		"mail": {"messageId": "`+*logs[0].SESMessageID+`"}
+	}`)))
+	email.event = EventMoveSubmitted
+	suite.NoError(sender.SendNotification(testNotification{email: email}))
+	suite.Len(svc.sent, 1)
+
+	logs, err = models.FetchNotificationLogs(suite.db, user.ID)
+	suite.NoError(err)
+	suite.Len(logs, 3)
+	statuses := map[models.NotificationStatus]int{}
+	for _, log := range logs {
+		statuses[log.Status]++
+	}
+	suite.Equal(map[models.NotificationStatus]int{
+		models.NotificationStatusBOUNCED:    1,
+		models.NotificationStatusSUPPRESSED: 2,
+	}, statuses)
+}
+
+func (suite *NotificationSuite) TestIngestSESEvents() {
+	messageID := uuid.Must(uuid.NewV4()).String()
+	log := models.NotificationLog{
+		Event:        EventMoveCanceled,
+		Channel:      models.NotificationChannelEmail,
+		Recipient:    "ingest@example.com",
+		Subject:      "Canceled",
+		Status:       models.NotificationStatusSENT,
+		SESMessageID: &messageID,
+	}
+	verrs, err := suite.db.ValidateAndCreate(&log)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	// The delivery arrives directly and the complaint in an SNS envelope, as read from the queue
+	events := `{"notificationType": "Delivery", "mail": {"messageId": "` + messageID + `"}}
+
+{"Type": "Notification", "Message": "{\"notificationType\": \"Complaint\", \"complaint\": {\"complaintFeedbackType\": \"abuse\"}, \"mail\": {\"messageId\": \"` + messageID + `\"}}"}
+{"notificationType": "Delivery", "mail": {"messageId": "unknown-message"}}
+`
+	ingested, err := IngestSESEvents(suite.db, suite.logger, strings.NewReader(events))
+	suite.NoError(err)
+	suite.Equal(3, ingested)
+
+	updated, err := models.FetchNotificationLogBySESMessageID(suite.db, messageID)
+	suite.NoError(err)
+	suite.Equal(models.NotificationStatusCOMPLAINED, updated.Status)
+	suite.Equal("abuse", *updated.StatusDetail)
+
+	_, err = IngestSESEvents(suite.db, suite.logger, strings.NewReader("not json\n"))
+	suite.Error(err)
+}
+
+func (suite *NotificationSuite) TestUnsubscribeLinks() {
+	user := testdatagen.MakeDefaultUser(suite.db)
+	signer := NewUnsubscribeSigner("secret", "my.move.mil")
+
+	userID, event, err := signer.Verify(signer.Token(user.ID, EventMoveCanceled))
+	suite.NoError(err)
+	suite.Equal(user.ID, userID)
+	suite.Equal(EventMoveCanceled, event)
+
+	_, _, err = NewUnsubscribeSigner("other secret", "my.move.mil").Verify(signer.Token(user.ID, EventMoveCanceled))
+	suite.Equal(ErrInvalidUnsubscribeToken, err)
+
+	unsubscribeURL, err := url.Parse(signer.URL(user.ID, EventMoveCanceled))
+	suite.NoError(err)
+	suite.Equal(UnsubscribePath, unsubscribeURL.Path)
+	handler := NewUnsubscribeHandler(suite.db, suite.logger, signer)
+
+	// Following the link only asks for confirmation
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", unsubscribeURL.RequestURI(), nil))
+	suite.Equal(http.StatusOK, rr.Code)
+	enabled, err := models.IsNotificationEnabled(suite.db, user.ID, EventMoveCanceled, models.NotificationChannelEmail)
+	suite.NoError(err)
+	suite.True(enabled)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("POST", unsubscribeURL.RequestURI(), nil))
+	suite.Equal(http.StatusOK, rr.Code)
+	enabled, err = models.IsNotificationEnabled(suite.db, user.ID, EventMoveCanceled, models.NotificationChannelEmail)
+	suite.NoError(err)
+	suite.False(enabled)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", UnsubscribePath+"?token=forged", nil))
+	suite.Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (suite *NotificationSuite) TestLoadUnsubscribeSecret() {
+	_, err := LoadUnsubscribeSecret("", "")
+	suite.Error(err)
+
+	secret, err := LoadUnsubscribeSecret("secret", "")
+	suite.NoError(err)
+	suite.Equal("secret", secret)
+
+	secretFile, err := ioutil.TempFile("", "unsubscribe_secret")
+	suite.NoError(err)
+	defer os.Remove(secretFile.Name())
+	_, err = secretFile.WriteString("file secret\n")
+	suite.NoError(err)
+	suite.NoError(secretFile.Close())
+	secret, err = LoadUnsubscribeSecret("secret", secretFile.Name())
+	suite.NoError(err)
+	suite.Equal("file secret", secret)
+}
+
+func (suite *NotificationSuite) TestSendNotificationThroughTextMessagesAndInbox() {
+	textMessagePreferred := true
+	move := testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		ServiceMember: models.ServiceMember{TextMessageIsPreferred: &textMessagePreferred},
+	})
+	serviceMember := move.Orders.ServiceMember
+	notification := MoveSubmitted{
+		db:     suite.db,
+		logger: suite.logger,
+		moveID: move.ID,
+		session: &auth.Session{
+			UserID:          serviceMember.UserID,
+			ServiceMemberID: serviceMember.ID,
+			ApplicationName: auth.MyApp,
+		},
+	}
+
+	svc := &mockSESClient{}
+	smsProvider := NewFakeSMSProvider(suite.logger)
+	sender := NewNotificationSender(svc, suite.logger, suite.db, NewUnsubscribeSigner("secret", "my.move.mil")).
+		WithSMSProvider(smsProvider)
+
+	suite.NoError(sender.SendNotification(notification))
+	suite.Len(svc.sent, 1)
+	suite.Len(smsProvider.Sent, 1)
+	suite.Equal(*serviceMember.Telephone, smsProvider.Sent[0].PhoneNumber)
+	inbox, err := models.FetchInboxNotifications(suite.db, serviceMember.UserID, true)
+	suite.NoError(err)
+	suite.Len(inbox, 1)
+	suite.Equal(EventMoveSubmitted, inbox[0].Event)
+	suite.NotEmpty(inbox[0].Body)
+
+	// Each channel can be turned off on its own
+	_, _, err = models.SetNotificationPreference(suite.db, serviceMember.UserID, EventMoveSubmitted, models.NotificationChannelSMS, false)
+	suite.NoError(err)
+	_, _, err = models.SetNotificationPreference(suite.db, serviceMember.UserID, EventMoveSubmitted, models.NotificationChannelInbox, false)
+	suite.NoError(err)
+	suite.NoError(sender.SendNotification(notification))
+	suite.Len(svc.sent, 2)
+	suite.Len(smsProvider.Sent, 1)
+	inbox, err = models.FetchInboxNotifications(suite.db, serviceMember.UserID, false)
+	suite.NoError(err)
+	suite.Len(inbox, 1)
+
+	logs, err := models.FetchNotificationLogs(suite.db, serviceMember.UserID)
+	suite.NoError(err)
+	statuses := map[models.NotificationChannel][]models.NotificationStatus{}
+	for _, log := range logs {
+		statuses[log.Channel] = append(statuses[log.Channel], log.Status)
+	}
+	suite.ElementsMatch([]models.NotificationStatus{models.NotificationStatusSENT, models.NotificationStatusSUPPRESSED}, statuses[models.NotificationChannelSMS])
+	suite.Len(statuses[models.NotificationChannelEmail], 2)
+}
+
+func (suite *NotificationSuite) TestSendNotificationTriesEveryChannel() {
+	textMessagePreferred := true
+	move := testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		ServiceMember: models.ServiceMember{TextMessageIsPreferred: &textMessagePreferred},
+	})
+	serviceMember := move.Orders.ServiceMember
+	notification := MoveSubmitted{
+		db:     suite.db,
+		logger: suite.logger,
+		moveID: move.ID,
+		session: &auth.Session{
+			UserID:          serviceMember.UserID,
+			ServiceMemberID: serviceMember.ID,
+			ApplicationName: auth.MyApp,
+		},
+	}
+
+	smsProvider := NewFakeSMSProvider(suite.logger)
+	sender := NewNotificationSender(failingSESClient{}, suite.logger, suite.db, NewUnsubscribeSigner("secret", "my.move.mil")).
+		WithSMSProvider(smsProvider)
+
+	// The email failing is reported, but the text message and inbox message still go out
+	err := sender.SendNotification(notification)
+	suite.Error(err)
+	suite.Contains(err.Error(), "SES is unavailable")
+	suite.Len(smsProvider.Sent, 1)
+	inbox, err := models.FetchInboxNotifications(suite.db, serviceMember.UserID, true)
+	suite.NoError(err)
+	suite.Len(inbox, 1)
+}
@@ -1,6 +1,16 @@
 package notifications
 
 import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/mail"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ses"
+	"github.com/aws/aws-sdk-go/service/ses/sesiface"
+	"github.com/gofrs/uuid"
 	"go.uber.org/zap"
 )
 
@@ -21,16 +31,93 @@ func (m StubNotificationSender) SendNotification(notification notification) erro
 		return err
 	}
 
+	// Emails hold personal information, so only what identifies the message is logged
 	for _, email := range emails {
-		rawMessage, err := formatRawEmailMessage(email)
+		m.logger.Info("Not sending this email", zap.String("subject", email.subject))
+	}
+
+	return nil
+}
+
+// stubSESClient logs emails instead of sending them through SES
+type stubSESClient struct {
+	sesiface.SESAPI
+	logger *zap.Logger
+}
+
+// NewStubSESClient returns an SES client for local usage that logs emails instead of sending them.
+// Unlike StubNotificationSender, a NotificationSendingContext using it records notifications and
+// sends them through the other channels.
+func NewStubSESClient(logger *zap.Logger) sesiface.SESAPI {
+	return stubSESClient{logger: logger}
+}
+
+// SendRawEmail returns a dummy ID. Emails hold personal information, so only the number of recipients
+// and the subject are logged.
+func (c stubSESClient) SendRawEmail(input *ses.SendRawEmailInput) (*ses.SendRawEmailOutput, error) {
+	var subject string
+	if message, err := mail.ReadMessage(bytes.NewReader(input.RawMessage.Data)); err == nil {
+		subject, err = new(mime.WordDecoder).DecodeHeader(message.Header.Get("Subject"))
 		if err != nil {
-			return err
+			subject = message.Header.Get("Subject")
 		}
+	}
+	c.logger.Info("Not sending this email",
+		zap.Int("recipients", len(input.Destinations)),
+		zap.String("subject", subject))
+	return &ses.SendRawEmailOutput{MessageId: aws.String("stub-" + uuid.Must(uuid.NewV4()).String())}, nil
+}
 
-		m.logger.Info("Not sending this email",
-			zap.String("destinations", email.recipientEmail),
-			zap.String("raw message", string(rawMessage[:])))
+// RecordedNotification is a notification the RecordingNotificationSender was asked to send. Event and
+// ShipmentID are only set for shipment events; Type is the notification's Go type.
+type RecordedNotification struct {
+	Type       string
+	Event      string
+	ShipmentID uuid.UUID
+	TSPID      *uuid.UUID
+}
+
+// RecordingNotificationSender records the notifications it's asked to send instead of sending them,
+// so tests can check which were sent
+type RecordingNotificationSender struct {
+	mutex sync.Mutex
+	sent  []RecordedNotification
+}
+
+// NewRecordingNotificationSender returns a new RecordingNotificationSender
+func NewRecordingNotificationSender() *RecordingNotificationSender {
+	return &RecordingNotificationSender{}
+}
+
+// SendNotification records the notification
+func (r *RecordingNotificationSender) SendNotification(notification notification) error {
+	recorded := RecordedNotification{Type: fmt.Sprintf("%T", notification)}
+	if event, ok := notification.(*ShipmentEvent); ok {
+		recorded.Event = event.event
+		recorded.ShipmentID = event.shipmentID
+		recorded.TSPID = event.tspID
 	}
 
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.sent = append(r.sent, recorded)
 	return nil
 }
+
+// Sent returns the notifications recorded so far, in the order they were sent
+func (r *RecordingNotificationSender) Sent() []RecordedNotification {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RecordedNotification{}, r.sent...)
+}
+
+// Events returns the events of the shipment events recorded so far, in the order they were sent
+func (r *RecordingNotificationSender) Events() []string {
+	events := []string{}
+	for _, recorded := range r.Sent() {
+		if recorded.Event != "" {
+			events = append(events, recorded.Event)
+		}
+	}
+	return events
+}
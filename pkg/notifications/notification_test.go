@@ -9,6 +9,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
 	"github.com/transcom/mymove/pkg/testdatagen"
 )
 
@@ -22,6 +23,10 @@ type testNotification struct {
 	email emailContent
 }
 
+func (n testNotification) channels() []models.NotificationChannel {
+	return []models.NotificationChannel{models.NotificationChannelEmail}
+}
+
 func (n testNotification) emails() ([]emailContent, error) {
 	return []emailContent{n.email}, nil
 }
@@ -29,14 +34,15 @@ func (n testNotification) emails() ([]emailContent, error) {
 func (suite *NotificationSuite) TestMoveApproved() {
 	t := suite.T()
 
-	approver := testdatagen.MakeDefaultUser(suite.db)
+	approver := testdatagen.MakeDefaultOfficeUser(suite.db)
 	move := testdatagen.MakeDefaultMove(suite.db)
 	notification := MoveApproved{
 		db:     suite.db,
 		logger: suite.logger,
 		moveID: move.ID,
 		session: &auth.Session{
-			UserID:          approver.ID,
+			UserID:          *approver.UserID,
+			OfficeUserID:    approver.ID,
 			ApplicationName: auth.OfficeApp,
 		},
 	}
@@ -85,6 +91,57 @@ func (suite *NotificationSuite) TestMoveSubmitted() {
 	suite.NotEmpty(email.textBody)
 }
 
+func (suite *NotificationSuite) TestShipmentAccepted() {
+	t := suite.T()
+
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	notification := NewShipmentAccepted(suite.db, suite.logger, shipment.ID)
+
+	emails, err := notification.emails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite.Equal(1, len(emails))
+	suite.Equal(*shipment.ServiceMember.PersonalEmail, emails[0].recipientEmail)
+	suite.Equal(shipment.ServiceMember.UserID, *emails[0].userID)
+
+	inboxMessages, err := notification.inboxMessages()
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite.Equal(1, len(inboxMessages))
+	suite.Equal(shipment.ServiceMember.UserID, inboxMessages[0].userID)
+	suite.Equal(emails[0].subject, inboxMessages[0].subject)
+}
+
+func (suite *NotificationSuite) TestShipmentRejectedNotifiesOffice() {
+	t := suite.T()
+
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	office := models.TransportationOffice{ID: *shipment.Move.Orders.ServiceMember.DutyStation.TransportationOfficeID}
+	officeUser := testdatagen.MakeOfficeUser(suite.db, testdatagen.Assertions{
+		OfficeUser: models.OfficeUser{TransportationOffice: office},
+	})
+	notification := NewShipmentRejected(suite.db, suite.logger, shipment.ID, "No capacity")
+	suite.Equal(officeUserChannels, notification.channels())
+
+	emails, err := notification.emails()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var recipients []string
+	for _, email := range emails {
+		recipients = append(recipients, email.recipientEmail)
+		suite.Contains(email.textBody, "No capacity")
+	}
+	suite.Contains(recipients, officeUser.Email)
+
+	// Office users aren't sent text messages
+	textMessages, err := notification.textMessages()
+	suite.Nil(err)
+	suite.Empty(textMessages)
+}
+
 func (suite *NotificationSuite) GetTestEmailContent() emailContent {
 	return emailContent{
 		recipientEmail: "lucky@winner.com",
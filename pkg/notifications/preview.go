@@ -0,0 +1,73 @@
+package notifications
+
+import (
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// PreviewEmail renders a template with the data of a move the session may access, without sending
+// anything. SLA breaches are previewed as if the move had been waiting since it was created, and
+// shipment events with the move's first shipment.
+// Templates that fail to render with the move's data are returned as validation errors.
+func PreviewEmail(db *pop.Connection,
+	logger *zap.Logger,
+	session *auth.Session,
+	template models.NotificationTemplate,
+	moveID uuid.UUID) (*RenderedEmail, *validate.Errors, error) {
+
+	verrs := validate.NewErrors()
+
+	move, err := models.FetchMove(db, session, moveID)
+	if err != nil {
+		return nil, verrs, err
+	}
+
+	var data interface{}
+	switch template.Event {
+	case EventMoveApproved:
+		data, _, err = NewMoveApproved(db, logger, session, moveID).templateData()
+	case EventMoveCanceled:
+		data, _, err = NewMoveCanceled(db, logger, session, moveID).templateData()
+	case EventMoveSubmitted:
+		data, _, err = NewMoveSubmitted(db, logger, session, moveID).templateData()
+	case EventSLABreached:
+		escalation := models.SLAEscalation{
+			RecordType: "move",
+			RecordID:   move.ID,
+			MoveID:     move.ID,
+			EnteredAt:  move.CreatedAt,
+			DueAt:      time.Now(),
+		}
+		data, err = NewSLABreached(db, logger, session.Hostname, escalation).templateData()
+	default:
+		if _, ok := shipmentEventRecipients[template.Event]; !ok {
+			return nil, verrs, models.ErrFetchNotFound
+		}
+		if len(move.Shipments) == 0 {
+			verrs.Add("move_id", "The move has no shipment to preview the template with.")
+			return nil, verrs, nil
+		}
+		event := ShipmentEvent{db: db, logger: logger, event: template.Event, shipmentID: move.Shipments[0].ID}
+		var shipment models.Shipment
+		if shipment, err = event.fetchShipment(); err == nil {
+			data = event.templateData(shipment)
+		}
+	}
+	if err != nil {
+		return nil, verrs, err
+	}
+
+	email, err := renderTemplate(template, data)
+	if err != nil {
+		verrs.Add("template", err.Error())
+		return nil, verrs, nil
+	}
+	return &email, verrs, nil
+}
@@ -0,0 +1,136 @@
+package notifications
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// snsMessage is the envelope SNS delivers SES events in
+type snsMessage struct {
+	Type    string `json:"Type"`
+	Message string `json:"Message"`
+}
+
+// sesEvent is an SES bounce, complaint or delivery notification
+type sesEvent struct {
+	NotificationType string `json:"notificationType"`
+	// EventType replaces NotificationType in events published through configuration sets
+	EventType string `json:"eventType"`
+	Mail      struct {
+		MessageID string `json:"messageId"`
+	} `json:"mail"`
+	Bounce *struct {
+		BounceType    string `json:"bounceType"`
+		BounceSubType string `json:"bounceSubType"`
+	} `json:"bounce"`
+	Complaint *struct {
+		ComplaintFeedbackType string `json:"complaintFeedbackType"`
+	} `json:"complaint"`
+}
+
+func parseSESEvent(message []byte) (sesEvent, error) {
+	var event sesEvent
+	var envelope snsMessage
+	if err := json.Unmarshal(message, &envelope); err != nil {
+		return event, errors.Wrap(err, "parsing SES event")
+	}
+	if envelope.Type == "Notification" {
+		message = []byte(envelope.Message)
+	}
+	if err := json.Unmarshal(message, &event); err != nil {
+		return event, errors.Wrap(err, "parsing SES event")
+	}
+	if event.NotificationType == "" {
+		event.NotificationType = event.EventType
+	}
+	if event.Mail.MessageID == "" {
+		return event, errors.New("SES event has no message ID")
+	}
+	return event, nil
+}
+
+// IngestSESEvent updates the notification log with an SES delivery, bounce or complaint event,
+// delivered directly or in an SNS envelope. Only permanent bounces suppress later emails to the
+// recipient; transient bounces are recorded as detail.
+func IngestSESEvent(db *pop.Connection, logger *zap.Logger, message []byte) error {
+	event, err := parseSESEvent(message)
+	if err != nil {
+		return err
+	}
+
+	log, err := models.FetchNotificationLogBySESMessageID(db, event.Mail.MessageID)
+	if err == models.ErrFetchNotFound {
+		logger.Warn("SES event for unknown email", zap.String("ses_message_id", event.Mail.MessageID))
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	switch event.NotificationType {
+	case "Delivery":
+		// Bounces and complaints can arrive before the delivery they follow
+		if log.Status != models.NotificationStatusSENT {
+			return nil
+		}
+		log.Status = models.NotificationStatusDELIVERED
+	case "Bounce":
+		if event.Bounce == nil {
+			return errors.New("SES bounce event has no bounce")
+		}
+		detail := fmt.Sprintf("%s bounce: %s", event.Bounce.BounceType, event.Bounce.BounceSubType)
+		log.StatusDetail = &detail
+		if event.Bounce.BounceType == "Permanent" {
+			log.Status = models.NotificationStatusBOUNCED
+		}
+	case "Complaint":
+		log.Status = models.NotificationStatusCOMPLAINED
+		if event.Complaint != nil && event.Complaint.ComplaintFeedbackType != "" {
+			log.StatusDetail = &event.Complaint.ComplaintFeedbackType
+		}
+	default:
+		logger.Debug("Ignoring SES event", zap.String("notification_type", event.NotificationType))
+		return nil
+	}
+
+	verrs, err := db.ValidateAndUpdate(log)
+	if err != nil {
+		return err
+	}
+	if verrs.HasAny() {
+		return errors.New(verrs.String())
+	}
+	logger.Info("Ingested SES event",
+		zap.String("ses_message_id", event.Mail.MessageID),
+		zap.String("notification_type", event.NotificationType),
+		zap.String("status", string(log.Status)))
+	return nil
+}
+
+// IngestSESEvents ingests SES events from a reader with one event per line, such as a file of
+// messages received from the SES notification queue. It returns how many lines were ingested and
+// stops at the first event that can't be.
+func IngestSESEvents(db *pop.Connection, logger *zap.Logger, r io.Reader) (int, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	ingested := 0
+	for line := 1; scanner.Scan(); line++ {
+		message := strings.TrimSpace(scanner.Text())
+		if message == "" {
+			continue
+		}
+		if err := IngestSESEvent(db, logger, []byte(message)); err != nil {
+			return ingested, errors.Wrapf(err, "line %d", line)
+		}
+		ingested++
+	}
+	return ingested, scanner.Err()
+}
@@ -0,0 +1,276 @@
+package notifications
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// shipmentRecipient is who is notified of a shipment event
+type shipmentRecipient int
+
+const (
+	shipmentRecipientServiceMember shipmentRecipient = iota
+	shipmentRecipientTSP
+	shipmentRecipientOffice
+)
+
+// shipmentEventRecipients are who each shipment event is sent to
+var shipmentEventRecipients = map[string]shipmentRecipient{
+	EventShipmentOffered:            shipmentRecipientTSP,
+	EventShipmentAccepted:           shipmentRecipientServiceMember,
+	EventShipmentRejected:           shipmentRecipientOffice,
+	EventPremoveSurveyScheduled:     shipmentRecipientServiceMember,
+	EventPremoveSurveySlotsProposed: shipmentRecipientServiceMember,
+	EventPremoveSurveyConfirmed:     shipmentRecipientTSP,
+	EventShipmentPickedUp:           shipmentRecipientServiceMember,
+	EventShipmentDelivered:          shipmentRecipientServiceMember,
+	EventPreApprovalRequested:       shipmentRecipientOffice,
+	EventSurveyRequested:            shipmentRecipientServiceMember,
+}
+
+// ShipmentEvent has notification content for changes in a shipment's lifecycle. Each event is sent
+// to the service member, the TSP handling the shipment or the move's transportation office.
+type ShipmentEvent struct {
+	db         *pop.Connection
+	logger     *zap.Logger
+	event      string
+	shipmentID uuid.UUID
+	tspID      *uuid.UUID
+	reason     string
+	lineItem   string
+	surveyURL  string
+}
+
+// NewShipmentOffered returns a notification to the users of a TSP that was offered a shipment
+func NewShipmentOffered(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID, tspID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventShipmentOffered, shipmentID: shipmentID, tspID: &tspID}
+}
+
+// NewShipmentAccepted returns a notification to the service member that a TSP accepted their shipment
+func NewShipmentAccepted(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventShipmentAccepted, shipmentID: shipmentID}
+}
+
+// NewShipmentRejected returns a notification to the transportation office that a TSP rejected a shipment
+func NewShipmentRejected(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID, reason string) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventShipmentRejected, shipmentID: shipmentID, reason: reason}
+}
+
+// NewPremoveSurveyScheduled returns a notification to the service member with the date of their
+// pre-move survey and the planned move dates
+func NewPremoveSurveyScheduled(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventPremoveSurveyScheduled, shipmentID: shipmentID}
+}
+
+// NewPremoveSurveySlotsProposed returns a notification to the service member that the TSP proposed
+// times for the pre-move survey for them to choose from
+func NewPremoveSurveySlotsProposed(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventPremoveSurveySlotsProposed, shipmentID: shipmentID}
+}
+
+// NewPremoveSurveyConfirmed returns a notification to the users of the TSP that proposed the time
+// the service member confirmed for the pre-move survey
+func NewPremoveSurveyConfirmed(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID, tspID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventPremoveSurveyConfirmed, shipmentID: shipmentID, tspID: &tspID}
+}
+
+// NewShipmentPickedUp returns a notification to the service member that their shipment was picked up
+func NewShipmentPickedUp(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventShipmentPickedUp, shipmentID: shipmentID}
+}
+
+// NewShipmentDelivered returns a notification to the service member that their shipment was delivered
+func NewShipmentDelivered(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventShipmentDelivered, shipmentID: shipmentID}
+}
+
+// NewPreApprovalRequested returns a notification to the transportation office that a TSP requested
+// pre-approval of a line item, described by lineItem
+func NewPreApprovalRequested(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID, lineItem string) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventPreApprovalRequested, shipmentID: shipmentID, lineItem: lineItem}
+}
+
+// NewSurveyRequested returns a notification to the service member with the one-time link to the
+// satisfaction survey about their delivered shipment
+func NewSurveyRequested(db *pop.Connection, logger *zap.Logger, shipmentID uuid.UUID, surveyURL string) *ShipmentEvent {
+	return &ShipmentEvent{db: db, logger: logger, event: EventSurveyRequested, shipmentID: shipmentID, surveyURL: surveyURL}
+}
+
+// formatShipmentDate formats the first date that is known, or returns an empty string
+func formatShipmentDate(dates ...*time.Time) string {
+	for _, date := range dates {
+		if date != nil {
+			return date.Format("Jan 2, 2006")
+		}
+	}
+	return ""
+}
+
+func (s ShipmentEvent) fetchShipment() (models.Shipment, error) {
+	var shipment models.Shipment
+	err := s.db.Eager(
+		"ServiceMember",
+		"Move.Orders.NewDutyStation",
+		"PickupAddress",
+		"ShipmentOffers.TransportationServiceProvider").Find(&shipment, s.shipmentID)
+	return shipment, err
+}
+
+// tsp returns the TSP the event is about: the one offered the shipment, or else the one it was
+// offered to most recently
+func (s ShipmentEvent) tsp(shipment models.Shipment) *models.TransportationServiceProvider {
+	for _, offer := range shipment.ShipmentOffers {
+		if s.tspID == nil || offer.TransportationServiceProviderID == *s.tspID {
+			return &offer.TransportationServiceProvider
+		}
+	}
+	return nil
+}
+
+func (s ShipmentEvent) templateData(shipment models.Shipment) shipmentData {
+	data := shipmentData{
+		Locator:     shipment.Move.Locator,
+		Destination: shipment.Move.Orders.NewDutyStation.Name,
+		// Dates are the most current known: actual, then planned at the pre-move survey, then requested
+		PackDate:     formatShipmentDate(shipment.ActualPackDate, shipment.PmSurveyPlannedPackDate, shipment.OriginalPackDate),
+		PickupDate:   formatShipmentDate(shipment.ActualPickupDate, shipment.PmSurveyPlannedPickupDate, shipment.RequestedPickupDate),
+		DeliveryDate: formatShipmentDate(shipment.ActualDeliveryDate, shipment.PmSurveyPlannedDeliveryDate, shipment.OriginalDeliveryDate),
+		SurveyDate:   formatShipmentDate(shipment.PmSurveyConductedDate),
+		Reason:       s.reason,
+		LineItem:     s.lineItem,
+		SurveyURL:    s.surveyURL,
+	}
+	if shipment.GBLNumber != nil {
+		data.GBLNumber = *shipment.GBLNumber
+	}
+	if shipment.PickupAddress != nil {
+		data.Origin = fmt.Sprintf("%s, %s", shipment.PickupAddress.City, shipment.PickupAddress.State)
+	}
+	if tsp := s.tsp(shipment); tsp != nil {
+		data.TSPName = tsp.StandardCarrierAlphaCode
+		if tsp.Name != nil {
+			data.TSPName = *tsp.Name
+		}
+	}
+	return data
+}
+
+// shipmentEventUser is someone a shipment event is sent to
+type shipmentEventUser struct {
+	email  string
+	userID *uuid.UUID
+}
+
+// recipientUsers returns everyone the event is sent to
+func (s ShipmentEvent) recipientUsers(shipment models.Shipment) ([]shipmentEventUser, error) {
+	recipients := []shipmentEventUser{}
+	switch shipmentEventRecipients[s.event] {
+	case shipmentRecipientServiceMember:
+		recipient := shipmentEventUser{userID: &shipment.ServiceMember.UserID}
+		if shipment.ServiceMember.PersonalEmail != nil {
+			recipient.email = *shipment.ServiceMember.PersonalEmail
+		}
+		recipients = append(recipients, recipient)
+	case shipmentRecipientTSP:
+		tsp := s.tsp(shipment)
+		if tsp == nil {
+			return recipients, nil
+		}
+		tspUsers, err := models.FetchTspUsersForTSP(s.db, tsp.ID)
+		if err != nil {
+			return recipients, err
+		}
+		for _, tspUser := range tspUsers {
+			if !tspUser.Deactivated {
+				recipients = append(recipients, shipmentEventUser{email: tspUser.Email, userID: tspUser.UserID})
+			}
+		}
+	case shipmentRecipientOffice:
+		officeUsers, err := models.FetchMoveOfficeUsers(s.db, shipment.MoveID)
+		if err != nil {
+			return recipients, err
+		}
+		for _, officeUser := range officeUsers {
+			recipients = append(recipients, shipmentEventUser{email: officeUser.Email, userID: officeUser.UserID})
+		}
+	}
+	return recipients, nil
+}
+
+func (s ShipmentEvent) channels() []models.NotificationChannel {
+	switch shipmentEventRecipients[s.event] {
+	case shipmentRecipientServiceMember:
+		return serviceMemberChannels
+	case shipmentRecipientOffice:
+		return officeUserChannels
+	}
+	return tspUserChannels
+}
+
+func (s ShipmentEvent) emails() ([]emailContent, error) {
+	var emails []emailContent
+
+	shipment, err := s.fetchShipment()
+	if err != nil {
+		return emails, err
+	}
+	recipients, err := s.recipientUsers(shipment)
+	if err != nil {
+		return emails, err
+	}
+	if len(recipients) == 0 {
+		s.logger.Warn("No one to notify of shipment event",
+			zap.String("event", s.event),
+			zap.String("shipment_id", s.shipmentID.String()))
+		return emails, nil
+	}
+
+	email, err := renderEmail(s.db, s.event, s.templateData(shipment), "")
+	if err != nil {
+		return emails, err
+	}
+	for _, recipient := range recipients {
+		if recipient.email == "" {
+			continue
+		}
+		email.recipientEmail = recipient.email
+		email.userID = recipient.userID
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+func (s ShipmentEvent) textMessages() ([]textMessageContent, error) {
+	if shipmentEventRecipients[s.event] != shipmentRecipientServiceMember {
+		return nil, nil
+	}
+	shipment, err := s.fetchShipment()
+	if err != nil {
+		return nil, err
+	}
+	return serviceMemberTextMessages(s.db, s.event, s.templateData(shipment), shipment.ServiceMember)
+}
+
+func (s ShipmentEvent) inboxMessages() ([]inboxContent, error) {
+	shipment, err := s.fetchShipment()
+	if err != nil {
+		return nil, err
+	}
+	recipients, err := s.recipientUsers(shipment)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uuid.UUID
+	for _, recipient := range recipients {
+		if recipient.userID != nil {
+			userIDs = append(userIDs, *recipient.userID)
+		}
+	}
+	return renderInboxMessages(s.db, s.event, s.templateData(shipment), userIDs)
+}
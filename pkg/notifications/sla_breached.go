@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// slaRecordDescriptions describe the kinds of records SLAs are tracked for in escalation emails
+var slaRecordDescriptions = map[string]string{
+	"move":                     "move",
+	"personally_procured_move": "PPM payment request",
+	"move_document":            "document",
+}
+
+// SLABreached has notification content for records that are overdue for office action
+type SLABreached struct {
+	db             *pop.Connection
+	logger         *zap.Logger
+	officeHostname string
+	escalation     models.SLAEscalation
+}
+
+// NewSLABreached returns a new SLA breach notification, sent to the supervisors of the move's
+// transportation office and to the office user working on the record
+func NewSLABreached(db *pop.Connection,
+	logger *zap.Logger,
+	officeHostname string,
+	escalation models.SLAEscalation) *SLABreached {
+
+	return &SLABreached{
+		db:             db,
+		logger:         logger,
+		officeHostname: officeHostname,
+		escalation:     escalation,
+	}
+}
+
+func (s SLABreached) recipients() ([]models.OfficeUser, error) {
+	recipients := []models.OfficeUser{}
+	seen := map[string]bool{}
+	add := func(officeUser models.OfficeUser) {
+		if officeUser.Deactivated || seen[officeUser.Email] {
+			return
+		}
+		seen[officeUser.Email] = true
+		recipients = append(recipients, officeUser)
+	}
+
+	if s.escalation.TransportationOfficeID != nil {
+		officeUsers, err := models.FetchOfficeUsers(s.db, s.escalation.TransportationOfficeID)
+		if err != nil {
+			return recipients, err
+		}
+		for _, officeUser := range officeUsers {
+			if officeUser.IsSupervisor {
+				add(officeUser)
+			}
+		}
+	}
+
+	claimRecordType := models.WorkClaimRecordType(s.escalation.RecordType)
+	if claimRecordType == models.WorkClaimRecordTypeMove || claimRecordType == models.WorkClaimRecordTypePPM {
+		var claims models.WorkClaims
+		err := s.db.Eager("OfficeUser").
+			Where("record_type = $1 AND record_id = $2", claimRecordType, s.escalation.RecordID).
+			All(&claims)
+		if err != nil {
+			return recipients, err
+		}
+		for _, claim := range claims {
+			if claim.IsActive() {
+				add(claim.OfficeUser)
+			}
+		}
+	}
+
+	return recipients, nil
+}
+
+func (s SLABreached) templateData() (slaBreachedData, error) {
+	var move models.Move
+	if err := s.db.Find(&move, s.escalation.MoveID); err != nil {
+		return slaBreachedData{}, err
+	}
+
+	moveURL := url.URL{
+		Scheme: "https",
+		Host:   s.officeHostname,
+		Path:   fmt.Sprintf("queues/new/moves/%s", move.ID),
+	}
+	data := slaBreachedData{
+		Locator:     move.Locator,
+		Description: slaRecordDescriptions[s.escalation.RecordType],
+		EnteredAt:   s.escalation.EnteredAt,
+		DueAt:       s.escalation.DueAt,
+		MoveURL:     moveURL.String(),
+	}
+	return data, nil
+}
+
+func (s SLABreached) emails() ([]emailContent, error) {
+	var emails []emailContent
+
+	data, err := s.templateData()
+	if err != nil {
+		return emails, err
+	}
+
+	recipients, err := s.recipients()
+	if err != nil {
+		return emails, err
+	}
+	if len(recipients) == 0 {
+		s.logger.Warn("No one to escalate overdue record to",
+			zap.String("record_type", s.escalation.RecordType),
+			zap.String("record_id", s.escalation.RecordID.String()))
+		return emails, nil
+	}
+
+	email, err := renderEmail(s.db, EventSLABreached, data, "")
+	if err != nil {
+		return emails, err
+	}
+	for _, recipient := range recipients {
+		email.recipientEmail = recipient.Email
+		email.userID = recipient.UserID
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+func (s SLABreached) channels() []models.NotificationChannel {
+	return officeUserChannels
+}
+
+func (s SLABreached) inboxMessages() ([]inboxContent, error) {
+	data, err := s.templateData()
+	if err != nil {
+		return nil, err
+	}
+
+	recipients, err := s.recipients()
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []uuid.UUID
+	for _, recipient := range recipients {
+		if recipient.UserID != nil {
+			userIDs = append(userIDs, *recipient.UserID)
+		}
+	}
+	return renderInboxMessages(s.db, EventSLABreached, data, userIDs)
+}
@@ -0,0 +1,417 @@
+package notifications
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"sort"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gobuffalo/validate"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// Notification events that send emails. Each has a template in defaultTemplates and the data its
+// templates are rendered with in sampleTemplateData.
+const (
+	// EventMoveApproved is sent to the service member when the office approves their move
+	EventMoveApproved = "move_approved"
+	// EventMoveCanceled is sent to the service member when their move is canceled
+	EventMoveCanceled = "move_canceled"
+	// EventMoveSubmitted is sent to the service member when they submit their move
+	EventMoveSubmitted = "move_submitted"
+	// EventSLABreached is sent to office users when a record is overdue for office action
+	EventSLABreached = "sla_breached"
+	// EventShipmentOffered is sent to TSP users when the award queue offers their TSP a shipment
+	EventShipmentOffered = "shipment_offered"
+	// EventShipmentAccepted is sent to the service member when a TSP accepts their shipment
+	EventShipmentAccepted = "shipment_accepted"
+	// EventShipmentRejected is sent to office users when a TSP rejects a shipment
+	EventShipmentRejected = "shipment_rejected"
+	// EventPremoveSurveyScheduled is sent to the service member when the TSP schedules the pre-move survey
+	EventPremoveSurveyScheduled = "premove_survey_scheduled"
+	// EventPremoveSurveySlotsProposed is sent to the service member when the TSP proposes times for the pre-move survey
+	EventPremoveSurveySlotsProposed = "premove_survey_slots_proposed"
+	// EventPremoveSurveyConfirmed is sent to TSP users when the service member confirms a time for the pre-move survey
+	EventPremoveSurveyConfirmed = "premove_survey_confirmed"
+	// EventShipmentPickedUp is sent to the service member when the TSP picks up their shipment
+	EventShipmentPickedUp = "shipment_picked_up"
+	// EventShipmentDelivered is sent to the service member when the TSP delivers their shipment
+	EventShipmentDelivered = "shipment_delivered"
+	// EventPreApprovalRequested is sent to office users when a TSP requests pre-approval of a line item
+	EventPreApprovalRequested = "preapproval_requested"
+	// EventSurveyRequested is sent to the service member after delivery with a link to the satisfaction survey
+	EventSurveyRequested = "survey_requested"
+)
+
+// ServiceMemberEvents are the events service members are notified of
+var ServiceMemberEvents = []string{
+	EventMoveApproved,
+	EventMoveCanceled,
+	EventMoveSubmitted,
+	EventShipmentAccepted,
+	EventPremoveSurveyScheduled,
+	EventPremoveSurveySlotsProposed,
+	EventShipmentPickedUp,
+	EventShipmentDelivered,
+	EventSurveyRequested,
+}
+
+// OfficeUserEvents are the events office users are notified of
+var OfficeUserEvents = []string{EventSLABreached, EventShipmentRejected, EventPreApprovalRequested}
+
+// TSPUserEvents are the events TSP users are notified of
+var TSPUserEvents = []string{EventShipmentOffered, EventPremoveSurveyConfirmed}
+
+// RenderedEmail is an email rendered from an event's templates
+type RenderedEmail struct {
+	Subject  string
+	HTMLBody string
+	TextBody string
+}
+
+type moveApprovedData struct {
+	Locator         string
+	HasPPM          bool
+	PPMInfoSheetURL string
+}
+
+type moveCanceledData struct {
+	Locator                string
+	OriginDutyStation      string
+	DestinationDutyStation string
+	PPPOPhone              string
+}
+
+type moveSubmittedData struct {
+	Locator string
+	// OriginDutyStation, DestinationDutyStation and PPPOPhone are empty when the service member
+	// has no duty station
+	OriginDutyStation      string
+	DestinationDutyStation string
+	PPPOPhone              string
+}
+
+type slaBreachedData struct {
+	Locator     string
+	Description string
+	EnteredAt   time.Time
+	DueAt       time.Time
+	MoveURL     string
+}
+
+// shipmentData is the data of every shipment event. Dates are formatted, and empty when unknown.
+type shipmentData struct {
+	Locator      string
+	GBLNumber    string
+	Origin       string
+	Destination  string
+	TSPName      string
+	SurveyDate   string
+	PackDate     string
+	PickupDate   string
+	DeliveryDate string
+	// Reason is why a TSP rejected the shipment
+	Reason string
+	// LineItem describes the line item a TSP requested pre-approval of
+	LineItem string
+	// SurveyURL is the one-time link to the satisfaction survey about the shipment
+	SurveyURL string
+}
+
+// defaultTemplates are sent for events that have no versions stored in the database. Copy comes from here:
+// https://docs.google.com/document/d/1bgE0Q_-_c93uruMP8dcNSHugXo8Pidz6YFojWBKn1Gg/edit#heading=h.h3ys1ur2qhpn
+var defaultTemplates = map[string]models.NotificationTemplate{
+	EventMoveApproved: {
+		Event:   EventMoveApproved,
+		Subject: `MOVE.MIL: Your move has been approved.`,
+		HTMLBody: `Your move has been approved and you are ready to move!` +
+			`{{if .HasPPM}} Please review the PPM info sheet for more detailed instructions: <a href="{{.PPMInfoSheetURL}}">{{.PPMInfoSheetURL}}</a>{{end}}<br/>` +
+			`Next steps:<br/>` +
+			`{{if .HasPPM}}For your “Do-it-Yourself” shipment, you can begin your move whenever you are ready. Be sure to save your weight tickets and any receipts associated with your move for when you request payment later on in the process.<br/>{{end}}` +
+			`If you have any questions, contact your origin PPPO.`,
+		TextBody: `Your move has been approved and you are ready to move!` +
+			`{{if .HasPPM}} Please review the PPM info sheet for more detailed instructions: {{.PPMInfoSheetURL}}{{end}}
+Next steps:
+{{if .HasPPM}}For your “Do-it-Yourself” shipment, you can begin your move whenever you are ready. Be sure to save your weight tickets and any receipts associated with your move for when you request payment later on in the process.
+{{end}}If you have any questions, contact your origin PPPO.`,
+	},
+	EventMoveCanceled: {
+		Event:   EventMoveCanceled,
+		Subject: `MOVE.MIL: Your move has been canceled.`,
+		HTMLBody: `Your move from {{.OriginDutyStation}} to {{.DestinationDutyStation}} with the move locator ID {{.Locator}} was canceled.<br/>` +
+			`Contact your local PPPO {{.OriginDutyStation}} at {{.PPPOPhone}} if you have any questions.`,
+		TextBody: `Your move from {{.OriginDutyStation}} to {{.DestinationDutyStation}} with the move locator ID {{.Locator}} was canceled.
+Contact your local PPPO {{.OriginDutyStation}} at {{.PPPOPhone}} if you have any questions.`,
+	},
+	EventMoveSubmitted: {
+		Event:    EventMoveSubmitted,
+		Subject:  `MOVE.MIL: Your move has been submitted.`,
+		HTMLBody: moveSubmittedBody,
+		TextBody: moveSubmittedBody,
+	},
+	EventSLABreached: {
+		Event:   EventSLABreached,
+		Subject: `MOVE.MIL: Overdue {{.Description}} for move {{.Locator}}`,
+		HTMLBody: `The {{.Description}} for move {{.Locator}} has been waiting since {{.EnteredAt.Format "Jan 2, 2006"}} and was due by {{.DueAt.Format "Jan 2, 2006 15:04 MST"}}.<br/>` +
+			`Please review it or assign it to someone who can: <a href="{{.MoveURL}}">{{.MoveURL}}</a>`,
+		TextBody: `The {{.Description}} for move {{.Locator}} has been waiting since {{.EnteredAt.Format "Jan 2, 2006"}} and was due by {{.DueAt.Format "Jan 2, 2006 15:04 MST"}}.
+Please review it or assign it to someone who can: {{.MoveURL}}`,
+	},
+	EventShipmentOffered: {
+		Event:   EventShipmentOffered,
+		Subject: `MOVE.MIL: New shipment offered from {{.Origin}} to {{.Destination}}`,
+		HTMLBody: `{{.TSPName}} has been offered a shipment from {{.Origin}} to {{.Destination}}` +
+			`{{if .PickupDate}} with a requested pickup date of {{.PickupDate}}{{end}}.<br/>` +
+			`Log in to the TSP app to accept or reject it.`,
+		TextBody: `{{.TSPName}} has been offered a shipment from {{.Origin}} to {{.Destination}}` +
+			`{{if .PickupDate}} with a requested pickup date of {{.PickupDate}}{{end}}.
+Log in to the TSP app to accept or reject it.`,
+	},
+	EventShipmentAccepted: {
+		Event:   EventShipmentAccepted,
+		Subject: `MOVE.MIL: A mover has accepted your shipment.`,
+		HTMLBody: `{{.TSPName}} has accepted the shipment for your move {{.Locator}}.<br/>` +
+			`They will contact you to schedule a pre-move survey of your belongings.`,
+		TextBody: `{{.TSPName}} has accepted the shipment for your move {{.Locator}}.
+They will contact you to schedule a pre-move survey of your belongings.`,
+	},
+	EventShipmentRejected: {
+		Event:   EventShipmentRejected,
+		Subject: `MOVE.MIL: {{.TSPName}} rejected the shipment for move {{.Locator}}`,
+		HTMLBody: `{{.TSPName}} rejected the shipment for move {{.Locator}}: {{.Reason}}<br/>` +
+			`The shipment will be offered to the next TSP.`,
+		TextBody: `{{.TSPName}} rejected the shipment for move {{.Locator}}: {{.Reason}}
+The shipment will be offered to the next TSP.`,
+	},
+	EventPremoveSurveyScheduled: {
+		Event:   EventPremoveSurveyScheduled,
+		Subject: `MOVE.MIL: Your pre-move survey has been scheduled.`,
+		HTMLBody: `{{.TSPName}} has scheduled the pre-move survey for your move {{.Locator}} on {{.SurveyDate}}.<br/>` +
+			`{{if .PackDate}}Planned pack date: {{.PackDate}}<br/>{{end}}` +
+			`{{if .PickupDate}}Planned pickup date: {{.PickupDate}}<br/>{{end}}` +
+			`{{if .DeliveryDate}}Planned delivery date: {{.DeliveryDate}}<br/>{{end}}` +
+			`Contact {{.TSPName}} if you need to change these dates.`,
+		TextBody: `{{.TSPName}} has scheduled the pre-move survey for your move {{.Locator}} on {{.SurveyDate}}.
+{{if .PackDate}}Planned pack date: {{.PackDate}}
+{{end}}{{if .PickupDate}}Planned pickup date: {{.PickupDate}}
+{{end}}{{if .DeliveryDate}}Planned delivery date: {{.DeliveryDate}}
+{{end}}Contact {{.TSPName}} if you need to change these dates.`,
+	},
+	EventPremoveSurveySlotsProposed: {
+		Event:   EventPremoveSurveySlotsProposed,
+		Subject: `MOVE.MIL: Choose a time for your pre-move survey.`,
+		HTMLBody: `{{.TSPName}} has proposed times for the pre-move survey for your move {{.Locator}}.<br/>` +
+			`Log in to choose the time that works best for you.`,
+		TextBody: `{{.TSPName}} has proposed times for the pre-move survey for your move {{.Locator}}.
+Log in to choose the time that works best for you.`,
+	},
+	EventPremoveSurveyConfirmed: {
+		Event:   EventPremoveSurveyConfirmed,
+		Subject: `MOVE.MIL: Pre-move survey confirmed for move {{.Locator}}`,
+		HTMLBody: `The service member confirmed the pre-move survey for move {{.Locator}} on {{.SurveyDate}}.<br/>` +
+			`Log in to the TSP app to see the appointment and record the survey.`,
+		TextBody: `The service member confirmed the pre-move survey for move {{.Locator}} on {{.SurveyDate}}.
+Log in to the TSP app to see the appointment and record the survey.`,
+	},
+	EventShipmentPickedUp: {
+		Event:   EventShipmentPickedUp,
+		Subject: `MOVE.MIL: Your shipment has been picked up.`,
+		HTMLBody: `{{.TSPName}} picked up the shipment for your move {{.Locator}} on {{.PickupDate}}` +
+			`{{if .DeliveryDate}}. It is expected to be delivered by {{.DeliveryDate}}{{end}}.`,
+		TextBody: `{{.TSPName}} picked up the shipment for your move {{.Locator}} on {{.PickupDate}}` +
+			`{{if .DeliveryDate}}. It is expected to be delivered by {{.DeliveryDate}}{{end}}.`,
+	},
+	EventShipmentDelivered: {
+		Event:   EventShipmentDelivered,
+		Subject: `MOVE.MIL: Your shipment has been delivered.`,
+		HTMLBody: `{{.TSPName}} delivered the shipment for your move {{.Locator}} to {{.Destination}} on {{.DeliveryDate}}.<br/>` +
+			`If anything was lost or damaged, contact your local PPPO.`,
+		TextBody: `{{.TSPName}} delivered the shipment for your move {{.Locator}} to {{.Destination}} on {{.DeliveryDate}}.
+If anything was lost or damaged, contact your local PPPO.`,
+	},
+	EventPreApprovalRequested: {
+		Event:   EventPreApprovalRequested,
+		Subject: `MOVE.MIL: Pre-approval requested for move {{.Locator}}`,
+		HTMLBody: `{{.TSPName}} requested pre-approval of {{.LineItem}} for the shipment of move {{.Locator}}` +
+			`{{if .GBLNumber}} (GBL {{.GBLNumber}}){{end}}.<br/>` +
+			`Please approve it or follow up with the TSP in the office app.`,
+		TextBody: `{{.TSPName}} requested pre-approval of {{.LineItem}} for the shipment of move {{.Locator}}` +
+			`{{if .GBLNumber}} (GBL {{.GBLNumber}}){{end}}.
+Please approve it or follow up with the TSP in the office app.`,
+	},
+	EventSurveyRequested: {
+		Event:   EventSurveyRequested,
+		Subject: `MOVE.MIL: How did your move go?`,
+		HTMLBody: `Your shipment for move {{.Locator}} was delivered by {{.TSPName}} on {{.DeliveryDate}}.<br/>` +
+			`Please take a few minutes to tell us how it went: <a href="{{.SurveyURL}}">{{.SurveyURL}}</a><br/>` +
+			`Your answers help us choose the movers we offer shipments to.`,
+		TextBody: `Your shipment for move {{.Locator}} was delivered by {{.TSPName}} on {{.DeliveryDate}}.
+Please take a few minutes to tell us how it went: {{.SurveyURL}}
+Your answers help us choose the movers we offer shipments to.`,
+	},
+}
+
+const moveSubmittedBody = `{{if .OriginDutyStation}}Your move from {{.OriginDutyStation}} to {{.DestinationDutyStation}} has been submitted to your local transportation office for review.` +
+	`{{else}}Your move has been submitted to your local transportation office for review.{{end}}` +
+	` This process can take up to 3 business days.` +
+	`{{if .OriginDutyStation}} If you have questions or need expedited processing contact your local PPPO {{.OriginDutyStation}} at {{.PPPOPhone}}.` +
+	`{{else}} If you have questions or need expedited processing contact your local transportation office.{{end}}`
+
+// sampleTemplateData is rendered to check that templates only use the data of their event
+var sampleTemplateData = map[string]interface{}{
+	EventMoveApproved: moveApprovedData{
+		Locator:         "ABC123",
+		HasPPM:          true,
+		PPMInfoSheetURL: "https://my.move.mil/downloads/ppm_info_sheet.pdf",
+	},
+	EventMoveCanceled: moveCanceledData{
+		Locator:                "ABC123",
+		OriginDutyStation:      "Fort Gordon",
+		DestinationDutyStation: "Yuma AFB",
+		PPPOPhone:              "(706) 791-8382",
+	},
+	EventMoveSubmitted: moveSubmittedData{
+		Locator:                "ABC123",
+		OriginDutyStation:      "Fort Gordon",
+		DestinationDutyStation: "Yuma AFB",
+		PPPOPhone:              "(706) 791-8382",
+	},
+	EventSLABreached: slaBreachedData{
+		Locator:     "ABC123",
+		Description: "PPM payment request",
+		EnteredAt:   time.Date(2018, time.November, 26, 14, 0, 0, 0, time.UTC),
+		DueAt:       time.Date(2018, time.November, 29, 14, 0, 0, 0, time.UTC),
+		MoveURL:     "https://office.move.mil/queues/new/moves/c56a4180-65aa-42ec-a945-5fd21dec0538",
+	},
+	EventShipmentOffered:            sampleShipmentData,
+	EventShipmentAccepted:           sampleShipmentData,
+	EventShipmentRejected:           sampleShipmentData,
+	EventPremoveSurveyScheduled:     sampleShipmentData,
+	EventPremoveSurveySlotsProposed: sampleShipmentData,
+	EventPremoveSurveyConfirmed:     sampleShipmentData,
+	EventShipmentPickedUp:           sampleShipmentData,
+	EventShipmentDelivered:          sampleShipmentData,
+	EventPreApprovalRequested:       sampleShipmentData,
+	EventSurveyRequested:            sampleShipmentData,
+}
+
+var sampleShipmentData = shipmentData{
+	Locator:      "ABC123",
+	GBLNumber:    "KKFA7000001",
+	Origin:       "Augusta, GA",
+	Destination:  "Yuma AFB",
+	TSPName:      "Truss Movers",
+	SurveyDate:   "Nov 26, 2018",
+	PackDate:     "Dec 3, 2018",
+	PickupDate:   "Dec 4, 2018",
+	DeliveryDate: "Dec 11, 2018",
+	Reason:       "No capacity on the requested dates",
+	LineItem:     "105B Pack Reg Crate",
+	SurveyURL:    "https://my.move.mil/surveys/respond?token=c56a4180",
+}
+
+// NotificationEvents returns every event that sends emails in alphabetical order
+func NotificationEvents() []string {
+	events := make([]string, 0, len(defaultTemplates))
+	for event := range defaultTemplates {
+		events = append(events, event)
+	}
+	sort.Strings(events)
+	return events
+}
+
+// CurrentTemplate returns the latest stored version of an event's templates, or the default
+// templates with version 0 if none are stored
+func CurrentTemplate(db *pop.Connection, event string) (models.NotificationTemplate, error) {
+	defaultTemplate, ok := defaultTemplates[event]
+	if !ok {
+		return models.NotificationTemplate{}, models.ErrFetchNotFound
+	}
+
+	stored, err := models.FetchLatestNotificationTemplate(db, event)
+	if err != nil {
+		return models.NotificationTemplate{}, err
+	}
+	if stored == nil {
+		return defaultTemplate, nil
+	}
+	return *stored, nil
+}
+
+// ValidateTemplate checks that a template is for a known event and that its subject and bodies
+// render with the event's data
+func ValidateTemplate(template models.NotificationTemplate) *validate.Errors {
+	verrs := validate.NewErrors()
+	data, ok := sampleTemplateData[template.Event]
+	if !ok {
+		verrs.Add("event", template.Event+" is not a notification event.")
+		return verrs
+	}
+
+	if _, err := renderText(template.Event+" subject", template.Subject, data); err != nil {
+		verrs.Add("subject", err.Error())
+	}
+	if _, err := renderHTML(template.Event+" html body", template.HTMLBody, data); err != nil {
+		verrs.Add("html_body", err.Error())
+	}
+	if _, err := renderText(template.Event+" text body", template.TextBody, data); err != nil {
+		verrs.Add("text_body", err.Error())
+	}
+	return verrs
+}
+
+func renderText(name string, text string, data interface{}) (string, error) {
+	tmpl, err := texttemplate.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+// renderHTML renders HTML bodies with html/template so that move data is escaped
+func renderHTML(name string, text string, data interface{}) (string, error) {
+	tmpl, err := htmltemplate.New(name).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, data)
+	return buf.String(), err
+}
+
+func renderTemplate(template models.NotificationTemplate, data interface{}) (RenderedEmail, error) {
+	var email RenderedEmail
+	var err error
+	if email.Subject, err = renderText(template.Event+" subject", template.Subject, data); err != nil {
+		return email, err
+	}
+	if email.HTMLBody, err = renderHTML(template.Event+" html body", template.HTMLBody, data); err != nil {
+		return email, err
+	}
+	email.TextBody, err = renderText(template.Event+" text body", template.TextBody, data)
+	return email, err
+}
+
+// renderEmail renders the current templates of an event into an email for the recipient
+func renderEmail(db *pop.Connection, event string, data interface{}, recipientEmail string) (emailContent, error) {
+	template, err := CurrentTemplate(db, event)
+	if err != nil {
+		return emailContent{}, err
+	}
+	rendered, err := renderTemplate(template, data)
+	if err != nil {
+		return emailContent{}, err
+	}
+	return emailContent{
+		event:          event,
+		recipientEmail: recipientEmail,
+		subject:        rendered.Subject,
+		htmlBody:       rendered.HTMLBody,
+		textBody:       rendered.TextBody,
+	}, nil
+}
@@ -0,0 +1,122 @@
+package notifications
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/transcom/mymove/pkg/auth"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files of the notification templates")
+
+// goldenTemplateCases render every default template, and the branches of templates with conditionals
+var goldenTemplateCases = []struct {
+	name  string
+	event string
+	data  interface{}
+}{
+	{"move_approved", EventMoveApproved, sampleTemplateData[EventMoveApproved]},
+	{"move_approved_without_ppm", EventMoveApproved, moveApprovedData{Locator: "ABC123"}},
+	{"move_canceled", EventMoveCanceled, sampleTemplateData[EventMoveCanceled]},
+	{"move_submitted", EventMoveSubmitted, sampleTemplateData[EventMoveSubmitted]},
+	{"move_submitted_without_duty_station", EventMoveSubmitted, moveSubmittedData{Locator: "ABC123"}},
+	{"sla_breached", EventSLABreached, sampleTemplateData[EventSLABreached]},
+	{"shipment_offered", EventShipmentOffered, sampleShipmentData},
+	{"shipment_accepted", EventShipmentAccepted, sampleShipmentData},
+	{"shipment_rejected", EventShipmentRejected, sampleShipmentData},
+	{"premove_survey_scheduled", EventPremoveSurveyScheduled, sampleShipmentData},
+	{"premove_survey_scheduled_without_dates", EventPremoveSurveyScheduled, shipmentData{Locator: "ABC123", TSPName: "Truss Movers", SurveyDate: "Nov 26, 2018"}},
+	{"premove_survey_slots_proposed", EventPremoveSurveySlotsProposed, sampleShipmentData},
+	{"premove_survey_confirmed", EventPremoveSurveyConfirmed, sampleShipmentData},
+	{"shipment_picked_up", EventShipmentPickedUp, sampleShipmentData},
+	{"shipment_delivered", EventShipmentDelivered, sampleShipmentData},
+	{"preapproval_requested", EventPreApprovalRequested, sampleShipmentData},
+	{"survey_requested", EventSurveyRequested, sampleShipmentData},
+}
+
+func formatGoldenEmail(email RenderedEmail) string {
+	return fmt.Sprintf("Subject: %s\n\n-- html --\n%s\n\n-- text --\n%s\n", email.Subject, email.HTMLBody, email.TextBody)
+}
+
+// Run with -update to rewrite the golden files after changing a default template
+func (suite *NotificationSuite) TestDefaultTemplatesMatchGoldenFiles() {
+	covered := map[string]bool{}
+	for _, tc := range goldenTemplateCases {
+		covered[tc.event] = true
+		email, err := renderTemplate(defaultTemplates[tc.event], tc.data)
+		if !suite.NoError(err, tc.name) {
+			continue
+		}
+
+		golden := filepath.Join("testdata", tc.name+".golden")
+		actual := formatGoldenEmail(email)
+		if *updateGolden {
+			suite.NoError(ioutil.WriteFile(golden, []byte(actual), 0644))
+			continue
+		}
+		expected, err := ioutil.ReadFile(golden)
+		if suite.NoError(err, tc.name) {
+			suite.Equal(string(expected), actual, tc.name)
+		}
+	}
+
+	for _, event := range NotificationEvents() {
+		suite.True(covered[event], "no golden file for %s", event)
+		suite.False(ValidateTemplate(defaultTemplates[event]).HasAny(), event)
+	}
+}
+
+func (suite *NotificationSuite) TestValidateTemplate() {
+	template := defaultTemplates[EventMoveCanceled]
+	template.Subject = "{{.Locator"
+	template.TextBody = "{{.ShipmentID}}"
+	verrs := ValidateTemplate(template)
+	suite.Len(verrs.Get("subject"), 1)
+	suite.Empty(verrs.Get("html_body"))
+	suite.Len(verrs.Get("text_body"), 1)
+
+	template.Event = "move_misplaced"
+	suite.Len(ValidateTemplate(template).Get("event"), 1)
+}
+
+func (suite *NotificationSuite) TestStoredTemplateVersionIsSent() {
+	approver := testdatagen.MakeDefaultOfficeUser(suite.db)
+	move := testdatagen.MakeDefaultMove(suite.db)
+	session := &auth.Session{
+		UserID:          *approver.UserID,
+		OfficeUserID:    approver.ID,
+		ApplicationName: auth.OfficeApp,
+	}
+
+	stored := models.NotificationTemplate{
+		Event:    EventMoveApproved,
+		Subject:  "Move {{.Locator}} approved",
+		HTMLBody: "<p>{{.Locator}}</p>",
+		TextBody: "{{.Locator}}",
+	}
+	verrs, err := models.CreateNotificationTemplateVersion(suite.db, &stored)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+
+	current, err := CurrentTemplate(suite.db, EventMoveApproved)
+	suite.NoError(err)
+	suite.Equal(stored.ID, current.ID)
+
+	emails, err := NewMoveApproved(suite.db, suite.logger, session, move.ID).emails()
+	suite.NoError(err)
+	suite.Len(emails, 1)
+	suite.Equal("Move "+move.Locator+" approved", emails[0].subject)
+	suite.Equal("<p>"+move.Locator+"</p>", emails[0].htmlBody)
+
+	// Previews render drafts without storing them
+	draft := stored
+	draft.TextBody = "Draft for {{.Locator}}"
+	preview, verrs, err := PreviewEmail(suite.db, suite.logger, session, draft, move.ID)
+	suite.NoError(err)
+	suite.False(verrs.HasAny())
+	suite.Equal("Draft for "+move.Locator, preview.TextBody)
+}
@@ -0,0 +1,185 @@
+package notifications
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// UnsubscribePath is where the links in notifications to stop receiving them point
+const UnsubscribePath = "/notifications/unsubscribe"
+
+// ErrInvalidUnsubscribeToken is returned for unsubscribe tokens that weren't signed by us
+var ErrInvalidUnsubscribeToken = errors.New("invalid unsubscribe token")
+
+// UnsubscribeSigner signs the links that let recipients stop receiving an event's notifications
+// without logging in
+type UnsubscribeSigner struct {
+	secret   []byte
+	hostname string
+}
+
+// LoadUnsubscribeSecret returns the secret that signs unsubscribe links, read from secretFile when one is
+// given. The secret is only used for unsubscribe links, so it can be rotated without signing anyone out.
+func LoadUnsubscribeSecret(secret string, secretFile string) (string, error) {
+	if secretFile != "" {
+		contents, err := ioutil.ReadFile(secretFile) // #nosec
+		if err != nil {
+			return "", errors.Wrap(err, "reading unsubscribe secret key file")
+		}
+		secret = strings.TrimSpace(string(contents))
+	}
+	if secret == "" {
+		return "", errors.New("missing unsubscribe secret key")
+	}
+	return secret, nil
+}
+
+// NewUnsubscribeSigner returns a signer for unsubscribe links on the given host
+func NewUnsubscribeSigner(secret string, hostname string) UnsubscribeSigner {
+	return UnsubscribeSigner{
+		secret:   []byte(secret),
+		hostname: hostname,
+	}
+}
+
+func (s UnsubscribeSigner) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Token returns the token that unsubscribes a user from an event's notifications
+func (s UnsubscribeSigner) Token(userID uuid.UUID, event string) string {
+	payload := userID.String() + ":" + event
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + s.signature(payload)
+}
+
+// URL returns the link that unsubscribes a user from an event's notifications
+func (s UnsubscribeSigner) URL(userID uuid.UUID, event string) string {
+	unsubscribeURL := url.URL{
+		Scheme:   "https",
+		Host:     s.hostname,
+		Path:     UnsubscribePath,
+		RawQuery: url.Values{"token": {s.Token(userID, event)}}.Encode(),
+	}
+	return unsubscribeURL.String()
+}
+
+// Verify returns the user and event of a token signed by the signer
+func (s UnsubscribeSigner) Verify(token string) (uuid.UUID, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return uuid.Nil, "", ErrInvalidUnsubscribeToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidUnsubscribeToken
+	}
+	if !hmac.Equal([]byte(s.signature(string(payload))), []byte(parts[1])) {
+		return uuid.Nil, "", ErrInvalidUnsubscribeToken
+	}
+
+	fields := strings.SplitN(string(payload), ":", 2)
+	if len(fields) != 2 {
+		return uuid.Nil, "", ErrInvalidUnsubscribeToken
+	}
+	userID, err := uuid.FromString(fields[0])
+	if err != nil {
+		return uuid.Nil, "", ErrInvalidUnsubscribeToken
+	}
+	return userID, fields[1], nil
+}
+
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><title>move.mil notifications</title></head>
+<body>
+{{if .Unsubscribed}}<p>You will no longer receive {{.Event}} emails from move.mil.</p>
+{{else}}<form method="post">
+<p>Stop receiving {{.Event}} emails from move.mil?</p>
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">Unsubscribe</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+// UnsubscribeHandler turns off a user's email notifications for the event of a signed unsubscribe link.
+// GET asks for confirmation so that link scanners don't unsubscribe recipients.
+type UnsubscribeHandler struct {
+	db     *pop.Connection
+	logger *zap.Logger
+	signer UnsubscribeSigner
+}
+
+// NewUnsubscribeHandler returns a handler for unsubscribe links
+func NewUnsubscribeHandler(db *pop.Connection, logger *zap.Logger, signer UnsubscribeSigner) UnsubscribeHandler {
+	return UnsubscribeHandler{
+		db:     db,
+		logger: logger,
+		signer: signer,
+	}
+}
+
+func (h UnsubscribeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.FormValue("token")
+	userID, event, err := h.signer.Verify(token)
+	if err != nil {
+		h.logger.Info("Invalid unsubscribe token", zap.Error(err))
+		http.Error(w, "This unsubscribe link is invalid.", http.StatusBadRequest)
+		return
+	}
+	eventDescription := strings.Replace(event, "_", " ", -1)
+
+	if r.Method == http.MethodPost {
+		_, verrs, err := models.SetNotificationPreference(h.db, userID, event, models.NotificationChannelEmail, false)
+		if err != nil || verrs.HasAny() {
+			h.logger.Error("Failed to unsubscribe user",
+				zap.String("user_id", userID.String()),
+				zap.String("event", event),
+				zap.String("validation_errors", verrs.String()),
+				zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		h.logger.Info("User unsubscribed from notifications",
+			zap.String("user_id", userID.String()),
+			zap.String("event", event))
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = unsubscribePage.Execute(w, struct {
+		Event        string
+		Token        string
+		Unsubscribed bool
+	}{eventDescription, token, r.Method == http.MethodPost})
+	if err != nil {
+		h.logger.Error("Failed to render unsubscribe page", zap.Error(err))
+	}
+}
+
+// unsubscribeFooters returns the text and HTML footers that link to the unsubscribe URL
+func unsubscribeFooters(unsubscribeURL string) (string, string) {
+	text := fmt.Sprintf("\n\nTo stop receiving these emails, visit %s", unsubscribeURL)
+	html := fmt.Sprintf(`<br/><br/>To stop receiving these emails, <a href="%s">unsubscribe</a>.`, template.HTMLEscapeString(unsubscribeURL))
+	return text, html
+}
@@ -0,0 +1,159 @@
+package paperwork
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// PremoveSurveyReport draws the printable record of a pre-move survey: the dates and weights the
+// TSP planned and the inventory it took room by room.
+type PremoveSurveyReport struct {
+	pdf      *gofpdf.Fpdf
+	shipment *models.Shipment
+	items    models.PremoveSurveyItems
+}
+
+// NewPremoveSurveyReport creates and returns a new PremoveSurveyReport. The shipment's service member
+// and pickup address are drawn if they are loaded.
+func NewPremoveSurveyReport(shipment *models.Shipment, items models.PremoveSurveyItems) *PremoveSurveyReport {
+	pdf := gofpdf.New(PdfOrientation, PdfUnit, PdfPageSize, PdfFontDir)
+	pdf.SetMargins(horizontalMargin, topMargin, horizontalMargin)
+
+	return &PremoveSurveyReport{
+		pdf:      pdf,
+		shipment: shipment,
+		items:    items,
+	}
+}
+
+// Widths of the inventory table's columns, as fractions of the body
+var premoveSurveyColumnWidths = []float64{0.25, 0.39, 0.08, 0.14, 0.14}
+
+// DrawForm writes the report PDF to the provided Writer.
+func (r *PremoveSurveyReport) DrawForm(output io.Writer) error {
+	shipment := r.shipment
+
+	r.pdf.SetHeaderFunc(func() {
+		r.pdf.SetFont(fontFace, "B", 17)
+		r.pdf.Cell(bodyWidth*0.75, fieldHeight*2, "PRE-MOVE SURVEY REPORT")
+		r.pdf.SetFont(fontFace, "B", 9)
+		r.pdf.Cell(bodyWidth*0.25, fieldHeight, "Date Prepared (YYYY-MM-DD)")
+		r.pdf.SetFont(fontFace, "", 10)
+		r.pdf.SetXY(horizontalMargin+bodyWidth*0.75, r.pdf.GetY()+5)
+		r.pdf.Cell(bodyWidth*0.25, fieldHeight, time.Now().Format("2006-01-02"))
+		r.pdf.SetLineWidth(1.0)
+		r.pdf.Line(0, 20, PdfPageWidth, 20)
+		r.pdf.Ln(-1)
+	})
+
+	r.pdf.AddPage()
+	r.addSectionHeader("SHIPMENT")
+	var origin string
+	if shipment.PickupAddress != nil {
+		origin = shipment.PickupAddress.LineFormat()
+	}
+	r.addFormRow([]formField{
+		{label: "Service Member", value: shipment.ServiceMember.ReverseNameLineFormat()},
+		{label: "GBL Number", value: coalesce(shipment.GBLNumber, "")},
+		{label: "Survey Date", value: formatReportDate(shipment.PmSurveyConductedDate)},
+		{label: "Survey Method", value: shipment.PmSurveyMethod},
+	})
+	r.addFormRow([]formField{
+		{label: "Pickup Address", value: origin},
+	})
+
+	r.addSectionHeader("PLANNED DATES")
+	r.addFormRow([]formField{
+		{label: "Pack", value: formatReportDate(shipment.PmSurveyPlannedPackDate)},
+		{label: "Pickup", value: formatReportDate(shipment.PmSurveyPlannedPickupDate)},
+		{label: "Delivery", value: formatReportDate(shipment.PmSurveyPlannedDeliveryDate)},
+	})
+
+	r.addSectionHeader("WEIGHT ESTIMATES")
+	r.addFormRow([]formField{
+		{label: "Household Goods", value: formatReportWeight(shipment.PmSurveyWeightEstimate)},
+		{label: "Pro-Gear", value: formatReportWeight(shipment.PmSurveyProgearWeightEstimate)},
+		{label: "Spouse Pro-Gear", value: formatReportWeight(shipment.PmSurveySpouseProgearWeightEstimate)},
+	})
+
+	r.addSectionHeader("INVENTORY")
+	r.addInventoryRow([]string{"Room", "Item", "Qty", "Cube (cu ft)", "Weight"}, "B")
+	for _, item := range r.items {
+		r.addInventoryRow([]string{
+			item.Room,
+			item.Description,
+			fmt.Sprintf("%d", item.Quantity),
+			fmt.Sprintf("%.1f", item.CubeFeet*float64(item.Quantity)),
+			formatPounds(item.WeightEstimate.Int() * item.Quantity),
+		}, "")
+	}
+	r.addInventoryRow([]string{
+		"Total",
+		"",
+		"",
+		fmt.Sprintf("%.1f", r.items.TotalCubeFeet()),
+		formatPounds(r.items.TotalWeightEstimate().Int()),
+	}, "B")
+
+	r.addSectionHeader("NOTES")
+	r.pdf.SetFont(fontFace, "", 10)
+	r.pdf.MultiCell(bodyWidth, fieldHeight, coalesce(shipment.PmSurveyNotes, ""), "", "L", false)
+
+	return r.pdf.Output(output)
+}
+
+func formatReportDate(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
+func formatReportWeight(weight *unit.Pound) string {
+	if weight == nil {
+		return ""
+	}
+	return formatPounds(weight.Int())
+}
+
+func (r *PremoveSurveyReport) addSectionHeader(title string) {
+	r.pdf.Ln(2)
+	r.pdf.SetFont(fontFace, "B", 10)
+	r.pdf.SetFillColor(221, 231, 240)
+	r.pdf.CellFormat(0, 7, title, "", 1, "L", true, 0, "")
+	r.pdf.Ln(1)
+}
+
+func (r *PremoveSurveyReport) addFormRow(fields []formField) {
+	fieldWidth := bodyWidth / float64(len(fields))
+
+	r.pdf.SetFont(fontFace, "B", 9)
+	for _, field := range fields {
+		r.pdf.Cell(fieldWidth, fieldHeight, field.label)
+	}
+	r.pdf.Ln(-1)
+
+	r.pdf.SetFont(fontFace, "", 10)
+	for _, field := range fields {
+		r.pdf.Cell(fieldWidth, fieldHeight, field.value)
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *PremoveSurveyReport) addInventoryRow(values []string, style string) {
+	r.pdf.SetFont(fontFace, style, 9)
+	for i, value := range values {
+		align := "L"
+		if i >= 2 {
+			align = "R"
+		}
+		r.pdf.CellFormat(bodyWidth*premoveSurveyColumnWidths[i], fieldHeight, value, "B", 0, align, false, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
@@ -0,0 +1,25 @@
+package paperwork
+
+import (
+	"bytes"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// Tests if we can draw a survey report without blowing up
+func (suite *PaperworkSuite) TestPremoveSurveyReportSmokeTest() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	notes := "Piano in the basement needs a crate"
+	shipment.PmSurveyNotes = &notes
+	items := models.PremoveSurveyItems{
+		{Room: "Living room", Description: "Sofa", Quantity: 1, CubeFeet: 35, WeightEstimate: unit.Pound(245)},
+		{Room: "Basement", Description: "Upright piano", Quantity: 1, CubeFeet: 70, WeightEstimate: unit.Pound(490)},
+	}
+
+	var output bytes.Buffer
+	err := NewPremoveSurveyReport(&shipment, items).DrawForm(&output)
+	suite.FatalNil(err)
+	suite.True(bytes.HasPrefix(output.Bytes(), []byte("%PDF")))
+}
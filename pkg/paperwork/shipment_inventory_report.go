@@ -0,0 +1,160 @@
+package paperwork
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// ShipmentInventoryReport draws the printable descriptive inventory of a shipment: each tagged item
+// with its condition at pickup and the exceptions noted at delivery, and who signed it.
+type ShipmentInventoryReport struct {
+	pdf       *gofpdf.Fpdf
+	shipment  *models.Shipment
+	inventory *models.ShipmentInventory
+}
+
+// NewShipmentInventoryReport creates and returns a new ShipmentInventoryReport. The shipment's service
+// member and pickup address are drawn if they are loaded.
+func NewShipmentInventoryReport(shipment *models.Shipment, inventory *models.ShipmentInventory) *ShipmentInventoryReport {
+	pdf := gofpdf.New(PdfOrientation, PdfUnit, PdfPageSize, PdfFontDir)
+	pdf.SetMargins(horizontalMargin, topMargin, horizontalMargin)
+
+	return &ShipmentInventoryReport{
+		pdf:       pdf,
+		shipment:  shipment,
+		inventory: inventory,
+	}
+}
+
+// Widths of the item table's columns, as fractions of the body
+var inventoryColumnWidths = []float64{0.08, 0.32, 0.18, 0.18, 0.24}
+
+// DrawForm writes the inventory PDF to the provided Writer.
+func (r *ShipmentInventoryReport) DrawForm(output io.Writer) error {
+	shipment := r.shipment
+	inventory := r.inventory
+
+	r.pdf.SetHeaderFunc(func() {
+		r.pdf.SetFont(fontFace, "B", 17)
+		r.pdf.Cell(bodyWidth*0.75, fieldHeight*2, "HOUSEHOLD GOODS DESCRIPTIVE INVENTORY")
+		r.pdf.SetFont(fontFace, "B", 9)
+		r.pdf.Cell(bodyWidth*0.25, fieldHeight, "Date Prepared (YYYY-MM-DD)")
+		r.pdf.SetFont(fontFace, "", 10)
+		r.pdf.SetXY(horizontalMargin+bodyWidth*0.75, r.pdf.GetY()+5)
+		r.pdf.Cell(bodyWidth*0.25, fieldHeight, time.Now().Format("2006-01-02"))
+		r.pdf.SetLineWidth(1.0)
+		r.pdf.Line(0, 20, PdfPageWidth, 20)
+		r.pdf.Ln(-1)
+	})
+
+	r.pdf.AddPage()
+	r.addSectionHeader("SHIPMENT")
+	var origin string
+	if shipment.PickupAddress != nil {
+		origin = shipment.PickupAddress.LineFormat()
+	}
+	r.addFormRow([]formField{
+		{label: "Service Member", value: shipment.ServiceMember.ReverseNameLineFormat()},
+		{label: "GBL Number", value: coalesce(shipment.GBLNumber, "")},
+		{label: "Lot Number", value: inventory.LotNumber},
+		{label: "Items", value: fmt.Sprintf("%d", len(inventory.Items))},
+	})
+	r.addFormRow([]formField{
+		{label: "Pickup Address", value: origin},
+	})
+
+	r.addSectionHeader("ITEMS")
+	r.addItemRow([]string{"Tag", "Item", "At Pickup", "At Delivery", "Delivery Notes"}, "B")
+	for _, item := range inventory.Items {
+		deliveryCondition := strings.Join(models.SplitConditionCodes(coalesce(item.DeliveryConditionCodes, "")), " ")
+		if item.MissingAtDelivery {
+			deliveryCondition = "MISSING"
+		}
+		r.addItemRow([]string{
+			fmt.Sprintf("%d", item.TagNumber),
+			item.Description,
+			strings.Join(models.SplitConditionCodes(item.PickupConditionCodes), " "),
+			deliveryCondition,
+			coalesce(item.DeliveryExceptionNotes, ""),
+		}, "")
+	}
+
+	r.addSectionHeader("SIGNATURES")
+	for _, stage := range []models.InventoryStage{models.InventoryStagePICKUP, models.InventoryStageDELIVERY} {
+		var fields []formField
+		for _, role := range []models.InventorySignerRole{models.InventorySignerRoleSERVICEMEMBER, models.InventorySignerRoleTSP} {
+			field := formField{label: inventorySignatureLabel(stage, role)}
+			if signature := inventory.Signature(stage, role); signature != nil {
+				field.value = fmt.Sprintf("%s, %s", signature.SignerName, signature.SignedAt.Format("2006-01-02 15:04"))
+			}
+			fields = append(fields, field)
+		}
+		r.addFormRow(fields)
+	}
+
+	r.addSectionHeader("CONDITION CODES")
+	codes := make([]string, 0, len(models.InventoryConditionCodes))
+	for code := range models.InventoryConditionCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	legend := make([]string, len(codes))
+	for i, code := range codes {
+		legend[i] = fmt.Sprintf("%s - %s", code, models.InventoryConditionCodes[code])
+	}
+	r.pdf.SetFont(fontFace, "", 9)
+	r.pdf.MultiCell(bodyWidth, fieldHeight, strings.Join(legend, ";  "), "", "L", false)
+
+	return r.pdf.Output(output)
+}
+
+func inventorySignatureLabel(stage models.InventoryStage, role models.InventorySignerRole) string {
+	stageLabel := "Pickup"
+	if stage == models.InventoryStageDELIVERY {
+		stageLabel = "Delivery"
+	}
+	roleLabel := "Service Member or Agent"
+	if role == models.InventorySignerRoleTSP {
+		roleLabel = "Crew Chief or Driver"
+	}
+	return fmt.Sprintf("%s - %s", stageLabel, roleLabel)
+}
+
+func (r *ShipmentInventoryReport) addSectionHeader(title string) {
+	r.pdf.Ln(2)
+	r.pdf.SetFont(fontFace, "B", 10)
+	r.pdf.SetFillColor(221, 231, 240)
+	r.pdf.CellFormat(0, 7, title, "", 1, "L", true, 0, "")
+	r.pdf.Ln(1)
+}
+
+func (r *ShipmentInventoryReport) addFormRow(fields []formField) {
+	fieldWidth := bodyWidth / float64(len(fields))
+
+	r.pdf.SetFont(fontFace, "B", 9)
+	for _, field := range fields {
+		r.pdf.Cell(fieldWidth, fieldHeight, field.label)
+	}
+	r.pdf.Ln(-1)
+
+	r.pdf.SetFont(fontFace, "", 10)
+	for _, field := range fields {
+		r.pdf.Cell(fieldWidth, fieldHeight, field.value)
+	}
+	r.pdf.Ln(-1)
+}
+
+func (r *ShipmentInventoryReport) addItemRow(values []string, style string) {
+	r.pdf.SetFont(fontFace, style, 9)
+	for i, value := range values {
+		r.pdf.CellFormat(bodyWidth*inventoryColumnWidths[i], fieldHeight, value, "B", 0, "L", false, 0, "")
+	}
+	r.pdf.Ln(-1)
+}
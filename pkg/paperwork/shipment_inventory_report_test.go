@@ -0,0 +1,31 @@
+package paperwork
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+// Tests if we can draw an inventory with exceptions and signatures without blowing up
+func (suite *PaperworkSuite) TestShipmentInventoryReportSmokeTest() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	notes := "Top drawer front split"
+	codes := "BR,SC"
+	inventory := models.ShipmentInventory{
+		LotNumber: "A4417",
+		Items: models.ShipmentInventoryItems{
+			{TagNumber: 1, Description: "Dresser, 6 drawer", PickupConditionCodes: "SC,M", DeliveryConditionCodes: &codes, DeliveryExceptionNotes: &notes},
+			{TagNumber: 2, Description: "Carton, books", PickupConditionCodes: "PBO", MissingAtDelivery: true},
+		},
+		Signatures: models.ShipmentInventorySignatures{
+			{Stage: models.InventoryStagePICKUP, SignerRole: models.InventorySignerRoleSERVICEMEMBER, SignerName: "Jane Smith", Signature: "Jane Smith", SignedAt: time.Now()},
+		},
+	}
+
+	var output bytes.Buffer
+	err := NewShipmentInventoryReport(&shipment, &inventory).DrawForm(&output)
+	suite.FatalNil(err)
+	suite.True(bytes.HasPrefix(output.Bytes(), []byte("%PDF")))
+}
@@ -0,0 +1,105 @@
+package rdd
+
+import (
+	"github.com/gobuffalo/pop"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/dates"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+)
+
+// Calculator works out the required delivery dates of shipments
+type Calculator struct {
+	db        *pop.Connection
+	logger    *zap.Logger
+	planner   route.Planner
+	calendars *dates.CalendarCache
+}
+
+// NewCalculator creates a new Calculator
+func NewCalculator(db *pop.Connection, logger *zap.Logger, planner route.Planner, calendars *dates.CalendarCache) *Calculator {
+	return &Calculator{
+		db:        db,
+		logger:    logger,
+		planner:   planner,
+		calendars: calendars,
+	}
+}
+
+// Calculate returns the RDD of a shipment. The miles run from the pickup address through the secondary
+// pickup address, if any, to the delivery address or, without one, the new duty station. Workdays are
+// those of the new duty station.
+func (c *Calculator) Calculate(shipment *models.Shipment) (models.RequiredDeliveryDate, error) {
+	var rdd models.RequiredDeliveryDate
+
+	if c.planner == nil {
+		return rdd, errors.New("No planner to measure the miles of the shipment")
+	}
+	if shipment.Move.Orders.NewDutyStation.Address.PostalCode == "" {
+		var move models.Move
+		if err := c.db.Eager("Orders.NewDutyStation.Address").Find(&move, shipment.MoveID); err != nil {
+			return rdd, errors.Wrapf(err, "Could not fetch new duty station for move ID %s", shipment.MoveID)
+		}
+		shipment.Move = move
+	}
+	newDutyStation := shipment.Move.Orders.NewDutyStation
+
+	if shipment.PickupAddress == nil {
+		return rdd, errors.New("Shipment must have a pickup address")
+	}
+	stops := []*models.Address{shipment.PickupAddress}
+	if shipment.HasSecondaryPickupAddress && shipment.SecondaryPickupAddress != nil {
+		stops = append(stops, shipment.SecondaryPickupAddress)
+	}
+	if shipment.HasDeliveryAddress && shipment.DeliveryAddress != nil {
+		stops = append(stops, shipment.DeliveryAddress)
+	} else {
+		stops = append(stops, &newDutyStation.Address)
+	}
+
+	miles := 0
+	for i := 1; i < len(stops); i++ {
+		distance, err := c.planner.TransitDistance(stops[i-1], stops[i])
+		if err != nil {
+			return rdd, err
+		}
+		miles += distance
+	}
+
+	calendar, err := c.calendars.Calendar(models.DutyStationCalendarScope(newDutyStation))
+	if err != nil {
+		return rdd, err
+	}
+
+	return models.CalculateRequiredDeliveryDate(shipment, miles, calendar)
+}
+
+// Update recalculates the RDD of a shipment and records it on the shipment, without saving it
+func (c *Calculator) Update(shipment *models.Shipment) error {
+	rdd, err := c.Calculate(shipment)
+	if err != nil {
+		return err
+	}
+	shipment.SetRequiredDeliveryDate(rdd)
+	c.logger.Info("Calculated required delivery date",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.Time("required_delivery_date", rdd.Date),
+		zap.Int("transit_days", rdd.TransitDays))
+	return nil
+}
+
+// UpdateIfChanged recalculates the RDD of a submitted shipment if it hasn't got one yet or if anything
+// it is derived from changed since before. Draft shipments don't have an RDD, and the RDD of a delivered
+// or completed shipment is frozen, since delivery was measured against it.
+func (c *Calculator) UpdateIfChanged(before models.Shipment, shipment *models.Shipment) error {
+	switch shipment.Status {
+	case models.ShipmentStatusDRAFT, models.ShipmentStatusDELIVERED, models.ShipmentStatusCOMPLETED:
+		return nil
+	}
+	if shipment.RequiredDeliveryDate != nil && !models.RequiredDeliveryDateInputsChanged(before, *shipment) {
+		return nil
+	}
+	return c.Update(shipment)
+}
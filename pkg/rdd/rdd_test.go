@@ -0,0 +1,110 @@
+package rdd
+
+import (
+	"log"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/route"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *RDDSuite) TestCalculateAndUpdate() {
+	requestedPickupDate := time.Date(2019, time.June, 3, 0, 0, 0, 0, time.UTC)
+	weightEstimate := unit.Pound(4500)
+	progearWeightEstimate := unit.Pound(500)
+	shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status:                models.ShipmentStatusSUBMITTED,
+			RequestedPickupDate:   &requestedPickupDate,
+			WeightEstimate:        &weightEstimate,
+			ProgearWeightEstimate: &progearWeightEstimate,
+		},
+	})
+	calculator := NewCalculator(suite.db, suite.logger, suite.planner, models.NewCalendarCache(suite.db))
+
+	// 5312 lbs over 550 miles takes 8 days
+	rdd, err := calculator.Calculate(&shipment)
+	suite.NoError(err)
+	suite.Equal(550, rdd.Miles)
+	suite.Equal(8, rdd.TransitDays)
+	suite.Equal(time.Date(2019, time.June, 11, 0, 0, 0, 0, time.UTC), rdd.Date)
+
+	suite.NoError(calculator.UpdateIfChanged(shipment, &shipment))
+	suite.Equal(rdd.Date, *shipment.RequiredDeliveryDate)
+
+	// A secondary pickup adds a leg to the miles and a day for the stop
+	before := shipment
+	secondaryPickupAddress := testdatagen.MakeAddress2(suite.db, testdatagen.Assertions{})
+	shipment.HasSecondaryPickupAddress = true
+	shipment.SecondaryPickupAddressID = &secondaryPickupAddress.ID
+	shipment.SecondaryPickupAddress = &secondaryPickupAddress
+
+	suite.NoError(calculator.UpdateIfChanged(before, &shipment))
+	suite.Equal(time.Date(2019, time.June, 14, 0, 0, 0, 0, time.UTC), *shipment.RequiredDeliveryDate)
+	suite.Len(shipment.RequiredDeliveryDateBasisLines(), 4)
+}
+
+func (suite *RDDSuite) TestUpdateIfChangedSkipsDrafts() {
+	shipment := testdatagen.MakeDefaultShipment(suite.db)
+	calculator := NewCalculator(suite.db, suite.logger, suite.planner, models.NewCalendarCache(suite.db))
+
+	suite.NoError(calculator.UpdateIfChanged(shipment, &shipment))
+	suite.Nil(shipment.RequiredDeliveryDate)
+}
+
+func (suite *RDDSuite) TestUpdateIfChangedFreezesDeliveredShipments() {
+	requiredDeliveryDate := time.Date(2019, time.June, 11, 0, 0, 0, 0, time.UTC)
+	for _, status := range []models.ShipmentStatus{models.ShipmentStatusDELIVERED, models.ShipmentStatusCOMPLETED} {
+		shipment := testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+			Shipment: models.Shipment{
+				Status:               status,
+				RequiredDeliveryDate: &requiredDeliveryDate,
+			},
+		})
+		calculator := NewCalculator(suite.db, suite.logger, suite.planner, models.NewCalendarCache(suite.db))
+
+		// Delivery was measured against the RDD, so later edits to its inputs don't move it
+		before := shipment
+		secondaryPickupAddress := testdatagen.MakeAddress2(suite.db, testdatagen.Assertions{})
+		shipment.HasSecondaryPickupAddress = true
+		shipment.SecondaryPickupAddressID = &secondaryPickupAddress.ID
+		shipment.SecondaryPickupAddress = &secondaryPickupAddress
+
+		suite.NoError(calculator.UpdateIfChanged(before, &shipment))
+		suite.Equal(requiredDeliveryDate, *shipment.RequiredDeliveryDate, "%s shipment", status)
+	}
+}
+
+type RDDSuite struct {
+	suite.Suite
+	db      *pop.Connection
+	logger  *zap.Logger
+	planner route.Planner
+}
+
+func (suite *RDDSuite) SetupTest() {
+	suite.db.TruncateAll()
+}
+
+func TestRDDSuite(t *testing.T) {
+	configLocation := "../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	// Use a no-op logger during testing
+	logger, _ := zap.NewDevelopment()
+	planner := route.NewTestingPlanner(550)
+
+	hs := &RDDSuite{db: db, logger: logger, planner: planner}
+	suite.Run(t, hs)
+}
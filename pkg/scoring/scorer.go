@@ -0,0 +1,312 @@
+// Package scoring computes TSP Best Value Scores from how TSPs performed on the shipments they moved
+// in MyMove, and writes them into the performance records that the award queue assigns quality
+// bands to.
+package scoring
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+const scorerLockID = 3
+
+// The BVS weighs the performance score against the rate score. The performance score in turn weighs
+// on-time pickups and deliveries, accepted offers, shipments delivered without a claim and how service
+// members rated the TSP in satisfaction surveys.
+const (
+	performanceWeight    = 0.7
+	rateWeight           = 0.3
+	onTimePickupWeight   = 0.2
+	onTimeDeliveryWeight = 0.3
+	acceptanceWeight     = 0.15
+	claimFreeWeight      = 0.15
+	satisfactionWeight   = 0.2
+)
+
+// Scorer scores TSPs for a performance period and writes their performance records for the next one
+type Scorer struct {
+	db     *pop.Connection
+	logger *hnyzap.Logger
+}
+
+// NewScorer creates a new Scorer
+func NewScorer(db *pop.Connection, logger *hnyzap.Logger) *Scorer {
+	return &Scorer{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Run scores every TSP with a performance record in the performance period that contains date. It
+// records their metrics and writes a performance record with the new BVS and no quality band for the
+// following period, unless one already exists. It returns how many performance records were written.
+func (s *Scorer) Run(ctx context.Context, date time.Time) (int, error) {
+	ctx, span := beeline.StartSpan(ctx, "tsp_scorer")
+	defer span.Send()
+
+	start, end := models.GetPerformancePeriod(date)
+	nextStart, nextEnd := models.GetPerformancePeriod(end.AddDate(0, 0, 1))
+	span.AddField("performance_period_start", start.String())
+
+	written := 0
+	err := s.db.Transaction(func(tx *pop.Connection) error {
+		s.logger.Info("Waiting to acquire advisory lock...")
+		if err := tx.RawQuery("SELECT pg_advisory_xact_lock($1)", scorerLockID).Exec(); err != nil {
+			return err
+		}
+
+		perfs, err := models.FetchTSPPerformancesForPerformancePeriod(tx, start)
+		if err != nil {
+			return err
+		}
+
+		for _, tdlPerfs := range groupByTDL(perfs) {
+			metrics, err := s.scoreTDL(tx, tdlPerfs, start, end)
+			if err != nil {
+				return err
+			}
+			for i, metric := range metrics {
+				didWrite, err := s.writeNextPerformance(tx, tdlPerfs[i], metric, nextStart, nextEnd)
+				if err != nil {
+					return err
+				}
+				if didWrite {
+					written++
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.TraceInfo(ctx, "Scored TSPs",
+		zap.String("performance_period_start", start.String()),
+		zap.Int("performance_records_written", written))
+	return written, nil
+}
+
+// groupByTDL splits TSPPs ordered by TDL into one slice per TDL, with a single TSPP per TSP
+func groupByTDL(perfs models.TransportationServiceProviderPerformances) []models.TransportationServiceProviderPerformances {
+	var groups []models.TransportationServiceProviderPerformances
+	seen := make(map[uuid.UUID]bool)
+	for i, perf := range perfs {
+		if i == 0 || perf.TrafficDistributionListID != perfs[i-1].TrafficDistributionListID {
+			groups = append(groups, models.TransportationServiceProviderPerformances{})
+			seen = make(map[uuid.UUID]bool)
+		}
+		if seen[perf.TransportationServiceProviderID] {
+			continue
+		}
+		seen[perf.TransportationServiceProviderID] = true
+		groups[len(groups)-1] = append(groups[len(groups)-1], perf)
+	}
+	return groups
+}
+
+// scoreTDL measures and scores the TSPs of a TDL, replacing any metrics recorded for the period
+// before. TSPs that weren't offered or moved any shipments get the average performance score of
+// those that were. When none were, the TDL isn't scored and nil is returned.
+func (s *Scorer) scoreTDL(tx *pop.Connection, perfs models.TransportationServiceProviderPerformances, start time.Time, end time.Time) (models.TSPPerformanceMetrics, error) {
+	metrics := make(models.TSPPerformanceMetrics, len(perfs))
+	for i, perf := range perfs {
+		metric, err := models.FetchTSPPerformanceMetric(tx, perf.TransportationServiceProviderID, perf.TrafficDistributionListID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		metrics[i] = metric
+	}
+
+	means := channelMeans(metrics)
+	scored := 0
+	totalPerformanceScore := 0.0
+	for i := range metrics {
+		metric := &metrics[i]
+		if metric.HasShipments() {
+			metric.PerformanceScore = performanceScore(*metric, means)
+			totalPerformanceScore += metric.PerformanceScore
+			scored++
+		}
+	}
+	if scored == 0 {
+		return nil, nil
+	}
+	averagePerformanceScore := totalPerformanceScore / float64(scored)
+
+	lowestRate, highestRate := perfs[0].LinehaulRate, perfs[0].LinehaulRate
+	for _, perf := range perfs {
+		if perf.LinehaulRate < lowestRate {
+			lowestRate = perf.LinehaulRate
+		}
+		if perf.LinehaulRate > highestRate {
+			highestRate = perf.LinehaulRate
+		}
+	}
+
+	for i := range metrics {
+		metric := &metrics[i]
+		if !metric.HasShipments() {
+			metric.PerformanceScore = averagePerformanceScore
+		}
+		// A bigger linehaul discount is a better rate
+		metric.RateScore = 100
+		if highestRate > lowestRate {
+			metric.RateScore = 100 * float64(perfs[i].LinehaulRate-lowestRate) / float64(highestRate-lowestRate)
+		}
+		metric.PerformanceScore = round(metric.PerformanceScore)
+		metric.RateScore = round(metric.RateScore)
+		metric.BestValueScore = round(performanceWeight*metric.PerformanceScore + rateWeight*metric.RateScore)
+
+		err := tx.RawQuery(`DELETE FROM tsp_performance_metrics
+			WHERE transportation_service_provider_id = $1
+			AND traffic_distribution_list_id = $2
+			AND performance_period_start = $3`,
+			metric.TransportationServiceProviderID, metric.TrafficDistributionListID, start).Exec()
+		if err != nil {
+			return nil, errors.Wrap(err, "replacing TSP performance metric")
+		}
+		verrs, err := tx.ValidateAndCreate(metric)
+		if err != nil {
+			return nil, err
+		} else if verrs.HasAny() {
+			return nil, errors.Errorf("Validation failure: %s", verrs)
+		}
+	}
+	return metrics, nil
+}
+
+// neutralShare is what a TSP is judged to have done on a measure when no TSP in its TDL had anything to
+// be judged on
+const neutralShare = 0.5
+
+// measureWeights are how much each measure, in the order measures returns them, counts towards the
+// performance score
+var measureWeights = []float64{onTimePickupWeight, onTimeDeliveryWeight, acceptanceWeight, claimFreeWeight, satisfactionWeight}
+
+// measures returns the TSP's share of good outcomes on each measure it's judged on, in the order of
+// measureWeights. Measures the TSP had nothing to be judged on are nil.
+func measures(metric models.TSPPerformanceMetric) []*float64 {
+	share := func(good int, total int) *float64 {
+		if total == 0 {
+			return nil
+		}
+		value := float64(good) / float64(total)
+		return &value
+	}
+	// Ratings go from 1, the worst, to SurveyRatingMax
+	var satisfaction *float64
+	if metric.AverageRating != nil {
+		value := (*metric.AverageRating - 1) / (models.SurveyRatingMax - 1)
+		satisfaction = &value
+	}
+	return []*float64{
+		share(metric.PickedUp-metric.PickedUpLate, metric.PickedUp),
+		share(metric.Delivered-metric.DeliveredLate, metric.Delivered),
+		share(metric.Offers-metric.Rejections, metric.Offers),
+		share(metric.Delivered-metric.Claims, metric.Delivered),
+		satisfaction,
+	}
+}
+
+// channelMeans returns the mean share of good outcomes on each measure across the TSPs of a TDL that had
+// something to be judged on, or neutralShare for measures none of them had
+func channelMeans(metrics models.TSPPerformanceMetrics) []float64 {
+	totals := make([]float64, len(measureWeights))
+	counts := make([]int, len(measureWeights))
+	for _, metric := range metrics {
+		for i, value := range measures(metric) {
+			if value != nil {
+				totals[i] += *value
+				counts[i]++
+			}
+		}
+	}
+	means := make([]float64, len(measureWeights))
+	for i := range means {
+		means[i] = neutralShare
+		if counts[i] > 0 {
+			means[i] = totals[i] / float64(counts[i])
+		}
+	}
+	return means
+}
+
+// performanceScore rates a TSP from 0 to 100 on the shipments it was offered and moved. A measure
+// the TSP had nothing to be judged on counts as the mean of its TDL, so that no shipments or ratings
+// neither helps nor hurts it against the TSPs that had some.
+func performanceScore(metric models.TSPPerformanceMetric, means []float64) float64 {
+	score := 0.0
+	for i, value := range measures(metric) {
+		share := means[i]
+		if value != nil {
+			share = *value
+		}
+		score += measureWeights[i] * share
+	}
+	return 100 * math.Max(score, 0)
+}
+
+// writeNextPerformance creates the TSP's performance record for the next performance period with its
+// new BVS, carrying its discount rates over. It leaves an existing record for that period alone.
+func (s *Scorer) writeNextPerformance(tx *pop.Connection, perf models.TransportationServiceProviderPerformance, metric models.TSPPerformanceMetric, nextStart time.Time, nextEnd time.Time) (bool, error) {
+	count, err := tx.Where("transportation_service_provider_id = ?", perf.TransportationServiceProviderID).
+		Where("traffic_distribution_list_id = ?", perf.TrafficDistributionListID).
+		Where("performance_period_start = ?", nextStart).
+		Count(&models.TransportationServiceProviderPerformance{})
+	if err != nil {
+		return false, err
+	}
+	if count > 0 {
+		s.logger.Info("TSP already has a performance record for the next period",
+			zap.String("transportation_service_provider_id", perf.TransportationServiceProviderID.String()),
+			zap.String("traffic_distribution_list_id", perf.TrafficDistributionListID.String()))
+		return false, nil
+	}
+
+	rateCycleStart, rateCycleEnd := models.GetRateCycle(nextStart.Year(), isPeak(nextStart))
+	if nextStart.Before(rateCycleStart) {
+		// Non-peak rate cycles start the year before
+		rateCycleStart, rateCycleEnd = models.GetRateCycle(nextStart.Year()-1, false)
+	}
+
+	next := models.TransportationServiceProviderPerformance{
+		PerformancePeriodStart:          nextStart,
+		PerformancePeriodEnd:            nextEnd,
+		RateCycleStart:                  rateCycleStart,
+		RateCycleEnd:                    rateCycleEnd,
+		TrafficDistributionListID:       perf.TrafficDistributionListID,
+		TransportationServiceProviderID: perf.TransportationServiceProviderID,
+		BestValueScore:                  metric.BestValueScore,
+		LinehaulRate:                    perf.LinehaulRate,
+		SITRate:                         perf.SITRate,
+	}
+	verrs, err := tx.ValidateAndCreate(&next)
+	if err != nil {
+		return false, err
+	} else if verrs.HasAny() {
+		return false, errors.Errorf("Validation failure: %s", verrs)
+	}
+	return true, nil
+}
+
+// isPeak returns whether the date falls in a peak rate cycle
+func isPeak(date time.Time) bool {
+	start, end := models.GetRateCycle(date.Year(), true)
+	return !date.Before(start) && !date.After(end)
+}
+
+// round rounds a score to the four decimal places a BVS has
+func round(score float64) float64 {
+	return math.Round(score*10000) / 10000
+}
@@ -0,0 +1,194 @@
+package scoring
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+func (suite *ScorerSuite) TestScoresTSPsForNextPerformancePeriod() {
+	start, end := models.GetPerformancePeriod(time.Now())
+	accepted := true
+	rejected := false
+
+	onTimeShipment := suite.makeShipment(start)
+	rejectedShipment := suite.makeShipment(start)
+	tdl := *onTimeShipment.TrafficDistributionList
+
+	reliablePerf := suite.makePerformance(tdl, start, end, 0.30)
+	cheapPerf := suite.makePerformance(tdl, start, end, 0.50)
+	suite.makeOffer(onTimeShipment, reliablePerf, &accepted)
+	suite.makeOffer(rejectedShipment, cheapPerf, &rejected)
+	suite.answerSurvey(onTimeShipment, 3)
+
+	scorer := NewScorer(suite.db, suite.logger)
+	written, err := scorer.Run(context.Background(), start)
+	suite.Nil(err)
+	suite.Equal(2, written)
+
+	// The cheaper TSP's rate outweighs the offer it rejected and the other TSP's middling rating
+	metrics, err := models.FetchTSPPerformanceMetrics(suite.db, tdl.ID, start)
+	suite.Nil(err)
+	suite.Len(metrics, 2)
+	suite.Equal(cheapPerf.TransportationServiceProviderID, metrics[0].TransportationServiceProviderID)
+	suite.Equal(1, metrics[0].Rejections)
+	suite.Equal(75.0, metrics[0].PerformanceScore)
+	suite.Equal(100.0, metrics[0].RateScore)
+	suite.Equal(82.5, metrics[0].BestValueScore)
+	suite.Equal(reliablePerf.TransportationServiceProviderID, metrics[1].TransportationServiceProviderID)
+	suite.Equal(1, metrics[1].Delivered)
+	suite.Equal(1, metrics[1].Surveys)
+	suite.Equal(90.0, metrics[1].PerformanceScore)
+	suite.Equal(63.0, metrics[1].BestValueScore)
+
+	// The next period's records are left for the award queue to band
+	nextStart, _ := models.GetPerformancePeriod(end.AddDate(0, 0, 1))
+	next, err := models.FetchTSPPerformancesForPerformancePeriod(suite.db, nextStart)
+	suite.Nil(err)
+	suite.Len(next, 2)
+	for _, perf := range next {
+		suite.Nil(perf.QualityBand)
+		suite.Equal(tdl.ID, perf.TrafficDistributionListID)
+	}
+
+	// Scoring again replaces the metrics but doesn't write the next period twice
+	written, err = scorer.Run(context.Background(), start)
+	suite.Nil(err)
+	suite.Equal(0, written)
+	metrics, err = models.FetchTSPPerformanceMetrics(suite.db, tdl.ID, start)
+	suite.Nil(err)
+	suite.Len(metrics, 2)
+}
+
+func (suite *ScorerSuite) TestPerformanceScoreFallsBackToChannelMeans() {
+	rating := 3.0
+	rated := models.TSPPerformanceMetric{Offers: 2, PickedUp: 2, PickedUpLate: 1, Delivered: 2, AverageRating: &rating}
+	unrated := models.TSPPerformanceMetric{Offers: 1}
+	means := channelMeans(models.TSPPerformanceMetrics{rated, unrated})
+
+	// Without pickups, deliveries or ratings the TSP scores what the TDL's TSPs did on average, rather
+	// than perfectly
+	suite.Equal(performanceScore(rated, means), performanceScore(unrated, means))
+
+	// Measures nobody in the TDL was judged on are neutral
+	suite.InDelta(100*(acceptanceWeight+(1-acceptanceWeight)*neutralShare), performanceScore(unrated, channelMeans(models.TSPPerformanceMetrics{unrated})), 1e-9)
+}
+
+func (suite *ScorerSuite) TestSkipsTDLsWithoutShipments() {
+	start, end := models.GetPerformancePeriod(time.Now())
+	suite.makePerformance(testdatagen.MakeDefaultTDL(suite.db), start, end, 0.30)
+
+	written, err := NewScorer(suite.db, suite.logger).Run(context.Background(), start)
+	suite.Nil(err)
+	suite.Equal(0, written)
+}
+
+func (suite *ScorerSuite) TestGroupByTDLKeepsOneRecordPerTSP() {
+	tdl := testdatagen.MakeDefaultTDL(suite.db)
+	perf := testdatagen.MakeTSPPerformance(suite.db, testdatagen.Assertions{
+		TransportationServiceProviderPerformance: models.TransportationServiceProviderPerformance{
+			TrafficDistributionListID: tdl.ID,
+			TrafficDistributionList:   tdl,
+		},
+	})
+	groups := groupByTDL(models.TransportationServiceProviderPerformances{perf, perf})
+	suite.Len(groups, 1)
+	suite.Len(groups[0], 1)
+}
+
+func (suite *ScorerSuite) makeShipment(date time.Time) models.Shipment {
+	return testdatagen.MakeShipment(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status:               models.ShipmentStatusDELIVERED,
+			RequestedPickupDate:  &date,
+			ActualPickupDate:     &date,
+			ActualDeliveryDate:   &date,
+			RequiredDeliveryDate: &date,
+		},
+	})
+}
+
+func (suite *ScorerSuite) makePerformance(tdl models.TrafficDistributionList, start time.Time, end time.Time, linehaulRate unit.DiscountRate) models.TransportationServiceProviderPerformance {
+	tsp := testdatagen.MakeDefaultTSP(suite.db)
+	return testdatagen.MakeTSPPerformance(suite.db, testdatagen.Assertions{
+		TransportationServiceProviderPerformance: models.TransportationServiceProviderPerformance{
+			TransportationServiceProviderID: tsp.ID,
+			TransportationServiceProvider:   tsp,
+			TrafficDistributionListID:       tdl.ID,
+			TrafficDistributionList:         tdl,
+			PerformancePeriodStart:          start,
+			PerformancePeriodEnd:            end,
+			LinehaulRate:                    linehaulRate,
+		},
+	})
+}
+
+func (suite *ScorerSuite) makeOffer(shipment models.Shipment, perf models.TransportationServiceProviderPerformance, accepted *bool) {
+	testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		ShipmentOffer: models.ShipmentOffer{
+			ShipmentID:                      shipment.ID,
+			Shipment:                        shipment,
+			TransportationServiceProviderID: perf.TransportationServiceProviderID,
+			TransportationServiceProvider:   perf.TransportationServiceProvider,
+			TransportationServiceProviderPerformanceID: perf.ID,
+			TransportationServiceProviderPerformance:   perf,
+			Accepted:                                   accepted,
+		},
+	})
+}
+
+func (suite *ScorerSuite) answerSurvey(shipment models.Shipment, rating int) {
+	question := models.SurveyQuestion{Prompt: "How did it go?", Kind: models.SurveyQuestionKindRATING, Active: true}
+	verrs, err := suite.db.ValidateAndCreate(&question)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	survey, _, verrs, err := models.CreateShipmentSurvey(suite.db, shipment, time.Now())
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+	verrs, err = models.CompleteShipmentSurvey(suite.db, survey, models.SurveyResponses{
+		{SurveyQuestionID: question.ID, Rating: &rating},
+	}, time.Now())
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+}
+
+type ScorerSuite struct {
+	suite.Suite
+	db     *pop.Connection
+	logger *hnyzap.Logger
+}
+
+func (suite *ScorerSuite) SetupTest() {
+	suite.db.TruncateAll()
+}
+
+func TestScorerSuite(t *testing.T) {
+	configLocation := "../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &ScorerSuite{
+		db:     db,
+		logger: &hnyzap.Logger{Logger: logger},
+	}
+	suite.Run(t, hs)
+}
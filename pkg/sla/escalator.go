@@ -0,0 +1,132 @@
+// Package sla escalates moves, PPM payment requests and documents that have waited on the
+// transportation office for longer than their SLA policy allows.
+package sla
+
+import (
+	"context"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/dates"
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+const escalatorLockID = 2
+
+// Escalator finds overdue SLA items and notifies the office users responsible for them
+type Escalator struct {
+	db                 *pop.Connection
+	logger             *hnyzap.Logger
+	notificationSender notifications.NotificationSender
+	calendars          *dates.CalendarCache
+	officeHostname     string
+	now                func() time.Time
+}
+
+// NewEscalator creates a new Escalator. Due dates are counted in business days on the calendars of the
+// transportation offices.
+func NewEscalator(db *pop.Connection,
+	logger *hnyzap.Logger,
+	notificationSender notifications.NotificationSender,
+	calendars *dates.CalendarCache,
+	officeHostname string) *Escalator {
+
+	return &Escalator{
+		db:                 db,
+		logger:             logger,
+		notificationSender: notificationSender,
+		calendars:          calendars,
+		officeHostname:     officeHostname,
+		now:                time.Now,
+	}
+}
+
+// Run escalates every overdue item that hasn't been escalated since it entered its status and
+// returns how many were escalated. Only one escalator runs at a time. The escalations are recorded
+// in one transaction and their emails sent once it commits, so that no email goes out for an
+// escalation that was rolled back.
+func (e *Escalator) Run(ctx context.Context) (int, error) {
+	ctx, span := beeline.StartSpan(ctx, "sla_escalator")
+	defer span.Send()
+
+	var escalations []models.SLAEscalation
+	err := e.db.Transaction(func(tx *pop.Connection) error {
+		escalations = nil
+		e.logger.Info("Waiting to acquire advisory lock...")
+		if err := tx.RawQuery("SELECT pg_advisory_xact_lock($1)", escalatorLockID).Exec(); err != nil {
+			return err
+		}
+
+		items, err := models.FetchSLAItems(tx, e.calendars, models.SLAItemFilter{})
+		if err != nil {
+			return err
+		}
+
+		now := e.now()
+		for _, item := range items {
+			if !item.IsOverdue(now) {
+				// Items are earliest due first, so nothing after this one is overdue either
+				break
+			}
+			escalation, err := e.escalate(ctx, tx, item)
+			if err != nil {
+				return err
+			}
+			if escalation != nil {
+				escalations = append(escalations, *escalation)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	e.notifyEscalations(escalations)
+	span.AddField("escalated", len(escalations))
+	return len(escalations), nil
+}
+
+// escalate records an escalation of the item unless it has already been escalated in its status
+func (e *Escalator) escalate(ctx context.Context, tx *pop.Connection, item models.SLAItem) (*models.SLAEscalation, error) {
+	existing, err := models.FetchSLAEscalation(tx, item)
+	if err != nil || existing != nil {
+		return nil, err
+	}
+
+	escalation := item.Escalation()
+	verrs, err := tx.ValidateAndCreate(&escalation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "saving SLA escalation for %s %s", item.Policy.RecordType, item.RecordID)
+	}
+	if verrs.HasAny() {
+		return nil, errors.Errorf("saving SLA escalation for %s %s: %s", item.Policy.RecordType, item.RecordID, verrs)
+	}
+
+	e.logger.TraceInfo(ctx, "Escalating overdue record",
+		zap.String("record_type", item.Policy.RecordType),
+		zap.String("record_id", item.RecordID.String()),
+		zap.String("move_id", item.MoveID.String()),
+		zap.Time("due_at", item.DueAt))
+	return &escalation, nil
+}
+
+// notifyEscalations emails the office users responsible for the escalated items. A failed email
+// doesn't stop the others, and its escalation stays recorded so it counts toward the office's metrics.
+func (e *Escalator) notifyEscalations(escalations []models.SLAEscalation) {
+	for _, escalation := range escalations {
+		err := e.notificationSender.SendNotification(
+			notifications.NewSLABreached(e.db, e.logger.Logger, e.officeHostname, escalation),
+		)
+		if err != nil {
+			e.logger.Error("Failed to send SLA escalation", zap.Error(err),
+				zap.String("sla_escalation_id", escalation.ID.String()))
+		}
+	}
+}
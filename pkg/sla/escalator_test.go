@@ -0,0 +1,89 @@
+package sla
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *EscalatorSuite) TestEscalatesOverdueMovesOnce() {
+	policy := models.SLAPolicy{RecordType: "move", Status: string(models.MoveStatusSUBMITTED), BusinessDays: 2}
+	verrs, err := suite.db.ValidateAndCreate(&policy)
+	suite.Nil(err)
+	suite.False(verrs.HasAny())
+
+	testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		Move: models.Move{Status: models.MoveStatusSUBMITTED},
+	})
+	testdatagen.MakeMove(suite.db, testdatagen.Assertions{
+		Move: models.Move{Status: models.MoveStatusAPPROVED},
+	})
+
+	calendars := models.NewCalendarCache(suite.db)
+	sender := notifications.NewRecordingNotificationSender()
+	escalator := NewEscalator(suite.db, suite.logger, sender, calendars, "office.example.com")
+
+	// Nothing is overdue yet
+	escalated, err := escalator.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, escalated)
+
+	escalator.now = func() time.Time { return time.Now().AddDate(0, 0, 14) }
+	escalated, err = escalator.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(1, escalated)
+	suite.Len(sender.Sent(), 1)
+
+	// An item is only escalated once while it stays in the same status
+	escalated, err = escalator.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, escalated)
+	suite.Len(sender.Sent(), 1)
+
+	metrics, err := models.FetchSLAMetrics(suite.db, calendars, escalator.now(), time.Now().AddDate(0, 0, -1))
+	suite.Nil(err)
+	suite.Len(metrics, 1)
+	suite.Equal(1, metrics[0].Open)
+	suite.Equal(1, metrics[0].Overdue)
+	suite.Equal(1, metrics[0].Escalations)
+}
+
+type EscalatorSuite struct {
+	suite.Suite
+	db     *pop.Connection
+	logger *hnyzap.Logger
+}
+
+func (suite *EscalatorSuite) SetupTest() {
+	suite.db.TruncateAll()
+}
+
+func TestEscalatorSuite(t *testing.T) {
+	configLocation := "../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &EscalatorSuite{
+		db:     db,
+		logger: &hnyzap.Logger{Logger: logger},
+	}
+	suite.Run(t, hs)
+}
@@ -0,0 +1,112 @@
+// Package surveys asks service members how their move went once their shipment is delivered, and
+// collects their answers through one-time links that don't require logging in.
+package surveys
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+)
+
+const requesterLockID = 4
+
+// ResponsePath is where the links in survey notifications point
+const ResponsePath = "/surveys/respond"
+
+// ResponseURL returns the one-time link to the survey of a token on the given host
+func ResponseURL(hostname string, token string) string {
+	responseURL := url.URL{
+		Scheme:   "https",
+		Host:     hostname,
+		Path:     ResponsePath,
+		RawQuery: url.Values{"token": {token}}.Encode(),
+	}
+	return responseURL.String()
+}
+
+// Requester creates the surveys of delivered shipments and sends their links to the service members
+type Requester struct {
+	db                 *pop.Connection
+	logger             *hnyzap.Logger
+	notificationSender notifications.NotificationSender
+	hostname           string
+	now                func() time.Time
+}
+
+// NewRequester creates a new Requester. Survey links point to the service member app on hostname.
+func NewRequester(db *pop.Connection,
+	logger *hnyzap.Logger,
+	notificationSender notifications.NotificationSender,
+	hostname string) *Requester {
+
+	return &Requester{
+		db:                 db,
+		logger:             logger,
+		notificationSender: notificationSender,
+		hostname:           hostname,
+		now:                time.Now,
+	}
+}
+
+// Run requests a survey for every shipment that was recently delivered or completed and hasn't been
+// surveyed, and returns how many were requested. Only one requester runs at a time.
+func (r *Requester) Run(ctx context.Context) (int, error) {
+	ctx, span := beeline.StartSpan(ctx, "survey_requester")
+	defer span.Send()
+
+	requested := 0
+	err := r.db.Transaction(func(tx *pop.Connection) error {
+		r.logger.Info("Waiting to acquire advisory lock...")
+		if err := tx.RawQuery("SELECT pg_advisory_xact_lock($1)", requesterLockID).Exec(); err != nil {
+			return err
+		}
+
+		shipments, err := models.FetchShipmentsAwaitingSurvey(tx, r.now())
+		if err != nil {
+			return err
+		}
+		for _, shipment := range shipments {
+			if err := r.request(ctx, tx, shipment); err != nil {
+				return err
+			}
+			requested++
+		}
+		return nil
+	})
+	span.AddField("requested", requested)
+	return requested, err
+}
+
+func (r *Requester) request(ctx context.Context, tx *pop.Connection, shipment models.Shipment) error {
+	survey, token, verrs, err := models.CreateShipmentSurvey(tx, shipment, r.now())
+	if err != nil {
+		return errors.Wrapf(err, "creating survey for shipment %s", shipment.ID)
+	} else if verrs.HasAny() {
+		return errors.Errorf("Validation failure creating survey for shipment %s: %s", shipment.ID, verrs)
+	}
+
+	r.logger.TraceInfo(ctx, "Requesting shipment survey",
+		zap.String("shipment_id", shipment.ID.String()),
+		zap.String("shipment_survey_id", survey.ID.String()),
+		zap.String("transportation_service_provider_id", survey.TransportationServiceProviderID.String()))
+
+	// A failed notification isn't retried since only the hash of the token is stored. The survey is
+	// still recorded so the shipment isn't asked about again.
+	err = r.notificationSender.SendNotification(
+		notifications.NewSurveyRequested(tx, r.logger.Logger, shipment.ID, ResponseURL(r.hostname, token)),
+	)
+	if err != nil {
+		r.logger.Error("Failed to send shipment survey", zap.Error(err),
+			zap.String("shipment_survey_id", survey.ID.String()))
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package surveys
+
+import (
+	"context"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/notifications"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *SurveySuite) TestRequestsSurveysOfDeliveredShipmentsOnce() {
+	delivered := time.Now().AddDate(0, 0, -2)
+	longAgo := time.Now().AddDate(0, 0, -models.SurveyLinkDays-1)
+	suite.makeAcceptedShipment(models.ShipmentStatusDELIVERED, &delivered)
+	suite.makeAcceptedShipment(models.ShipmentStatusCOMPLETED, &delivered)
+	suite.makeAcceptedShipment(models.ShipmentStatusINTRANSIT, nil)
+	// Shipments delivered before surveys were sent aren't asked about
+	suite.makeAcceptedShipment(models.ShipmentStatusDELIVERED, &longAgo)
+
+	requester := NewRequester(suite.db, suite.logger, notifications.NewStubNotificationSender(suite.logger.Logger), "my.example.com")
+	requested, err := requester.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(2, requested)
+
+	requested, err = requester.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, requested)
+}
+
+func (suite *SurveySuite) TestResponseURL() {
+	suite.Equal("https://my.example.com/surveys/respond?token=abc-123", ResponseURL("my.example.com", "abc-123"))
+}
+
+func (suite *SurveySuite) makeAcceptedShipment(status models.ShipmentStatus, actualDeliveryDate *time.Time) models.Shipment {
+	accepted := true
+	offer := testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status:             status,
+			ActualDeliveryDate: actualDeliveryDate,
+		},
+		ShipmentOffer: models.ShipmentOffer{
+			Accepted: &accepted,
+		},
+	})
+	return offer.Shipment
+}
+
+type SurveySuite struct {
+	suite.Suite
+	db     *pop.Connection
+	logger *hnyzap.Logger
+}
+
+func (suite *SurveySuite) SetupTest() {
+	suite.db.TruncateAll()
+}
+
+func TestSurveySuite(t *testing.T) {
+	configLocation := "../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &SurveySuite{
+		db:     db,
+		logger: &hnyzap.Logger{Logger: logger},
+	}
+	suite.Run(t, hs)
+}
@@ -0,0 +1,142 @@
+package surveys
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+var responsePage = template.Must(template.New("survey").Parse(`<!DOCTYPE html>
+<html>
+<head><title>move.mil survey</title></head>
+<body>
+{{if .Closed}}<p>This survey has already been answered or has expired. Thank you for your time.</p>
+{{else if .Completed}}<p>Thank you for telling us about your move.</p>
+{{else}}<form method="post">
+<p>Tell us how your move went. Every question is optional.</p>
+<input type="hidden" name="token" value="{{.Token}}">
+{{range .Questions}}<fieldset>
+<legend>{{.Prompt}}</legend>
+{{if eq .Kind "RATING"}}{{$id := .ID}}{{range $.Ratings}}<label><input type="radio" name="question_{{$id}}" value="{{.}}"> {{.}}</label>
+{{end}}{{else}}<textarea name="question_{{.ID}}" rows="4" cols="60"></textarea>
+{{end}}</fieldset>
+{{end}}<button type="submit">Submit</button>
+</form>
+{{end}}</body>
+</html>
+`))
+
+// ResponseHandler shows the survey of a one-time link and stores the service member's answers. GET
+// only shows the questions so that link scanners don't close the survey.
+type ResponseHandler struct {
+	db     *pop.Connection
+	logger *zap.Logger
+}
+
+// NewResponseHandler returns a handler for survey links
+func NewResponseHandler(db *pop.Connection, logger *zap.Logger) ResponseHandler {
+	return ResponseHandler{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (h ResponseHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.FormValue("token")
+	survey, err := models.FetchShipmentSurveyByToken(h.db, token)
+	if err == models.ErrFetchNotFound {
+		http.Error(w, "This survey link is invalid.", http.StatusNotFound)
+		return
+	} else if err != nil {
+		h.logger.Error("Failed to fetch shipment survey", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	questions, err := models.FetchSurveyQuestions(h.db, true)
+	if err != nil {
+		h.logger.Error("Failed to fetch survey questions", zap.Error(err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	now := time.Now()
+	closed := !survey.IsOpen(now)
+	completed := false
+	if r.Method == http.MethodPost && !closed {
+		responses, ok := parseResponses(r, questions)
+		if !ok {
+			http.Error(w, "Ratings must be between 1 and 5.", http.StatusBadRequest)
+			return
+		}
+		verrs, err := models.CompleteShipmentSurvey(h.db, survey, responses, now)
+		if err == models.ErrSurveyClosed {
+			closed = true
+		} else if err != nil || verrs.HasAny() {
+			h.logger.Error("Failed to store shipment survey responses",
+				zap.String("shipment_survey_id", survey.ID.String()),
+				zap.String("validation_errors", verrs.String()),
+				zap.Error(err))
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		} else {
+			completed = true
+			h.logger.Info("Shipment survey answered",
+				zap.String("shipment_survey_id", survey.ID.String()),
+				zap.Int("responses", len(responses)))
+		}
+	}
+
+	ratings := make([]int, models.SurveyRatingMax)
+	for i := range ratings {
+		ratings[i] = i + 1
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	err = responsePage.Execute(w, struct {
+		Token     string
+		Questions models.SurveyQuestions
+		Ratings   []int
+		Closed    bool
+		Completed bool
+	}{token, questions, ratings, closed, completed})
+	if err != nil {
+		h.logger.Error("Failed to render survey page", zap.Error(err))
+	}
+}
+
+// parseResponses reads the answers to the questions from the form. Unanswered questions are skipped,
+// and a rating that isn't a number from 1 to SurveyRatingMax makes the form invalid.
+func parseResponses(r *http.Request, questions models.SurveyQuestions) (models.SurveyResponses, bool) {
+	responses := models.SurveyResponses{}
+	for _, question := range questions {
+		value := strings.TrimSpace(r.PostFormValue("question_" + question.ID.String()))
+		if value == "" {
+			continue
+		}
+		response := models.SurveyResponse{SurveyQuestionID: question.ID}
+		if question.Kind == models.SurveyQuestionKindRATING {
+			rating, err := strconv.Atoi(value)
+			if err != nil || rating < 1 || rating > models.SurveyRatingMax {
+				return nil, false
+			}
+			response.Rating = &rating
+		} else {
+			response.Comment = &value
+		}
+		responses = append(responses, response)
+	}
+	return responses, true
+}
@@ -0,0 +1,68 @@
+package surveys
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+func (suite *SurveySuite) TestResponseHandlerStoresAnswersOnce() {
+	delivered := time.Now().AddDate(0, 0, -2)
+	shipment := suite.makeAcceptedShipment(models.ShipmentStatusDELIVERED, &delivered)
+	rating := models.SurveyQuestion{Prompt: "How satisfied were you?", Kind: models.SurveyQuestionKindRATING, Active: true, Position: 1}
+	comment := models.SurveyQuestion{Prompt: "Anything else?", Kind: models.SurveyQuestionKindTEXT, Active: true, Position: 2}
+	for _, question := range []*models.SurveyQuestion{&rating, &comment} {
+		verrs, err := suite.db.ValidateAndCreate(question)
+		suite.Nil(err)
+		suite.False(verrs.HasAny())
+	}
+	survey, token, _, err := models.CreateShipmentSurvey(suite.db, shipment, time.Now())
+	suite.Nil(err)
+
+	handler := NewResponseHandler(suite.db, suite.logger.Logger)
+
+	// Following the link only shows the questions
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", ResponseURL("my.example.com", token), nil))
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Contains(rr.Body.String(), "How satisfied were you?")
+	fetched, err := models.FetchShipmentSurveyByToken(suite.db, token)
+	suite.Nil(err)
+	suite.Nil(fetched.CompletedAt)
+
+	form := url.Values{
+		"token":                           {token},
+		"question_" + rating.ID.String():  {"4"},
+		"question_" + comment.ID.String(): {" "},
+	}
+	post := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", ResponsePath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr
+	}
+	rr = post()
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Contains(rr.Body.String(), "Thank you")
+
+	var responses models.SurveyResponses
+	suite.Nil(suite.db.Where("shipment_survey_id = $1", survey.ID).All(&responses))
+	suite.Len(responses, 1)
+	suite.Equal(4, *responses[0].Rating)
+
+	// The link can't be used to answer again
+	rr = post()
+	suite.Equal(http.StatusOK, rr.Code)
+	suite.Contains(rr.Body.String(), "already been answered")
+	suite.Nil(suite.db.Where("shipment_survey_id = $1", survey.ID).All(&responses))
+	suite.Len(responses, 1)
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest("GET", ResponsePath+"?token=invalid", nil))
+	suite.Equal(http.StatusNotFound, rr.Code)
+}
@@ -60,7 +60,7 @@ func MakeShipment(db *pop.Connection, assertions Assertions) models.Shipment {
 		requestedPickupDate = &PerformancePeriodStart
 	}
 	var summary dates.MoveDatesSummary
-	summary.CalculateMoveDates(*requestedPickupDate, 2, 3)
+	summary.CalculateMoveDates(*requestedPickupDate, 2, 3, dates.NewUSCalendar())
 
 	shipment := models.Shipment{
 		Status:           status,
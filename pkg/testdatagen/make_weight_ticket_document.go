@@ -0,0 +1,38 @@
+package testdatagen
+
+import (
+	"github.com/gobuffalo/pop"
+
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/unit"
+)
+
+// MakeWeightTicketDocument creates a single Weight Ticket Document.
+func MakeWeightTicketDocument(db *pop.Connection, assertions Assertions) models.WeightTicketDocument {
+	moveDoc := assertions.WeightTicketDocument.MoveDocument
+	// ID is required because it must be populated for Eager saving to work.
+	if isZeroUUID(assertions.WeightTicketDocument.MoveDocumentID) {
+		assertions.MoveDocument.MoveDocumentType = models.MoveDocumentTypeWEIGHTTICKET
+		moveDoc = MakeMoveDocument(db, assertions)
+	}
+
+	weightTicketDocument := models.WeightTicketDocument{
+		MoveDocumentID: moveDoc.ID,
+		MoveDocument:   moveDoc,
+		TripNumber:     1,
+		EmptyWeight:    unit.Pound(3000),
+		FullWeight:     unit.Pound(5000),
+	}
+
+	// Overwrite values with those from assertions
+	mergeModels(&weightTicketDocument, assertions.WeightTicketDocument)
+
+	mustCreate(db, &weightTicketDocument)
+
+	return weightTicketDocument
+}
+
+// MakeDefaultWeightTicketDocument returns a WeightTicketDocument with default values
+func MakeDefaultWeightTicketDocument(db *pop.Connection) models.WeightTicketDocument {
+	return MakeWeightTicketDocument(db, Assertions{})
+}
@@ -59,8 +59,9 @@ func (e e2eBasicScenario) Run(db *pop.Connection, loader *uploader.Uploader, log
 			LoginGovEmail: email,
 		},
 		OfficeUser: models.OfficeUser{
-			ID:    uuid.FromStringOrNil("9c5911a7-5885-4cf4-abec-021a40692403"),
-			Email: email,
+			ID:          uuid.FromStringOrNil("9c5911a7-5885-4cf4-abec-021a40692403"),
+			Email:       email,
+			AccessScope: models.OfficeAccessScopeNational,
 		},
 	})
 
@@ -49,6 +49,7 @@ type Assertions struct {
 	Upload                                   models.Upload
 	Uploader                                 *uploader.Uploader
 	User                                     models.User
+	WeightTicketDocument                     models.WeightTicketDocument
 }
 
 func stringPointer(s string) *string {
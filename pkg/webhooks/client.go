@@ -0,0 +1,63 @@
+package webhooks
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// ErrNonPublicAddress is returned when a webhook's host resolves to an address that isn't on the internet
+var ErrNonPublicAddress = errors.New("webhook host resolves to a non-public address")
+
+// AllowPrivateHosts lets webhooks be registered on and delivered to this machine and private networks,
+// so that cmd/webhook_receiver can be run locally without a tunnel. It is refused outside the
+// development and test environments.
+func AllowPrivateHosts(env string) error {
+	if env != "development" && env != "test" {
+		return errors.Errorf("private webhook hosts can't be allowed in the %s environment", env)
+	}
+	models.AllowPrivateWebhookHosts = true
+	return nil
+}
+
+// NewClient returns a client for posting deliveries that only connects to public addresses, unless
+// AllowPrivateHosts was called. Hosts are checked when subscriptions are registered, but a name can be
+// pointed at our own network later, so the addresses it resolves to are checked again on every
+// connection and the checked address is the one dialed.
+func NewClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Going through a proxy would hide the address being connected to
+			Proxy: nil,
+			DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+				host, port, err := net.SplitHostPort(address)
+				if err != nil {
+					return nil, err
+				}
+				addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+				if err != nil {
+					return nil, err
+				}
+				if len(addrs) == 0 {
+					return nil, errors.Errorf("no addresses found for %s", host)
+				}
+				for _, addr := range addrs {
+					if !models.AllowPrivateWebhookHosts && !models.IsPublicIP(addr.IP) {
+						return nil, errors.Wrapf(ErrNonPublicAddress, "%s resolves to %s", host, addr.IP)
+					}
+				}
+				return dialer.DialContext(ctx, network, net.JoinHostPort(addrs[0].IP.String(), port))
+			},
+			TLSHandshakeTimeout:   timeout,
+			ResponseHeaderTimeout: timeout,
+			MaxIdleConnsPerHost:   2,
+		},
+	}
+}
@@ -0,0 +1,250 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/honeycombio/beeline-go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+)
+
+const dispatcherLockID = 5
+
+// A delivery is attempted up to maxAttempts times. The wait before each retry doubles from
+// retryBaseDelay up to retryMaxDelay, so that a receiver can be down for about a day before its
+// deliveries fail. Claimed deliveries aren't claimed again for claimLease, by when their attempts
+// have been recorded unless the dispatcher died.
+const (
+	maxAttempts    = 12
+	retryBaseDelay = time.Minute
+	retryMaxDelay  = 6 * time.Hour
+	claimLease     = 10 * time.Minute
+	batchSize      = 100
+)
+
+// Dispatcher posts queued webhook deliveries to the URLs TSPs registered
+type Dispatcher struct {
+	db     *pop.Connection
+	logger *hnyzap.Logger
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewDispatcher creates a new Dispatcher that posts deliveries with client
+func NewDispatcher(db *pop.Connection, logger *hnyzap.Logger, client *http.Client) *Dispatcher {
+	return &Dispatcher{
+		db:     db,
+		logger: logger,
+		client: client,
+		now:    time.Now,
+	}
+}
+
+// Run queues deliveries of the events recorded since the last run, then attempts the deliveries that
+// are due and returns how many were delivered. Deliveries are claimed in a short transaction, then
+// posted and their outcomes saved one by one, so a failure to save one outcome doesn't cause the
+// others to be sent again.
+func (d *Dispatcher) Run(ctx context.Context) (int, error) {
+	ctx, span := beeline.StartSpan(ctx, "webhook_dispatcher")
+	defer span.Send()
+
+	queued, err := d.queueEvents(ctx)
+	if err != nil {
+		return 0, err
+	}
+	span.AddField("queued", queued)
+
+	deliveries, err := d.claim()
+	if err != nil {
+		return 0, err
+	}
+	span.AddField("claimed", len(deliveries))
+
+	delivered := 0
+	for i := range deliveries {
+		didDeliver, err := d.attempt(ctx, &deliveries[i])
+		if err != nil {
+			return delivered, err
+		}
+		if didDeliver {
+			delivered++
+		}
+	}
+	span.AddField("delivered", delivered)
+	return delivered, nil
+}
+
+// queueEvents queues deliveries of the outbox events that are due, each in its own transaction, and
+// returns how many deliveries were queued. An event that can't be queued keeps the error and is
+// retried with the same backoff as deliveries.
+func (d *Dispatcher) queueEvents(ctx context.Context) (int, error) {
+	now := d.now()
+	events, err := models.FetchDueWebhookOutboxEvents(d.db, now, maxAttempts, batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	queued := 0
+	for i := range events {
+		event := &events[i]
+		var count int
+		queueErr := d.db.Transaction(func(tx *pop.Connection) error {
+			var err error
+			count, err = queueDeliveries(tx, event, now)
+			return err
+		})
+		if queueErr == nil {
+			queued += count
+			continue
+		}
+
+		d.logger.TraceError(ctx, "Failed to queue webhook deliveries for event",
+			zap.String("webhook_outbox_event_id", event.ID.String()),
+			zap.String("event", string(event.Event)),
+			zap.Int("attempts", event.Attempts+1),
+			zap.Error(queueErr))
+		if err := d.recordQueueFailure(event, queueErr, now); err != nil {
+			return queued, err
+		}
+	}
+	return queued, nil
+}
+
+// recordQueueFailure saves why an outbox event couldn't be queued and when to try again, unless
+// another dispatcher has queued it in the meantime
+func (d *Dispatcher) recordQueueFailure(event *models.WebhookOutboxEvent, queueErr error, now time.Time) error {
+	attempts := event.Attempts + 1
+	sql := `UPDATE webhook_outbox_events
+		SET attempts = $2, last_error = $3, next_attempt_at = $4, updated_at = $5
+		WHERE id = $1 AND queued_at IS NULL`
+	err := d.db.RawQuery(sql, event.ID, attempts, queueErr.Error(), now.Add(retryDelay(attempts)), now).Exec()
+	if err != nil {
+		return errors.Wrapf(err, "recording failure to queue webhook outbox event %s", event.ID)
+	}
+	return nil
+}
+
+// claim takes the deliveries that are due and counts the attempt about to be made, deferring their
+// next attempt by claimLease so that other dispatchers leave them alone. Only one dispatcher claims
+// at a time.
+func (d *Dispatcher) claim() (models.WebhookDeliveries, error) {
+	var claimed models.WebhookDeliveries
+	err := d.db.Transaction(func(tx *pop.Connection) error {
+		d.logger.Info("Waiting to acquire advisory lock...")
+		if err := tx.RawQuery("SELECT pg_advisory_xact_lock($1)", dispatcherLockID).Exec(); err != nil {
+			return err
+		}
+
+		now := d.now()
+		deliveries, err := models.FetchDueWebhookDeliveries(tx, now, batchSize)
+		if err != nil {
+			return err
+		}
+		for i := range deliveries {
+			deliveries[i].Attempts++
+			deliveries[i].NextAttemptAt = now.Add(claimLease)
+			if err := saveDelivery(tx, &deliveries[i]); err != nil {
+				return err
+			}
+		}
+		claimed = deliveries
+		return nil
+	})
+	return claimed, err
+}
+
+// attempt posts a claimed delivery once and saves the outcome. Any 2xx response counts as delivered.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery) (bool, error) {
+	now := d.now()
+
+	responseCode, postErr := d.post(ctx, delivery, now)
+	if responseCode != 0 {
+		delivery.LastResponseCode = &responseCode
+	}
+	if postErr == nil {
+		delivery.Status = models.WebhookDeliveryStatusDELIVERED
+		delivery.DeliveredAt = &now
+		delivery.LastError = nil
+	} else {
+		lastError := postErr.Error()
+		delivery.LastError = &lastError
+		if delivery.Attempts >= maxAttempts {
+			delivery.Status = models.WebhookDeliveryStatusFAILED
+		} else {
+			delivery.NextAttemptAt = now.Add(retryDelay(delivery.Attempts))
+		}
+		d.logger.TraceInfo(ctx, "Webhook delivery attempt failed",
+			zap.String("webhook_delivery_id", delivery.ID.String()),
+			zap.String("webhook_subscription_id", delivery.WebhookSubscriptionID.String()),
+			zap.Int("attempts", delivery.Attempts),
+			zap.String("status", string(delivery.Status)),
+			zap.Error(postErr))
+	}
+
+	if err := saveDelivery(d.db, delivery); err != nil {
+		return false, err
+	}
+	return postErr == nil, nil
+}
+
+func saveDelivery(db *pop.Connection, delivery *models.WebhookDelivery) error {
+	verrs, err := db.ValidateAndUpdate(delivery)
+	if err != nil {
+		return errors.Wrapf(err, "saving webhook delivery %s", delivery.ID)
+	} else if verrs.HasAny() {
+		return errors.Errorf("Validation failure saving webhook delivery %s: %s", delivery.ID, verrs)
+	}
+	return nil
+}
+
+// post sends the delivery's payload to its webhook and returns the response code, or 0 if there was
+// no response
+func (d *Dispatcher) post(ctx context.Context, delivery *models.WebhookDelivery, now time.Time) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequest(http.MethodPost, delivery.WebhookSubscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.Event))
+	req.Header.Set(DeliveryHeader, delivery.ID.String())
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now.Unix(), 10))
+	req.Header.Set(SignatureHeader, Sign(delivery.WebhookSubscription.Secret, now, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain some of the body so the connection can be reused
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay returns how long to wait after a delivery's attempts before trying again
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,295 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+
+	"github.com/transcom/mymove/pkg/logging/hnyzap"
+	"github.com/transcom/mymove/pkg/models"
+	"github.com/transcom/mymove/pkg/testdatagen"
+)
+
+func (suite *WebhookSuite) TestDeliversSignedPayloads() {
+	var received []Payload
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		suite.Nil(Verify("s3cret", r.Header, body, time.Now()))
+		suite.Equal(string(models.WebhookEventShipmentAccepted), r.Header.Get(EventHeader))
+		var payload Payload
+		suite.Nil(json.Unmarshal(body, &payload))
+		suite.Equal(r.Header.Get(DeliveryHeader), payload.ID.String())
+		received = append(received, payload)
+	}))
+	defer receiver.Close()
+
+	offer := suite.makeAcceptedOffer()
+	suite.makeSubscription(offer.TransportationServiceProviderID, models.WebhookEventShipmentAccepted, receiver.URL)
+	// Other events aren't sent to the webhook
+	suite.makeSubscription(offer.TransportationServiceProviderID, models.WebhookEventShipmentApproved, receiver.URL)
+
+	data := EventData{ShipmentID: offer.ShipmentID, ShipmentStatus: string(models.ShipmentStatusACCEPTED)}
+	suite.Nil(NotifyShipmentTSP(suite.db, models.WebhookEventShipmentAccepted, data))
+
+	dispatcher := NewDispatcher(suite.db, suite.logger, receiver.Client())
+	delivered, err := dispatcher.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(1, delivered)
+	suite.Len(received, 1)
+	suite.Equal(data, received[0].Data)
+
+	// Delivered events aren't sent again
+	delivered, err = dispatcher.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, delivered)
+	suite.Len(received, 1)
+}
+
+func (suite *WebhookSuite) TestRetriesFailedDeliveries() {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer receiver.Close()
+
+	offer := suite.makeAcceptedOffer()
+	suite.makeSubscription(offer.TransportationServiceProviderID, models.WebhookEventShipmentAccepted, receiver.URL)
+	suite.Nil(NotifyShipmentTSP(suite.db, models.WebhookEventShipmentAccepted, EventData{ShipmentID: offer.ShipmentID}))
+
+	now := time.Now()
+	dispatcher := NewDispatcher(suite.db, suite.logger, receiver.Client())
+	dispatcher.now = func() time.Time { return now }
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		delivered, err := dispatcher.Run(context.Background())
+		suite.Nil(err)
+		suite.Equal(0, delivered)
+
+		var delivery models.WebhookDelivery
+		suite.Nil(suite.db.First(&delivery))
+		suite.Equal(attempt, delivery.Attempts)
+		suite.Equal(http.StatusServiceUnavailable, *delivery.LastResponseCode)
+		if attempt < maxAttempts {
+			suite.Equal(models.WebhookDeliveryStatusPENDING, delivery.Status)
+			suite.True(delivery.NextAttemptAt.After(now))
+			now = delivery.NextAttemptAt
+		} else {
+			suite.Equal(models.WebhookDeliveryStatusFAILED, delivery.Status)
+		}
+	}
+}
+
+func (suite *WebhookSuite) TestClaimedDeliveriesAreLeftToTheirDispatcher() {
+	posts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer receiver.Close()
+
+	offer := suite.makeAcceptedOffer()
+	suite.makeSubscription(offer.TransportationServiceProviderID, models.WebhookEventShipmentAccepted, receiver.URL)
+	suite.Nil(NotifyShipmentTSP(suite.db, models.WebhookEventShipmentAccepted, EventData{ShipmentID: offer.ShipmentID}))
+
+	dispatcher := NewDispatcher(suite.db, suite.logger, receiver.Client())
+	queued, err := dispatcher.queueEvents(context.Background())
+	suite.Nil(err)
+	suite.Equal(1, queued)
+	claimed, err := dispatcher.claim()
+	suite.Nil(err)
+	suite.Len(claimed, 1)
+
+	// Other runs don't send a delivery while it is claimed
+	delivered, err := dispatcher.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, delivered)
+	suite.Equal(0, posts)
+
+	// Once the claim lapses, as when the dispatcher that made it died, it is sent
+	later := time.Now().Add(claimLease + time.Minute)
+	dispatcher.now = func() time.Time { return later }
+	delivered, err = dispatcher.Run(context.Background())
+	suite.Nil(err)
+	suite.Equal(1, delivered)
+	suite.Equal(1, posts)
+
+	var delivery models.WebhookDelivery
+	suite.Nil(suite.db.First(&delivery))
+	suite.Equal(2, delivery.Attempts)
+	suite.Equal(models.WebhookDeliveryStatusDELIVERED, delivery.Status)
+}
+
+func (suite *WebhookSuite) TestQueuesEventsOnce() {
+	offer := suite.makeAcceptedOffer()
+	suite.makeSubscription(offer.TransportationServiceProviderID, models.WebhookEventShipmentOffered, "https://tsp.example.com/hooks")
+	other := testdatagen.MakeDefaultTSP(suite.db)
+	suite.makeSubscription(other.ID, models.WebhookEventShipmentOffered, "https://other.example.com/hooks")
+
+	suite.Nil(Notify(suite.db, offer.TransportationServiceProviderID, models.WebhookEventShipmentOffered, EventData{ShipmentID: offer.ShipmentID}))
+	// Nothing is sent for shipments no TSP has accepted
+	unaccepted := testdatagen.MakeDefaultShipment(suite.db)
+	suite.Nil(NotifyShipmentTSP(suite.db, models.WebhookEventShipmentOffered, EventData{ShipmentID: unaccepted.ID}))
+
+	dispatcher := NewDispatcher(suite.db, suite.logger, http.DefaultClient)
+	queued, err := dispatcher.queueEvents(context.Background())
+	suite.Nil(err)
+	suite.Equal(1, queued)
+
+	var deliveries models.WebhookDeliveries
+	suite.Nil(suite.db.Eager("WebhookSubscription").All(&deliveries))
+	suite.Len(deliveries, 1)
+	suite.Equal(offer.TransportationServiceProviderID, deliveries[0].WebhookSubscription.TransportationServiceProviderID)
+
+	var events models.WebhookOutboxEvents
+	suite.Nil(suite.db.All(&events))
+	suite.Len(events, 2)
+	for _, event := range events {
+		suite.NotNil(event.QueuedAt)
+	}
+
+	// Queued events aren't queued again
+	queued, err = dispatcher.queueEvents(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, queued)
+}
+
+func (suite *WebhookSuite) TestSavesEventsThatFailToQueue() {
+	now := time.Now()
+	event := models.WebhookOutboxEvent{
+		Event:         models.WebhookEventShipmentOffered,
+		Data:          "not json",
+		NextAttemptAt: now,
+	}
+	suite.Nil(suite.db.Create(&event))
+
+	dispatcher := NewDispatcher(suite.db, suite.logger, http.DefaultClient)
+	dispatcher.now = func() time.Time { return now }
+	queued, err := dispatcher.queueEvents(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, queued)
+
+	suite.Nil(suite.db.Find(&event, event.ID))
+	suite.Nil(event.QueuedAt)
+	suite.Equal(1, event.Attempts)
+	suite.NotNil(event.LastError)
+	suite.True(event.NextAttemptAt.After(now))
+
+	// It isn't retried before its next attempt is due
+	queued, err = dispatcher.queueEvents(context.Background())
+	suite.Nil(err)
+	suite.Equal(0, queued)
+	suite.Nil(suite.db.Find(&event, event.ID))
+	suite.Equal(1, event.Attempts)
+}
+
+func (suite *WebhookSuite) TestClientRefusesNonPublicAddresses() {
+	posts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer receiver.Close()
+
+	_, err := NewClient(time.Second).Post(receiver.URL, "application/json", nil)
+	suite.NotNil(err)
+	suite.Equal(ErrNonPublicAddress, errors.Cause(err.(*url.Error).Err))
+	suite.Equal(0, posts)
+}
+
+func (suite *WebhookSuite) TestClientConnectsToPrivateAddressesWhenAllowed() {
+	posts := 0
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posts++
+	}))
+	defer receiver.Close()
+
+	suite.NotNil(AllowPrivateHosts("container"))
+	suite.False(models.AllowPrivateWebhookHosts)
+
+	suite.Nil(AllowPrivateHosts("test"))
+	defer func() { models.AllowPrivateWebhookHosts = false }()
+	resp, err := NewClient(time.Second).Post(receiver.URL, "application/json", nil)
+	suite.Nil(err)
+	resp.Body.Close()
+	suite.Equal(1, posts)
+}
+
+func (suite *WebhookSuite) TestRetryDelay() {
+	suite.Equal(time.Minute, retryDelay(1))
+	suite.Equal(4*time.Minute, retryDelay(3))
+	suite.Equal(retryMaxDelay, retryDelay(maxAttempts))
+}
+
+func (suite *WebhookSuite) TestVerifyRejectsReplays() {
+	body := []byte(`{"event":"shipment.offered"}`)
+	sent := time.Now().Add(-time.Hour)
+	header := http.Header{}
+	header.Set(TimestampHeader, strconv.FormatInt(sent.Unix(), 10))
+	header.Set(SignatureHeader, Sign("s3cret", sent, body))
+
+	suite.Nil(Verify("s3cret", header, body, sent))
+	suite.Equal(ErrInvalidSignature, Verify("s3cret", header, body, time.Now()))
+	suite.Equal(ErrInvalidSignature, Verify("other", header, body, sent))
+}
+
+func (suite *WebhookSuite) makeAcceptedOffer() models.ShipmentOffer {
+	accepted := true
+	return testdatagen.MakeShipmentOffer(suite.db, testdatagen.Assertions{
+		Shipment: models.Shipment{
+			Status: models.ShipmentStatusACCEPTED,
+		},
+		ShipmentOffer: models.ShipmentOffer{
+			Accepted: &accepted,
+		},
+	})
+}
+
+func (suite *WebhookSuite) makeSubscription(tspID uuid.UUID, event models.WebhookEvent, url string) {
+	subscription := models.WebhookSubscription{
+		TransportationServiceProviderID: tspID,
+		Event:                           event,
+		URL:                             url,
+		Secret:                          "s3cret",
+	}
+	// Receivers in tests are local, which validation rejects
+	suite.Nil(suite.db.Create(&subscription))
+}
+
+type WebhookSuite struct {
+	suite.Suite
+	db     *pop.Connection
+	logger *hnyzap.Logger
+}
+
+func (suite *WebhookSuite) SetupTest() {
+	suite.db.TruncateAll()
+}
+
+func TestWebhookSuite(t *testing.T) {
+	configLocation := "../../config"
+	pop.AddLookupPaths(configLocation)
+	db, err := pop.Connect("test")
+	if err != nil {
+		log.Panic(err)
+	}
+
+	logger, err := zap.NewDevelopment()
+	if err != nil {
+		log.Panic(err)
+	}
+
+	hs := &WebhookSuite{
+		db:     db,
+		logger: &hnyzap.Logger{Logger: logger},
+	}
+	suite.Run(t, hs)
+}
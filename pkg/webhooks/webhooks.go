@@ -0,0 +1,200 @@
+// Package webhooks tells TSP systems about changes to their shipments by posting signed events to the
+// URLs they registered. Events are recorded in an outbox by the request that makes the change. A
+// dispatcher queues them as deliveries to the webhooks registered for them and posts those, retrying
+// anything that fails with backoff.
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gobuffalo/pop"
+	"github.com/gofrs/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/transcom/mymove/pkg/models"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-MilMove-Event"
+	DeliveryHeader  = "X-MilMove-Delivery"
+	TimestampHeader = "X-MilMove-Timestamp"
+	SignatureHeader = "X-MilMove-Signature"
+)
+
+// signatureTolerance is how old a delivery's timestamp may be before receivers should reject it as
+// a replay
+const signatureTolerance = 5 * time.Minute
+
+// ErrInvalidSignature is returned for deliveries that weren't signed with the webhook's secret
+var ErrInvalidSignature = errors.New("invalid webhook signature")
+
+// EventData describes what changed. Receivers fetch the shipment through the API for its details.
+type EventData struct {
+	ShipmentID         uuid.UUID  `json:"shipment_id"`
+	ShipmentStatus     string     `json:"shipment_status,omitempty"`
+	ShipmentLineItemID *uuid.UUID `json:"shipment_line_item_id,omitempty"`
+	LineItemStatus     string     `json:"line_item_status,omitempty"`
+	InvoiceStatus      string     `json:"invoice_status,omitempty"`
+}
+
+// Payload is the body of a delivery
+type Payload struct {
+	ID        uuid.UUID           `json:"id"`
+	Event     models.WebhookEvent `json:"event"`
+	CreatedAt time.Time           `json:"created_at"`
+	Data      EventData           `json:"data"`
+}
+
+// Notify records the event for the dispatcher to queue deliveries of to every webhook the TSP
+// registered for it
+func Notify(db *pop.Connection, tspID uuid.UUID, event models.WebhookEvent, data EventData) error {
+	return recordEvent(db, &tspID, event, data)
+}
+
+// NotifyShipmentTSP records the event for the dispatcher to queue deliveries of to the webhooks of the
+// TSP that accepted the shipment. Nothing is queued for shipments no TSP has accepted.
+func NotifyShipmentTSP(db *pop.Connection, event models.WebhookEvent, data EventData) error {
+	return recordEvent(db, nil, event, data)
+}
+
+// recordEvent saves an event to the outbox. Callers pass the transaction making the change, so the
+// event is recorded if and only if the change commits. Only the one row is written there; working
+// out who to tell is left to the dispatcher, which keeps retrying if it fails.
+func recordEvent(db *pop.Connection, tspID *uuid.UUID, event models.WebhookEvent, data EventData) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return errors.Wrap(err, "encoding webhook event data")
+	}
+	outboxEvent := models.WebhookOutboxEvent{
+		TransportationServiceProviderID: tspID,
+		Event:                           event,
+		Data:                            string(body),
+		NextAttemptAt:                   time.Now(),
+	}
+	verrs, err := db.ValidateAndCreate(&outboxEvent)
+	if err != nil {
+		return errors.Wrap(err, "recording webhook event")
+	} else if verrs.HasAny() {
+		return errors.Errorf("Validation failure recording webhook event: %s", verrs)
+	}
+	return nil
+}
+
+// queueDeliveries creates a delivery of an outbox event to each webhook registered for it and marks
+// the event queued. It returns how many deliveries were queued. Events another dispatcher has queued
+// are skipped.
+func queueDeliveries(tx *pop.Connection, outboxEvent *models.WebhookOutboxEvent, now time.Time) (int, error) {
+	var locked models.WebhookOutboxEvent
+	err := tx.RawQuery("SELECT * FROM webhook_outbox_events WHERE id = $1 AND queued_at IS NULL FOR UPDATE", outboxEvent.ID).First(&locked)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, errors.Wrap(err, "locking webhook outbox event")
+	}
+
+	var data EventData
+	if err := json.Unmarshal([]byte(locked.Data), &data); err != nil {
+		return 0, errors.Wrap(err, "decoding webhook event data")
+	}
+
+	var subscriptions models.WebhookSubscriptions
+	if locked.TransportationServiceProviderID != nil {
+		subscriptions, err = models.FetchWebhookSubscriptions(tx, *locked.TransportationServiceProviderID, locked.Event)
+	} else {
+		subscriptions, err = shipmentTSPSubscriptions(tx, data.ShipmentID, locked.Event)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, subscription := range subscriptions {
+		deliveryID, err := uuid.NewV4()
+		if err != nil {
+			return 0, err
+		}
+		body, err := json.Marshal(Payload{ID: deliveryID, Event: locked.Event, CreatedAt: locked.CreatedAt.UTC(), Data: data})
+		if err != nil {
+			return 0, errors.Wrap(err, "encoding webhook payload")
+		}
+		delivery := models.WebhookDelivery{
+			ID:                    deliveryID,
+			WebhookSubscriptionID: subscription.ID,
+			Event:                 locked.Event,
+			Payload:               string(body),
+			Status:                models.WebhookDeliveryStatusPENDING,
+			NextAttemptAt:         now,
+		}
+		verrs, err := tx.ValidateAndCreate(&delivery)
+		if err != nil {
+			return 0, errors.Wrap(err, "queueing webhook delivery")
+		} else if verrs.HasAny() {
+			return 0, errors.Errorf("Validation failure queueing webhook delivery: %s", verrs)
+		}
+	}
+
+	locked.QueuedAt = &now
+	if err := saveOutboxEvent(tx, &locked); err != nil {
+		return 0, err
+	}
+	*outboxEvent = locked
+	return len(subscriptions), nil
+}
+
+// shipmentTSPSubscriptions returns the webhooks for an event of the TSP that accepted a shipment, or
+// none if no TSP has
+func shipmentTSPSubscriptions(db *pop.Connection, shipmentID uuid.UUID, event models.WebhookEvent) (models.WebhookSubscriptions, error) {
+	var offer models.ShipmentOffer
+	err := db.Where("shipment_id = $1 AND accepted = true", shipmentID).First(&offer)
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "fetching accepted shipment offer")
+	}
+	return models.FetchWebhookSubscriptions(db, offer.TransportationServiceProviderID, event)
+}
+
+func saveOutboxEvent(db *pop.Connection, outboxEvent *models.WebhookOutboxEvent) error {
+	verrs, err := db.ValidateAndUpdate(outboxEvent)
+	if err != nil {
+		return errors.Wrapf(err, "saving webhook outbox event %s", outboxEvent.ID)
+	} else if verrs.HasAny() {
+		return errors.Errorf("Validation failure saving webhook outbox event %s: %s", outboxEvent.ID, verrs)
+	}
+	return nil
+}
+
+// Sign returns the signature of a delivery body sent at a time
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp.Unix(), 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks that a delivery received at now was signed with the secret and isn't a replay. It is
+// what receivers are expected to do with each delivery.
+func Verify(secret string, header http.Header, body []byte, now time.Time) error {
+	seconds, err := strconv.ParseInt(header.Get(TimestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	timestamp := time.Unix(seconds, 0)
+	if now.Sub(timestamp) > signatureTolerance || timestamp.Sub(now) > signatureTolerance {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(header.Get(SignatureHeader))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
@@ -4,9 +4,12 @@ import ReactTable from 'react-table';
 import { connect } from 'react-redux';
 import { get } from 'lodash';
 import 'react-table/react-table.css';
-import { RetrieveMovesForOffice } from './api.js';
+import { SearchMovesForOffice } from './api.js';
 import Alert from 'shared/Alert';
 
+// The number of moves loaded at a time
+const pageSize = 100;
+
 class QueueTable extends Component {
   constructor() {
     super();
@@ -14,8 +17,12 @@ class QueueTable extends Component {
       data: [],
       pages: null,
       loading: true,
+      sorted: [],
+      nextCursor: null,
     };
     this.fetchData = this.fetchData.bind(this);
+    this.fetchMore = this.fetchMore.bind(this);
+    this.onSortedChange = this.onSortedChange.bind(this);
   }
 
   componentDidMount() {
@@ -34,6 +41,19 @@ class QueueTable extends Component {
     lastName: '',
   };
 
+  searchParams(cursor) {
+    // The status column shows PPM payment requests, but sorts by move status on the server
+    const sort = this.state.sorted.map(s => (s.desc ? '-' : '') + (s.id === 'synthetic_status' ? 'status' : s.id));
+    const params = { limit: pageSize };
+    if (sort.length) {
+      params.sort = sort;
+    }
+    if (cursor) {
+      params.cursor = cursor;
+    }
+    return params;
+  }
+
   async fetchData() {
     const loadingQueueType = this.props.queueType;
 
@@ -42,19 +62,21 @@ class QueueTable extends Component {
       pages: null,
       loading: true,
       loadingQueue: loadingQueueType,
+      nextCursor: null,
     });
 
     // Catch any errors here and render an empty queue
     try {
-      const body = await RetrieveMovesForOffice(this.props.queueType);
+      const body = await SearchMovesForOffice(this.props.queueType, this.searchParams());
 
       // Only update the queue list if the request that is returning
       // is for the same queue as the most recent request.
       if (this.state.loadingQueue === loadingQueueType) {
         this.setState({
-          data: body,
+          data: body.items,
           pages: 1,
           loading: false,
+          nextCursor: body.next_cursor,
         });
       }
     } catch (e) {
@@ -66,6 +88,27 @@ class QueueTable extends Component {
     }
   }
 
+  async fetchMore() {
+    const loadingQueueType = this.props.queueType;
+    this.setState({ loading: true });
+    try {
+      const body = await SearchMovesForOffice(loadingQueueType, this.searchParams(this.state.nextCursor));
+      if (this.state.loadingQueue === loadingQueueType) {
+        this.setState({
+          data: this.state.data.concat(body.items),
+          loading: false,
+          nextCursor: body.next_cursor,
+        });
+      }
+    } catch (e) {
+      this.setState({ loading: false });
+    }
+  }
+
+  onSortedChange(sorted) {
+    this.setState({ sorted }, this.fetchData);
+  }
+
   render() {
     const titles = {
       new: 'New Moves',
@@ -134,8 +177,16 @@ class QueueTable extends Component {
                 Header: 'Last modified',
                 accessor: 'last_modified_date',
               },
+              {
+                Header: 'Assigned to',
+                accessor: 'assignee_name',
+              },
             ]}
             data={this.state.data}
+            manual // Sorting happens on the server
+            sorted={this.state.sorted}
+            onSortedChange={this.onSortedChange}
+            showPagination={false}
             loading={this.state.loading} // Display the loading overlay when we need it
             pageSize={this.state.data.length}
             className="-striped -highlight"
@@ -143,6 +194,11 @@ class QueueTable extends Component {
               onDoubleClick: e => this.props.history.push(`new/moves/${rowInfo.original.id}`),
             })}
           />
+          {this.state.nextCursor && (
+            <button className="usa-button-secondary" onClick={this.fetchMore} disabled={this.state.loading}>
+              Load more moves
+            </button>
+          )}
         </div>
       </div>
     );
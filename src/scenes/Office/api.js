@@ -11,6 +11,16 @@ export async function RetrieveMovesForOffice(queueType) {
   return response.body;
 }
 
+export async function SearchMovesForOffice(queueType, params = {}) {
+  const client = await getClient();
+  const response = await client.apis.queues.searchQueue({
+    queueType,
+    ...params,
+  });
+  checkResponse(response, 'failed to search moves due to server error');
+  return response.body;
+}
+
 // MOVE
 export async function LoadMove(moveId) {
   const client = await getClient();
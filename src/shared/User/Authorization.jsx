@@ -18,7 +18,7 @@ const AuthorizationContainer = (WrappedComponent, feature) =>
   };
 
 const mapStateToProps = state => ({
-  features: state.user.features,
+  features: state.loggedInUser.features,
 });
 
 // see https://medium.com/practo-engineering/connected-higher-order-component-hoc-93ee63c91526
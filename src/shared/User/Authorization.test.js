@@ -14,7 +14,7 @@ describe('Authorization tests', () => {
   describe('When the user is in the role', () => {
     let mockStore = configureStore();
     let initialState = {
-      user: {
+      loggedInUser: {
         features: ['fakeFeature'],
       },
     };
@@ -28,7 +28,7 @@ describe('Authorization tests', () => {
   describe('When the user is not in the role', () => {
     let mockStore = configureStore();
     let initialState = {
-      user: {
+      loggedInUser: {
         features: ['somethingElse'],
       },
     };
@@ -50,6 +50,7 @@ export const loggedInUserReducer = (state = {}, action) => {
         isLoading: false,
         hasErrored: false,
         hasSucceeded: true,
+        features: action.payload.features,
       };
     case GET_LOGGED_IN_USER.error:
       return {
@@ -80,7 +81,6 @@ function getUserInfo() {
     userId: UserID,
     firstName: FirstName,
     isLoggedIn: true,
-    features: jwt.SessionValue.Features,
   };
 }
 